@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/grafana/dskit/user"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+type queryExplainCmd struct {
+	HostPort string `arg:"" help:"tempo host and port. scheme and path will be provided based on query type. e.g. localhost:3200"`
+	TraceQL  string `arg:"" help:"traceql query"`
+	Start    string `arg:"" optional:"" help:"start time in RFC3339 (e.g. 2006-01-02T15:04:05Z07:00) or relative (e.g. now-1h) format"`
+	End      string `arg:"" optional:"" help:"end time in RFC3339 (e.g. 2006-01-02T15:04:05Z07:00) or relative (e.g. now) format"`
+
+	OrgID      string   `help:"optional orgID"`
+	Headers    []string `help:"extra headers in key=value format" name:"header"`
+	PathPrefix string   `help:"string to prefix all http paths with"`
+	Secure     bool     `help:"use https"`
+}
+
+func (cmd *queryExplainCmd) Run(_ *globalOptions) error {
+	req := &tempopb.SearchRequest{
+		Query: cmd.TraceQL,
+	}
+
+	// without a time range the query frontend plans the search of its default time range
+	if cmd.Start != "" {
+		start, err := parseTime(cmd.Start)
+		if err != nil {
+			return err
+		}
+		req.Start = uint32(start.Unix())
+	}
+	if cmd.End != "" {
+		end, err := parseTime(cmd.End)
+		if err != nil {
+			return err
+		}
+		req.End = uint32(end.Unix())
+	}
+
+	httpReq, err := http.NewRequest("GET", httpScheme(cmd.Secure)+"://"+path.Join(cmd.HostPort, cmd.PathPrefix, api.PathSearchExplain), nil)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err = api.BuildSearchRequest(httpReq, req)
+	if err != nil {
+		return err
+	}
+
+	httpReq.Header = http.Header{}
+	err = user.InjectOrgIDIntoHTTPRequest(user.InjectOrgID(context.Background(), cmd.OrgID), httpReq)
+	if err != nil {
+		return err
+	}
+	applyHeadersHTTP(httpReq, cmd.Headers)
+
+	httpResp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return errors.New("failed to query. body: " + string(body) + " status: " + httpResp.Status)
+	}
+
+	out := &bytes.Buffer{}
+	err = json.Indent(out, body, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to parse resp: %w", err)
+	}
+
+	fmt.Println(out.String())
+	return nil
+}
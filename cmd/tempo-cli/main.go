@@ -71,6 +71,7 @@ var cli struct {
 		TraceID      queryBlocksCmd       `cmd:"" help:"query for a traceid directly from backend blocks"`
 		TraceSummary queryTraceSummaryCmd `cmd:"" help:"query summary for a traceid directly from backend blocks"`
 		Search       searchBlocksCmd      `cmd:"" help:"search for a traceid directly from backend blocks"`
+		Explain      queryExplainCmd      `cmd:"" help:"explain how Tempo plans a TraceQL search without executing it"`
 	} `cmd:""`
 
 	RewriteBlocks struct {
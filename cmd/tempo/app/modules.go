@@ -288,6 +288,8 @@ func (t *App) initDistributor() (services.Service, error) {
 		t.Server.HTTPRouter().Handle("/usage_metrics", usageHandler)
 	}
 
+	t.Server.HTTPRouter().Path("/distributor/schema_quality").HandlerFunc(distributor.SchemaQualityHandler).Methods("GET")
+
 	return t.distributor, nil
 }
 
@@ -309,6 +311,8 @@ func (t *App) initGenerator() (services.Service, error) {
 	}
 	t.generator = genSvc
 
+	t.Server.HTTPRouter().Path("/metrics-generator/span_names").HandlerFunc(t.generator.SpanNamesHandler).Methods("GET")
+
 	return t.generator, nil
 }
 
@@ -502,6 +506,7 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 
 	// http search endpoints
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearch), base.Wrap(queryFrontend.SearchHandler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchExplain), base.Wrap(queryFrontend.SearchExplainHandler))
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTags), base.Wrap(queryFrontend.SearchTagsHandler))
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTagsV2), base.Wrap(queryFrontend.SearchTagsV2Handler))
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTagValues), base.Wrap(queryFrontend.SearchTagsValuesHandler))
@@ -511,6 +516,9 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryInstant), base.Wrap(queryFrontend.MetricsQueryInstantHandler))
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryRange), base.Wrap(queryFrontend.MetricsQueryRangeHandler))
 
+	// http traceql macros endpoint
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraceQLMacros), base.Wrap(queryFrontend.MacrosHandler))
+
 	// http mcp endpoint
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathMCP), base.Wrap(queryFrontend.MCPHandler))
 
@@ -172,6 +172,7 @@ func TestInitGeneratorNoLocalBlocks_forcesGeneratorRingMode(t *testing.T) {
 
 	app := &App{
 		cfg:                  *cfg,
+		Server:               &fakeTempoServer{router: mux.NewRouter(), grpc: grpc.NewServer()},
 		generatorRingWatcher: &dskitring.PartitionRingWatcher{},
 	}
 
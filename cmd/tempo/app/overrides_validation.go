@@ -3,6 +3,7 @@ package app
 import (
 	"fmt"
 
+	"github.com/grafana/tempo/modules/distributor"
 	"github.com/grafana/tempo/modules/generator/validation"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/overrides/userconfigurable/api"
@@ -23,6 +24,18 @@ func newRuntimeConfigValidator(cfg *Config) overrides.Validator {
 }
 
 func (r *runtimeConfigValidator) Validate(config *overrides.Overrides) (warnings []error, err error) {
+	if err := distributor.ValidateTailSampling(config.Ingestion.TailSampling); err != nil {
+		return warnings, err
+	}
+
+	if err := distributor.ValidateAttributeTransforms(config.Ingestion.AttributeTransforms); err != nil {
+		return warnings, err
+	}
+
+	if err := distributor.ValidateSchemaValidation(config.Ingestion.SchemaValidation); err != nil {
+		return warnings, err
+	}
+
 	if config.MetricsGenerator.GenerateNativeHistograms != "" {
 		if err := validation.ValidateHistogramMode(string(config.MetricsGenerator.GenerateNativeHistograms)); err != nil {
 			return warnings, err
@@ -35,6 +48,18 @@ func (r *runtimeConfigValidator) Validate(config *overrides.Overrides) (warnings
 		}
 	}
 
+	if len(config.MetricsGenerator.SpanNameSanitizationRules) > 0 {
+		if err := validation.ValidateSpanNameSanitizationRules(config.MetricsGenerator.SpanNameSanitizationRules); err != nil {
+			return warnings, err
+		}
+	}
+
+	if config.MetricsGenerator.MaxCardinalityPerLabelMode != "" {
+		if err := validation.ValidateMaxCardinalityPerLabelMode(config.MetricsGenerator.MaxCardinalityPerLabelMode); err != nil {
+			return warnings, err
+		}
+	}
+
 	if config.MetricsGenerator.NativeHistogramBucketFactor != 0 {
 		if err := validation.ValidateNativeHistogramBucketFactor(config.MetricsGenerator.NativeHistogramBucketFactor); err != nil {
 			return warnings, err
@@ -66,6 +91,31 @@ func (r *runtimeConfigValidator) Validate(config *overrides.Overrides) (warnings
 		return warnings, err
 	}
 
+	traceQLMetrics := config.MetricsGenerator.Processor.TraceQLMetrics
+	if err := validation.ValidateHistogramBuckets(traceQLMetrics.HistogramBuckets, "metrics_generator.processor.traceql_metrics.histogram_buckets"); err != nil {
+		return warnings, err
+	}
+	if err := validation.ValidateTraceQLMetrics(traceQLMetrics.Metrics); err != nil {
+		return warnings, err
+	}
+
+	criticalPath := config.MetricsGenerator.Processor.CriticalPath
+	if criticalPath.Wait != 0 {
+		if err := validation.ValidateCriticalPathWait(criticalPath.Wait); err != nil {
+			return warnings, err
+		}
+	}
+	if criticalPath.MaxItems != 0 {
+		if err := validation.ValidateCriticalPathMaxItems(criticalPath.MaxItems); err != nil {
+			return warnings, err
+		}
+	}
+	if criticalPath.MaxSpansPerTrace != 0 {
+		if err := validation.ValidateCriticalPathMaxSpansPerTrace(criticalPath.MaxSpansPerTrace); err != nil {
+			return warnings, err
+		}
+	}
+
 	return
 }
 
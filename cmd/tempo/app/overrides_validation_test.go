@@ -107,6 +107,48 @@ func Test_runtimeOverridesValidator(t *testing.T) {
 			},
 			expErr: "metrics_generator.processor.span_metrics.histogram_buckets must be strictly increasing: bucket[1]=0.5 is <= bucket[0]=0.5",
 		},
+		{
+			name: "traceql metrics query invalid",
+			cfg:  Config{},
+			overrides: overrides.Overrides{
+				MetricsGenerator: overrides.MetricsGeneratorOverrides{
+					Processor: overrides.ProcessorOverrides{
+						TraceQLMetrics: overrides.TraceQLMetricsOverrides{
+							Metrics: []sharedconfig.TraceQLMetric{{Name: "db_calls", Query: `{ span.db.system = "postgres" }`}},
+						},
+					},
+				},
+			},
+			expErr: "traceql_metrics metric \"db_calls\" has an invalid query: query must be a metrics query with a single metrics function",
+		},
+		{
+			name: "traceql metrics valid",
+			cfg:  Config{},
+			overrides: overrides.Overrides{
+				MetricsGenerator: overrides.MetricsGeneratorOverrides{
+					Processor: overrides.ProcessorOverrides{
+						TraceQLMetrics: overrides.TraceQLMetricsOverrides{
+							Metrics: []sharedconfig.TraceQLMetric{{Name: "db_calls", Query: `{ span.db.system = "postgres" } | rate() by (span.db.name)`}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "critical path max items invalid",
+			cfg:  Config{},
+			overrides: overrides.Overrides{
+				MetricsGenerator: overrides.MetricsGeneratorOverrides{
+					Processor: overrides.ProcessorOverrides{
+						CriticalPath: overrides.CriticalPathOverrides{
+							Wait:     30 * time.Second,
+							MaxItems: -1,
+						},
+					},
+				},
+			},
+			expErr: "metrics_generator.processor.critical_path.max_items must be greater than 0",
+		},
 		{
 			name: "service graphs histogram buckets valid",
 			cfg:  Config{},
@@ -246,6 +288,102 @@ func Test_runtimeOverridesValidator(t *testing.T) {
 			}},
 			expErr: "span_name_sanitization \"invalid\" is not valid, valid values: [ dry_run enabled]",
 		},
+		{
+			name: "metrics_generator.span_name_sanitization_rules",
+			cfg:  Config{},
+			overrides: overrides.Overrides{MetricsGenerator: overrides.MetricsGeneratorOverrides{
+				SpanNameSanitizationRules: []sharedconfig.SpanNameSanitizationRule{{Template: "/users/{id}"}, {Pattern: "^cache-.*", Replacement: "cache"}},
+			}},
+		},
+		{
+			name: "metrics_generator.span_name_sanitization_rules invalid",
+			cfg:  Config{},
+			overrides: overrides.Overrides{MetricsGenerator: overrides.MetricsGeneratorOverrides{
+				SpanNameSanitizationRules: []sharedconfig.SpanNameSanitizationRule{{Template: "/users/{id}"}, {Template: "/users/{id"}},
+			}},
+			expErr: "span_name_sanitization_rules[1]: invalid template \"/users/{id\": unclosed {",
+		},
+		{
+			name: "ingestion.tail_sampling",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{
+				TailSampling: overrides.TailSamplingOverrides{Policies: []overrides.TailSamplingPolicy{
+					{Name: "errors", Type: overrides.TailSamplingPolicyStatusCode},
+					{Name: "checkout", Type: overrides.TailSamplingPolicyTraceQL, Query: `{ resource.service.name = "checkout" }`},
+				}},
+			}},
+		},
+		{
+			name: "ingestion.tail_sampling invalid",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{
+				TailSampling: overrides.TailSamplingOverrides{Policies: []overrides.TailSamplingPolicy{
+					{Name: "sample", Type: overrides.TailSamplingPolicyProbabilistic},
+				}},
+			}},
+			expErr: "tail_sampling policy \"sample\": sampling_percentage must be greater than 0 and at most 100",
+		},
+		{
+			name: "ingestion.attribute_transforms",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{
+				AttributeTransforms: []overrides.AttributeTransform{
+					{Action: overrides.AttributeTransformHash, Key: "user.email", Salt: "salt"},
+					{Action: overrides.AttributeTransformMask, KeyPattern: ".*", Pattern: `\d+\.\d+\.\d+\.\d+`},
+				},
+			}},
+		},
+		{
+			name: "ingestion.attribute_transforms invalid",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{
+				AttributeTransforms: []overrides.AttributeTransform{
+					{Action: overrides.AttributeTransformDelete, Key: "password"},
+					{Action: overrides.AttributeTransformRename, Key: "user"},
+				},
+			}},
+			expErr: "attribute_transforms[1]: rename requires new_key",
+		},
+		{
+			name: "ingestion.schema_validation",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{
+				SchemaValidation: overrides.SchemaValidationOverrides{
+					Action: overrides.SchemaValidationActionTag,
+					Rules: []overrides.SchemaValidationRule{
+						{Name: "service-name", Type: overrides.SchemaValidationRuleRequiredAttribute, Action: overrides.SchemaValidationActionReject, Key: "service.name"},
+						{Name: "span-names", Type: overrides.SchemaValidationRuleSpanNameCardinality, MaxSpanNames: 100},
+					},
+				},
+			}},
+		},
+		{
+			name: "ingestion.schema_validation invalid",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{
+				SchemaValidation: overrides.SchemaValidationOverrides{
+					Rules: []overrides.SchemaValidationRule{
+						{Name: "status-code", Type: overrides.SchemaValidationRuleAttributeType, Scope: overrides.AttributeTransformScopeSpan, Key: "http.status_code", ValueType: "integer"},
+					},
+				},
+			}},
+			expErr: "schema_validation rule \"status-code\": invalid value_type \"integer\", valid value types: [string int double bool array kvlist bytes]",
+		},
+		{
+			name: "metrics_generator.max_cardinality_per_label_mode top_k",
+			cfg:  Config{},
+			overrides: overrides.Overrides{MetricsGenerator: overrides.MetricsGeneratorOverrides{
+				MaxCardinalityPerLabelMode: "top_k",
+			}},
+		},
+		{
+			name: "metrics_generator.max_cardinality_per_label_mode invalid",
+			cfg:  Config{},
+			overrides: overrides.Overrides{MetricsGenerator: overrides.MetricsGeneratorOverrides{
+				MaxCardinalityPerLabelMode: "invalid",
+			}},
+			expErr: "max_cardinality_per_label_mode \"invalid\" is not valid, valid values: [ top_k]",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,373 @@
+package distributor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/spanfilter"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+const defaultMaskReplacement = "****"
+
+var metricAttributesTransformed = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "distributor_attributes_transformed_total",
+	Help:      "The total number of attributes changed by attribute transforms per tenant, action and scope",
+}, []string{"tenant", "action", "scope"})
+
+// indexes of attributeTransformScopes
+const (
+	transformScopeResource = iota
+	transformScopeSpan
+	transformScopeEvent
+	transformScopeLink
+)
+
+var attributeTransformScopes = []string{
+	overrides.AttributeTransformScopeResource,
+	overrides.AttributeTransformScopeSpan,
+	overrides.AttributeTransformScopeEvent,
+	overrides.AttributeTransformScopeLink,
+}
+
+// attributeTransformer applies the attribute transforms of a tenant to the received spans. The
+// compiled transforms are cached per tenant and compiled again when the overrides change.
+type attributeTransformer struct {
+	logger log.Logger
+
+	mtx     sync.RWMutex
+	tenants map[string]*tenantAttributeTransforms
+}
+
+type tenantAttributeTransforms struct {
+	// cfg are the transforms the compiled transforms were created from
+	cfg        []overrides.AttributeTransform
+	transforms []*attributeTransform
+}
+
+func newAttributeTransformer(logger log.Logger) *attributeTransformer {
+	return &attributeTransformer{
+		logger:  logger,
+		tenants: make(map[string]*tenantAttributeTransforms),
+	}
+}
+
+// Transform applies the transforms in order to the attributes of the batches. Later transforms see
+// the attributes changed by earlier transforms. Returns true if any attribute was changed.
+func (t *attributeTransformer) Transform(tenantID string, cfg []overrides.AttributeTransform, batches []*v1.ResourceSpans) bool {
+	transforms := t.transformsFor(tenantID, cfg)
+
+	// changed counts the attributes changed by every transform per scope
+	changed := make([]transformCounts, len(transforms))
+	for _, rs := range batches {
+		for i, tr := range transforms {
+			tr.applyToResourceSpans(rs, &changed[i])
+		}
+	}
+
+	anyChanged := false
+	for i, tr := range transforms {
+		for scope, count := range changed[i] {
+			if count > 0 {
+				metricAttributesTransformed.WithLabelValues(tenantID, tr.action, attributeTransformScopes[scope]).Add(float64(count))
+				anyChanged = true
+			}
+		}
+	}
+	return anyChanged
+}
+
+func (t *attributeTransformer) transformsFor(tenantID string, cfg []overrides.AttributeTransform) []*attributeTransform {
+	t.mtx.RLock()
+	tt, ok := t.tenants[tenantID]
+	t.mtx.RUnlock()
+
+	if ok && reflect.DeepEqual(tt.cfg, cfg) {
+		return tt.transforms
+	}
+
+	tt = &tenantAttributeTransforms{cfg: slices.Clone(cfg)}
+	for i, c := range cfg {
+		tr, err := newAttributeTransform(c)
+		if err != nil {
+			_ = level.Warn(t.logger).Log("msg", "skipping invalid attribute transform", "tenant", tenantID, "index", i, "err", err)
+			continue
+		}
+		tt.transforms = append(tt.transforms, tr)
+	}
+
+	t.mtx.Lock()
+	t.tenants[tenantID] = tt
+	t.mtx.Unlock()
+
+	return tt.transforms
+}
+
+// transformCounts is indexed like attributeTransformScopes.
+type transformCounts [4]int
+
+type attributeTransform struct {
+	action string
+	// scopes is indexed like attributeTransformScopes
+	scopes [4]bool
+
+	key   string
+	keyRe *regexp.Regexp
+
+	salt        []byte
+	pattern     *regexp.Regexp
+	replacement string
+	newKey      string
+	value       string
+
+	// filter is nil if the transform applies to all spans
+	filter *spanfilter.SpanFilter
+}
+
+func newAttributeTransform(cfg overrides.AttributeTransform) (*attributeTransform, error) {
+	t := &attributeTransform{
+		action:      cfg.Action,
+		key:         cfg.Key,
+		salt:        []byte(cfg.Salt),
+		replacement: cfg.Replacement,
+		newKey:      cfg.NewKey,
+		value:       cfg.Value,
+	}
+
+	if len(cfg.Scopes) == 0 {
+		t.scopes = [4]bool{true, true, true, true}
+	}
+	for _, s := range cfg.Scopes {
+		i := slices.Index(attributeTransformScopes, s)
+		if i == -1 {
+			return nil, fmt.Errorf("invalid scope %q, valid scopes: %v", s, attributeTransformScopes)
+		}
+		t.scopes[i] = true
+	}
+
+	switch cfg.Action {
+	case overrides.AttributeTransformDelete, overrides.AttributeTransformHash, overrides.AttributeTransformMask:
+		if (cfg.Key == "") == (cfg.KeyPattern == "") {
+			return nil, errors.New("exactly one of key or key_pattern must be set")
+		}
+		if cfg.KeyPattern != "" {
+			re, err := regexp.Compile(cfg.KeyPattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key_pattern %q: %w", cfg.KeyPattern, err)
+			}
+			t.keyRe = re
+		}
+	case overrides.AttributeTransformRename, overrides.AttributeTransformInsert:
+		if cfg.Key == "" || cfg.KeyPattern != "" {
+			return nil, fmt.Errorf("%s requires key and doesn't support key_pattern", cfg.Action)
+		}
+		if cfg.Action == overrides.AttributeTransformRename && cfg.NewKey == "" {
+			return nil, errors.New("rename requires new_key")
+		}
+	default:
+		return nil, fmt.Errorf("unknown action %q", cfg.Action)
+	}
+
+	if cfg.Action == overrides.AttributeTransformMask {
+		if cfg.Pattern != "" {
+			re, err := regexp.Compile(cfg.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", cfg.Pattern, err)
+			}
+			t.pattern = re
+		}
+		if t.replacement == "" {
+			t.replacement = defaultMaskReplacement
+		}
+	}
+
+	if len(cfg.FilterPolicies) > 0 {
+		filter, err := spanfilter.NewSpanFilter(cfg.FilterPolicies)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter_policies: %w", err)
+		}
+		t.filter = filter
+	}
+
+	return t, nil
+}
+
+// ValidateAttributeTransforms returns an error if any of the transforms is invalid.
+func ValidateAttributeTransforms(transforms []overrides.AttributeTransform) error {
+	for i, t := range transforms {
+		if _, err := newAttributeTransform(t); err != nil {
+			return fmt.Errorf("attribute_transforms[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (t *attributeTransform) applyToResourceSpans(rs *v1.ResourceSpans, changed *transformCounts) {
+	// spans are selected before any attribute is changed, so the transform doesn't change which
+	// spans it applies to
+	var selected []*v1.Span
+	for _, ss := range rs.ScopeSpans {
+		for _, span := range ss.Spans {
+			if t.filter == nil || t.filter.ApplyFilterPolicy(rs.Resource, span) {
+				selected = append(selected, span)
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return
+	}
+
+	var n int
+	if t.scopes[transformScopeResource] && rs.Resource != nil {
+		rs.Resource.Attributes, n = t.apply(rs.Resource.Attributes)
+		changed[transformScopeResource] += n
+	}
+
+	for _, span := range selected {
+		if t.scopes[transformScopeSpan] {
+			span.Attributes, n = t.apply(span.Attributes)
+			changed[transformScopeSpan] += n
+		}
+		if t.scopes[transformScopeEvent] {
+			for _, event := range span.Events {
+				event.Attributes, n = t.apply(event.Attributes)
+				changed[transformScopeEvent] += n
+			}
+		}
+		if t.scopes[transformScopeLink] {
+			for _, link := range span.Links {
+				link.Attributes, n = t.apply(link.Attributes)
+				changed[transformScopeLink] += n
+			}
+		}
+	}
+}
+
+func (t *attributeTransform) matchKey(key string) bool {
+	if t.keyRe != nil {
+		return t.keyRe.MatchString(key)
+	}
+	return key == t.key
+}
+
+// apply transforms the attributes and returns them with the number of attributes changed. The
+// attributes are changed in place.
+func (t *attributeTransform) apply(attributes []*v1_common.KeyValue) ([]*v1_common.KeyValue, int) {
+	changed := 0
+
+	switch t.action {
+	case overrides.AttributeTransformDelete:
+		attributes = slices.DeleteFunc(attributes, func(a *v1_common.KeyValue) bool {
+			if t.matchKey(a.Key) {
+				changed++
+				return true
+			}
+			return false
+		})
+
+	case overrides.AttributeTransformHash, overrides.AttributeTransformMask:
+		for _, a := range attributes {
+			if t.matchKey(a.Key) && t.transformAnyValue(a.Value) {
+				changed++
+			}
+		}
+
+	case overrides.AttributeTransformRename:
+		i := slices.IndexFunc(attributes, func(a *v1_common.KeyValue) bool { return a.Key == t.key })
+		if i == -1 {
+			break
+		}
+		renamed := attributes[i]
+		// the renamed attribute replaces an existing attribute with the new key
+		attributes = slices.DeleteFunc(attributes, func(a *v1_common.KeyValue) bool { return a.Key == t.newKey && a != renamed })
+		renamed.Key = t.newKey
+		changed++
+
+	case overrides.AttributeTransformInsert:
+		if slices.ContainsFunc(attributes, func(a *v1_common.KeyValue) bool { return a.Key == t.key }) {
+			break
+		}
+		attributes = append(attributes, &v1_common.KeyValue{
+			Key:   t.key,
+			Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: t.value}},
+		})
+		changed++
+	}
+
+	return attributes, changed
+}
+
+// transformAnyValue hashes or masks the value in place and returns true if it changed. Values that
+// aren't strings are transformed in their string representation and replaced by a string, the
+// elements of arrays and key-value lists are transformed one by one.
+func (t *attributeTransform) transformAnyValue(v *v1_common.AnyValue) bool {
+	var value string
+
+	switch x := v.GetValue().(type) {
+	case nil:
+		return false
+	case *v1_common.AnyValue_StringValue:
+		transformed := t.transformValue(x.StringValue)
+		if transformed == x.StringValue {
+			return false
+		}
+		x.StringValue = transformed
+		return true
+	case *v1_common.AnyValue_ArrayValue:
+		changed := false
+		for _, e := range x.ArrayValue.GetValues() {
+			changed = t.transformAnyValue(e) || changed
+		}
+		return changed
+	case *v1_common.AnyValue_KvlistValue:
+		changed := false
+		for _, kv := range x.KvlistValue.GetValues() {
+			changed = t.transformAnyValue(kv.GetValue()) || changed
+		}
+		return changed
+	case *v1_common.AnyValue_BoolValue:
+		value = strconv.FormatBool(x.BoolValue)
+	case *v1_common.AnyValue_IntValue:
+		value = strconv.FormatInt(x.IntValue, 10)
+	case *v1_common.AnyValue_DoubleValue:
+		value = strconv.FormatFloat(x.DoubleValue, 'g', -1, 64)
+	case *v1_common.AnyValue_BytesValue:
+		value = hex.EncodeToString(x.BytesValue)
+	}
+
+	transformed := t.transformValue(value)
+	if transformed == value {
+		return false
+	}
+	v.Value = &v1_common.AnyValue_StringValue{StringValue: transformed}
+	return true
+}
+
+func (t *attributeTransform) transformValue(value string) string {
+	if t.action == overrides.AttributeTransformHash {
+		h := sha256.New()
+		_, _ = h.Write(t.salt)
+		_, _ = h.Write([]byte(value))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	if t.pattern == nil {
+		return t.replacement
+	}
+	return t.pattern.ReplaceAllString(value, t.replacement)
+}
@@ -0,0 +1,277 @@
+package distributor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	kitlog "github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/distributor/forwarder"
+	"github.com/grafana/tempo/modules/distributor/forwarder/file"
+	"github.com/grafana/tempo/modules/overrides"
+	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func attributesMap(attributes []*v1_common.KeyValue) map[string]string {
+	m := make(map[string]string, len(attributes))
+	for _, a := range attributes {
+		m[a.Key] = a.GetValue().GetStringValue()
+	}
+	return m
+}
+
+func TestAttributeTransformer(t *testing.T) {
+	sha := func(s string) string {
+		h := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(h[:])
+	}
+
+	newBatch := func() *v1.ResourceSpans {
+		span := makeSpan(okTraceID, "0000000000000001", "span", nil,
+			makeAttribute("user.email", "jane@example.com"),
+			makeAttribute("auth.token", "secret"),
+			makeAttribute("http.url", "/users?ip=10.0.0.1"),
+			makeAttribute("old", "value"),
+		)
+		span.Events = []*v1.Span_Event{{Attributes: []*v1_common.KeyValue{makeAttribute("user.email", "jane@example.com")}}}
+		span.Links = []*v1.Span_Link{{Attributes: []*v1_common.KeyValue{makeAttribute("auth.token", "secret")}}}
+		return makeResourceSpans("api", []*v1.ScopeSpans{makeScope(span)}, makeAttribute("host.ip", "10.0.0.1"))
+	}
+
+	tcs := []struct {
+		name       string
+		transforms []overrides.AttributeTransform
+		resource   map[string]string
+		span       map[string]string
+		event      map[string]string
+		link       map[string]string
+	}{
+		{
+			name:       "delete with key pattern",
+			transforms: []overrides.AttributeTransform{{Action: overrides.AttributeTransformDelete, KeyPattern: `\.(token|ip)$`}},
+			resource:   map[string]string{"service.name": "api"},
+			span:       map[string]string{"user.email": "jane@example.com", "http.url": "/users?ip=10.0.0.1", "old": "value"},
+			event:      map[string]string{"user.email": "jane@example.com"},
+			link:       map[string]string{},
+		},
+		{
+			name:       "salted hash in span scope",
+			transforms: []overrides.AttributeTransform{{Action: overrides.AttributeTransformHash, Scopes: []string{"span"}, Key: "user.email", Salt: "salt"}},
+			resource:   map[string]string{"service.name": "api", "host.ip": "10.0.0.1"},
+			span:       map[string]string{"user.email": sha("saltjane@example.com"), "auth.token": "secret", "http.url": "/users?ip=10.0.0.1", "old": "value"},
+			event:      map[string]string{"user.email": "jane@example.com"},
+			link:       map[string]string{"auth.token": "secret"},
+		},
+		{
+			name: "mask",
+			transforms: []overrides.AttributeTransform{
+				{Action: overrides.AttributeTransformMask, KeyPattern: ".*", Pattern: `\d+\.\d+\.\d+\.\d+`, Replacement: "x.x.x.x"},
+				{Action: overrides.AttributeTransformMask, Key: "auth.token"},
+			},
+			resource: map[string]string{"service.name": "api", "host.ip": "x.x.x.x"},
+			span:     map[string]string{"user.email": "jane@example.com", "auth.token": "****", "http.url": "/users?ip=x.x.x.x", "old": "value"},
+			event:    map[string]string{"user.email": "jane@example.com"},
+			link:     map[string]string{"auth.token": "****"},
+		},
+		{
+			name: "rename and insert",
+			transforms: []overrides.AttributeTransform{
+				{Action: overrides.AttributeTransformRename, Scopes: []string{"span"}, Key: "old", NewKey: "user.email"},
+				{Action: overrides.AttributeTransformInsert, Scopes: []string{"resource"}, Key: "redacted", Value: "true"},
+				{Action: overrides.AttributeTransformInsert, Scopes: []string{"resource"}, Key: "service.name", Value: "ignored"},
+			},
+			resource: map[string]string{"service.name": "api", "host.ip": "10.0.0.1", "redacted": "true"},
+			span:     map[string]string{"user.email": "value", "auth.token": "secret", "http.url": "/users?ip=10.0.0.1"},
+			event:    map[string]string{"user.email": "jane@example.com"},
+			link:     map[string]string{"auth.token": "secret"},
+		},
+		{
+			name: "filter policies",
+			transforms: []overrides.AttributeTransform{
+				{
+					Action: overrides.AttributeTransformDelete, Key: "auth.token",
+					FilterPolicies: []filterconfig.FilterPolicy{{Include: &filterconfig.PolicyMatch{
+						MatchType:  filterconfig.Strict,
+						Attributes: []filterconfig.MatchPolicyAttribute{{Key: "resource.service.name", Value: "api"}},
+					}}},
+				},
+				{
+					Action: overrides.AttributeTransformDelete, Key: "user.email",
+					FilterPolicies: []filterconfig.FilterPolicy{{Include: &filterconfig.PolicyMatch{
+						MatchType:  filterconfig.Strict,
+						Attributes: []filterconfig.MatchPolicyAttribute{{Key: "resource.service.name", Value: "db"}},
+					}}},
+				},
+			},
+			resource: map[string]string{"service.name": "api", "host.ip": "10.0.0.1"},
+			span:     map[string]string{"user.email": "jane@example.com", "http.url": "/users?ip=10.0.0.1", "old": "value"},
+			event:    map[string]string{"user.email": "jane@example.com"},
+			link:     map[string]string{},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			batch := newBatch()
+			newAttributeTransformer(kitlog.NewNopLogger()).Transform("test", tc.transforms, []*v1.ResourceSpans{batch})
+
+			span := batch.ScopeSpans[0].Spans[0]
+			require.Equal(t, tc.resource, attributesMap(batch.Resource.Attributes))
+			require.Equal(t, tc.span, attributesMap(span.Attributes))
+			require.Equal(t, tc.event, attributesMap(span.Events[0].Attributes))
+			require.Equal(t, tc.link, attributesMap(span.Links[0].Attributes))
+		})
+	}
+}
+
+func TestAttributeTransformer_nonStringValues(t *testing.T) {
+	sha := func(s string) string {
+		h := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(h[:])
+	}
+	intValue := func(key string, v int64) *v1_common.KeyValue {
+		return &v1_common.KeyValue{Key: key, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_IntValue{IntValue: v}}}
+	}
+
+	span := makeSpan(okTraceID, "0000000000000001", "span", nil,
+		intValue("user.id", 1234),
+		intValue("account.id", 5678),
+		&v1_common.KeyValue{Key: "user.emails", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_ArrayValue{ArrayValue: &v1_common.ArrayValue{
+			Values: []*v1_common.AnyValue{{Value: &v1_common.AnyValue_StringValue{StringValue: "jane@example.com"}}},
+		}}}},
+	)
+	batch := makeResourceSpans("api", []*v1.ScopeSpans{makeScope(span)})
+
+	newAttributeTransformer(kitlog.NewNopLogger()).Transform("test", []overrides.AttributeTransform{
+		{Action: overrides.AttributeTransformHash, Key: "user.id", Salt: "salt"},
+		{Action: overrides.AttributeTransformMask, Key: "account.id"},
+		{Action: overrides.AttributeTransformMask, Key: "user.emails", Pattern: "@.*"},
+	}, []*v1.ResourceSpans{batch})
+
+	require.Equal(t, map[string]string{"user.id": sha("salt1234"), "account.id": "****", "user.emails": ""}, attributesMap(span.Attributes))
+	require.Equal(t, "jane****", span.Attributes[2].Value.GetArrayValue().Values[0].GetStringValue())
+}
+
+func TestAttributeTransformer_metricsAndReload(t *testing.T) {
+	transformer := newAttributeTransformer(kitlog.NewNopLogger())
+	tenant := "attribute-transformer-reload"
+
+	batch := makeResourceSpans("api", []*v1.ScopeSpans{makeScope(
+		makeSpan(okTraceID, "0000000000000001", "span", nil, makeAttribute("a", "1")),
+		makeSpan(okTraceID, "0000000000000002", "span", nil, makeAttribute("a", "2")),
+	)})
+	require.True(t, transformer.Transform(tenant, []overrides.AttributeTransform{{Action: overrides.AttributeTransformMask, Key: "a"}}, []*v1.ResourceSpans{batch}))
+	require.Equal(t, 2.0, testutil.ToFloat64(metricAttributesTransformed.WithLabelValues(tenant, "mask", "span")))
+
+	// the transforms are compiled again when the overrides change
+	require.True(t, transformer.Transform(tenant, []overrides.AttributeTransform{{Action: overrides.AttributeTransformDelete, Key: "a"}}, []*v1.ResourceSpans{batch}))
+	require.Equal(t, 2.0, testutil.ToFloat64(metricAttributesTransformed.WithLabelValues(tenant, "delete", "span")))
+	require.Empty(t, batch.ScopeSpans[0].Spans[0].Attributes)
+	require.Empty(t, batch.ScopeSpans[0].Spans[1].Attributes)
+
+	// nothing is left to change
+	require.False(t, transformer.Transform(tenant, []overrides.AttributeTransform{{Action: overrides.AttributeTransformDelete, Key: "a"}}, []*v1.ResourceSpans{batch}))
+}
+
+func TestValidateAttributeTransforms(t *testing.T) {
+	tcs := []struct {
+		transform overrides.AttributeTransform
+		err       string
+	}{
+		{transform: overrides.AttributeTransform{Action: "delete", Key: "a"}},
+		{transform: overrides.AttributeTransform{Action: "hash", KeyPattern: "^user\\.", Salt: "salt"}},
+		{transform: overrides.AttributeTransform{Action: "mask", Key: "a", Pattern: "[0-9]+", Scopes: []string{"span", "event"}}},
+		{transform: overrides.AttributeTransform{Action: "rename", Key: "a", NewKey: "b"}},
+		{transform: overrides.AttributeTransform{Action: "insert", Key: "a", Value: "b"}},
+		{transform: overrides.AttributeTransform{Action: "upsert", Key: "a"}, err: `attribute_transforms[0]: unknown action "upsert"`},
+		{transform: overrides.AttributeTransform{Action: "delete"}, err: "exactly one of key or key_pattern must be set"},
+		{transform: overrides.AttributeTransform{Action: "delete", Key: "a", KeyPattern: "a"}, err: "exactly one of key or key_pattern must be set"},
+		{transform: overrides.AttributeTransform{Action: "hash", KeyPattern: "("}, err: "invalid key_pattern"},
+		{transform: overrides.AttributeTransform{Action: "mask", Key: "a", Pattern: "("}, err: "invalid pattern"},
+		{transform: overrides.AttributeTransform{Action: "rename", Key: "a"}, err: "rename requires new_key"},
+		{transform: overrides.AttributeTransform{Action: "insert", KeyPattern: "a"}, err: "insert requires key and doesn't support key_pattern"},
+		{transform: overrides.AttributeTransform{Action: "delete", Key: "a", Scopes: []string{"scope"}}, err: `invalid scope "scope"`},
+		{
+			transform: overrides.AttributeTransform{Action: "delete", Key: "a", FilterPolicies: []filterconfig.FilterPolicy{{}}},
+			err:       "invalid filter_policies",
+		},
+	}
+
+	for _, tc := range tcs {
+		err := ValidateAttributeTransforms([]overrides.AttributeTransform{tc.transform})
+		if tc.err == "" {
+			require.NoError(t, err)
+			continue
+		}
+		require.ErrorContains(t, err, tc.err)
+	}
+}
+
+func TestPushTracesAttributeTransforms(t *testing.T) {
+	limits := overrides.Config{}
+	limits.RegisterFlagsAndApplyDefaults(&flag.FlagSet{})
+	limits.Defaults.Ingestion.AttributeTransforms = []overrides.AttributeTransform{
+		{Action: overrides.AttributeTransformDelete, Key: "auth.token"},
+	}
+	limits.Defaults.Forwarders = []string{"archive"}
+
+	distributorCfg, overridesSvc, loggingLevel, middleware := setupDependencies(t, limits)
+	archiveDir := t.TempDir()
+	distributorCfg.Forwarders = forwarder.ConfigList{
+		{Name: "archive", Backend: forwarder.FileBackend, File: file.Config{Directory: archiveDir}},
+	}
+
+	var pushed *tempopb.PushBytesRequest
+	d, err := New(
+		distributorCfg,
+		LocalPushTargets{
+			LiveStore: func(_ context.Context, req *tempopb.PushBytesRequest) (*tempopb.PushResponse, error) {
+				pushed = req
+				return &tempopb.PushResponse{}, nil
+			},
+		},
+		nil,
+		overridesSvc,
+		middleware,
+		kitlog.NewNopLogger(),
+		loggingLevel,
+		prometheus.NewRegistry(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), d.forwardersManager))
+
+	traces := batchesToTraces(t, []*v1.ResourceSpans{
+		makeResourceSpans("api", []*v1.ScopeSpans{makeScope(
+			makeSpan(okTraceID, "0000000000000001", "span", nil, makeAttribute("auth.token", "secret"), makeAttribute("a", "b")),
+		)}),
+	})
+	_, err = d.PushTraces(ctx, traces)
+	require.NoError(t, err)
+	require.NotNil(t, pushed)
+
+	trace := &tempopb.Trace{}
+	require.NoError(t, trace.Unmarshal(pushed.Traces[0].Slice))
+	require.Equal(t, map[string]string{"a": "b"}, attributesMap(trace.ResourceSpans[0].ScopeSpans[0].Spans[0].Attributes))
+
+	// forwarders receive the transformed spans
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), d.forwardersManager))
+	files, err := filepath.Glob(filepath.Join(archiveDir, "test", "*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	archived, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	require.Contains(t, string(archived), `"key":"a"`)
+	require.NotContains(t, string(archived), "auth.token")
+}
@@ -48,6 +48,7 @@ const (
 	distributorRingKey = "distributor"
 
 	truncationLogsPerSecond = 1
+	rejectionLogsPerSecond  = 1
 )
 
 var (
@@ -182,6 +183,14 @@ type Distributor struct {
 	localPushTargets   LocalPushTargets
 	generatorForwarder *generatorForwarder
 
+	// Tail sampling of the tenants with tail sampling policies
+	tailSampler *tailSampler
+
+	attributeTransformer *attributeTransformer
+
+	// Schema validation and quality scores of the tenants with schema validation rules
+	schemaValidator *schemaValidator
+
 	// Generic Forwarder
 	forwardersManager *forwarder.Manager
 
@@ -207,6 +216,7 @@ type Distributor struct {
 	tracePushMiddlewares []TracePushMiddleware
 
 	truncationLogger *tempo_log.RateLimitedLogger
+	rejectionLogger  *tempo_log.RateLimitedLogger
 
 	// For testing functionality that relies on timing without having to sleep in unit tests.
 	sleep func(time.Duration)
@@ -265,6 +275,8 @@ func New(
 		overrides:            o,
 		tracePushMiddlewares: cfg.TracePushMiddlewares,
 		truncationLogger:     tempo_log.NewRateLimitedLogger(truncationLogsPerSecond, level.Warn(logger)),
+		rejectionLogger:      tempo_log.NewRateLimitedLogger(rejectionLogsPerSecond, level.Warn(logger)),
+		attributeTransformer: newAttributeTransformer(logger),
 		logger:               logger,
 		sleep:                time.Sleep,
 		now:                  time.Now,
@@ -283,6 +295,12 @@ func New(
 		subservices = append(subservices, d.generatorForwarder)
 	}
 
+	d.tailSampler = newTailSampler(logger, d.pushSampledTraces, o)
+	subservices = append(subservices, d.tailSampler)
+
+	d.schemaValidator = newSchemaValidator(logger)
+	subservices = append(subservices, d.schemaValidator)
+
 	forwardersManager, err := forwarder.NewManager(d.cfg.Forwarders, logger, o, loggingLevel)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create forwarders manager: %w", err)
@@ -446,6 +464,49 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 
 	batches := trace.ResourceSpans
 
+	var (
+		forwarders = d.forwardersManager.ForTenant(userID)
+		// changed is set if the spans are changed before they are written, so the forwarders
+		// receive the changed spans instead of the spans as they were received
+		changed bool
+	)
+
+	// transform attributes before the spans are logged, truncated, forwarded and written
+	if transforms := d.overrides.IngestionAttributeTransforms(userID); len(transforms) > 0 {
+		changed = d.attributeTransformer.Transform(userID, transforms, batches)
+	}
+
+	// validate the transformed spans, so transforms can fix violations
+	if schemaValidation := d.overrides.IngestionSchemaValidation(userID); len(schemaValidation.Rules) > 0 {
+		var (
+			rejected  int
+			validated bool
+		)
+		batches, rejected, validated = d.schemaValidator.Validate(userID, schemaValidation, batches)
+		changed = changed || validated
+		if rejected > 0 {
+			overrides.RecordDiscardedSpans(rejected, overrides.ReasonSchemaValidation, userID)
+			if rejected == spanCount {
+				return nil, status.Errorf(codes.InvalidArgument, "%s: %d of %d spans violate a schema validation rule with action reject for user %s",
+					overrides.ErrorPrefixSchemaValidation, rejected, spanCount, userID)
+			}
+			// the push succeeds if some spans are accepted, an error would make the client retry
+			// or drop the accepted spans too
+			d.rejectionLogger.Log("msg", "spans rejected by schema validation",
+				"tenant", userID,
+				"rejected", rejected,
+				"total", spanCount)
+			spanCount -= rejected
+		}
+	}
+
+	// forwarders receive the spans that are written, before they are truncated
+	if changed && len(forwarders) > 0 {
+		if traces, err = tracesFromBatches(batches); err != nil {
+			return nil, err
+		}
+	}
+
 	logReceivedSpans(batches, &d.cfg.LogReceivedSpans, d.logger)
 	if d.cfg.MetricReceivedSpans.Enabled {
 		metricSpans(batches, userID, &d.cfg.MetricReceivedSpans)
@@ -489,17 +550,26 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 		}
 	}
 
-	if err := d.forwardersManager.ForTenant(userID).ForwardTraces(ctx, traces); err != nil {
+	// forwarders receive all spans, tail sampling only applies to the spans written to Kafka or the
+	// live-stores
+	if err := forwarders.ForwardTraces(ctx, traces); err != nil {
 		_ = level.Warn(d.logger).Log("msg", "failed to forward batches for tenant=%s: %w", userID, err)
 	}
 
+	if tailSampling := d.overrides.IngestionTailSampling(userID); len(tailSampling.Policies) > 0 {
+		ringTokens, rebatchedTraces = d.tailSampler.Sample(ctx, userID, tailSampling, ringTokens, rebatchedTraces)
+		if len(rebatchedTraces) == 0 {
+			return nil, nil
+		}
+	}
+
 	if d.pushSpansToKafka {
 		if err := d.pushTracesKafka(ctx, userID, ringTokens, rebatchedTraces); err != nil {
 			level.Error(d.logger).Log("msg", "failed to write to kafka", "err", err, "tenant", userID)
 			return nil, err
 		}
 	} else {
-		if err := d.pushLocal(ctx, userID, ringTokens, rebatchedTraces); err != nil {
+		if err := d.pushLocal(ctx, userID, ringTokens, rebatchedTraces, generator.ExtractNoGenerateMetrics(ctx)); err != nil {
 			level.Error(d.logger).Log("msg", "failed to push to local consumers", "err", err, "tenant", userID)
 			return nil, err
 		}
@@ -508,26 +578,44 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 	return nil, nil // PushRequest is ignored, so no reason to create one
 }
 
+// tracesFromBatches converts the batches back to the traces received by PushTraces.
+func tracesFromBatches(batches []*v1.ResourceSpans) (ptrace.Traces, error) {
+	b, err := (&tempopb.Trace{ResourceSpans: batches}).Marshal()
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+	return (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(b)
+}
+
 func (d *Distributor) pushTracesKafka(ctx context.Context, userID string, keys []uint32, traces []*rebatchedTrace) error {
 	skipMetricsGeneration := generator.ExtractNoGenerateMetrics(ctx)
 	return d.sendToKafka(ctx, userID, keys, traces, skipMetricsGeneration)
 }
 
-func (d *Distributor) pushLocal(ctx context.Context, userID string, keys []uint32, traces []*rebatchedTrace) error {
+// pushSampledTraces pushes the traces kept by tail sampling, after the requests that received them
+// have completed.
+func (d *Distributor) pushSampledTraces(ctx context.Context, userID string, keys []uint32, traces []*rebatchedTrace, noGenerateMetrics bool) error {
+	if d.pushSpansToKafka {
+		return d.sendToKafka(ctx, userID, keys, traces, noGenerateMetrics)
+	}
+	return d.pushLocal(ctx, userID, keys, traces, noGenerateMetrics)
+}
+
+func (d *Distributor) pushLocal(ctx context.Context, userID string, keys []uint32, traces []*rebatchedTrace, noGenerateMetrics bool) error {
 	if err := d.pushTracesToLiveStore(ctx, userID, traces); err != nil {
 		return err
 	}
 
-	d.pushTracesToGenerator(ctx, userID, keys, traces)
+	d.pushTracesToGenerator(ctx, userID, keys, traces, noGenerateMetrics)
 	return nil
 }
 
-func (d *Distributor) pushTracesToGenerator(ctx context.Context, userID string, keys []uint32, traces []*rebatchedTrace) {
+func (d *Distributor) pushTracesToGenerator(ctx context.Context, userID string, keys []uint32, traces []*rebatchedTrace, noGenerateMetrics bool) {
 	if d.localPushTargets.Generator == nil {
 		return
 	}
 	if len(d.overrides.MetricsGeneratorProcessors(userID)) > 0 {
-		d.generatorForwarder.SendTraces(ctx, userID, keys, traces)
+		d.generatorForwarder.sendTraces(ctx, userID, keys, traces, noGenerateMetrics)
 	}
 }
 
@@ -589,6 +677,12 @@ func (d *Distributor) UsageTrackerHandler() http.Handler {
 	return nil
 }
 
+// SchemaQualityHandler shows the schema quality scores of the services of the tenants with schema
+// validation rules.
+func (d *Distributor) SchemaQualityHandler(w http.ResponseWriter, req *http.Request) {
+	d.schemaValidator.Handler(w, req)
+}
+
 func (d *Distributor) sendToKafka(ctx context.Context, userID string, keys []uint32, traces []*rebatchedTrace, skipMetricsGeneration bool) error {
 	marshalledTraces := make([][]byte, len(traces))
 	for i, t := range traces {
@@ -79,6 +79,12 @@ func newGeneratorForwarder(logger log.Logger, fn forwardFunc, o overrides.Interf
 
 // SendTraces queues up traces to be sent to the metrics-generators
 func (f *generatorForwarder) SendTraces(ctx context.Context, tenantID string, keys []uint32, traces []*rebatchedTrace) {
+	f.sendTraces(ctx, tenantID, keys, traces, generator.ExtractNoGenerateMetrics(ctx))
+}
+
+// sendTraces is like SendTraces, but noGenerateMetrics is passed explicitly instead of being
+// extracted from the context.
+func (f *generatorForwarder) sendTraces(ctx context.Context, tenantID string, keys []uint32, traces []*rebatchedTrace, noGenerateMetrics bool) {
 	select {
 	case <-f.shutdown:
 		return
@@ -86,7 +92,7 @@ func (f *generatorForwarder) SendTraces(ctx context.Context, tenantID string, ke
 	}
 
 	q := f.getOrCreateQueue(tenantID)
-	err := q.Push(ctx, &request{tenantID: tenantID, keys: keys, traces: traces, noGenerateMetrics: generator.ExtractNoGenerateMetrics(ctx)})
+	err := q.Push(ctx, &request{tenantID: tenantID, keys: keys, traces: traces, noGenerateMetrics: noGenerateMetrics})
 	if err != nil {
 		_ = level.Error(f.logger).Log("msg", "failed to push traces to queue", "tenant", tenantID, "err", err)
 		metricForwarderPushesFailures.WithLabelValues(tenantID).Inc()
@@ -4,17 +4,26 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/grafana/tempo/modules/distributor/forwarder/file"
+	"github.com/grafana/tempo/modules/distributor/forwarder/kafka"
 	"github.com/grafana/tempo/modules/distributor/forwarder/otlpgrpc"
+	"github.com/grafana/tempo/modules/distributor/forwarder/otlphttp"
 )
 
 const (
 	OTLPGRPCBackend = "otlpgrpc"
+	OTLPHTTPBackend = "otlphttp"
+	KafkaBackend    = "kafka"
+	FileBackend     = "file"
 )
 
 type Config struct {
 	Name     string          `yaml:"name"`
 	Backend  string          `yaml:"backend"`
 	OTLPGRPC otlpgrpc.Config `yaml:"otlpgrpc"`
+	OTLPHTTP otlphttp.Config `yaml:"otlphttp"`
+	Kafka    kafka.Config    `yaml:"kafka"`
+	File     file.Config     `yaml:"file"`
 	Filter   FilterConfig    `yaml:"filter"`
 }
 
@@ -35,6 +44,12 @@ func (cfg *Config) Validate() error {
 	switch cfg.Backend {
 	case OTLPGRPCBackend:
 		return cfg.OTLPGRPC.Validate()
+	case OTLPHTTPBackend:
+		return cfg.OTLPHTTP.Validate()
+	case KafkaBackend:
+		return cfg.Kafka.Validate()
+	case FileBackend:
+		return cfg.File.Validate()
 	default:
 	}
 
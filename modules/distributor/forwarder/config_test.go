@@ -5,7 +5,10 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/tempo/modules/distributor/forwarder/file"
+	"github.com/grafana/tempo/modules/distributor/forwarder/kafka"
 	"github.com/grafana/tempo/modules/distributor/forwarder/otlpgrpc"
+	"github.com/grafana/tempo/modules/distributor/forwarder/otlphttp"
 )
 
 func TestConfig_Validate(t *testing.T) {
@@ -13,6 +16,9 @@ func TestConfig_Validate(t *testing.T) {
 		Name     string
 		Backend  string
 		OTLPGRPC otlpgrpc.Config
+		OTLPHTTP otlphttp.Config
+		Kafka    kafka.Config
+		File     file.Config
 	}
 	tests := []struct {
 		name    string
@@ -48,6 +54,42 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "ReturnsNoErrorWithValidOTLPHTTPArguments",
+			fields: fields{
+				Name:    "test",
+				Backend: OTLPHTTPBackend,
+				OTLPHTTP: otlphttp.Config{
+					Endpoints: []string{"https://otlp.example.com/v1/traces"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ReturnsNoErrorWithValidFileArguments",
+			fields: fields{
+				Name:    "test",
+				Backend: FileBackend,
+				File:    file.Config{Directory: "/var/tempo/forwarder"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ReturnsErrorWithInvalidFileArguments",
+			fields: fields{
+				Name:    "test",
+				Backend: FileBackend,
+			},
+			wantErr: true,
+		},
+		{
+			name: "ReturnsErrorWithInvalidKafkaArguments",
+			fields: fields{
+				Name:    "test",
+				Backend: KafkaBackend,
+			},
+			wantErr: true,
+		},
 		{
 			name: "ReturnsErrorWithUnsupportedBackendName",
 			fields: fields{
@@ -69,6 +111,9 @@ func TestConfig_Validate(t *testing.T) {
 				Name:     tt.fields.Name,
 				Backend:  tt.fields.Backend,
 				OTLPGRPC: tt.fields.OTLPGRPC,
+				OTLPHTTP: tt.fields.OTLPHTTP,
+				Kafka:    tt.fields.Kafka,
+				File:     tt.fields.File,
 			}
 
 			err := cfg.Validate()
@@ -0,0 +1,33 @@
+package file
+
+import (
+	"errors"
+)
+
+const defaultMaxFileSizeBytes = 100 * 1024 * 1024
+
+type Config struct {
+	// Directory the files are written to. Every tenant has its own subdirectory.
+	Directory string `yaml:"directory"`
+	// MaxFileSizeBytes is the size after which a file is rotated. Defaults to 100MiB.
+	MaxFileSizeBytes int64 `yaml:"max_file_size_bytes"`
+	// MaxFiles is the number of files kept per tenant, including the file being written. The
+	// oldest files are deleted on rotation. A value of 0 keeps all files.
+	MaxFiles int `yaml:"max_files"`
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.Directory == "" {
+		return errors.New("directory is empty")
+	}
+
+	if cfg.MaxFileSizeBytes < 0 {
+		return errors.New("max_file_size_bytes must not be negative")
+	}
+
+	if cfg.MaxFiles < 0 {
+		return errors.New("max_files must not be negative")
+	}
+
+	return nil
+}
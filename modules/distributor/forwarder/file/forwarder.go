@@ -0,0 +1,223 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/user"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/multierr"
+)
+
+const (
+	filePrefix = "traces-"
+	fileSuffix = ".jsonl"
+
+	// fileTimeFormat sorts lexically in the order the files were created.
+	fileTimeFormat = "20060102T150405.000000000"
+)
+
+// Forwarder appends the traces as OTLP JSON, one line per request, to files in a directory per
+// tenant. This is the format of the file exporter of the OpenTelemetry Collector.
+type Forwarder struct {
+	cfg    Config
+	logger log.Logger
+
+	mu      sync.Mutex
+	closed  bool
+	tenants map[string]*tenantFile
+}
+
+func NewForwarder(cfg Config, logger log.Logger) (*Forwarder, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate config: %w", err)
+	}
+
+	if cfg.MaxFileSizeBytes == 0 {
+		cfg.MaxFileSizeBytes = defaultMaxFileSizeBytes
+	}
+
+	if err := os.MkdirAll(cfg.Directory, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	return &Forwarder{
+		cfg:     cfg,
+		logger:  logger,
+		tenants: make(map[string]*tenantFile),
+	}, nil
+}
+
+func (f *Forwarder) ForwardTraces(ctx context.Context, traces ptrace.Traces) error {
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to extract tenant: %w", err)
+	}
+
+	line, err := (&ptrace.JSONMarshaler{}).MarshalTraces(traces)
+	if err != nil {
+		return fmt.Errorf("failed to marshal traces: %w", err)
+	}
+	line = append(line, '\n')
+
+	t, err := f.tenantFile(tenantID)
+	if err != nil {
+		return err
+	}
+
+	return t.write(line)
+}
+
+func (f *Forwarder) tenantFile(tenantID string) (*tenantFile, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return nil, errors.New("forwarder is shut down")
+	}
+
+	if t, ok := f.tenants[tenantID]; ok {
+		return t, nil
+	}
+
+	dir := filepath.Join(f.cfg.Directory, tenantID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create tenant directory: %w", err)
+	}
+
+	t := &tenantFile{
+		dir:          dir,
+		maxSizeBytes: f.cfg.MaxFileSizeBytes,
+		maxFiles:     f.cfg.MaxFiles,
+		logger:       log.With(f.logger, "tenant", tenantID),
+	}
+	f.tenants[tenantID] = t
+
+	return t, nil
+}
+
+func (f *Forwarder) Shutdown(_ context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+
+	var errs []error
+	for tenantID, t := range f.tenants {
+		if err := t.close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close file for tenant=%s: %w", tenantID, err))
+		}
+
+		delete(f.tenants, tenantID)
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// tenantFile is the file of a tenant that is currently written. The file is rotated once it
+// reaches the max size.
+type tenantFile struct {
+	dir          string
+	maxSizeBytes int64
+	maxFiles     int
+	logger       log.Logger
+
+	mu     sync.Mutex
+	closed bool
+	file   *os.File
+	size   int64
+}
+
+func (t *tenantFile) write(line []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return errors.New("forwarder is shut down")
+	}
+
+	if t.file == nil || (t.size > 0 && t.size+int64(len(line)) > t.maxSizeBytes) {
+		if err := t.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := t.file.Write(line)
+	t.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write to file=%s: %w", t.file.Name(), err)
+	}
+
+	return nil
+}
+
+// rotate closes the current file, opens a new one and deletes the oldest files over maxFiles.
+// Must be called with mu held.
+func (t *tenantFile) rotate() error {
+	if t.file != nil {
+		if err := t.file.Close(); err != nil {
+			_ = level.Warn(t.logger).Log("msg", "failed to close file", "file", t.file.Name(), "err", err)
+		}
+		t.file = nil
+	}
+
+	name := filepath.Join(t.dir, filePrefix+time.Now().UTC().Format(fileTimeFormat)+fileSuffix)
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	t.file = file
+	t.size = 0
+
+	if t.maxFiles > 0 {
+		t.deleteOldFiles()
+	}
+
+	return nil
+}
+
+func (t *tenantFile) deleteOldFiles() {
+	entries, err := os.ReadDir(t.dir)
+	if err != nil {
+		_ = level.Warn(t.logger).Log("msg", "failed to list files", "dir", t.dir, "err", err)
+		return
+	}
+
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), filePrefix) && strings.HasSuffix(e.Name(), fileSuffix) {
+			files = append(files, e.Name())
+		}
+	}
+	slices.Sort(files)
+
+	for len(files) > t.maxFiles {
+		if err := os.Remove(filepath.Join(t.dir, files[0])); err != nil {
+			_ = level.Warn(t.logger).Log("msg", "failed to delete file", "file", files[0], "err", err)
+		}
+		files = files[1:]
+	}
+}
+
+func (t *tenantFile) close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closed = true
+	if t.file == nil {
+		return nil
+	}
+
+	err := t.file.Close()
+	t.file = nil
+	return err
+}
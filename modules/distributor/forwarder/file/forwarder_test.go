@@ -0,0 +1,95 @@
+package file
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func newTraces(name string) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName(name)
+	return traces
+}
+
+// readTraces returns the traces of all files in dir, in the order they were written.
+func readTraces(t *testing.T, dir string) [][]ptrace.Traces {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, filePrefix+"*"+fileSuffix))
+	require.NoError(t, err)
+
+	var traces [][]ptrace.Traces
+	for _, name := range files {
+		file, err := os.Open(name)
+		require.NoError(t, err)
+
+		var fileTraces []ptrace.Traces
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			tr, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(scanner.Bytes())
+			require.NoError(t, err)
+			fileTraces = append(fileTraces, tr)
+		}
+		require.NoError(t, scanner.Err())
+		require.NoError(t, file.Close())
+
+		traces = append(traces, fileTraces)
+	}
+	return traces
+}
+
+func TestForwarder(t *testing.T) {
+	dir := t.TempDir()
+
+	f, err := NewForwarder(Config{Directory: dir}, log.NewNopLogger())
+	require.NoError(t, err)
+
+	for _, tenant := range []string{"tenant-1", "tenant-2"} {
+		ctx := user.InjectOrgID(context.Background(), tenant)
+		require.NoError(t, f.ForwardTraces(ctx, newTraces(tenant+"-a")))
+		require.NoError(t, f.ForwardTraces(ctx, newTraces(tenant+"-b")))
+	}
+	require.NoError(t, f.Shutdown(context.Background()))
+
+	for _, tenant := range []string{"tenant-1", "tenant-2"} {
+		require.Equal(t, [][]ptrace.Traces{{newTraces(tenant + "-a"), newTraces(tenant + "-b")}}, readTraces(t, filepath.Join(dir, tenant)))
+	}
+
+	// writes after shutdown fail
+	require.Error(t, f.ForwardTraces(user.InjectOrgID(context.Background(), "tenant-1"), newTraces("c")))
+	// the tenant is required
+	require.Error(t, f.ForwardTraces(context.Background(), newTraces("c")))
+}
+
+func TestForwarder_rotation(t *testing.T) {
+	dir := t.TempDir()
+
+	line, err := (&ptrace.JSONMarshaler{}).MarshalTraces(newTraces("0"))
+	require.NoError(t, err)
+
+	// every file fits two requests
+	f, err := NewForwarder(Config{Directory: dir, MaxFileSizeBytes: int64(2*len(line) + 2), MaxFiles: 2}, log.NewNopLogger())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, f.Shutdown(context.Background()))
+	})
+
+	ctx := user.InjectOrgID(context.Background(), "tenant")
+	for _, name := range []string{"0", "1", "2", "3", "4"} {
+		require.NoError(t, f.ForwardTraces(ctx, newTraces(name)))
+	}
+
+	// the first file was deleted
+	require.Equal(t, [][]ptrace.Traces{
+		{newTraces("2"), newTraces("3")},
+		{newTraces("4")},
+	}, readTraces(t, filepath.Join(dir, "tenant")))
+}
@@ -11,6 +11,7 @@ import (
 	zaplogfmt "github.com/jsternberg/zap-logfmt"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/filterprocessor"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -21,7 +22,10 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/grafana/tempo/modules/distributor/forwarder/file"
+	"github.com/grafana/tempo/modules/distributor/forwarder/kafka"
 	"github.com/grafana/tempo/modules/distributor/forwarder/otlpgrpc"
+	"github.com/grafana/tempo/modules/distributor/forwarder/otlphttp"
 )
 
 type Forwarder interface {
@@ -62,6 +66,28 @@ func New(cfg Config, logger log.Logger, logLevel dslog.Level) (Forwarder, error)
 			return nil, fmt.Errorf("failed to dial: %w", err)
 		}
 
+		forwarder = f
+	case OTLPHTTPBackend:
+		f, err := otlphttp.NewForwarder(cfg.OTLPHTTP, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new otlphttp forwarder: %w", err)
+		}
+
+		forwarder = f
+	case KafkaBackend:
+		reg := prometheus.WrapRegistererWith(prometheus.Labels{"forwarder": cfg.Name}, prometheus.WrapRegistererWithPrefix("tempo_distributor_forwarder_kafka_", prometheus.DefaultRegisterer))
+		f, err := kafka.NewForwarder(cfg.Kafka, logger, reg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new kafka forwarder: %w", err)
+		}
+
+		forwarder = f
+	case FileBackend:
+		f, err := file.NewForwarder(cfg.File, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new file forwarder: %w", err)
+		}
+
 		forwarder = f
 	default:
 		return nil, fmt.Errorf("%s backend is not supported", cfg.Backend)
@@ -0,0 +1,34 @@
+package kafka
+
+import (
+	"flag"
+
+	"github.com/grafana/dskit/flagext"
+
+	"github.com/grafana/tempo/pkg/ingest"
+)
+
+// Config is the Kafka client config of the forwarder. It supports the same settings as the
+// ingest Kafka config, including TLS and SASL, with the same defaults.
+type Config struct {
+	ingest.KafkaConfig `yaml:",inline"`
+}
+
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	cfg.KafkaConfig.RegisterFlags(f)
+
+	// The forwarder doesn't change the configuration of the brokers.
+	cfg.AutoCreateTopicDefaultPartitions = 0
+}
+
+// UnmarshalYAML applies the defaults before unmarshalling, forwarders aren't registered as flags.
+func (cfg *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	flagext.DefaultValues(cfg)
+
+	type rawConfig Config
+	return unmarshal((*rawConfig)(cfg))
+}
+
+func (cfg *Config) Validate() error {
+	return cfg.KafkaConfig.Validate()
+}
@@ -0,0 +1,73 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+
+	"github.com/grafana/tempo/pkg/ingest"
+)
+
+// TenantHeader is the record header with the tenant ID of the traces.
+const TenantHeader = "tenant"
+
+// Forwarder writes the traces as OTLP protobuf export requests to a Kafka topic, one record per
+// request. Records don't have a key and are spread over the partitions.
+type Forwarder struct {
+	cfg    Config
+	logger log.Logger
+	client *kgo.Client
+}
+
+// NewForwarder creates a Kafka client. The metrics of the client are registered with reg.
+func NewForwarder(cfg Config, logger log.Logger, reg prometheus.Registerer) (*Forwarder, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate config: %w", err)
+	}
+
+	client, err := ingest.NewWriterClient(cfg.KafkaConfig, 10, logger, reg,
+		// the writer client expects records with a partition set
+		kgo.RecordPartitioner(kgo.StickyKeyPartitioner(nil)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+
+	return &Forwarder{
+		cfg:    cfg,
+		logger: logger,
+		client: client,
+	}, nil
+}
+
+func (f *Forwarder) ForwardTraces(ctx context.Context, traces ptrace.Traces) error {
+	value, err := ptraceotlp.NewExportRequestFromTraces(traces).MarshalProto()
+	if err != nil {
+		return fmt.Errorf("failed to marshal traces: %w", err)
+	}
+	if len(value) > f.cfg.ProducerMaxRecordSizeBytes {
+		return fmt.Errorf("traces of %d bytes exceed the max record size of %d bytes", len(value), f.cfg.ProducerMaxRecordSizeBytes)
+	}
+
+	record := &kgo.Record{Value: value}
+	if tenantID, err := user.ExtractOrgID(ctx); err == nil {
+		record.Headers = append(record.Headers, kgo.RecordHeader{Key: TenantHeader, Value: []byte(tenantID)})
+	}
+
+	if err := f.client.ProduceSync(ctx, record).FirstErr(); err != nil {
+		return fmt.Errorf("failed to write traces to topic=%s: %w", f.cfg.Topic, err)
+	}
+
+	return nil
+}
+
+func (f *Forwarder) Shutdown(_ context.Context) error {
+	f.client.Close()
+	return nil
+}
@@ -0,0 +1,100 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.yaml.in/yaml/v2"
+
+	"github.com/grafana/tempo/pkg/ingest/testkafka"
+)
+
+const topic = "archive"
+
+func TestConfig_UnmarshalYAML(t *testing.T) {
+	cfg := Config{}
+	require.NoError(t, yaml.UnmarshalStrict([]byte(`
+address: kafka:9092
+topic: archive
+sasl_mechanism: SCRAM-SHA-512
+sasl_username: user
+sasl_password: pass
+tls_enabled: true
+`), &cfg))
+
+	require.Equal(t, "kafka:9092", cfg.Address)
+	require.Equal(t, "archive", cfg.Topic)
+	require.Equal(t, "SCRAM-SHA-512", cfg.SASLMechanism)
+	require.True(t, cfg.TLSEnabled)
+
+	// defaults of the ingest Kafka config
+	require.Equal(t, 10*time.Second, cfg.WriteTimeout)
+	require.Positive(t, cfg.ProducerMaxRecordSizeBytes)
+	// the forwarder doesn't change the configuration of the brokers
+	require.Zero(t, cfg.AutoCreateTopicDefaultPartitions)
+
+	require.NoError(t, cfg.Validate())
+}
+
+func TestForwarder(t *testing.T) {
+	_, addr := testkafka.CreateCluster(t, 2, topic)
+
+	cfg := Config{}
+	require.NoError(t, yaml.UnmarshalStrict([]byte("address: "+addr+"\ntopic: "+topic), &cfg))
+
+	f, err := NewForwarder(cfg, log.NewNopLogger(), prometheus.NewRegistry())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, f.Shutdown(context.Background()))
+	})
+
+	traces := ptrace.NewTraces()
+	traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("test")
+
+	ctx := user.InjectOrgID(context.Background(), "tenant")
+	require.NoError(t, f.ForwardTraces(ctx, traces))
+
+	reader, err := kgo.NewClient(kgo.SeedBrokers(addr), kgo.ConsumeTopics(topic))
+	require.NoError(t, err)
+	t.Cleanup(reader.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	fetches := reader.PollFetches(ctx)
+	require.NoError(t, fetches.Err())
+
+	records := fetches.Records()
+	require.Len(t, records, 1)
+	require.Equal(t, []kgo.RecordHeader{{Key: TenantHeader, Value: []byte("tenant")}}, records[0].Headers)
+
+	req := ptraceotlp.NewExportRequest()
+	require.NoError(t, req.UnmarshalProto(records[0].Value))
+	require.Equal(t, traces, req.Traces())
+}
+
+func TestForwarder_recordTooLarge(t *testing.T) {
+	_, addr := testkafka.CreateCluster(t, 1, topic)
+
+	cfg := Config{}
+	require.NoError(t, yaml.UnmarshalStrict([]byte("address: "+addr+"\ntopic: "+topic), &cfg))
+
+	f, err := NewForwarder(cfg, log.NewNopLogger(), prometheus.NewRegistry())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, f.Shutdown(context.Background()))
+	})
+	f.cfg.ProducerMaxRecordSizeBytes = 10
+
+	traces := ptrace.NewTraces()
+	traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("a span name longer than the limit")
+
+	require.ErrorContains(t, f.ForwardTraces(context.Background(), traces), "exceed the max record size of 10 bytes")
+}
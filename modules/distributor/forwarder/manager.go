@@ -10,6 +10,8 @@ import (
 	"github.com/go-kit/log/level"
 	dslog "github.com/grafana/dskit/log"
 	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/multierr"
 
@@ -20,6 +22,26 @@ import (
 const (
 	defaultWorkerCount = 2
 	defaultQueueSize   = 100
+
+	dropReasonEnqueueFailed = "enqueue_failed"
+	dropReasonForwardFailed = "forward_failed"
+)
+
+var (
+	metricForwarderDroppedSpans = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_forwarder_dropped_spans_total",
+		Help:      "The total number of spans dropped by a forwarder per tenant and reason",
+	}, []string{"forwarder", "tenant", "reason"})
+	metricForwarderRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:                       "tempo",
+		Name:                            "distributor_forwarder_request_duration_seconds",
+		Help:                            "Time taken by a forwarder to forward a batch of traces",
+		Buckets:                         prometheus.DefBuckets,
+		NativeHistogramBucketFactor:     1.1,
+		NativeHistogramMaxBucketNumber:  100,
+		NativeHistogramMinResetDuration: 1 * time.Hour,
+	}, []string{"forwarder", "result"})
 )
 
 type Overrides interface {
@@ -232,14 +254,20 @@ func newQueueList(logger log.Logger, tenantID string, forwarderNames []string, f
 		}
 
 		processFunc := func(ctx context.Context, traces ptrace.Traces) {
-			if err := forwarder.ForwardTraces(ctx, traces); err != nil {
+			start := time.Now()
+			err := forwarder.ForwardTraces(ctx, traces)
+			if err != nil {
+				metricForwarderRequestDuration.WithLabelValues(forwarderName, "error").Observe(time.Since(start).Seconds())
+				metricForwarderDroppedSpans.WithLabelValues(forwarderName, tenantID, dropReasonForwardFailed).Add(float64(traces.SpanCount()))
 				_ = level.Warn(logger).Log("msg", "failed to forward batches", "forwarderName", forwarderName, "tenantID", tenantID, "err", err)
+				return
 			}
+			metricForwarderRequestDuration.WithLabelValues(forwarderName, "success").Observe(time.Since(start).Seconds())
 		}
 		newQueue := queue.New(queueCfg, logger, processFunc)
 		newQueue.StartWorkers()
 		forwarderNameToQueue[forwarderName] = newQueue
-		list = append(list, queueAdapter{queue: newQueue})
+		list = append(list, queueAdapter{queue: newQueue, forwarderName: forwarderName, tenantID: tenantID})
 	}
 
 	return &queueList{
@@ -278,11 +306,18 @@ func (l *queueList) shutdown(ctx context.Context) error {
 }
 
 type queueAdapter struct {
-	queue *queue.Queue[ptrace.Traces]
+	queue         *queue.Queue[ptrace.Traces]
+	forwarderName string
+	tenantID      string
 }
 
 func (a queueAdapter) ForwardTraces(ctx context.Context, traces ptrace.Traces) error {
-	return a.queue.Push(ctx, traces)
+	if err := a.queue.Push(ctx, traces); err != nil {
+		metricForwarderDroppedSpans.WithLabelValues(a.forwarderName, a.tenantID, dropReasonEnqueueFailed).Add(float64(traces.SpanCount()))
+		return err
+	}
+
+	return nil
 }
 
 // Shutdown does nothing. Queue lifecycle is handled by queueList.
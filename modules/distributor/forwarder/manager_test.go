@@ -2,11 +2,14 @@ package forwarder
 
 import (
 	"context"
+	"errors"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/go-kit/log"
 	dslog "github.com/grafana/dskit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 )
@@ -540,3 +543,40 @@ func TestManager_ForTenant_List_ForwardTraces_ReturnsNoErrorAndCorrectlyDoesNotF
 	require.Len(t, forwarder1Ch, 0)
 	require.Len(t, forwarder2Ch, 0)
 }
+
+func TestManager_ForTenant_List_ForwardTraces_CountsSpansDroppedByFailingForwarder(t *testing.T) {
+	// Given
+	logger := log.NewNopLogger()
+	o := &mockWorkingOverrides{
+		tenantIDs: []string{"droppedTenantID"},
+		tenantIDToForwarders: map[string][]string{
+			"droppedTenantID": {"failingForwarder"},
+		},
+	}
+
+	forwarderCh := make(chan ptrace.Traces)
+	forwarder := &mockChannelledInterceptorForwarder{
+		next:   &mockFailingForwarder{forwardTracesErr: errors.New("forward error")},
+		traces: forwarderCh,
+	}
+
+	forwarderNameToForwarder := map[string]Forwarder{
+		"failingForwarder": forwarder,
+	}
+
+	traces := ptrace.NewTraces()
+	spans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	spans.AppendEmpty()
+	spans.AppendEmpty()
+	manager := newManagerWithForwarders(t, forwarderNameToForwarder, logger, o)
+
+	// When
+	err := manager.ForTenant("droppedTenantID").ForwardTraces(context.Background(), traces)
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, traces, <-forwarderCh)
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(metricForwarderDroppedSpans.WithLabelValues("failingForwarder", "droppedTenantID", dropReasonForwardFailed)) == 2
+	}, time.Second, 10*time.Millisecond)
+}
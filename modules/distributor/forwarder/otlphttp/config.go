@@ -0,0 +1,61 @@
+package otlphttp
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+)
+
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+
+	defaultTimeout = 10 * time.Second
+)
+
+type Config struct {
+	// Endpoints are the full URLs the traces are posted to, e.g. https://host:4318/v1/traces.
+	Endpoints   flagext.StringSlice `yaml:"endpoints"`
+	Headers     map[string]string   `yaml:"headers"`
+	Compression string              `yaml:"compression"`
+	Timeout     time.Duration       `yaml:"timeout"`
+	TLS         TLSConfig           `yaml:"tls"`
+}
+
+func (cfg *Config) Validate() error {
+	if len(cfg.Endpoints) == 0 {
+		return errors.New("endpoints are empty")
+	}
+
+	for _, endpoint := range cfg.Endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid endpoint=%s: %w", endpoint, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("invalid endpoint=%s: scheme must be http or https", endpoint)
+		}
+	}
+
+	switch cfg.Compression {
+	case "", CompressionNone, CompressionGzip:
+	default:
+		return fmt.Errorf("compression %s is not supported", cfg.Compression)
+	}
+
+	if cfg.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+
+	return nil
+}
+
+type TLSConfig struct {
+	// CertFile is the CA certificate used to verify https endpoints. The host's root CA
+	// certificates are used if it's empty.
+	CertFile           string `yaml:"cert_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
@@ -0,0 +1,54 @@
+package otlphttp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{
+			name: "ReturnsNoErrorForValidConfig",
+			cfg: Config{
+				Endpoints:   []string{"http://localhost:4318/v1/traces", "https://otlp.example.com/v1/traces"},
+				Compression: CompressionGzip,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "ReturnsErrorWithNoEndpoints",
+			cfg:     Config{},
+			wantErr: true,
+		},
+		{
+			name: "ReturnsErrorWithInvalidScheme",
+			cfg: Config{
+				Endpoints: []string{"localhost:4318"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ReturnsErrorWithUnsupportedCompression",
+			cfg: Config{
+				Endpoints:   []string{"http://localhost:4318/v1/traces"},
+				Compression: "zstd",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package otlphttp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.uber.org/multierr"
+)
+
+// maxErrorBodyBytes is the maximum number of bytes of an error response included in the error.
+const maxErrorBodyBytes = 1024
+
+type Forwarder struct {
+	cfg    Config
+	logger log.Logger
+	client *http.Client
+}
+
+func NewForwarder(cfg Config, logger log.Logger) (*Forwarder, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate config: %w", err)
+	}
+
+	tlsCfg := &tls.Config{InsecureSkipVerify: cfg.TLS.InsecureSkipVerify}
+	if cfg.TLS.CertFile != "" {
+		cert, err := os.ReadFile(cfg.TLS.CertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cert_file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(cert) {
+			return nil, errors.New("failed to parse cert_file")
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsCfg
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	return &Forwarder{
+		cfg:    cfg,
+		logger: logger,
+		client: &http.Client{
+			Transport: otelhttp.NewTransport(transport),
+			Timeout:   timeout,
+		},
+	}, nil
+}
+
+func (f *Forwarder) ForwardTraces(ctx context.Context, traces ptrace.Traces) error {
+	body, err := ptraceotlp.NewExportRequestFromTraces(traces).MarshalProto()
+	if err != nil {
+		return fmt.Errorf("failed to marshal traces: %w", err)
+	}
+
+	if f.cfg.Compression == CompressionGzip {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(body); err != nil {
+			return fmt.Errorf("failed to compress traces: %w", err)
+		}
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("failed to compress traces: %w", err)
+		}
+		body = buf.Bytes()
+	}
+
+	var errs []error
+	for _, endpoint := range f.cfg.Endpoints {
+		if err := f.post(ctx, endpoint, body); err != nil {
+			errs = append(errs, fmt.Errorf("failed to export trace to endpoint=%s: %w", endpoint, err))
+		}
+	}
+
+	return multierr.Combine(errs...)
+}
+
+func (f *Forwarder) post(ctx context.Context, endpoint string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for k, v := range f.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	if f.cfg.Compression == CompressionGzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	// propagate the tenant like the otlpgrpc forwarder does
+	if tenantID, err := user.ExtractOrgID(ctx); err == nil {
+		req.Header.Set(user.OrgIDHeaderName, tenantID)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, msg)
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func (f *Forwarder) Shutdown(_ context.Context) error {
+	f.client.CloseIdleConnections()
+	return nil
+}
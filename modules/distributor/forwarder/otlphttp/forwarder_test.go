@@ -0,0 +1,121 @@
+package otlphttp
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+type mockHTTPServer struct {
+	mu         sync.Mutex
+	statusCode int
+	headers    http.Header
+	req        ptraceotlp.ExportRequest
+}
+
+func (m *mockHTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.headers = r.Header.Clone()
+
+	body := r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = gz
+	}
+	b, err := io.ReadAll(body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	m.req = ptraceotlp.NewExportRequest()
+	if err := m.req.UnmarshalProto(b); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if m.statusCode != 0 {
+		w.WriteHeader(m.statusCode)
+		_, _ = w.Write([]byte("rejected"))
+	}
+}
+
+func newServer(t *testing.T, srv *mockHTTPServer) string {
+	t.Helper()
+
+	s := httptest.NewServer(srv)
+	t.Cleanup(s.Close)
+
+	return s.URL + "/v1/traces"
+}
+
+func newTraces() ptrace.Traces {
+	traces := ptrace.NewTraces()
+	span := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("test")
+	return traces
+}
+
+func TestForwarder(t *testing.T) {
+	for _, compression := range []string{"", CompressionGzip} {
+		t.Run("compression="+compression, func(t *testing.T) {
+			srv := &mockHTTPServer{}
+			cfg := Config{
+				Endpoints:   []string{newServer(t, srv)},
+				Headers:     map[string]string{"Authorization": "Bearer token"},
+				Compression: compression,
+			}
+
+			f, err := NewForwarder(cfg, log.NewNopLogger())
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, f.Shutdown(context.Background()))
+			})
+
+			ctx := user.InjectOrgID(context.Background(), "tenant")
+			require.NoError(t, f.ForwardTraces(ctx, newTraces()))
+
+			srv.mu.Lock()
+			defer srv.mu.Unlock()
+			require.Equal(t, "Bearer token", srv.headers.Get("Authorization"))
+			require.Equal(t, "tenant", srv.headers.Get(user.OrgIDHeaderName))
+			require.Equal(t, "application/x-protobuf", srv.headers.Get("Content-Type"))
+			require.Equal(t, newTraces(), srv.req.Traces())
+		})
+	}
+}
+
+func TestForwarder_returnsErrorForFailedEndpoint(t *testing.T) {
+	ok := &mockHTTPServer{}
+	failing := &mockHTTPServer{statusCode: http.StatusServiceUnavailable}
+	cfg := Config{
+		Endpoints: []string{newServer(t, ok), newServer(t, failing)},
+	}
+
+	f, err := NewForwarder(cfg, log.NewNopLogger())
+	require.NoError(t, err)
+
+	err = f.ForwardTraces(context.Background(), newTraces())
+	require.ErrorContains(t, err, "unexpected status code 503: rejected")
+
+	// the traces are still sent to the healthy endpoint
+	ok.mu.Lock()
+	defer ok.mu.Unlock()
+	require.Equal(t, newTraces(), ok.req.Traces())
+}
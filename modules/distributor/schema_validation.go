@@ -0,0 +1,566 @@
+package distributor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/modules/overrides"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	// schemaQualityWindow is how often the quality scores start a new window. Scores are calculated
+	// from the spans of the current and the previous window.
+	schemaQualityWindow = 5 * time.Minute
+
+	// maxSchemaQualityServices is the number of services per tenant with a quality score. The other
+	// services share the score of schemaQualityOverflowService.
+	maxSchemaQualityServices     = 1000
+	schemaQualityOverflowService = "__overflow__"
+	schemaQualityUnknownService  = "unknown_service"
+
+	// schemaViolationsAttribute is the span attribute with the names of the violated rules with action
+	// tag.
+	schemaViolationsAttribute = "tempo.schema_violations"
+)
+
+var (
+	metricSchemaViolations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_schema_violations_total",
+		Help:      "The total number of spans violating a schema validation rule per tenant, rule and action.",
+	}, []string{"tenant", "rule", "action"})
+	metricSchemaQualityScore = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "distributor_schema_quality_score",
+		Help:      "The ratio of spans without schema violations per tenant and service.",
+	}, []string{"tenant", "service"})
+)
+
+var schemaValidationValueTypes = []string{"string", "int", "double", "bool", "array", "kvlist", "bytes"}
+
+// schemaValidator validates the received spans against the schema validation rules of the tenant and
+// keeps a quality score for every service. The compiled rules are cached per tenant and compiled
+// again when the overrides change.
+type schemaValidator struct {
+	services.Service
+
+	logger log.Logger
+
+	mtx     sync.Mutex
+	tenants map[string]*schemaValidationTenant
+}
+
+type schemaValidationTenant struct {
+	mtx sync.Mutex
+	// cfg is the config the rules were compiled from
+	cfg   overrides.SchemaValidationOverrides
+	rules []*schemaValidationRule
+
+	services map[string]*schemaQualityService
+}
+
+type schemaQualityService struct {
+	current, previous schemaQualityCounts
+	// spanNames are the span names seen by every span_name_cardinality rule in the current window
+	spanNames map[string]map[string]struct{}
+}
+
+type schemaQualityCounts struct {
+	spans          int
+	violatingSpans int
+	// violations are the spans violating every rule
+	violations map[string]int
+}
+
+func (c *schemaQualityCounts) add(o schemaQualityCounts) {
+	c.spans += o.spans
+	c.violatingSpans += o.violatingSpans
+	for rule, n := range o.violations {
+		if c.violations == nil {
+			c.violations = make(map[string]int)
+		}
+		c.violations[rule] += n
+	}
+}
+
+func (c schemaQualityCounts) score() float64 {
+	if c.spans == 0 {
+		return 1
+	}
+	return 1 - float64(c.violatingSpans)/float64(c.spans)
+}
+
+func newSchemaValidator(logger log.Logger) *schemaValidator {
+	v := &schemaValidator{
+		logger:  logger,
+		tenants: make(map[string]*schemaValidationTenant),
+	}
+	v.Service = services.NewTimerService(schemaQualityWindow, nil, v.iteration, nil)
+	return v
+}
+
+// Validate checks the spans of the batches against the rules of the tenant and applies the action
+// of the violated rules. It returns the batches without the rejected spans, the number of rejected
+// spans and whether any span was rejected or tagged. Only the span names and the quality scores are
+// updated holding the lock of the tenant.
+func (v *schemaValidator) Validate(tenantID string, cfg overrides.SchemaValidationOverrides, batches []*v1.ResourceSpans) ([]*v1.ResourceSpans, int, bool) {
+	t := v.tenant(tenantID)
+
+	rules := t.compile(tenantID, cfg, v.logger)
+	if len(rules) == 0 {
+		return batches, 0, false
+	}
+
+	var (
+		rejected   int
+		tagged     bool
+		violations = make(map[*schemaValidationRule]int, len(rules))
+		scores     = make(map[string]float64)
+		// violated are the violated rules of every span of a resource
+		violated [][]*schemaValidationRule
+	)
+
+	keptBatches := batches[:0]
+	for _, rs := range batches {
+		resourceAttrs := rs.GetResource().GetAttributes()
+		serviceName := schemaQualityUnknownService
+		for _, a := range resourceAttrs {
+			if a.Key == "service.name" && a.GetValue().GetStringValue() != "" {
+				serviceName = a.GetValue().GetStringValue()
+				break
+			}
+		}
+
+		violated = violated[:0]
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				var spanViolated []*schemaValidationRule
+				for _, r := range rules {
+					if r.violatedBy(resourceAttrs, span) {
+						spanViolated = append(spanViolated, r)
+					}
+				}
+				violated = append(violated, spanViolated)
+			}
+		}
+
+		serviceName, score := t.record(serviceName, rules, rs, violated)
+		scores[serviceName] = score
+
+		i := 0
+		keptScopes := rs.ScopeSpans[:0]
+		for _, ss := range rs.ScopeSpans {
+			keptSpans := ss.Spans[:0]
+			for _, span := range ss.Spans {
+				spanViolated := violated[i]
+				i++
+
+				for _, r := range spanViolated {
+					violations[r]++
+				}
+				if slices.ContainsFunc(spanViolated, func(r *schemaValidationRule) bool { return r.action == overrides.SchemaValidationActionReject }) {
+					rejected++
+					continue
+				}
+				if tagSchemaViolations(span, spanViolated) {
+					tagged = true
+				}
+				keptSpans = append(keptSpans, span)
+			}
+
+			if len(keptSpans) > 0 {
+				ss.Spans = keptSpans
+				keptScopes = append(keptScopes, ss)
+			}
+		}
+
+		if len(keptScopes) > 0 {
+			rs.ScopeSpans = keptScopes
+			keptBatches = append(keptBatches, rs)
+		}
+	}
+
+	for r, n := range violations {
+		metricSchemaViolations.WithLabelValues(tenantID, r.cfg.Name, r.action).Add(float64(n))
+	}
+	for serviceName, score := range scores {
+		metricSchemaQualityScore.WithLabelValues(tenantID, serviceName).Set(score)
+	}
+
+	return keptBatches, rejected, rejected > 0 || tagged
+}
+
+func (v *schemaValidator) tenant(tenantID string) *schemaValidationTenant {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	t, ok := v.tenants[tenantID]
+	if !ok {
+		t = &schemaValidationTenant{services: make(map[string]*schemaQualityService)}
+		v.tenants[tenantID] = t
+	}
+	return t
+}
+
+// compile compiles the rules if the config changed and returns them. The span names of the services
+// are reset, because they are kept per rule. The returned rules are not changed by later calls.
+func (t *schemaValidationTenant) compile(tenantID string, cfg overrides.SchemaValidationOverrides, logger log.Logger) []*schemaValidationRule {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.rules != nil && reflect.DeepEqual(t.cfg, cfg) {
+		return t.rules
+	}
+
+	t.cfg = overrides.SchemaValidationOverrides{Action: cfg.Action, Rules: slices.Clone(cfg.Rules)}
+	t.rules = make([]*schemaValidationRule, 0, len(cfg.Rules))
+	for i, c := range cfg.Rules {
+		r, err := newSchemaValidationRule(c, cfg.Action)
+		if err != nil {
+			_ = level.Warn(logger).Log("msg", "skipping invalid schema validation rule", "tenant", tenantID, "index", i, "err", err)
+			continue
+		}
+		t.rules = append(t.rules, r)
+	}
+
+	for _, s := range t.services {
+		s.spanNames = make(map[string]map[string]struct{})
+	}
+	return t.rules
+}
+
+// record checks the span_name_cardinality rules and adds the spans of the resource to the quality of
+// its service. violated holds the violated rules of every span of the resource, the violated
+// span_name_cardinality rules are added to it. It returns the name and the score of the service.
+func (t *schemaValidationTenant) record(serviceName string, rules []*schemaValidationRule, rs *v1.ResourceSpans, violated [][]*schemaValidationRule) (string, float64) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	serviceName, service := t.service(serviceName)
+
+	i := 0
+	for _, ss := range rs.ScopeSpans {
+		for _, span := range ss.Spans {
+			for _, r := range rules {
+				if r.cfg.Type == overrides.SchemaValidationRuleSpanNameCardinality && service.newSpanName(r, span.Name) {
+					violated[i] = append(violated[i], r)
+				}
+			}
+
+			service.current.spans++
+			if len(violated[i]) > 0 {
+				service.current.violatingSpans++
+			}
+			for _, r := range violated[i] {
+				service.current.violations[r.cfg.Name]++
+			}
+			i++
+		}
+	}
+
+	return serviceName, service.counts().score()
+}
+
+// service returns the quality of the service, or of the overflow service if the tenant has reached
+// the max number of services. Must be called with mtx held.
+func (t *schemaValidationTenant) service(name string) (string, *schemaQualityService) {
+	s, ok := t.services[name]
+	if ok {
+		return name, s
+	}
+	if len(t.services) >= maxSchemaQualityServices {
+		name = schemaQualityOverflowService
+		if s, ok = t.services[name]; ok {
+			return name, s
+		}
+	}
+
+	s = &schemaQualityService{
+		current:   schemaQualityCounts{violations: make(map[string]int)},
+		spanNames: make(map[string]map[string]struct{}),
+	}
+	t.services[name] = s
+	return name, s
+}
+
+func (s *schemaQualityService) counts() schemaQualityCounts {
+	c := schemaQualityCounts{}
+	c.add(s.previous)
+	c.add(s.current)
+	return c
+}
+
+// iteration starts a new window of the quality scores and the span names. Services without spans in
+// the last two windows are removed.
+func (v *schemaValidator) iteration(_ context.Context) error {
+	v.rotate()
+	return nil
+}
+
+func (v *schemaValidator) rotate() {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	for tenantID, t := range v.tenants {
+		t.mtx.Lock()
+		for serviceName, s := range t.services {
+			s.previous = s.current
+			s.current = schemaQualityCounts{violations: make(map[string]int)}
+			// the max span names apply to the span names of a window
+			s.spanNames = make(map[string]map[string]struct{})
+
+			if s.previous.spans == 0 {
+				delete(t.services, serviceName)
+				metricSchemaQualityScore.DeleteLabelValues(tenantID, serviceName)
+				continue
+			}
+			metricSchemaQualityScore.WithLabelValues(tenantID, serviceName).Set(s.counts().score())
+		}
+		t.mtx.Unlock()
+	}
+}
+
+type schemaQualityServiceStatus struct {
+	Spans          int            `json:"spans"`
+	ViolatingSpans int            `json:"violating_spans"`
+	Score          float64        `json:"score"`
+	Violations     map[string]int `json:"violations,omitempty"`
+}
+
+// status returns the quality of every service of every tenant, or of a single tenant if tenantID is
+// set.
+func (v *schemaValidator) status(tenantID string) map[string]map[string]schemaQualityServiceStatus {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	tenants := make(map[string]map[string]schemaQualityServiceStatus)
+	for id, t := range v.tenants {
+		if tenantID != "" && id != tenantID {
+			continue
+		}
+
+		t.mtx.Lock()
+		services := make(map[string]schemaQualityServiceStatus, len(t.services))
+		for name, s := range t.services {
+			c := s.counts()
+			services[name] = schemaQualityServiceStatus{
+				Spans:          c.spans,
+				ViolatingSpans: c.violatingSpans,
+				Score:          c.score(),
+				Violations:     c.violations,
+			}
+		}
+		t.mtx.Unlock()
+
+		tenants[id] = services
+	}
+	return tenants
+}
+
+// Handler shows the quality scores and the violations per rule of every service of the last one to
+// two windows. Use the tenant query parameter to show a single tenant.
+func (v *schemaValidator) Handler(w http.ResponseWriter, req *http.Request) {
+	tenant := req.URL.Query().Get("tenant")
+
+	tenants := v.status(tenant)
+	if tenant != "" && len(tenants) == 0 {
+		http.Error(w, "tenant not found: "+tenant, http.StatusNotFound)
+		return
+	}
+
+	util.WriteJSONResponse(w, tenants)
+}
+
+type schemaValidationRule struct {
+	cfg    overrides.SchemaValidationRule
+	action string
+	// resource is set if the attribute of the rule is a resource attribute
+	resource bool
+}
+
+func newSchemaValidationRule(cfg overrides.SchemaValidationRule, defaultAction string) (*schemaValidationRule, error) {
+	r := &schemaValidationRule{
+		cfg:    cfg,
+		action: cfg.Action,
+	}
+	if r.action == "" {
+		r.action = defaultAction
+	}
+	if r.action == "" {
+		r.action = overrides.SchemaValidationActionCount
+	}
+	if err := validateSchemaValidationAction(r.action); err != nil {
+		return nil, err
+	}
+
+	switch cfg.Type {
+	case overrides.SchemaValidationRuleRequiredAttribute, overrides.SchemaValidationRuleAttributeType:
+		switch cfg.Scope {
+		case "", overrides.AttributeTransformScopeResource:
+			r.resource = true
+		case overrides.AttributeTransformScopeSpan:
+		default:
+			return nil, fmt.Errorf("invalid scope %q, valid scopes: [%s %s]", cfg.Scope, overrides.AttributeTransformScopeResource, overrides.AttributeTransformScopeSpan)
+		}
+		if cfg.Key == "" {
+			return nil, fmt.Errorf("%s requires key", cfg.Type)
+		}
+		if cfg.Type == overrides.SchemaValidationRuleAttributeType && !slices.Contains(schemaValidationValueTypes, cfg.ValueType) {
+			return nil, fmt.Errorf("invalid value_type %q, valid value types: %v", cfg.ValueType, schemaValidationValueTypes)
+		}
+	case overrides.SchemaValidationRuleSpanNameCardinality:
+		if cfg.MaxSpanNames <= 0 {
+			return nil, errors.New("span_name_cardinality requires max_span_names greater than 0")
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %q", cfg.Type)
+	}
+
+	return r, nil
+}
+
+func validateSchemaValidationAction(action string) error {
+	switch action {
+	case overrides.SchemaValidationActionCount, overrides.SchemaValidationActionTag, overrides.SchemaValidationActionReject:
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q", action)
+	}
+}
+
+// ValidateSchemaValidation returns an error if the schema validation config is invalid.
+func ValidateSchemaValidation(cfg overrides.SchemaValidationOverrides) error {
+	if cfg.Action != "" {
+		if err := validateSchemaValidationAction(cfg.Action); err != nil {
+			return fmt.Errorf("schema_validation: %w", err)
+		}
+	}
+
+	names := make(map[string]struct{}, len(cfg.Rules))
+	for i, r := range cfg.Rules {
+		if r.Name == "" {
+			return fmt.Errorf("schema_validation rules[%d]: name must be set", i)
+		}
+		if _, ok := names[r.Name]; ok {
+			return fmt.Errorf("schema_validation rule %q is not unique", r.Name)
+		}
+		names[r.Name] = struct{}{}
+
+		if _, err := newSchemaValidationRule(r, cfg.Action); err != nil {
+			return fmt.Errorf("schema_validation rule %q: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// violatedBy returns true if the span or its resource violates the rule. span_name_cardinality
+// rules depend on the other spans of the service and are checked by newSpanName.
+func (r *schemaValidationRule) violatedBy(resourceAttrs []*v1_common.KeyValue, span *v1.Span) bool {
+	switch r.cfg.Type {
+	case overrides.SchemaValidationRuleRequiredAttribute:
+		value, ok := r.attribute(resourceAttrs, span)
+		return !ok || isEmptyValue(value)
+	case overrides.SchemaValidationRuleAttributeType:
+		value, ok := r.attribute(resourceAttrs, span)
+		return ok && valueType(value) != r.cfg.ValueType
+	}
+	return false
+}
+
+// newSpanName returns true if the span name violates the span_name_cardinality rule. The span names
+// of the service are remembered until it has the max number of span names.
+func (s *schemaQualityService) newSpanName(r *schemaValidationRule, name string) bool {
+	names, ok := s.spanNames[r.cfg.Name]
+	if !ok {
+		names = make(map[string]struct{})
+		s.spanNames[r.cfg.Name] = names
+	}
+	if _, ok := names[name]; ok {
+		return false
+	}
+	if len(names) >= r.cfg.MaxSpanNames {
+		return true
+	}
+	names[name] = struct{}{}
+	return false
+}
+
+func (r *schemaValidationRule) attribute(resourceAttrs []*v1_common.KeyValue, span *v1.Span) (*v1_common.AnyValue, bool) {
+	attrs := span.Attributes
+	if r.resource {
+		attrs = resourceAttrs
+	}
+	for _, a := range attrs {
+		if a.Key == r.cfg.Key {
+			return a.Value, true
+		}
+	}
+	return nil, false
+}
+
+// isEmptyValue returns true if the value isn't set or is an empty string.
+func isEmptyValue(v *v1_common.AnyValue) bool {
+	switch val := v.GetValue().(type) {
+	case nil:
+		return true
+	case *v1_common.AnyValue_StringValue:
+		return val.StringValue == ""
+	default:
+		return false
+	}
+}
+
+func valueType(v *v1_common.AnyValue) string {
+	switch v.GetValue().(type) {
+	case *v1_common.AnyValue_StringValue:
+		return "string"
+	case *v1_common.AnyValue_IntValue:
+		return "int"
+	case *v1_common.AnyValue_DoubleValue:
+		return "double"
+	case *v1_common.AnyValue_BoolValue:
+		return "bool"
+	case *v1_common.AnyValue_ArrayValue:
+		return "array"
+	case *v1_common.AnyValue_KvlistValue:
+		return "kvlist"
+	case *v1_common.AnyValue_BytesValue:
+		return "bytes"
+	default:
+		return ""
+	}
+}
+
+// tagSchemaViolations adds the names of the violated rules with action tag to the span.
+func tagSchemaViolations(span *v1.Span, violated []*schemaValidationRule) bool {
+	var names []*v1_common.AnyValue
+	for _, r := range violated {
+		if r.action == overrides.SchemaValidationActionTag {
+			names = append(names, &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: r.cfg.Name}})
+		}
+	}
+	if len(names) == 0 {
+		return false
+	}
+
+	span.Attributes = append(span.Attributes, &v1_common.KeyValue{
+		Key:   schemaViolationsAttribute,
+		Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_ArrayValue{ArrayValue: &v1_common.ArrayValue{Values: names}}},
+	})
+	return true
+}
@@ -0,0 +1,358 @@
+package distributor
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	kitlog "github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/modules/distributor/forwarder"
+	"github.com/grafana/tempo/modules/distributor/forwarder/file"
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func makeIntAttribute(key string, value int64) *v1_common.KeyValue {
+	return &v1_common.KeyValue{
+		Key:   key,
+		Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_IntValue{IntValue: value}},
+	}
+}
+
+// schemaViolations returns the span names and the violations the spans are tagged with.
+func schemaViolations(batches []*v1.ResourceSpans) map[string][]string {
+	spans := make(map[string][]string)
+	for _, rs := range batches {
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				var violations []string
+				for _, a := range span.Attributes {
+					if a.Key != schemaViolationsAttribute {
+						continue
+					}
+					for _, v := range a.GetValue().GetArrayValue().GetValues() {
+						violations = append(violations, v.GetStringValue())
+					}
+				}
+				spans[span.Name] = violations
+			}
+		}
+	}
+	return spans
+}
+
+func newSchemaValidationBatches() []*v1.ResourceSpans {
+	return []*v1.ResourceSpans{
+		makeResourceSpans("api", []*v1.ScopeSpans{makeScope(
+			makeSpan(okTraceID, "0000000000000001", "a", nil, makeIntAttribute("http.status_code", 200)),
+			makeSpan(okTraceID, "0000000000000002", "b", nil, makeAttribute("http.status_code", "200")),
+		)}, makeAttribute("deployment.environment", "prod")),
+		makeResourceSpans("worker", []*v1.ScopeSpans{makeScope(
+			makeSpan(okTraceID, "0000000000000003", "c", nil),
+		)}),
+	}
+}
+
+func TestSchemaValidator(t *testing.T) {
+	requiredEnvironment := overrides.SchemaValidationRule{Name: "environment", Type: overrides.SchemaValidationRuleRequiredAttribute, Key: "deployment.environment"}
+	statusCodeType := overrides.SchemaValidationRule{Name: "status-code", Type: overrides.SchemaValidationRuleAttributeType, Scope: overrides.AttributeTransformScopeSpan, Key: "http.status_code", ValueType: "int"}
+	spanNames := overrides.SchemaValidationRule{Name: "span-names", Type: overrides.SchemaValidationRuleSpanNameCardinality, MaxSpanNames: 1}
+
+	tcs := []struct {
+		name     string
+		cfg      overrides.SchemaValidationOverrides
+		spans    map[string][]string
+		rejected int
+		changed  bool
+		status   map[string]schemaQualityServiceStatus
+	}{
+		{
+			name:  "count",
+			cfg:   overrides.SchemaValidationOverrides{Rules: []overrides.SchemaValidationRule{requiredEnvironment, statusCodeType}},
+			spans: map[string][]string{"a": nil, "b": nil, "c": nil},
+			status: map[string]schemaQualityServiceStatus{
+				"api":    {Spans: 2, ViolatingSpans: 1, Score: 0.5, Violations: map[string]int{"status-code": 1}},
+				"worker": {Spans: 1, ViolatingSpans: 1, Score: 0, Violations: map[string]int{"environment": 1}},
+			},
+		},
+		{
+			name:    "tag",
+			cfg:     overrides.SchemaValidationOverrides{Action: overrides.SchemaValidationActionTag, Rules: []overrides.SchemaValidationRule{requiredEnvironment, statusCodeType}},
+			spans:   map[string][]string{"a": nil, "b": {"status-code"}, "c": {"environment"}},
+			changed: true,
+			status: map[string]schemaQualityServiceStatus{
+				"api":    {Spans: 2, ViolatingSpans: 1, Score: 0.5, Violations: map[string]int{"status-code": 1}},
+				"worker": {Spans: 1, ViolatingSpans: 1, Score: 0, Violations: map[string]int{"environment": 1}},
+			},
+		},
+		{
+			name:     "reject removes the resource without spans",
+			cfg:      overrides.SchemaValidationOverrides{Action: overrides.SchemaValidationActionReject, Rules: []overrides.SchemaValidationRule{requiredEnvironment}},
+			spans:    map[string][]string{"a": nil, "b": nil},
+			rejected: 1,
+			changed:  true,
+			status: map[string]schemaQualityServiceStatus{
+				"api":    {Spans: 2, Score: 1},
+				"worker": {Spans: 1, ViolatingSpans: 1, Score: 0, Violations: map[string]int{"environment": 1}},
+			},
+		},
+		{
+			name:     "span name cardinality per service",
+			cfg:      overrides.SchemaValidationOverrides{Action: overrides.SchemaValidationActionReject, Rules: []overrides.SchemaValidationRule{spanNames}},
+			spans:    map[string][]string{"a": nil, "c": nil},
+			rejected: 1,
+			changed:  true,
+			status: map[string]schemaQualityServiceStatus{
+				"api":    {Spans: 2, ViolatingSpans: 1, Score: 0.5, Violations: map[string]int{"span-names": 1}},
+				"worker": {Spans: 1, Score: 1},
+			},
+		},
+		{
+			name: "rule action overrides the default action",
+			cfg: overrides.SchemaValidationOverrides{Action: overrides.SchemaValidationActionTag, Rules: []overrides.SchemaValidationRule{
+				{Name: "environment", Type: overrides.SchemaValidationRuleRequiredAttribute, Action: overrides.SchemaValidationActionCount, Key: "deployment.environment"},
+				{Name: "version", Type: overrides.SchemaValidationRuleRequiredAttribute, Key: "service.version"},
+			}},
+			spans:   map[string][]string{"a": {"version"}, "b": {"version"}, "c": {"version"}},
+			changed: true,
+			status: map[string]schemaQualityServiceStatus{
+				"api":    {Spans: 2, ViolatingSpans: 2, Score: 0, Violations: map[string]int{"version": 2}},
+				"worker": {Spans: 1, ViolatingSpans: 1, Score: 0, Violations: map[string]int{"environment": 1, "version": 1}},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			v := newSchemaValidator(kitlog.NewNopLogger())
+
+			batches, rejected, changed := v.Validate("test", tc.cfg, newSchemaValidationBatches())
+			require.Equal(t, tc.rejected, rejected)
+			require.Equal(t, tc.changed, changed)
+			require.Equal(t, tc.spans, schemaViolations(batches))
+			require.Equal(t, map[string]map[string]schemaQualityServiceStatus{"test": tc.status}, v.status(""))
+		})
+	}
+}
+
+func TestSchemaValidator_metricsAndRotation(t *testing.T) {
+	const tenant = "test-schema-validation-metrics"
+	cfg := overrides.SchemaValidationOverrides{Rules: []overrides.SchemaValidationRule{
+		{Name: "status-code", Type: overrides.SchemaValidationRuleAttributeType, Scope: overrides.AttributeTransformScopeSpan, Key: "http.status_code", ValueType: "int"},
+	}}
+
+	v := newSchemaValidator(kitlog.NewNopLogger())
+	v.Validate(tenant, cfg, newSchemaValidationBatches())
+
+	require.Equal(t, 1.0, testutil.ToFloat64(metricSchemaViolations.WithLabelValues(tenant, "status-code", overrides.SchemaValidationActionCount)))
+	require.Equal(t, 0.5, testutil.ToFloat64(metricSchemaQualityScore.WithLabelValues(tenant, "api")))
+	require.Equal(t, 1.0, testutil.ToFloat64(metricSchemaQualityScore.WithLabelValues(tenant, "worker")))
+
+	// the score includes the spans of the previous window
+	v.rotate()
+	v.Validate(tenant, cfg, []*v1.ResourceSpans{newSchemaValidationBatches()[0]})
+	require.Equal(t, 0.5, testutil.ToFloat64(metricSchemaQualityScore.WithLabelValues(tenant, "api")))
+	require.Equal(t, 1.0, testutil.ToFloat64(metricSchemaQualityScore.WithLabelValues(tenant, "worker")))
+
+	// worker didn't receive spans in the last two windows
+	v.rotate()
+	require.Equal(t, []string{"api"}, slices.Collect(maps.Keys(v.status(tenant)[tenant])))
+	require.False(t, metricSchemaQualityScore.DeleteLabelValues(tenant, "worker"))
+
+	v.rotate()
+	require.Empty(t, v.status(tenant)[tenant])
+	require.False(t, metricSchemaQualityScore.DeleteLabelValues(tenant, "api"))
+}
+
+func TestSchemaValidator_spanNamesRotation(t *testing.T) {
+	cfg := overrides.SchemaValidationOverrides{Action: overrides.SchemaValidationActionReject, Rules: []overrides.SchemaValidationRule{
+		{Name: "span-names", Type: overrides.SchemaValidationRuleSpanNameCardinality, MaxSpanNames: 1},
+	}}
+	batch := func(name string) []*v1.ResourceSpans {
+		return []*v1.ResourceSpans{makeResourceSpans("api", []*v1.ScopeSpans{makeScope(
+			makeSpan(okTraceID, "0000000000000001", name, nil),
+		)})}
+	}
+
+	v := newSchemaValidator(kitlog.NewNopLogger())
+	_, rejected, _ := v.Validate("test", cfg, batch("a"))
+	require.Zero(t, rejected)
+	_, rejected, _ = v.Validate("test", cfg, batch("b"))
+	require.Equal(t, 1, rejected)
+
+	// the span names are reset with every window
+	v.rotate()
+	_, rejected, _ = v.Validate("test", cfg, batch("b"))
+	require.Zero(t, rejected)
+	_, rejected, _ = v.Validate("test", cfg, batch("a"))
+	require.Equal(t, 1, rejected)
+}
+
+func TestSchemaValidator_Handler(t *testing.T) {
+	v := newSchemaValidator(kitlog.NewNopLogger())
+	v.Validate("test", overrides.SchemaValidationOverrides{Rules: []overrides.SchemaValidationRule{
+		{Name: "environment", Type: overrides.SchemaValidationRuleRequiredAttribute, Key: "deployment.environment"},
+	}}, newSchemaValidationBatches())
+
+	rec := httptest.NewRecorder()
+	v.Handler(rec, httptest.NewRequest(http.MethodGet, "/distributor/schema_quality?tenant=test", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var tenants map[string]map[string]schemaQualityServiceStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tenants))
+	require.Equal(t, map[string]map[string]schemaQualityServiceStatus{"test": {
+		"api":    {Spans: 2, Score: 1},
+		"worker": {Spans: 1, ViolatingSpans: 1, Score: 0, Violations: map[string]int{"environment": 1}},
+	}}, tenants)
+
+	rec = httptest.NewRecorder()
+	v.Handler(rec, httptest.NewRequest(http.MethodGet, "/distributor/schema_quality?tenant=other", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestValidateSchemaValidation(t *testing.T) {
+	tcs := []struct {
+		name   string
+		cfg    overrides.SchemaValidationOverrides
+		expErr string
+	}{
+		{
+			name: "valid",
+			cfg: overrides.SchemaValidationOverrides{Action: overrides.SchemaValidationActionReject, Rules: []overrides.SchemaValidationRule{
+				{Name: "service-name", Type: overrides.SchemaValidationRuleRequiredAttribute, Key: "service.name"},
+				{Name: "status-code", Type: overrides.SchemaValidationRuleAttributeType, Action: overrides.SchemaValidationActionTag, Scope: overrides.AttributeTransformScopeSpan, Key: "http.status_code", ValueType: "int"},
+				{Name: "span-names", Type: overrides.SchemaValidationRuleSpanNameCardinality, MaxSpanNames: 100},
+			}},
+		},
+		{
+			name:   "unknown action",
+			cfg:    overrides.SchemaValidationOverrides{Action: "drop"},
+			expErr: `schema_validation: unknown action "drop"`,
+		},
+		{
+			name:   "missing name",
+			cfg:    overrides.SchemaValidationOverrides{Rules: []overrides.SchemaValidationRule{{Type: overrides.SchemaValidationRuleRequiredAttribute, Key: "service.name"}}},
+			expErr: "schema_validation rules[0]: name must be set",
+		},
+		{
+			name: "duplicate name",
+			cfg: overrides.SchemaValidationOverrides{Rules: []overrides.SchemaValidationRule{
+				{Name: "rule", Type: overrides.SchemaValidationRuleRequiredAttribute, Key: "service.name"},
+				{Name: "rule", Type: overrides.SchemaValidationRuleRequiredAttribute, Key: "service.version"},
+			}},
+			expErr: `schema_validation rule "rule" is not unique`,
+		},
+		{
+			name:   "unknown type",
+			cfg:    overrides.SchemaValidationOverrides{Rules: []overrides.SchemaValidationRule{{Name: "rule", Type: "regex"}}},
+			expErr: `schema_validation rule "rule": unknown type "regex"`,
+		},
+		{
+			name:   "invalid scope",
+			cfg:    overrides.SchemaValidationOverrides{Rules: []overrides.SchemaValidationRule{{Name: "rule", Type: overrides.SchemaValidationRuleRequiredAttribute, Scope: "event", Key: "name"}}},
+			expErr: `schema_validation rule "rule": invalid scope "event", valid scopes: [resource span]`,
+		},
+		{
+			name:   "missing key",
+			cfg:    overrides.SchemaValidationOverrides{Rules: []overrides.SchemaValidationRule{{Name: "rule", Type: overrides.SchemaValidationRuleAttributeType, ValueType: "int"}}},
+			expErr: `schema_validation rule "rule": attribute_type requires key`,
+		},
+		{
+			name:   "missing max span names",
+			cfg:    overrides.SchemaValidationOverrides{Rules: []overrides.SchemaValidationRule{{Name: "rule", Type: overrides.SchemaValidationRuleSpanNameCardinality}}},
+			expErr: `schema_validation rule "rule": span_name_cardinality requires max_span_names greater than 0`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateSchemaValidation(tc.cfg)
+			if tc.expErr != "" {
+				require.EqualError(t, err, tc.expErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPushTracesSchemaValidation(t *testing.T) {
+	limits := overrides.Config{}
+	limits.RegisterFlagsAndApplyDefaults(&flag.FlagSet{})
+	limits.Defaults.Ingestion.SchemaValidation = overrides.SchemaValidationOverrides{
+		Action: overrides.SchemaValidationActionReject,
+		Rules: []overrides.SchemaValidationRule{
+			{Name: "environment", Type: overrides.SchemaValidationRuleRequiredAttribute, Key: "deployment.environment"},
+		},
+	}
+
+	limits.Defaults.Forwarders = []string{"archive"}
+
+	distributorCfg, overridesSvc, loggingLevel, middleware := setupDependencies(t, limits)
+	archiveDir := t.TempDir()
+	distributorCfg.Forwarders = forwarder.ConfigList{
+		{Name: "archive", Backend: forwarder.FileBackend, File: file.Config{Directory: archiveDir}},
+	}
+
+	var pushed []*tempopb.PushBytesRequest
+	d, err := New(
+		distributorCfg,
+		LocalPushTargets{
+			LiveStore: func(_ context.Context, req *tempopb.PushBytesRequest) (*tempopb.PushResponse, error) {
+				pushed = append(pushed, req)
+				return &tempopb.PushResponse{}, nil
+			},
+		},
+		nil,
+		overridesSvc,
+		middleware,
+		kitlog.NewNopLogger(),
+		loggingLevel,
+		prometheus.NewRegistry(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), d.forwardersManager))
+
+	// the spans that are not rejected are written and the request succeeds
+	_, err = d.PushTraces(ctx, batchesToTraces(t, newSchemaValidationBatches()))
+	require.NoError(t, err)
+	require.Len(t, pushed, 1)
+
+	trace := &tempopb.Trace{}
+	require.NoError(t, trace.Unmarshal(pushed[0].Traces[0].Slice))
+	require.Equal(t, map[string][]string{"a": nil, "b": nil}, schemaViolations(trace.ResourceSpans))
+
+	// the request fails and nothing is pushed or forwarded if all spans are rejected
+	_, err = d.PushTraces(ctx, batchesToTraces(t, newSchemaValidationBatches()[1:]))
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.ErrorContains(t, err, "1 of 1 spans")
+	require.Len(t, pushed, 1)
+
+	// forwarders only receive the spans that are not rejected
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), d.forwardersManager))
+	files, err := filepath.Glob(filepath.Join(archiveDir, "test", "*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	archived, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(archived), "\n"))
+	require.Contains(t, string(archived), `"name":"a"`)
+	require.NotContains(t, string(archived), `"name":"c"`)
+}
@@ -0,0 +1,581 @@
+package distributor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/tempo/modules/generator"
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	tailSamplingShards = 16
+	// tailSamplingInterval is how often buffered traces are checked for their decision wait.
+	tailSamplingInterval = time.Second
+
+	defaultTailSamplingDecisionWait     = 10 * time.Second
+	defaultTailSamplingMaxTraces        = 10_000
+	defaultTailSamplingMaxSpansPerTrace = 10_000
+
+	// maxTailSamplingServices is the number of root services a rate_limiting policy keeps a rate
+	// limiter for. The other services share a single rate limiter.
+	maxTailSamplingServices = 1000
+
+	tailSamplingKeep = "keep"
+	tailSamplingDrop = "drop"
+)
+
+var (
+	metricTailSamplingPolicyDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_tail_sampling_policy_decisions_total",
+		Help:      "The total number of traces kept or dropped by a tail sampling policy.",
+	}, []string{"tenant", "policy", "decision"})
+	metricTailSamplingTraces = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_tail_sampling_traces_total",
+		Help:      "The total number of traces kept or dropped by tail sampling.",
+	}, []string{"tenant", "decision"})
+	metricTailSamplingSpans = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_tail_sampling_spans_total",
+		Help:      "The total number of spans kept or dropped by tail sampling, including spans received after the decision.",
+	}, []string{"tenant", "decision"})
+	metricTailSamplingPushFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_tail_sampling_push_failures_total",
+		Help:      "The total number of failed pushes of traces kept by tail sampling.",
+	}, []string{"tenant"})
+	metricTailSamplingOversizedTraces = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_tail_sampling_oversized_traces_total",
+		Help:      "The total number of traces kept without evaluating the tail sampling policies because they exceeded max_spans_per_trace.",
+	}, []string{"tenant"})
+)
+
+// tailSampler buffers the spans of a trace for the decision wait of the tenant, then keeps or drops
+// the trace based on the tail sampling policies of the tenant. Kept traces are pushed with pushFunc.
+//
+// Decisions are made on the spans received by this distributor. Only probabilistic policies are
+// guaranteed to make the same decision for the spans of a trace received by other distributors.
+type tailSampler struct {
+	services.Service
+
+	logger   log.Logger
+	o        overrides.Interface
+	pushFunc forwardFunc
+
+	// traces are sharded by the hash of their ID
+	shards [tailSamplingShards]*tailSamplingShard
+
+	tenants    map[string]*tailSamplingTenant
+	tenantsMtx sync.Mutex
+
+	now func() time.Time
+}
+
+type tailSamplingKey struct {
+	tenantID string
+	traceID  string
+}
+
+type tailSamplingShard struct {
+	mtx     sync.Mutex
+	pending map[tailSamplingKey]*pendingTrace
+	// decided holds the decisions of recent traces, so spans received after the decision follow it
+	decided map[tailSamplingKey]tailSamplingDecision
+}
+
+type pendingTrace struct {
+	key               uint32
+	trace             *rebatchedTrace
+	noGenerateMetrics bool
+	wait              time.Duration
+	deadline          time.Time
+}
+
+type tailSamplingDecision struct {
+	keep       bool
+	expiration time.Time
+}
+
+func newTailSampler(logger log.Logger, fn forwardFunc, o overrides.Interface) *tailSampler {
+	s := &tailSampler{
+		logger:   logger,
+		o:        o,
+		pushFunc: fn,
+		tenants:  make(map[string]*tailSamplingTenant),
+		now:      time.Now,
+	}
+	for i := range s.shards {
+		s.shards[i] = &tailSamplingShard{
+			pending: make(map[tailSamplingKey]*pendingTrace),
+			decided: make(map[tailSamplingKey]tailSamplingDecision),
+		}
+	}
+
+	s.Service = services.NewTimerService(tailSamplingInterval, nil, s.iteration, s.stopping)
+	return s
+}
+
+// Sample buffers the traces of a tenant until their tail sampling decision. It returns the traces
+// that must be pushed right away: spans of traces that have already been kept, traces kept because
+// the buffer of the tenant is full and traces kept because they exceed the max spans per trace.
+func (s *tailSampler) Sample(ctx context.Context, tenantID string, cfg overrides.TailSamplingOverrides, keys []uint32, traces []*rebatchedTrace) ([]uint32, []*rebatchedTrace) {
+	var (
+		tenant            = s.tenant(tenantID)
+		noGenerateMetrics = generator.ExtractNoGenerateMetrics(ctx)
+		now               = s.now()
+		wait              = cfg.DecisionWait
+		maxTraces         = cfg.MaxTraces
+		maxSpans          = cfg.MaxSpansPerTrace
+
+		pushKeys   []uint32
+		pushTraces []*rebatchedTrace
+	)
+	if wait <= 0 {
+		wait = defaultTailSamplingDecisionWait
+	}
+	if maxTraces <= 0 {
+		maxTraces = defaultTailSamplingMaxTraces
+	}
+	if maxSpans <= 0 {
+		maxSpans = defaultTailSamplingMaxSpansPerTrace
+	}
+
+	for i, tr := range traces {
+		k := tailSamplingKey{tenantID: tenantID, traceID: string(tr.id)}
+		shard := s.shards[util.HashForTraceID(tr.id)%tailSamplingShards]
+
+		shard.mtx.Lock()
+
+		if d, ok := shard.decided[k]; ok {
+			shard.mtx.Unlock()
+
+			metricTailSamplingSpans.WithLabelValues(tenantID, decisionLabel(d.keep)).Add(float64(tr.spanCount))
+			if d.keep {
+				pushKeys = append(pushKeys, keys[i])
+				pushTraces = append(pushTraces, tr)
+			}
+			continue
+		}
+
+		if p, ok := shard.pending[k]; ok {
+			if p.trace.spanCount+tr.spanCount <= maxSpans {
+				p.add(tr)
+				shard.mtx.Unlock()
+				continue
+			}
+
+			// the trace is too large to buffer, it's kept together with the buffered spans
+			delete(shard.pending, k)
+			tenant.buffered.Add(-1)
+			shard.decided[k] = tailSamplingDecision{keep: true, expiration: now.Add(wait)}
+			shard.mtx.Unlock()
+
+			p.add(tr)
+			tenant.keepOversized(p.trace)
+			pushKeys = append(pushKeys, keys[i])
+			pushTraces = append(pushTraces, p.trace)
+			continue
+		}
+
+		if tr.spanCount > maxSpans {
+			shard.decided[k] = tailSamplingDecision{keep: true, expiration: now.Add(wait)}
+			shard.mtx.Unlock()
+
+			tenant.keepOversized(tr)
+			pushKeys = append(pushKeys, keys[i])
+			pushTraces = append(pushTraces, tr)
+			continue
+		}
+
+		if tenant.buffered.Load() >= int64(maxTraces) {
+			keep := tenant.decide(cfg.Policies, tr, now)
+			shard.decided[k] = tailSamplingDecision{keep: keep, expiration: now.Add(wait)}
+			shard.mtx.Unlock()
+
+			if keep {
+				pushKeys = append(pushKeys, keys[i])
+				pushTraces = append(pushTraces, tr)
+			}
+			continue
+		}
+
+		shard.pending[k] = &pendingTrace{
+			key:               keys[i],
+			trace:             tr,
+			noGenerateMetrics: noGenerateMetrics,
+			wait:              wait,
+			deadline:          now.Add(wait),
+		}
+		tenant.buffered.Add(1)
+		shard.mtx.Unlock()
+	}
+
+	return pushKeys, pushTraces
+}
+
+func (s *tailSampler) iteration(_ context.Context) error {
+	s.decide(s.now(), false)
+	return nil
+}
+
+// stopping decides all buffered traces, so kept traces are pushed before shutting down.
+func (s *tailSampler) stopping(_ error) error {
+	s.decide(s.now(), true)
+	return nil
+}
+
+// decide makes the decision for the traces that have waited for their decision wait, or for all
+// traces if all is set, and pushes the kept traces.
+func (s *tailSampler) decide(now time.Time, all bool) {
+	type group struct {
+		tenantID          string
+		noGenerateMetrics bool
+	}
+	type batch struct {
+		keys   []uint32
+		traces []*rebatchedTrace
+	}
+
+	var (
+		kept     = map[group]*batch{}
+		policies = map[string][]overrides.TailSamplingPolicy{}
+	)
+
+	for _, shard := range s.shards {
+		shard.mtx.Lock()
+
+		for k, p := range shard.pending {
+			if !all && now.Before(p.deadline) {
+				continue
+			}
+			delete(shard.pending, k)
+
+			tenantPolicies, ok := policies[k.tenantID]
+			if !ok {
+				tenantPolicies = s.o.IngestionTailSampling(k.tenantID).Policies
+				policies[k.tenantID] = tenantPolicies
+			}
+
+			tenant := s.tenant(k.tenantID)
+			tenant.buffered.Add(-1)
+
+			keep := tenant.decide(tenantPolicies, p.trace, now)
+			shard.decided[k] = tailSamplingDecision{keep: keep, expiration: now.Add(p.wait)}
+			if !keep {
+				continue
+			}
+
+			g := group{tenantID: k.tenantID, noGenerateMetrics: p.noGenerateMetrics}
+			b, ok := kept[g]
+			if !ok {
+				b = &batch{}
+				kept[g] = b
+			}
+			b.keys = append(b.keys, p.key)
+			b.traces = append(b.traces, p.trace)
+		}
+
+		for k, d := range shard.decided {
+			if !now.Before(d.expiration) {
+				delete(shard.decided, k)
+			}
+		}
+
+		shard.mtx.Unlock()
+	}
+
+	for g, b := range kept {
+		if err := s.pushFunc(context.Background(), g.tenantID, b.keys, b.traces, g.noGenerateMetrics); err != nil {
+			_ = level.Error(s.logger).Log("msg", "failed to push traces kept by tail sampling", "tenant", g.tenantID, "err", err)
+			metricTailSamplingPushFailures.WithLabelValues(g.tenantID).Inc()
+		}
+	}
+}
+
+func (s *tailSampler) tenant(tenantID string) *tailSamplingTenant {
+	s.tenantsMtx.Lock()
+	defer s.tenantsMtx.Unlock()
+
+	t, ok := s.tenants[tenantID]
+	if !ok {
+		t = &tailSamplingTenant{tenantID: tenantID, logger: s.logger}
+		s.tenants[tenantID] = t
+	}
+	return t
+}
+
+// add merges the spans of tr into the pending trace.
+func (p *pendingTrace) add(tr *rebatchedTrace) {
+	p.trace.trace.ResourceSpans = append(p.trace.trace.ResourceSpans, tr.trace.ResourceSpans...)
+	p.trace.spanCount += tr.spanCount
+	p.trace.start = min(p.trace.start, tr.start)
+	p.trace.end = max(p.trace.end, tr.end)
+}
+
+type tailSamplingTenant struct {
+	tenantID string
+	logger   log.Logger
+
+	// buffered is the number of pending traces of the tenant
+	buffered atomic.Int64
+
+	mtx sync.Mutex
+	// cfg are the policies the compiled policies were created from
+	cfg      []overrides.TailSamplingPolicy
+	policies []*tailSamplingPolicy
+}
+
+// decide evaluates the policies in order. The trace is kept by the first policy that keeps it, the
+// following policies are not evaluated. The trace is kept if no policy is set.
+func (t *tailSamplingTenant) decide(policies []overrides.TailSamplingPolicy, tr *rebatchedTrace, now time.Time) bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if !slices.Equal(t.cfg, policies) {
+		t.compile(policies)
+	}
+
+	keep := len(t.policies) == 0
+	for _, p := range t.policies {
+		if p.sample(tr, now) {
+			metricTailSamplingPolicyDecisions.WithLabelValues(t.tenantID, p.name, tailSamplingKeep).Inc()
+			keep = true
+			break
+		}
+		metricTailSamplingPolicyDecisions.WithLabelValues(t.tenantID, p.name, tailSamplingDrop).Inc()
+	}
+
+	decision := decisionLabel(keep)
+	metricTailSamplingTraces.WithLabelValues(t.tenantID, decision).Inc()
+	metricTailSamplingSpans.WithLabelValues(t.tenantID, decision).Add(float64(tr.spanCount))
+	return keep
+}
+
+// keepOversized counts a trace kept without evaluating the policies because it exceeds the max
+// spans per trace.
+func (t *tailSamplingTenant) keepOversized(tr *rebatchedTrace) {
+	metricTailSamplingOversizedTraces.WithLabelValues(t.tenantID).Inc()
+	metricTailSamplingTraces.WithLabelValues(t.tenantID, tailSamplingKeep).Inc()
+	metricTailSamplingSpans.WithLabelValues(t.tenantID, tailSamplingKeep).Add(float64(tr.spanCount))
+}
+
+// compile replaces the compiled policies. Invalid policies are skipped. Must be called holding the
+// lock.
+func (t *tailSamplingTenant) compile(policies []overrides.TailSamplingPolicy) {
+	t.cfg = slices.Clone(policies)
+	t.policies = t.policies[:0]
+
+	for _, cfg := range policies {
+		p, err := newTailSamplingPolicy(cfg)
+		if err != nil {
+			_ = level.Warn(t.logger).Log("msg", "skipping invalid tail sampling policy", "tenant", t.tenantID, "policy", cfg.Name, "err", err)
+			continue
+		}
+		t.policies = append(t.policies, p)
+	}
+}
+
+func decisionLabel(keep bool) string {
+	if keep {
+		return tailSamplingKeep
+	}
+	return tailSamplingDrop
+}
+
+type tailSamplingPolicy struct {
+	name   string
+	sample func(tr *rebatchedTrace, now time.Time) bool
+}
+
+func newTailSamplingPolicy(cfg overrides.TailSamplingPolicy) (*tailSamplingPolicy, error) {
+	p := &tailSamplingPolicy{name: cfg.Name}
+
+	switch cfg.Type {
+	case overrides.TailSamplingPolicyStatusCode:
+		p.sample = func(tr *rebatchedTrace, _ time.Time) bool {
+			return hasErrorSpan(tr.trace)
+		}
+	case overrides.TailSamplingPolicyLatency:
+		if cfg.Threshold <= 0 {
+			return nil, errors.New("threshold must be greater than 0")
+		}
+		threshold := uint64(cfg.Threshold.Nanoseconds())
+		p.sample = func(tr *rebatchedTrace, _ time.Time) bool {
+			return traceDurationNanos(tr.trace) >= threshold
+		}
+	case overrides.TailSamplingPolicyTraceQL:
+		condition, err := traceql.CompileSpansetCondition(cfg.Query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query: %w", err)
+		}
+		p.sample = func(tr *rebatchedTrace, _ time.Time) bool {
+			matches, err := condition.Matches(tr.trace.ResourceSpans)
+			return err == nil && matches
+		}
+	case overrides.TailSamplingPolicyProbabilistic:
+		if cfg.SamplingPercentage <= 0 || cfg.SamplingPercentage > 100 {
+			return nil, errors.New("sampling_percentage must be greater than 0 and at most 100")
+		}
+		// the decision only depends on the trace ID, so all distributors make the same decision
+		threshold := uint64(cfg.SamplingPercentage / 100 * (1 << 32))
+		p.sample = func(tr *rebatchedTrace, _ time.Time) bool {
+			return uint64(util.TokenForTraceID(tr.id)) < threshold
+		}
+	case overrides.TailSamplingPolicyRateLimiting:
+		if cfg.TracesPerSecond <= 0 {
+			return nil, errors.New("traces_per_second must be greater than 0")
+		}
+		limiter := newServiceRateLimiter(cfg.TracesPerSecond)
+		p.sample = func(tr *rebatchedTrace, now time.Time) bool {
+			return limiter.allow(rootServiceName(tr.trace), now)
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %q", cfg.Type)
+	}
+
+	return p, nil
+}
+
+// ValidateTailSampling returns an error if the tail sampling config is invalid.
+func ValidateTailSampling(cfg overrides.TailSamplingOverrides) error {
+	if cfg.DecisionWait < 0 {
+		return errors.New("tail_sampling decision_wait must not be negative")
+	}
+	if cfg.MaxTraces < 0 {
+		return errors.New("tail_sampling max_traces must not be negative")
+	}
+	if cfg.MaxSpansPerTrace < 0 {
+		return errors.New("tail_sampling max_spans_per_trace must not be negative")
+	}
+
+	names := make(map[string]struct{}, len(cfg.Policies))
+	for i, p := range cfg.Policies {
+		if p.Name == "" {
+			return fmt.Errorf("tail_sampling policies[%d]: name must be set", i)
+		}
+		if _, ok := names[p.Name]; ok {
+			return fmt.Errorf("tail_sampling policy %q is not unique", p.Name)
+		}
+		names[p.Name] = struct{}{}
+
+		if _, err := newTailSamplingPolicy(p); err != nil {
+			return fmt.Errorf("tail_sampling policy %q: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// serviceRateLimiter limits the traces per second of every root service.
+type serviceRateLimiter struct {
+	mtx      sync.Mutex
+	limit    rate.Limit
+	burst    int
+	limiters map[string]*rate.Limiter
+	// overflow is shared by the services that don't fit in limiters
+	overflow *rate.Limiter
+}
+
+func newServiceRateLimiter(tracesPerSecond float64) *serviceRateLimiter {
+	burst := max(1, int(math.Ceil(tracesPerSecond)))
+	return &serviceRateLimiter{
+		limit:    rate.Limit(tracesPerSecond),
+		burst:    burst,
+		limiters: make(map[string]*rate.Limiter),
+		overflow: rate.NewLimiter(rate.Limit(tracesPerSecond), burst),
+	}
+}
+
+func (l *serviceRateLimiter) allow(service string, now time.Time) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	limiter, ok := l.limiters[service]
+	if !ok {
+		if len(l.limiters) >= maxTailSamplingServices {
+			return l.overflow.AllowN(now, 1)
+		}
+		limiter = rate.NewLimiter(l.limit, l.burst)
+		l.limiters[service] = limiter
+	}
+	return limiter.AllowN(now, 1)
+}
+
+func hasErrorSpan(trace *tempopb.Trace) bool {
+	for _, rs := range trace.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				if s.GetStatus().GetCode() == v1.Status_STATUS_CODE_ERROR {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// traceDurationNanos returns the time between the start of the first span and the end of the last
+// span of the trace.
+func traceDurationNanos(trace *tempopb.Trace) uint64 {
+	var start, end uint64
+	for _, rs := range trace.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				if start == 0 || s.StartTimeUnixNano < start {
+					start = s.StartTimeUnixNano
+				}
+				end = max(end, s.EndTimeUnixNano)
+			}
+		}
+	}
+	if end < start {
+		return 0
+	}
+	return end - start
+}
+
+// rootServiceName returns the service of the root span of the trace, or the first service of the
+// trace if the root span hasn't been received.
+func rootServiceName(trace *tempopb.Trace) string {
+	first := ""
+	for i, rs := range trace.ResourceSpans {
+		service := ""
+		for _, a := range rs.GetResource().GetAttributes() {
+			if a.GetKey() == "service.name" {
+				service = a.Value.GetStringValue()
+				break
+			}
+		}
+		if i == 0 {
+			first = service
+		}
+
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				if len(s.ParentSpanId) == 0 {
+					return service
+				}
+			}
+		}
+	}
+	return first
+}
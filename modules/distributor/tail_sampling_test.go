@@ -0,0 +1,408 @@
+package distributor
+
+import (
+	"context"
+	"flag"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+const (
+	okTraceID    = "0123456789abcdef0123456789abcdef"
+	errorTraceID = "fedcba9876543210fedcba9876543210"
+)
+
+func newTestTailSampler(t *testing.T, cfg overrides.TailSamplingOverrides, fn forwardFunc) (*tailSampler, *time.Time) {
+	t.Helper()
+
+	limits := overrides.Config{}
+	limits.RegisterFlagsAndApplyDefaults(&flag.FlagSet{})
+	limits.Defaults.Ingestion.TailSampling = cfg
+
+	o, err := overrides.NewOverrides(limits, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	now := time.Unix(1000, 0)
+	s := newTailSampler(kitlog.NewNopLogger(), fn, o)
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func tailSamplingTraces(t *testing.T, batches ...*v1.ResourceSpans) ([]uint32, []*rebatchedTrace) {
+	t.Helper()
+
+	spanCount := 0
+	for _, b := range batches {
+		for _, ss := range b.ScopeSpans {
+			spanCount += len(ss.Spans)
+		}
+	}
+
+	keys, traces, _, _, err := requestsByTraceID(batches, "test", spanCount, 1000)
+	require.NoError(t, err)
+	return keys, traces
+}
+
+func TestTailSampler(t *testing.T) {
+	cfg := overrides.TailSamplingOverrides{
+		DecisionWait: 10 * time.Second,
+		Policies: []overrides.TailSamplingPolicy{
+			{Name: "errors", Type: overrides.TailSamplingPolicyStatusCode},
+		},
+	}
+
+	var pushed []*rebatchedTrace
+	s, now := newTestTailSampler(t, cfg, func(_ context.Context, tenantID string, keys []uint32, traces []*rebatchedTrace, _ bool) error {
+		require.Equal(t, "test", tenantID)
+		require.Len(t, keys, len(traces))
+		pushed = append(pushed, traces...)
+		return nil
+	})
+
+	keys, traces := tailSamplingTraces(t,
+		makeResourceSpans("api", []*v1.ScopeSpans{makeScope(
+			makeSpan(okTraceID, "0000000000000001", "ok", nil),
+			makeSpan(errorTraceID, "0000000000000001", "root", nil),
+		)}),
+	)
+	pushKeys, pushTraces := s.Sample(context.Background(), "test", cfg, keys, traces)
+	require.Empty(t, pushKeys)
+	require.Empty(t, pushTraces)
+
+	// the error span is received in a later request before the decision
+	keys, traces = tailSamplingTraces(t,
+		makeResourceSpans("db", []*v1.ScopeSpans{makeScope(
+			makeSpan(errorTraceID, "0000000000000002", "query", &v1.Status{Code: v1.Status_STATUS_CODE_ERROR}),
+		)}),
+	)
+	_, pushTraces = s.Sample(context.Background(), "test", cfg, keys, traces)
+	require.Empty(t, pushTraces)
+
+	s.decide(now.Add(5*time.Second), false)
+	require.Empty(t, pushed)
+
+	*now = now.Add(10 * time.Second)
+	s.decide(*now, false)
+	require.Len(t, pushed, 1)
+	require.Equal(t, errorTraceID, hexID(pushed[0].id))
+	require.Equal(t, 2, pushed[0].spanCount)
+	require.Len(t, pushed[0].trace.ResourceSpans, 2)
+
+	// spans received after the decision follow it
+	keys, traces = tailSamplingTraces(t,
+		makeResourceSpans("api", []*v1.ScopeSpans{makeScope(
+			makeSpan(okTraceID, "0000000000000002", "late", nil),
+			makeSpan(errorTraceID, "0000000000000003", "late", nil),
+		)}),
+	)
+	pushKeys, pushTraces = s.Sample(context.Background(), "test", cfg, keys, traces)
+	require.Len(t, pushKeys, 1)
+	require.Len(t, pushTraces, 1)
+	require.Equal(t, errorTraceID, hexID(pushTraces[0].id))
+
+	// decisions are forgotten after the decision wait
+	s.decide(now.Add(10*time.Second), false)
+	for _, shard := range s.shards {
+		require.Empty(t, shard.pending)
+		require.Empty(t, shard.decided)
+	}
+	require.Zero(t, s.tenant("test").buffered.Load())
+}
+
+func TestTailSampler_maxTraces(t *testing.T) {
+	cfg := overrides.TailSamplingOverrides{
+		MaxTraces: 1,
+		Policies: []overrides.TailSamplingPolicy{
+			{Name: "errors", Type: overrides.TailSamplingPolicyStatusCode},
+		},
+	}
+
+	var pushed []*rebatchedTrace
+	s, _ := newTestTailSampler(t, cfg, func(_ context.Context, _ string, _ []uint32, traces []*rebatchedTrace, _ bool) error {
+		pushed = append(pushed, traces...)
+		return nil
+	})
+
+	keys, traces := tailSamplingTraces(t,
+		makeResourceSpans("api", []*v1.ScopeSpans{makeScope(
+			makeSpan(okTraceID, "0000000000000001", "ok", nil),
+		)}),
+	)
+	_, pushTraces := s.Sample(context.Background(), "test", cfg, keys, traces)
+	require.Empty(t, pushTraces)
+
+	// the buffer is full, the decision is made right away
+	keys, traces = tailSamplingTraces(t,
+		makeResourceSpans("api", []*v1.ScopeSpans{makeScope(
+			makeSpan(errorTraceID, "0000000000000001", "error", &v1.Status{Code: v1.Status_STATUS_CODE_ERROR}),
+		)}),
+	)
+	_, pushTraces = s.Sample(context.Background(), "test", cfg, keys, traces)
+	require.Len(t, pushTraces, 1)
+	require.Equal(t, errorTraceID, hexID(pushTraces[0].id))
+
+	// stopping decides the buffered traces
+	require.NoError(t, s.stopping(nil))
+	require.Empty(t, pushed)
+	require.Zero(t, s.tenant("test").buffered.Load())
+}
+
+func TestTailSampler_maxSpansPerTrace(t *testing.T) {
+	cfg := overrides.TailSamplingOverrides{
+		MaxSpansPerTrace: 2,
+		Policies: []overrides.TailSamplingPolicy{
+			{Name: "errors", Type: overrides.TailSamplingPolicyStatusCode},
+		},
+	}
+
+	s, _ := newTestTailSampler(t, cfg, func(context.Context, string, []uint32, []*rebatchedTrace, bool) error {
+		return nil
+	})
+
+	keys, traces := tailSamplingTraces(t,
+		makeResourceSpans("api", []*v1.ScopeSpans{makeScope(
+			makeSpan(okTraceID, "0000000000000001", "root", nil),
+			makeSpan(okTraceID, "0000000000000002", "child", nil),
+		)}),
+	)
+	_, pushTraces := s.Sample(context.Background(), "test", cfg, keys, traces)
+	require.Empty(t, pushTraces)
+
+	// the trace exceeds the max spans, it's kept with the buffered spans
+	keys, traces = tailSamplingTraces(t,
+		makeResourceSpans("db", []*v1.ScopeSpans{makeScope(
+			makeSpan(okTraceID, "0000000000000003", "query", nil),
+		)}),
+	)
+	_, pushTraces = s.Sample(context.Background(), "test", cfg, keys, traces)
+	require.Len(t, pushTraces, 1)
+	require.Equal(t, 3, pushTraces[0].spanCount)
+	require.Zero(t, s.tenant("test").buffered.Load())
+
+	// a trace that exceeds the max spans in a single request isn't buffered
+	keys, traces = tailSamplingTraces(t,
+		makeResourceSpans("api", []*v1.ScopeSpans{makeScope(
+			makeSpan(errorTraceID, "0000000000000001", "root", nil),
+			makeSpan(errorTraceID, "0000000000000002", "child", nil),
+			makeSpan(errorTraceID, "0000000000000003", "child", nil),
+		)}),
+	)
+	_, pushTraces = s.Sample(context.Background(), "test", cfg, keys, traces)
+	require.Len(t, pushTraces, 1)
+	require.Zero(t, s.tenant("test").buffered.Load())
+
+	oversized, err := test.GetCounterVecValue(metricTailSamplingOversizedTraces, "test")
+	require.NoError(t, err)
+	require.Equal(t, 2.0, oversized)
+}
+
+func TestTailSamplingPolicies(t *testing.T) {
+	trace := func(service string, parentSpanID []byte, status v1.Status_StatusCode, durationMs uint64) *rebatchedTrace {
+		span := makeSpan(okTraceID, "0000000000000001", "span", &v1.Status{Code: status}, makeAttribute("http.route", "/users"))
+		span.ParentSpanId = parentSpanID
+		span.StartTimeUnixNano = 1_000_000
+		span.EndTimeUnixNano = 1_000_000 + durationMs*1_000_000
+		_, traces := tailSamplingTraces(t, makeResourceSpans(service, []*v1.ScopeSpans{makeScope(span)}))
+		return traces[0]
+	}
+	now := time.Unix(1000, 0)
+
+	tcs := []struct {
+		name   string
+		policy overrides.TailSamplingPolicy
+		keep   []*rebatchedTrace
+		drop   []*rebatchedTrace
+	}{
+		{
+			name:   "status_code",
+			policy: overrides.TailSamplingPolicy{Type: overrides.TailSamplingPolicyStatusCode},
+			keep:   []*rebatchedTrace{trace("api", nil, v1.Status_STATUS_CODE_ERROR, 1)},
+			drop:   []*rebatchedTrace{trace("api", nil, v1.Status_STATUS_CODE_OK, 1)},
+		},
+		{
+			name:   "latency",
+			policy: overrides.TailSamplingPolicy{Type: overrides.TailSamplingPolicyLatency, Threshold: time.Second},
+			keep:   []*rebatchedTrace{trace("api", nil, v1.Status_STATUS_CODE_OK, 1000), trace("api", nil, v1.Status_STATUS_CODE_OK, 2000)},
+			drop:   []*rebatchedTrace{trace("api", nil, v1.Status_STATUS_CODE_OK, 999)},
+		},
+		{
+			name:   "traceql",
+			policy: overrides.TailSamplingPolicy{Type: overrides.TailSamplingPolicyTraceQL, Query: `{ resource.service.name = "api" && span.http.route = "/users" }`},
+			keep:   []*rebatchedTrace{trace("api", nil, v1.Status_STATUS_CODE_OK, 1)},
+			drop:   []*rebatchedTrace{trace("db", nil, v1.Status_STATUS_CODE_OK, 1)},
+		},
+		{
+			name:   "probabilistic 100%",
+			policy: overrides.TailSamplingPolicy{Type: overrides.TailSamplingPolicyProbabilistic, SamplingPercentage: 100},
+			keep:   []*rebatchedTrace{trace("api", nil, v1.Status_STATUS_CODE_OK, 1)},
+		},
+		{
+			name:   "rate_limiting",
+			policy: overrides.TailSamplingPolicy{Type: overrides.TailSamplingPolicyRateLimiting, TracesPerSecond: 1},
+			// the rate limit is per root service
+			keep: []*rebatchedTrace{trace("api", nil, v1.Status_STATUS_CODE_OK, 1), trace("db", nil, v1.Status_STATUS_CODE_OK, 1)},
+			drop: []*rebatchedTrace{trace("api", nil, v1.Status_STATUS_CODE_OK, 1), trace("db", []byte{1}, v1.Status_STATUS_CODE_OK, 1)},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := newTailSamplingPolicy(tc.policy)
+			require.NoError(t, err)
+
+			for _, tr := range tc.keep {
+				require.True(t, p.sample(tr, now))
+			}
+			for _, tr := range tc.drop {
+				require.False(t, p.sample(tr, now))
+			}
+		})
+	}
+}
+
+func TestTailSamplingPolicies_probabilistic(t *testing.T) {
+	p, err := newTailSamplingPolicy(overrides.TailSamplingPolicy{Type: overrides.TailSamplingPolicyProbabilistic, SamplingPercentage: 25})
+	require.NoError(t, err)
+
+	kept := 0
+	for i := range 10_000 {
+		id := []byte{byte(i), byte(i >> 8), 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14}
+		tr := &rebatchedTrace{id: id, trace: &tempopb.Trace{}}
+		keep := p.sample(tr, time.Time{})
+		// the decision is the same for every distributor
+		require.Equal(t, keep, p.sample(tr, time.Time{}))
+		if keep {
+			kept++
+		}
+	}
+	require.InDelta(t, 2500, kept, 250)
+}
+
+func TestValidateTailSampling(t *testing.T) {
+	tcs := []struct {
+		name string
+		cfg  overrides.TailSamplingOverrides
+		err  string
+	}{
+		{
+			name: "valid",
+			cfg: overrides.TailSamplingOverrides{
+				DecisionWait: time.Second,
+				MaxTraces:    100,
+				Policies: []overrides.TailSamplingPolicy{
+					{Name: "errors", Type: overrides.TailSamplingPolicyStatusCode},
+					{Name: "slow", Type: overrides.TailSamplingPolicyLatency, Threshold: time.Second},
+					{Name: "checkout", Type: overrides.TailSamplingPolicyTraceQL, Query: `{ resource.service.name = "checkout" }`},
+					{Name: "sample", Type: overrides.TailSamplingPolicyProbabilistic, SamplingPercentage: 10},
+					{Name: "services", Type: overrides.TailSamplingPolicyRateLimiting, TracesPerSecond: 5},
+				},
+			},
+		},
+		{
+			name: "negative decision wait",
+			cfg:  overrides.TailSamplingOverrides{DecisionWait: -time.Second},
+			err:  "tail_sampling decision_wait must not be negative",
+		},
+		{
+			name: "missing name",
+			cfg:  overrides.TailSamplingOverrides{Policies: []overrides.TailSamplingPolicy{{Type: overrides.TailSamplingPolicyStatusCode}}},
+			err:  "tail_sampling policies[0]: name must be set",
+		},
+		{
+			name: "duplicate name",
+			cfg: overrides.TailSamplingOverrides{Policies: []overrides.TailSamplingPolicy{
+				{Name: "errors", Type: overrides.TailSamplingPolicyStatusCode},
+				{Name: "errors", Type: overrides.TailSamplingPolicyStatusCode},
+			}},
+			err: `tail_sampling policy "errors" is not unique`,
+		},
+		{
+			name: "unknown type",
+			cfg:  overrides.TailSamplingOverrides{Policies: []overrides.TailSamplingPolicy{{Name: "p", Type: "foo"}}},
+			err:  `tail_sampling policy "p": unknown type "foo"`,
+		},
+		{
+			name: "invalid query",
+			cfg:  overrides.TailSamplingOverrides{Policies: []overrides.TailSamplingPolicy{{Name: "p", Type: overrides.TailSamplingPolicyTraceQL, Query: `{ } | rate()`}}},
+			err:  `tail_sampling policy "p": invalid query`,
+		},
+		{
+			name: "invalid percentage",
+			cfg:  overrides.TailSamplingOverrides{Policies: []overrides.TailSamplingPolicy{{Name: "p", Type: overrides.TailSamplingPolicyProbabilistic, SamplingPercentage: 101}}},
+			err:  "sampling_percentage must be greater than 0 and at most 100",
+		},
+		{
+			name: "missing rate",
+			cfg:  overrides.TailSamplingOverrides{Policies: []overrides.TailSamplingPolicy{{Name: "p", Type: overrides.TailSamplingPolicyRateLimiting}}},
+			err:  "traces_per_second must be greater than 0",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTailSampling(tc.cfg)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestPushTracesTailSampling(t *testing.T) {
+	limits := overrides.Config{}
+	limits.RegisterFlagsAndApplyDefaults(&flag.FlagSet{})
+	limits.Defaults.Ingestion.TailSampling.Policies = []overrides.TailSamplingPolicy{
+		{Name: "errors", Type: overrides.TailSamplingPolicyStatusCode},
+	}
+
+	distributorCfg, overridesSvc, loggingLevel, middleware := setupDependencies(t, limits)
+
+	var pushed []*tempopb.PushBytesRequest
+	d, err := New(
+		distributorCfg,
+		LocalPushTargets{
+			LiveStore: func(_ context.Context, req *tempopb.PushBytesRequest) (*tempopb.PushResponse, error) {
+				pushed = append(pushed, req)
+				return &tempopb.PushResponse{}, nil
+			},
+		},
+		nil,
+		overridesSvc,
+		middleware,
+		kitlog.NewNopLogger(),
+		loggingLevel,
+		prometheus.NewRegistry(),
+	)
+	require.NoError(t, err)
+
+	traces := batchesToTraces(t, []*v1.ResourceSpans{
+		makeResourceSpans("api", []*v1.ScopeSpans{makeScope(
+			makeSpan(okTraceID, "0000000000000001", "ok", nil),
+			makeSpan(errorTraceID, "0000000000000001", "error", &v1.Status{Code: v1.Status_STATUS_CODE_ERROR}),
+		)}),
+	})
+	_, err = d.PushTraces(ctx, traces)
+	require.NoError(t, err)
+	require.Empty(t, pushed)
+
+	d.tailSampler.decide(time.Now().Add(time.Minute), false)
+	require.Len(t, pushed, 1)
+	require.Len(t, pushed[0].Ids, 1)
+	require.Equal(t, errorTraceID, hexID(pushed[0].Ids[0]))
+}
+
+func hexID(id []byte) string {
+	return util.TraceIDToHexString(id)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"slices"
 	"sort"
+	"time"
 
 	"github.com/grafana/tempo/modules/frontend/shardtracker"
 	"github.com/grafana/tempo/pkg/api"
@@ -48,6 +49,17 @@ func NewQueryRange(req *tempopb.QueryRangeRequest, maxSeriesLimit int) (Combiner
 		return nil, err
 	}
 
+	// the sharder shifts the time range of queries with an offset, move the results back
+	expr, err := traceql.Parse(req.Query)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := traceql.QueryRangeOffset(req, expr.Offset)
+	if err != nil {
+		return nil, err
+	}
+	offsetMs := int64(offset / uint64(time.Millisecond)) //nolint:gosec
+
 	completionTracker := &shardtracker.CompletionTracker{}
 	maxSeriesReachedErrorMsg := fmt.Sprintf("Response exceeds maximum series limit of %d, a partial response is returned. Warning: the accuracy of each individual value is not guaranteed.", maxSeries)
 
@@ -58,6 +70,7 @@ func NewQueryRange(req *tempopb.QueryRangeRequest, maxSeriesLimit int) (Combiner
 		new:            func() *tempopb.QueryRangeResponse { return &tempopb.QueryRangeResponse{} },
 		current:        &tempopb.QueryRangeResponse{Metrics: &tempopb.SearchMetrics{}},
 		combine: func(partial *tempopb.QueryRangeResponse, _ *tempopb.QueryRangeResponse, resp PipelineResponse) error {
+			shiftSeries(partial.Series, offsetMs)
 			combiner.Combine(partial)
 			metricsCombiner.Combine(partial.Metrics, resp)
 
@@ -88,6 +101,7 @@ func NewQueryRange(req *tempopb.QueryRangeRequest, maxSeriesLimit int) (Combiner
 			}
 
 			sortResponse(resp)
+			combiner.SortSeries(resp.Series)
 			if combiner.MaxSeriesReached() {
 				// Truncating the final response because even if we bail as soon as len(resp.Series) >= maxSeries
 				// it's possible that the last response pushed us over the max series limit.
@@ -128,6 +142,7 @@ func NewQueryRange(req *tempopb.QueryRangeRequest, maxSeriesLimit int) (Combiner
 			lastCompletedThrough = completedThrough
 
 			sortResponse(resp)
+			combiner.SortSeries(resp.Series)
 			if combiner.MaxSeriesReached() {
 				// Truncating the final response because even if we bail as soon as len(resp.Series) >= maxSeries
 				// it's possible that the last response pushed us over the max series limit.
@@ -197,6 +212,22 @@ func trimSeriesToCompletedWindow(series []*tempopb.TimeSeries, lastCompletedThro
 	}
 }
 
+// shiftSeries moves the samples and exemplars of the series forward by offsetMs.
+func shiftSeries(series []*tempopb.TimeSeries, offsetMs int64) {
+	if offsetMs == 0 {
+		return
+	}
+
+	for _, s := range series {
+		for i := range s.Samples {
+			s.Samples[i].TimestampMs += offsetMs
+		}
+		for i := range s.Exemplars {
+			s.Exemplars[i].TimestampMs += offsetMs
+		}
+	}
+}
+
 func sortResponse(res *tempopb.QueryRangeResponse) {
 	// Sort all output, series alphabetically, samples by time
 	sort.SliceStable(res.Series, func(i, j int) bool {
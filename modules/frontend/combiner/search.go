@@ -32,9 +32,28 @@ var _ PipelineResponse = (*SearchJobResponse)(nil)
 
 var _ GRPCCombiner[*tempopb.SearchResponse] = (*genericCombiner[*tempopb.SearchResponse])(nil)
 
-// NewSearch returns a search combiner
-func NewSearch(limit int, keepMostRecent bool, marshalingFormat api.MarshallingFormat, padTraceIDs bool) Combiner {
-	metadataCombiner := traceql.NewMetadataCombiner(limit, keepMostRecent)
+// LinkResolver follows the links of the traces of a TraceQL ->link query, see traceql.ResolveLinks.
+type LinkResolver struct {
+	// MaxTraces is the maximum number of linked traces that are looked up.
+	MaxTraces int
+	// Resolve follows the links and keeps at most limit traces of the left side.
+	Resolve func(resp *tempopb.SearchResponse, limit int) error
+}
+
+// NewSearch returns a search combiner. The query is used to filter the groups of the search table, it can be nil.
+// If resolveLinks is set, the links are followed once all jobs are combined and only the final response has traces.
+// The limit is then applied once the traces are filtered by their links.
+func NewSearch(limit int, keepMostRecent bool, marshalingFormat api.MarshallingFormat, padTraceIDs bool, query *traceql.RootExpr, resolveLinks *LinkResolver) Combiner {
+	metadataLimit := limit
+	// the traces of a ->link query with links are kept until the links are followed, see quit
+	linkedTraceIDs := map[string]struct{}{}
+	if resolveLinks != nil {
+		metadataLimit = 0
+	}
+	metadataCombiner := traceql.NewMetadataCombiner(metadataLimit, keepMostRecent)
+	// the table is combined from the rows of every job, not only the traces kept within the limit
+	tableCombiner := traceql.NewSearchTableCombiner(query)
+	tableChanged := false
 	diffTraces := map[string]struct{}{}
 	completedThroughTracker := &shardtracker.CompletionTracker{}
 	metricsCombiner := NewSearchMetricsCombiner()
@@ -50,12 +69,19 @@ func NewSearch(limit int, keepMostRecent bool, marshalingFormat api.MarshallingF
 			}
 
 			for _, t := range partial.Traces {
+				if resolveLinks != nil && !addLinkedTraceIDs(linkedTraceIDs, t) {
+					continue
+				}
 				if metadataCombiner.AddMetadata(t) {
 					// record modified traces
 					diffTraces[t.TraceID] = struct{}{}
 				}
 			}
 
+			if tableCombiner.AddRows(partial.Table) {
+				tableChanged = true
+			}
+
 			metricsCombiner.Combine(partial.Metrics, resp)
 
 			return nil
@@ -80,10 +106,19 @@ func NewSearch(limit int, keepMostRecent bool, marshalingFormat api.MarshallingF
 			// metrics are already combined on the passed in final
 			final.Traces = metadataCombiner.Metadata()
 			final.Metrics = metricsCombiner.Metrics
+			if resolveLinks != nil {
+				if err := resolveLinks.Resolve(final, limit); err != nil {
+					return nil, err
+				}
+			}
 			addRootSpanNotReceivedText(final.Traces)
 			if padTraceIDs {
 				padTraceIDsInResponse(final.Traces)
 			}
+			final.Table = tableCombiner.Rows()
+			if padTraceIDs {
+				padSampleTraceIDs(final.Table)
+			}
 			return final, nil
 		},
 		diff: func(current *tempopb.SearchResponse) (*tempopb.SearchResponse, error) {
@@ -106,7 +141,13 @@ func NewSearch(limit int, keepMostRecent bool, marshalingFormat api.MarshallingF
 				}
 			}
 
-			for _, tr := range metadataFn() {
+			// the traces of a ->link query are only known once the links are followed in finalize
+			if resolveLinks != nil {
+				metadataFn = func() []*tempopb.TraceSearchMetadata { return nil }
+			}
+
+			metadata := metadataFn()
+			for _, tr := range metadata {
 				// if not in the map, skip. we haven't seen an update
 				if _, ok := diffTraces[tr.TraceID]; !ok {
 					continue
@@ -121,9 +162,27 @@ func NewSearch(limit int, keepMostRecent bool, marshalingFormat api.MarshallingF
 				padTraceIDsInResponse(diff.Traces)
 			}
 
+			// the table is sent whole, it replaces the one sent with the previous diff
+			if tableChanged {
+				tableChanged = false
+				diff.Table = tableCombiner.Rows()
+				if padTraceIDs {
+					padSampleTraceIDs(diff.Table)
+				}
+			}
+
 			return diff, nil
 		},
 		quit: func(_ *tempopb.SearchResponse) bool {
+			// the table needs the response of every job, the limit only applies to the traces
+			if tableCombiner.Enabled() {
+				return false
+			}
+			// links to more traces than the fan-out are not followed, there is no need to look for more
+			if resolveLinks != nil {
+				return len(linkedTraceIDs) >= resolveLinks.MaxTraces
+			}
+
 			completedThroughSeconds := completedThroughTracker.CompletedThroughSeconds()
 			// have we completed any shards?
 			if completedThroughSeconds == shardtracker.TimestampUnknown {
@@ -137,6 +196,16 @@ func NewSearch(limit int, keepMostRecent bool, marshalingFormat api.MarshallingF
 	return c
 }
 
+// addLinkedTraceIDs adds the trace IDs that the trace links to. It returns false if the trace has no links,
+// it can't match a ->link query.
+func addLinkedTraceIDs(linkedTraceIDs map[string]struct{}, tr *tempopb.TraceSearchMetadata) bool {
+	ids := traceql.LinkedTraceIDs(tr)
+	for _, id := range ids {
+		linkedTraceIDs[id] = struct{}{}
+	}
+	return len(ids) > 0
+}
+
 func addRootSpanNotReceivedText(results []*tempopb.TraceSearchMetadata) {
 	for _, tr := range results {
 		if tr.RootServiceName == "" {
@@ -145,8 +214,8 @@ func addRootSpanNotReceivedText(results []*tempopb.TraceSearchMetadata) {
 	}
 }
 
-func NewTypedSearch(limit int, keepMostRecent bool, marshalingFormat api.MarshallingFormat, padTraceIDs bool) GRPCCombiner[*tempopb.SearchResponse] {
-	return NewSearch(limit, keepMostRecent, marshalingFormat, padTraceIDs).(GRPCCombiner[*tempopb.SearchResponse])
+func NewTypedSearch(limit int, keepMostRecent bool, marshalingFormat api.MarshallingFormat, padTraceIDs bool, query *traceql.RootExpr, resolveLinks *LinkResolver) GRPCCombiner[*tempopb.SearchResponse] {
+	return NewSearch(limit, keepMostRecent, marshalingFormat, padTraceIDs, query, resolveLinks).(GRPCCombiner[*tempopb.SearchResponse])
 }
 
 // padTraceIDsInResponse left-pads all trace IDs in the given search metadata to 32 hex characters.
@@ -155,3 +224,12 @@ func padTraceIDsInResponse(traces []*tempopb.TraceSearchMetadata) {
 		t.TraceID = util.PadTraceIDString(t.TraceID)
 	}
 }
+
+// padSampleTraceIDs left-pads the sample trace IDs of the given search table to 32 hex characters.
+func padSampleTraceIDs(table []*tempopb.SearchTableRow) {
+	for _, row := range table {
+		for i, id := range row.SampleTraceIDs {
+			row.SampleTraceIDs[i] = util.PadTraceIDString(id)
+		}
+	}
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/search"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 )
@@ -26,33 +28,33 @@ func TestSearchProgressShouldQuitAnyProtobuf(t *testing.T) {
 
 func testSearchProgressShouldQuitAny(t *testing.T, marshalingFormat api.MarshallingFormat) {
 	// new combiner should not quit
-	c := NewSearch(0, false, marshalingFormat, false)
+	c := NewSearch(0, false, marshalingFormat, false, nil, nil)
 	should := c.ShouldQuit()
 	require.False(t, should)
 
 	// 500 response should quit
-	c = NewSearch(0, false, marshalingFormat, false)
+	c = NewSearch(0, false, marshalingFormat, false, nil, nil)
 	err := c.AddResponse(toHTTPResponseWithFormat(t, &tempopb.SearchResponse{}, 500, nil, marshalingFormat))
 	require.NoError(t, err)
 	should = c.ShouldQuit()
 	require.True(t, should)
 
 	// 429 response should quit
-	c = NewSearch(0, false, marshalingFormat, false)
+	c = NewSearch(0, false, marshalingFormat, false, nil, nil)
 	err = c.AddResponse(toHTTPResponseWithFormat(t, &tempopb.SearchResponse{}, 429, nil, marshalingFormat))
 	require.NoError(t, err)
 	should = c.ShouldQuit()
 	require.True(t, should)
 
 	// unparseable body should not quit, but should return an error
-	c = NewSearch(0, false, marshalingFormat, false)
+	c = NewSearch(0, false, marshalingFormat, false, nil, nil)
 	err = c.AddResponse(&testPipelineResponse{r: &http.Response{Body: io.NopCloser(strings.NewReader("foo")), StatusCode: 200}})
 	require.Error(t, err)
 	should = c.ShouldQuit()
 	require.False(t, should)
 
 	// under limit should not quit
-	c = NewSearch(2, false, marshalingFormat, false)
+	c = NewSearch(2, false, marshalingFormat, false, nil, nil)
 	err = c.AddResponse(toHTTPResponseWithFormat(t, &tempopb.SearchResponse{
 		Traces: []*tempopb.TraceSearchMetadata{
 			{
@@ -65,7 +67,7 @@ func testSearchProgressShouldQuitAny(t *testing.T, marshalingFormat api.Marshall
 	require.False(t, should)
 
 	// over limit should quit
-	c = NewSearch(1, false, marshalingFormat, false)
+	c = NewSearch(1, false, marshalingFormat, false, nil, nil)
 	err = c.AddResponse(toHTTPResponseWithFormat(t, &tempopb.SearchResponse{
 		Traces: []*tempopb.TraceSearchMetadata{
 			{
@@ -91,33 +93,33 @@ func TestSearchProgressShouldQuitMostRecentProtobuf(t *testing.T) {
 
 func testSearchProgressShouldQuitMostRecent(t *testing.T, marshalingFormat api.MarshallingFormat) {
 	// new combiner should not quit
-	c := NewSearch(0, true, marshalingFormat, false)
+	c := NewSearch(0, true, marshalingFormat, false, nil, nil)
 	should := c.ShouldQuit()
 	require.False(t, should)
 
 	// 500 response should quit
-	c = NewSearch(0, true, marshalingFormat, false)
+	c = NewSearch(0, true, marshalingFormat, false, nil, nil)
 	err := c.AddResponse(toHTTPResponseWithFormat(t, &tempopb.SearchResponse{}, 500, nil, marshalingFormat))
 	require.NoError(t, err)
 	should = c.ShouldQuit()
 	require.True(t, should)
 
 	// 429 response should quit
-	c = NewSearch(0, true, marshalingFormat, false)
+	c = NewSearch(0, true, marshalingFormat, false, nil, nil)
 	err = c.AddResponse(toHTTPResponseWithFormat(t, &tempopb.SearchResponse{}, 429, nil, marshalingFormat))
 	require.NoError(t, err)
 	should = c.ShouldQuit()
 	require.True(t, should)
 
 	// unparseable body should not quit, but should return an error
-	c = NewSearch(0, true, marshalingFormat, false)
+	c = NewSearch(0, true, marshalingFormat, false, nil, nil)
 	err = c.AddResponse(&testPipelineResponse{r: &http.Response{Body: io.NopCloser(strings.NewReader("foo")), StatusCode: 200}})
 	require.Error(t, err)
 	should = c.ShouldQuit()
 	require.False(t, should)
 
 	// under limit should not quit
-	c = NewSearch(2, true, marshalingFormat, false)
+	c = NewSearch(2, true, marshalingFormat, false, nil, nil)
 	err = c.AddResponse(toHTTPResponseWithFormat(t, &tempopb.SearchResponse{
 		Traces: []*tempopb.TraceSearchMetadata{
 			{
@@ -130,7 +132,7 @@ func testSearchProgressShouldQuitMostRecent(t *testing.T, marshalingFormat api.M
 	require.False(t, should)
 
 	// over limit but no search job response, should not quit
-	c = NewSearch(1, true, marshalingFormat, false)
+	c = NewSearch(1, true, marshalingFormat, false, nil, nil)
 	err = c.AddResponse(toHTTPResponseWithFormat(t, &tempopb.SearchResponse{
 		Traces: []*tempopb.TraceSearchMetadata{
 			{
@@ -198,7 +200,7 @@ func testSearchCombinesResults(t *testing.T, marshalingFormat api.MarshallingFor
 		start := time.Date(1, 2, 3, 4, 5, 6, 7, time.UTC)
 		traceID := "traceID"
 
-		c := NewSearch(10, keepMostRecent, marshalingFormat, false)
+		c := NewSearch(10, keepMostRecent, marshalingFormat, false, nil, nil)
 		sr := toHTTPResponseWithFormat(t, &tempopb.SearchResponse{
 			Traces: []*tempopb.TraceSearchMetadata{
 				{
@@ -420,7 +422,7 @@ func testSearchResponseCombiner(t *testing.T, marshalingFormat api.MarshallingFo
 
 		for _, tc := range tests {
 			t.Run(tc.name, func(t *testing.T) {
-				combiner := NewTypedSearch(20, keepMostRecent, marshalingFormat, false)
+				combiner := NewTypedSearch(20, keepMostRecent, marshalingFormat, false, nil, nil)
 
 				err := combiner.AddResponse(tc.response1)
 				require.NoError(t, err)
@@ -691,7 +693,7 @@ func testCombinerShards(t *testing.T, marshalingFormat api.MarshallingFormat) {
 
 	// apply tests one at a time to the combiner and check expected results
 
-	combiner := NewTypedSearch(5, true, marshalingFormat, false)
+	combiner := NewTypedSearch(5, true, marshalingFormat, false, nil, nil)
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			if tc.pipelineResponse != nil {
@@ -767,7 +769,7 @@ func TestSearchCombinerPadTraceIDs(t *testing.T) {
 				traces = append(traces, &tempopb.TraceSearchMetadata{TraceID: id})
 			}
 
-			c := NewTypedSearch(10, false, tc.marshalingFmt, tc.padTraceIDs)
+			c := NewTypedSearch(10, false, tc.marshalingFmt, tc.padTraceIDs, nil, nil)
 			err := c.AddResponse(toHTTPResponseWithFormat(t, &tempopb.SearchResponse{Traces: traces}, 200, nil, tc.marshalingFmt))
 			require.NoError(t, err)
 
@@ -787,3 +789,118 @@ func TestSearchCombinerPadTraceIDs(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchCombinerTable(t *testing.T) {
+	row := func(svc string, count int64, traceID string) *tempopb.SearchTableRow {
+		return &tempopb.SearchTableRow{
+			Group:          []*v1.KeyValue{{Key: "by(resource.service.name)", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: svc}}}},
+			Aggregates:     []*v1.KeyValue{{Key: "count()", Value: &v1.AnyValue{Value: &v1.AnyValue_IntValue{IntValue: count}}}},
+			TraceCount:     1,
+			SpanCount:      uint32(count), //nolint:gosec
+			SampleTraceIDs: []string{traceID},
+		}
+	}
+
+	c := NewTypedSearch(10, false, api.MarshallingFormatJSON, true, nil, nil)
+
+	err := c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{
+		Table: []*tempopb.SearchTableRow{row("a", 2, "1"), row("b", 1, "1")},
+	}, 200))
+	require.NoError(t, err)
+
+	diff, err := c.GRPCDiff()
+	require.NoError(t, err)
+	require.Len(t, diff.Table, 2)
+	require.Equal(t, uint32(2), diff.Table[0].SpanCount)
+	require.Equal(t, []string{"00000000000000000000000000000001"}, diff.Table[0].SampleTraceIDs)
+
+	// no new rows, no new table
+	diff, err = c.GRPCDiff()
+	require.NoError(t, err)
+	require.Nil(t, diff.Table)
+
+	err = c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{
+		Table: []*tempopb.SearchTableRow{row("a", 3, "2")},
+	}, 200))
+	require.NoError(t, err)
+
+	final, err := c.GRPCFinal()
+	require.NoError(t, err)
+	require.Equal(t, []*tempopb.SearchTableRow{
+		{
+			Group:          []*v1.KeyValue{{Key: "by(resource.service.name)", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "a"}}}},
+			Aggregates:     []*v1.KeyValue{{Key: "count()", Value: &v1.AnyValue{Value: &v1.AnyValue_IntValue{IntValue: 5}}}},
+			TraceCount:     2,
+			SpanCount:      5,
+			SampleTraceIDs: []string{"00000000000000000000000000000001", "00000000000000000000000000000002"},
+		},
+		{
+			Group:          []*v1.KeyValue{{Key: "by(resource.service.name)", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "b"}}}},
+			Aggregates:     []*v1.KeyValue{{Key: "count()", Value: &v1.AnyValue{Value: &v1.AnyValue_IntValue{IntValue: 1}}}},
+			TraceCount:     1,
+			SpanCount:      1,
+			SampleTraceIDs: []string{"00000000000000000000000000000001"},
+		},
+	}, final.Table)
+}
+
+func TestSearchCombinerTableBeyondLimit(t *testing.T) {
+	row := func(svc string) *tempopb.SearchTableRow {
+		return &tempopb.SearchTableRow{
+			Group:      []*v1.KeyValue{{Key: "by(resource.service.name)", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: svc}}}},
+			Aggregates: []*v1.KeyValue{{Key: "count()", Value: &v1.AnyValue{Value: &v1.AnyValue_IntValue{IntValue: 2}}}},
+			TraceCount: 1,
+			SpanCount:  2,
+		}
+	}
+	response := func(traceID string, rows ...*tempopb.SearchTableRow) *tempopb.SearchResponse {
+		return &tempopb.SearchResponse{
+			Traces: []*tempopb.TraceSearchMetadata{{TraceID: traceID}},
+			Table:  rows,
+		}
+	}
+	responses := []*tempopb.SearchResponse{
+		response("1", row("a")),
+		response("2", row("a")),
+		response("3", row("a"), row("b")),
+	}
+
+	tcs := []struct {
+		query    string
+		expected map[string]int64
+	}{
+		{
+			// every trace passes count() > 1, every group too
+			query:    "{ } | by(resource.service.name) | count() > 1",
+			expected: map[string]int64{"a": 6, "b": 2},
+		},
+		{
+			// every trace passes count() < 4 but the combined count of group a doesn't
+			query:    "{ } | by(resource.service.name) | count() < 4",
+			expected: map[string]int64{"b": 2},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			query, err := traceql.Parse(tc.query)
+			require.NoError(t, err)
+
+			// the limit is lower than the number of matching traces
+			c := NewTypedSearch(1, false, api.MarshallingFormatJSON, false, query, nil)
+			for _, resp := range responses {
+				require.NoError(t, c.AddResponse(toHTTPResponse(t, resp, 200)))
+			}
+
+			final, err := c.GRPCFinal()
+			require.NoError(t, err)
+			require.Len(t, final.Traces, 1)
+
+			actual := map[string]int64{}
+			for _, row := range final.Table {
+				actual[row.Group[0].Value.GetStringValue()] = row.Aggregates[0].Value.GetIntValue()
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/grafana/tempo/modules/frontend/combiner"
+	"github.com/grafana/tempo/modules/frontend/macros"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/api"
@@ -51,6 +52,8 @@ type QueryFrontend struct {
 	SearchTagsHandler, SearchTagsV2Handler, SearchTagsValuesHandler, SearchTagsValuesV2Handler http.Handler
 	MetricsQueryInstantHandler, MetricsQueryRangeHandler                                       http.Handler
 	MCPHandler                                                                                 http.Handler
+	MacrosHandler                                                                              http.Handler
+	SearchExplainHandler                                                                       http.Handler
 	cacheProvider                                                                              cache.Provider
 	streamingSearch                                                                            streamingSearchHandler
 	streamingTags                                                                              streamingTagsHandler
@@ -119,6 +122,16 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 		return nil, fmt.Errorf("QueryBackendAfter (%v) must be greater than query end cutoff (%v)", cfg.Search.Sharder.QueryBackendAfter, cfg.QueryEndCutoff)
 	}
 
+	// the macros referenced by search and metrics queries are expanded before the query is parsed
+	var macroStore *macros.Store
+	if cfg.TraceQLMacros.Enabled {
+		var err error
+		macroStore, err = macros.New(&cfg.TraceQLMacros)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create TraceQL macros store: %w", err)
+		}
+	}
+
 	jobsPerQuery := promauto.With(registerer).NewHistogramVec(prometheus.HistogramOpts{
 		Name:                            "tempo_query_frontend_jobs_per_query",
 		Help:                            "Number of planned jobs per query in the query frontend.",
@@ -237,19 +250,21 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 
 	traces := newTraceIDHandler(cfg, tracePipeline, o, combiner.NewTypedTraceByID, logger, dataAccessController)
 	tracesV2 := newTraceIDV2Handler(cfg, tracePipeline, o, combiner.NewTypedTraceByIDV2, logger, dataAccessController)
-	search := newSearchHTTPHandler(cfg, searchPipeline, o, logger, dataAccessController)
+	search := newMacroExpansionRoundTripper(macroStore, newSearchHTTPHandler(cfg, searchPipeline, tracePipeline, apiPrefix, o, logger, dataAccessController))
 	searchTags := newTagsHTTPHandler(cfg, searchTagsPipeline, o, logger, dataAccessController)
 	searchTagsV2 := newTagsV2HTTPHandler(cfg, searchTagsPipeline, o, logger, dataAccessController)
 	searchTagValues := newTagValuesHTTPHandler(cfg, searchTagValuesPipeline, o, logger, dataAccessController)
 	searchTagValuesV2 := newTagValuesV2HTTPHandler(cfg, searchTagValuesV2Pipeline, o, logger, dataAccessController)
-	queryInstant := newMetricsQueryInstantHTTPHandler(cfg, queryInstantPipeline, logger, dataAccessController) // Reuses the same pipeline
-	queryRange := newMetricsQueryRangeHTTPHandler(cfg, queryRangePipeline, logger, dataAccessController)
+	searchExplain := newMacroExpansionRoundTripper(macroStore, newSearchExplainHTTPHandler(cfg, reader, o, logger, dataAccessController))
+	queryInstant := newMacroExpansionRoundTripper(macroStore, newMetricsQueryInstantHTTPHandler(cfg, queryInstantPipeline, logger, dataAccessController)) // Reuses the same pipeline
+	queryRange := newMacroExpansionRoundTripper(macroStore, newMetricsQueryRangeHTTPHandler(cfg, queryRangePipeline, logger, dataAccessController))
 
 	f := &QueryFrontend{
 		// http/discrete
 		TraceByIDHandler:           newHandler(cfg.Config.LogQueryRequestHeaders, traces, logger),
 		TraceByIDHandlerV2:         newHandler(cfg.Config.LogQueryRequestHeaders, tracesV2, logger),
 		SearchHandler:              newHandler(cfg.Config.LogQueryRequestHeaders, search, logger),
+		SearchExplainHandler:       newHandler(cfg.Config.LogQueryRequestHeaders, searchExplain, logger),
 		SearchTagsHandler:          newHandler(cfg.Config.LogQueryRequestHeaders, searchTags, logger),
 		SearchTagsV2Handler:        newHandler(cfg.Config.LogQueryRequestHeaders, searchTagsV2, logger),
 		SearchTagsValuesHandler:    newHandler(cfg.Config.LogQueryRequestHeaders, searchTagValues, logger),
@@ -258,18 +273,26 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 		MetricsQueryRangeHandler:   newHandler(cfg.Config.LogQueryRequestHeaders, queryRange, logger),
 
 		// grpc/streaming
-		streamingSearch:       newSearchStreamingGRPCHandler(cfg, searchPipeline, apiPrefix, o, logger, dataAccessController),
+		streamingSearch:       newMacroExpansionStreamingSearchHandler(macroStore, newSearchStreamingGRPCHandler(cfg, searchPipeline, tracePipeline, apiPrefix, o, logger, dataAccessController)),
 		streamingTags:         newTagsStreamingGRPCHandler(cfg, searchTagsPipeline, apiPrefix, o, logger, dataAccessController),
 		streamingTagsV2:       newTagsV2StreamingGRPCHandler(cfg, searchTagsPipeline, apiPrefix, o, logger, dataAccessController),
 		streamingTagValues:    newTagValuesStreamingGRPCHandler(cfg, searchTagValuesPipeline, apiPrefix, o, logger, dataAccessController),
 		streamingTagValuesV2:  newTagValuesV2StreamingGRPCHandler(cfg, searchTagValuesV2Pipeline, apiPrefix, o, logger, dataAccessController),
-		streamingQueryRange:   newQueryRangeStreamingGRPCHandler(cfg, queryRangePipeline, apiPrefix, logger, dataAccessController),
-		streamingQueryInstant: newQueryInstantStreamingGRPCHandler(cfg, queryRangePipeline, apiPrefix, logger, dataAccessController), // Reuses the same pipeline
+		streamingQueryRange:   newMacroExpansionStreamingQueryRangeHandler(macroStore, newQueryRangeStreamingGRPCHandler(cfg, queryRangePipeline, apiPrefix, logger, dataAccessController)),
+		streamingQueryInstant: newMacroExpansionStreamingQueryInstantHandler(macroStore, newQueryInstantStreamingGRPCHandler(cfg, queryRangePipeline, apiPrefix, logger, dataAccessController)), // Reuses the same pipeline
 
 		cacheProvider: cacheProvider,
 		logger:        logger,
 	}
 
+	if macroStore != nil {
+		f.MacrosHandler = macroStore.Handler()
+	} else {
+		f.MacrosHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		})
+	}
+
 	if cfg.MCPServer.Enabled {
 		// Initialize MCP server
 		mcpServer := NewMCPServer(f, apiPrefix, logger, authMiddleware)
@@ -0,0 +1,149 @@
+package macros
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-kit/log/level"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/grafana/tempo/pkg/api"
+	tempo_log "github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/pkg/validation"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const (
+	headerEtag    = "ETag"
+	headerIfMatch = "If-Match"
+
+	errNoIfMatchHeader = "must specify If-Match header"
+
+	// maxBodyBytes caps the size of the macros document accepted by the API.
+	maxBodyBytes = 1 << 20
+)
+
+// Handler serves the TraceQL macros API. GET returns the macros of the tenant, POST replaces them
+// and DELETE removes them. POST and DELETE require the If-Match header with the version returned
+// in the ETag header, use "0" to create the macros of a new tenant.
+func (s *Store) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			s.getHandler(w, r)
+		case http.MethodPost:
+			s.postHandler(w, r)
+		case http.MethodDelete:
+			s.deleteHandler(w, r)
+		default:
+			w.Header().Set("Allow", "GET, POST, DELETE")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func (s *Store) getHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := validation.ExtractValidTenantID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	macros, version, err := s.Get(r.Context(), tenantID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeMacros(w, macros, version)
+}
+
+func (s *Store) postHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := validation.ExtractValidTenantID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ifMatchVersion := r.Header.Get(headerIfMatch)
+	if ifMatchVersion == "" {
+		http.Error(w, errNoIfMatchHeader, http.StatusPreconditionRequired)
+		return
+	}
+
+	// read one byte more than allowed to tell a body at the limit from a larger one
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(data) > maxBodyBytes {
+		http.Error(w, fmt.Sprintf("macros document exceeds the maximum size of %d bytes", maxBodyBytes), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	macros := Macros{}
+	if err := jsoniter.Unmarshal(data, &macros); err != nil {
+		http.Error(w, "invalid macros: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, err := s.Set(r.Context(), tenantID, macros, backend.Version(ifMatchVersion))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeMacros(w, macros, version)
+}
+
+func (s *Store) deleteHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := validation.ExtractValidTenantID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ifMatchVersion := r.Header.Get(headerIfMatch)
+	if ifMatchVersion == "" {
+		http.Error(w, errNoIfMatchHeader, http.StatusPreconditionRequired)
+		return
+	}
+
+	if err := s.Delete(r.Context(), tenantID, backend.Version(ifMatchVersion)); err != nil {
+		writeError(w, r, err)
+	}
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, backend.ErrVersionDoesNotMatch) || errors.Is(err, backend.ErrVersionInvalid) {
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+	var valErr *validationError
+	if errors.As(err, &valErr) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	level.Error(tempo_log.Logger).Log("msg", "TraceQL macros request failed", "method", r.Method, "url", r.URL.RequestURI(), "err", err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+func writeMacros(w http.ResponseWriter, macros Macros, version backend.Version) {
+	data, err := jsoniter.Marshal(macros)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(headerEtag, string(version))
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+	_, _ = w.Write(data)
+}
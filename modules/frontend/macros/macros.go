@@ -0,0 +1,266 @@
+package macros
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/tenant"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/tempo/modules/overrides/userconfigurable/client"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const (
+	KeyPath  = "traceql_macros"
+	FileName = "macros.json"
+)
+
+var tracer = otel.Tracer("modules/frontend/macros")
+
+var (
+	metricExpanded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_traceql_macros_expanded_total",
+		Help:      "How often a query referencing macros was expanded for this tenant",
+	}, []string{"tenant"})
+	metricFetchFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_traceql_macros_fetch_failed_total",
+		Help:      "How often fetching the TraceQL macros failed for this tenant",
+	}, []string{"tenant"})
+)
+
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+
+	// CacheTTL is how long the macros of a tenant are cached before they are read from the backend
+	// again. Changes made through another query-frontend are picked up after this period.
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+
+	// MaxMacros is the maximum number of macros a tenant can define. 0 disables the limit.
+	MaxMacros int `yaml:"max_macros"`
+
+	Client client.Config `yaml:"client"`
+}
+
+func (c *Config) RegisterFlagsAndApplyDefaults(f *flag.FlagSet) {
+	c.CacheTTL = time.Minute
+	c.MaxMacros = 100
+	c.Client.RegisterFlagsAndApplyDefaults(f)
+}
+
+// Macros maps the name of a macro to the TraceQL fragment it expands to.
+type Macros map[string]string
+
+// Validate checks that every macro can be referenced and fully expanded.
+func (m Macros) Validate(maxMacros int) error {
+	if maxMacros > 0 && len(m) > maxMacros {
+		return &validationError{fmt.Sprintf("too many macros: %d, the maximum is %d", len(m), maxMacros)}
+	}
+	for name, body := range m {
+		if !traceql.IsValidMacroName(name) {
+			return &validationError{fmt.Sprintf("invalid macro name %q: names may only contain letters, digits and underscores and must not start with a digit", name)}
+		}
+		if strings.TrimSpace(body) == "" {
+			return &validationError{fmt.Sprintf("macro $%s is empty", name)}
+		}
+		if _, err := traceql.ExpandMacros(body, m); err != nil {
+			return &validationError{fmt.Sprintf("macro $%s: %s", name, err.Error())}
+		}
+	}
+	return nil
+}
+
+type validationError struct {
+	msg string
+}
+
+func (e *validationError) Error() string {
+	return e.msg
+}
+
+// InvalidQueryError is returned by Store.Expand when the query references macros that can't be
+// expanded.
+type InvalidQueryError struct {
+	err error
+}
+
+func (e *InvalidQueryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *InvalidQueryError) Unwrap() error {
+	return e.err
+}
+
+type cacheEntry struct {
+	macros  Macros
+	expires time.Time
+}
+
+// Store keeps the TraceQL macros of every tenant in the backend and expands the macros referenced
+// by queries.
+type Store struct {
+	cfg *Config
+	rw  backend.VersionedReaderWriter
+
+	mtx   sync.Mutex
+	cache map[string]cacheEntry
+}
+
+func New(cfg *Config) (*Store, error) {
+	rw, err := client.NewVersionedReaderWriter(&cfg.Client, KeyPath)
+	if err != nil {
+		return nil, err
+	}
+	return newStore(cfg, rw), nil
+}
+
+func newStore(cfg *Config, rw backend.VersionedReaderWriter) *Store {
+	return &Store{
+		cfg:   cfg,
+		rw:    rw,
+		cache: map[string]cacheEntry{},
+	}
+}
+
+func (s *Store) Shutdown() {
+	s.rw.Shutdown()
+}
+
+// Get the macros of the tenant. Returns backend.ErrDoesNotExist if no macros are set.
+func (s *Store) Get(ctx context.Context, tenantID string) (Macros, backend.Version, error) {
+	ctx, span := tracer.Start(ctx, "Store.Get", trace.WithAttributes(attribute.String("tenant", tenantID)))
+	defer span.End()
+
+	reader, version, err := s.rw.ReadVersioned(ctx, FileName, []string{KeyPath, tenantID})
+	if err != nil {
+		return nil, "", err
+	}
+	defer reader.Close()
+
+	macros := Macros{}
+	if err := jsoniter.NewDecoder(reader).Decode(&macros); err != nil {
+		return nil, "", err
+	}
+	return macros, version, nil
+}
+
+// Set replaces the macros of the tenant. Returns backend.ErrVersionDoesNotMatch if the backend
+// has a newer version.
+func (s *Store) Set(ctx context.Context, tenantID string, macros Macros, version backend.Version) (backend.Version, error) {
+	ctx, span := tracer.Start(ctx, "Store.Set", trace.WithAttributes(attribute.String("tenant", tenantID)))
+	defer span.End()
+
+	if err := macros.Validate(s.cfg.MaxMacros); err != nil {
+		return "", err
+	}
+
+	data, err := jsoniter.Marshal(macros)
+	if err != nil {
+		return "", err
+	}
+
+	newVersion, err := s.rw.WriteVersioned(ctx, FileName, []string{KeyPath, tenantID}, bytes.NewReader(data), int64(len(data)), version)
+	if err != nil {
+		return "", err
+	}
+
+	s.setCache(tenantID, macros)
+	return newVersion, nil
+}
+
+// Delete all macros of the tenant.
+func (s *Store) Delete(ctx context.Context, tenantID string, version backend.Version) error {
+	ctx, span := tracer.Start(ctx, "Store.Delete", trace.WithAttributes(attribute.String("tenant", tenantID)))
+	defer span.End()
+
+	if err := s.rw.DeleteVersioned(ctx, FileName, []string{KeyPath, tenantID}, version); err != nil {
+		return err
+	}
+
+	s.setCache(tenantID, Macros{})
+	return nil
+}
+
+// Expand replaces the macros referenced by the query with their definition. Queries for multiple
+// tenants are only expanded if the macros they reference expand the same way for every tenant.
+// Expansion failures are returned as an *InvalidQueryError.
+func (s *Store) Expand(ctx context.Context, query string) (string, error) {
+	if !strings.ContainsRune(query, traceql.MacroPrefix) {
+		return query, nil
+	}
+
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	expanded := ""
+	for i, tenantID := range tenantIDs {
+		macros, err := s.macros(ctx, tenantID)
+		if err != nil {
+			return "", err
+		}
+
+		tenantExpanded, err := traceql.ExpandMacros(query, macros)
+		if err != nil {
+			return "", &InvalidQueryError{err}
+		}
+		if i > 0 && tenantExpanded != expanded {
+			return "", &InvalidQueryError{fmt.Errorf("macros expand differently for tenants %s and %s", tenantIDs[0], tenantID)}
+		}
+		expanded = tenantExpanded
+
+		metricExpanded.WithLabelValues(tenantID).Inc()
+	}
+
+	return expanded, nil
+}
+
+// macros returns the cached macros of the tenant, reading them from the backend if the cache
+// entry is missing or expired.
+func (s *Store) macros(ctx context.Context, tenantID string) (Macros, error) {
+	s.mtx.Lock()
+	entry, ok := s.cache[tenantID]
+	s.mtx.Unlock()
+
+	if ok && time.Now().Before(entry.expires) {
+		return entry.macros, nil
+	}
+
+	macros, _, err := s.Get(ctx, tenantID)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		macros, err = Macros{}, nil
+	}
+	if err != nil {
+		metricFetchFailed.WithLabelValues(tenantID).Inc()
+		return nil, fmt.Errorf("failed to fetch TraceQL macros: %w", err)
+	}
+
+	s.setCache(tenantID, macros)
+	return macros, nil
+}
+
+func (s *Store) setCache(tenantID string, macros Macros) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.cache[tenantID] = cacheEntry{
+		macros:  macros,
+		expires: time.Now().Add(s.cfg.CacheTTL),
+	}
+}
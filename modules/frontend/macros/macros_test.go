@@ -0,0 +1,201 @@
+package macros
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides/userconfigurable/client"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+func newTestStore(t *testing.T) *Store {
+	cfg := &Config{
+		Enabled:   true,
+		CacheTTL:  time.Hour,
+		MaxMacros: 3,
+		Client: client.Config{
+			Backend: backend.Local,
+			Local:   &local.Config{Path: t.TempDir()},
+		},
+	}
+
+	s, err := New(cfg)
+	require.NoError(t, err)
+	t.Cleanup(s.Shutdown)
+	return s
+}
+
+func TestMacrosValidate(t *testing.T) {
+	tcs := []struct {
+		name   string
+		macros Macros
+		err    string
+	}{
+		{
+			name:   "valid",
+			macros: Macros{"errors": "status = error", "slow_errors": "$errors && duration > 1s"},
+		},
+		{
+			name:   "too many",
+			macros: Macros{"a": "true", "b": "true", "c": "true", "d": "true"},
+			err:    "too many macros: 4, the maximum is 3",
+		},
+		{
+			name:   "invalid name",
+			macros: Macros{"1errors": "status = error"},
+			err:    `invalid macro name "1errors": names may only contain letters, digits and underscores and must not start with a digit`,
+		},
+		{
+			name:   "empty",
+			macros: Macros{"errors": " "},
+			err:    "macro $errors is empty",
+		},
+		{
+			name:   "unknown reference",
+			macros: Macros{"errors": "$missing"},
+			err:    "macro $errors: unknown macro: $missing",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.macros.Validate(3)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestStoreExpand(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	_, err := s.Set(ctx, "a", Macros{"errors": "status = error"}, backend.VersionNew)
+	require.NoError(t, err)
+	_, err = s.Set(ctx, "b", Macros{"errors": "status = error"}, backend.VersionNew)
+	require.NoError(t, err)
+	_, err = s.Set(ctx, "c", Macros{"errors": "span.http.status_code >= 500"}, backend.VersionNew)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		tenant   string
+		query    string
+		expected string
+		err      string
+	}{
+		{
+			tenant:   "a",
+			query:    "{ $errors }",
+			expected: "{ (status = error) }",
+		},
+		{
+			tenant:   "c",
+			query:    "{ $errors }",
+			expected: "{ (span.http.status_code >= 500) }",
+		},
+		{
+			tenant:   "a|b",
+			query:    "{ $errors }",
+			expected: "{ (status = error) }",
+		},
+		{
+			tenant: "a|c",
+			query:  "{ $errors }",
+			err:    "macros expand differently for tenants a and c",
+		},
+		{
+			tenant: "d",
+			query:  "{ $errors }",
+			err:    "unknown macro: $errors",
+		},
+		{
+			// queries without macros are never looked up
+			tenant:   "d",
+			query:    "{ status = error }",
+			expected: "{ status = error }",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.tenant+tc.query, func(t *testing.T) {
+			actual, err := s.Expand(user.InjectOrgID(ctx, tc.tenant), tc.query)
+			if tc.err != "" {
+				var invalidErr *InvalidQueryError
+				require.ErrorAs(t, err, &invalidErr)
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+
+	// deleting the macros is visible immediately
+	_, version, err := s.Get(ctx, "a")
+	require.NoError(t, err)
+	require.NoError(t, s.Delete(ctx, "a", version))
+
+	_, err = s.Expand(user.InjectOrgID(ctx, "a"), "{ $errors }")
+	require.EqualError(t, err, "unknown macro: $errors")
+}
+
+func TestHandler(t *testing.T) {
+	s := newTestStore(t)
+	handler := s.Handler()
+
+	do := func(method, body, ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/traceql/macros", bytes.NewReader([]byte(body)))
+		req = req.WithContext(user.InjectOrgID(req.Context(), "tenant"))
+		if ifMatch != "" {
+			req.Header.Set(headerIfMatch, ifMatch)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	resp := do(http.MethodGet, "", "")
+	require.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp = do(http.MethodPost, `{"errors":"status = error"}`, "")
+	require.Equal(t, http.StatusPreconditionRequired, resp.Code)
+
+	resp = do(http.MethodPost, `{"errors":"$missing"}`, string(backend.VersionNew))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Equal(t, "macro $errors: unknown macro: $missing\n", resp.Body.String())
+
+	resp = do(http.MethodPost, `{"errors":"`+strings.Repeat(" ", maxBodyBytes)+`status = error"}`, string(backend.VersionNew))
+	require.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+
+	resp = do(http.MethodPost, `{"errors":"status = error"}`, string(backend.VersionNew))
+	require.Equal(t, http.StatusOK, resp.Code)
+	version := resp.Header().Get(headerEtag)
+	require.NotEmpty(t, version)
+
+	resp = do(http.MethodGet, "", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, version, resp.Header().Get(headerEtag))
+	require.JSONEq(t, `{"errors":"status = error"}`, resp.Body.String())
+
+	resp = do(http.MethodPut, "", "")
+	require.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+
+	resp = do(http.MethodDelete, "", version)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	resp = do(http.MethodGet, "", "")
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -0,0 +1,126 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gogo/status"
+	"google.golang.org/grpc/codes"
+
+	"github.com/grafana/tempo/modules/frontend/macros"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// queryParams are the url parameters that can hold a TraceQL query.
+var queryParams = []string{"q", "query"}
+
+// newMacroExpansionRoundTripper expands the TraceQL macros referenced by the query of the
+// request before it is parsed by the next round tripper. If store is nil next is returned.
+func newMacroExpansionRoundTripper(store *macros.Store, next http.RoundTripper) http.RoundTripper {
+	if store == nil {
+		return next
+	}
+
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		vals := req.URL.Query()
+		changed := false
+
+		for _, param := range queryParams {
+			query := vals.Get(param)
+			if query == "" {
+				continue
+			}
+
+			expanded, err := store.Expand(req.Context(), query)
+			if err != nil {
+				return macroExpansionHTTPError(err), nil
+			}
+			if expanded != query {
+				vals.Set(param, expanded)
+				changed = true
+			}
+		}
+
+		if changed {
+			req = req.Clone(req.Context())
+			req.URL.RawQuery = vals.Encode()
+			req.RequestURI = ""
+		}
+
+		return next.RoundTrip(req)
+	})
+}
+
+// expandMacros expands the macros referenced by a query received over gRPC.
+func expandMacros(ctx context.Context, store *macros.Store, query *string) error {
+	if store == nil {
+		return nil
+	}
+
+	expanded, err := store.Expand(ctx, *query)
+	if err != nil {
+		var invalidErr *macros.InvalidQueryError
+		if errors.As(err, &invalidErr) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	*query = expanded
+	return nil
+}
+
+func newMacroExpansionStreamingSearchHandler(store *macros.Store, next streamingSearchHandler) streamingSearchHandler {
+	if store == nil {
+		return next
+	}
+
+	return func(req *tempopb.SearchRequest, srv tempopb.StreamingQuerier_SearchServer) error {
+		if err := expandMacros(srv.Context(), store, &req.Query); err != nil {
+			return err
+		}
+		return next(req, srv)
+	}
+}
+
+func newMacroExpansionStreamingQueryRangeHandler(store *macros.Store, next streamingQueryRangeHandler) streamingQueryRangeHandler {
+	if store == nil {
+		return next
+	}
+
+	return func(req *tempopb.QueryRangeRequest, srv tempopb.StreamingQuerier_MetricsQueryRangeServer) error {
+		if err := expandMacros(srv.Context(), store, &req.Query); err != nil {
+			return err
+		}
+		return next(req, srv)
+	}
+}
+
+func newMacroExpansionStreamingQueryInstantHandler(store *macros.Store, next streamingQueryInstantHandler) streamingQueryInstantHandler {
+	if store == nil {
+		return next
+	}
+
+	return func(req *tempopb.QueryInstantRequest, srv tempopb.StreamingQuerier_MetricsQueryInstantServer) error {
+		if err := expandMacros(srv.Context(), store, &req.Query); err != nil {
+			return err
+		}
+		return next(req, srv)
+	}
+}
+
+func macroExpansionHTTPError(err error) *http.Response {
+	var invalidErr *macros.InvalidQueryError
+	if errors.As(err, &invalidErr) {
+		return httpInvalidRequest(err)
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Status:     http.StatusText(http.StatusInternalServerError),
+		Body:       io.NopCloser(strings.NewReader(err.Error())),
+	}
+}
@@ -0,0 +1,78 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/frontend/macros"
+	"github.com/grafana/tempo/modules/overrides/userconfigurable/client"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+func TestMacroExpansionRoundTripper(t *testing.T) {
+	store, err := macros.New(&macros.Config{
+		Enabled:  true,
+		CacheTTL: time.Hour,
+		Client: client.Config{
+			Backend: backend.Local,
+			Local:   &local.Config{Path: t.TempDir()},
+		},
+	})
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	_, err = store.Set(context.Background(), "tenant", macros.Macros{"errors": "status = error"}, backend.VersionNew)
+	require.NoError(t, err)
+
+	var received string
+	rt := newMacroExpansionRoundTripper(store, RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		received = req.URL.Query().Get("q")
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}))
+
+	tcs := []struct {
+		query              string
+		expectedQuery      string
+		expectedStatusCode int
+	}{
+		{
+			query:              "{ $errors } | rate()",
+			expectedQuery:      "{ (status = error) } | rate()",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			query:              "{ span.foo = `$errors` }",
+			expectedQuery:      "{ span.foo = `$errors` }",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			query:              "{ $missing }",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			received = ""
+
+			req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+			req.URL.RawQuery = url.Values{"q": []string{tc.query}}.Encode()
+			req = req.WithContext(user.InjectOrgID(req.Context(), "tenant"))
+
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+			require.Equal(t, tc.expectedQuery, received)
+		})
+	}
+}
@@ -774,3 +774,76 @@ func (m *mockRoundTripperWithCapture) RoundTrip(req pipeline.Request) (*http.Res
 	res, err := m.rt.RoundTrip(req)
 	return res, err
 }
+
+func TestQueryRangeHandlerOffset(t *testing.T) {
+	meta := &backend.BlockMeta{
+		StartTime:         time.Unix(150, 0),
+		EndTime:           time.Unix(160, 0),
+		Size_:             defaultTargetBytesPerRequest,
+		TotalRecords:      1,
+		BlockID:           backend.MustParse("00000000-0000-0000-0000-000000000123"),
+		ReplicationFactor: 1,
+	}
+
+	rt := &mockRoundTripperWithCapture{
+		rt: mockRoundTripper{
+			responseFn: func() proto.Message {
+				// the queriers return the results at the shifted timestamps
+				return &tempopb.QueryRangeResponse{
+					Series: []*tempopb.TimeSeries{
+						{
+							Labels:  []v1.KeyValue{{Key: "foo", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "bar"}}}},
+							Samples: []tempopb.Sample{{TimestampMs: 200_000, Value: 3}},
+						},
+					},
+				}
+			},
+		},
+	}
+	f := frontendWithSettings(t, rt, &mockReader{metas: []*backend.BlockMeta{meta}}, nil, nil)
+
+	httpReq := httptest.NewRequest("GET", api.PathMetricsQueryRange, nil)
+	httpReq = api.BuildQueryRangeRequest(httpReq, &tempopb.QueryRangeRequest{
+		Query: "{} | rate() offset 900s",
+		Start: uint64(1100 * time.Second),
+		End:   uint64(1300 * time.Second),
+		Step:  uint64(100 * time.Second),
+	}, "")
+	httpReq = httpReq.WithContext(user.InjectOrgID(httpReq.Context(), "foo"))
+
+	httpResp := httptest.NewRecorder()
+	f.MetricsQueryRangeHandler.ServeHTTP(httpResp, httpReq)
+	require.Equal(t, 200, httpResp.Code, httpResp.Body.String())
+
+	// the block is selected for the shifted time range
+	require.NotNil(t, rt.req)
+	require.LessOrEqual(t, rt.req.Start, uint64(150*time.Second))
+	require.GreaterOrEqual(t, rt.req.End, uint64(160*time.Second))
+
+	actualResp := &tempopb.QueryRangeResponse{}
+	require.NoError(t, jsonpb.Unmarshal(httpResp.Body, actualResp))
+	require.Len(t, actualResp.Series, 1)
+	require.Equal(t, []tempopb.Sample{
+		{TimestampMs: 1100_000, Value: 3}, // 200s + 900s
+		{TimestampMs: 1200_000, Value: 0},
+		{TimestampMs: 1300_000, Value: 0},
+	}, actualResp.Series[0].Samples)
+}
+
+func TestQueryRangeHandlerOffsetNotMultipleOfStep(t *testing.T) {
+	f := frontendWithSettings(t, &mockRoundTripper{}, &mockReader{}, nil, nil)
+
+	httpReq := httptest.NewRequest("GET", api.PathMetricsQueryRange, nil)
+	httpReq = api.BuildQueryRangeRequest(httpReq, &tempopb.QueryRangeRequest{
+		Query: "{} | rate() offset 150s",
+		Start: uint64(1100 * time.Second),
+		End:   uint64(1300 * time.Second),
+		Step:  uint64(100 * time.Second),
+	}, "")
+	httpReq = httpReq.WithContext(user.InjectOrgID(httpReq.Context(), "foo"))
+
+	httpResp := httptest.NewRecorder()
+	f.MetricsQueryRangeHandler.ServeHTTP(httpResp, httpReq)
+	require.Equal(t, 400, httpResp.Code)
+	require.Contains(t, httpResp.Body.String(), "must be a multiple of the step")
+}
@@ -105,6 +105,23 @@ func (s queryRangeSharder) RoundTrip(pipelineRequest pipeline.Request) (pipeline
 		return pipeline.NewBadRequest(errors.New("step must be greater than 0")), nil
 	}
 
+	// Queries with an offset read an earlier time range. The blocks are selected for the shifted
+	// range and the combiner moves the results back to the requested timestamps.
+	offset, err := traceql.QueryRangeOffset(req, expr.Offset)
+	if err != nil {
+		return pipeline.NewBadRequest(err), nil
+	}
+	if offset > 0 {
+		if req.Start == 0 || req.End == 0 {
+			return pipeline.NewBadRequest(errors.New("offset requires a start and end time")), nil
+		}
+		if offset >= req.Start {
+			return pipeline.NewBadRequest(fmt.Errorf("offset %s is before the start of the unix epoch", expr.Offset)), nil
+		}
+		req.Start -= offset
+		req.End -= offset
+	}
+
 	// calculate and enforce max search duration
 	// This is checked before alignment because we may need to read a larger
 	// range internally to satisfy the query.
@@ -144,6 +161,7 @@ func (s queryRangeSharder) RoundTrip(pipelineRequest pipeline.Request) (pipeline
 	}
 
 	s.backendRequests(ctx, tenantID, pipelineRequest, *req, cutoff, targetBytesPerRequest, reqCh, jobMetadata)
+	shiftShards(jobMetadata.Shards, offset)
 
 	span.SetAttributes(attribute.Int64("totalJobs", int64(jobMetadata.TotalJobs)))
 	span.SetAttributes(attribute.Int64("totalBlocks", int64(jobMetadata.TotalBlocks)))
@@ -399,6 +417,23 @@ func (s *queryRangeSharder) exemplarsForBlock(m *backend.BlockMeta, totalExempla
 	return max(uint32(math.Ceil(share)), 1)
 }
 
+// shiftShards moves the completion times of the shards of a query with an offset to the
+// requested time range, the same as the results returned by the combiner.
+func shiftShards(shards []shardtracker.Shard, offsetNanos uint64) {
+	offsetSeconds := uint32(offsetNanos / uint64(time.Second)) //nolint:gosec
+	if offsetSeconds == 0 {
+		return
+	}
+
+	for i := range shards {
+		switch shards[i].CompletedThroughSeconds {
+		case shardtracker.TimestampNever, shardtracker.TimestampAlways, shardtracker.TimestampUnknown:
+			continue
+		}
+		shards[i].CompletedThroughSeconds += offsetSeconds
+	}
+}
+
 func hashForQueryRangeRequest(req *tempopb.QueryRangeRequest) uint64 {
 	if req.Query == "" {
 		return 0
@@ -312,7 +312,7 @@ func TestAsyncResponsesDoesNotLeak(t *testing.T) {
 					bridge := &pipelineBridge{
 						next: tc.finalRT(cancel),
 					}
-					httpCollector := NewHTTPCollector(sharder{next: bridge}, 0, combiner.NewSearch(0, false, api.HeaderAcceptJSON, false))
+					httpCollector := NewHTTPCollector(sharder{next: bridge}, 0, combiner.NewSearch(0, false, api.HeaderAcceptJSON, false, nil, nil))
 
 					_, _ = httpCollector.RoundTrip(req)
 
@@ -336,7 +336,7 @@ func TestAsyncResponsesDoesNotLeak(t *testing.T) {
 					bridge := &pipelineBridge{
 						next: tc.finalRT(cancel),
 					}
-					grpcCollector := NewGRPCCollector[*tempopb.SearchResponse](sharder{next: bridge}, 0, combiner.NewTypedSearch(0, false, api.HeaderAcceptJSON, false), func(_ *tempopb.SearchResponse) error { return nil })
+					grpcCollector := NewGRPCCollector[*tempopb.SearchResponse](sharder{next: bridge}, 0, combiner.NewTypedSearch(0, false, api.HeaderAcceptJSON, false, nil, nil), func(_ *tempopb.SearchResponse) error { return nil })
 
 					_ = grpcCollector.RoundTrip(req)
 
@@ -362,7 +362,7 @@ func TestAsyncResponsesDoesNotLeak(t *testing.T) {
 					}
 
 					s := sharder{next: sharder{next: bridge}, funcSharder: true}
-					grpcCollector := NewGRPCCollector[*tempopb.SearchResponse](s, 0, combiner.NewTypedSearch(0, false, api.HeaderAcceptJSON, false), func(_ *tempopb.SearchResponse) error { return nil })
+					grpcCollector := NewGRPCCollector[*tempopb.SearchResponse](s, 0, combiner.NewTypedSearch(0, false, api.HeaderAcceptJSON, false, nil, nil), func(_ *tempopb.SearchResponse) error { return nil })
 
 					_ = grpcCollector.RoundTrip(req)
 
@@ -387,7 +387,7 @@ func TestAsyncResponsesDoesNotLeak(t *testing.T) {
 					}
 
 					s := sharder{next: sharder{next: bridge, funcSharder: true}}
-					grpcCollector := NewGRPCCollector[*tempopb.SearchResponse](s, 0, combiner.NewTypedSearch(0, false, api.HeaderAcceptJSON, false), func(_ *tempopb.SearchResponse) error { return nil })
+					grpcCollector := NewGRPCCollector[*tempopb.SearchResponse](s, 0, combiner.NewTypedSearch(0, false, api.HeaderAcceptJSON, false, nil, nil), func(_ *tempopb.SearchResponse) error { return nil })
 
 					_ = grpcCollector.RoundTrip(req)
 
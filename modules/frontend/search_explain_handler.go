@@ -0,0 +1,255 @@
+package frontend
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log" //nolint:all deprecated
+	"github.com/go-kit/log/level"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/validation"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const (
+	explainTierIngesters = "ingesters"
+	explainTierBackend   = "backend"
+)
+
+// searchExplainResponse describes how a TraceQL search would be executed without executing it.
+type searchExplainResponse struct {
+	// Query is the query after the AST rewrites
+	Query             string              `json:"query"`
+	OptimizationCount int                 `json:"optimizationCount"`
+	Hints             []searchExplainHint `json:"hints,omitempty"`
+	// AllConditions is true if the storage layer only returns spansets that match all conditions
+	AllConditions bool                `json:"allConditions"`
+	Tiers         []searchExplainTier `json:"tiers"`
+}
+
+type searchExplainHint struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Applied is false for unsafe hints that are not allowed for the tenant
+	Applied bool `json:"applied"`
+}
+
+// searchExplainTier is the part of the search that is executed by the ingesters or on the backend blocks.
+type searchExplainTier struct {
+	Name                 string                   `json:"name"`
+	Start                uint32                   `json:"start"`
+	End                  uint32                   `json:"end"`
+	Jobs                 int                      `json:"jobs"`
+	Shards               int                      `json:"shards,omitempty"`
+	Blocks               int                      `json:"blocks,omitempty"`
+	Bytes                uint64                   `json:"bytes,omitempty"`
+	Conditions           []searchExplainCondition `json:"conditions"`
+	SecondPassConditions []searchExplainCondition `json:"secondPassConditions"`
+	DedicatedColumns     []string                 `json:"dedicatedColumns,omitempty"`
+}
+
+type searchExplainCondition struct {
+	Attribute string `json:"attribute"`
+	// Op is empty if the attribute is fetched without filtering
+	Op              string   `json:"op,omitempty"`
+	Operands        []string `json:"operands,omitempty"`
+	DedicatedColumn bool     `json:"dedicatedColumn,omitempty"`
+}
+
+// newSearchExplainHTTPHandler returns a handler that plans a search like the search sharder does and
+// returns the plan instead of executing it.
+func newSearchExplainHTTPHandler(cfg Config, reader tempodb.Reader, o overrides.Interface, logger log.Logger, dataAccessController DataAccessController) http.RoundTripper {
+	sharder := &asyncSearchSharder{
+		reader:    reader,
+		overrides: o,
+		cfg:       cfg.Search.Sharder,
+		logger:    logger,
+	}
+
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		tenant, err := validation.ExtractValidTenantID(req.Context())
+		if err != nil {
+			return httpInvalidRequest(err), nil
+		}
+
+		if dataAccessController != nil {
+			if err := dataAccessController.HandleHTTPSearchReq(req); err != nil {
+				level.Error(logger).Log("msg", "search explain: access control handling failed", "err", err)
+				return httpInvalidRequest(err), nil
+			}
+		}
+
+		searchReq, err := api.ParseSearchRequestWithDefault(req, cfg.Search.Sharder.DefaultSpansPerSpanSet)
+		if err != nil {
+			return httpInvalidRequest(err), nil
+		}
+
+		// the search pipeline always searches a time range, see NewAdjustStartEndWare
+		start, end, err := api.ClampDateRangeReq(req, cfg.Search.Sharder.QueryBackendAfter, cfg.QueryEndCutoff)
+		if err != nil {
+			return httpInvalidRequest(fmt.Errorf("error parsing date range: %w", err)), nil
+		}
+		searchReq.Start = uint32(start.Unix())
+		searchReq.End = uint32(end.Unix())
+
+		maxDuration := sharder.maxDuration(tenant)
+		if maxDuration != 0 && time.Duration(searchReq.End-searchReq.Start)*time.Second > maxDuration {
+			return httpInvalidRequest(fmt.Errorf("range specified by start and end exceeds %s. received start=%d end=%d", maxDuration, searchReq.Start, searchReq.End)), nil
+		}
+
+		rootExpr, _, _, _, fetchReq, err := traceql.Compile(searchReq.Query)
+		if err != nil {
+			return httpInvalidRequest(err), nil
+		}
+
+		secondPass := append(fetchReq.SecondPassConditions, traceql.SearchMetaConditionsWithout(fetchReq.Conditions, fetchReq.AllConditions)...)
+
+		explain := &searchExplainResponse{
+			Query:             rootExpr.String(),
+			OptimizationCount: rootExpr.OptimizationCount,
+			Hints:             explainHints(rootExpr.Hints, o.UnsafeQueryHints(tenant)),
+			AllConditions:     fetchReq.AllConditions,
+		}
+
+		// ingesters
+		if subReqs := sharder.ingesterSubRequests(*searchReq); len(subReqs) > 0 {
+			tier := searchExplainTier{
+				Name:  explainTierIngesters,
+				Start: subReqs[0].Start,
+				End:   subReqs[len(subReqs)-1].End,
+				Jobs:  len(subReqs),
+			}
+			tier.explainConditions(fetchReq.Conditions, secondPass, o.DedicatedColumns(tenant))
+			explain.Tiers = append(explain.Tiers, tier)
+		}
+
+		// backend
+		if blocks := sharder.backendBlocks(tenant, searchReq); len(blocks) > 0 {
+			backendStart, backendEnd := backendRange(searchReq.Start, searchReq.End, cfg.Search.Sharder.QueryBackendAfter)
+			tier := searchExplainTier{
+				Name:   explainTierBackend,
+				Start:  backendStart,
+				End:    backendEnd,
+				Blocks: len(blocks),
+			}
+			backendJobsFunc(blocks, cfg.Search.Sharder.TargetBytesPerRequest, cfg.Search.Sharder.MostRecentShards, searchReq.End)(func(jobs int, sz uint64, _ uint32) {
+				tier.Jobs += jobs
+				tier.Bytes += sz
+				tier.Shards++
+			}, nil)
+
+			var dcs backend.DedicatedColumns
+			for _, b := range blocks {
+				dcs = append(dcs, b.DedicatedColumns...)
+			}
+			tier.explainConditions(fetchReq.Conditions, secondPass, dcs)
+			explain.Tiers = append(explain.Tiers, tier)
+		}
+
+		body, err := json.Marshal(explain)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling response body: %w", err)
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				api.HeaderContentType: {api.HeaderAcceptJSON},
+			},
+			Body:          io.NopCloser(strings.NewReader(string(body))),
+			ContentLength: int64(len(body)),
+		}, nil
+	})
+}
+
+func explainHints(hints *traceql.Hints, allowUnsafe bool) []searchExplainHint {
+	if hints == nil {
+		return nil
+	}
+
+	result := make([]searchExplainHint, 0, len(hints.Hints))
+	for _, h := range hints.Hints {
+		// a hint is only returned without allowing unsafe hints if it's safe
+		_, safe := hints.Get(h.Name, h.Value.Type, false)
+		result = append(result, searchExplainHint{
+			Name:    h.Name,
+			Value:   h.Value.EncodeToString(false),
+			Applied: safe || allowUnsafe,
+		})
+	}
+	return result
+}
+
+// explainConditions sets the conditions of the tier and the dedicated columns they are read from.
+func (t *searchExplainTier) explainConditions(conditions, secondPass []traceql.Condition, dcs backend.DedicatedColumns) {
+	hit := map[string]struct{}{}
+
+	convert := func(conds []traceql.Condition) []searchExplainCondition {
+		result := make([]searchExplainCondition, 0, len(conds))
+		for _, c := range conds {
+			ec := searchExplainCondition{
+				Attribute: c.Attribute.String(),
+			}
+			if c.Op != traceql.OpNone {
+				ec.Op = c.Op.String()
+			}
+			for _, o := range c.Operands {
+				ec.Operands = append(ec.Operands, o.String())
+			}
+			for _, col := range dedicatedColumnsForAttribute(c.Attribute, dcs) {
+				ec.DedicatedColumn = true
+				hit[col] = struct{}{}
+			}
+			result = append(result, ec)
+		}
+		return result
+	}
+
+	t.Conditions = convert(conditions)
+	t.SecondPassConditions = convert(secondPass)
+
+	for col := range hit {
+		t.DedicatedColumns = append(t.DedicatedColumns, col)
+	}
+	sort.Strings(t.DedicatedColumns)
+}
+
+// dedicatedColumnsForAttribute returns the dedicated columns an attribute is read from. Unscoped attributes
+// are read from the span and resource columns.
+func dedicatedColumnsForAttribute(a traceql.Attribute, dcs backend.DedicatedColumns) []string {
+	if a.Intrinsic != traceql.IntrinsicNone {
+		return nil
+	}
+
+	var cols []string
+	for _, dc := range dcs {
+		if dc.Name != a.Name {
+			continue
+		}
+
+		match := false
+		switch a.Scope {
+		case traceql.AttributeScopeNone:
+			match = dc.Scope == backend.DedicatedColumnScopeSpan || dc.Scope == backend.DedicatedColumnScopeResource
+		case traceql.AttributeScopeSpan:
+			match = dc.Scope == backend.DedicatedColumnScopeSpan
+		case traceql.AttributeScopeResource:
+			match = dc.Scope == backend.DedicatedColumnScopeResource
+		case traceql.AttributeScopeEvent:
+			match = dc.Scope == backend.DedicatedColumnScopeEvent
+		}
+		if match {
+			cols = append(cols, string(dc.Scope)+"."+dc.Name)
+		}
+	}
+	return cols
+}
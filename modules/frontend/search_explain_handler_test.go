@@ -0,0 +1,135 @@
+package frontend
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestSearchExplainHandler(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{
+			Storage: overrides.StorageOverrides{
+				DedicatedColumns: backend.DedicatedColumns{
+					{Scope: backend.DedicatedColumnScopeSpan, Name: "http.method", Type: backend.DedicatedColumnTypeString},
+				},
+			},
+		},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	now := time.Now()
+	blockStart := now.Add(-3 * time.Hour)
+	blockEnd := now.Add(-2 * time.Hour)
+	reader := &mockReader{
+		metas: []*backend.BlockMeta{
+			{
+				StartTime:    blockStart,
+				EndTime:      blockEnd,
+				Size_:        defaultTargetBytesPerRequest * 2,
+				TotalRecords: 2,
+				DedicatedColumns: backend.DedicatedColumns{
+					{Scope: backend.DedicatedColumnScopeResource, Name: "cluster", Type: backend.DedicatedColumnTypeString},
+				},
+			},
+		},
+	}
+
+	cfg := Config{
+		Search: SearchConfig{
+			Sharder: SearchSharderConfig{
+				ConcurrentRequests:    defaultConcurrentRequests,
+				TargetBytesPerRequest: defaultTargetBytesPerRequest,
+				MostRecentShards:      defaultMostRecentShards,
+				QueryBackendAfter:     time.Hour,
+				IngesterShards:        2,
+			},
+		},
+	}
+	rt := newSearchExplainHTTPHandler(cfg, reader, o, nil, nil)
+
+	do := func(query string, start, end time.Time) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/api/search/explain", nil)
+		req.URL.RawQuery = url.Values{
+			"q":     []string{query},
+			"start": []string{strconv.FormatInt(start.Unix(), 10)},
+			"end":   []string{strconv.FormatInt(end.Unix(), 10)},
+		}.Encode()
+		req = req.WithContext(user.InjectOrgID(req.Context(), "tenant"))
+
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := do(`{ span.http.method = "GET" || span.http.method = "POST" } && { .cluster = "prod" } with(most_recent=true, job_size=1)`, now.Add(-4*time.Hour), now)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	explain := &searchExplainResponse{}
+	require.NoError(t, json.Unmarshal(body, explain))
+
+	// the or of the span filter is rewritten to an IN
+	require.Equal(t, 1, explain.OptimizationCount)
+	require.Contains(t, explain.Query, "span.http.method IN [`GET`, `POST`]")
+	require.False(t, explain.AllConditions)
+	require.Equal(t, []searchExplainHint{
+		{Name: traceql.HintMostRecent, Value: "true", Applied: true},
+		{Name: traceql.HintJobSize, Value: "1", Applied: false}, // unsafe
+	}, explain.Hints)
+
+	require.Len(t, explain.Tiers, 2)
+
+	ingesters := explain.Tiers[0]
+	require.Equal(t, explainTierIngesters, ingesters.Name)
+	require.Equal(t, 2, ingesters.Jobs)
+	require.Equal(t, []string{"span.http.method"}, ingesters.DedicatedColumns)
+	require.Equal(t, []searchExplainCondition{
+		{Attribute: "span.http.method", Op: "IN", Operands: []string{"[`GET`, `POST`]"}, DedicatedColumn: true},
+		{Attribute: ".cluster", Op: "=", Operands: []string{"`prod`"}},
+	}, ingesters.Conditions)
+	require.NotEmpty(t, ingesters.SecondPassConditions)
+
+	be := explain.Tiers[1]
+	require.Equal(t, explainTierBackend, be.Name)
+	require.Equal(t, 1, be.Blocks)
+	require.Equal(t, 2, be.Jobs)
+	require.Equal(t, 1, be.Shards)
+	require.Equal(t, uint64(defaultTargetBytesPerRequest*2), be.Bytes)
+	require.Equal(t, []string{"resource.cluster"}, be.DedicatedColumns)
+	require.Equal(t, []searchExplainCondition{
+		{Attribute: "span.http.method", Op: "IN", Operands: []string{"[`GET`, `POST`]"}},
+		{Attribute: ".cluster", Op: "=", Operands: []string{"`prod`"}, DedicatedColumn: true},
+	}, be.Conditions)
+
+	// recent data is only searched in the ingesters
+	resp = do(`{ }`, now.Add(-30*time.Minute), now)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	explain = &searchExplainResponse{}
+	require.NoError(t, json.Unmarshal(body, explain))
+	require.Len(t, explain.Tiers, 1)
+	require.Equal(t, explainTierIngesters, explain.Tiers[0].Name)
+
+	// invalid queries are rejected
+	resp = do(`{ span.foo = }`, now.Add(-30*time.Minute), now)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
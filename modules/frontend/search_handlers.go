@@ -12,6 +12,7 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level" //nolint:all //deprecated
+	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/status"
 	"github.com/grafana/dskit/user"
 	"github.com/grafana/tempo/modules/frontend/combiner"
@@ -23,10 +24,11 @@ import (
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util"
 )
 
 // newSearchStreamingGRPCHandler returns a handler that streams results from the HTTP handler
-func newSearchStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, logger log.Logger, dataAccessController DataAccessController) streamingSearchHandler {
+func newSearchStreamingGRPCHandler(cfg Config, next, traceByIDNext pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, logger log.Logger, dataAccessController DataAccessController) streamingSearchHandler {
 	postSLOHook := searchSLOPostHook(cfg.Search.SLO)
 	downstreamPath := path.Join(apiPrefix, api.PathSearch)
 
@@ -57,7 +59,8 @@ func newSearchStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[c
 		tenant, _ := user.ExtractOrgID(ctx)
 		start := time.Now()
 
-		comb, err := newCombiner(req, cfg.Search.Sharder, api.MarshallingFormatProtobuf, o.LeftPadTraceIDs(tenant))
+		lookup := newLinkedTraceLookup(cfg, traceByIDNext, apiPrefix, headers, o.MaxBytesPerTrace(tenant), dataAccessController)
+		comb, err := newCombiner(ctx, req, cfg.Search.Sharder, api.MarshallingFormatProtobuf, o.LeftPadTraceIDs(tenant), lookup, o.MaxLinkFanOut(tenant))
 		if err != nil {
 			level.Error(logger).Log("msg", "search streaming: could not create combiner", "err", err)
 			return status.Error(codes.InvalidArgument, err.Error())
@@ -85,7 +88,7 @@ func newSearchStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[c
 }
 
 // newSearchHTTPHandler returns a handler that returns a single response from the HTTP handler
-func newSearchHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, logger log.Logger, dataAccessController DataAccessController) http.RoundTripper {
+func newSearchHTTPHandler(cfg Config, next, traceByIDNext pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, logger log.Logger, dataAccessController DataAccessController) http.RoundTripper {
 	postSLOHook := searchSLOPostHook(cfg.Search.SLO)
 
 	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
@@ -112,7 +115,8 @@ func newSearchHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.P
 		// check marshalling format
 		marshallingFormat := api.MarshalingFormatFromAcceptHeader(req.Header)
 
-		comb, err := newCombiner(searchReq, cfg.Search.Sharder, marshallingFormat, o.LeftPadTraceIDs(tenant))
+		lookup := newLinkedTraceLookup(cfg, traceByIDNext, apiPrefix, req.Header, o.MaxBytesPerTrace(tenant), dataAccessController)
+		comb, err := newCombiner(req.Context(), searchReq, cfg.Search.Sharder, marshallingFormat, o.LeftPadTraceIDs(tenant), lookup, o.MaxLinkFanOut(tenant))
 		if err != nil {
 			level.Error(logger).Log("msg", "search: could not create combiner", "err", err)
 			return httpInvalidRequest(err), nil
@@ -139,15 +143,19 @@ func newSearchHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.P
 	})
 }
 
-func newCombiner(req *tempopb.SearchRequest, cfg SearchSharderConfig, marshalingFormat api.MarshallingFormat, padTraceIDs bool) (combiner.GRPCCombiner[*tempopb.SearchResponse], error) {
+// newCombiner returns the combiner of a search request. The links of a ->link query are followed
+// with lookup once all jobs are combined, at most maxLinkedTraces linked traces are looked up.
+func newCombiner(ctx context.Context, req *tempopb.SearchRequest, cfg SearchSharderConfig, marshalingFormat api.MarshallingFormat, padTraceIDs bool, lookup traceql.TraceLookup, maxLinkedTraces int) (combiner.GRPCCombiner[*tempopb.SearchResponse], error) {
 	limit, err := adjustLimit(req.Limit, cfg.DefaultLimit, cfg.MaxLimit)
 	if err != nil {
 		return nil, err
 	}
 
 	mostRecent := false
+	var query *traceql.RootExpr
+	var resolveLinks *combiner.LinkResolver
 	if len(req.Query) > 0 {
-		query, err := traceql.Parse(req.Query)
+		query, err = traceql.Parse(req.Query)
 		if err != nil {
 			return nil, fmt.Errorf("invalid TraceQL query: %s", err)
 		}
@@ -156,9 +164,69 @@ func newCombiner(req *tempopb.SearchRequest, cfg SearchSharderConfig, marshaling
 		if mostRecent, ok = query.Hints.GetBool(traceql.HintMostRecent, false); !ok {
 			mostRecent = false
 		}
+
+		if query.Linked != nil {
+			if maxLinkedTraces <= 0 {
+				return nil, traceql.ErrLinkTraversalDisabled
+			}
+			resolveLinks = &combiner.LinkResolver{
+				MaxTraces: maxLinkedTraces,
+				Resolve: func(resp *tempopb.SearchResponse, limit int) error {
+					return traceql.ResolveLinks(ctx, req, resp, lookup, limit, maxLinkedTraces)
+				},
+			}
+		}
 	}
 
-	return combiner.NewTypedSearch(int(limit), mostRecent, marshalingFormat, padTraceIDs), nil
+	return combiner.NewTypedSearch(int(limit), mostRecent, marshalingFormat, padTraceIDs, query, resolveLinks), nil
+}
+
+// newLinkedTraceLookup returns a traceql.TraceLookup that looks up the linked traces of a ->link query
+// with the trace by ID pipeline, the same as a trace by ID request with the headers of the search request.
+func newLinkedTraceLookup(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, header http.Header, maxBytes int, dataAccessController DataAccessController) traceql.TraceLookup {
+	return func(ctx context.Context, traceID []byte) (*tempopb.Trace, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, path.Join(apiPrefix, "/api/v2/traces", util.TraceIDToHexString(traceID)), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header = header.Clone()
+		req.Header.Set(api.HeaderAccept, api.HeaderAcceptProtobuf)
+		req.RequestURI = req.URL.RequestURI()
+
+		var traceRedactor combiner.TraceRedactor
+		if dataAccessController != nil {
+			traceRedactor, err = dataAccessController.HandleHTTPTraceByIDReq(req)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		comb := combiner.NewTypedTraceByIDV2(maxBytes, api.MarshallingFormatProtobuf, traceRedactor)
+		resp, err := pipeline.NewHTTPCollector(next, cfg.ResponseConsumers, comb).RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		switch resp.StatusCode {
+		case http.StatusOK:
+		case http.StatusNotFound:
+			// traces hidden by the data access controller are treated as not found
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("linked trace lookup failed with status %d: %s", resp.StatusCode, body)
+		}
+
+		traceResp := &tempopb.TraceByIDResponse{}
+		if err := proto.Unmarshal(body, traceResp); err != nil {
+			return nil, err
+		}
+		return traceResp.Trace, nil
+	}
 }
 
 // adjusts the limit based on provided config
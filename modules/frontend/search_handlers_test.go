@@ -10,6 +10,7 @@ import (
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"sync"
 	"testing"
@@ -32,6 +33,9 @@ import (
 	"github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/pkg/search"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb"
@@ -910,3 +914,146 @@ func frontendWithSettings(t require.TestingT, next pipeline.RoundTripper, rdr te
 
 	return f
 }
+
+func TestSearchLinksResolvedOnce(t *testing.T) {
+	const (
+		producerTraceID = "10000000000000000000000000000001"
+		consumerTraceID = "10000000000000000000000000000002"
+		internalTraceID = "10000000000000000000000000000003"
+		unlinkedTraceID = "10000000000000000000000000000004"
+	)
+
+	linkedSpan := func(spanID, traceID string) *tempopb.Span {
+		return &tempopb.Span{
+			SpanID: spanID,
+			Attributes: []*v1.KeyValue{
+				{Key: "link:traceID", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: traceID}}},
+				{Key: "link:spanID", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "0000000000000001"}}},
+			},
+		}
+	}
+	linkedTrace := func(traceID string, kind v1_trace.Span_SpanKind) *tempopb.Trace {
+		id, err := util.HexStringToTraceID(traceID)
+		require.NoError(t, err)
+		return &tempopb.Trace{
+			ResourceSpans: []*v1_trace.ResourceSpans{{
+				ScopeSpans: []*v1_trace.ScopeSpans{{
+					Spans: []*v1_trace.Span{{TraceId: id, SpanId: []byte{0, 0, 0, 0, 0, 0, 0, 1}, Name: "consume", Kind: kind}},
+				}},
+			}},
+		}
+	}
+
+	mtx := sync.Mutex{}
+	lookups := map[string]int{}
+	next := pipeline.RoundTripperFunc(func(r pipeline.Request) (*http.Response, error) {
+		req := r.HTTPRequest()
+
+		var resp proto.Message
+		switch {
+		case strings.Contains(req.URL.Path, "/api/v2/traces/"+consumerTraceID):
+			resp = &tempopb.TraceByIDResponse{Trace: linkedTrace(consumerTraceID, v1_trace.Span_SPAN_KIND_CONSUMER), Metrics: &tempopb.TraceByIDMetrics{}}
+		case strings.Contains(req.URL.Path, "/api/v2/traces/"+internalTraceID):
+			resp = &tempopb.TraceByIDResponse{Trace: linkedTrace(internalTraceID, v1_trace.Span_SPAN_KIND_INTERNAL), Metrics: &tempopb.TraceByIDMetrics{}}
+		default:
+			// every search job returns the same producer spans, and a trace without links
+			resp = &tempopb.SearchResponse{
+				Traces: []*tempopb.TraceSearchMetadata{{
+					TraceID:  unlinkedTraceID,
+					SpanSets: []*tempopb.SpanSet{{Matched: 1, Spans: []*tempopb.Span{{SpanID: "0000000000000021"}}}},
+				}, {
+					TraceID: producerTraceID,
+					SpanSets: []*tempopb.SpanSet{{
+						Matched: 2,
+						Spans:   []*tempopb.Span{linkedSpan("0000000000000011", consumerTraceID), linkedSpan("0000000000000012", internalTraceID)},
+					}},
+				}},
+				Metrics: &tempopb.SearchMetrics{},
+			}
+		}
+
+		if strings.Contains(req.URL.Path, "/api/v2/traces/") {
+			mtx.Lock()
+			lookups[req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]]++
+			mtx.Unlock()
+		}
+
+		b, err := proto.Marshal(resp)
+		require.NoError(t, err)
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{api.HeaderContentType: {api.HeaderAcceptProtobuf}},
+			Body:       io.NopCloser(bytes.NewReader(b)),
+		}, nil
+	})
+
+	tcs := []struct {
+		name            string
+		maxLinkFanOut   int
+		expectedLookups map[string]int
+		expectedSpans   []string
+		expectedSkipped uint32
+	}{
+		{
+			name:          "all links followed",
+			maxLinkFanOut: 10,
+			// every trace is looked up once with all trace by ID shards, not once per search job
+			expectedLookups: map[string]int{consumerTraceID: minQueryShards, internalTraceID: minQueryShards},
+			expectedSpans:   []string{"0000000000000011"},
+		},
+		{
+			name:            "fan out limit",
+			maxLinkFanOut:   1,
+			expectedLookups: map[string]int{consumerTraceID: minQueryShards},
+			expectedSpans:   []string{"0000000000000011"},
+			expectedSkipped: 1,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			lookups = map[string]int{}
+			f := frontendWithSettings(t, next, nil, nil, nil, func(_ *Config, o *overrides.Config) {
+				o.Defaults.Read.MaxLinkFanOut = tc.maxLinkFanOut
+			})
+
+			httpReq := httptest.NewRequest("GET", "/api/search", nil)
+			httpReq, err := api.BuildSearchRequest(httpReq, &tempopb.SearchRequest{
+				Query: "{ kind = producer } ->link { kind = consumer }",
+				Start: 1,
+				End:   100000,
+				// the limit applies once the traces are filtered by their links
+				Limit: 1,
+			})
+			require.NoError(t, err)
+			httpReq = httpReq.WithContext(user.InjectOrgID(httpReq.Context(), "foo"))
+
+			httpResp := httptest.NewRecorder()
+			f.SearchHandler.ServeHTTP(httpResp, httpReq)
+			require.Equal(t, 200, httpResp.Code, httpResp.Body.String())
+
+			actualResp := &tempopb.SearchResponse{}
+			require.NoError(t, jsonpb.Unmarshal(httpResp.Body, actualResp))
+			require.Equal(t, tc.expectedLookups, lookups)
+			require.Equal(t, tc.expectedSkipped, actualResp.Metrics.SkippedLinkedTraces)
+
+			// the producer trace and the linked consumer trace
+			require.Len(t, actualResp.Traces, 2)
+			var spanIDs []string
+			for _, s := range actualResp.Traces[0].SpanSets[0].Spans {
+				spanIDs = append(spanIDs, s.SpanID)
+			}
+			require.Equal(t, tc.expectedSpans, spanIDs)
+			require.Equal(t, consumerTraceID, actualResp.Traces[1].TraceID)
+		})
+	}
+
+	// link traversal is disabled
+	f := frontendWithSettings(t, next, nil, nil, nil)
+	httpReq := httptest.NewRequest("GET", "/api/search?q="+url.QueryEscape("{ kind = producer } ->link { }"), nil)
+	httpReq = httpReq.WithContext(user.InjectOrgID(httpReq.Context(), "foo"))
+	httpResp := httptest.NewRecorder()
+	f.SearchHandler.ServeHTTP(httpResp, httpReq)
+	require.Equal(t, 400, httpResp.Code)
+	require.Contains(t, httpResp.Body.String(), traceql.ErrLinkTraversalDisabled.Error())
+}
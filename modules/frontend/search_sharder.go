@@ -132,26 +132,12 @@ func (s asyncSearchSharder) RoundTrip(pipelineRequest pipeline.Request) (pipelin
 // backendRequest builds backend requests to search backend blocks. backendRequest takes ownership of reqCh and closes it.
 // it returns 3 int values: totalBlocks, totalBlockBytes, and estimated jobs
 func (s *asyncSearchSharder) backendRequests(ctx context.Context, tenantID string, parent pipeline.Request, searchReq *tempopb.SearchRequest, resp *combiner.SearchJobResponse, reqCh chan<- pipeline.Request, errFn func(error)) {
-	// request without start or end, search only in ingester
-	if searchReq.Start == 0 || searchReq.End == 0 {
+	blocks := s.backendBlocks(tenantID, searchReq)
+	if len(blocks) == 0 {
 		close(reqCh)
 		return
 	}
 
-	// calculate duration (start and end) to search the backend blocks
-	start, end := backendRange(searchReq.Start, searchReq.End, s.cfg.QueryBackendAfter)
-
-	// no need to search backend
-	if start == end {
-		close(reqCh)
-		return
-	}
-
-	startT := time.Unix(int64(start), 0)
-	endT := time.Unix(int64(end), 0)
-
-	blocks := blockMetasForSearch(s.reader.BlockMetas(tenantID), startT, endT, acceptAllBlocks)
-
 	// calculate metrics to return to the caller
 	resp.TotalBlocks = len(blocks)
 
@@ -172,6 +158,28 @@ func (s *asyncSearchSharder) backendRequests(ctx context.Context, tenantID strin
 	}()
 }
 
+// backendBlocks returns the blocks that need to be searched in the backend. nil is returned if the
+// search is covered by the ingesters.
+func (s *asyncSearchSharder) backendBlocks(tenantID string, searchReq *tempopb.SearchRequest) []*backend.BlockMeta {
+	// request without start or end, search only in ingester
+	if searchReq.Start == 0 || searchReq.End == 0 {
+		return nil
+	}
+
+	// calculate duration (start and end) to search the backend blocks
+	start, end := backendRange(searchReq.Start, searchReq.End, s.cfg.QueryBackendAfter)
+
+	// no need to search backend
+	if start == end {
+		return nil
+	}
+
+	startT := time.Unix(int64(start), 0)
+	endT := time.Unix(int64(end), 0)
+
+	return blockMetasForSearch(s.reader.BlockMetas(tenantID), startT, endT, acceptAllBlocks)
+}
+
 // ingesterRequest returns a new start and end time range for the backend as well as an http request
 // that covers the ingesters. If nil is returned for the http.Request then there is no ingesters query.
 // since this function modifies searchReq.Start and End we are taking a value instead of a pointer to prevent it from
@@ -192,11 +200,38 @@ func (s *asyncSearchSharder) ingesterRequests(tenantID string, parent pipeline.R
 		return resp, buildIngesterRequest(tenantID, parent, &searchReq, reqCh)
 	}
 
+	subReqs := s.ingesterSubRequests(searchReq)
+	if len(subReqs) == 0 {
+		return resp, nil
+	}
+
+	for _, subReq := range subReqs {
+		err := buildIngesterRequest(tenantID, parent, &subReq, reqCh)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// add one shard that covers no time at all. this will force the combiner to wait
+	//  for ingester requests to complete before moving on to the backend requests
+	ingesterJobs := len(reqCh)
+	resp.TotalJobs = ingesterJobs
+	resp.Shards = append(resp.Shards, shardtracker.Shard{
+		TotalJobs:               uint32(ingesterJobs),
+		CompletedThroughSeconds: shardtracker.TimestampNever,
+	})
+
+	return resp, nil
+}
+
+// ingesterSubRequests splits the part of a search request with a start and end that is covered by the
+// ingesters into up to IngesterShards requests. nil is returned if the ingesters don't need to be queried.
+func (s *asyncSearchSharder) ingesterSubRequests(searchReq tempopb.SearchRequest) []tempopb.SearchRequest {
 	ingesterUntil := uint32(time.Now().Add(-s.cfg.QueryBackendAfter).Unix())
 
 	// if there's no overlap between the query and ingester range just return nil
 	if searchReq.End < ingesterUntil {
-		return resp, nil
+		return nil
 	}
 
 	ingesterStart := searchReq.Start
@@ -209,7 +244,7 @@ func (s *asyncSearchSharder) ingesterRequests(tenantID string, parent pipeline.R
 
 	// if ingester start == ingester end then we don't need to query it
 	if ingesterStart == ingesterEnd {
-		return resp, nil
+		return nil
 	}
 
 	searchReq.Start = ingesterStart
@@ -224,6 +259,7 @@ func (s *asyncSearchSharder) ingesterRequests(tenantID string, parent pipeline.R
 		interval = intervalMinimum
 	}
 
+	var subReqs []tempopb.SearchRequest
 	for i := 0; i < s.cfg.IngesterShards; i++ {
 		var (
 			subReq     = searchReq
@@ -244,22 +280,10 @@ func (s *asyncSearchSharder) ingesterRequests(tenantID string, parent pipeline.R
 		subReq.Start = shardStart
 		subReq.End = shardEnd
 
-		err := buildIngesterRequest(tenantID, parent, &subReq, reqCh)
-		if err != nil {
-			return nil, err
-		}
+		subReqs = append(subReqs, subReq)
 	}
 
-	// add one shard that covers no time at all. this will force the combiner to wait
-	//  for ingester requests to complete before moving on to the backend requests
-	ingesterJobs := len(reqCh)
-	resp.TotalJobs = ingesterJobs
-	resp.Shards = append(resp.Shards, shardtracker.Shard{
-		TotalJobs:               uint32(ingesterJobs),
-		CompletedThroughSeconds: shardtracker.TimestampNever,
-	})
-
-	return resp, nil
+	return subReqs
 }
 
 // maxDuration returns the max search duration allowed for this tenant.
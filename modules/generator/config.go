@@ -8,9 +8,12 @@ import (
 	"slices"
 	"time"
 
+	"github.com/grafana/tempo/modules/generator/processor/criticalpath"
 	"github.com/grafana/tempo/modules/generator/processor/hostinfo"
 	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
+	"github.com/grafana/tempo/modules/generator/processor/spanevents"
 	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
+	"github.com/grafana/tempo/modules/generator/processor/traceqlmetrics"
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/modules/generator/storage"
 	"github.com/grafana/tempo/modules/generator/validation"
@@ -117,6 +120,16 @@ func (cfg *Config) Validate() error {
 		return err
 	}
 
+	if err := cfg.Registry.Validate(); err != nil {
+		return err
+	}
+
+	// without the __metrics_gen_instance label, every metrics-generator would write the same series
+	// to Prometheus remote write. Delta samples are only summed by the OTLP export destination.
+	if cfg.Registry.Temporality == registry.TemporalityDelta && len(cfg.Storage.RemoteWrite) > 0 {
+		return errors.New("registry temporality delta is only supported with the OTLP export, remote_write must not be configured")
+	}
+
 	if !slices.Contains(validCodecs, cfg.Codec) {
 		return fmt.Errorf("invalid codec: %s, valid choices are %s", cfg.Codec, validCodecs)
 	}
@@ -141,15 +154,21 @@ func (cfg *Config) Validate() error {
 }
 
 type ProcessorConfig struct {
-	ServiceGraphs servicegraphs.Config `yaml:"service_graphs"`
-	SpanMetrics   spanmetrics.Config   `yaml:"span_metrics"`
-	HostInfo      hostinfo.Config      `yaml:"host_info"`
+	ServiceGraphs  servicegraphs.Config  `yaml:"service_graphs"`
+	SpanMetrics    spanmetrics.Config    `yaml:"span_metrics"`
+	HostInfo       hostinfo.Config       `yaml:"host_info"`
+	TraceQLMetrics traceqlmetrics.Config `yaml:"traceql_metrics"`
+	SpanEvents     spanevents.Config     `yaml:"span_events"`
+	CriticalPath   criticalpath.Config   `yaml:"critical_path"`
 }
 
 func (cfg *ProcessorConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	cfg.ServiceGraphs.RegisterFlagsAndApplyDefaults(prefix, f)
 	cfg.SpanMetrics.RegisterFlagsAndApplyDefaults(prefix, f)
 	cfg.HostInfo.RegisterFlagsAndApplyDefaults(prefix, f)
+	cfg.TraceQLMetrics.RegisterFlagsAndApplyDefaults(prefix, f)
+	cfg.SpanEvents.RegisterFlagsAndApplyDefaults(prefix, f)
+	cfg.CriticalPath.RegisterFlagsAndApplyDefaults(prefix, f)
 }
 
 func (cfg *ProcessorConfig) Validate() error {
@@ -160,6 +179,18 @@ func (cfg *ProcessorConfig) Validate() error {
 	if err := validation.ValidateHostInfoMetricName(cfg.HostInfo.MetricName); err != nil {
 		errs = append(errs, err)
 	}
+	if err := validation.ValidateTraceQLMetrics(cfg.TraceQLMetrics.Metrics); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validation.ValidateCriticalPathWait(cfg.CriticalPath.Wait); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validation.ValidateCriticalPathMaxItems(cfg.CriticalPath.MaxItems); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validation.ValidateCriticalPathMaxSpansPerTrace(cfg.CriticalPath.MaxSpansPerTrace); err != nil {
+		errs = append(errs, err)
+	}
 
 	if len(errs) > 0 {
 		return multierr.Combine(errs...)
@@ -202,6 +233,8 @@ func (cfg *ProcessorConfig) copyWithOverrides(o metricsGeneratorOverrides, userI
 	if histograms := o.MetricsGeneratorGenerateNativeHistograms(userID); histograms != "" {
 		copyCfg.ServiceGraphs.HistogramOverride = registry.HistogramModeToValue[string(histograms)]
 		copyCfg.SpanMetrics.HistogramOverride = registry.HistogramModeToValue[string(histograms)]
+		copyCfg.TraceQLMetrics.HistogramOverride = registry.HistogramModeToValue[string(histograms)]
+		copyCfg.CriticalPath.HistogramOverride = registry.HistogramModeToValue[string(histograms)]
 	}
 
 	if dimensionMappings := o.MetricsGeneratorProcessorSpanMetricsDimensionMappings(userID); dimensionMappings != nil {
@@ -240,6 +273,24 @@ func (cfg *ProcessorConfig) copyWithOverrides(o metricsGeneratorOverrides, userI
 		copyCfg.HostInfo.MetricName = o.MetricsGeneratorProcessorHostInfoMetricName(userID)
 	}
 
+	if metrics := o.MetricsGeneratorProcessorTraceQLMetricsMetrics(userID); metrics != nil {
+		copyCfg.TraceQLMetrics.Metrics = metrics
+	}
+
+	if buckets := o.MetricsGeneratorProcessorTraceQLMetricsHistogramBuckets(userID); buckets != nil {
+		copyCfg.TraceQLMetrics.HistogramBuckets = buckets
+	}
+
+	if dimensions := o.MetricsGeneratorProcessorSpanEventsDimensions(userID); dimensions != nil {
+		copyCfg.SpanEvents.Dimensions = dimensions
+	}
+	if filterPolicies := o.MetricsGeneratorProcessorSpanEventsFilterPolicies(userID); filterPolicies != nil {
+		copyCfg.SpanEvents.FilterPolicies = filterPolicies
+	}
+	if dimensionMappings := o.MetricsGeneratorProcessorSpanEventsDimensionMappings(userID); dimensionMappings != nil {
+		copyCfg.SpanEvents.DimensionMappings = dimensionMappings
+	}
+
 	if spanMultiplierKey := o.MetricsGeneratorProcessorServiceGraphsSpanMultiplierKey(userID); spanMultiplierKey != "" {
 		copyCfg.ServiceGraphs.SpanMultiplierKey = spanMultiplierKey
 	}
@@ -256,6 +307,22 @@ func (cfg *ProcessorConfig) copyWithOverrides(o metricsGeneratorOverrides, userI
 		copyCfg.SpanMetrics.EnableTraceStateSpanMultiplier = enableTraceStateSpanMultiplier
 	}
 
+	if rootDimensions := o.MetricsGeneratorProcessorSpanMetricsRootDimensions(userID); rootDimensions != nil {
+		copyCfg.SpanMetrics.RootDimensions = rootDimensions
+	}
+
+	if wait := o.MetricsGeneratorProcessorCriticalPathWait(userID); wait != 0 {
+		copyCfg.CriticalPath.Wait = wait
+	}
+
+	if maxItems := o.MetricsGeneratorProcessorCriticalPathMaxItems(userID); maxItems != 0 {
+		copyCfg.CriticalPath.MaxItems = maxItems
+	}
+
+	if maxSpansPerTrace := o.MetricsGeneratorProcessorCriticalPathMaxSpansPerTrace(userID); maxSpansPerTrace != 0 {
+		copyCfg.CriticalPath.MaxSpansPerTrace = maxSpansPerTrace
+	}
+
 	copySubprocessors := make(map[spanmetrics.Subprocessor]bool)
 	for sp, enabled := range cfg.SpanMetrics.Subprocessors {
 		copySubprocessors[sp] = enabled
@@ -2,13 +2,18 @@ package generator
 
 import (
 	"flag"
+	"net/url"
 	"testing"
+	"time"
 
+	prometheus_common_config "github.com/prometheus/common/config"
+	prometheus_config "github.com/prometheus/prometheus/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
 	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
+	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/pkg/sharedconfig"
 	"github.com/grafana/tempo/pkg/spanfilter/config"
 )
@@ -235,6 +240,33 @@ func TestProcessorConfig_copyWithOverrides(t *testing.T) {
 		assert.True(t, copied.SpanMetrics.EnableTraceStateSpanMultiplier)
 	})
 
+	t.Run("root dimensions overrides", func(t *testing.T) {
+		o := &mockOverrides{
+			spanMetricsRootDimensions: []string{"service", "http.route"},
+		}
+
+		copied, err := original.copyWithOverrides(o, "tenant")
+		require.NoError(t, err)
+
+		assert.NotEqual(t, *original, copied)
+		assert.Equal(t, []string{"service", "http.route"}, copied.SpanMetrics.RootDimensions)
+	})
+
+	t.Run("critical path overrides", func(t *testing.T) {
+		o := &mockOverrides{
+			criticalPathWait:     30 * time.Second,
+			criticalPathMaxItems: 5000,
+		}
+
+		copied, err := original.copyWithOverrides(o, "tenant")
+		require.NoError(t, err)
+
+		assert.NotEqual(t, *original, copied)
+		assert.Equal(t, 30*time.Second, copied.CriticalPath.Wait)
+		assert.Equal(t, 5000, copied.CriticalPath.MaxItems)
+		assert.Equal(t, original.CriticalPath.MaxSpansPerTrace, copied.CriticalPath.MaxSpansPerTrace)
+	})
+
 	t.Run("dimension_mappings preserved when no override", func(t *testing.T) {
 		// Create original config with dimension_mappings set
 		originalWithMappings := &ProcessorConfig{
@@ -340,6 +372,26 @@ func TestConfig_ValidateRingMode(t *testing.T) {
 	})
 }
 
+func TestConfig_ValidateDeltaTemporality(t *testing.T) {
+	cfg := &Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", flag.NewFlagSet("", flag.PanicOnError))
+	cfg.Storage.Path = t.TempDir()
+	cfg.Registry.Temporality = registry.TemporalityDelta
+
+	t.Run("otlp export only", func(t *testing.T) {
+		cfg := *cfg
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("remote write", func(t *testing.T) {
+		cfg := *cfg
+		remoteWriteConfig := prometheus_config.DefaultRemoteWriteConfig
+		remoteWriteConfig.URL = &prometheus_common_config.URL{URL: &url.URL{Scheme: "http", Host: "prometheus", Path: "/api/prom/push"}}
+		cfg.Storage.RemoteWrite = []prometheus_config.RemoteWriteConfig{remoteWriteConfig}
+		require.EqualError(t, cfg.Validate(), "registry temporality delta is only supported with the OTLP export, remote_write must not be configured")
+	})
+}
+
 func boolPtr(b bool) *bool {
 	return &b
 }
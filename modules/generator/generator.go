@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"sync"
 	"time"
 
@@ -21,6 +23,7 @@ import (
 	"go.uber.org/atomic"
 	"google.golang.org/grpc/metadata"
 
+	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/modules/generator/storage"
 	"github.com/grafana/tempo/pkg/ingest"
 	"github.com/grafana/tempo/pkg/tempopb"
@@ -84,6 +87,9 @@ func New(cfg *Config, overrides metricsGeneratorOverrides, reg prometheus.Regist
 		return nil, err
 	}
 
+	// the OTLP exporter must not compute deltas of samples that already are deltas
+	cfg.Storage.OTLPExport.DeltaSamples = cfg.Registry.Temporality == registry.TemporalityDelta
+
 	err := os.MkdirAll(cfg.Storage.Path, 0o700)
 	if err != nil {
 		return nil, fmt.Errorf("failed to mkdir on %s: %w", cfg.Storage.Path, err)
@@ -158,6 +164,9 @@ func (g *Generator) stopping(_ error) error {
 
 	for _, inst := range g.instances {
 		go func(inst *instance) {
+			// persist the edges waiting for their pair, the spans completing them may be consumed
+			// after the restart
+			inst.snapshotEdges()
 			inst.shutdown()
 			wg.Done()
 		}(inst)
@@ -241,6 +250,13 @@ func (g *Generator) getInstanceByID(id string) (*instance, bool) {
 	return inst, ok
 }
 
+func (g *Generator) getInstances() []*instance {
+	g.instancesMtx.RLock()
+	defer g.instancesMtx.RUnlock()
+
+	return slices.Collect(maps.Values(g.instances))
+}
+
 func (g *Generator) createInstance(id string) (*instance, error) {
 	// Duplicate metrics generation errors occur when creating
 	// the wal for a tenant twice. This happens if the wal is
@@ -268,6 +284,8 @@ func (g *Generator) createInstance(id string) (*instance, error) {
 		return nil, err
 	}
 
+	inst.restoreEdges()
+
 	return inst, nil
 }
 
@@ -163,6 +163,13 @@ func (g *Generator) handlePartitionsAssigned(m map[string][]int32) {
 
 	g.assignedPartitions = append(g.assignedPartitions, assigned...)
 	sort.Slice(g.assignedPartitions, func(i, j int) bool { return g.assignedPartitions[i] < g.assignedPartitions[j] })
+
+	// the edges snapshotted when partitions were revoked are still in memory, and some of them may
+	// have completed or expired since. Drop the snapshots instead of restoring them. Instances
+	// created later restore their edges on creation.
+	for _, inst := range g.getInstances() {
+		inst.discardEdgeSnapshot()
+	}
 }
 
 func (g *Generator) handlePartitionsRevoked(partitions map[string][]int32) {
@@ -176,6 +183,12 @@ func (g *Generator) handlePartitionsRevoked(partitions map[string][]int32) {
 	g.assignedPartitions = revokePartitions(g.assignedPartitions, revoked)
 
 	ingest.ResetLagMetricsForRevokedPartitions(g.cfg.Ingest.Kafka.ConsumerGroup, revoked)
+
+	// snapshot the edges waiting for their pair, so they survive a restart before the partitions
+	// are assigned again. The edges are kept in memory, the snapshot is discarded on assignment.
+	for _, inst := range g.getInstances() {
+		inst.snapshotEdges()
+	}
 }
 
 // Helper function to format []int32 slice
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"sync"
@@ -18,9 +19,12 @@ import (
 	"github.com/grafana/tempo/modules/generator/localentitylimiter"
 	"github.com/grafana/tempo/modules/generator/localserieslimiter"
 	"github.com/grafana/tempo/modules/generator/processor"
+	"github.com/grafana/tempo/modules/generator/processor/criticalpath"
 	"github.com/grafana/tempo/modules/generator/processor/hostinfo"
 	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
+	"github.com/grafana/tempo/modules/generator/processor/spanevents"
 	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
+	"github.com/grafana/tempo/modules/generator/processor/traceqlmetrics"
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/modules/generator/storage"
 	"github.com/grafana/tempo/modules/generator/validation"
@@ -68,9 +72,15 @@ const (
 	reasonOutsideTimeRangeSlack = "outside_metrics_ingestion_slack"
 	reasonSpanMetricsFiltered   = "span_metrics_filtered"
 	reasonServiceGraphsFiltered = "service_graphs_filtered"
+	reasonSpanEventsFiltered    = "span_events_filtered"
 	reasonInvalidUTF8           = "invalid_utf8"
 )
 
+// edgeSnapshotsDir is the directory in the storage path that holds the service graph edge
+// snapshots. The WAL directories of the tenants are cleared on startup and shutdown, so the
+// snapshots are kept outside of them.
+const edgeSnapshotsDir = ".servicegraph-edges"
+
 type instance struct {
 	cfg *Config
 
@@ -295,6 +305,18 @@ func (i *instance) diffProcessors(desiredProcessors map[string]struct{}, desired
 			if !reflect.DeepEqual(p.Cfg, desiredCfg.HostInfo) {
 				toReplace = append(toReplace, processorName)
 			}
+		case *traceqlmetrics.Processor:
+			if !reflect.DeepEqual(p.Cfg, desiredCfg.TraceQLMetrics) {
+				toReplace = append(toReplace, processorName)
+			}
+		case *spanevents.Processor:
+			if !reflect.DeepEqual(p.Cfg, desiredCfg.SpanEvents) {
+				toReplace = append(toReplace, processorName)
+			}
+		case *criticalpath.Processor:
+			if !reflect.DeepEqual(p.Cfg, desiredCfg.CriticalPath) {
+				toReplace = append(toReplace, processorName)
+			}
 		default:
 			level.Error(i.logger).Log(
 				"msg", fmt.Sprintf("processor does not exist, supported processors: [%s]", strings.Join(validation.SupportedProcessors, ", ")),
@@ -318,7 +340,7 @@ func (i *instance) addProcessor(processorName string, cfg ProcessorConfig) error
 	case processor.SpanMetricsName:
 		filteredSpansCounter := metricSpansDiscarded.WithLabelValues(i.instanceID, reasonSpanMetricsFiltered, processor.SpanMetricsName)
 		invalidUTF8Counter := metricSpansDiscarded.WithLabelValues(i.instanceID, reasonInvalidUTF8, processor.SpanMetricsName)
-		newProcessor, err = spanmetrics.New(cfg.SpanMetrics, i.registry, filteredSpansCounter, invalidUTF8Counter)
+		newProcessor, err = spanmetrics.New(cfg.SpanMetrics, i.instanceID, i.registry, filteredSpansCounter, invalidUTF8Counter)
 		if err != nil {
 			return err
 		}
@@ -335,6 +357,25 @@ func (i *instance) addProcessor(processorName string, cfg ProcessorConfig) error
 		if err != nil {
 			return err
 		}
+	case processor.TraceQLMetricsName:
+		invalidUTF8Counter := metricSpansDiscarded.WithLabelValues(i.instanceID, reasonInvalidUTF8, processor.TraceQLMetricsName)
+		newProcessor, err = traceqlmetrics.New(cfg.TraceQLMetrics, i.registry, i.logger, invalidUTF8Counter)
+		if err != nil {
+			return err
+		}
+	case processor.SpanEventsName:
+		filteredSpansCounter := metricSpansDiscarded.WithLabelValues(i.instanceID, reasonSpanEventsFiltered, processor.SpanEventsName)
+		invalidUTF8Counter := metricSpansDiscarded.WithLabelValues(i.instanceID, reasonInvalidUTF8, processor.SpanEventsName)
+		newProcessor, err = spanevents.New(cfg.SpanEvents, i.registry, filteredSpansCounter, invalidUTF8Counter)
+		if err != nil {
+			return err
+		}
+	case processor.CriticalPathName:
+		invalidUTF8Counter := metricSpansDiscarded.WithLabelValues(i.instanceID, reasonInvalidUTF8, processor.CriticalPathName)
+		newProcessor, err = criticalpath.New(cfg.CriticalPath, i.instanceID, i.registry, i.logger, invalidUTF8Counter)
+		if err != nil {
+			return err
+		}
 	default:
 		level.Error(i.logger).Log(
 			"msg", fmt.Sprintf("processor does not exist, supported processors: [%s]", strings.Join(validation.SupportedProcessors, ", ")),
@@ -386,7 +427,7 @@ func (i *instance) pushSpans(ctx context.Context, req *tempopb.PushSpansRequest)
 
 	for _, proc := range i.processors {
 		switch proc.Name() {
-		case processor.SpanMetricsName, processor.ServiceGraphsName, processor.HostInfoName:
+		case processor.SpanMetricsName, processor.ServiceGraphsName, processor.HostInfoName, processor.TraceQLMetricsName, processor.SpanEventsName, processor.CriticalPathName:
 			if req.SkipMetricsGeneration {
 				metricSkippedProcessorPushes.WithLabelValues(i.instanceID).Inc()
 				break
@@ -403,7 +444,7 @@ func (i *instance) pushSpansFromQueue(ctx context.Context, _ time.Time, req *tem
 
 	for _, proc := range i.processors {
 		switch proc.Name() {
-		case processor.SpanMetricsName, processor.ServiceGraphsName, processor.HostInfoName:
+		case processor.SpanMetricsName, processor.ServiceGraphsName, processor.HostInfoName, processor.TraceQLMetricsName, processor.SpanEventsName, processor.CriticalPathName:
 			if req.SkipMetricsGeneration {
 				metricSkippedProcessorPushes.WithLabelValues(i.instanceID).Inc()
 				break
@@ -471,3 +512,59 @@ func (i *instance) shutdown() {
 		level.Error(i.logger).Log("msg", "closing wal failed", "tenant", i.instanceID, "err", err)
 	}
 }
+
+// snapshotEdges snapshots the edges of the service graphs processor that are waiting for their
+// pair, if edge snapshots are enabled.
+func (i *instance) snapshotEdges() {
+	p := i.serviceGraphsProcessor()
+	if p == nil {
+		return
+	}
+
+	if err := p.SnapshotEdges(i.edgeSnapshotPath()); err != nil {
+		level.Error(i.logger).Log("msg", "failed to snapshot service graph edges", "err", err)
+	}
+}
+
+// restoreEdges restores the edges of the service graphs processor from its snapshot, if edge
+// snapshots are enabled.
+func (i *instance) restoreEdges() {
+	p := i.serviceGraphsProcessor()
+	if p == nil {
+		return
+	}
+
+	if err := p.RestoreEdges(i.edgeSnapshotPath()); err != nil {
+		level.Error(i.logger).Log("msg", "failed to restore service graph edges", "err", err)
+	}
+}
+
+// discardEdgeSnapshot removes the snapshot of the service graphs processor without restoring it,
+// if edge snapshots are enabled.
+func (i *instance) discardEdgeSnapshot() {
+	p := i.serviceGraphsProcessor()
+	if p == nil {
+		return
+	}
+
+	if err := p.DiscardEdgeSnapshot(i.edgeSnapshotPath()); err != nil {
+		level.Error(i.logger).Log("msg", "failed to discard service graph edge snapshot", "err", err)
+	}
+}
+
+// serviceGraphsProcessor returns the service graphs processor if it's active and edge snapshots
+// are enabled, nil otherwise.
+func (i *instance) serviceGraphsProcessor() *servicegraphs.Processor {
+	i.processorsMtx.RLock()
+	defer i.processorsMtx.RUnlock()
+
+	p, ok := i.processors[processor.ServiceGraphsName].(*servicegraphs.Processor)
+	if !ok || !p.Cfg.EnableEdgeSnapshots {
+		return nil
+	}
+	return p
+}
+
+func (i *instance) edgeSnapshotPath() string {
+	return filepath.Join(i.cfg.Storage.Path, edgeSnapshotsDir, i.instanceID+".json")
+}
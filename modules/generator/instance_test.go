@@ -21,9 +21,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/tempo/modules/generator/processor/criticalpath"
 	"github.com/grafana/tempo/modules/generator/processor/hostinfo"
+	"github.com/grafana/tempo/modules/generator/processor/spanevents"
 	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
+	"github.com/grafana/tempo/modules/generator/processor/traceqlmetrics"
 	"github.com/grafana/tempo/modules/generator/storage"
+	"github.com/grafana/tempo/pkg/sharedconfig"
 	"github.com/grafana/tempo/pkg/tempopb"
 	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/util/test"
@@ -138,6 +142,101 @@ tempo_metrics_generator_metrics_generation_skipped_processor_pushes_total{tenant
 	})
 }
 
+func Test_instance_edgeSnapshots(t *testing.T) {
+	overrides := &mockOverrides{}
+	overrides.processors = map[string]struct{}{
+		processor.ServiceGraphsName: {},
+	}
+	cfg := &Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+	cfg.Storage.Path = t.TempDir()
+	cfg.Processor.ServiceGraphs.EnableEdgeSnapshots = true
+
+	now := uint64(time.Now().UnixNano())
+	clientSpan := test.MakeBatch(0, nil)
+	clientSpan.ScopeSpans = []*v1.ScopeSpans{{Spans: []*v1.Span{{
+		TraceId:           []byte{0x01},
+		SpanId:            []byte{0x0a},
+		Kind:              v1.Span_SPAN_KIND_CLIENT,
+		StartTimeUnixNano: now,
+		EndTimeUnixNano:   now,
+	}}}}
+
+	i1, err := newInstance(cfg, "test", overrides, &noopStorage{}, log.NewNopLogger())
+	require.NoError(t, err)
+	i1.pushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1.ResourceSpans{clientSpan}})
+	i1.snapshotEdges()
+	i1.shutdown()
+
+	_, err = os.Stat(i1.edgeSnapshotPath())
+	require.NoError(t, err)
+
+	i2, err := newInstance(cfg, "test", overrides, &noopStorage{}, log.NewNopLogger())
+	require.NoError(t, err)
+	defer i2.shutdown()
+	i2.restoreEdges()
+
+	_, err = os.Stat(i2.edgeSnapshotPath())
+	require.True(t, os.IsNotExist(err))
+
+	// the restored edge is still waiting for its pair
+	i2.snapshotEdges()
+	_, err = os.Stat(i2.edgeSnapshotPath())
+	require.NoError(t, err)
+}
+
+func Test_instance_edgeSnapshotsPartitionRebalance(t *testing.T) {
+	overrides := &mockOverrides{}
+	overrides.processors = map[string]struct{}{
+		processor.ServiceGraphsName: {},
+	}
+	cfg := &Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+	cfg.Storage.Path = t.TempDir()
+	cfg.Processor.ServiceGraphs.EnableEdgeSnapshots = true
+
+	now := uint64(time.Now().UnixNano())
+	span := func(id, parentID []byte, kind v1.Span_SpanKind) *v1.ResourceSpans {
+		batch := test.MakeBatch(0, nil)
+		batch.ScopeSpans = []*v1.ScopeSpans{{Spans: []*v1.Span{{
+			TraceId:           []byte{0x01},
+			SpanId:            id,
+			ParentSpanId:      parentID,
+			Kind:              kind,
+			StartTimeUnixNano: now,
+			EndTimeUnixNano:   now,
+		}}}}
+		return batch
+	}
+
+	inst, err := newInstance(cfg, "test", overrides, &noopStorage{}, log.NewNopLogger())
+	require.NoError(t, err)
+	defer inst.shutdown()
+
+	g := &Generator{
+		cfg:       cfg,
+		instances: map[string]*instance{"test": inst},
+		logger:    log.NewNopLogger(),
+	}
+
+	inst.pushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1.ResourceSpans{span([]byte{0x0a}, nil, v1.Span_SPAN_KIND_CLIENT)}})
+	g.handlePartitionsRevoked(map[string][]int32{})
+	_, err = os.Stat(inst.edgeSnapshotPath())
+	require.NoError(t, err)
+
+	// the edge completes while the partitions are revoked
+	inst.pushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1.ResourceSpans{span([]byte{0x0b}, []byte{0x0a}, v1.Span_SPAN_KIND_SERVER)}})
+
+	g.handlePartitionsAssigned(map[string][]int32{})
+	_, err = os.Stat(inst.edgeSnapshotPath())
+	require.True(t, os.IsNotExist(err))
+
+	// the completed edge is not restored
+	inst.snapshotEdges()
+	_, err = os.Stat(inst.edgeSnapshotPath())
+	require.True(t, os.IsNotExist(err))
+}
+
 func Test_instance_updateProcessors(t *testing.T) {
 	cfg := Config{}
 	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
@@ -254,6 +353,66 @@ func Test_instance_updateProcessors(t *testing.T) {
 		assert.Equal(t, expectedConfig, instance.processors[processor.HostInfoName].(*hostinfo.Processor).Cfg)
 	})
 
+	t.Run("add traceql metrics processor", func(t *testing.T) {
+		overrides.processors = map[string]struct{}{
+			processor.ServiceGraphsName:  {},
+			processor.SpanMetricsName:    {},
+			processor.HostInfoName:       {},
+			processor.TraceQLMetricsName: {},
+		}
+		overrides.traceQLMetricsMetrics = []sharedconfig.TraceQLMetric{
+			{Name: "db_calls_total", Query: `{ span.db.system = "postgres" } | rate() by (span.db.name)`},
+		}
+		err := instance.updateProcessors()
+		assert.NoError(t, err)
+
+		var expectedConfig traceqlmetrics.Config
+		expectedConfig.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+		expectedConfig.Metrics = overrides.traceQLMetricsMetrics
+
+		assert.Len(t, instance.processors, 4)
+		assert.Equal(t, expectedConfig, instance.processors[processor.TraceQLMetricsName].(*traceqlmetrics.Processor).Cfg)
+	})
+
+	t.Run("add span events processor", func(t *testing.T) {
+		overrides.processors = map[string]struct{}{
+			processor.ServiceGraphsName:  {},
+			processor.SpanMetricsName:    {},
+			processor.HostInfoName:       {},
+			processor.TraceQLMetricsName: {},
+			processor.SpanEventsName:     {},
+		}
+		overrides.spanEventsDimensions = []string{"exception.escaped"}
+		err := instance.updateProcessors()
+		assert.NoError(t, err)
+
+		var expectedConfig spanevents.Config
+		expectedConfig.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+		expectedConfig.Dimensions = overrides.spanEventsDimensions
+
+		assert.Len(t, instance.processors, 5)
+		assert.Equal(t, expectedConfig, instance.processors[processor.SpanEventsName].(*spanevents.Processor).Cfg)
+	})
+
+	t.Run("add critical path processor", func(t *testing.T) {
+		overrides.processors = map[string]struct{}{
+			processor.ServiceGraphsName:  {},
+			processor.SpanMetricsName:    {},
+			processor.HostInfoName:       {},
+			processor.TraceQLMetricsName: {},
+			processor.SpanEventsName:     {},
+			processor.CriticalPathName:   {},
+		}
+		err := instance.updateProcessors()
+		assert.NoError(t, err)
+
+		var expectedConfig criticalpath.Config
+		expectedConfig.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+
+		assert.Len(t, instance.processors, 6)
+		assert.Equal(t, expectedConfig, instance.processors[processor.CriticalPathName].(*criticalpath.Processor).Cfg)
+	})
+
 	t.Run("remove processor", func(t *testing.T) {
 		overrides.processors = nil
 		err := instance.updateProcessors()
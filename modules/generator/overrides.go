@@ -36,10 +36,19 @@ type metricsGeneratorOverrides interface {
 	MetricsGeneratorProcessorSpanMetricsEnableInstanceLabel(userID string) (bool, bool)
 	MetricsGeneratorProcessorHostInfoHostIdentifiers(userID string) []string
 	MetricsGeneratorProcessorHostInfoMetricName(userID string) string
+	MetricsGeneratorProcessorTraceQLMetricsMetrics(userID string) []sharedconfig.TraceQLMetric
+	MetricsGeneratorProcessorTraceQLMetricsHistogramBuckets(userID string) []float64
+	MetricsGeneratorProcessorSpanEventsDimensions(userID string) []string
+	MetricsGeneratorProcessorSpanEventsFilterPolicies(userID string) []filterconfig.FilterPolicy
+	MetricsGeneratorProcessorSpanEventsDimensionMappings(userID string) []sharedconfig.DimensionMappings
 	MetricsGeneratorProcessorServiceGraphsSpanMultiplierKey(userID string) string
 	MetricsGeneratorProcessorServiceGraphsEnableTraceStateSpanMultiplier(userID string) (bool, bool)
 	MetricsGeneratorProcessorSpanMetricsSpanMultiplierKey(userID string) string
 	MetricsGeneratorProcessorSpanMetricsEnableTraceStateSpanMultiplier(userID string) (bool, bool)
+	MetricsGeneratorProcessorSpanMetricsRootDimensions(userID string) []string
+	MetricsGeneratorProcessorCriticalPathWait(userID string) time.Duration
+	MetricsGeneratorProcessorCriticalPathMaxItems(userID string) int
+	MetricsGeneratorProcessorCriticalPathMaxSpansPerTrace(userID string) int
 	DedicatedColumns(userID string) backend.DedicatedColumns
 	MaxLocalTracesPerUser(userID string) int
 	MaxBytesPerTrace(userID string) int
@@ -36,10 +36,20 @@ type mockOverrides struct {
 	nativeHistograms                                   histograms.HistogramMethod
 	hostInfoHostIdentifiers                            []string
 	hostInfoMetricName                                 string
+	traceQLMetricsMetrics                              []sharedconfig.TraceQLMetric
+	traceQLMetricsHistogramBuckets                     []float64
+	spanEventsDimensions                               []string
+	spanEventsFilterPolicies                           []filterconfig.FilterPolicy
+	spanEventsDimensionMappings                        []sharedconfig.DimensionMappings
 	serviceGraphsSpanMultiplierKey                     string
 	serviceGraphsEnableTraceStateSpanMultiplier        *bool
 	spanMetricsSpanMultiplierKey                       string
 	spanMetricsEnableTraceStateSpanMultiplier          *bool
+	spanMetricsRootDimensions                          []string
+	criticalPathWait                                   time.Duration
+	criticalPathMaxItems                               int
+	criticalPathMaxSpansPerTrace                       int
+	spanNameSanitizationRules                          []sharedconfig.SpanNameSanitizationRule
 }
 
 var _ metricsGeneratorOverrides = (*mockOverrides)(nil)
@@ -80,6 +90,14 @@ func (m *mockOverrides) MetricsGeneratorRemoteWriteHeaders(string) map[string]st
 	return nil
 }
 
+func (m *mockOverrides) MetricsGeneratorOTLPExportEndpoint(string) string {
+	return ""
+}
+
+func (m *mockOverrides) MetricsGeneratorOTLPExportHeaders(string) map[string]string {
+	return nil
+}
+
 func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsHistogramBuckets(string) []float64 {
 	return m.serviceGraphsHistogramBuckets
 }
@@ -133,10 +151,18 @@ func (m *mockOverrides) MetricsGeneratorSpanNameSanitization(string) string {
 	return ""
 }
 
+func (m *mockOverrides) MetricsGeneratorSpanNameSanitizationRules(string) []sharedconfig.SpanNameSanitizationRule {
+	return m.spanNameSanitizationRules
+}
+
 func (m *mockOverrides) MetricsGeneratorMaxCardinalityPerLabel(string) uint64 {
 	return 0
 }
 
+func (m *mockOverrides) MetricsGeneratorMaxCardinalityPerLabelMode(string) string {
+	return ""
+}
+
 // MetricsGeneratorProcessorSpanMetricsEnableTargetInfo enables target_info metrics
 func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsEnableTargetInfo(string) (bool, bool) {
 	spanMetricsEnableTargetInfo := m.spanMetricsEnableTargetInfo
@@ -202,6 +228,26 @@ func (m *mockOverrides) MetricsGeneratorProcessorHostInfoMetricName(string) stri
 	return m.hostInfoMetricName
 }
 
+func (m *mockOverrides) MetricsGeneratorProcessorTraceQLMetricsMetrics(string) []sharedconfig.TraceQLMetric {
+	return m.traceQLMetricsMetrics
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorTraceQLMetricsHistogramBuckets(string) []float64 {
+	return m.traceQLMetricsHistogramBuckets
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorSpanEventsDimensions(string) []string {
+	return m.spanEventsDimensions
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorSpanEventsFilterPolicies(string) []filterconfig.FilterPolicy {
+	return m.spanEventsFilterPolicies
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorSpanEventsDimensionMappings(string) []sharedconfig.DimensionMappings {
+	return m.spanEventsDimensionMappings
+}
+
 func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsSpanMultiplierKey(string) string {
 	return m.serviceGraphsSpanMultiplierKey
 }
@@ -223,3 +269,19 @@ func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsEnableTraceStateSpan
 	}
 	return false, false
 }
+
+func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsRootDimensions(string) []string {
+	return m.spanMetricsRootDimensions
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorCriticalPathWait(string) time.Duration {
+	return m.criticalPathWait
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorCriticalPathMaxItems(string) int {
+	return m.criticalPathMaxItems
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorCriticalPathMaxSpansPerTrace(string) int {
+	return m.criticalPathMaxSpansPerTrace
+}
@@ -0,0 +1,33 @@
+package criticalpath
+
+import (
+	"flag"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/tempo/modules/generator/registry"
+)
+
+type Config struct {
+	// Wait is the time to wait for new spans of a trace before it's considered complete
+	Wait time.Duration `yaml:"wait"`
+	// MaxItems is the amount of traces that will be buffered
+	MaxItems int `yaml:"max_items"`
+	// MaxSpansPerTrace is the amount of spans that will be buffered per trace
+	MaxSpansPerTrace int `yaml:"max_spans_per_trace"`
+
+	// Buckets for the self time and critical path histograms in seconds.
+	HistogramBuckets []float64 `yaml:"histogram_buckets"`
+
+	// The histogram mode to select.
+	HistogramOverride registry.HistogramMode `yaml:"-"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
+	cfg.Wait = 10 * time.Second
+	cfg.MaxItems = 10_000
+	cfg.MaxSpansPerTrace = 1_000
+	cfg.HistogramBuckets = prometheus.ExponentialBuckets(0.002, 2, 14)
+	cfg.HistogramOverride = registry.HistogramModeClassic
+}
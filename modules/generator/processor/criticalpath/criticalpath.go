@@ -0,0 +1,265 @@
+package criticalpath
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	gen "github.com/grafana/tempo/modules/generator/processor"
+	processor_util "github.com/grafana/tempo/modules/generator/processor/util"
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/tempopb"
+	tempo_util "github.com/grafana/tempo/pkg/util"
+)
+
+var (
+	metricDroppedSpans = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_processor_critical_path_dropped_spans",
+		Help:      "Number of spans dropped because the maximum number of traces or spans per trace was reached",
+	}, []string{"tenant"})
+	metricCompletedTraces = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_processor_critical_path_completed_traces",
+		Help:      "Number of traces for which the self time and critical path were computed",
+	}, []string{"tenant"})
+)
+
+const (
+	metricSpanSelfTimeSeconds  = "traces_span_self_time_seconds"
+	metricCriticalPathSeconds  = "traces_critical_path_seconds"
+	expirationIntervalDuration = 2 * time.Second
+)
+
+// Processor buffers the spans of a trace until no new spans have been received for the configured
+// wait time. Once the trace is complete, it computes the self time of every span, the duration of
+// the span minus the time covered by its children, and the time every service and operation
+// spends on the critical path of the trace.
+type Processor struct {
+	Cfg Config
+
+	registry registry.Registry
+	store    *store
+
+	spanSelfTimeHistogram registry.Histogram
+	criticalPathHistogram registry.Histogram
+	invalidUTF8Counter    prometheus.Counter
+	metricDroppedSpans    prometheus.Counter
+	metricCompletedTraces prometheus.Counter
+	closeCh               chan struct{}
+	logger                log.Logger
+}
+
+func New(cfg Config, tenant string, reg registry.Registry, logger log.Logger, invalidUTF8Counter prometheus.Counter) (gen.Processor, error) {
+	p := &Processor{
+		Cfg:      cfg,
+		registry: reg,
+		closeCh:  make(chan struct{}),
+
+		spanSelfTimeHistogram: reg.NewHistogram(metricSpanSelfTimeSeconds, cfg.HistogramBuckets, cfg.HistogramOverride),
+		criticalPathHistogram: reg.NewHistogram(metricCriticalPathSeconds, cfg.HistogramBuckets, cfg.HistogramOverride),
+		invalidUTF8Counter:    invalidUTF8Counter,
+		metricDroppedSpans:    metricDroppedSpans.WithLabelValues(tenant),
+		metricCompletedTraces: metricCompletedTraces.WithLabelValues(tenant),
+		logger:                log.With(logger, "component", "critical-path"),
+	}
+
+	p.store = newStore(cfg.Wait, cfg.MaxItems, cfg.MaxSpansPerTrace, p.onComplete)
+
+	go func() {
+		ticker := time.NewTicker(expirationIntervalDuration)
+		defer ticker.Stop()
+
+		for {
+			select {
+			// Periodically complete the traces that didn't receive new spans
+			case <-ticker.C:
+				p.store.Expire()
+
+			case <-p.closeCh:
+				return
+			}
+		}
+	}()
+
+	return p, nil
+}
+
+func (p *Processor) Name() string {
+	return gen.CriticalPathName
+}
+
+func (p *Processor) PushSpans(_ context.Context, req *tempopb.PushSpansRequest) {
+	traces := make(map[string][]span)
+
+	for _, rs := range req.Batches {
+		svcName, _ := processor_util.FindServiceName(rs.Resource.Attributes)
+		for _, ils := range rs.ScopeSpans {
+			for _, s := range ils.Spans {
+				traceID := tempo_util.TraceIDToHexString(s.TraceId)
+				traces[traceID] = append(traces[traceID], span{
+					id:       string(s.SpanId),
+					parentID: string(s.ParentSpanId),
+					service:  svcName,
+					name:     s.Name,
+					start:    s.StartTimeUnixNano,
+					end:      s.EndTimeUnixNano,
+				})
+			}
+		}
+	}
+
+	totalDroppedSpans := 0
+	for traceID, spans := range traces {
+		totalDroppedSpans += p.store.addSpans(traceID, spans)
+	}
+
+	if totalDroppedSpans > 0 {
+		p.metricDroppedSpans.Add(float64(totalDroppedSpans))
+		level.Warn(p.logger).Log("msg", "skipped processing of spans", "maxItems", p.Cfg.MaxItems, "maxSpansPerTrace", p.Cfg.MaxSpansPerTrace, "droppedSpans", totalDroppedSpans)
+	}
+}
+
+// Shutdown stops the expiration loop and completes the buffered traces, so their metrics are
+// still collected.
+func (p *Processor) Shutdown(_ context.Context) {
+	close(p.closeCh)
+	p.store.Flush()
+}
+
+type operation struct {
+	service string
+	name    string
+}
+
+// node is a span in the tree of a trace.
+type node struct {
+	*span
+	children []*node
+}
+
+func (p *Processor) onComplete(t *trace) {
+	p.metricCompletedTraces.Inc()
+
+	nodes := make(map[string]*node, len(t.spans))
+	ordered := make([]*node, 0, len(t.spans))
+	for i := range t.spans {
+		s := &t.spans[i]
+		// keep the first span if a span id is duplicated
+		if _, ok := nodes[s.id]; ok {
+			continue
+		}
+		n := &node{span: s}
+		nodes[s.id] = n
+		ordered = append(ordered, n)
+	}
+
+	// spans whose parent is missing are treated as roots
+	var roots []*node
+	for _, n := range ordered {
+		parent, ok := nodes[n.parentID]
+		if n.parentID == "" || !ok || parent == n {
+			roots = append(roots, n)
+			continue
+		}
+		parent.children = append(parent.children, n)
+	}
+
+	for _, n := range ordered {
+		p.observe(p.spanSelfTimeHistogram, n.service, n.name, selfTime(n), t.id)
+	}
+
+	criticalPath := make(map[operation]uint64)
+	for _, root := range roots {
+		walkCriticalPath(root, root.end, criticalPath)
+	}
+	for op, d := range criticalPath {
+		p.observe(p.criticalPathHistogram, op.service, op.name, d, t.id)
+	}
+}
+
+func (p *Processor) observe(h registry.Histogram, service, name string, nanos uint64, traceID string) {
+	builder := p.registry.NewLabelBuilder()
+	builder.Add(gen.DimService, service)
+	builder.Add(gen.DimSpanName, name)
+
+	lbls, validUTF8 := builder.CloseAndBuildLabels()
+	if !validUTF8 {
+		p.invalidUTF8Counter.Inc()
+		return
+	}
+
+	h.ObserveWithExemplar(lbls, float64(nanos)/float64(time.Second.Nanoseconds()), traceID, 1)
+}
+
+// selfTime returns the duration of the span that is not covered by any of its children. Children
+// are clipped to the span, time outside of the span doesn't count.
+func selfTime(n *node) uint64 {
+	if n.end <= n.start {
+		return 0
+	}
+
+	type interval struct{ start, end uint64 }
+	intervals := make([]interval, 0, len(n.children))
+	for _, c := range n.children {
+		start, end := max(c.start, n.start), min(c.end, n.end)
+		if start < end {
+			intervals = append(intervals, interval{start, end})
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].start < intervals[j].start
+	})
+
+	var covered, cur uint64
+	for _, i := range intervals {
+		if i.end <= cur {
+			continue
+		}
+		covered += i.end - max(i.start, cur)
+		cur = i.end
+	}
+
+	return n.end - n.start - covered
+}
+
+// walkCriticalPath adds the time each operation spends on the critical path of the subtree rooted
+// at n to criticalPath. The critical path is built backwards from end: the child that finished last
+// is on the critical path, and the parent waits for it. Before that child started, the child that
+// finished last before its start is on the critical path, and so on. The gaps in between belong to
+// the parent.
+func walkCriticalPath(n *node, end uint64, criticalPath map[operation]uint64) {
+	cur := min(end, n.end)
+	if cur <= n.start {
+		return
+	}
+
+	sort.Slice(n.children, func(i, j int) bool {
+		return n.children[i].end > n.children[j].end
+	})
+
+	op := operation{service: n.service, name: n.name}
+	for _, c := range n.children {
+		if cur <= n.start {
+			break
+		}
+		// the child started after the time we are looking at or ended before the parent started
+		if c.start >= cur || c.end <= n.start {
+			continue
+		}
+
+		childEnd := min(c.end, cur)
+		criticalPath[op] += cur - childEnd
+		walkCriticalPath(c, childEnd, criticalPath)
+		cur = max(c.start, n.start)
+	}
+
+	if cur > n.start {
+		criticalPath[op] += cur - n.start
+	}
+}
@@ -0,0 +1,172 @@
+package criticalpath
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gen "github.com/grafana/tempo/modules/generator/processor"
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestCriticalPath(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Wait = 0
+
+	p, err := New(cfg, "test", testRegistry, log.NewNopLogger(), prometheus.NewCounter(prometheus.CounterOpts{}))
+	require.NoError(t, err)
+	require.Equal(t, gen.CriticalPathName, p.Name())
+	defer p.Shutdown(context.Background())
+
+	// frontend  [0ms ---------------------------------------- 100ms]
+	// api         [10ms --------------- 60ms]
+	// cache            [20ms -- 40ms]
+	// db                                       [70ms -- 90ms]
+	// The spans of a trace can be pushed in separate requests.
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1_trace.ResourceSpans{
+		batch("frontend", testSpan(1, 0, "GET /", 0, 100)),
+		batch("api", testSpan(2, 1, "get-user", 10, 60)),
+	}})
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1_trace.ResourceSpans{
+		batch("cache", testSpan(3, 1, "lookup", 20, 40)),
+		batch("db", testSpan(4, 1, "select", 70, 90)),
+	}})
+
+	p.(*Processor).store.Expire()
+	assert.Equal(t, 0, p.(*Processor).store.len())
+
+	lbls := func(service, name string) labels.Labels {
+		return labels.FromStrings("service", service, "span_name", name)
+	}
+
+	// the self time of the frontend doesn't include the time of the overlapping api and cache spans
+	assert.InDelta(t, 0.030, testRegistry.Query("traces_span_self_time_seconds_sum", lbls("frontend", "GET /")), 1e-9)
+	assert.InDelta(t, 0.050, testRegistry.Query("traces_span_self_time_seconds_sum", lbls("api", "get-user")), 1e-9)
+	assert.InDelta(t, 0.020, testRegistry.Query("traces_span_self_time_seconds_sum", lbls("cache", "lookup")), 1e-9)
+	assert.InDelta(t, 0.020, testRegistry.Query("traces_span_self_time_seconds_sum", lbls("db", "select")), 1e-9)
+
+	// the cache call runs in parallel with the api call and is not on the critical path
+	assert.InDelta(t, 0.030, testRegistry.Query("traces_critical_path_seconds_sum", lbls("frontend", "GET /")), 1e-9)
+	assert.InDelta(t, 0.050, testRegistry.Query("traces_critical_path_seconds_sum", lbls("api", "get-user")), 1e-9)
+	assert.InDelta(t, 0.020, testRegistry.Query("traces_critical_path_seconds_sum", lbls("db", "select")), 1e-9)
+	assert.Equal(t, 0.0, testRegistry.Query("traces_critical_path_seconds_count", lbls("cache", "lookup")))
+}
+
+func TestCriticalPath_asyncChild(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Wait = 0
+
+	p, err := New(cfg, "test", testRegistry, log.NewNopLogger(), prometheus.NewCounter(prometheus.CounterOpts{}))
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	// the child outlives its parent, only the part within the parent counts
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1_trace.ResourceSpans{
+		batch("api", testSpan(1, 0, "handle", 0, 50), testSpan(2, 1, "send-email", 40, 200)),
+	}})
+	p.(*Processor).store.Expire()
+
+	lbls := func(name string) labels.Labels {
+		return labels.FromStrings("service", "api", "span_name", name)
+	}
+
+	assert.InDelta(t, 0.040, testRegistry.Query("traces_span_self_time_seconds_sum", lbls("handle")), 1e-9)
+	assert.InDelta(t, 0.160, testRegistry.Query("traces_span_self_time_seconds_sum", lbls("send-email")), 1e-9)
+	assert.InDelta(t, 0.040, testRegistry.Query("traces_critical_path_seconds_sum", lbls("handle")), 1e-9)
+	assert.InDelta(t, 0.010, testRegistry.Query("traces_critical_path_seconds_sum", lbls("send-email")), 1e-9)
+}
+
+func TestCriticalPath_waitAndLimits(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Wait = time.Hour
+	cfg.MaxItems = 1
+	cfg.MaxSpansPerTrace = 2
+
+	p, err := New(cfg, "test-limits", testRegistry, log.NewNopLogger(), prometheus.NewCounter(prometheus.CounterOpts{}))
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1_trace.ResourceSpans{
+		batch("api", testSpan(1, 0, "a", 0, 10), testSpan(2, 1, "b", 0, 10), testSpan(3, 1, "c", 0, 10)),
+	}})
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1_trace.ResourceSpans{
+		batch("api", &v1_trace.Span{TraceId: []byte{0x02}, SpanId: []byte{0x01}, Name: "other-trace"}),
+	}})
+
+	// the trace is not complete until no spans have been received for the wait time
+	p.(*Processor).store.Expire()
+	assert.Equal(t, 1, p.(*Processor).store.len())
+	assert.Equal(t, 0.0, testRegistry.Query("traces_span_self_time_seconds_count", labels.FromStrings("service", "api", "span_name", "a")))
+
+	// one span exceeded the spans per trace, one the number of traces
+	assert.Equal(t, 2.0, testutil.ToFloat64(p.(*Processor).metricDroppedSpans))
+}
+
+func TestCriticalPath_shutdownFlushesTraces(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Wait = time.Hour
+
+	p, err := New(cfg, "test", testRegistry, log.NewNopLogger(), prometheus.NewCounter(prometheus.CounterOpts{}))
+	require.NoError(t, err)
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1_trace.ResourceSpans{
+		batch("api", testSpan(1, 0, "handle", 0, 50)),
+	}})
+	assert.Equal(t, 1, p.(*Processor).store.len())
+
+	p.Shutdown(context.Background())
+
+	assert.Equal(t, 0, p.(*Processor).store.len())
+	assert.Equal(t, 1.0, testRegistry.Query("traces_span_self_time_seconds_count", labels.FromStrings("service", "api", "span_name", "handle")))
+}
+
+func batch(service string, spans ...*v1_trace.Span) *v1_trace.ResourceSpans {
+	return &v1_trace.ResourceSpans{
+		Resource: &v1_resource.Resource{
+			Attributes: []*v1_common.KeyValue{{
+				Key:   "service.name",
+				Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: service}},
+			}},
+		},
+		ScopeSpans: []*v1_trace.ScopeSpans{{Spans: spans}},
+	}
+}
+
+// testSpan returns a span of the trace 0x01. A parent id of 0 means it's the root span, start and
+// end are in milliseconds.
+func testSpan(id, parentID byte, name string, start, end uint64) *v1_trace.Span {
+	s := &v1_trace.Span{
+		TraceId:           []byte{0x01},
+		SpanId:            []byte{id},
+		Name:              name,
+		StartTimeUnixNano: start * uint64(time.Millisecond),
+		EndTimeUnixNano:   end * uint64(time.Millisecond),
+	}
+	if parentID != 0 {
+		s.ParentSpanId = []byte{parentID}
+	}
+	return s
+}
@@ -0,0 +1,131 @@
+package criticalpath
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	servicegraphs_store "github.com/grafana/tempo/modules/generator/processor/servicegraphs/store"
+)
+
+// span holds the fields of a span needed to compute its self time and critical path.
+type span struct {
+	id       string
+	parentID string
+	service  string
+	name     string
+	start    uint64
+	end      uint64
+}
+
+// trace is a buffered trace. It expires once no spans have been added for the ttl of the store.
+type trace struct {
+	id         string
+	spans      []span
+	expiration int64
+}
+
+// store buffers the spans of traces until they are complete. It uses the wait/expire mechanism of
+// the service graphs store, but the expiration of a trace is extended every time a span is added.
+// A trace is considered complete once it expires.
+type store struct {
+	traces *servicegraphs_store.ExpiringList[*trace]
+	mtx    sync.Mutex
+
+	onComplete func(t *trace)
+
+	ttl              time.Duration
+	maxItems         int
+	maxSpansPerTrace int
+}
+
+func newStore(ttl time.Duration, maxItems, maxSpansPerTrace int, onComplete func(t *trace)) *store {
+	return &store{
+		traces: servicegraphs_store.NewExpiringList(func(t *trace) int64 { return t.expiration }),
+
+		onComplete: onComplete,
+
+		ttl:              ttl,
+		maxItems:         maxItems,
+		maxSpansPerTrace: maxSpansPerTrace,
+	}
+}
+
+func (s *store) len() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.traces.Len()
+}
+
+// addSpans adds the spans to the trace with the given id and extends its expiration. Returns the
+// number of spans that were dropped.
+func (s *store) addSpans(traceID string, spans []span) int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	expiration := time.Now().Add(s.ttl).UnixNano()
+
+	if t, ok := s.traces.Get(traceID); ok {
+		t.expiration = expiration
+		// push the trace again to keep the list sorted by expiration
+		s.traces.Push(traceID, t)
+		return s.appendSpans(t, spans)
+	}
+
+	if s.traces.Len() >= s.maxItems {
+		return len(spans)
+	}
+
+	t := &trace{
+		id:         traceID,
+		expiration: expiration,
+	}
+	s.traces.Push(traceID, t)
+
+	return s.appendSpans(t, spans)
+}
+
+// appendSpans appends as many spans to the trace as allowed. Must be called holding lock.
+func (s *store) appendSpans(t *trace, spans []span) int {
+	available := s.maxSpansPerTrace - len(t.spans)
+	if available <= 0 {
+		return len(spans)
+	}
+	if len(spans) > available {
+		t.spans = append(t.spans, spans[:available]...)
+		return len(spans) - available
+	}
+
+	t.spans = append(t.spans, spans...)
+	return 0
+}
+
+// Expire removes all expired traces from the store and completes them. The traces are completed
+// without holding the lock, so spans can be added while they are processed.
+func (s *store) Expire() {
+	s.complete(time.Now().UnixNano())
+}
+
+// Flush removes all traces from the store and completes them, whether they expired or not.
+func (s *store) Flush() {
+	s.complete(math.MaxInt64)
+}
+
+func (s *store) complete(now int64) {
+	var expired []*trace
+
+	s.mtx.Lock()
+	for {
+		t, ok := s.traces.PopExpired(now)
+		if !ok {
+			break
+		}
+		expired = append(expired, t)
+	}
+	s.mtx.Unlock()
+
+	for _, t := range expired {
+		s.onComplete(t)
+	}
+}
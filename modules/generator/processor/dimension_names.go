@@ -8,4 +8,6 @@ const (
 	DimStatusMessage = "status_message"
 	DimJob           = "job"
 	DimInstance      = "instance"
+	DimEventName     = "event_name"
+	DimExceptionType = "exception_type"
 )
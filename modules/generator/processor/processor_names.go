@@ -9,7 +9,10 @@ const (
 )
 
 const (
-	SpanMetricsName   = "span-metrics"
-	ServiceGraphsName = "service-graphs"
-	HostInfoName      = "host-info"
+	SpanMetricsName    = "span-metrics"
+	ServiceGraphsName  = "service-graphs"
+	HostInfoName       = "host-info"
+	TraceQLMetricsName = "traceql-metrics"
+	SpanEventsName     = "span-events"
+	CriticalPathName   = "critical-path"
 )
@@ -58,6 +58,23 @@ type Config struct {
 
 	// FilterPolicies is a list of policies that will be applied to spans for inclusion or exclusion.
 	FilterPolicies []filterconfig.FilterPolicy `yaml:"filter_policies"`
+
+	// If enabled, edges are also built between producer spans and the consumer spans that link to
+	// them. Consumers often start a new trace that is only connected to the producer by a span link.
+	EnableMessagingLinks bool `yaml:"enable_messaging_links"`
+
+	// MessagingLinksWait is the value to wait for an edge built from a span link to be completed.
+	// Consumers can process messages long after they were produced, so it's usually larger than Wait.
+	MessagingLinksWait time.Duration `yaml:"messaging_links_wait"`
+
+	// MessagingLinksMaxItems is the amount of edges built from span links that will be stored. Every
+	// producer span waits for a linked consumer, so these edges don't count towards MaxItems.
+	MessagingLinksMaxItems int `yaml:"messaging_links_max_items"`
+
+	// If enabled, the edges waiting for their pair are snapshotted to the storage path of the
+	// metrics-generator on shutdown and when Kafka partitions are revoked, and restored on startup
+	// and when partitions are assigned.
+	EnableEdgeSnapshots bool `yaml:"enable_edge_snapshots"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
@@ -75,6 +92,8 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 	cfg.PeerAttributes = peerAttr
 
 	cfg.EnableMessagingSystemLatencyHistogram = false
+	cfg.MessagingLinksWait = time.Minute
+	cfg.MessagingLinksMaxItems = 10_000
 
 	cfg.DatabaseNameAttributes = []string{
 		string(semconvnew.DBNamespaceKey),
@@ -1,6 +1,7 @@
 package servicegraphs
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"errors"
@@ -54,6 +55,16 @@ var (
 		Name:      "metrics_generator_processor_service_graphs_expired_edges",
 		Help:      "Number of edges that expired before finding its matching span",
 	}, []string{"tenant"})
+	metricDroppedLinkSpans = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_processor_service_graphs_dropped_messaging_link_spans",
+		Help:      "Number of spans dropped when trying to add edges built from span links",
+	}, []string{"tenant"})
+	metricLinkEdges = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_processor_service_graphs_messaging_link_edges",
+		Help:      "Total number of completed edges built from span links",
+	}, []string{"tenant"})
 )
 
 const (
@@ -83,6 +94,9 @@ type Processor struct {
 
 	registry registry.Registry
 	store    store.Store
+	// linkStore holds the edges between producers and consumers connected by span links. It's only
+	// set if messaging links are enabled.
+	linkStore store.Store
 
 	closeCh chan struct{}
 
@@ -100,6 +114,10 @@ type Processor struct {
 	metricDroppedSpanSideCacheOverflows prometheus.Counter
 	metricTotalEdges                    prometheus.Counter
 	metricExpiredEdges                  prometheus.Counter
+	metricRestoredEdges                 prometheus.Counter
+	metricDroppedRestoredEdges          prometheus.Counter
+	metricDroppedLinkSpans              prometheus.Counter
+	metricLinkEdges                     prometheus.Counter
 	invalidUTF8Counter                  prometheus.Counter
 	logger                              log.Logger
 }
@@ -134,11 +152,18 @@ func New(cfg Config, tenant string, reg registry.Registry, logger log.Logger, fi
 		metricDroppedSpanSideCacheOverflows: metricDroppedSpanSideCacheOverflows.WithLabelValues(tenant),
 		metricTotalEdges:                    metricTotalEdges.WithLabelValues(tenant),
 		metricExpiredEdges:                  metricExpiredEdges.WithLabelValues(tenant),
+		metricRestoredEdges:                 metricRestoredEdges.WithLabelValues(tenant),
+		metricDroppedRestoredEdges:          metricDroppedRestoredEdges.WithLabelValues(tenant),
+		metricDroppedLinkSpans:              metricDroppedLinkSpans.WithLabelValues(tenant),
+		metricLinkEdges:                     metricLinkEdges.WithLabelValues(tenant),
 		invalidUTF8Counter:                  invalidUTF8Counter,
 		logger:                              log.With(logger, "component", "service-graphs"),
 	}
 
 	p.store = store.NewStore(cfg.Wait, cfg.MaxItems, p.onComplete, p.onExpire, p.metricDroppedSpanSideCacheOverflows)
+	if cfg.EnableMessagingLinks {
+		p.linkStore = store.NewStore(cfg.MessagingLinksWait, cfg.MessagingLinksMaxItems, p.onLinkComplete, p.onLinkExpire, p.metricDroppedSpanSideCacheOverflows)
+	}
 
 	expirationTicker := time.NewTicker(2 * time.Second)
 	for i := 0; i < cfg.Workers; i++ {
@@ -148,6 +173,9 @@ func New(cfg Config, tenant string, reg registry.Registry, logger log.Logger, fi
 				// Periodically clean expired edges from the store
 				case <-expirationTicker.C:
 					p.store.Expire()
+					if p.linkStore != nil {
+						p.linkStore.Expire()
+					}
 
 				case <-p.closeCh:
 					return
@@ -209,15 +237,7 @@ func (p *Processor) consume(resourceSpans []*v1_trace.ResourceSpans) (err error)
 				case v1_trace.Span_SPAN_KIND_CLIENT:
 					key := buildKey(hex.EncodeToString(span.TraceId), hex.EncodeToString(span.SpanId))
 					isNew, err = p.store.UpsertEdge(key, store.Client, func(e *store.Edge) {
-						e.TraceID = tempo_util.TraceIDToHexString(span.TraceId)
-						e.ConnectionType = connectionType
-						e.ClientService = svcName
-						e.ClientLatencySec = spanDurationSec(span)
-						e.ClientEndTimeUnixNano = span.EndTimeUnixNano
-						e.Failed = e.Failed || p.spanFailed(span)
-						p.upsertDimensions("client_", e.Dimensions, rs.Resource.Attributes, span.Attributes)
-						e.SpanMultiplier = spanMultiplier
-						p.upsertPeerNode(e, span.Attributes)
+						p.updateClientEdge(e, connectionType, svcName, rs.Resource.Attributes, span, spanMultiplier)
 						p.upsertDatabaseRequest(e, rs.Resource.Attributes, span)
 					})
 
@@ -228,32 +248,18 @@ func (p *Processor) consume(resourceSpans []*v1_trace.ResourceSpans) (err error)
 				case v1_trace.Span_SPAN_KIND_SERVER:
 					key := buildKey(hex.EncodeToString(span.TraceId), hex.EncodeToString(span.ParentSpanId))
 					isNew, err = p.store.UpsertEdge(key, store.Server, func(e *store.Edge) {
-						e.TraceID = tempo_util.TraceIDToHexString(span.TraceId)
-						e.ConnectionType = connectionType
-						e.ServerService = svcName
-						e.ServerLatencySec = spanDurationSec(span)
-						e.ServerStartTimeUnixNano = span.StartTimeUnixNano
-						e.Failed = e.Failed || p.spanFailed(span)
-						p.upsertDimensions("server_", e.Dimensions, rs.Resource.Attributes, span.Attributes)
-						e.SpanMultiplier = spanMultiplier
-						p.upsertPeerNode(e, span.Attributes)
+						p.updateServerEdge(e, connectionType, svcName, rs.Resource.Attributes, span, spanMultiplier)
 					})
 				}
 
-				switch {
-				case errors.Is(err, store.ErrTooManyItems):
-					totalDroppedSpans++
-					p.metricDroppedSpans.Inc()
-					continue
-				case errors.Is(err, store.ErrDroppedSpanSide):
-					p.metricDroppedEdges.Inc()
-					continue
-				case err != nil:
+				if err = p.handleUpsert(isNew, err, &totalDroppedSpans); err != nil {
 					return err
 				}
 
-				if isNew {
-					p.metricTotalEdges.Inc()
+				if p.linkStore != nil {
+					if err = p.consumeLinks(svcName, rs.Resource.Attributes, span, spanMultiplier); err != nil {
+						return err
+					}
 				}
 			}
 		}
@@ -268,6 +274,92 @@ func (p *Processor) consume(resourceSpans []*v1_trace.ResourceSpans) (err error)
 	return nil
 }
 
+// consumeLinks upserts the edges between producer spans and the consumer spans that link to them.
+// Links to the parent of the consumer span are skipped, these edges are already built from the
+// parent/child relationship. Every producer span waits in the link store, so the store has its own
+// limit and the edges are only counted once they are completed.
+func (p *Processor) consumeLinks(svcName string, resourceAttr []*v1_common.KeyValue, span *v1_trace.Span, spanMultiplier float64) error {
+	switch span.Kind {
+	case v1_trace.Span_SPAN_KIND_PRODUCER:
+		key := buildKey(hex.EncodeToString(span.TraceId), hex.EncodeToString(span.SpanId))
+		_, err := p.linkStore.UpsertEdge(key, store.Client, func(e *store.Edge) {
+			p.updateClientEdge(e, store.MessagingLink, svcName, resourceAttr, span, spanMultiplier)
+		})
+		return p.handleLinkUpsert(err)
+
+	case v1_trace.Span_SPAN_KIND_CONSUMER:
+		for _, link := range span.Links {
+			if bytes.Equal(link.TraceId, span.TraceId) && bytes.Equal(link.SpanId, span.ParentSpanId) {
+				continue
+			}
+
+			key := buildKey(hex.EncodeToString(link.TraceId), hex.EncodeToString(link.SpanId))
+			_, err := p.linkStore.UpsertEdge(key, store.Server, func(e *store.Edge) {
+				p.updateServerEdge(e, store.MessagingLink, svcName, resourceAttr, span, spanMultiplier)
+			})
+			if err := p.handleLinkUpsert(err); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// handleUpsert updates the metrics with the result of UpsertEdge. Only unexpected errors are
+// returned.
+func (p *Processor) handleUpsert(isNew bool, err error, totalDroppedSpans *int) error {
+	switch {
+	case errors.Is(err, store.ErrTooManyItems):
+		*totalDroppedSpans++
+		p.metricDroppedSpans.Inc()
+		return nil
+	case errors.Is(err, store.ErrDroppedSpanSide):
+		p.metricDroppedEdges.Inc()
+		return nil
+	case err != nil:
+		return err
+	}
+
+	if isNew {
+		p.metricTotalEdges.Inc()
+	}
+	return nil
+}
+
+// handleLinkUpsert updates the metrics with the result of UpsertEdge on the link store. Only
+// unexpected errors are returned.
+func (p *Processor) handleLinkUpsert(err error) error {
+	if errors.Is(err, store.ErrTooManyItems) {
+		p.metricDroppedLinkSpans.Inc()
+		return nil
+	}
+	return err
+}
+
+func (p *Processor) updateClientEdge(e *store.Edge, connectionType store.ConnectionType, svcName string, resourceAttr []*v1_common.KeyValue, span *v1_trace.Span, spanMultiplier float64) {
+	e.TraceID = tempo_util.TraceIDToHexString(span.TraceId)
+	e.ConnectionType = connectionType
+	e.ClientService = svcName
+	e.ClientLatencySec = spanDurationSec(span)
+	e.ClientEndTimeUnixNano = span.EndTimeUnixNano
+	e.Failed = e.Failed || p.spanFailed(span)
+	p.upsertDimensions("client_", e.Dimensions, resourceAttr, span.Attributes)
+	e.SpanMultiplier = spanMultiplier
+	p.upsertPeerNode(e, span.Attributes)
+}
+
+func (p *Processor) updateServerEdge(e *store.Edge, connectionType store.ConnectionType, svcName string, resourceAttr []*v1_common.KeyValue, span *v1_trace.Span, spanMultiplier float64) {
+	e.TraceID = tempo_util.TraceIDToHexString(span.TraceId)
+	e.ConnectionType = connectionType
+	e.ServerService = svcName
+	e.ServerLatencySec = spanDurationSec(span)
+	e.ServerStartTimeUnixNano = span.StartTimeUnixNano
+	e.Failed = e.Failed || p.spanFailed(span)
+	p.upsertDimensions("server_", e.Dimensions, resourceAttr, span.Attributes)
+	e.SpanMultiplier = spanMultiplier
+	p.upsertPeerNode(e, span.Attributes)
+}
+
 func (p *Processor) upsertDimensions(prefix string, m map[string]string, resourceAttr, spanAttr []*v1_common.KeyValue) {
 	for _, dim := range p.Cfg.Dimensions {
 		if v, ok := processor_util.FindAttributeValue(dim, resourceAttr, spanAttr); ok {
@@ -390,7 +482,7 @@ func (p *Processor) onComplete(e *store.Edge) {
 	p.serviceGraphRequestServerSecondsHistogram.ObserveWithExemplar(registryLabelValues, e.ServerLatencySec, e.TraceID, e.SpanMultiplier)
 	p.serviceGraphRequestClientSecondsHistogram.ObserveWithExemplar(registryLabelValues, e.ClientLatencySec, e.TraceID, e.SpanMultiplier)
 
-	if p.Cfg.EnableMessagingSystemLatencyHistogram && e.ConnectionType == store.MessagingSystem {
+	if p.Cfg.EnableMessagingSystemLatencyHistogram && (e.ConnectionType == store.MessagingSystem || e.ConnectionType == store.MessagingLink) {
 		messagingSystemLatencySec := unixNanosDiffSec(e.ClientEndTimeUnixNano, e.ServerStartTimeUnixNano)
 		if messagingSystemLatencySec == 0 {
 			level.Warn(p.logger).Log("msg", "producerSpanEndTime must be smaller than consumerSpanStartTime. maybe the peers clocks are not synced", "messagingSystemLatencySec", messagingSystemLatencySec, "traceID", e.TraceID)
@@ -447,6 +539,17 @@ func (p *Processor) onExpire(e *store.Edge) {
 	}
 }
 
+// onLinkComplete is called for edges built from span links that found their counterpart.
+func (p *Processor) onLinkComplete(e *store.Edge) {
+	p.metricLinkEdges.Inc()
+	p.onComplete(e)
+}
+
+// onLinkExpire is called for edges built from span links that didn't find their counterpart. Most
+// producer spans are consumed by child spans instead of linked ones, so these are not counted as
+// expired edges.
+func (p *Processor) onLinkExpire(*store.Edge) {}
+
 func (p *Processor) addDroppedSpanSide(span *v1_trace.Span) {
 	if isClient(span.Kind) {
 		key := buildKey(hex.EncodeToString(span.TraceId), hex.EncodeToString(span.SpanId))
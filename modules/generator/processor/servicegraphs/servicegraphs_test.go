@@ -6,6 +6,7 @@ import (
 	"errors"
 	"math"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
@@ -21,6 +22,7 @@ import (
 	tracev1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -980,6 +982,214 @@ func TestServiceGraphs_DatabaseNameAttributes(t *testing.T) {
 	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, labels))
 }
 
+func TestServiceGraphs_messagingLinks(t *testing.T) {
+	resourceSpans := func(service string, span *tracev1.Span) *tracev1.ResourceSpans {
+		return &tracev1.ResourceSpans{
+			Resource: &resourcev1.Resource{
+				Attributes: []*v1.KeyValue{{Key: "service.name", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: service}}}},
+			},
+			ScopeSpans: []*tracev1.ScopeSpans{{Spans: []*tracev1.Span{span}}},
+		}
+	}
+
+	producerTraceID, producerSpanID := []byte{0x01}, []byte{0x0a}
+	producer := &tracev1.Span{
+		TraceId:           producerTraceID,
+		SpanId:            producerSpanID,
+		Kind:              tracev1.Span_SPAN_KIND_PRODUCER,
+		StartTimeUnixNano: uint64(time.Second),
+		EndTimeUnixNano:   uint64(2 * time.Second),
+	}
+	// the consumer starts a new trace that is only connected to the producer by a link
+	consumer := &tracev1.Span{
+		TraceId:           []byte{0x02},
+		SpanId:            []byte{0x0b},
+		Kind:              tracev1.Span_SPAN_KIND_CONSUMER,
+		StartTimeUnixNano: uint64(5 * time.Second),
+		EndTimeUnixNano:   uint64(6 * time.Second),
+		Links:             []*tracev1.Span_Link{{TraceId: producerTraceID, SpanId: producerSpanID}},
+	}
+	// the child consumer also links to its parent, the edge is only counted once
+	childConsumer := &tracev1.Span{
+		TraceId:           producerTraceID,
+		SpanId:            []byte{0x0c},
+		ParentSpanId:      producerSpanID,
+		Kind:              tracev1.Span_SPAN_KIND_CONSUMER,
+		StartTimeUnixNano: uint64(3 * time.Second),
+		EndTimeUnixNano:   uint64(4 * time.Second),
+		Links:             []*tracev1.Span_Link{{TraceId: producerTraceID, SpanId: producerSpanID}},
+	}
+
+	request := &tempopb.PushSpansRequest{Batches: []*tracev1.ResourceSpans{
+		resourceSpans("publisher", producer),
+		resourceSpans("worker", consumer),
+		resourceSpans("child-worker", childConsumer),
+	}}
+
+	linkLabels := labels.FromMap(map[string]string{
+		"client":          "publisher",
+		"server":          "worker",
+		"connection_type": "messaging_link",
+	})
+	childLabels := labels.FromMap(map[string]string{
+		"client":          "publisher",
+		"server":          "child-worker",
+		"connection_type": "messaging_system",
+	})
+	childLinkLabels := labels.FromMap(map[string]string{
+		"client":          "publisher",
+		"server":          "child-worker",
+		"connection_type": "messaging_link",
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		testRegistry := registry.NewTestRegistry()
+
+		cfg := Config{}
+		cfg.RegisterFlagsAndApplyDefaults("", nil)
+		cfg.HistogramBuckets = []float64{2, 4}
+		cfg.EnableMessagingLinks = true
+		cfg.EnableMessagingSystemLatencyHistogram = true
+
+		p, err := New(cfg, "messaging-links-test", testRegistry, log.NewNopLogger(), prometheus.NewCounter(prometheus.CounterOpts{}), prometheus.NewCounter(prometheus.CounterOpts{}))
+		require.NoError(t, err)
+		defer p.Shutdown(context.Background())
+
+		p.PushSpans(context.Background(), request)
+
+		assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, linkLabels))
+		assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, childLabels))
+		assert.Equal(t, 0.0, testRegistry.Query(`traces_service_graph_request_total`, childLinkLabels))
+
+		// the time between the end of the producer and the start of the consumer is 3 seconds
+		assert.Equal(t, 0.0, testRegistry.Query(`traces_service_graph_request_messaging_system_seconds_bucket`, withLe(linkLabels, 2)))
+		assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_messaging_system_seconds_bucket`, withLe(linkLabels, 4)))
+
+		// the link store doesn't add to the edges of the parent/child relationships, the producer and
+		// the root consumer. Its edge is only counted once it's completed.
+		linkEdges, err := test.GetCounterVecValue(metricLinkEdges, "messaging-links-test")
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, linkEdges)
+
+		totalEdges, err := test.GetCounterVecValue(metricTotalEdges, "messaging-links-test")
+		require.NoError(t, err)
+		assert.Equal(t, 2.0, totalEdges)
+	})
+
+	t.Run("link store full", func(t *testing.T) {
+		testRegistry := registry.NewTestRegistry()
+
+		cfg := Config{}
+		cfg.RegisterFlagsAndApplyDefaults("", nil)
+		cfg.EnableMessagingLinks = true
+		cfg.MessagingLinksMaxItems = 1
+
+		p, err := New(cfg, "messaging-links-full-test", testRegistry, log.NewNopLogger(), prometheus.NewCounter(prometheus.CounterOpts{}), prometheus.NewCounter(prometheus.CounterOpts{}))
+		require.NoError(t, err)
+		defer p.Shutdown(context.Background())
+
+		otherProducer := *producer
+		otherProducer.SpanId = []byte{0x0d}
+		p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*tracev1.ResourceSpans{
+			resourceSpans("publisher", producer),
+			resourceSpans("publisher", &otherProducer),
+		}})
+
+		droppedLinkSpans, err := test.GetCounterVecValue(metricDroppedLinkSpans, "messaging-links-full-test")
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, droppedLinkSpans)
+
+		// the edges of the parent/child relationship are not limited by the link store
+		droppedSpans, err := test.GetCounterVecValue(metricDroppedSpans, "messaging-links-full-test")
+		require.NoError(t, err)
+		assert.Equal(t, 0.0, droppedSpans)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		testRegistry := registry.NewTestRegistry()
+
+		cfg := Config{}
+		cfg.RegisterFlagsAndApplyDefaults("", nil)
+
+		p, err := New(cfg, "test", testRegistry, log.NewNopLogger(), prometheus.NewCounter(prometheus.CounterOpts{}), prometheus.NewCounter(prometheus.CounterOpts{}))
+		require.NoError(t, err)
+		defer p.Shutdown(context.Background())
+
+		p.PushSpans(context.Background(), request)
+
+		assert.Equal(t, 0.0, testRegistry.Query(`traces_service_graph_request_total`, linkLabels))
+		assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, childLabels))
+	})
+}
+
+func TestServiceGraphs_edgeSnapshots(t *testing.T) {
+	resourceSpans := func(service string, span *tracev1.Span) *tracev1.ResourceSpans {
+		return &tracev1.ResourceSpans{
+			Resource: &resourcev1.Resource{
+				Attributes: []*v1.KeyValue{{Key: "service.name", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: service}}}},
+			},
+			ScopeSpans: []*tracev1.ScopeSpans{{Spans: []*tracev1.Span{span}}},
+		}
+	}
+
+	clientSpan := &tracev1.Span{
+		TraceId:           []byte{0x01},
+		SpanId:            []byte{0x0a},
+		Kind:              tracev1.Span_SPAN_KIND_CLIENT,
+		StartTimeUnixNano: uint64(time.Second),
+		EndTimeUnixNano:   uint64(3 * time.Second),
+	}
+	serverSpan := &tracev1.Span{
+		TraceId:           []byte{0x01},
+		SpanId:            []byte{0x0b},
+		ParentSpanId:      []byte{0x0a},
+		Kind:              tracev1.Span_SPAN_KIND_SERVER,
+		StartTimeUnixNano: uint64(time.Second),
+		EndTimeUnixNano:   uint64(2 * time.Second),
+	}
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Wait = time.Hour
+	cfg.EnableEdgeSnapshots = true
+
+	path := filepath.Join(t.TempDir(), "edges", "test.json")
+
+	// the client span is pushed before the restart
+	p, err := New(cfg, "test", registry.NewTestRegistry(), log.NewNopLogger(), prometheus.NewCounter(prometheus.CounterOpts{}), prometheus.NewCounter(prometheus.CounterOpts{}))
+	require.NoError(t, err)
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*tracev1.ResourceSpans{resourceSpans("frontend", clientSpan)}})
+	require.NoError(t, p.(*Processor).SnapshotEdges(path))
+	p.Shutdown(context.Background())
+	require.FileExists(t, path)
+
+	// the server span is pushed after the restart
+	testRegistry := registry.NewTestRegistry()
+	p, err = New(cfg, "test", testRegistry, log.NewNopLogger(), prometheus.NewCounter(prometheus.CounterOpts{}), prometheus.NewCounter(prometheus.CounterOpts{}))
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	require.NoError(t, p.(*Processor).RestoreEdges(path))
+	require.NoFileExists(t, path, "the snapshot is only restored once")
+	assert.Equal(t, 1.0, testutil.ToFloat64(p.(*Processor).metricRestoredEdges))
+	assert.Equal(t, 0.0, testutil.ToFloat64(p.(*Processor).metricDroppedRestoredEdges))
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*tracev1.ResourceSpans{resourceSpans("backend", serverSpan)}})
+
+	lbls := labels.FromMap(map[string]string{
+		"client":          "frontend",
+		"server":          "backend",
+		"connection_type": "",
+	})
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, lbls))
+	assert.Equal(t, 0.0, testutil.ToFloat64(p.(*Processor).metricExpiredEdges))
+
+	// without pending edges no snapshot is written, restoring is a no-op
+	require.NoError(t, p.(*Processor).SnapshotEdges(path))
+	require.NoFileExists(t, path)
+	require.NoError(t, p.(*Processor).RestoreEdges(path))
+}
+
 func BenchmarkServiceGraphs(b *testing.B) {
 	testRegistry := registry.NewTestRegistry()
 
@@ -0,0 +1,126 @@
+package servicegraphs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/modules/generator/processor/servicegraphs/store"
+)
+
+var (
+	metricRestoredEdges = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_processor_service_graphs_restored_edges_total",
+		Help:      "Number of edges restored from a snapshot",
+	}, []string{"tenant"})
+	metricDroppedRestoredEdges = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_processor_service_graphs_dropped_restored_edges_total",
+		Help:      "Number of edges of a snapshot that could not be restored because the store is full",
+	}, []string{"tenant"})
+)
+
+// edgeSnapshot is the content of a snapshot file.
+type edgeSnapshot struct {
+	Edges     []store.SnapshotEdge `json:"edges"`
+	LinkEdges []store.SnapshotEdge `json:"link_edges,omitempty"`
+}
+
+// SnapshotEdges writes the edges waiting for their pair to the file at path, so they can be
+// restored after a restart or a partition rebalance. If no edges are waiting, the file is removed.
+func (p *Processor) SnapshotEdges(path string) error {
+	snapshot := edgeSnapshot{
+		Edges: p.store.Snapshot(),
+	}
+	if p.linkStore != nil {
+		snapshot.LinkEdges = p.linkStore.Snapshot()
+	}
+
+	if len(snapshot.Edges) == 0 && len(snapshot.LinkEdges) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal edges: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so a crash doesn't leave a partial snapshot behind
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+
+	level.Info(p.logger).Log("msg", "snapshotted edges", "path", path, "edges", len(snapshot.Edges), "linkEdges", len(snapshot.LinkEdges))
+	return nil
+}
+
+// RestoreEdges adds the edges of the snapshot file at path to the store and removes the file. It
+// does nothing if the file doesn't exist.
+func (p *Processor) RestoreEdges(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// the snapshot is only restored once, even if it's invalid
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			// restored concurrently
+			return nil
+		}
+		return err
+	}
+
+	var snapshot edgeSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to unmarshal edges: %w", err)
+	}
+
+	restored, dropped := p.store.Restore(snapshot.Edges)
+	if p.linkStore != nil {
+		linkRestored, linkDropped := p.linkStore.Restore(snapshot.LinkEdges)
+		restored += linkRestored
+		dropped += linkDropped
+	} else {
+		// messaging links have been disabled since the snapshot was taken
+		dropped += len(snapshot.LinkEdges)
+	}
+
+	p.metricRestoredEdges.Add(float64(restored))
+	p.metricDroppedRestoredEdges.Add(float64(dropped))
+
+	level.Info(p.logger).Log("msg", "restored edges", "path", path, "restored", restored, "dropped", dropped)
+	return nil
+}
+
+// DiscardEdgeSnapshot removes the snapshot file at path without restoring it. It's used when the
+// edges of the snapshot are still in the store, restoring them would bring back edges that have
+// completed or expired since.
+func (p *Processor) DiscardEdgeSnapshot(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -1,7 +1,5 @@
 package store
 
-import "time"
-
 type ConnectionType string
 
 const (
@@ -9,6 +7,7 @@ const (
 	MessagingSystem ConnectionType = "messaging_system"
 	Database        ConnectionType = "database"
 	VirtualNode     ConnectionType = "virtual_node"
+	MessagingLink   ConnectionType = "messaging_link"
 )
 
 // Edge is an Edge between two nodes in the graph
@@ -54,10 +53,6 @@ func (e *Edge) isComplete() bool {
 	return len(e.ClientService) != 0 && len(e.ServerService) != 0
 }
 
-func (e *Edge) isExpired() bool {
-	return time.Now().Unix() >= e.expiration
-}
-
 func (e *Edge) Key() string {
 	return e.key
 }
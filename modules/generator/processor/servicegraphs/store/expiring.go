@@ -0,0 +1,95 @@
+package store
+
+import (
+	"container/list"
+)
+
+// ExpiringList holds items indexed by key and sorted by their expiration, so expired items can
+// be evicted from the front. It's the wait/expire mechanism of the edge store and is also used by
+// other processors that buffer spans. ExpiringList is not safe for concurrent use.
+type ExpiringList[T any] struct {
+	l *list.List
+	m map[string]*list.Element
+
+	expiration func(T) int64
+}
+
+type expiringItem[T any] struct {
+	key   string
+	value T
+}
+
+// NewExpiringList creates an ExpiringList. expiration returns the time at which an item expires,
+// all items must use the same unit.
+func NewExpiringList[T any](expiration func(T) int64) *ExpiringList[T] {
+	return &ExpiringList[T]{
+		l: list.New(),
+		m: make(map[string]*list.Element),
+
+		expiration: expiration,
+	}
+}
+
+func (e *ExpiringList[T]) Len() int {
+	return e.l.Len()
+}
+
+// Get returns the item stored under key.
+func (e *ExpiringList[T]) Get(key string) (T, bool) {
+	ele, ok := e.m[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return ele.Value.(*expiringItem[T]).value, true
+}
+
+// Push stores the item under key, replacing any item with the same key. The list is kept sorted
+// by expiration, Push must be called again after the expiration of a stored item changes.
+func (e *ExpiringList[T]) Push(key string, value T) {
+	if ele, ok := e.m[key]; ok {
+		e.l.Remove(ele)
+	}
+
+	item := &expiringItem[T]{key: key, value: value}
+	expiration := e.expiration(value)
+
+	// new items usually expire after the items in the list, start searching from the back
+	for ele := e.l.Back(); ele != nil; ele = ele.Prev() {
+		if e.expiration(ele.Value.(*expiringItem[T]).value) <= expiration {
+			e.m[key] = e.l.InsertAfter(item, ele)
+			return
+		}
+	}
+	e.m[key] = e.l.PushFront(item)
+}
+
+// Delete removes the item stored under key.
+func (e *ExpiringList[T]) Delete(key string) (T, bool) {
+	ele, ok := e.m[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	delete(e.m, key)
+	e.l.Remove(ele)
+	return ele.Value.(*expiringItem[T]).value, true
+}
+
+// PopExpired removes and returns the item that expires first if it has expired at now.
+func (e *ExpiringList[T]) PopExpired(now int64) (T, bool) {
+	head := e.l.Front()
+	if head == nil || e.expiration(head.Value.(*expiringItem[T]).value) > now {
+		var zero T
+		return zero, false
+	}
+	return e.Delete(head.Value.(*expiringItem[T]).key)
+}
+
+// Range calls f for every item, the item that expires first is visited first.
+func (e *ExpiringList[T]) Range(f func(key string, value T)) {
+	for ele := e.l.Front(); ele != nil; ele = ele.Next() {
+		item := ele.Value.(*expiringItem[T])
+		f(item.key, item.value)
+	}
+}
@@ -0,0 +1,53 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type expiringTestItem struct {
+	expiration int64
+}
+
+func TestExpiringList(t *testing.T) {
+	l := NewExpiringList(func(i *expiringTestItem) int64 { return i.expiration })
+
+	l.Push("b", &expiringTestItem{expiration: 20})
+	l.Push("c", &expiringTestItem{expiration: 30})
+	// inserted out of order
+	l.Push("a", &expiringTestItem{expiration: 10})
+	require.Equal(t, 3, l.Len())
+
+	var keys []string
+	l.Range(func(key string, _ *expiringTestItem) { keys = append(keys, key) })
+	assert.Equal(t, []string{"a", "b", "c"}, keys)
+
+	// extending the expiration moves the item
+	a, ok := l.Get("a")
+	require.True(t, ok)
+	a.expiration = 40
+	l.Push("a", a)
+
+	_, ok = l.PopExpired(19)
+	assert.False(t, ok)
+
+	b, ok := l.PopExpired(20)
+	require.True(t, ok)
+	assert.Equal(t, int64(20), b.expiration)
+
+	c, ok := l.Delete("c")
+	require.True(t, ok)
+	assert.Equal(t, int64(30), c.expiration)
+	_, ok = l.Get("c")
+	assert.False(t, ok)
+
+	a, ok = l.PopExpired(100)
+	require.True(t, ok)
+	assert.Equal(t, int64(40), a.expiration)
+
+	_, ok = l.PopExpired(100)
+	assert.False(t, ok)
+	assert.Equal(t, 0, l.Len())
+}
@@ -20,4 +20,9 @@ type Store interface {
 	HasDroppedSpanSide(key string, side Side) bool
 	// Expire evicts expired edges from the store.
 	Expire()
+	// Snapshot returns a copy of the edges waiting for their pair.
+	Snapshot() []SnapshotEdge
+	// Restore adds the edges of a snapshot to the store and returns the number of restored and
+	// dropped edges.
+	Restore(edges []SnapshotEdge) (restored, dropped int)
 }
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"maps"
+	"time"
+)
+
+// SnapshotEdge is an edge waiting for its pair, as persisted in a snapshot of the store.
+type SnapshotEdge struct {
+	Key string `json:"key"`
+	Edge
+	// TTL is the time the edge had left to find its pair when the snapshot was taken. The time the
+	// snapshot spends on disk doesn't count against it.
+	TTL time.Duration `json:"ttl"`
+}
+
+// Snapshot returns a copy of the edges in the store, oldest first.
+func (s *store) Snapshot() []SnapshotEdge {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := time.Now().Unix()
+
+	edges := make([]SnapshotEdge, 0, s.edges.Len())
+	s.edges.Range(func(_ string, e *Edge) {
+		se := SnapshotEdge{
+			Key:  e.key,
+			Edge: *e,
+			TTL:  time.Duration(max(e.expiration-now, 0)) * time.Second,
+		}
+		// edges are pooled, don't share the dimensions
+		se.Dimensions = maps.Clone(e.Dimensions)
+		edges = append(edges, se)
+	})
+
+	return edges
+}
+
+// Restore adds the edges of a snapshot to the store. Edges that are already in the store are
+// skipped. Returns the number of edges restored and the number of edges dropped because the store
+// is full.
+func (s *store) Restore(edges []SnapshotEdge) (restored, dropped int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := time.Now()
+
+	for _, se := range edges {
+		if _, ok := s.edges.Get(se.Key); ok {
+			continue
+		}
+		if s.edges.Len() >= s.maxItems {
+			dropped++
+			continue
+		}
+
+		edge := s.grabEdge(se.Key)
+		dimensions := edge.Dimensions
+		*edge = se.Edge
+		edge.key = se.Key
+		edge.expiration = now.Add(se.TTL).Unix()
+		edge.Dimensions = dimensions
+		maps.Copy(edge.Dimensions, se.Dimensions)
+
+		s.edges.Push(se.Key, edge)
+		restored++
+	}
+
+	return restored, dropped
+}
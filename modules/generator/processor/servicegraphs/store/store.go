@@ -1,7 +1,6 @@
 package store
 
 import (
-	"container/list"
 	"errors"
 	"sync"
 	"time"
@@ -17,10 +16,9 @@ var (
 var _ Store = (*store)(nil)
 
 type store struct {
-	l   *list.List
-	mtx sync.Mutex
-	m   map[string]*list.Element
-	d   map[droppedSpanSideKey]int64
+	edges *ExpiringList[*Edge]
+	mtx   sync.Mutex
+	d     map[droppedSpanSideKey]int64
 
 	onComplete Callback
 	onExpire   Callback
@@ -41,9 +39,8 @@ type droppedSpanSideKey struct {
 // have not found their pair are deleted after ttl time.
 func NewStore(ttl time.Duration, maxItems int, onComplete, onExpire Callback, droppedSpanSideOverflowCounter prometheus.Counter) Store {
 	s := &store{
-		l: list.New(),
-		m: make(map[string]*list.Element),
-		d: make(map[droppedSpanSideKey]int64),
+		edges: NewExpiringList(func(e *Edge) int64 { return e.expiration }),
+		d:     make(map[droppedSpanSideKey]int64),
 
 		onComplete: onComplete,
 		onExpire:   onExpire,
@@ -61,7 +58,7 @@ func (s *store) len() int {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
-	return s.l.Len()
+	return s.edges.Len()
 }
 
 // tryEvictHead checks if the oldest item (head of list) can be evicted and will delete it if so.
@@ -69,29 +66,21 @@ func (s *store) len() int {
 //
 // Must be called holding lock.
 func (s *store) tryEvictHead() bool {
-	head := s.l.Front()
-	if head == nil {
-		// list is empty
-		return false
-	}
-
-	headEdge := head.Value.(*Edge)
-	if !headEdge.isExpired() {
+	headEdge, ok := s.edges.PopExpired(time.Now().Unix())
+	if !ok {
 		return false
 	}
 
 	s.onExpire(headEdge)
-	s.deleteEdge(head)
+	s.returnEdge(headEdge)
 
 	return true
 }
 
-// deleteEdge removes an edge from the map/list and returns it to the pool.
+// deleteEdge removes an edge from the store and returns it to the pool.
 // Must be called holding lock.
-func (s *store) deleteEdge(ele *list.Element) {
-	edge := ele.Value.(*Edge)
-	delete(s.m, edge.key)
-	s.l.Remove(ele)
+func (s *store) deleteEdge(edge *Edge) {
+	s.edges.Delete(edge.key)
 	s.returnEdge(edge)
 }
 
@@ -102,13 +91,12 @@ func (s *store) UpsertEdge(key string, side Side, update Callback) (isNew bool,
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
-	if storedEdge, ok := s.m[key]; ok {
-		edge := storedEdge.Value.(*Edge)
+	if edge, ok := s.edges.Get(key); ok {
 		update(edge)
 
 		if edge.isComplete() {
 			s.onComplete(edge)
-			s.deleteEdge(storedEdge)
+			s.deleteEdge(edge)
 		}
 
 		return false, nil
@@ -128,14 +116,13 @@ func (s *store) UpsertEdge(key string, side Side, update Callback) (isNew bool,
 	}
 
 	// Check we can add new edges
-	if s.l.Len() >= s.maxItems {
+	if s.edges.Len() >= s.maxItems {
 		// todo: try to evict expired items
 		s.returnEdge(edge)
 		return false, ErrTooManyItems
 	}
 
-	ele := s.l.PushBack(edge)
-	s.m[key] = ele
+	s.edges.Push(key, edge)
 
 	return true, nil
 }
@@ -157,12 +144,11 @@ func (s *store) AddDroppedSpanSide(key string, side Side) bool {
 	defer s.mtx.Unlock()
 
 	droppedCounterpartEdge := false
-	if storedEdge, ok := s.m[key]; ok {
-		edge := storedEdge.Value.(*Edge)
+	if edge, ok := s.edges.Get(key); ok {
 		// If a counterpart edge is already buffered, drop it immediately instead of
 		// waiting for TTL expiration.
 		if !edge.isComplete() && getEdgeSide(edge) != side {
-			s.deleteEdge(storedEdge)
+			s.deleteEdge(edge)
 			droppedCounterpartEdge = true
 		}
 	}
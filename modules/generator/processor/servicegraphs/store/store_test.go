@@ -255,9 +255,9 @@ func TestStore_AddDroppedSpanSide_dropsExistingCounterpartEdge(t *testing.T) {
 	assert.True(t, droppedCounterpart)
 
 	assert.Equal(t, 1, s.len())
-	_, foundK1 := s.m["k1"]
+	_, foundK1 := s.edges.Get("k1")
 	assert.False(t, foundK1)
-	_, foundK2 := s.m["k2"]
+	_, foundK2 := s.edges.Get("k2")
 	assert.True(t, foundK2)
 }
 
@@ -385,6 +385,56 @@ func newTestCounter() prometheus.Counter {
 	return prometheus.NewCounter(prometheus.CounterOpts{})
 }
 
+func TestStore_SnapshotRestore(t *testing.T) {
+	var onCompletedCount int
+	var onExpireCount int
+
+	s := NewStore(time.Hour, 2, countingCallback(&onCompletedCount), countingCallback(&onExpireCount), newTestCounter()).(*store)
+
+	_, err := s.UpsertEdge("key-1", Client, func(e *Edge) {
+		e.ClientService = clientService
+		e.Dimensions["foo"] = "bar"
+	})
+	require.NoError(t, err)
+	_, err = s.UpsertEdge("key-2", Server, func(e *Edge) {
+		e.ServerService = "server"
+	})
+	require.NoError(t, err)
+
+	snapshot := s.Snapshot()
+	require.Len(t, snapshot, 2)
+	assert.Equal(t, "key-1", snapshot[0].Key)
+	assert.Equal(t, clientService, snapshot[0].ClientService)
+	assert.Equal(t, map[string]string{"foo": "bar"}, snapshot[0].Dimensions)
+	assert.InDelta(t, time.Hour, snapshot[0].TTL, float64(time.Second))
+
+	restored := NewStore(time.Hour, 2, countingCallback(&onCompletedCount), countingCallback(&onExpireCount), newTestCounter()).(*store)
+	_, err = restored.UpsertEdge("key-3", Client, func(e *Edge) {
+		e.ClientService = clientService
+	})
+	require.NoError(t, err)
+
+	// the store is full after the first edge
+	n, dropped := restored.Restore(snapshot)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 1, dropped)
+	assert.Equal(t, 2, restored.len())
+
+	// edges that are already in the store are skipped
+	n, dropped = restored.Restore(snapshot[:1])
+	assert.Equal(t, 0, n)
+	assert.Equal(t, 0, dropped)
+
+	// the restored edge can be completed
+	_, err = restored.UpsertEdge("key-1", Server, func(e *Edge) {
+		assert.Equal(t, "bar", e.Dimensions["foo"])
+		e.ServerService = "server"
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, onCompletedCount)
+	assert.Equal(t, 1, restored.len())
+}
+
 func TestResetEdge(t *testing.T) {
 	// Create an edge with all fields set to non-zero values
 	dimensions := map[string]string{"key1": "value1", "key2": "value2"}
@@ -0,0 +1,23 @@
+package spanevents
+
+import (
+	"flag"
+
+	"github.com/grafana/tempo/pkg/sharedconfig"
+	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+)
+
+type Config struct {
+	// Additional dimensions (labels) to be added to the metrics. The dimensions are generated from
+	// the event attributes, the span and resource attributes are used if the event doesn't have the
+	// attribute.
+	Dimensions []string `yaml:"dimensions"`
+
+	// Dimension label mapping to allow the user to rename attributes in their metrics
+	DimensionMappings []sharedconfig.DimensionMappings `yaml:"dimension_mappings"`
+
+	// FilterPolicies is a list of policies that will be applied to spans for inclusion or exclusion.
+	FilterPolicies []filterconfig.FilterPolicy `yaml:"filter_policies"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {}
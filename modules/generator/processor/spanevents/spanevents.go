@@ -0,0 +1,174 @@
+package spanevents
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	gen "github.com/grafana/tempo/modules/generator/processor"
+	processor_util "github.com/grafana/tempo/modules/generator/processor/util"
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/modules/generator/validation"
+	"github.com/grafana/tempo/pkg/cache/reclaimable"
+	"github.com/grafana/tempo/pkg/spanfilter"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+const (
+	metricSpanEventsTotal = "traces_span_events_total"
+	metricExceptionsTotal = "traces_exceptions_total"
+
+	// exceptionEventName and exceptionTypeKey follow the OpenTelemetry semantic conventions for
+	// exceptions: https://opentelemetry.io/docs/specs/semconv/exceptions/exceptions-spans/
+	exceptionEventName = "exception"
+	exceptionTypeKey   = "exception.type"
+)
+
+var intrinsicDimensions = []string{gen.DimService, gen.DimSpanName, gen.DimEventName, gen.DimExceptionType}
+
+// Processor counts the events of spans. All events are counted in traces_span_events_total by
+// event name, exception events are counted in traces_exceptions_total by exception type.
+type Processor struct {
+	Cfg Config
+
+	registry registry.Registry
+
+	spanEventsTotal registry.Counter
+	exceptionsTotal registry.Counter
+
+	// labels are the sanitized label names of the dimensions and dimension mappings
+	labels []string
+
+	filter               *spanfilter.SpanFilter
+	filteredSpansCounter prometheus.Counter
+	invalidUTF8Counter   prometheus.Counter
+}
+
+func New(cfg Config, reg registry.Registry, filteredSpansCounter, invalidUTF8Counter prometheus.Counter) (gen.Processor, error) {
+	c := reclaimable.New(validation.SanitizeLabelName, 10000)
+
+	err := validation.ValidateDimensions(cfg.Dimensions, intrinsicDimensions, cfg.DimensionMappings, c.Get)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := spanfilter.NewSpanFilter(cfg.FilterPolicies)
+	if err != nil {
+		return nil, err
+	}
+
+	intrinsicSet := make(map[string]struct{}, len(intrinsicDimensions))
+	for _, d := range intrinsicDimensions {
+		intrinsicSet[d] = struct{}{}
+	}
+
+	labels := make([]string, 0, len(cfg.Dimensions)+len(cfg.DimensionMappings))
+	for _, d := range cfg.Dimensions {
+		labels = append(labels, validation.SanitizeLabelNameWithCollisions(d, intrinsicSet, c.Get))
+	}
+	for _, m := range cfg.DimensionMappings {
+		labels = append(labels, validation.SanitizeLabelNameWithCollisions(m.Name, intrinsicSet, c.Get))
+	}
+
+	return &Processor{
+		Cfg:                  cfg,
+		registry:             reg,
+		spanEventsTotal:      reg.NewCounter(metricSpanEventsTotal),
+		exceptionsTotal:      reg.NewCounter(metricExceptionsTotal),
+		labels:               labels,
+		filter:               filter,
+		filteredSpansCounter: filteredSpansCounter,
+		invalidUTF8Counter:   invalidUTF8Counter,
+	}, nil
+}
+
+func (p *Processor) Name() string {
+	return gen.SpanEventsName
+}
+
+func (p *Processor) PushSpans(_ context.Context, req *tempopb.PushSpansRequest) {
+	for _, rs := range req.Batches {
+		svcName, _ := processor_util.FindServiceName(rs.Resource.Attributes)
+		for _, ils := range rs.ScopeSpans {
+			for _, span := range ils.Spans {
+				if len(span.Events) == 0 {
+					continue
+				}
+				if !p.filter.ApplyFilterPolicy(rs.Resource, span) {
+					p.filteredSpansCounter.Inc()
+					continue
+				}
+				for _, event := range span.Events {
+					p.aggregateMetricsForEvent(svcName, rs.Resource.Attributes, span, event)
+				}
+			}
+		}
+	}
+}
+
+func (p *Processor) Shutdown(_ context.Context) {
+}
+
+func (p *Processor) aggregateMetricsForEvent(svcName string, resourceAttributes []*v1_common.KeyValue, span *v1_trace.Span, event *v1_trace.Span_Event) {
+	isException := event.Name == exceptionEventName
+
+	eventBuilder := p.registry.NewLabelBuilder()
+	eventBuilder.Add(gen.DimService, svcName)
+	eventBuilder.Add(gen.DimSpanName, span.GetName())
+	eventBuilder.Add(gen.DimEventName, event.Name)
+
+	var exceptionBuilder registry.LabelBuilder
+	if isException {
+		exceptionType, _ := processor_util.FindAttributeValue(exceptionTypeKey, event.Attributes)
+
+		exceptionBuilder = p.registry.NewLabelBuilder()
+		exceptionBuilder.Add(gen.DimService, svcName)
+		exceptionBuilder.Add(gen.DimSpanName, span.GetName())
+		exceptionBuilder.Add(gen.DimExceptionType, exceptionType)
+	}
+
+	add := func(label, value string) {
+		eventBuilder.Add(label, value)
+		if isException {
+			exceptionBuilder.Add(label, value)
+		}
+	}
+
+	// event attributes take precedence over span and resource attributes
+	for i, d := range p.Cfg.Dimensions {
+		value, _ := processor_util.FindAttributeValue(d, event.Attributes, span.Attributes, resourceAttributes)
+		add(p.labels[i], value)
+	}
+
+	for i, m := range p.Cfg.DimensionMappings {
+		values := ""
+		for _, s := range m.SourceLabel {
+			if value, _ := processor_util.FindAttributeValue(s, event.Attributes, span.Attributes, resourceAttributes); value != "" {
+				if values == "" {
+					values += value
+				} else {
+					values = values + m.Join + value
+				}
+			}
+		}
+		add(p.labels[len(p.Cfg.Dimensions)+i], values)
+	}
+
+	eventLabels, validUTF8 := eventBuilder.CloseAndBuildLabels()
+	if !validUTF8 {
+		p.invalidUTF8Counter.Inc()
+		return
+	}
+	p.spanEventsTotal.Inc(eventLabels, 1)
+
+	if isException {
+		exceptionLabels, validUTF8 := exceptionBuilder.CloseAndBuildLabels()
+		if !validUTF8 {
+			p.invalidUTF8Counter.Inc()
+			return
+		}
+		p.exceptionsTotal.Inc(exceptionLabels, 1)
+	}
+}
@@ -0,0 +1,129 @@
+package spanevents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/generator/processor"
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/sharedconfig"
+	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	resource_v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	trace_v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestSpanEvents(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Dimensions = []string{"http.method", "level"}
+	cfg.DimensionMappings = []sharedconfig.DimensionMappings{{
+		Name:        "env",
+		SourceLabel: []string{"deployment.environment"},
+	}}
+
+	p, err := New(cfg, testRegistry, prometheus.NewCounter(prometheus.CounterOpts{}), prometheus.NewCounter(prometheus.CounterOpts{}))
+	require.NoError(t, err)
+	require.Equal(t, processor.SpanEventsName, p.Name())
+	defer p.Shutdown(context.Background())
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{
+		batch("test-service", []*trace_v1.Span{
+			span("GET /users", []*trace_v1.Span_Event{
+				exception("java.lang.NullPointerException"),
+				exception("java.lang.NullPointerException"),
+				exception("java.io.IOException"),
+				{Name: "log", Attributes: []*common_v1.KeyValue{str("level", "warn")}},
+			}),
+			span("GET /orders", nil),
+		}),
+	}})
+
+	lbls := func(kv ...string) labels.Labels {
+		return labels.FromStrings(append([]string{"service", "test-service", "span_name", "GET /users", "http_method", "GET", "env", "prod"}, kv...)...)
+	}
+
+	assert.Equal(t, 3.0, testRegistry.Query("traces_span_events_total", lbls("event_name", "exception")))
+	assert.Equal(t, 1.0, testRegistry.Query("traces_span_events_total", lbls("event_name", "log", "level", "warn")))
+	assert.Equal(t, 2.0, testRegistry.Query("traces_exceptions_total", lbls("exception_type", "java.lang.NullPointerException")))
+	assert.Equal(t, 1.0, testRegistry.Query("traces_exceptions_total", lbls("exception_type", "java.io.IOException")))
+}
+
+func TestSpanEventsFilterPolicies(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+	filteredSpansCounter := prometheus.NewCounter(prometheus.CounterOpts{})
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.FilterPolicies = []filterconfig.FilterPolicy{{
+		Include: &filterconfig.PolicyMatch{
+			MatchType: filterconfig.Strict,
+			Attributes: []filterconfig.MatchPolicyAttribute{
+				{Key: "resource.service.name", Value: "included"},
+			},
+		},
+	}}
+
+	p, err := New(cfg, testRegistry, filteredSpansCounter, prometheus.NewCounter(prometheus.CounterOpts{}))
+	require.NoError(t, err)
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{
+		batch("included", []*trace_v1.Span{span("op", []*trace_v1.Span_Event{exception("Error")})}),
+		batch("excluded", []*trace_v1.Span{span("op", []*trace_v1.Span_Event{exception("Error")})}),
+	}})
+
+	assert.Equal(t, 1.0, testRegistry.Query("traces_exceptions_total", labels.FromStrings("service", "included", "span_name", "op", "exception_type", "Error")))
+	assert.Equal(t, 0.0, testRegistry.Query("traces_exceptions_total", labels.FromStrings("service", "excluded", "span_name", "op", "exception_type", "Error")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(filteredSpansCounter))
+}
+
+func TestSpanEventsInvalidDimensionMapping(t *testing.T) {
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.DimensionMappings = []sharedconfig.DimensionMappings{{
+		Name:        "exception_type",
+		SourceLabel: []string{"error.type"},
+	}}
+
+	_, err := New(cfg, registry.NewTestRegistry(), prometheus.NewCounter(prometheus.CounterOpts{}), prometheus.NewCounter(prometheus.CounterOpts{}))
+	require.ErrorContains(t, err, `dimension_mapping "exception_type" produces label "exception_type" which collides with intrinsic dimension "exception_type"`)
+}
+
+func batch(service string, spans []*trace_v1.Span) *trace_v1.ResourceSpans {
+	return &trace_v1.ResourceSpans{
+		Resource: &resource_v1.Resource{
+			Attributes: []*common_v1.KeyValue{str("service.name", service), str("deployment.environment", "prod")},
+		},
+		ScopeSpans: []*trace_v1.ScopeSpans{{Spans: spans}},
+	}
+}
+
+func span(name string, events []*trace_v1.Span_Event) *trace_v1.Span {
+	return &trace_v1.Span{
+		TraceId:    []byte{1},
+		SpanId:     []byte{1},
+		Name:       name,
+		Attributes: []*common_v1.KeyValue{str("http.method", "GET")},
+		Events:     events,
+	}
+}
+
+func exception(typ string) *trace_v1.Span_Event {
+	return &trace_v1.Span_Event{
+		Name:       "exception",
+		Attributes: []*common_v1.KeyValue{str("exception.type", typ), str("exception.message", "something failed")},
+	}
+}
+
+func str(k, v string) *common_v1.KeyValue {
+	return &common_v1.KeyValue{Key: k, Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: v}}}
+}
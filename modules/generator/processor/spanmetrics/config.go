@@ -3,6 +3,7 @@ package spanmetrics
 import (
 	"flag"
 	"fmt"
+	"time"
 
 	"github.com/grafana/tempo/modules/generator/processor"
 	"github.com/grafana/tempo/modules/generator/registry"
@@ -64,6 +65,23 @@ type Config struct {
 
 	// Allow user to disable instance label from all span metrics series
 	EnableInstanceLabel bool `yaml:"enable_instance_label"`
+
+	// Dimensions of the root span of the trace to be added to the metrics of all its spans, prefixed
+	// with "root_". service and span_name are the service and name of the root span, any other value
+	// is looked up in the root span and resource attributes.
+	RootDimensions []string `yaml:"root_dimensions"`
+
+	// RootDimensionsWait is the time spans are buffered waiting for the root span of their trace. It
+	// runs from the first span of the trace, not from the arrival of the root span. Spans whose root
+	// span doesn't arrive within this time are recorded with unknown root dimensions.
+	RootDimensionsWait time.Duration `yaml:"root_dimensions_wait"`
+
+	// RootDimensionsMaxTraces is the amount of traces buffered waiting for their root span.
+	RootDimensionsMaxTraces int `yaml:"root_dimensions_max_traces"`
+
+	// RootDimensionsMaxSpansPerTrace is the amount of spans buffered per trace waiting for the root
+	// span. Spans over this limit are recorded right away with unknown root dimensions.
+	RootDimensionsMaxSpansPerTrace int `yaml:"root_dimensions_max_spans_per_trace"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
@@ -78,6 +96,9 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 	cfg.Subprocessors[Count] = true
 	cfg.Subprocessors[Size] = true
 	cfg.EnableInstanceLabel = true
+	cfg.RootDimensionsWait = 5 * time.Second
+	cfg.RootDimensionsMaxTraces = 10_000
+	cfg.RootDimensionsMaxSpansPerTrace = 1_000
 }
 
 type IntrinsicDimensions struct {
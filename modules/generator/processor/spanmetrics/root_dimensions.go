@@ -0,0 +1,153 @@
+package spanmetrics
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// spanSample holds the labels and values needed to record the metrics of a span. It's used to
+// record spans once the root dimensions of their trace are known.
+type spanSample struct {
+	labels         []string
+	values         []string
+	latencySeconds float64
+	size           float64
+	spanMultiplier float64
+	traceID        string
+}
+
+func (s *spanSample) add(label, value string) {
+	s.labels = append(s.labels, label)
+	s.values = append(s.values, value)
+}
+
+// rootTrace is a trace in the rootBuffer.
+type rootTrace struct {
+	key string
+	// rootValues are the values of the root dimensions, nil until the root span has been received.
+	rootValues []string
+	// pending are the spans waiting for the root span.
+	pending []spanSample
+	// expiration is set when the first span of the trace is received. It's not extended by later
+	// spans or by the root span.
+	expiration int64
+}
+
+// rootBuffer is a short trace-assembly buffer. It holds the spans of a trace until its root span is
+// received, and remembers the root dimensions of a trace for the spans that arrive after the root
+// span. Traces are removed from the buffer ttl after their first span was received.
+type rootBuffer struct {
+	mtx sync.Mutex
+	l   *list.List
+	m   map[string]*list.Element
+
+	ttl              time.Duration
+	maxTraces        int
+	maxSpansPerTrace int
+
+	// overflowCounter counts the spans that weren't buffered because the buffer was full.
+	overflowCounter prometheus.Counter
+}
+
+func newRootBuffer(ttl time.Duration, maxTraces, maxSpansPerTrace int, overflowCounter prometheus.Counter) *rootBuffer {
+	return &rootBuffer{
+		l:                list.New(),
+		m:                make(map[string]*list.Element),
+		ttl:              ttl,
+		maxTraces:        maxTraces,
+		maxSpansPerTrace: maxSpansPerTrace,
+		overflowCounter:  overflowCounter,
+	}
+}
+
+// setRoot sets the root dimensions of the trace and returns the spans that were waiting for them.
+func (b *rootBuffer) setRoot(key string, rootValues []string) []spanSample {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	t := b.getOrCreate(key)
+	if t == nil {
+		return nil
+	}
+
+	t.rootValues = rootValues
+	pending := t.pending
+	t.pending = nil
+	return pending
+}
+
+// getOrAdd returns the root dimensions of the trace if they are known. Otherwise, the span is
+// buffered until the root span is received and buffered is true. If the buffer is full or the
+// trace has reached the maximum number of buffered spans, the span is neither buffered nor are
+// root dimensions returned, so it's recorded right away with unknown root dimensions.
+func (b *rootBuffer) getOrAdd(key string, s spanSample) (rootValues []string, buffered bool) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	t := b.getOrCreate(key)
+	if t == nil {
+		b.overflowCounter.Inc()
+		return nil, false
+	}
+	if t.rootValues != nil {
+		return t.rootValues, false
+	}
+	if len(t.pending) >= b.maxSpansPerTrace {
+		b.overflowCounter.Inc()
+		return nil, false
+	}
+
+	t.pending = append(t.pending, s)
+	return nil, true
+}
+
+// getOrCreate returns the trace with the given key, or nil if it doesn't exist and the buffer is
+// full. Must be called holding lock.
+func (b *rootBuffer) getOrCreate(key string) *rootTrace {
+	if ele, ok := b.m[key]; ok {
+		return ele.Value.(*rootTrace)
+	}
+
+	if b.l.Len() >= b.maxTraces {
+		return nil
+	}
+
+	t := &rootTrace{
+		key:        key,
+		expiration: time.Now().Add(b.ttl).UnixNano(),
+	}
+	b.m[key] = b.l.PushBack(t)
+	return t
+}
+
+// expire removes the expired traces from the buffer and returns the spans that didn't receive
+// their root span. If all is set, all traces are removed.
+func (b *rootBuffer) expire(all bool) []spanSample {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	now := time.Now().UnixNano()
+
+	var expired []spanSample
+	for head := b.l.Front(); head != nil; head = b.l.Front() {
+		t := head.Value.(*rootTrace)
+		if !all && now < t.expiration {
+			break
+		}
+		expired = append(expired, t.pending...)
+		delete(b.m, t.key)
+		b.l.Remove(head)
+	}
+
+	return expired
+}
+
+func (b *rootBuffer) len() int {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b.l.Len()
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/grafana/tempo/modules/generator/validation"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	gen "github.com/grafana/tempo/modules/generator/processor"
 	processor_util "github.com/grafana/tempo/modules/generator/processor/util"
@@ -20,11 +21,21 @@ import (
 	tempo_util "github.com/grafana/tempo/pkg/util"
 )
 
+var metricRootDimensionsOverflowSpans = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "metrics_generator_processor_span_metrics_root_dimensions_overflow_spans_total",
+	Help:      "Number of spans recorded with unknown root dimensions because the maximum number of buffered traces or spans per trace was reached",
+}, []string{"tenant"})
+
 const (
 	metricCallsTotal      = "traces_spanmetrics_calls_total"
 	metricDurationSeconds = "traces_spanmetrics_latency"
 	metricSizeTotal       = "traces_spanmetrics_size_total"
 	targetInfo            = "traces_target_info"
+
+	rootDimensionPrefix          = "root_"
+	rootDimensionUnknown         = "unknown"
+	rootBufferExpirationInterval = time.Second
 )
 
 type Processor struct {
@@ -42,11 +53,17 @@ type Processor struct {
 	invalidUTF8Counter   prometheus.Counter
 	sanitizeCache        reclaimable.Cache[string, string]
 
+	// rootDimensionLabels are the label names of the root dimensions. rootBuffer is only set if
+	// root dimensions are configured.
+	rootDimensionLabels []string
+	rootBuffer          *rootBuffer
+	closeCh             chan struct{}
+
 	// for testing
 	now func() time.Time
 }
 
-func New(cfg Config, reg registry.Registry, filteredSpansCounter, invalidUTF8Counter prometheus.Counter) (gen.Processor, error) {
+func New(cfg Config, tenant string, reg registry.Registry, filteredSpansCounter, invalidUTF8Counter prometheus.Counter) (gen.Processor, error) {
 	var configuredIntrinsicDimensions []string
 
 	if cfg.IntrinsicDimensions.Service {
@@ -98,6 +115,32 @@ func New(cfg Config, reg registry.Registry, filteredSpansCounter, invalidUTF8Cou
 	}
 
 	p.filter = filter
+
+	if len(cfg.RootDimensions) > 0 {
+		p.rootDimensionLabels = make([]string, 0, len(cfg.RootDimensions))
+		for _, d := range cfg.RootDimensions {
+			p.rootDimensionLabels = append(p.rootDimensionLabels, validation.SanitizeLabelNameWithCollisions(rootDimensionPrefix+d, validation.SupportedIntrinsicDimensionsSet, c.Get))
+		}
+		p.rootBuffer = newRootBuffer(cfg.RootDimensionsWait, cfg.RootDimensionsMaxTraces, cfg.RootDimensionsMaxSpansPerTrace, metricRootDimensionsOverflowSpans.WithLabelValues(tenant))
+		p.closeCh = make(chan struct{})
+
+		go func() {
+			ticker := time.NewTicker(rootBufferExpirationInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				// Periodically record the spans whose root span didn't arrive in time
+				case <-ticker.C:
+					p.recordSpansWithoutRoot(p.rootBuffer.expire(false))
+
+				case <-p.closeCh:
+					return
+				}
+			}
+		}()
+	}
+
 	return p, nil
 }
 
@@ -110,9 +153,17 @@ func (p *Processor) PushSpans(_ context.Context, req *tempopb.PushSpansRequest)
 }
 
 func (p *Processor) Shutdown(_ context.Context) {
+	if p.rootBuffer != nil {
+		close(p.closeCh)
+		p.recordSpansWithoutRoot(p.rootBuffer.expire(true))
+	}
 }
 
 func (p *Processor) aggregateMetrics(resourceSpans []*v1_trace.ResourceSpans) {
+	if p.rootBuffer != nil {
+		p.collectRootDimensions(resourceSpans)
+	}
+
 	resourceLabels := make([]string, 0)
 	resourceValues := make([]string, 0)
 	for _, rs := range resourceSpans {
@@ -142,26 +193,30 @@ func (p *Processor) aggregateMetricsForSpan(svcName string, jobName string, inst
 		latencySeconds = float64(end-start) / float64(time.Second.Nanoseconds())
 	}
 
-	builder := p.registry.NewLabelBuilder()
+	sample := &spanSample{
+		labels:         make([]string, 0, 8+len(p.Cfg.Dimensions)+len(p.Cfg.DimensionMappings)),
+		values:         make([]string, 0, 8+len(p.Cfg.Dimensions)+len(p.Cfg.DimensionMappings)),
+		latencySeconds: latencySeconds,
+	}
 	targetInfoBuilder := p.registry.NewInfoMetricLabelBuilder()
 	for i := range resourceLabels {
 		targetInfoBuilder.Add(resourceLabels[i], resourceValues[i])
 	}
 
 	if p.Cfg.IntrinsicDimensions.Service {
-		builder.Add(gen.DimService, svcName)
+		sample.add(gen.DimService, svcName)
 	}
 	if p.Cfg.IntrinsicDimensions.SpanName {
-		builder.Add(gen.DimSpanName, span.GetName())
+		sample.add(gen.DimSpanName, span.GetName())
 	}
 	if p.Cfg.IntrinsicDimensions.SpanKind {
-		builder.Add(gen.DimSpanKind, span.GetKind().String())
+		sample.add(gen.DimSpanKind, span.GetKind().String())
 	}
 	if p.Cfg.IntrinsicDimensions.StatusCode {
-		builder.Add(gen.DimStatusCode, span.GetStatus().GetCode().String())
+		sample.add(gen.DimStatusCode, span.GetStatus().GetCode().String())
 	}
 	if p.Cfg.IntrinsicDimensions.StatusMessage {
-		builder.Add(gen.DimStatusMessage, span.GetStatus().GetMessage())
+		sample.add(gen.DimStatusMessage, span.GetStatus().GetMessage())
 	}
 
 	for _, d := range p.Cfg.Dimensions {
@@ -169,7 +224,7 @@ func (p *Processor) aggregateMetricsForSpan(svcName string, jobName string, inst
 		label := validation.SanitizeLabelNameWithCollisions(d, validation.SupportedIntrinsicDimensionsSet, p.sanitizeCache.Get)
 		// if there is a collision, for example deployment.environment and deployment_environment,
 		// both sanitized to deployment_environment, we just take the last one configured.
-		builder.Add(label, value)
+		sample.add(label, value)
 	}
 
 	for _, m := range p.Cfg.DimensionMappings {
@@ -184,39 +239,37 @@ func (p *Processor) aggregateMetricsForSpan(svcName string, jobName string, inst
 			}
 		}
 		label := validation.SanitizeLabelNameWithCollisions(m.Name, validation.SupportedIntrinsicDimensionsSet, p.sanitizeCache.Get)
-		builder.Add(label, values)
+		sample.add(label, values)
 	}
 
 	// add job label only if job is not blank and target_info is enabled
 	identifyingLabels := 0
 	if jobName != "" && p.Cfg.EnableTargetInfo {
-		builder.Add(gen.DimJob, jobName)
+		sample.add(gen.DimJob, jobName)
 		identifyingLabels++
 	}
 	// add instance label only if instance is not blank and enabled and target_info is enabled
 	if instanceID != "" && p.Cfg.EnableTargetInfo && p.Cfg.EnableInstanceLabel {
-		builder.Add(gen.DimInstance, instanceID)
+		sample.add(gen.DimInstance, instanceID)
 		identifyingLabels++
 	}
 
-	spanMultiplier := processor_util.GetSpanMultiplier(p.Cfg.SpanMultiplierKey, span, rs, p.Cfg.EnableTraceStateSpanMultiplier)
-
-	registryLabelValues, validUTF8 := builder.CloseAndBuildLabels()
-	if !validUTF8 {
-		p.invalidUTF8Counter.Inc()
-		return
+	sample.spanMultiplier = processor_util.GetSpanMultiplier(p.Cfg.SpanMultiplierKey, span, rs, p.Cfg.EnableTraceStateSpanMultiplier)
+	if p.Cfg.Subprocessors[Latency] {
+		sample.traceID = tempo_util.TraceIDToHexString(span.TraceId)
 	}
-
-	if p.Cfg.Subprocessors[Count] {
-		p.spanMetricsCallsTotal.Inc(registryLabelValues, 1*spanMultiplier)
+	if p.Cfg.Subprocessors[Size] {
+		sample.size = float64(span.Size())
 	}
 
-	if p.Cfg.Subprocessors[Latency] {
-		p.spanMetricsDurationSeconds.ObserveWithExemplar(registryLabelValues, latencySeconds, tempo_util.TraceIDToHexString(span.TraceId), spanMultiplier)
+	// spans whose root span hasn't been received yet are buffered and recorded later
+	var rootValues []string
+	buffered := false
+	if p.rootBuffer != nil {
+		rootValues, buffered = p.rootBuffer.getOrAdd(string(span.TraceId), *sample)
 	}
-
-	if p.Cfg.Subprocessors[Size] {
-		p.spanMetricsSizeTotal.Inc(registryLabelValues, float64(span.Size()))
+	if !buffered && !p.recordSpan(sample, rootValues) {
+		return
 	}
 
 	// update target_info label values
@@ -249,6 +302,83 @@ func (p *Processor) aggregateMetricsForSpan(svcName string, jobName string, inst
 	}
 }
 
+// recordSpan records the metrics of the span with the given root dimension values. rootValues is
+// nil if the root dimensions are unknown, they are recorded as "unknown" so all series of the
+// processor have the same labels. Returns false if the labels are not valid UTF-8.
+func (p *Processor) recordSpan(s *spanSample, rootValues []string) bool {
+	builder := p.registry.NewLabelBuilder()
+	for i := range s.labels {
+		builder.Add(s.labels[i], s.values[i])
+	}
+	for i, label := range p.rootDimensionLabels {
+		if rootValues == nil {
+			builder.Add(label, rootDimensionUnknown)
+			continue
+		}
+		builder.Add(label, rootValues[i])
+	}
+
+	registryLabelValues, validUTF8 := builder.CloseAndBuildLabels()
+	if !validUTF8 {
+		p.invalidUTF8Counter.Inc()
+		return false
+	}
+
+	if p.Cfg.Subprocessors[Count] {
+		p.spanMetricsCallsTotal.Inc(registryLabelValues, 1*s.spanMultiplier)
+	}
+
+	if p.Cfg.Subprocessors[Latency] {
+		p.spanMetricsDurationSeconds.ObserveWithExemplar(registryLabelValues, s.latencySeconds, s.traceID, s.spanMultiplier)
+	}
+
+	if p.Cfg.Subprocessors[Size] {
+		p.spanMetricsSizeTotal.Inc(registryLabelValues, s.size)
+	}
+
+	return true
+}
+
+func (p *Processor) recordSpansWithoutRoot(samples []spanSample) {
+	for i := range samples {
+		p.recordSpan(&samples[i], nil)
+	}
+}
+
+// collectRootDimensions stores the root dimensions of the root spans in the batches, and records
+// the buffered spans of their traces. It runs before the spans are aggregated, so spans pushed
+// together with their root span aren't buffered. Root spans are used even if they are filtered.
+func (p *Processor) collectRootDimensions(resourceSpans []*v1_trace.ResourceSpans) {
+	for _, rs := range resourceSpans {
+		svcName, _ := processor_util.FindServiceName(rs.Resource.Attributes)
+		for _, ils := range rs.ScopeSpans {
+			for _, span := range ils.Spans {
+				if len(span.ParentSpanId) != 0 {
+					continue
+				}
+
+				rootValues := make([]string, 0, len(p.Cfg.RootDimensions))
+				for _, d := range p.Cfg.RootDimensions {
+					switch d {
+					case gen.DimService:
+						rootValues = append(rootValues, svcName)
+					case gen.DimSpanName:
+						rootValues = append(rootValues, span.GetName())
+					default:
+						value, _ := processor_util.FindAttributeValue(d, rs.Resource.Attributes, span.Attributes)
+						rootValues = append(rootValues, value)
+					}
+				}
+
+				pending := p.rootBuffer.setRoot(string(span.TraceId), rootValues)
+				for i := range pending {
+					p.recordSpan(&pending[i], rootValues)
+				}
+			}
+		}
+	}
+}
+
 func getTargetInfoAttributesValues(keys, values *[]string, attributes []*v1_common.KeyValue, exclude []string, sanitizeFn validation.SanitizeFn) {
 	// TODO allocate with known length, or take new params for existing buffers
 	*keys = (*keys)[:0]
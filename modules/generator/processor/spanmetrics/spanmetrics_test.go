@@ -12,6 +12,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -42,7 +43,7 @@ func TestSpanMetrics(t *testing.T) {
 	cfg.RegisterFlagsAndApplyDefaults("", nil)
 	cfg.HistogramBuckets = []float64{0.5, 1}
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -82,7 +83,7 @@ func TestSpanMetricsTargetInfoEnabled(t *testing.T) {
 	cfg.EnableTargetInfo = true
 	cfg.TargetInfoExcludedDimensions = []string{"random.res.attr"}
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -230,7 +231,7 @@ func TestSpanMetrics_dimensions(t *testing.T) {
 			cfg.IntrinsicDimensions.StatusMessage = true
 			cfg.Dimensions = tc.dimensions
 
-			p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+			p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 			require.NoError(t, err)
 			defer p.Shutdown(context.Background())
 
@@ -275,7 +276,7 @@ func TestSpanMetrics_collisions(t *testing.T) {
 	cfg.Dimensions = []string{"span.kind", "span_name"}
 	cfg.IntrinsicDimensions.SpanKind = false
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -322,7 +323,7 @@ func TestJobLabelWithNamespaceAndInstanceID(t *testing.T) {
 	cfg.HistogramBuckets = []float64{0.5, 1}
 	cfg.EnableTargetInfo = true
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -454,7 +455,7 @@ func TestSpanMetrics_applyFilterPolicy(t *testing.T) {
 			cfg.FilterPolicies = tc.filterPolicies
 
 			testRegistry := registry.NewTestRegistry()
-			p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+			p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 			require.NoError(t, err)
 			defer p.Shutdown(context.Background())
 
@@ -515,7 +516,7 @@ func TestJobLabelWithNamespaceAndNoServiceName(t *testing.T) {
 	cfg.HistogramBuckets = []float64{0.5, 1}
 	cfg.EnableTargetInfo = true
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -568,7 +569,7 @@ func TestLabelsWithDifferentBatches(t *testing.T) {
 	cfg.HistogramBuckets = []float64{0.5, 1}
 	cfg.EnableTargetInfo = true
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -647,7 +648,7 @@ func TestTargetInfoEnabled(t *testing.T) {
 	cfg.EnableTargetInfo = true
 	cfg.HistogramBuckets = []float64{0.5, 1}
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -700,7 +701,7 @@ func TestTargetInfoDisabled(t *testing.T) {
 	cfg.EnableTargetInfo = false
 	cfg.HistogramBuckets = []float64{0.5, 1}
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -749,7 +750,7 @@ func TestTargetInfoWithEmptyKey(t *testing.T) {
 	cfg.EnableTargetInfo = true
 	cfg.HistogramBuckets = []float64{0.5, 1}
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -802,7 +803,7 @@ func TestTargetInfoWithEmptyValue(t *testing.T) {
 	cfg.EnableTargetInfo = true
 	cfg.HistogramBuckets = []float64{0.5, 1}
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -846,7 +847,7 @@ func TestTargetInfoWithAllEmptyValues(t *testing.T) {
 	cfg.EnableTargetInfo = true
 	cfg.HistogramBuckets = []float64{0.5, 1}
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -895,7 +896,7 @@ func TestTargetInfoWithExclusion(t *testing.T) {
 	cfg.TargetInfoExcludedDimensions = []string{"container", "container.id"}
 	cfg.HistogramBuckets = []float64{0.5, 1}
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -959,7 +960,7 @@ func TestTargetInfoSanitizeLabelName(t *testing.T) {
 	cfg.EnableTargetInfo = true
 	cfg.HistogramBuckets = []float64{0.5, 1}
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -1014,7 +1015,7 @@ func TestTargetInfoWithJobAndInstanceOnly(t *testing.T) {
 	cfg.HistogramBuckets = []float64{0.5, 1}
 	cfg.EnableTargetInfo = true
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -1055,7 +1056,7 @@ func TestTargetInfoNoJobAndNoInstance(t *testing.T) {
 	cfg.RegisterFlagsAndApplyDefaults("", nil)
 	cfg.HistogramBuckets = []float64{0.5, 1}
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -1104,7 +1105,7 @@ func TestTargetInfoWithDifferentBatches(t *testing.T) {
 	cfg.EnableTargetInfo = true
 	cfg.HistogramBuckets = []float64{0.5, 1}
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -1195,7 +1196,7 @@ func TestEnableInstanceLabelFalse(t *testing.T) {
 	cfg.Dimensions = []string{"http.method", "foo"}
 	cfg.EnableInstanceLabel = false
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -1315,7 +1316,7 @@ func TestEnableInstanceLabelUnset(t *testing.T) {
 	cfg.Dimensions = []string{"http.method", "foo"}
 	// cfg.EnableInstanceLabel = true // by default it is true
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -1466,7 +1467,7 @@ func TestSpanMetricsDimensionMapping(t *testing.T) {
 		},
 	}
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -1539,7 +1540,7 @@ func TestSpanMetricsDimensionMappingMissingLabels(t *testing.T) {
 		},
 	}
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -1596,7 +1597,7 @@ func TestSpanMetricsNegativeLatency(t *testing.T) {
 	cfg.RegisterFlagsAndApplyDefaults("", nil)
 	cfg.HistogramBuckets = []float64{0.5, 1}
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -1629,6 +1630,127 @@ func TestSpanMetricsNegativeLatency(t *testing.T) {
 	require.Equal(t, 0.0, testRegistry.Query("traces_spanmetrics_latency_sum", lbls), "sum")
 }
 
+func TestSpanMetricsRootDimensions(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+	filteredSpansCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "filtered", "span-metrics")
+	invalidUTF8SpanLabelsCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "invalid_utf8", "span-metrics")
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.RootDimensions = []string{"service", "span_name", "http.route"}
+
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	require.NoError(t, err)
+
+	batch := func(service string, spans ...*trace_v1.Span) *trace_v1.ResourceSpans {
+		return &trace_v1.ResourceSpans{
+			Resource: &resource_v1.Resource{
+				Attributes: []*common_v1.KeyValue{{
+					Key:   "service.name",
+					Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: service}},
+				}},
+			},
+			ScopeSpans: []*trace_v1.ScopeSpans{{Spans: spans}},
+		}
+	}
+	dbSpan := func(traceID byte) *trace_v1.Span {
+		return &trace_v1.Span{
+			TraceId:      []byte{traceID},
+			SpanId:       []byte{0x02},
+			ParentSpanId: []byte{0x01},
+			Name:         "SELECT",
+			Kind:         trace_v1.Span_SPAN_KIND_CLIENT,
+		}
+	}
+	rootSpan := &trace_v1.Span{
+		TraceId: []byte{0x01},
+		SpanId:  []byte{0x01},
+		Name:    "GET /users",
+		Kind:    trace_v1.Span_SPAN_KIND_SERVER,
+		Attributes: []*common_v1.KeyValue{{
+			Key:   "http.route",
+			Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: "/users"}},
+		}},
+	}
+
+	dbLabels := labels.FromMap(map[string]string{
+		"service":         "db",
+		"span_name":       "SELECT",
+		"span_kind":       "SPAN_KIND_CLIENT",
+		"status_code":     "STATUS_CODE_UNSET",
+		"root_service":    "frontend",
+		"root_span_name":  "GET /users",
+		"root_http_route": "/users",
+	})
+
+	// the span is buffered until its root span is received
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch("db", dbSpan(0x01))}})
+	assert.Equal(t, 0.0, testRegistry.Query("traces_spanmetrics_calls_total", dbLabels))
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch("frontend", rootSpan)}})
+	assert.Equal(t, 1.0, testRegistry.Query("traces_spanmetrics_calls_total", dbLabels))
+	assert.Equal(t, 1.0, testRegistry.Query("traces_spanmetrics_calls_total", labels.FromMap(map[string]string{
+		"service":         "frontend",
+		"span_name":       "GET /users",
+		"span_kind":       "SPAN_KIND_SERVER",
+		"status_code":     "STATUS_CODE_UNSET",
+		"root_service":    "frontend",
+		"root_span_name":  "GET /users",
+		"root_http_route": "/users",
+	})))
+
+	// spans received after the root span are recorded immediately
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch("db", dbSpan(0x01))}})
+	assert.Equal(t, 2.0, testRegistry.Query("traces_spanmetrics_calls_total", dbLabels))
+
+	// spans that don't receive their root span are recorded with unknown root dimensions
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch("db", dbSpan(0x02))}})
+	assert.Equal(t, 2, p.(*Processor).rootBuffer.len())
+
+	p.Shutdown(context.Background())
+	assert.Equal(t, 0, p.(*Processor).rootBuffer.len())
+	assert.Equal(t, 1.0, testRegistry.Query("traces_spanmetrics_calls_total", labels.FromMap(map[string]string{
+		"service":         "db",
+		"span_name":       "SELECT",
+		"span_kind":       "SPAN_KIND_CLIENT",
+		"status_code":     "STATUS_CODE_UNSET",
+		"root_service":    "unknown",
+		"root_span_name":  "unknown",
+		"root_http_route": "unknown",
+	})))
+}
+
+func TestSpanMetricsRootDimensions_maxSpansPerTrace(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+	filteredSpansCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "filtered", "span-metrics")
+	invalidUTF8SpanLabelsCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "invalid_utf8", "span-metrics")
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.IntrinsicDimensions = IntrinsicDimensions{SpanName: true}
+	cfg.RootDimensions = []string{"span_name"}
+	cfg.RootDimensionsMaxSpansPerTrace = 1
+
+	p, err := New(cfg, "test-root-overflow", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	overflow := metricRootDimensionsOverflowSpans.WithLabelValues("test-root-overflow")
+	before := testutil.ToFloat64(overflow)
+
+	span := func(id byte) *trace_v1.Span {
+		return &trace_v1.Span{TraceId: []byte{0x01}, SpanId: []byte{id}, ParentSpanId: []byte{0x01}, Name: "child"}
+	}
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{{
+		Resource:   &resource_v1.Resource{},
+		ScopeSpans: []*trace_v1.ScopeSpans{{Spans: []*trace_v1.Span{span(0x02), span(0x03), span(0x04)}}},
+	}}})
+
+	// the first span is buffered, the others are recorded right away with unknown root dimensions
+	assert.Equal(t, 2.0, testutil.ToFloat64(overflow)-before)
+	assert.Equal(t, 2.0, testRegistry.Query("traces_spanmetrics_calls_total", labels.FromStrings("span_name", "child", "root_span_name", "unknown")))
+}
+
 func withLe(lbls labels.Labels, le float64) labels.Labels {
 	lb := labels.NewBuilder(lbls)
 	lb = lb.Set(labels.BucketLabel, strconv.FormatFloat(le, 'f', -1, 64))
@@ -1729,7 +1851,7 @@ func benchmarkFilterPolicy(b *testing.B, policies []filterconfig.FilterPolicy, b
 	cfg.RegisterFlagsAndApplyDefaults("", nil)
 
 	cfg.FilterPolicies = policies
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(b, err)
 	defer p.Shutdown(context.Background())
 	b.ResetTimer()
@@ -1748,7 +1870,7 @@ func TestTargetInfoSkipsLabelsStartingWithNumber(t *testing.T) {
 	cfg.EnableTargetInfo = true
 	cfg.HistogramBuckets = []float64{0.5, 1}
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -1829,7 +1951,7 @@ func TestValidationErrors(t *testing.T) {
 	)
 
 	for _, tc := range testCases {
-		p, err := New(tc.cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+		p, err := New(tc.cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 		defer func() {
 			if p != nil {
 				p.Shutdown(t.Context())
@@ -1849,7 +1971,7 @@ func TestSpanMetricsTraceStateMultiplier(t *testing.T) {
 	cfg.HistogramBuckets = []float64{0.5, 1}
 	cfg.EnableTraceStateSpanMultiplier = true
 
-	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
+	p, err := New(cfg, "test", testRegistry, filteredSpansCounter, invalidUTF8SpanLabelsCounter)
 	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
@@ -0,0 +1,27 @@
+package traceqlmetrics
+
+import (
+	"flag"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/sharedconfig"
+)
+
+type Config struct {
+	// Metrics are the metrics generated from TraceQL metrics queries, e.g.
+	// { span.db.system = "postgres" } | quantile_over_time(duration, .99) by (span.db.name)
+	Metrics []sharedconfig.TraceQLMetric `yaml:"metrics"`
+
+	// Buckets for histograms in seconds, used if the query doesn't set explicit buckets.
+	HistogramBuckets []float64 `yaml:"histogram_buckets"`
+
+	// The histogram mode to select.
+	HistogramOverride registry.HistogramMode `yaml:"-"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
+	cfg.HistogramBuckets = prometheus.ExponentialBuckets(0.002, 2, 14)
+	cfg.HistogramOverride = registry.HistogramModeClassic
+}
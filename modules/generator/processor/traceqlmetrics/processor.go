@@ -0,0 +1,121 @@
+package traceqlmetrics
+
+import (
+	"context"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/tempo/modules/generator/processor"
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/modules/generator/validation"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	tempo_util "github.com/grafana/tempo/pkg/util"
+)
+
+// Processor generates metrics from TraceQL metrics queries. The queries are evaluated on the spans as
+// they are pushed, so the generated series can be used instead of running the queries as range queries.
+type Processor struct {
+	Cfg    Config
+	logger log.Logger
+
+	registry           registry.Registry
+	metrics            []*metric
+	invalidUTF8Counter prometheus.Counter
+}
+
+type metric struct {
+	name  string
+	query *traceql.GeneratorQuery
+	// labels are the label names of the by() attributes of the query
+	labels []string
+
+	counter   registry.Counter
+	histogram registry.Histogram
+}
+
+func New(cfg Config, reg registry.Registry, logger log.Logger, invalidUTF8Counter prometheus.Counter) (*Processor, error) {
+	if err := validation.ValidateTraceQLMetrics(cfg.Metrics); err != nil {
+		return nil, err
+	}
+
+	p := &Processor{
+		Cfg:                cfg,
+		logger:             logger,
+		registry:           reg,
+		invalidUTF8Counter: invalidUTF8Counter,
+	}
+
+	for _, m := range cfg.Metrics {
+		query, err := traceql.CompileGeneratorQuery(m.Query)
+		if err != nil {
+			return nil, err
+		}
+
+		pm := &metric{
+			name:  m.Name,
+			query: query,
+		}
+		for _, a := range query.By() {
+			pm.labels = append(pm.labels, validation.SanitizeLabelName(a.Name))
+		}
+
+		switch query.Type() {
+		case traceql.GeneratorQueryCounter:
+			pm.counter = reg.NewCounter(m.Name)
+		case traceql.GeneratorQueryHistogram:
+			buckets := query.Buckets()
+			if buckets == nil {
+				buckets = cfg.HistogramBuckets
+			}
+			pm.histogram = reg.NewHistogram(m.Name, buckets, cfg.HistogramOverride)
+		}
+
+		p.metrics = append(p.metrics, pm)
+	}
+
+	return p, nil
+}
+
+func (p *Processor) Name() string {
+	return processor.TraceQLMetricsName
+}
+
+func (p *Processor) PushSpans(_ context.Context, req *tempopb.PushSpansRequest) {
+	for _, m := range p.metrics {
+		err := m.query.Observe(req.Batches, func(traceID []byte, s traceql.Span, value float64) {
+			p.observe(m, traceID, s, value)
+		})
+		if err != nil {
+			level.Error(p.logger).Log("msg", "failed to evaluate traceql metric", "metric", m.name, "err", err)
+		}
+	}
+}
+
+func (p *Processor) observe(m *metric, traceID []byte, s traceql.Span, value float64) {
+	builder := p.registry.NewLabelBuilder()
+	for i, a := range m.query.By() {
+		if v, ok := s.AttributeFor(a); ok {
+			builder.Add(m.labels[i], v.EncodeToString(false))
+		}
+	}
+
+	labels, validUTF8 := builder.CloseAndBuildLabels()
+	if !validUTF8 {
+		p.invalidUTF8Counter.Inc()
+		return
+	}
+
+	if m.counter != nil {
+		// counters can't decrease
+		if value > 0 {
+			m.counter.Inc(labels, value)
+		}
+		return
+	}
+	m.histogram.ObserveWithExemplar(labels, value, tempo_util.TraceIDToHexString(traceID), 1)
+}
+
+func (p *Processor) Shutdown(_ context.Context) {}
@@ -0,0 +1,107 @@
+package traceqlmetrics
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/generator/processor"
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/sharedconfig"
+	"github.com/grafana/tempo/pkg/tempopb"
+	trace_v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestTraceQLMetrics(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Metrics = []sharedconfig.TraceQLMetric{
+		{Name: "test_calls_total", Query: `{ resource.service.name = "test-service" } | rate() by (resource.service.name)`},
+		{Name: "test_unmatched_total", Query: `{ resource.service.name = "other" } | count_over_time()`},
+		{Name: "test_latency", Query: `{ } | quantile_over_time(duration, .99) by (resource.service.name)`},
+		{Name: "test_latency_explicit", Query: `{ } | histogram_over_time(duration, buckets=[0.5, 2]) by (resource.service.name)`},
+	}
+
+	p, err := New(cfg, testRegistry, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
+	require.NoError(t, err)
+	require.Equal(t, processor.TraceQLMetricsName, p.Name())
+	defer p.Shutdown(context.TODO())
+
+	req := &tempopb.PushSpansRequest{
+		Batches: []*trace_v1.ResourceSpans{
+			test.MakeBatch(10, nil),
+			test.MakeBatch(10, nil),
+		},
+	}
+	p.PushSpans(context.Background(), req)
+
+	lbls := labels.FromMap(map[string]string{
+		"service_name": "test-service",
+	})
+
+	assert.Equal(t, 20.0, testRegistry.Query("test_calls_total", lbls))
+	assert.Equal(t, 0.0, testRegistry.Query("test_unmatched_total", labels.EmptyLabels()))
+
+	// the spans of test batches take 1 second
+	assert.Equal(t, 0.0, testRegistry.Query("test_latency_bucket", withLe(lbls, 0.512)))
+	assert.Equal(t, 20.0, testRegistry.Query("test_latency_bucket", withLe(lbls, 1.024)))
+	assert.Equal(t, 20.0, testRegistry.Query("test_latency_bucket", withLe(lbls, math.Inf(1))))
+	assert.Equal(t, 20.0, testRegistry.Query("test_latency_count", lbls))
+
+	assert.Equal(t, 0.0, testRegistry.Query("test_latency_explicit_bucket", withLe(lbls, 0.5)))
+	assert.Equal(t, 20.0, testRegistry.Query("test_latency_explicit_bucket", withLe(lbls, 2)))
+	assert.Equal(t, 20.0, testRegistry.Query("test_latency_explicit_count", lbls))
+}
+
+func TestTraceQLMetricsInvalidConfig(t *testing.T) {
+	tcs := []struct {
+		name    string
+		metrics []sharedconfig.TraceQLMetric
+		err     string
+	}{
+		{
+			name:    "invalid name",
+			metrics: []sharedconfig.TraceQLMetric{{Name: "", Query: `{ } | rate()`}},
+			err:     `traceql_metrics metric name "" is invalid`,
+		},
+		{
+			name: "duplicate name",
+			metrics: []sharedconfig.TraceQLMetric{
+				{Name: "calls", Query: `{ } | rate()`},
+				{Name: "calls", Query: `{ } | count_over_time()`},
+			},
+			err: `traceql_metrics metric name "calls" is not unique`,
+		},
+		{
+			name:    "unsupported function",
+			metrics: []sharedconfig.TraceQLMetric{{Name: "max_latency", Query: `{ } | max_over_time(duration)`}},
+			err:     `traceql_metrics metric "max_latency" has an invalid query: max_over_time is not supported by the metrics-generator`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{}
+			cfg.RegisterFlagsAndApplyDefaults("", nil)
+			cfg.Metrics = tc.metrics
+
+			_, err := New(cfg, registry.NewTestRegistry(), nil, prometheus.NewCounter(prometheus.CounterOpts{}))
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func withLe(lbls labels.Labels, le float64) labels.Labels {
+	lb := labels.NewBuilder(lbls)
+	lb = lb.Set(labels.BucketLabel, strconv.FormatFloat(le, 'f', -1, 64))
+	return lb.Labels()
+}
@@ -2,6 +2,7 @@ package registry
 
 import (
 	"flag"
+	"fmt"
 	"time"
 )
 
@@ -27,6 +28,13 @@ type Config struct {
 	// MaxLabelValueLength configures the maximum length of label values. Label values exceeding
 	// this limit will be truncated.
 	MaxLabelValueLength int `yaml:"max_label_value_length"`
+
+	// Temporality of counters and histograms, cumulative or delta. In delta mode, the increase since
+	// the previous collection is written and the series don't have the __metrics_gen_instance label,
+	// so multiple metrics-generators can write the same series and a downstream store sums them.
+	// Delta mode is only supported with the OTLP export, not with remote write. Defaults to
+	// cumulative.
+	Temporality string `yaml:"temporality"`
 }
 
 // RegisterFlagsAndApplyDefaults registers the flags.
@@ -35,10 +43,35 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 	cfg.StaleDuration = 15 * time.Minute
 	cfg.MaxLabelNameLength = 1024
 	cfg.MaxLabelValueLength = 2048
+	cfg.Temporality = TemporalityCumulative
+}
+
+func (cfg *Config) Validate() error {
+	// an empty value falls back to cumulative
+	switch cfg.Temporality {
+	case "", TemporalityCumulative, TemporalityDelta:
+	default:
+		return fmt.Errorf("invalid registry temporality: %s, valid values are %s and %s", cfg.Temporality, TemporalityCumulative, TemporalityDelta)
+	}
+	return nil
 }
 
+const (
+	TemporalityCumulative = "cumulative"
+	TemporalityDelta      = "delta"
+)
+
 const (
 	SpanNameSanitizationDisabled = ""
 	SpanNameSanitizationDryRun   = "dry_run"
 	SpanNameSanitizationEnabled  = "enabled"
 )
+
+const (
+	// MaxCardinalityPerLabelModeOverflow replaces all values of a label over the limit with
+	// __cardinality_overflow__.
+	MaxCardinalityPerLabelModeOverflow = ""
+	// MaxCardinalityPerLabelModeTopK keeps the most active values of a label over the limit and
+	// replaces the other values with __other__.
+	MaxCardinalityPerLabelModeTopK = "top_k"
+)
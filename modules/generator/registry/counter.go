@@ -22,6 +22,9 @@ type counter struct {
 	lifecycler Limiter
 
 	externalLabels map[string]string
+
+	// delta is set if the increase since the previous collection is written instead of the total
+	delta bool
 }
 
 type counterSeries struct {
@@ -48,13 +51,14 @@ func (co *counterSeries) registerSeenSeries() {
 	co.firstSeries.Store(false)
 }
 
-func newCounter(name string, lifecycler Limiter, externalLabels map[string]string, staleDuration time.Duration) *counter {
+func newCounter(name string, lifecycler Limiter, externalLabels map[string]string, staleDuration time.Duration, temporality string) *counter {
 	return &counter{
 		metricName:     name,
 		series:         make(map[uint64]*counterSeries),
 		seriesDemand:   NewCardinality(staleDuration, removeStaleSeriesInterval),
 		lifecycler:     lifecycler,
 		externalLabels: externalLabels,
+		delta:          temporality == TemporalityDelta,
 	}
 }
 
@@ -129,6 +133,20 @@ func (c *counter) collectMetrics(appender storage.Appender, timeMs int64) error
 	defer c.seriesMtx.RUnlock()
 
 	for _, s := range c.series {
+		if c.delta {
+			// series that haven't been updated since the previous collection are skipped, a
+			// downstream store sums the deltas of all metrics-generators
+			v := swapFloat64(s.value, 0)
+			if v == 0 {
+				continue
+			}
+			_, err := appender.Append(0, s.labels, timeMs, v)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
 		// If we are about to call Append for the first time on a series, we need
 		// to first insert a 0 value to allow Prometheus to start from a non-null
 		// value.
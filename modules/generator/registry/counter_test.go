@@ -21,7 +21,7 @@ func Test_counter(t *testing.T) {
 		},
 	}
 
-	c := newCounter("my_counter", lifecycler, map[string]string{}, 15*time.Minute)
+	c := newCounter("my_counter", lifecycler, map[string]string{}, 15*time.Minute, TemporalityCumulative)
 
 	c.Inc(buildTestLabels([]string{"label"}, []string{"value-1"}), 1.0)
 	c.Inc(buildTestLabels([]string{"label"}, []string{"value-2"}), 2.0)
@@ -64,7 +64,7 @@ func TestCounterDifferentLabels(t *testing.T) {
 		},
 	}
 
-	c := newCounter("my_counter", lifecycler, map[string]string{}, 15*time.Minute)
+	c := newCounter("my_counter", lifecycler, map[string]string{}, 15*time.Minute, TemporalityCumulative)
 
 	c.Inc(buildTestLabels([]string{"label"}, []string{"value-1"}), 1.0)
 	c.Inc(buildTestLabels([]string{"another_label"}, []string{"another_value"}), 2.0)
@@ -96,7 +96,7 @@ func Test_counter_cantAdd(t *testing.T) {
 		},
 	}
 
-	c := newCounter("my_counter", lifecycler, map[string]string{}, 15*time.Minute)
+	c := newCounter("my_counter", lifecycler, map[string]string{}, 15*time.Minute, TemporalityCumulative)
 
 	// allow adding new series
 	canAdd = true
@@ -140,7 +140,7 @@ func Test_counter_removeStaleSeries(t *testing.T) {
 		},
 	}
 
-	c := newCounter("my_counter", lifecycler, map[string]string{}, 15*time.Minute)
+	c := newCounter("my_counter", lifecycler, map[string]string{}, 15*time.Minute, TemporalityCumulative)
 
 	timeMs := time.Now().UnixMilli()
 	c.Inc(buildTestLabels([]string{"label"}, []string{"value-1"}), 1.0)
@@ -178,7 +178,7 @@ func Test_counter_removeStaleSeries(t *testing.T) {
 }
 
 func Test_counter_externalLabels(t *testing.T) {
-	c := newCounter("my_counter", noopLimiter, map[string]string{"external_label": "external_value"}, 15*time.Minute)
+	c := newCounter("my_counter", noopLimiter, map[string]string{"external_label": "external_value"}, 15*time.Minute, TemporalityCumulative)
 
 	c.Inc(buildTestLabels([]string{"label"}, []string{"value-1"}), 1.0)
 	c.Inc(buildTestLabels([]string{"label"}, []string{"value-2"}), 2.0)
@@ -195,7 +195,7 @@ func Test_counter_externalLabels(t *testing.T) {
 }
 
 func Test_counter_concurrencyDataRace(t *testing.T) {
-	c := newCounter("my_counter", noopLimiter, map[string]string{}, 15*time.Minute)
+	c := newCounter("my_counter", noopLimiter, map[string]string{}, 15*time.Minute, TemporalityCumulative)
 
 	end := make(chan struct{})
 
@@ -241,7 +241,7 @@ func Test_counter_concurrencyDataRace(t *testing.T) {
 }
 
 func Test_counter_concurrencyCorrectness(t *testing.T) {
-	c := newCounter("my_counter", noopLimiter, map[string]string{}, 15*time.Minute)
+	c := newCounter("my_counter", noopLimiter, map[string]string{}, 15*time.Minute, TemporalityCumulative)
 
 	var wg sync.WaitGroup
 	end := make(chan struct{})
@@ -278,6 +278,32 @@ func Test_counter_concurrencyCorrectness(t *testing.T) {
 	collectMetricAndAssert(t, c, collectionTimeMs, 1, expectedSamples, nil)
 }
 
+func Test_counter_delta(t *testing.T) {
+	c := newCounter("my_counter", noopLimiter, map[string]string{}, 15*time.Minute, TemporalityDelta)
+
+	c.Inc(buildTestLabels([]string{"label"}, []string{"value-1"}), 1.0)
+	c.Inc(buildTestLabels([]string{"label"}, []string{"value-2"}), 2.0)
+
+	// no leading zero, the first sample is the increase itself
+	collectionTimeMs := time.Now().UnixMilli()
+	expectedSamples := []sample{
+		newSample(map[string]string{"__name__": "my_counter", "label": "value-1"}, collectionTimeMs, 1),
+		newSample(map[string]string{"__name__": "my_counter", "label": "value-2"}, collectionTimeMs, 2),
+	}
+	collectMetricAndAssert(t, c, collectionTimeMs, 2, expectedSamples, nil)
+
+	c.Inc(buildTestLabels([]string{"label"}, []string{"value-2"}), 2.0)
+	c.Inc(buildTestLabels([]string{"label"}, []string{"value-3"}), 3.0)
+
+	// value-1 didn't change and is skipped
+	collectionTimeMs = time.Now().UnixMilli()
+	expectedSamples = []sample{
+		newSample(map[string]string{"__name__": "my_counter", "label": "value-2"}, collectionTimeMs, 2),
+		newSample(map[string]string{"__name__": "my_counter", "label": "value-3"}, collectionTimeMs, 3),
+	}
+	collectMetricAndAssert(t, c, collectionTimeMs, 3, expectedSamples, nil)
+}
+
 func collectMetricAndAssert(t *testing.T, m metric, collectionTimeMs int64, expectedActiveSeries int, expectedSamples []sample, expectedExemplars []exemplarSample) {
 	appender := &capturingAppender{}
 
@@ -299,7 +325,7 @@ func collectMetricAndAssert(t *testing.T, m metric, collectionTimeMs int64, expe
 }
 
 func Test_counter_demandTracking(t *testing.T) {
-	c := newCounter("my_counter", noopLimiter, map[string]string{}, 15*time.Minute)
+	c := newCounter("my_counter", noopLimiter, map[string]string{}, 15*time.Minute, TemporalityCumulative)
 
 	// Initially, demand should be 0
 	assert.Equal(t, 0, c.countSeriesDemand())
@@ -332,7 +358,7 @@ func Test_counter_demandVsActiveSeries(t *testing.T) {
 		},
 	}
 
-	c := newCounter("my_counter", lifecycler, map[string]string{}, 15*time.Minute)
+	c := newCounter("my_counter", lifecycler, map[string]string{}, 15*time.Minute, TemporalityCumulative)
 
 	// Add series up to a point
 	for i := 0; i < 30; i++ {
@@ -361,7 +387,7 @@ func Test_counter_demandVsActiveSeries(t *testing.T) {
 }
 
 func Test_counter_demandDecay(t *testing.T) {
-	c := newCounter("my_counter", noopLimiter, map[string]string{}, 15*time.Minute)
+	c := newCounter("my_counter", noopLimiter, map[string]string{}, 15*time.Minute, TemporalityCumulative)
 
 	// Add series
 	for i := 0; i < 40; i++ {
@@ -394,7 +420,7 @@ func Test_counter_onUpdate(t *testing.T) {
 		},
 	}
 
-	c := newCounter("my_counter", lifecycler, map[string]string{}, 15*time.Minute)
+	c := newCounter("my_counter", lifecycler, map[string]string{}, 15*time.Minute, TemporalityCumulative)
 
 	// Add initial series
 	c.Inc(buildTestLabels([]string{"label"}, []string{"value-1"}), 1.0)
@@ -1,10 +1,13 @@
 package registry
 
 import (
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/grafana/tempo/pkg/drain"
+	"github.com/grafana/tempo/pkg/sharedconfig"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/prometheus/model/labels"
@@ -28,13 +31,24 @@ var (
 // sanitizeModeFunc returns the current span name sanitization mode for the tenant.
 type sanitizeModeFunc func(tenant string) string
 
+// sanitizeRulesFunc returns the current span name sanitization rules for the tenant.
+type sanitizeRulesFunc func(tenant string) []sharedconfig.SpanNameSanitizationRule
+
+// DrainSanitizer sanitizes span names. The span name sanitization rules of the tenant are evaluated
+// first, span names not matching any rule are clustered with drain.
 type DrainSanitizer struct {
 	mtx    sync.Mutex
 	drain  *drain.Drain
 	demand *Cardinality
 
-	tenant        string
-	sanitizeModeF sanitizeModeFunc
+	tenant         string
+	sanitizeModeF  sanitizeModeFunc
+	sanitizeRulesF sanitizeRulesFunc
+
+	// rules is the last seen configuration of the rules, compiledRules are recompiled when it
+	// changes. Must be accessed holding lock.
+	rules         []sharedconfig.SpanNameSanitizationRule
+	compiledRules []*spanNameRule
 
 	metricTotalSpansSanitized prometheus.Counter
 	demandGauge               prometheus.Gauge
@@ -46,11 +60,12 @@ type DrainSanitizer struct {
 	pruneChan        <-chan time.Time
 }
 
-func NewDrainSanitizer(tenant string, sanitizeModeF sanitizeModeFunc, staleDuration time.Duration) *DrainSanitizer {
+func NewDrainSanitizer(tenant string, sanitizeModeF sanitizeModeFunc, sanitizeRulesF sanitizeRulesFunc, staleDuration time.Duration) *DrainSanitizer {
 	return &DrainSanitizer{
 		drain:                     drain.New(tenant, drain.DefaultConfig()),
 		tenant:                    tenant,
 		sanitizeModeF:             sanitizeModeF,
+		sanitizeRulesF:            sanitizeRulesF,
 		metricTotalSpansSanitized: metricTotalSpansSanitized.WithLabelValues(tenant),
 		demand:                    NewCardinality(staleDuration, removeStaleSeriesInterval),
 		demandGauge:               metricPostSanitizationDemand.WithLabelValues(tenant),
@@ -63,7 +78,8 @@ func (s *DrainSanitizer) Sanitize(lbls labels.Labels) labels.Labels {
 	// Check the override at runtime so that changes to the sanitization mode override
 	// take effect without restarting the generator.
 	mode := s.sanitizeModeF(s.tenant)
-	if mode == SpanNameSanitizationDisabled {
+	rules := s.sanitizeRulesF(s.tenant)
+	if mode == SpanNameSanitizationDisabled && len(rules) == 0 {
 		return lbls
 	}
 
@@ -73,6 +89,20 @@ func (s *DrainSanitizer) Sanitize(lbls labels.Labels) labels.Labels {
 	defer s.mtx.Unlock()
 
 	spanName := lbls.Get(labelSpanName)
+
+	// the rules take precedence over drain, a span name matching a rule is not clustered
+	if newSpanName, ok := s.applyRules(rules, spanName); ok {
+		if newSpanName == spanName {
+			s.demand.Insert(lbls.Hash())
+			return lbls
+		}
+		return s.setSpanName(lbls, newSpanName, mode)
+	}
+
+	if mode == SpanNameSanitizationDisabled {
+		return lbls
+	}
+
 	cluster := s.drain.Train(spanName)
 	// drain has various limits to prevent excessive memory usage, etc. in these
 	// cases, we will just return the original labels.
@@ -90,6 +120,11 @@ func (s *DrainSanitizer) Sanitize(lbls labels.Labels) labels.Labels {
 		return lbls
 	}
 
+	return s.setSpanName(lbls, newSpanName, mode)
+}
+
+// setSpanName returns the labels with the sanitized span name. Must be called holding lock.
+func (s *DrainSanitizer) setSpanName(lbls labels.Labels, newSpanName string, mode string) labels.Labels {
 	s.metricTotalSpansSanitized.Inc()
 	builder := labels.NewBuilder(lbls)
 	builder.Set(labelSpanName, newSpanName)
@@ -105,6 +140,99 @@ func (s *DrainSanitizer) Sanitize(lbls labels.Labels) labels.Labels {
 	return newLbls
 }
 
+// applyRules returns the span name rewritten by the first matching rule. Must be called holding
+// lock.
+func (s *DrainSanitizer) applyRules(rules []sharedconfig.SpanNameSanitizationRule, spanName string) (string, bool) {
+	// series without a span name, don't add one
+	if spanName == "" {
+		return "", false
+	}
+
+	s.updateRules(rules)
+
+	for _, r := range s.compiledRules {
+		if newSpanName, ok := r.apply(spanName); ok {
+			return newSpanName, true
+		}
+	}
+	return "", false
+}
+
+// updateRules recompiles the rules if they have changed. Must be called holding lock.
+func (s *DrainSanitizer) updateRules(rules []sharedconfig.SpanNameSanitizationRule) {
+	if slices.Equal(s.rules, rules) {
+		return
+	}
+
+	s.rules = slices.Clone(rules)
+	s.compiledRules = make([]*spanNameRule, 0, len(rules))
+	for _, rule := range rules {
+		compiled, err := compileSpanNameRule(rule)
+		if err != nil {
+			// invalid rules are rejected by the overrides validation, keep it to report the error
+			compiled = &spanNameRule{rule: rule, err: err}
+		}
+		s.compiledRules = append(s.compiledRules, compiled)
+	}
+}
+
+// SpanNameSanitizationStatus is the state of the span name sanitization of a tenant.
+type SpanNameSanitizationStatus struct {
+	Mode     string                `json:"mode"`
+	Rules    []SpanNameRuleStatus  `json:"rules"`
+	Clusters []SpanNameClusterInfo `json:"clusters"`
+}
+
+// SpanNameRuleStatus is a span name sanitization rule and the number of span names it rewrote.
+type SpanNameRuleStatus struct {
+	sharedconfig.SpanNameSanitizationRule
+	Hits  uint64 `json:"hits"`
+	Error string `json:"error,omitempty"`
+}
+
+// SpanNameClusterInfo is a span name pattern found by drain and the number of span names in it.
+type SpanNameClusterInfo struct {
+	Pattern string `json:"pattern"`
+	Size    int    `json:"size"`
+}
+
+// Status returns the rules and their hits since they were last changed, and the drain clusters,
+// largest first.
+func (s *DrainSanitizer) Status() SpanNameSanitizationStatus {
+	mode := s.sanitizeModeF(s.tenant)
+	rules := s.sanitizeRulesF(s.tenant)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.updateRules(rules)
+
+	status := SpanNameSanitizationStatus{
+		Mode:     mode,
+		Rules:    make([]SpanNameRuleStatus, 0, len(s.compiledRules)),
+		Clusters: []SpanNameClusterInfo{},
+	}
+	for _, r := range s.compiledRules {
+		rs := SpanNameRuleStatus{SpanNameSanitizationRule: r.rule, Hits: r.hits}
+		if r.err != nil {
+			rs.Error = r.err.Error()
+		}
+		status.Rules = append(status.Rules, rs)
+	}
+
+	for _, c := range s.drain.Clusters() {
+		status.Clusters = append(status.Clusters, SpanNameClusterInfo{Pattern: c.String(), Size: c.Size})
+	}
+	sort.Slice(status.Clusters, func(i, j int) bool {
+		if status.Clusters[i].Size != status.Clusters[j].Size {
+			return status.Clusters[i].Size > status.Clusters[j].Size
+		}
+		return status.Clusters[i].Pattern < status.Clusters[j].Pattern
+	})
+
+	return status
+}
+
 func (s *DrainSanitizer) doPeriodicMaintenance() {
 	select {
 	case <-s.demandUpdateChan:
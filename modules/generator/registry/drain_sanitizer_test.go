@@ -5,12 +5,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/tempo/pkg/sharedconfig"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/require"
 )
 
+func noSanitizeRules(string) []sharedconfig.SpanNameSanitizationRule { return nil }
+
 func newTestDrainSanitizer(mode string) *DrainSanitizer {
-	return NewDrainSanitizer("test-tenant", func(string) string { return mode }, 15*time.Minute)
+	return NewDrainSanitizer("test-tenant", func(string) string { return mode }, noSanitizeRules, 15*time.Minute)
 }
 
 func TestDrainSanitizer_PatternDetection(t *testing.T) {
@@ -61,7 +64,7 @@ func TestDrainSanitizer_RuntimeModeToggle(t *testing.T) {
 	t.Parallel()
 
 	mode := SpanNameSanitizationEnabled
-	sanitizer := NewDrainSanitizer("test-tenant", func(string) string { return mode }, 15*time.Minute)
+	sanitizer := NewDrainSanitizer("test-tenant", func(string) string { return mode }, noSanitizeRules, 15*time.Minute)
 
 	// Train the drain tree with similar span names to establish a pattern
 	sanitizer.Sanitize(labels.FromStrings("span_name", "GET /api/users/123"))
@@ -249,3 +252,58 @@ func TestDrainSanitizer_FullSanitizedOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestDrainSanitizer_Rules(t *testing.T) {
+	t.Parallel()
+
+	mode := SpanNameSanitizationEnabled
+	rules := []sharedconfig.SpanNameSanitizationRule{
+		{Template: "/api/users/{id}"},
+		{Pattern: `^SELECT .* FROM (\w+)$`, Replacement: "SELECT $1"},
+	}
+	sanitizer := NewDrainSanitizer("test-tenant", func(string) string { return mode }, func(string) []sharedconfig.SpanNameSanitizationRule { return rules }, 15*time.Minute)
+
+	result := sanitizer.Sanitize(labels.FromStrings("span_name", "GET /api/users/123", "service", "api"))
+	require.Equal(t, "GET /api/users/{id}", result.Get("span_name"))
+	require.Equal(t, "api", result.Get("service"))
+
+	result = sanitizer.Sanitize(labels.FromStrings("span_name", "SELECT id, name FROM users"))
+	require.Equal(t, "SELECT users", result.Get("span_name"))
+
+	// span names matching a rule are not clustered
+	sanitizer.Sanitize(labels.FromStrings("span_name", "GET /api/users/456"))
+	status := sanitizer.Status()
+	require.Empty(t, status.Clusters)
+	require.Equal(t, uint64(2), status.Rules[0].Hits)
+	require.Equal(t, uint64(1), status.Rules[1].Hits)
+
+	// span names not matching any rule are clustered by drain
+	sanitizer.Sanitize(labels.FromStrings("span_name", "GET /api/orders/1"))
+	result = sanitizer.Sanitize(labels.FromStrings("span_name", "GET /api/orders/2"))
+	require.Equal(t, "GET /api/orders/<_>", result.Get("span_name"))
+	status = sanitizer.Status()
+	require.Equal(t, []SpanNameClusterInfo{{Pattern: "GET /api/orders/<_>", Size: 2}}, status.Clusters)
+
+	// rules are applied if drain is disabled
+	mode = SpanNameSanitizationDisabled
+	result = sanitizer.Sanitize(labels.FromStrings("span_name", "GET /api/users/789"))
+	require.Equal(t, "GET /api/users/{id}", result.Get("span_name"))
+	result = sanitizer.Sanitize(labels.FromStrings("span_name", "GET /api/orders/3"))
+	require.Equal(t, "GET /api/orders/3", result.Get("span_name"))
+
+	// in dry-run mode, the span names are not changed
+	mode = SpanNameSanitizationDryRun
+	result = sanitizer.Sanitize(labels.FromStrings("span_name", "GET /api/users/789"))
+	require.Equal(t, "GET /api/users/789", result.Get("span_name"))
+
+	// series without a span name are not changed
+	rules = []sharedconfig.SpanNameSanitizationRule{{Pattern: ".*", Replacement: "all"}}
+	mode = SpanNameSanitizationEnabled
+	result = sanitizer.Sanitize(labels.FromStrings("service", "api"))
+	require.Equal(t, labels.FromStrings("service", "api"), result)
+
+	// hits are reset when the rules change
+	status = sanitizer.Status()
+	require.Len(t, status.Rules, 1)
+	require.Equal(t, uint64(0), status.Rules[0].Hits)
+}
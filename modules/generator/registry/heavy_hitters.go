@@ -0,0 +1,106 @@
+package registry
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// heavyHitters is a streaming top-K sketch based on the Space-Saving algorithm. It tracks at most
+// capacity values. When a new value arrives and the sketch is full, the least frequent value is
+// replaced and the new value inherits its count. Values that occur more often than 1/capacity of
+// the time are guaranteed to be tracked.
+//
+// heavyHitters is not safe for concurrent use.
+type heavyHitters struct {
+	capacity int
+	entries  map[string]*heavyHitter
+	// h is a min-heap on the count of the entries
+	h heavyHittersHeap
+}
+
+type heavyHitter struct {
+	value string
+	count uint64
+	index int
+}
+
+func newHeavyHitters(capacity int) *heavyHitters {
+	return &heavyHitters{
+		capacity: capacity,
+		entries:  make(map[string]*heavyHitter, capacity),
+	}
+}
+
+// Insert counts one occurrence of the value.
+func (hh *heavyHitters) Insert(value string) {
+	if e, ok := hh.entries[value]; ok {
+		e.count++
+		heap.Fix(&hh.h, e.index)
+		return
+	}
+
+	if len(hh.h) < hh.capacity {
+		e := &heavyHitter{value: value, count: 1}
+		hh.entries[value] = e
+		heap.Push(&hh.h, e)
+		return
+	}
+
+	// replace the least frequent value, the new value inherits its count
+	minEntry := hh.h[0]
+	delete(hh.entries, minEntry.value)
+	minEntry.value = value
+	minEntry.count++
+	hh.entries[value] = minEntry
+	heap.Fix(&hh.h, 0)
+}
+
+// Top returns the k most frequent values.
+func (hh *heavyHitters) Top(k int) map[string]struct{} {
+	entries := make([]*heavyHitter, len(hh.h))
+	copy(entries, hh.h)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].count > entries[j].count
+	})
+
+	if len(entries) > k {
+		entries = entries[:k]
+	}
+
+	top := make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		top[e.value] = struct{}{}
+	}
+	return top
+}
+
+// Reset removes all values from the sketch.
+func (hh *heavyHitters) Reset() {
+	clear(hh.entries)
+	hh.h = hh.h[:0]
+}
+
+type heavyHittersHeap []*heavyHitter
+
+func (h heavyHittersHeap) Len() int           { return len(h) }
+func (h heavyHittersHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+func (h heavyHittersHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *heavyHittersHeap) Push(x any) {
+	e := x.(*heavyHitter)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *heavyHittersHeap) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
@@ -0,0 +1,30 @@
+package registry
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeavyHitters(t *testing.T) {
+	hh := newHeavyHitters(8)
+
+	// a few frequent values mixed with many values that occur once
+	for i := 0; i < 100; i++ {
+		hh.Insert("/api/users")
+		if i%2 == 0 {
+			hh.Insert("/api/orders")
+		}
+		hh.Insert(fmt.Sprintf("/api/items/%d", i))
+	}
+
+	require.Equal(t, map[string]struct{}{"/api/users": {}, "/api/orders": {}}, hh.Top(2))
+	require.Len(t, hh.Top(10), 8, "no more values than the capacity are tracked")
+
+	hh.Reset()
+	require.Empty(t, hh.Top(2))
+
+	hh.Insert("/api/items/1")
+	require.Equal(t, map[string]struct{}{"/api/items/1": {}}, hh.Top(2))
+}
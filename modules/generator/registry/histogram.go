@@ -32,6 +32,10 @@ type histogram struct {
 	lifecycler Limiter
 
 	traceIDLabelName string
+
+	// delta is set if the observations since the previous collection are written instead of the
+	// totals
+	delta bool
 }
 
 type histogramSeries struct {
@@ -67,7 +71,7 @@ var (
 	_ metric    = (*histogram)(nil)
 )
 
-func newHistogram(name string, buckets []float64, lifecycler Limiter, traceIDLabelName string, externalLabels map[string]string, staleDuration time.Duration) *histogram {
+func newHistogram(name string, buckets []float64, lifecycler Limiter, traceIDLabelName string, externalLabels map[string]string, staleDuration time.Duration, temporality string) *histogram {
 	if traceIDLabelName == "" {
 		traceIDLabelName = "traceID"
 	}
@@ -92,6 +96,7 @@ func newHistogram(name string, buckets []float64, lifecycler Limiter, traceIDLab
 		lifecycler:       lifecycler,
 		traceIDLabelName: traceIDLabelName,
 		externalLabels:   externalLabels,
+		delta:            temporality == TemporalityDelta,
 	}
 }
 
@@ -179,6 +184,14 @@ func (h *histogram) collectMetrics(appender storage.Appender, timeMs int64) erro
 	defer h.seriesMtx.Unlock()
 
 	for _, s := range h.series {
+		if h.delta {
+			err := h.collectDelta(appender, timeMs, s)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
 		// If we are about to call Append for the first time on a series,
 		// we need to first insert a 0 value to allow Prometheus to start from a non-null value.
 		if s.isNew() {
@@ -246,6 +259,48 @@ func (h *histogram) collectMetrics(appender storage.Appender, timeMs int64) erro
 	return nil
 }
 
+// collectDelta appends the observations since the previous collection and resets the series.
+// Series without observations are skipped. Must be called holding the series lock, so no
+// observations are added while the series is reset.
+func (h *histogram) collectDelta(appender storage.Appender, timeMs int64, s *histogramSeries) error {
+	count := swapFloat64(s.count, 0)
+	sum := swapFloat64(s.sum, 0)
+	if count == 0 {
+		return nil
+	}
+
+	_, err := appender.Append(0, s.sumLabels, timeMs, sum)
+	if err != nil {
+		return err
+	}
+	_, err = appender.Append(0, s.countLabels, timeMs, count)
+	if err != nil {
+		return err
+	}
+
+	for i := range h.bucketLabels {
+		ref, err := appender.Append(0, s.bucketLabels[i], timeMs, swapFloat64(s.buckets[i], 0))
+		if err != nil {
+			return err
+		}
+
+		ex := s.exemplars[i].Swap("")
+		if ex == "" {
+			continue
+		}
+		_, err = appender.AppendExemplar(ref, s.bucketLabels[i], exemplar.Exemplar{
+			Labels: labels.New(labels.Label{Name: h.traceIDLabelName, Value: ex}),
+			Value:  s.exemplarValues[i].Load(),
+			Ts:     timeMs,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (h *histogram) countActiveSeries() int {
 	h.seriesMtx.Lock()
 	defer h.seriesMtx.Unlock()
@@ -22,7 +22,7 @@ func Test_histogram(t *testing.T) {
 		},
 	}
 
-	h := newHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "trace_id", nil, 15*time.Minute)
+	h := newHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "trace_id", nil, 15*time.Minute, TemporalityCumulative)
 
 	h.ObserveWithExemplar(buildTestLabels([]string{"label"}, []string{"value-1"}), 1.0, "trace-1", 1.0)
 	h.ObserveWithExemplar(buildTestLabels([]string{"label"}, []string{"value-2"}), 1.5, "trace-2", 1.0)
@@ -152,6 +152,61 @@ func Test_histogram(t *testing.T) {
 	collectMetricAndAssert(t, h, collectionTimeMs, 15, expectedSamples, expectedExemplars)
 }
 
+func Test_histogram_delta(t *testing.T) {
+	h := newHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "trace_id", nil, 15*time.Minute, TemporalityDelta)
+
+	h.ObserveWithExemplar(buildTestLabels([]string{"label"}, []string{"value-1"}), 1.0, "trace-1", 1.0)
+	h.ObserveWithExemplar(buildTestLabels([]string{"label"}, []string{"value-2"}), 1.5, "trace-2", 1.0)
+
+	// no leading zeros, the first samples are the observations themselves
+	collectionTimeMs := time.Now().UnixMilli()
+	expectedSamples := []sample{
+		newSample(map[string]string{"__name__": "my_histogram_count", "label": "value-1"}, collectionTimeMs, 1),
+		newSample(map[string]string{"__name__": "my_histogram_sum", "label": "value-1"}, collectionTimeMs, 1),
+		newSample(map[string]string{"__name__": "my_histogram_bucket", "label": "value-1", "le": "1"}, collectionTimeMs, 1),
+		newSample(map[string]string{"__name__": "my_histogram_bucket", "label": "value-1", "le": "2"}, collectionTimeMs, 1),
+		newSample(map[string]string{"__name__": "my_histogram_bucket", "label": "value-1", "le": "+Inf"}, collectionTimeMs, 1),
+		newSample(map[string]string{"__name__": "my_histogram_count", "label": "value-2"}, collectionTimeMs, 1),
+		newSample(map[string]string{"__name__": "my_histogram_sum", "label": "value-2"}, collectionTimeMs, 1.5),
+		newSample(map[string]string{"__name__": "my_histogram_bucket", "label": "value-2", "le": "1"}, collectionTimeMs, 0),
+		newSample(map[string]string{"__name__": "my_histogram_bucket", "label": "value-2", "le": "2"}, collectionTimeMs, 1),
+		newSample(map[string]string{"__name__": "my_histogram_bucket", "label": "value-2", "le": "+Inf"}, collectionTimeMs, 1),
+	}
+	expectedExemplars := []exemplarSample{
+		newExemplar(map[string]string{"__name__": "my_histogram_bucket", "label": "value-1", "le": "1"}, exemplar.Exemplar{
+			Labels: labels.FromMap(map[string]string{"trace_id": "trace-1"}),
+			Value:  1.0,
+			Ts:     collectionTimeMs,
+		}),
+		newExemplar(map[string]string{"__name__": "my_histogram_bucket", "label": "value-2", "le": "2"}, exemplar.Exemplar{
+			Labels: labels.FromMap(map[string]string{"trace_id": "trace-2"}),
+			Value:  1.5,
+			Ts:     collectionTimeMs,
+		}),
+	}
+	collectMetricAndAssert(t, h, collectionTimeMs, 10, expectedSamples, expectedExemplars)
+
+	h.ObserveWithExemplar(buildTestLabels([]string{"label"}, []string{"value-2"}), 2.5, "trace-2.2", 1.0)
+
+	// value-1 didn't receive observations and is skipped, exemplars are only written once
+	collectionTimeMs = time.Now().UnixMilli()
+	expectedSamples = []sample{
+		newSample(map[string]string{"__name__": "my_histogram_count", "label": "value-2"}, collectionTimeMs, 1),
+		newSample(map[string]string{"__name__": "my_histogram_sum", "label": "value-2"}, collectionTimeMs, 2.5),
+		newSample(map[string]string{"__name__": "my_histogram_bucket", "label": "value-2", "le": "1"}, collectionTimeMs, 0),
+		newSample(map[string]string{"__name__": "my_histogram_bucket", "label": "value-2", "le": "2"}, collectionTimeMs, 0),
+		newSample(map[string]string{"__name__": "my_histogram_bucket", "label": "value-2", "le": "+Inf"}, collectionTimeMs, 1),
+	}
+	expectedExemplars = []exemplarSample{
+		newExemplar(map[string]string{"__name__": "my_histogram_bucket", "label": "value-2", "le": "+Inf"}, exemplar.Exemplar{
+			Labels: labels.FromMap(map[string]string{"trace_id": "trace-2.2"}),
+			Value:  2.5,
+			Ts:     collectionTimeMs,
+		}),
+	}
+	collectMetricAndAssert(t, h, collectionTimeMs, 10, expectedSamples, expectedExemplars)
+}
+
 func Test_histogram_cantAdd(t *testing.T) {
 	canAdd := false
 	overflowLabels := labels.FromStrings("metric_overflow", "true")
@@ -166,7 +221,7 @@ func Test_histogram_cantAdd(t *testing.T) {
 		},
 	}
 
-	h := newHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "", nil, 15*time.Minute)
+	h := newHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "", nil, 15*time.Minute, TemporalityCumulative)
 
 	// allow adding new series
 	canAdd = true
@@ -238,7 +293,7 @@ func Test_histogram_removeStaleSeries(t *testing.T) {
 		},
 	}
 
-	h := newHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "", nil, 15*time.Minute)
+	h := newHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "", nil, 15*time.Minute, TemporalityCumulative)
 
 	timeMs := time.Now().UnixMilli()
 	h.ObserveWithExemplar(buildTestLabels([]string{"label"}, []string{"value-1"}), 1.0, "", 1.0)
@@ -296,7 +351,7 @@ func Test_histogram_removeStaleSeries(t *testing.T) {
 func Test_histogram_externalLabels(t *testing.T) {
 	extLabels := map[string]string{"external_label": "external_value"}
 
-	h := newHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", extLabels, 15*time.Minute)
+	h := newHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", extLabels, 15*time.Minute, TemporalityCumulative)
 
 	h.ObserveWithExemplar(buildTestLabels([]string{"label"}, []string{"value-1"}), 1.0, "", 1.0)
 	h.ObserveWithExemplar(buildTestLabels([]string{"label"}, []string{"value-2"}), 1.5, "", 1.0)
@@ -327,7 +382,7 @@ func Test_histogram_externalLabels(t *testing.T) {
 }
 
 func Test_histogram_concurrencyDataRace(t *testing.T) {
-	h := newHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", nil, 15*time.Minute)
+	h := newHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", nil, 15*time.Minute, TemporalityCumulative)
 
 	end := make(chan struct{})
 
@@ -373,7 +428,7 @@ func Test_histogram_concurrencyDataRace(t *testing.T) {
 }
 
 func Test_histogram_concurrencyCorrectness(t *testing.T) {
-	h := newHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", nil, 15*time.Minute)
+	h := newHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", nil, 15*time.Minute, TemporalityCumulative)
 
 	var wg sync.WaitGroup
 	end := make(chan struct{})
@@ -418,7 +473,7 @@ func Test_histogram_concurrencyCorrectness(t *testing.T) {
 }
 
 func Test_histogram_span_multiplier(t *testing.T) {
-	h := newHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", nil, 15*time.Minute)
+	h := newHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", nil, 15*time.Minute, TemporalityCumulative)
 	h.ObserveWithExemplar(buildTestLabels([]string{"label"}, []string{"value-1"}), 1.0, "", 1.5)
 	h.ObserveWithExemplar(buildTestLabels([]string{"label"}, []string{"value-1"}), 2.0, "", 5)
 
@@ -439,7 +494,7 @@ func Test_histogram_span_multiplier(t *testing.T) {
 }
 
 func Test_histogram_demandTracking(t *testing.T) {
-	h := newHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", nil, 15*time.Minute)
+	h := newHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", nil, 15*time.Minute, TemporalityCumulative)
 
 	// Initially, demand should be 0
 	assert.Equal(t, 0, h.countSeriesDemand())
@@ -463,15 +518,15 @@ func Test_histogram_demandTracking(t *testing.T) {
 
 func Test_histogram_activeSeriesPerHistogramSerie(t *testing.T) {
 	// Test with 2 buckets (creates: sum, count, bucket1, bucket2, +Inf)
-	h := newHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", nil, 15*time.Minute)
+	h := newHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", nil, 15*time.Minute, TemporalityCumulative)
 	assert.Equal(t, uint32(5), h.activeSeriesPerHistogramSerie(), "should be sum + count + 3 buckets")
 
 	// Test with 3 buckets
-	h2 := newHistogram("my_histogram", []float64{1.0, 2.0, 3.0}, noopLimiter, "", nil, 15*time.Minute)
+	h2 := newHistogram("my_histogram", []float64{1.0, 2.0, 3.0}, noopLimiter, "", nil, 15*time.Minute, TemporalityCumulative)
 	assert.Equal(t, uint32(6), h2.activeSeriesPerHistogramSerie(), "should be sum + count + 4 buckets")
 
 	// Test with no buckets (still has +Inf)
-	h3 := newHistogram("my_histogram", []float64{}, noopLimiter, "", nil, 15*time.Minute)
+	h3 := newHistogram("my_histogram", []float64{}, noopLimiter, "", nil, 15*time.Minute, TemporalityCumulative)
 	assert.Equal(t, uint32(3), h3.activeSeriesPerHistogramSerie(), "should be sum + count + +Inf bucket")
 }
 
@@ -488,7 +543,7 @@ func Test_histogram_demandVsActiveSeries(t *testing.T) {
 		},
 	}
 
-	h := newHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "", nil, 15*time.Minute)
+	h := newHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "", nil, 15*time.Minute, TemporalityCumulative)
 
 	// Add some histogram series
 	for i := 0; i < 10; i++ {
@@ -46,6 +46,10 @@ type nativeHistogram struct {
 	// The tenant for this registry instance is received at create time and does not change.
 	tenant string
 
+	// delta is set if the observations since the previous collection are written instead of the
+	// totals
+	delta bool
+
 	externalLabels map[string]string
 
 	// classic
@@ -59,8 +63,10 @@ type nativeHistogramSeries struct {
 	lb            *labels.Builder
 	labels        labels.Labels
 	promHistogram prometheus.Histogram
-	lastUpdated   int64
-	histogram     *dto.Histogram
+	// opts are used to recreate promHistogram after every collection in delta mode
+	opts        prometheus.HistogramOpts
+	lastUpdated int64
+	histogram   *dto.Histogram
 
 	// firstSeries is used to track if this series is new to the counter.
 	// This is used in classic histograms to ensure that new counters begin with 0.
@@ -90,7 +96,7 @@ var (
 	_ metric    = (*nativeHistogram)(nil)
 )
 
-func newNativeHistogram(name string, buckets []float64, lifecycler Limiter, traceIDLabelName string, histogramOverride HistogramMode, externalLabels map[string]string, tenant string, overrides Overrides, staleDuration time.Duration) *nativeHistogram {
+func newNativeHistogram(name string, buckets []float64, lifecycler Limiter, traceIDLabelName string, histogramOverride HistogramMode, externalLabels map[string]string, tenant string, overrides Overrides, staleDuration time.Duration, temporality string) *nativeHistogram {
 	if traceIDLabelName == "" {
 		traceIDLabelName = "traceID"
 	}
@@ -106,6 +112,7 @@ func newNativeHistogram(name string, buckets []float64, lifecycler Limiter, trac
 		externalLabels:    externalLabels,
 		overrides:         overrides,
 		tenant:            tenant,
+		delta:             temporality == TemporalityDelta,
 
 		// classic
 		nameCount:  fmt.Sprintf("%s_count", name),
@@ -165,8 +172,10 @@ func (h *nativeHistogram) newSeries(lbls labels.Labels, value float64, traceID s
 
 	newSeries := &nativeHistogramSeries{
 		promHistogram: prometheus.NewHistogram(nativeOpts),
+		opts:          nativeOpts,
 		lastUpdated:   0,
-		firstSeries:   atomic.NewBool(true),
+		// the zero sample is only needed for cumulative series
+		firstSeries:   atomic.NewBool(!h.delta),
 		overridesHash: hsh,
 	}
 
@@ -229,6 +238,16 @@ func (h *nativeHistogram) collectMetrics(appender storage.Appender, timeMs int64
 		// the exemplars.
 		s.histogram = encodedMetric.GetHistogram()
 
+		if h.delta {
+			// series without observations since the previous collection are skipped
+			if s.histogram.GetSampleCount() == 0 && s.histogram.GetSampleCountFloat() == 0 {
+				continue
+			}
+			// start over, the next collection only contains the new observations. The series
+			// lock is held, so no observations are lost.
+			s.promHistogram = prometheus.NewHistogram(s.opts)
+		}
+
 		// If we are in "both" or "classic" mode, also emit classic histograms.
 		if hasClassicHistograms(h.histogramOverride) {
 			classicErr := h.classicHistograms(appender, timeMs, s)
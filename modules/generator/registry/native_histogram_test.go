@@ -25,7 +25,7 @@ func Test_ObserveWithExemplar_duplicate(t *testing.T) {
 		},
 	}
 
-	h := newNativeHistogram("my_histogram", []float64{0.1, 0.2}, lifecycler, "trace_id", HistogramModeBoth, nil, testTenant, &mockOverrides{}, 15*time.Minute)
+	h := newNativeHistogram("my_histogram", []float64{0.1, 0.2}, lifecycler, "trace_id", HistogramModeBoth, nil, testTenant, &mockOverrides{}, 15*time.Minute, TemporalityCumulative)
 
 	lv := buildTestLabels([]string{"label"}, []string{"value-1"})
 
@@ -500,7 +500,7 @@ func Test_Histograms(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Run("classic", func(t *testing.T) {
-				h := newHistogram("test_histogram", tc.buckets, noopLimiter, "trace_id", nil, 15*time.Minute)
+				h := newHistogram("test_histogram", tc.buckets, noopLimiter, "trace_id", nil, 15*time.Minute, TemporalityCumulative)
 				testHistogram(t, h, tc.collections)
 			})
 			t.Run("native", func(t *testing.T) {
@@ -508,7 +508,7 @@ func Test_Histograms(t *testing.T) {
 					t.SkipNow()
 				}
 
-				h := newNativeHistogram("test_histogram", tc.buckets, noopLimiter, "trace_id", HistogramModeBoth, nil, testTenant, &mockOverrides{}, 15*time.Minute)
+				h := newNativeHistogram("test_histogram", tc.buckets, noopLimiter, "trace_id", HistogramModeBoth, nil, testTenant, &mockOverrides{}, 15*time.Minute, TemporalityCumulative)
 				testHistogram(t, h, tc.collections)
 			})
 		})
@@ -627,7 +627,7 @@ func Test_NativeOnlyExemplars(t *testing.T) {
 		}
 
 		// Use HistogramModeNative to test native-only behavior
-		h := newNativeHistogram("test_native_histogram", buckets, noopLimiter, "trace_id", HistogramModeNative, nil, testTenant, overrides, 15*time.Minute)
+		h := newNativeHistogram("test_native_histogram", buckets, noopLimiter, "trace_id", HistogramModeNative, nil, testTenant, overrides, 15*time.Minute, TemporalityCumulative)
 
 		// Add some observations with exemplars
 		lbls := buildTestLabels([]string{"service"}, []string{"test-service"})
@@ -672,7 +672,7 @@ func Test_NativeOnlyExemplars(t *testing.T) {
 		}
 
 		// Create a native histogram with empty buckets to force native-only mode
-		h := newNativeHistogram("test_native_only", []float64{}, noopLimiter, "trace_id", HistogramModeNative, nil, testTenant, overrides, 15*time.Minute)
+		h := newNativeHistogram("test_native_only", []float64{}, noopLimiter, "trace_id", HistogramModeNative, nil, testTenant, overrides, 15*time.Minute, TemporalityCumulative)
 
 		// Add some observations with exemplars
 		lbls := buildTestLabels([]string{"service"}, []string{"native-only-xyz"})
@@ -703,7 +703,7 @@ func Test_NativeOnlyExemplars(t *testing.T) {
 }
 
 func Test_nativeHistogram_demandTracking(t *testing.T) {
-	h := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", HistogramModeBoth, nil, testTenant, &mockOverrides{}, 15*time.Minute)
+	h := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", HistogramModeBoth, nil, testTenant, &mockOverrides{}, 15*time.Minute, TemporalityCumulative)
 
 	// Initially, demand should be 0
 	assert.Equal(t, 0, h.countSeriesDemand())
@@ -727,19 +727,19 @@ func Test_nativeHistogram_demandTracking(t *testing.T) {
 
 func Test_nativeHistogram_activeSeriesPerHistogramSerie(t *testing.T) {
 	// Test BOTH mode with 2 buckets: sum, count, bucket1, bucket2, +Inf, native = 6
-	h := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", HistogramModeBoth, nil, testTenant, &mockOverrides{}, 15*time.Minute)
+	h := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", HistogramModeBoth, nil, testTenant, &mockOverrides{}, 15*time.Minute, TemporalityCumulative)
 	assert.Equal(t, uint32(6), h.activeSeriesPerHistogramSerie(), "BOTH mode should be classic + native")
 
 	// Test NATIVE mode only: 1 native histogram series
-	h2 := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", HistogramModeNative, nil, testTenant, &mockOverrides{}, 15*time.Minute)
+	h2 := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", HistogramModeNative, nil, testTenant, &mockOverrides{}, 15*time.Minute, TemporalityCumulative)
 	assert.Equal(t, uint32(1), h2.activeSeriesPerHistogramSerie(), "NATIVE mode should be 1 series")
 
 	// Test CLASSIC mode with 2 buckets: sum, count, bucket1, bucket2, +Inf = 5
-	h3 := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", HistogramModeClassic, nil, testTenant, &mockOverrides{}, 15*time.Minute)
+	h3 := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, noopLimiter, "", HistogramModeClassic, nil, testTenant, &mockOverrides{}, 15*time.Minute, TemporalityCumulative)
 	assert.Equal(t, uint32(5), h3.activeSeriesPerHistogramSerie(), "CLASSIC mode should be sum + count + buckets")
 
 	// Test BOTH mode with 3 buckets: sum, count, 4 buckets, native = 7
-	h4 := newNativeHistogram("my_histogram", []float64{1.0, 2.0, 3.0}, noopLimiter, "", HistogramModeBoth, nil, testTenant, &mockOverrides{}, 15*time.Minute)
+	h4 := newNativeHistogram("my_histogram", []float64{1.0, 2.0, 3.0}, noopLimiter, "", HistogramModeBoth, nil, testTenant, &mockOverrides{}, 15*time.Minute, TemporalityCumulative)
 	assert.Equal(t, uint32(7), h4.activeSeriesPerHistogramSerie(), "BOTH mode with 3 buckets")
 }
 
@@ -756,7 +756,7 @@ func Test_nativeHistogram_demandVsActiveSeries(t *testing.T) {
 		},
 	}
 
-	h := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "", HistogramModeNative, nil, testTenant, &mockOverrides{}, 15*time.Minute)
+	h := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "", HistogramModeNative, nil, testTenant, &mockOverrides{}, 15*time.Minute, TemporalityCumulative)
 
 	// Add some histogram series
 	for i := 0; i < 10; i++ {
@@ -804,7 +804,7 @@ func Test_nativeHistogram_onUpdate(t *testing.T) {
 			},
 		}
 
-		h := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "", HistogramModeBoth, nil, testTenant, &mockOverrides{}, 15*time.Minute)
+		h := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "", HistogramModeBoth, nil, testTenant, &mockOverrides{}, 15*time.Minute, TemporalityCumulative)
 
 		// Add initial series (first observation triggers both OnAdd and OnUpdate)
 		h.ObserveWithExemplar(buildTestLabels([]string{"label"}, []string{"value-1"}), 1.0, "", 1.0)
@@ -838,7 +838,7 @@ func Test_nativeHistogram_onUpdate(t *testing.T) {
 			},
 		}
 
-		h := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "", HistogramModeNative, nil, testTenant, &mockOverrides{}, 15*time.Minute)
+		h := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "", HistogramModeNative, nil, testTenant, &mockOverrides{}, 15*time.Minute, TemporalityCumulative)
 
 		// Add initial series (first observation triggers both OnAdd and OnUpdate)
 		h.ObserveWithExemplar(buildTestLabels([]string{"label"}, []string{"value-1"}), 1.0, "", 1.0)
@@ -873,7 +873,7 @@ func Test_nativeHistogram_onUpdate(t *testing.T) {
 			},
 		}
 
-		h := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "", HistogramModeClassic, nil, testTenant, &mockOverrides{}, 15*time.Minute)
+		h := newNativeHistogram("my_histogram", []float64{1.0, 2.0}, lifecycler, "", HistogramModeClassic, nil, testTenant, &mockOverrides{}, 15*time.Minute, TemporalityCumulative)
 
 		// Add initial series (first observation triggers both OnAdd and OnUpdate)
 		h.ObserveWithExemplar(buildTestLabels([]string{"label"}, []string{"value-1"}), 1.0, "", 1.0)
@@ -897,7 +897,7 @@ func Test_nativeHistogram_leadingZero(t *testing.T) {
 	endOfLastMinuteMs := getEndOfLastMinuteMs(collectionTimeMs)
 
 	t.Run("native_mode", func(t *testing.T) {
-		h := newNativeHistogram("test_histogram", []float64{}, noopLimiter, "trace_id", HistogramModeNative, nil, testTenant, &mockOverrides{}, 15*time.Minute)
+		h := newNativeHistogram("test_histogram", []float64{}, noopLimiter, "trace_id", HistogramModeNative, nil, testTenant, &mockOverrides{}, 15*time.Minute, TemporalityCumulative)
 
 		lbls := buildTestLabels([]string{"label"}, []string{"value-1"})
 		h.ObserveWithExemplar(lbls, 1.5, "trace-1", 1.0)
@@ -923,7 +923,7 @@ func Test_nativeHistogram_leadingZero(t *testing.T) {
 	})
 
 	t.Run("both_mode", func(t *testing.T) {
-		h := newNativeHistogram("test_histogram", []float64{1, 2}, noopLimiter, "trace_id", HistogramModeBoth, nil, testTenant, &mockOverrides{}, 15*time.Minute)
+		h := newNativeHistogram("test_histogram", []float64{1, 2}, noopLimiter, "trace_id", HistogramModeBoth, nil, testTenant, &mockOverrides{}, 15*time.Minute, TemporalityCumulative)
 
 		lbls := buildTestLabels([]string{"label"}, []string{"value-1"})
 		h.ObserveWithExemplar(lbls, 1.5, "trace-1", 1.0)
@@ -962,3 +962,40 @@ func Test_nativeHistogram_leadingZero(t *testing.T) {
 		}
 	})
 }
+
+func Test_nativeHistogram_delta(t *testing.T) {
+	collectionTimeMs := time.Now().UnixMilli()
+
+	h := newNativeHistogram("test_histogram", []float64{1, 2}, noopLimiter, "trace_id", HistogramModeBoth, nil, testTenant, &mockOverrides{}, 15*time.Minute, TemporalityDelta)
+
+	lbls := buildTestLabels([]string{"label"}, []string{"value-1"})
+	h.ObserveWithExemplar(lbls, 1.5, "trace-1", 1.0)
+	h.ObserveWithExemplar(lbls, 2.5, "trace-2", 1.0)
+
+	// First collection: no leading zeros
+	appender1 := &capturingAppender{}
+	err := h.collectMetrics(appender1, collectionTimeMs)
+	require.NoError(t, err)
+	require.Len(t, appender1.histograms, 1)
+	assert.Equal(t, uint64(2), appender1.histograms[0].h.Count)
+	for _, s := range appender1.samples {
+		assert.Equal(t, collectionTimeMs, s.t)
+	}
+
+	// Second collection: only the new observation
+	h.ObserveWithExemplar(lbls, 0.5, "trace-3", 1.0)
+	appender2 := &capturingAppender{}
+	err = h.collectMetrics(appender2, collectionTimeMs+1000)
+	require.NoError(t, err)
+	require.Len(t, appender2.histograms, 1)
+	assert.Equal(t, uint64(1), appender2.histograms[0].h.Count)
+	assert.Equal(t, 0.5, appender2.histograms[0].h.Sum)
+
+	// Third collection: no observations, nothing is written
+	appender3 := &capturingAppender{}
+	err = h.collectMetrics(appender3, collectionTimeMs+2000)
+	require.NoError(t, err)
+	assert.Empty(t, appender3.histograms)
+	assert.Empty(t, appender3.samples)
+	assert.Len(t, h.series, 1)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/overrides/histograms"
+	"github.com/grafana/tempo/pkg/sharedconfig"
 )
 
 type Overrides interface {
@@ -18,7 +19,9 @@ type Overrides interface {
 	MetricsGeneratorNativeHistogramMaxBucketNumber(userID string) uint32
 	MetricsGeneratorNativeHistogramMinResetDuration(userID string) time.Duration
 	MetricsGeneratorSpanNameSanitization(userID string) string
+	MetricsGeneratorSpanNameSanitizationRules(userID string) []sharedconfig.SpanNameSanitizationRule
 	MetricsGeneratorMaxCardinalityPerLabel(userID string) uint64
+	MetricsGeneratorMaxCardinalityPerLabelMode(userID string) string
 }
 
 var _ Overrides = (overrides.Interface)(nil)
@@ -14,6 +14,12 @@ import (
 
 const (
 	overflowValue = "__cardinality_overflow__"
+	// otherValue replaces the values that are not among the most active values of a label in the
+	// top-K mode.
+	otherValue = "__other__"
+	// heavyHittersCapacityFactor is the number of values tracked by the heavy hitters sketch relative
+	// to the max cardinality. Tracking more values than needed improves the accuracy of the top-K.
+	heavyHittersCapacityFactor = 2
 	// demandUpdateInterval controls how often the cardinality estimate from HLL
 	// is refreshed and the overLimit flag and demand gauge are updated.
 	// kept at 15s to limit lock contention with Limit() (hot path) which shares the same mutex
@@ -35,15 +41,27 @@ var metricLabelCardinalityDemand = promauto.NewGaugeVec(prometheus.GaugeOpts{
 // maxCardinalityFunc returns the MaxCardinalityPerLabel config value for the tenant.
 type maxCardinalityFunc func(tenant string) uint64
 
+// limitModeFunc returns the MaxCardinalityPerLabelMode config value for the tenant.
+type limitModeFunc func(tenant string) string
+
 type labelCardinalityState struct {
 	sketch    *Cardinality
 	overLimit bool // cached flag, updated periodically in maintenance tick
+
+	// heavyHitters and top are only used in the top-K mode. top holds the most active values of
+	// the last collection interval, it's nil until the first evaluation.
+	heavyHitters *heavyHitters
+	top          map[string]struct{}
 }
 
 // PerLabelLimiter caps the number of distinct values any single label can have.
 // When a label's estimated cardinality exceeds maxCardinality, its value is replaced
 // with '__cardinality_overflow__' while all other labels are preserved.
 //
+// In the top-K mode, the limiter keeps the maxCardinality most active values of the label
+// instead, tracked with a heavy hitters sketch, and replaces the other values with '__other__'.
+// The most active values are re-evaluated every collection interval by EvaluateTopK.
+//
 // This is conceptually a limiter, not a sanitizer - it enforces a cardinality ceiling
 // rather than normalizing label values (like DrainSanitizer does for span names).
 // It runs in the label-building pipeline after sanitization but before the global
@@ -53,6 +71,8 @@ type PerLabelLimiter struct {
 	tenant             string
 	maxCardinalityFunc maxCardinalityFunc
 	maxCardinality     atomic.Uint64 // refreshed on demand update tick, read atomically in Limit() hot path
+	limitModeFunc      limitModeFunc
+	topK               atomic.Bool // refreshed on demand update tick, like maxCardinality
 
 	labelsState   map[string]*labelCardinalityState
 	staleDuration time.Duration
@@ -61,10 +81,11 @@ type PerLabelLimiter struct {
 	pruneChan        <-chan time.Time
 }
 
-func NewPerLabelLimiter(tenant string, maxCardinalityF maxCardinalityFunc, staleDuration time.Duration) *PerLabelLimiter {
+func NewPerLabelLimiter(tenant string, maxCardinalityF maxCardinalityFunc, limitModeF limitModeFunc, staleDuration time.Duration) *PerLabelLimiter {
 	pll := &PerLabelLimiter{
 		tenant:             tenant,
 		maxCardinalityFunc: maxCardinalityF,
+		limitModeFunc:      limitModeF,
 		labelsState:        make(map[string]*labelCardinalityState),
 		staleDuration:      staleDuration,
 		demandUpdateChan:   time.Tick(demandUpdateInterval),
@@ -72,12 +93,14 @@ func NewPerLabelLimiter(tenant string, maxCardinalityF maxCardinalityFunc, stale
 	}
 	// init on New, config is refreshed on demand update tick
 	pll.maxCardinality.Store(maxCardinalityF(tenant))
+	pll.topK.Store(limitModeF(tenant) == MaxCardinalityPerLabelModeTopK)
 	return pll
 }
 
 // Limit applies the per-label cardinality limit to the given labels.
 // Labels whose estimated cardinality exceeds the configured max have their
-// value replaced with __cardinality_overflow__.
+// value replaced with __cardinality_overflow__, or with __other__ in the top-K
+// mode if the value is not among the most active values.
 func (s *PerLabelLimiter) Limit(lbls labels.Labels) labels.Labels {
 	// do maintenance check as the first thing to ensure maxCardinality
 	// is refreshed from runtime overrides. without this,
@@ -85,9 +108,11 @@ func (s *PerLabelLimiter) Limit(lbls labels.Labels) labels.Labels {
 	s.doPeriodicMaintenance()
 
 	// maxCardinality is zero, so limiter is disabled, return labels as is
-	if s.maxCardinality.Load() == 0 {
+	maxCardinality := s.maxCardinality.Load()
+	if maxCardinality == 0 {
 		return lbls
 	}
+	topK := s.topK.Load()
 
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
@@ -102,6 +127,9 @@ func (s *PerLabelLimiter) Limit(lbls labels.Labels) labels.Labels {
 		}
 
 		state := s.getOrCreateState(l.Name)
+		if topK {
+			state.insertHeavyHitter(l.Value, maxCardinality)
+		}
 
 		// we always insert the ORIGINAL value to hash even while overflowing,
 		// which prevents the estimate from artificially dropping.
@@ -115,12 +143,21 @@ func (s *PerLabelLimiter) Limit(lbls labels.Labels) labels.Labels {
 
 		// we are over the limit, replace label value and capture the metric
 		if state.overLimit {
+			replacement := overflowValue
+			if topK {
+				// the most active values are kept
+				if _, ok := state.top[l.Value]; ok {
+					return
+				}
+				replacement = otherValue
+			}
+
 			// Lazy init: only create once, so previous Set calls are preserved
 			// when multiple labels overflow in the same series
 			if builder == nil {
 				builder = labels.NewBuilder(lbls)
 			}
-			builder.Set(l.Name, overflowValue)
+			builder.Set(l.Name, replacement)
 			metricLabelValuesLimited.WithLabelValues(s.tenant, l.Name).Inc()
 		}
 	})
@@ -132,6 +169,39 @@ func (s *PerLabelLimiter) Limit(lbls labels.Labels) labels.Labels {
 	return builder.Labels()
 }
 
+// EvaluateTopK updates the most active values of every label from the heavy hitters sketches and
+// resets them, so the next evaluation only considers the values seen since. It's called every
+// collection interval and does nothing if the top-K mode is disabled.
+func (s *PerLabelLimiter) EvaluateTopK() {
+	maxCardinality := s.maxCardinality.Load()
+	if maxCardinality == 0 || !s.topK.Load() {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for _, state := range s.labelsState {
+		if state.heavyHitters == nil {
+			continue
+		}
+		state.top = state.heavyHitters.Top(int(maxCardinality))
+		state.heavyHitters.Reset()
+	}
+}
+
+// insertHeavyHitter inserts the value into the heavy hitters sketch, creating it if the top-K mode
+// was just enabled. Must be called holding lock.
+func (state *labelCardinalityState) insertHeavyHitter(value string, maxCardinality uint64) {
+	capacity := int(maxCardinality) * heavyHittersCapacityFactor
+	// recreate the sketch if the max cardinality changed
+	if state.heavyHitters == nil || state.heavyHitters.capacity != capacity {
+		state.heavyHitters = newHeavyHitters(capacity)
+		state.top = nil
+	}
+	state.heavyHitters.Insert(value)
+}
+
 func (s *PerLabelLimiter) getOrCreateState(labelName string) *labelCardinalityState {
 	state, ok := s.labelsState[labelName]
 	if !ok {
@@ -155,6 +225,8 @@ func (s *PerLabelLimiter) doPeriodicMaintenance() {
 		// fetch once per tick and cache atomically, the limit is the same for all labels in a tenant
 		maxCardinality := s.maxCardinalityFunc(s.tenant)
 		s.maxCardinality.Store(maxCardinality)
+		topK := s.limitModeFunc(s.tenant) == MaxCardinalityPerLabelModeTopK
+		s.topK.Store(topK)
 
 		// if the check is disabled, skip the demand update and, exit early.
 		// no data is being inserted into the sketch, so nothing to estimate or publish
@@ -167,6 +239,14 @@ func (s *PerLabelLimiter) doPeriodicMaintenance() {
 		for labelName, state := range s.labelsState {
 			estimate := state.sketch.Estimate()
 			state.overLimit = estimate > maxCardinality
+			// the label just went over the limit, use the values seen so far until the next
+			// evaluation instead of replacing all values
+			if topK && state.overLimit && state.top == nil && state.heavyHitters != nil {
+				state.top = state.heavyHitters.Top(int(maxCardinality))
+			}
+			if !topK {
+				state.heavyHitters, state.top = nil, nil
+			}
 			metricLabelCardinalityDemand.WithLabelValues(s.tenant, labelName).Set(float64(estimate))
 		}
 		s.mtx.Unlock()
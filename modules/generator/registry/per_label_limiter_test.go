@@ -17,8 +17,12 @@ func testMaxCardinality(value uint64) maxCardinalityFunc {
 	return func(string) uint64 { return value }
 }
 
+func testLimitMode(mode string) limitModeFunc {
+	return func(string) string { return mode }
+}
+
 func TestPerLabelLimiter_Disabled(t *testing.T) {
-	s := NewPerLabelLimiter("test", testMaxCardinality(0), 15*time.Minute)
+	s := NewPerLabelLimiter("test", testMaxCardinality(0), testLimitMode(MaxCardinalityPerLabelModeOverflow), 15*time.Minute)
 
 	lbls := labels.FromStrings("__name__", "foo", "method", "GET", "url", "/api/users/123")
 	result := s.Limit(lbls)
@@ -34,7 +38,7 @@ func TestPerLabelLimiter_RuntimeEnableDisable(t *testing.T) {
 
 	s := NewPerLabelLimiter(tenant, func(string) uint64 {
 		return maxCardinality.Load()
-	}, 15*time.Minute)
+	}, testLimitMode(MaxCardinalityPerLabelModeOverflow), 15*time.Minute)
 
 	// Phase 1: Disabled - all labels pass through
 	for i := 0; i < 10; i++ {
@@ -91,7 +95,7 @@ func TestPerLabelLimiter_RuntimeEnableDisable(t *testing.T) {
 }
 
 func TestPerLabelLimiter_UnderLimit(t *testing.T) {
-	s := NewPerLabelLimiter("test", testMaxCardinality(100), 15*time.Minute)
+	s := NewPerLabelLimiter("test", testMaxCardinality(100), testLimitMode(MaxCardinalityPerLabelModeOverflow), 15*time.Minute)
 
 	// Insert a few distinct values - well under the limit
 	for i := 0; i < 5; i++ {
@@ -111,7 +115,7 @@ func TestPerLabelLimiter_UnderLimit(t *testing.T) {
 }
 
 func TestPerLabelLimiter_HighCardinalityOverflows(t *testing.T) {
-	s := NewPerLabelLimiter("test", testMaxCardinality(5), 15*time.Minute)
+	s := NewPerLabelLimiter("test", testMaxCardinality(5), testLimitMode(MaxCardinalityPerLabelModeOverflow), 15*time.Minute)
 
 	// Push distinct url values but few method values
 	for i := 0; i < 10; i++ {
@@ -131,7 +135,7 @@ func TestPerLabelLimiter_HighCardinalityOverflows(t *testing.T) {
 }
 
 func TestPerLabelLimiter_MultipleHighCardinalityOverflows(t *testing.T) {
-	s := NewPerLabelLimiter("test", testMaxCardinality(5), 15*time.Minute)
+	s := NewPerLabelLimiter("test", testMaxCardinality(5), testLimitMode(MaxCardinalityPerLabelModeOverflow), 15*time.Minute)
 
 	// Push many distinct values for BOTH url and user_id
 	for i := 0; i < 10; i++ {
@@ -149,7 +153,7 @@ func TestPerLabelLimiter_MultipleHighCardinalityOverflows(t *testing.T) {
 }
 
 func TestPerLabelLimiter_MetadataLabelsNeverOverflows(t *testing.T) {
-	s := NewPerLabelLimiter("test", testMaxCardinality(5), 15*time.Minute)
+	s := NewPerLabelLimiter("test", testMaxCardinality(5), testLimitMode(MaxCardinalityPerLabelModeOverflow), 15*time.Minute)
 
 	// Push many distinct values for all metadata labels to exceed the limit
 	for i := 0; i < 10; i++ {
@@ -177,7 +181,7 @@ func TestPerLabelLimiter_MetadataLabelsNeverOverflows(t *testing.T) {
 // high-cardinality sketches rotate out of the sliding window.
 func TestPerLabelLimiter_RecoveryAfterOverflow(t *testing.T) {
 	staleDuration := 15 * time.Minute
-	s := NewPerLabelLimiter("test", testMaxCardinality(5), staleDuration)
+	s := NewPerLabelLimiter("test", testMaxCardinality(5), testLimitMode(MaxCardinalityPerLabelModeOverflow), staleDuration)
 
 	// Phase 1: Push high-cardinality data to trigger overflow
 	for i := 0; i < 10; i++ {
@@ -224,9 +228,70 @@ func TestPerLabelLimiter_RecoveryAfterOverflow(t *testing.T) {
 	require.Equal(t, overflowValue, result.Get("url"), "should overflow again after cardinality increases")
 }
 
+func TestPerLabelLimiter_TopK(t *testing.T) {
+	s := NewPerLabelLimiter("test", testMaxCardinality(2), testLimitMode(MaxCardinalityPerLabelModeTopK), 15*time.Minute)
+
+	// two busy endpoints and a long tail of endpoints that are requested once
+	push := func(busy ...string) {
+		for i := 0; i < 10; i++ {
+			for _, url := range busy {
+				s.Limit(labels.FromStrings("__name__", "http_requests", "url", url))
+			}
+			s.Limit(labels.FromStrings("__name__", "http_requests", "url", fmt.Sprintf("/users/%d", i)))
+		}
+	}
+	push("/api/orders", "/api/cart")
+	triggerDemandUpdate(s)
+
+	// the most active values are kept, the other values are folded into __other__
+	require.Equal(t, "/api/orders", s.Limit(labels.FromStrings("__name__", "http_requests", "url", "/api/orders")).Get("url"))
+	require.Equal(t, "/api/cart", s.Limit(labels.FromStrings("__name__", "http_requests", "url", "/api/cart")).Get("url"))
+	require.Equal(t, otherValue, s.Limit(labels.FromStrings("__name__", "http_requests", "url", "/users/999")).Get("url"))
+
+	// the most active values are re-evaluated every collection interval
+	s.EvaluateTopK()
+	push("/api/checkout", "/api/cart")
+	s.EvaluateTopK()
+
+	require.Equal(t, "/api/checkout", s.Limit(labels.FromStrings("__name__", "http_requests", "url", "/api/checkout")).Get("url"))
+	require.Equal(t, "/api/cart", s.Limit(labels.FromStrings("__name__", "http_requests", "url", "/api/cart")).Get("url"))
+	require.Equal(t, otherValue, s.Limit(labels.FromStrings("__name__", "http_requests", "url", "/api/orders")).Get("url"))
+}
+
+func TestPerLabelLimiter_TopKModeChange(t *testing.T) {
+	var mode atomic.Value
+	mode.Store(MaxCardinalityPerLabelModeOverflow)
+	s := NewPerLabelLimiter("test", testMaxCardinality(2), func(string) string {
+		return mode.Load().(string)
+	}, 15*time.Minute)
+
+	for i := 0; i < 10; i++ {
+		s.Limit(labels.FromStrings("__name__", "m", "url", "/api/orders"))
+		s.Limit(labels.FromStrings("__name__", "m", "url", fmt.Sprintf("/users/%d", i)))
+	}
+	triggerDemandUpdate(s)
+	require.Equal(t, overflowValue, s.Limit(labels.FromStrings("__name__", "m", "url", "/api/orders")).Get("url"))
+
+	// enable the top-K mode, the values are tracked from now on
+	mode.Store(MaxCardinalityPerLabelModeTopK)
+	triggerDemandUpdate(s)
+	for i := 0; i < 10; i++ {
+		s.Limit(labels.FromStrings("__name__", "m", "url", "/api/orders"))
+		s.Limit(labels.FromStrings("__name__", "m", "url", fmt.Sprintf("/users/%d", i)))
+	}
+	s.EvaluateTopK()
+	require.Equal(t, "/api/orders", s.Limit(labels.FromStrings("__name__", "m", "url", "/api/orders")).Get("url"))
+	require.Equal(t, otherValue, s.Limit(labels.FromStrings("__name__", "m", "url", "/users/999")).Get("url"))
+
+	// disable it again
+	mode.Store(MaxCardinalityPerLabelModeOverflow)
+	triggerDemandUpdate(s)
+	require.Equal(t, overflowValue, s.Limit(labels.FromStrings("__name__", "m", "url", "/api/orders")).Get("url"))
+}
+
 func TestPerLabelLimiter_OverflowMetrics(t *testing.T) {
 	tenant := "test-overflow-metrics"
-	s := NewPerLabelLimiter(tenant, testMaxCardinality(5), 15*time.Minute)
+	s := NewPerLabelLimiter(tenant, testMaxCardinality(5), testLimitMode(MaxCardinalityPerLabelModeOverflow), 15*time.Minute)
 
 	// Push enough distinct values to exceed the limit
 	for i := 0; i < 10; i++ {
@@ -255,7 +320,7 @@ func TestPerLabelLimiter_OverflowMetrics(t *testing.T) {
 // from multiple goroutines while doPeriodicMaintenance fires concurrently.
 // Run with -race to detect unsynchronized access.
 func TestPerLabelLimiter_ConcurrentAccess(t *testing.T) {
-	s := NewPerLabelLimiter("test", testMaxCardinality(10), 15*time.Minute)
+	s := NewPerLabelLimiter("test", testMaxCardinality(10), testLimitMode(MaxCardinalityPerLabelModeOverflow), 15*time.Minute)
 
 	// Replace tickers with channels we control, so doPeriodicMaintenance
 	// actually runs its demand-update and prune paths during the test.
@@ -315,7 +380,7 @@ func TestPerLabelLimiter_ConcurrentAccess(t *testing.T) {
 
 func BenchmarkPerLabelLimiter_Limit(b *testing.B) {
 	b.Run("disabled", func(b *testing.B) {
-		s := NewPerLabelLimiter("bench", testMaxCardinality(0), 15*time.Minute)
+		s := NewPerLabelLimiter("bench", testMaxCardinality(0), testLimitMode(MaxCardinalityPerLabelModeOverflow), 15*time.Minute)
 		lbls := labels.FromStrings("__name__", "http_requests", "method", "GET", "url", "/api/v1/users")
 		b.ReportAllocs()
 		// Reset timer so setup (limiter creation, label generation, warmup) isn't measured
@@ -326,7 +391,7 @@ func BenchmarkPerLabelLimiter_Limit(b *testing.B) {
 	})
 
 	b.Run("under_limit", func(b *testing.B) {
-		s := NewPerLabelLimiter("bench", testMaxCardinality(1000), 15*time.Minute)
+		s := NewPerLabelLimiter("bench", testMaxCardinality(1000), testLimitMode(MaxCardinalityPerLabelModeOverflow), 15*time.Minute)
 		// Pre-generate distinct labels to simulate real traffic with unique values
 		n := 500
 		allLbls := make([]labels.Labels, n)
@@ -343,7 +408,7 @@ func BenchmarkPerLabelLimiter_Limit(b *testing.B) {
 	})
 
 	b.Run("over_limit", func(b *testing.B) {
-		s := NewPerLabelLimiter("bench", testMaxCardinality(5), 15*time.Minute)
+		s := NewPerLabelLimiter("bench", testMaxCardinality(5), testLimitMode(MaxCardinalityPerLabelModeOverflow), 15*time.Minute)
 		n := 500
 		allLbls := make([]labels.Labels, n)
 		for i := 0; i < n; i++ {
@@ -361,7 +426,7 @@ func BenchmarkPerLabelLimiter_Limit(b *testing.B) {
 	})
 
 	b.Run("many_labels_under_limit", func(b *testing.B) {
-		s := NewPerLabelLimiter("bench", testMaxCardinality(1000), 15*time.Minute)
+		s := NewPerLabelLimiter("bench", testMaxCardinality(1000), testLimitMode(MaxCardinalityPerLabelModeOverflow), 15*time.Minute)
 		n := 500
 		allLbls := make([]labels.Labels, n)
 		for i := 0; i < n; i++ {
@@ -385,7 +450,7 @@ func BenchmarkPerLabelLimiter_Limit(b *testing.B) {
 	})
 
 	b.Run("many_labels_over_limit", func(b *testing.B) {
-		s := NewPerLabelLimiter("bench", testMaxCardinality(5), 15*time.Minute)
+		s := NewPerLabelLimiter("bench", testMaxCardinality(5), testLimitMode(MaxCardinalityPerLabelModeOverflow), 15*time.Minute)
 		n := 500
 		allLbls := make([]labels.Labels, n)
 		for i := 0; i < n; i++ {
@@ -412,7 +477,7 @@ func BenchmarkPerLabelLimiter_Limit(b *testing.B) {
 	})
 
 	b.Run("parallel", func(b *testing.B) {
-		s := NewPerLabelLimiter("bench", testMaxCardinality(1000), 15*time.Minute)
+		s := NewPerLabelLimiter("bench", testMaxCardinality(1000), testLimitMode(MaxCardinalityPerLabelModeOverflow), 15*time.Minute)
 		n := 500
 		allLbls := make([]labels.Labels, n)
 		for i := 0; i < n; i++ {
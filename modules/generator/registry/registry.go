@@ -11,7 +11,9 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
 	"github.com/prometheus/prometheus/storage"
 
 	tempo_log "github.com/grafana/tempo/pkg/util/log"
@@ -92,6 +94,8 @@ type noopLabelLimiter struct{}
 
 func (noopLabelLimiter) Limit(lbls labels.Labels) labels.Labels { return lbls }
 
+func (noopLabelLimiter) EvaluateTopK() {}
+
 // noopSanitizer is a Sanitizer that passes labels through unchanged.
 type noopSanitizer struct{}
 
@@ -114,6 +118,8 @@ type Limiter interface {
 // LabelLimiter caps label cardinality by replacing high-cardinality values.
 type LabelLimiter interface {
 	Limit(lbls labels.Labels) labels.Labels
+	// EvaluateTopK re-evaluates the most active label values, it's called every collection interval.
+	EvaluateTopK()
 }
 
 // Sanitizer applies a transformation to all non-constant labels.
@@ -130,15 +136,18 @@ func New(cfg *Config, overrides Overrides, tenant string, appendable storage.App
 	for k, v := range cfg.ExternalLabels {
 		externalLabels[k] = v
 	}
-	hostname, _ := os.Hostname()
-	externalLabels["__metrics_gen_instance"] = hostname
+	// in delta mode, all metrics-generators write the same series
+	if cfg.Temporality != TemporalityDelta {
+		hostname, _ := os.Hostname()
+		externalLabels["__metrics_gen_instance"] = hostname
+	}
 
 	if cfg.InjectTenantIDAs != "" {
 		externalLabels[cfg.InjectTenantIDAs] = tenant
 	}
 
-	drainSanitizer := NewDrainSanitizer(tenant, overrides.MetricsGeneratorSpanNameSanitization, cfg.StaleDuration)
-	perLabelLimiter := NewPerLabelLimiter(tenant, overrides.MetricsGeneratorMaxCardinalityPerLabel, cfg.StaleDuration)
+	drainSanitizer := NewDrainSanitizer(tenant, overrides.MetricsGeneratorSpanNameSanitization, overrides.MetricsGeneratorSpanNameSanitizationRules, cfg.StaleDuration)
+	perLabelLimiter := NewPerLabelLimiter(tenant, overrides.MetricsGeneratorMaxCardinalityPerLabel, overrides.MetricsGeneratorMaxCardinalityPerLabelMode, cfg.StaleDuration)
 
 	r := &ManagedRegistry{
 		onShutdown: cancel,
@@ -192,7 +201,7 @@ func (r *ManagedRegistry) OnDelete(labelHash uint64, seriesCount uint32) {
 }
 
 func (r *ManagedRegistry) NewCounter(name string) Counter {
-	c := newCounter(name, r, r.externalLabels, r.cfg.StaleDuration)
+	c := newCounter(name, r, r.externalLabels, r.cfg.StaleDuration, r.cfg.Temporality)
 	r.registerMetric(c)
 	return c
 }
@@ -204,9 +213,9 @@ func (r *ManagedRegistry) NewHistogram(name string, buckets []float64, histogram
 	// are disabled, eventually the new implementation can handle all cases
 
 	if hasNativeHistograms(histogramOverride) {
-		h = newNativeHistogram(name, buckets, r, traceIDLabelName, histogramOverride, r.externalLabels, r.tenant, r.overrides, r.cfg.StaleDuration)
+		h = newNativeHistogram(name, buckets, r, traceIDLabelName, histogramOverride, r.externalLabels, r.tenant, r.overrides, r.cfg.StaleDuration, r.cfg.Temporality)
 	} else {
-		h = newHistogram(name, buckets, r, traceIDLabelName, r.externalLabels, r.cfg.StaleDuration)
+		h = newHistogram(name, buckets, r, traceIDLabelName, r.externalLabels, r.cfg.StaleDuration, r.cfg.Temporality)
 	}
 
 	r.registerMetric(h)
@@ -245,6 +254,9 @@ func (r *ManagedRegistry) CollectMetrics(ctx context.Context) {
 	r.metricSeriesDemand.Set(float64(seriesDemand))
 	r.metricEntityDemand.Set(float64(r.entityDemand.Estimate()))
 
+	// re-evaluate the most active label values every collection interval
+	r.perLabelLimiter.EvaluateTopK()
+
 	if r.overrides.MetricsGeneratorDisableCollection(r.tenant) {
 		return
 	}
@@ -262,6 +274,10 @@ func (r *ManagedRegistry) CollectMetrics(ctx context.Context) {
 	collectionTimeMs := time.Now().UnixMilli()
 
 	for _, m := range r.metrics {
+		// the type of the metric is only used by appenders that export typed metrics, e.g. OTLP
+		if _, err = appender.UpdateMetadata(0, labels.FromStrings(labels.MetricName, m.name()), metadata.Metadata{Type: metricType(m)}); err != nil {
+			return
+		}
 		if err = m.collectMetrics(appender, collectionTimeMs); err != nil {
 			return
 		}
@@ -280,6 +296,27 @@ func (r *ManagedRegistry) CollectMetrics(ctx context.Context) {
 	}
 }
 
+func metricType(m metric) model.MetricType {
+	switch m.(type) {
+	case *counter:
+		return model.MetricTypeCounter
+	case *gauge:
+		return model.MetricTypeGauge
+	case *histogram, *nativeHistogram:
+		return model.MetricTypeHistogram
+	}
+	return model.MetricTypeUnknown
+}
+
+// SpanNameSanitizationStatus returns the state of the span name sanitization of the tenant.
+func (r *ManagedRegistry) SpanNameSanitizationStatus() (SpanNameSanitizationStatus, bool) {
+	ds, ok := r.sanitizer.(*DrainSanitizer)
+	if !ok {
+		return SpanNameSanitizationStatus{}, false
+	}
+	return ds.Status(), true
+}
+
 func (r *ManagedRegistry) collectionInterval() time.Duration {
 	interval := r.overrides.MetricsGeneratorCollectionInterval(r.tenant)
 	if interval != 0 {
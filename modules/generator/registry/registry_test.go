@@ -12,6 +12,7 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/grafana/tempo/modules/overrides/histograms"
+	"github.com/grafana/tempo/pkg/sharedconfig"
 	"github.com/prometheus/client_golang/prometheus"
 	io_prometheus_client "github.com/prometheus/client_model/go"
 	"github.com/prometheus/prometheus/model/labels"
@@ -221,6 +222,25 @@ func TestManagedRegistry_externalLabels(t *testing.T) {
 	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
 }
 
+func TestManagedRegistry_deltaTemporality(t *testing.T) {
+	appender := &capturingAppender{}
+
+	cfg := &Config{
+		Temporality: TemporalityDelta,
+	}
+	registry := New(cfg, &mockOverrides{}, "test", appender, log.NewNopLogger(), noopLimiter)
+	defer registry.Close()
+
+	counter := registry.NewCounter("my_counter")
+	counter.Inc(labels.New(), 1.0)
+
+	// all metrics-generators write the same series, there is no __metrics_gen_instance label
+	expectedSamples := []sample{
+		newSample(map[string]string{"__name__": "my_counter"}, 0, 1),
+	}
+	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
+}
+
 func TestManagedRegistry_injectTenantIDAs(t *testing.T) {
 	appender := &capturingAppender{}
 
@@ -487,6 +507,7 @@ type mockOverrides struct {
 	nativeHistogramBucketFactor     float64
 	nativeHistogramMinResetDuration time.Duration
 	maxCardinalityPerLabel          uint64
+	maxCardinalityPerLabelMode      string
 	spanNameSanitization            string
 }
 
@@ -535,10 +556,18 @@ func (m *mockOverrides) MetricsGeneratorSpanNameSanitization(string) string {
 	return SpanNameSanitizationDisabled
 }
 
+func (m *mockOverrides) MetricsGeneratorSpanNameSanitizationRules(string) []sharedconfig.SpanNameSanitizationRule {
+	return nil
+}
+
 func (m *mockOverrides) MetricsGeneratorMaxCardinalityPerLabel(string) uint64 {
 	return m.maxCardinalityPerLabel
 }
 
+func (m *mockOverrides) MetricsGeneratorMaxCardinalityPerLabelMode(string) string {
+	return m.maxCardinalityPerLabelMode
+}
+
 func mustGetHostname() string {
 	hostname, _ := os.Hostname()
 	return hostname
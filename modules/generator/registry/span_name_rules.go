@@ -0,0 +1,112 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/tempo/pkg/sharedconfig"
+)
+
+// spanNameRule is a compiled span name sanitization rule.
+type spanNameRule struct {
+	rule sharedconfig.SpanNameSanitizationRule
+	re   *regexp.Regexp
+	// replacement is expanded by re.ReplaceAllString
+	replacement string
+	// err is set if the rule is invalid, invalid rules never match
+	err error
+
+	// hits is the number of span names rewritten by this rule. Must be accessed holding the lock
+	// of the sanitizer.
+	hits uint64
+}
+
+func compileSpanNameRule(rule sharedconfig.SpanNameSanitizationRule) (*spanNameRule, error) {
+	switch {
+	case rule.Pattern != "" && rule.Template != "":
+		return nil, errors.New("pattern and template can't both be set")
+	case rule.Pattern != "":
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", rule.Pattern, err)
+		}
+		return &spanNameRule{rule: rule, re: re, replacement: rule.Replacement}, nil
+	case rule.Template != "":
+		expr, err := templateRegexp(rule.Template)
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template %q: %w", rule.Template, err)
+		}
+		target := rule.Template
+		if rule.Replacement != "" {
+			target = rule.Replacement
+		}
+		// keep the prefix of the span name, usually the HTTP method
+		replacement := "${1}" + strings.ReplaceAll(target, "$", "$$")
+		return &spanNameRule{rule: rule, re: re, replacement: replacement}, nil
+	default:
+		return nil, errors.New("pattern or template must be set")
+	}
+}
+
+// templateRegexp converts a URL route template like /users/{id} to a regular expression. Every
+// {param} matches a single path segment. The span name may be prefixed, for example with the
+// HTTP method as in "GET /users/123".
+func templateRegexp(template string) (string, error) {
+	if !strings.HasPrefix(template, "/") {
+		return "", fmt.Errorf("invalid template %q: must start with /", template)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`^(\S+\s+)?`)
+
+	rest := template
+	for rest != "" {
+		start := strings.IndexAny(rest, "{}")
+		if start == -1 {
+			sb.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+		if rest[start] == '}' {
+			return "", fmt.Errorf("invalid template %q: unexpected }", template)
+		}
+		sb.WriteString(regexp.QuoteMeta(rest[:start]))
+
+		end := strings.IndexAny(rest[start+1:], "{}/")
+		if end == -1 || rest[start+1+end] != '}' {
+			return "", fmt.Errorf("invalid template %q: unclosed {", template)
+		}
+		if end == 0 {
+			return "", fmt.Errorf("invalid template %q: empty parameter name", template)
+		}
+		sb.WriteString(`[^/]+`)
+		rest = rest[start+1+end+1:]
+	}
+
+	sb.WriteString(`$`)
+	return sb.String(), nil
+}
+
+// apply returns the rewritten span name if the rule matches.
+func (r *spanNameRule) apply(spanName string) (string, bool) {
+	if r.err != nil || !r.re.MatchString(spanName) {
+		return "", false
+	}
+	r.hits++
+	return r.re.ReplaceAllString(spanName, r.replacement), true
+}
+
+// ValidateSpanNameSanitizationRules returns an error if any of the rules is invalid.
+func ValidateSpanNameSanitizationRules(rules []sharedconfig.SpanNameSanitizationRule) error {
+	for i, rule := range rules {
+		if _, err := compileSpanNameRule(rule); err != nil {
+			return fmt.Errorf("span_name_sanitization_rules[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
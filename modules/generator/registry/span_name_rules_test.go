@@ -0,0 +1,107 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/sharedconfig"
+)
+
+func TestSpanNameRule(t *testing.T) {
+	tcs := []struct {
+		name     string
+		rule     sharedconfig.SpanNameSanitizationRule
+		spanName string
+		expected string
+		matches  bool
+	}{
+		{
+			name:     "template",
+			rule:     sharedconfig.SpanNameSanitizationRule{Template: "/users/{id}/orders/{order_id}"},
+			spanName: "/users/123/orders/456",
+			expected: "/users/{id}/orders/{order_id}",
+			matches:  true,
+		},
+		{
+			name:     "template keeps the method",
+			rule:     sharedconfig.SpanNameSanitizationRule{Template: "/users/{id}"},
+			spanName: "GET /users/123",
+			expected: "GET /users/{id}",
+			matches:  true,
+		},
+		{
+			name:     "template with replacement",
+			rule:     sharedconfig.SpanNameSanitizationRule{Template: "/users/{id}", Replacement: "/users/:id"},
+			spanName: "GET /users/123",
+			expected: "GET /users/:id",
+			matches:  true,
+		},
+		{
+			name:     "template parameter within a segment",
+			rule:     sharedconfig.SpanNameSanitizationRule{Template: "/files/{name}.json"},
+			spanName: "/files/report.json",
+			expected: "/files/{name}.json",
+			matches:  true,
+		},
+		{
+			name:     "template parameter matches a single segment",
+			rule:     sharedconfig.SpanNameSanitizationRule{Template: "/users/{id}"},
+			spanName: "/users/123/orders",
+		},
+		{
+			name:     "template literals are not regular expressions",
+			rule:     sharedconfig.SpanNameSanitizationRule{Template: "/v1.0/{id}"},
+			spanName: "/v1x0/123",
+		},
+		{
+			name:     "pattern",
+			rule:     sharedconfig.SpanNameSanitizationRule{Pattern: `^cache-(get|set)-\d+$`, Replacement: "cache-$1"},
+			spanName: "cache-get-42",
+			expected: "cache-get",
+			matches:  true,
+		},
+		{
+			name:     "pattern doesn't match",
+			rule:     sharedconfig.SpanNameSanitizationRule{Pattern: `^cache-(get|set)-\d+$`, Replacement: "cache-$1"},
+			spanName: "cache-delete-42",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := compileSpanNameRule(tc.rule)
+			require.NoError(t, err)
+
+			spanName, ok := r.apply(tc.spanName)
+			assert.Equal(t, tc.matches, ok)
+			assert.Equal(t, tc.expected, spanName)
+		})
+	}
+}
+
+func TestValidateSpanNameSanitizationRules(t *testing.T) {
+	tcs := []struct {
+		rule        sharedconfig.SpanNameSanitizationRule
+		expectedErr string
+	}{
+		{rule: sharedconfig.SpanNameSanitizationRule{}, expectedErr: "span_name_sanitization_rules[0]: pattern or template must be set"},
+		{rule: sharedconfig.SpanNameSanitizationRule{Pattern: "a", Template: "/a"}, expectedErr: "span_name_sanitization_rules[0]: pattern and template can't both be set"},
+		{rule: sharedconfig.SpanNameSanitizationRule{Pattern: "("}, expectedErr: "span_name_sanitization_rules[0]: invalid pattern \"(\": error parsing regexp: missing closing ): `(`"},
+		{rule: sharedconfig.SpanNameSanitizationRule{Template: "users/{id}"}, expectedErr: "span_name_sanitization_rules[0]: invalid template \"users/{id}\": must start with /"},
+		{rule: sharedconfig.SpanNameSanitizationRule{Template: "/users/{id"}, expectedErr: "span_name_sanitization_rules[0]: invalid template \"/users/{id\": unclosed {"},
+		{rule: sharedconfig.SpanNameSanitizationRule{Template: "/users/{}"}, expectedErr: "span_name_sanitization_rules[0]: invalid template \"/users/{}\": empty parameter name"},
+		{rule: sharedconfig.SpanNameSanitizationRule{Template: "/users/id}"}, expectedErr: "span_name_sanitization_rules[0]: invalid template \"/users/id}\": unexpected }"},
+		{rule: sharedconfig.SpanNameSanitizationRule{Template: "/users/{id}"}},
+	}
+
+	for _, tc := range tcs {
+		err := ValidateSpanNameSanitizationRules([]sharedconfig.SpanNameSanitizationRule{tc.rule})
+		if tc.expectedErr == "" {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.EqualError(t, err, tc.expectedErr)
+	}
+}
@@ -6,13 +6,14 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/grafana/tempo/pkg/sharedconfig"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/storage"
 )
 
 // newTestLabelLimiter returns a PerLabelLimiter with limiting disabled (maxCardinality=0).
 func newTestLabelLimiter() *PerLabelLimiter {
-	return NewPerLabelLimiter("test", func(string) uint64 { return 0 }, 0)
+	return NewPerLabelLimiter("test", func(string) uint64 { return 0 }, func(string) string { return "" }, 0)
 }
 
 // TestRegistry is a simple implementation of Registry intended for tests. It is not concurrent-safe.
@@ -44,7 +45,7 @@ func (t *TestRegistry) NewGauge(name string) Gauge {
 }
 
 func (t *TestRegistry) NewLabelBuilder() LabelBuilder {
-	nds := NewDrainSanitizer("test", func(string) string { return SpanNameSanitizationDisabled }, 0)
+	nds := NewDrainSanitizer("test", func(string) string { return SpanNameSanitizationDisabled }, func(string) []sharedconfig.SpanNameSanitizationRule { return nil }, 0)
 
 	return NewLabelBuilder(0, 0, nds, newTestLabelLimiter())
 }
@@ -1,6 +1,9 @@
 package registry
 
-import "github.com/prometheus/prometheus/model/labels"
+import (
+	"github.com/prometheus/prometheus/model/labels"
+	"go.uber.org/atomic"
+)
 
 // newSeriesLabelsBuilder creates a labels builder with user labels and external labels pre-populated.
 func newSeriesLabelsBuilder(lbls labels.Labels, externalLabels map[string]string) *labels.Builder {
@@ -17,3 +20,13 @@ func getSeriesLabels(metricName string, lbls labels.Labels, externalLabels map[s
 	builder.Set(labels.MetricName, metricName)
 	return builder.Labels()
 }
+
+// swapFloat64 stores the new value and returns the old value.
+func swapFloat64(f *atomic.Float64, value float64) float64 {
+	for {
+		old := f.Load()
+		if f.CompareAndSwap(old, value) {
+			return old
+		}
+	}
+}
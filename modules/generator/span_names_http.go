@@ -0,0 +1,31 @@
+package generator
+
+import (
+	"net/http"
+
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// SpanNamesHandler shows the span name sanitization rules and their hits, and the span name
+// patterns found by drain for every tenant. Use the tenant query parameter to show a single tenant.
+func (g *Generator) SpanNamesHandler(w http.ResponseWriter, req *http.Request) {
+	tenant := req.URL.Query().Get("tenant")
+
+	tenants := make(map[string]registry.SpanNameSanitizationStatus)
+	for _, inst := range g.getInstances() {
+		if tenant != "" && inst.instanceID != tenant {
+			continue
+		}
+		if status, ok := inst.registry.SpanNameSanitizationStatus(); ok {
+			tenants[inst.instanceID] = status
+		}
+	}
+
+	if tenant != "" && len(tenants) == 0 {
+		http.Error(w, "tenant not found: "+tenant, http.StatusNotFound)
+		return
+	}
+
+	util.WriteJSONResponse(w, tenants)
+}
@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/generator/processor"
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/sharedconfig"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestGenerator_SpanNamesHandler(t *testing.T) {
+	overrides := &mockOverrides{
+		processors: map[string]struct{}{
+			processor.SpanMetricsName: {},
+		},
+		spanNameSanitizationRules: []sharedconfig.SpanNameSanitizationRule{{Template: "/users/{id}"}},
+	}
+	cfg := &Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+
+	inst, err := newInstance(cfg, "test", overrides, &noopStorage{}, log.NewNopLogger())
+	require.NoError(t, err)
+	defer inst.shutdown()
+
+	batch := test.MakeBatch(2, nil)
+	for _, ss := range batch.ScopeSpans {
+		for i, span := range ss.Spans {
+			span.Name = fmt.Sprintf("GET /users/%d", i)
+		}
+	}
+	inst.pushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1.ResourceSpans{batch}})
+
+	g := &Generator{
+		instances: map[string]*instance{"test": inst},
+	}
+
+	rec := httptest.NewRecorder()
+	g.SpanNamesHandler(rec, httptest.NewRequest(http.MethodGet, "/metrics-generator/span_names", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var tenants map[string]registry.SpanNameSanitizationStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tenants))
+	require.Contains(t, tenants, "test")
+	require.Len(t, tenants["test"].Rules, 1)
+	assert.Equal(t, "/users/{id}", tenants["test"].Rules[0].Template)
+	assert.Positive(t, tenants["test"].Rules[0].Hits)
+
+	rec = httptest.NewRecorder()
+	g.SpanNamesHandler(rec, httptest.NewRequest(http.MethodGet, "/metrics-generator/span_names?tenant=other", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/grafana/dskit/backoff"
+	dstls "github.com/grafana/dskit/crypto/tls"
 	"github.com/prometheus/common/model"
 	prometheus_config "github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/tsdb/agent"
@@ -26,6 +28,68 @@ type Config struct {
 	// Prometheus remote write config
 	// https://prometheus.io/docs/prometheus/latest/configuration/configuration/#remote_write
 	RemoteWrite []prometheus_config.RemoteWriteConfig `yaml:"remote_write,omitempty"`
+
+	// OTLP metrics export, metrics are exported alongside remote write if an endpoint is configured
+	OTLPExport OTLPExportConfig `yaml:"otlp_export,omitempty"`
+}
+
+const (
+	OTLPProtocolGRPC = "grpc"
+	OTLPProtocolHTTP = "http"
+
+	OTLPTemporalityCumulative = "cumulative"
+	OTLPTemporalityDelta      = "delta"
+)
+
+type OTLPExportConfig struct {
+	// Endpoint is host:port for grpc and the URL of the metrics endpoint for http, e.g.
+	// http://localhost:4318/v1/metrics. It can be overridden per tenant.
+	Endpoint string `yaml:"endpoint"`
+
+	// Protocol is grpc or http.
+	Protocol string `yaml:"protocol"`
+
+	// Temporality of counters and histograms, cumulative or delta.
+	Temporality string `yaml:"temporality"`
+
+	// Headers are added to every export request. Per tenant headers are added as well.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Add X-Scope-OrgID header in export requests
+	AddOrgIDHeader bool `yaml:"add_org_id_header"`
+
+	// Timeout of an export request
+	Timeout time.Duration `yaml:"timeout"`
+
+	// QueueSize is the number of exports buffered while the previous ones are sent. New exports
+	// are dropped while the queue is full.
+	QueueSize int `yaml:"queue_size"`
+
+	// Backoff of the retries of failed exports.
+	Backoff backoff.Config `yaml:"backoff"`
+
+	TLSEnabled bool               `yaml:"tls_enabled"`
+	TLS        dstls.ClientConfig `yaml:",inline"`
+
+	// DeltaSamples is set if the registry appends the increase since the previous collection
+	// instead of totals. The samples are exported as deltas as they are. This is injected by the
+	// metrics-generator and not user configurable.
+	DeltaSamples bool `yaml:"-"`
+}
+
+func (cfg *OTLPExportConfig) Validate() error {
+	// empty values fall back to the defaults
+	switch cfg.Protocol {
+	case "", OTLPProtocolGRPC, OTLPProtocolHTTP:
+	default:
+		return fmt.Errorf("invalid otlp export protocol %q, valid values are %s and %s", cfg.Protocol, OTLPProtocolGRPC, OTLPProtocolHTTP)
+	}
+	switch cfg.Temporality {
+	case "", OTLPTemporalityCumulative, OTLPTemporalityDelta:
+	default:
+		return fmt.Errorf("invalid otlp export temporality %q, valid values are %s and %s", cfg.Temporality, OTLPTemporalityCumulative, OTLPTemporalityDelta)
+	}
+	return nil
 }
 
 // Validate initializes and validates the remote write configurations. This must
@@ -38,7 +102,7 @@ func (cfg *Config) Validate() error {
 			return fmt.Errorf("invalid remote write config %q: %w", cfg.RemoteWrite[i].Name, err)
 		}
 	}
-	return nil
+	return cfg.OTLPExport.Validate()
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
@@ -47,6 +111,17 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 	cfg.RemoteWriteFlushDeadline = time.Minute
 
 	cfg.RemoteWriteAddOrgIDHeader = true
+
+	cfg.OTLPExport.Protocol = OTLPProtocolGRPC
+	cfg.OTLPExport.Temporality = OTLPTemporalityCumulative
+	cfg.OTLPExport.AddOrgIDHeader = true
+	cfg.OTLPExport.Timeout = 10 * time.Second
+	cfg.OTLPExport.QueueSize = 10
+	cfg.OTLPExport.Backoff = backoff.Config{
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: 5 * time.Second,
+		MaxRetries: 5,
+	}
 }
 
 // agentOptions is a copy of agent.Options but with yaml struct tags. Refer to agent.Options for
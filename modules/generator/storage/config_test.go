@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/dskit/backoff"
 	prometheus_common_config "github.com/prometheus/common/config"
 	prometheus_config "github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/util/compression"
@@ -49,6 +50,18 @@ remote_write:
 		RemoteWrite: []prometheus_config.RemoteWriteConfig{
 			remoteWriteConfig,
 		},
+		OTLPExport: OTLPExportConfig{
+			Protocol:       OTLPProtocolGRPC,
+			Temporality:    OTLPTemporalityCumulative,
+			AddOrgIDHeader: true,
+			Timeout:        10 * time.Second,
+			QueueSize:      10,
+			Backoff: backoff.Config{
+				MinBackoff: 100 * time.Millisecond,
+				MaxBackoff: 5 * time.Second,
+				MaxRetries: 5,
+			},
+		},
 	}
 	assert.Equal(t, expectedCfg, cfg)
 }
@@ -117,11 +117,17 @@ func New(cfg *Config, o Overrides, tenant string, reg prometheus.Registerer, _ l
 		return nil, err
 	}
 
+	// Set up OTLP export, the endpoint can be set per tenant at runtime so it's always added
+	otlpExporter, err := newOTLPExporter(&cfg.OTLPExport, o, tenant, logger.With("component", "otlp"))
+	if err != nil {
+		return nil, err
+	}
+
 	s := &storageImpl{
 		cfg:     cfg,
 		walDir:  walDir,
 		remote:  remoteStorage,
-		storage: storage.NewFanout(logger, wal, remoteStorage),
+		storage: storage.NewFanout(logger, wal, remoteStorage, otlpExporter),
 
 		tenantID:             tenant,
 		currentHeaders:       headers,
@@ -209,7 +209,7 @@ func TestInstance_remoteWriteHeaders(t *testing.T) {
 
 	headers := map[string]string{user.OrgIDHeaderName: "my-other-tenant"}
 
-	instance, err := New(&cfg, &mockOverrides{headers, histograms.HistogramMethodClassic}, "test-tenant", &noopRegisterer{}, logger)
+	instance, err := New(&cfg, &mockOverrides{headers: headers, nativeHistograms: histograms.HistogramMethodClassic}, "test-tenant", &noopRegisterer{}, logger)
 	require.NoError(t, err)
 
 	// Refuse requests - the WAL should buffer data until requests succeed
@@ -355,6 +355,8 @@ var _ Overrides = (*mockOverrides)(nil)
 type mockOverrides struct {
 	headers          map[string]string
 	nativeHistograms histograms.HistogramMethod
+	otlpEndpoint     string
+	otlpHeaders      map[string]string
 }
 
 func (m *mockOverrides) MetricsGeneratorRemoteWriteHeaders(string) map[string]string {
@@ -365,6 +367,14 @@ func (m *mockOverrides) MetricsGeneratorGenerateNativeHistograms(string) histogr
 	return m.nativeHistograms
 }
 
+func (m *mockOverrides) MetricsGeneratorOTLPExportEndpoint(string) string {
+	return m.otlpEndpoint
+}
+
+func (m *mockOverrides) MetricsGeneratorOTLPExportHeaders(string) map[string]string {
+	return m.otlpHeaders
+}
+
 var _ prometheus.Registerer = (*noopRegisterer)(nil)
 
 type noopRegisterer struct{}
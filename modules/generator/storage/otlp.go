@@ -0,0 +1,754 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	grpc_metadata "google.golang.org/grpc/metadata"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+var (
+	metricStorageOTLPExportFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_storage_otlp_export_failed_total",
+		Help:      "The total number of times exporting metrics over OTLP failed",
+	}, []string{"tenant"})
+	metricStorageOTLPExportDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_storage_otlp_export_dropped_total",
+		Help:      "The total number of OTLP exports dropped because the queue was full or all retries failed",
+	}, []string{"tenant"})
+)
+
+const otlpScopeName = "tempo-metrics-generator"
+
+// otlpExporter exports the samples appended by the registry as OTLP metrics. It is added to the
+// fanout storage next to the WAL, only appending is supported. The endpoint and headers are read
+// from the overrides on every commit, nothing is exported if no endpoint is configured. Commits
+// only convert the samples and queue them, they are sent in the background so that a slow endpoint
+// doesn't hold up the collection and remote write.
+type otlpExporter struct {
+	cfg       *OTLPExportConfig
+	tenant    string
+	overrides Overrides
+	logger    *slog.Logger
+
+	tlsConfig  *tls.Config
+	httpClient *http.Client
+
+	mtx sync.Mutex
+	// series holds the start time and the last exported values of every series
+	series map[uint64]*otlpSeriesState
+	// lastCommit is the timestamp of the latest sample of the previous commit, it's the start
+	// time of new series
+	lastCommit int64
+
+	queue  chan otlpExport
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// conn is only used by the goroutine sending the exports
+	conn         *grpc.ClientConn
+	connEndpoint string
+}
+
+type otlpExport struct {
+	endpoint string
+	req      pmetricotlp.ExportRequest
+}
+
+var _ storage.Storage = (*otlpExporter)(nil)
+
+type otlpSeriesState struct {
+	start int64
+	last  int64
+
+	value     float64
+	sum       float64
+	count     float64
+	buckets   []float64
+	histogram *histogram.FloatHistogram
+}
+
+func newOTLPExporter(cfg *OTLPExportConfig, o Overrides, tenant string, logger *slog.Logger) (*otlpExporter, error) {
+	var tlsConfig *tls.Config
+	if cfg.TLSEnabled {
+		var err error
+		tlsConfig, err = cfg.TLS.GetTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("invalid otlp export tls config: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &otlpExporter{
+		cfg:       cfg,
+		tenant:    tenant,
+		overrides: o,
+		logger:    logger,
+		tlsConfig: tlsConfig,
+		httpClient: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		series:     map[uint64]*otlpSeriesState{},
+		lastCommit: time.Now().UnixMilli(),
+		queue:      make(chan otlpExport, max(cfg.QueueSize, 1)),
+		ctx:        ctx,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+	go e.run()
+
+	return e, nil
+}
+
+// endpoint returns the endpoint of the tenant, the per-tenant override takes precedence.
+func (e *otlpExporter) endpoint() string {
+	if endpoint := e.overrides.MetricsGeneratorOTLPExportEndpoint(e.tenant); endpoint != "" {
+		return endpoint
+	}
+	return e.cfg.Endpoint
+}
+
+func (e *otlpExporter) headers() map[string]string {
+	headers := make(map[string]string, len(e.cfg.Headers)+1)
+	maps.Copy(headers, e.cfg.Headers)
+	maps.Copy(headers, e.overrides.MetricsGeneratorOTLPExportHeaders(e.tenant))
+
+	// Inject X-Scope-OrgID header in multi-tenant setups if not set already
+	if e.tenant != util.FakeTenantID && e.cfg.AddOrgIDHeader {
+		for k := range headers {
+			if strings.EqualFold(user.OrgIDHeaderName, strings.TrimSpace(k)) {
+				return headers
+			}
+		}
+		headers[user.OrgIDHeaderName] = e.tenant
+	}
+	return headers
+}
+
+func (e *otlpExporter) Appender(context.Context) storage.Appender {
+	return &otlpAppender{
+		exporter:   e,
+		endpoint:   e.endpoint(),
+		types:      map[string]model.MetricType{},
+		samples:    map[uint64]*otlpSample{},
+		histograms: map[uint64]*otlpHistogramSample{},
+	}
+}
+
+func (e *otlpExporter) Querier(int64, int64) (storage.Querier, error) {
+	return storage.NoopQuerier(), nil
+}
+
+func (e *otlpExporter) ChunkQuerier(int64, int64) (storage.ChunkQuerier, error) {
+	return storage.NoopChunkedQuerier(), nil
+}
+
+func (e *otlpExporter) StartTime() (int64, error) {
+	return int64(model.Latest), nil
+}
+
+// Close stops sending the exports, the exports that are still queued are dropped.
+func (e *otlpExporter) Close() error {
+	e.cancel()
+	<-e.done
+
+	if e.conn == nil {
+		return nil
+	}
+	err := e.conn.Close()
+	e.conn = nil
+	return err
+}
+
+// enqueue converts the samples of the appender and queues them to be sent. The export is dropped
+// if the queue is full.
+func (e *otlpExporter) enqueue(a *otlpAppender) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	md := e.convert(a)
+	if md.DataPointCount() == 0 {
+		return
+	}
+
+	select {
+	case e.queue <- otlpExport{endpoint: a.endpoint, req: pmetricotlp.NewExportRequestFromMetrics(md)}:
+	default:
+		metricStorageOTLPExportDropped.WithLabelValues(e.tenant).Inc()
+		e.logger.Warn("dropped OTLP export, the queue is full", "endpoint", a.endpoint)
+	}
+}
+
+// run sends the queued exports until the exporter is closed.
+func (e *otlpExporter) run() {
+	defer close(e.done)
+
+	for {
+		select {
+		case <-e.ctx.Done():
+			metricStorageOTLPExportDropped.WithLabelValues(e.tenant).Add(float64(len(e.queue)))
+			return
+		case export := <-e.queue:
+			e.send(export)
+		}
+	}
+}
+
+// send sends the export, failed exports are retried with backoff. Export failures are logged and
+// counted but not returned, they should not affect remote write.
+func (e *otlpExporter) send(export otlpExport) {
+	retries := backoff.New(e.ctx, e.cfg.Backoff)
+	for {
+		err := e.exportOnce(export)
+		if err == nil {
+			return
+		}
+
+		metricStorageOTLPExportFailed.WithLabelValues(e.tenant).Inc()
+		e.logger.Warn("failed to export metrics over OTLP", "endpoint", export.endpoint, "err", err.Error())
+
+		retries.Wait()
+		if !retries.Ongoing() {
+			break
+		}
+	}
+	metricStorageOTLPExportDropped.WithLabelValues(e.tenant).Inc()
+}
+
+func (e *otlpExporter) exportOnce(export otlpExport) error {
+	ctx := e.ctx
+	if e.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.cfg.Timeout)
+		defer cancel()
+	}
+
+	switch e.cfg.Protocol {
+	case OTLPProtocolHTTP:
+		return e.exportHTTP(ctx, export.endpoint, export.req)
+	default:
+		return e.exportGRPC(ctx, export.endpoint, export.req)
+	}
+}
+
+func (e *otlpExporter) exportGRPC(ctx context.Context, endpoint string, req pmetricotlp.ExportRequest) error {
+	if e.conn == nil || e.connEndpoint != endpoint {
+		if e.conn != nil {
+			_ = e.conn.Close()
+			e.conn = nil
+		}
+
+		creds := insecure.NewCredentials()
+		if e.tlsConfig != nil {
+			creds = credentials.NewTLS(e.tlsConfig)
+		}
+		conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return err
+		}
+		e.conn = conn
+		e.connEndpoint = endpoint
+	}
+
+	ctx = grpc_metadata.NewOutgoingContext(ctx, grpc_metadata.New(e.headers()))
+	_, err := pmetricotlp.NewGRPCClient(e.conn).Export(ctx, req)
+	return err
+}
+
+func (e *otlpExporter) exportHTTP(ctx context.Context, endpoint string, req pmetricotlp.ExportRequest) error {
+	body, err := req.MarshalProto()
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range e.headers() {
+		httpReq.Header.Set(k, v)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := e.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// convert builds the OTLP metrics from the samples of the appender. Counters are converted to
+// monotonic sums, gauges to gauges, classic histograms to histograms and native histograms to
+// exponential histograms. The state of the series is updated once they are converted, an export
+// that fails is not included in the following deltas. New series start at the previous commit.
+func (e *otlpExporter) convert(a *otlpAppender) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	sm := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName(otlpScopeName)
+
+	temporality := pmetric.AggregationTemporalityCumulative
+	if e.cfg.Temporality == OTLPTemporalityDelta || e.cfg.DeltaSamples {
+		temporality = pmetric.AggregationTemporalityDelta
+	}
+
+	series := make(map[uint64]*otlpSeriesState, len(e.series))
+	var lastCommit int64
+	// newSeries returns the state of a series that wasn't exported before
+	newSeries := func(start, t int64) *otlpSeriesState {
+		lastCommit = max(lastCommit, t)
+		if e.lastCommit < start {
+			start = e.lastCommit
+		}
+		return &otlpSeriesState{start: start, last: t}
+	}
+	metrics := map[string]pmetric.Metric{}
+	metric := func(name string, init func(m pmetric.Metric)) pmetric.Metric {
+		m, ok := metrics[name]
+		if !ok {
+			m = sm.Metrics().AppendEmpty()
+			m.SetName(name)
+			init(m)
+			metrics[name] = m
+		}
+		return m
+	}
+
+	// nativeHistograms holds the names of the native histograms, classic series of the same
+	// histogram are not exported twice
+	nativeHistograms := map[string]struct{}{}
+	for _, hash := range sortedKeys(a.histograms) {
+		s := a.histograms[hash]
+		name := s.labels.Get(labels.MetricName)
+		nativeHistograms[name] = struct{}{}
+
+		prev := e.series[hash]
+		state := newSeries(s.start, s.t)
+		state.histogram = s.h
+		if prev != nil {
+			state.start = prev.start
+		}
+		series[hash] = state
+
+		h := s.h
+		start := state.start
+		if temporality == pmetric.AggregationTemporalityDelta && prev != nil {
+			start = prev.last
+			if d, ok := histogramDelta(s.h, prev.histogram); ok && !e.cfg.DeltaSamples {
+				h = d
+			}
+		}
+
+		m := metric(name, func(m pmetric.Metric) {
+			m.SetEmptyExponentialHistogram().SetAggregationTemporality(temporality)
+		})
+		dp := m.ExponentialHistogram().DataPoints().AppendEmpty()
+		setAttributes(dp.Attributes(), s.labels)
+		dp.SetStartTimestamp(otlpTimestamp(start))
+		dp.SetTimestamp(otlpTimestamp(s.t))
+		setExponentialHistogram(dp, h)
+	}
+
+	classicHistograms := map[uint64]*otlpClassicHistogram{}
+	for _, hash := range sortedKeys(a.samples) {
+		s := a.samples[hash]
+		name := s.labels.Get(labels.MetricName)
+
+		if base, suffix, ok := a.histogramSeries(name); ok {
+			if _, ok := nativeHistograms[base]; ok {
+				continue
+			}
+			addClassicHistogramSample(classicHistograms, base, suffix, s)
+			continue
+		}
+
+		prev := e.series[hash]
+		state := newSeries(s.start, s.t)
+		state.value = s.v
+		if prev != nil {
+			state.start = prev.start
+		}
+		series[hash] = state
+
+		if a.types[name] != model.MetricTypeCounter {
+			m := metric(name, func(m pmetric.Metric) { m.SetEmptyGauge() })
+			dp := m.Gauge().DataPoints().AppendEmpty()
+			setAttributes(dp.Attributes(), s.labels)
+			dp.SetTimestamp(otlpTimestamp(s.t))
+			dp.SetDoubleValue(s.v)
+			continue
+		}
+
+		v := s.v
+		start := state.start
+		if temporality == pmetric.AggregationTemporalityDelta && prev != nil {
+			start = prev.last
+			// a counter that decreased has been reset, the new value is the delta
+			if v >= prev.value && !e.cfg.DeltaSamples {
+				v -= prev.value
+			}
+		}
+
+		m := metric(name, func(m pmetric.Metric) {
+			sum := m.SetEmptySum()
+			sum.SetIsMonotonic(true)
+			sum.SetAggregationTemporality(temporality)
+		})
+		dp := m.Sum().DataPoints().AppendEmpty()
+		setAttributes(dp.Attributes(), s.labels)
+		dp.SetStartTimestamp(otlpTimestamp(start))
+		dp.SetTimestamp(otlpTimestamp(s.t))
+		dp.SetDoubleValue(v)
+	}
+
+	for _, hash := range sortedKeys(classicHistograms) {
+		h := classicHistograms[hash]
+		bounds, cumulative := h.cumulativeBuckets()
+
+		prev := e.series[hash]
+		state := newSeries(h.start, h.t)
+		state.sum, state.count, state.buckets = h.sum, h.count, cumulative
+		if prev != nil {
+			state.start = prev.start
+		}
+		series[hash] = state
+
+		sum, count := h.sum, h.count
+		start := state.start
+		if temporality == pmetric.AggregationTemporalityDelta && prev != nil {
+			start = prev.last
+			if d, ok := classicHistogramDelta(state, prev); ok && !e.cfg.DeltaSamples {
+				sum, count, cumulative = d.sum, d.count, d.buckets
+			}
+		}
+
+		m := metric(h.name, func(m pmetric.Metric) {
+			m.SetEmptyHistogram().SetAggregationTemporality(temporality)
+		})
+		dp := m.Histogram().DataPoints().AppendEmpty()
+		setAttributes(dp.Attributes(), h.labels)
+		dp.SetStartTimestamp(otlpTimestamp(start))
+		dp.SetTimestamp(otlpTimestamp(h.t))
+		dp.SetSum(sum)
+		dp.SetCount(uint64(count))
+		dp.ExplicitBounds().FromRaw(bounds)
+
+		// OTLP bucket counts are not cumulative, the last bucket is the +Inf bucket
+		counts := make([]uint64, len(cumulative)+1)
+		var previous float64
+		for i, c := range cumulative {
+			counts[i] = uint64(c - previous)
+			previous = c
+		}
+		counts[len(cumulative)] = uint64(math.Max(count-previous, 0))
+		dp.BucketCounts().FromRaw(counts)
+	}
+
+	if md.DataPointCount() > 0 {
+		e.series = series
+		e.lastCommit = lastCommit
+	}
+	return md
+}
+
+// otlpAppender buffers the samples of a single collection, only the latest sample of every series
+// is exported on commit.
+type otlpAppender struct {
+	exporter *otlpExporter
+	endpoint string
+
+	types      map[string]model.MetricType
+	samples    map[uint64]*otlpSample
+	histograms map[uint64]*otlpHistogramSample
+}
+
+var _ storage.Appender = (*otlpAppender)(nil)
+
+type otlpSample struct {
+	labels labels.Labels
+	// start is the timestamp of the first sample of the series in this collection
+	start int64
+	t     int64
+	v     float64
+}
+
+type otlpHistogramSample struct {
+	labels labels.Labels
+	start  int64
+	t      int64
+	h      *histogram.FloatHistogram
+}
+
+func (a *otlpAppender) Append(_ storage.SeriesRef, l labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
+	if a.endpoint == "" {
+		return 0, nil
+	}
+
+	hash := l.Hash()
+	if s, ok := a.samples[hash]; ok {
+		if t >= s.t {
+			s.t, s.v = t, v
+		}
+		s.start = min(s.start, t)
+		return 0, nil
+	}
+	a.samples[hash] = &otlpSample{labels: l, start: t, t: t, v: v}
+	return 0, nil
+}
+
+func (a *otlpAppender) AppendHistogram(_ storage.SeriesRef, l labels.Labels, t int64, h *histogram.Histogram, fh *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	if a.endpoint == "" {
+		return 0, nil
+	}
+
+	if fh == nil {
+		fh = h.ToFloat(nil)
+	}
+
+	hash := l.Hash()
+	if s, ok := a.histograms[hash]; ok {
+		if t >= s.t {
+			s.t, s.h = t, fh
+		}
+		s.start = min(s.start, t)
+		return 0, nil
+	}
+	a.histograms[hash] = &otlpHistogramSample{labels: l, start: t, t: t, h: fh}
+	return 0, nil
+}
+
+func (a *otlpAppender) UpdateMetadata(_ storage.SeriesRef, l labels.Labels, m metadata.Metadata) (storage.SeriesRef, error) {
+	a.types[l.Get(labels.MetricName)] = m.Type
+	return 0, nil
+}
+
+func (a *otlpAppender) AppendExemplar(storage.SeriesRef, labels.Labels, exemplar.Exemplar) (storage.SeriesRef, error) {
+	return 0, nil
+}
+
+func (a *otlpAppender) AppendCTZeroSample(storage.SeriesRef, labels.Labels, int64, int64) (storage.SeriesRef, error) {
+	return 0, nil
+}
+
+func (a *otlpAppender) AppendHistogramCTZeroSample(storage.SeriesRef, labels.Labels, int64, int64, *histogram.Histogram, *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	return 0, nil
+}
+
+func (a *otlpAppender) SetOptions(*storage.AppendOptions) {}
+
+func (a *otlpAppender) Commit() error {
+	if a.endpoint == "" {
+		return nil
+	}
+	a.exporter.enqueue(a)
+	return nil
+}
+
+func (a *otlpAppender) Rollback() error {
+	return nil
+}
+
+// histogramSeries returns the name of the histogram and the suffix if the series is part of a
+// classic histogram.
+func (a *otlpAppender) histogramSeries(name string) (string, string, bool) {
+	for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+		base, ok := strings.CutSuffix(name, suffix)
+		if ok && a.types[base] == model.MetricTypeHistogram {
+			return base, suffix, true
+		}
+	}
+	return "", "", false
+}
+
+type otlpClassicHistogram struct {
+	name   string
+	labels labels.Labels
+	start  int64
+	t      int64
+
+	sum     float64
+	count   float64
+	buckets map[float64]float64
+}
+
+func addClassicHistogramSample(histograms map[uint64]*otlpClassicHistogram, name, suffix string, s *otlpSample) {
+	lb := labels.NewBuilder(s.labels)
+	lb.Set(labels.MetricName, name)
+	lb.Del(labels.BucketLabel)
+	lbls := lb.Labels()
+
+	hash := lbls.Hash()
+	h, ok := histograms[hash]
+	if !ok {
+		h = &otlpClassicHistogram{
+			name:    name,
+			labels:  lbls,
+			start:   s.start,
+			t:       s.t,
+			buckets: map[float64]float64{},
+		}
+		histograms[hash] = h
+	}
+	h.start = min(h.start, s.start)
+	h.t = max(h.t, s.t)
+
+	switch suffix {
+	case "_sum":
+		h.sum = s.v
+	case "_count":
+		h.count = s.v
+	case "_bucket":
+		le, err := strconv.ParseFloat(s.labels.Get(labels.BucketLabel), 64)
+		if err != nil || math.IsInf(le, 1) {
+			return
+		}
+		h.buckets[le] = s.v
+	}
+}
+
+// cumulativeBuckets returns the sorted upper bounds and cumulative counts of the buckets, the +Inf
+// bucket is not included.
+func (h *otlpClassicHistogram) cumulativeBuckets() ([]float64, []float64) {
+	bounds := slices.Sorted(maps.Keys(h.buckets))
+	counts := make([]float64, len(bounds))
+	for i, b := range bounds {
+		counts[i] = h.buckets[b]
+	}
+	return bounds, counts
+}
+
+// classicHistogramDelta subtracts the previous state. false is returned if the histogram has been
+// reset or the buckets changed.
+func classicHistogramDelta(cur, prev *otlpSeriesState) (*otlpSeriesState, bool) {
+	if len(cur.buckets) != len(prev.buckets) || cur.count < prev.count {
+		return nil, false
+	}
+
+	d := &otlpSeriesState{
+		sum:     cur.sum - prev.sum,
+		count:   cur.count - prev.count,
+		buckets: make([]float64, len(cur.buckets)),
+	}
+	for i := range cur.buckets {
+		d.buckets[i] = cur.buckets[i] - prev.buckets[i]
+		if d.buckets[i] < 0 {
+			return nil, false
+		}
+	}
+	return d, true
+}
+
+// histogramDelta subtracts the previous native histogram. false is returned if the histogram has
+// been reset.
+func histogramDelta(cur, prev *histogram.FloatHistogram) (*histogram.FloatHistogram, bool) {
+	if prev == nil || cur.Count < prev.Count {
+		return nil, false
+	}
+
+	d, _, err := cur.Copy().Sub(prev)
+	if err != nil || d.ZeroCount < 0 {
+		return nil, false
+	}
+	for _, c := range d.PositiveBuckets {
+		if c < 0 {
+			return nil, false
+		}
+	}
+	for _, c := range d.NegativeBuckets {
+		if c < 0 {
+			return nil, false
+		}
+	}
+	return d, true
+}
+
+func setExponentialHistogram(dp pmetric.ExponentialHistogramDataPoint, h *histogram.FloatHistogram) {
+	dp.SetScale(h.Schema)
+	dp.SetCount(uint64(h.Count))
+	dp.SetSum(h.Sum)
+	dp.SetZeroCount(uint64(h.ZeroCount))
+	dp.SetZeroThreshold(h.ZeroThreshold)
+	setExponentialBuckets(dp.Positive(), h.PositiveSpans, h.PositiveBuckets)
+	setExponentialBuckets(dp.Negative(), h.NegativeSpans, h.NegativeBuckets)
+}
+
+// setExponentialBuckets converts the sparse buckets of a native histogram to the dense buckets of
+// an OTLP exponential histogram. Prometheus bucket i covers (base^(i-1), base^i], OTLP bucket i
+// covers (base^i, base^(i+1)], so the offset is shifted by one.
+func setExponentialBuckets(b pmetric.ExponentialHistogramDataPointBuckets, spans []histogram.Span, counts []float64) {
+	if len(spans) == 0 {
+		return
+	}
+	b.SetOffset(spans[0].Offset - 1)
+
+	bucketCounts := b.BucketCounts()
+	var i int
+	for n, span := range spans {
+		// the offset of the following spans is the gap to the previous span
+		if n > 0 {
+			for range span.Offset {
+				bucketCounts.Append(0)
+			}
+		}
+		for range span.Length {
+			bucketCounts.Append(uint64(counts[i]))
+			i++
+		}
+	}
+}
+
+func setAttributes(attrs pcommon.Map, lbls labels.Labels) {
+	lbls.Range(func(l labels.Label) {
+		if l.Name == labels.MetricName {
+			return
+		}
+		attrs.PutStr(l.Name, l.Value)
+	})
+}
+
+func otlpTimestamp(ms int64) pcommon.Timestamp {
+	return pcommon.NewTimestampFromTime(time.UnixMilli(ms))
+}
+
+func sortedKeys[V any](m map[uint64]V) []uint64 {
+	return slices.Sorted(maps.Keys(m))
+}
@@ -0,0 +1,382 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/backoff"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"google.golang.org/grpc"
+	grpc_metadata "google.golang.org/grpc/metadata"
+)
+
+func TestOTLPExport_grpc(t *testing.T) {
+	receiver := &mockOTLPReceiver{}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	pmetricotlp.RegisterGRPCServer(srv, receiver)
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	var cfg Config
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Path = t.TempDir()
+	cfg.OTLPExport.Headers = map[string]string{"foo": "bar"}
+
+	o := &mockOverrides{
+		otlpEndpoint: lis.Addr().String(),
+		otlpHeaders:  map[string]string{"tenant-header": "value"},
+	}
+	instance, err := New(&cfg, o, "test-tenant", &noopRegisterer{}, log.NewNopLogger())
+	require.NoError(t, err)
+	defer instance.Close()
+
+	appendTestMetrics(t, instance.Appender(context.Background()), 1000, 1)
+
+	requests := receiver.waitForRequests(t, 1)
+	assert.Equal(t, []string{"bar"}, requests[0].headers.Get("foo"))
+	assert.Equal(t, []string{"value"}, requests[0].headers.Get("tenant-header"))
+	assert.Equal(t, []string{"test-tenant"}, requests[0].headers.Get("x-scope-orgid"))
+
+	metrics := metricsByName(requests[0].metrics)
+	require.Len(t, metrics, 4)
+
+	counter := metrics["calls_total"]
+	require.Equal(t, pmetric.MetricTypeSum, counter.Type())
+	assert.True(t, counter.Sum().IsMonotonic())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, counter.Sum().AggregationTemporality())
+	require.Equal(t, 1, counter.Sum().DataPoints().Len())
+	dp := counter.Sum().DataPoints().At(0)
+	assert.Equal(t, 1.0, dp.DoubleValue())
+	assert.Equal(t, time.UnixMilli(1000).UTC(), dp.Timestamp().AsTime())
+	v, _ := dp.Attributes().Get("service")
+	assert.Equal(t, "api", v.Str())
+
+	gauge := metrics["size"]
+	require.Equal(t, pmetric.MetricTypeGauge, gauge.Type())
+	assert.Equal(t, 1.0, gauge.Gauge().DataPoints().At(0).DoubleValue())
+
+	classic := metrics["latency"]
+	require.Equal(t, pmetric.MetricTypeHistogram, classic.Type())
+	hdp := classic.Histogram().DataPoints().At(0)
+	assert.Equal(t, []float64{1, 2}, hdp.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{1, 2, 1}, hdp.BucketCounts().AsRaw())
+	assert.Equal(t, uint64(4), hdp.Count())
+	assert.Equal(t, 5.0, hdp.Sum())
+	_, ok := hdp.Attributes().Get(labels.BucketLabel)
+	assert.False(t, ok)
+
+	native := metrics["native_latency"]
+	require.Equal(t, pmetric.MetricTypeExponentialHistogram, native.Type())
+	edp := native.ExponentialHistogram().DataPoints().At(0)
+	assert.Equal(t, int32(0), edp.Scale())
+	assert.Equal(t, uint64(4), edp.Count())
+	assert.Equal(t, uint64(1), edp.ZeroCount())
+	assert.Equal(t, int32(-1), edp.Positive().Offset())
+	assert.Equal(t, []uint64{1, 0, 2}, edp.Positive().BucketCounts().AsRaw())
+}
+
+func TestOTLPExport_httpDelta(t *testing.T) {
+	receiver := &mockOTLPReceiver{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		req := pmetricotlp.NewExportRequest()
+		require.NoError(t, req.UnmarshalProto(body))
+		_, _ = receiver.Export(grpc_metadata.NewIncomingContext(r.Context(), grpc_metadata.MD{"content-type": r.Header.Values("Content-Type")}), req)
+	}))
+	defer server.Close()
+
+	cfg := testOTLPExportConfig()
+	cfg.Protocol = OTLPProtocolHTTP
+	cfg.Temporality = OTLPTemporalityDelta
+	cfg.Endpoint = server.URL + "/v1/metrics"
+
+	exporter, err := newOTLPExporter(&cfg, &mockOverrides{}, "test-tenant", slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	defer exporter.Close()
+
+	appendTestMetrics(t, exporter.Appender(context.Background()), 1000, 1)
+	receiver.waitForRequests(t, 1)
+	appendTestMetrics(t, exporter.Appender(context.Background()), 2000, 3, "new_total")
+
+	requests := receiver.waitForRequests(t, 2)
+	assert.Equal(t, []string{"application/x-protobuf"}, requests[0].headers.Get("content-type"))
+
+	// the first export contains the values since the start of the series
+	first := metricsByName(requests[0].metrics)
+	assert.Equal(t, 1.0, first["calls_total"].Sum().DataPoints().At(0).DoubleValue())
+
+	second := metricsByName(requests[1].metrics)
+
+	counter := second["calls_total"]
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, counter.Sum().AggregationTemporality())
+	dp := counter.Sum().DataPoints().At(0)
+	assert.Equal(t, 2.0, dp.DoubleValue())
+	assert.Equal(t, time.UnixMilli(1000).UTC(), dp.StartTimestamp().AsTime())
+	assert.Equal(t, time.UnixMilli(2000).UTC(), dp.Timestamp().AsTime())
+
+	// a new series starts at the previous export
+	dp = second["new_total"].Sum().DataPoints().At(0)
+	assert.Equal(t, 3.0, dp.DoubleValue())
+	assert.Equal(t, time.UnixMilli(1000).UTC(), dp.StartTimestamp().AsTime())
+	assert.Equal(t, time.UnixMilli(2000).UTC(), dp.Timestamp().AsTime())
+
+	// gauges are not changed
+	assert.Equal(t, 3.0, second["size"].Gauge().DataPoints().At(0).DoubleValue())
+
+	hdp := second["latency"].Histogram().DataPoints().At(0)
+	assert.Equal(t, []uint64{2, 4, 2}, hdp.BucketCounts().AsRaw())
+	assert.Equal(t, uint64(8), hdp.Count())
+	assert.Equal(t, 10.0, hdp.Sum())
+
+	edp := second["native_latency"].ExponentialHistogram().DataPoints().At(0)
+	assert.Equal(t, uint64(8), edp.Count())
+	assert.Equal(t, []uint64{2, 0, 4}, edp.Positive().BucketCounts().AsRaw())
+
+	// a reset counter exports the new value
+	appendTestMetrics(t, exporter.Appender(context.Background()), 3000, 1)
+	requests = receiver.waitForRequests(t, 3)
+	assert.Equal(t, 1.0, metricsByName(requests[2].metrics)["calls_total"].Sum().DataPoints().At(0).DoubleValue())
+}
+
+func TestOTLPExport_deltaSamples(t *testing.T) {
+	receiver := &mockOTLPReceiver{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		req := pmetricotlp.NewExportRequest()
+		require.NoError(t, req.UnmarshalProto(body))
+		_, _ = receiver.Export(grpc_metadata.NewIncomingContext(r.Context(), grpc_metadata.MD{"content-type": r.Header.Values("Content-Type")}), req)
+	}))
+	defer server.Close()
+
+	// the registry appends deltas, the exporter must not subtract the previous samples
+	cfg := testOTLPExportConfig()
+	cfg.Protocol = OTLPProtocolHTTP
+	cfg.Temporality = OTLPTemporalityCumulative
+	cfg.DeltaSamples = true
+	cfg.Endpoint = server.URL + "/v1/metrics"
+
+	exporter, err := newOTLPExporter(&cfg, &mockOverrides{}, "test-tenant", slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	defer exporter.Close()
+
+	appendTestMetrics(t, exporter.Appender(context.Background()), 1000, 1)
+	appendTestMetrics(t, exporter.Appender(context.Background()), 2000, 3)
+
+	requests := receiver.waitForRequests(t, 2)
+
+	second := metricsByName(requests[1].metrics)
+
+	counter := second["calls_total"]
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, counter.Sum().AggregationTemporality())
+	dp := counter.Sum().DataPoints().At(0)
+	assert.Equal(t, 3.0, dp.DoubleValue())
+	assert.Equal(t, time.UnixMilli(1000).UTC(), dp.StartTimestamp().AsTime())
+	assert.Equal(t, time.UnixMilli(2000).UTC(), dp.Timestamp().AsTime())
+}
+
+func TestOTLPExport_retry(t *testing.T) {
+	receiver := &mockOTLPReceiver{}
+	release := make(chan struct{})
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first export is slow and fails, it's retried
+		if calls.Add(1) == 1 {
+			<-release
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		req := pmetricotlp.NewExportRequest()
+		require.NoError(t, req.UnmarshalProto(body))
+		_, _ = receiver.Export(r.Context(), req)
+	}))
+	defer server.Close()
+
+	cfg := testOTLPExportConfig()
+	cfg.Protocol = OTLPProtocolHTTP
+	cfg.Endpoint = server.URL + "/v1/metrics"
+
+	exporter, err := newOTLPExporter(&cfg, &mockOverrides{}, "test-tenant", slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	defer exporter.Close()
+
+	// commits don't wait for the slow export
+	appendTestMetrics(t, exporter.Appender(context.Background()), 1000, 1)
+	appendTestMetrics(t, exporter.Appender(context.Background()), 2000, 2)
+	require.Empty(t, receiver.requests())
+
+	close(release)
+	requests := receiver.waitForRequests(t, 2)
+	assert.Equal(t, 1.0, metricsByName(requests[0].metrics)["calls_total"].Sum().DataPoints().At(0).DoubleValue())
+	assert.Equal(t, 2.0, metricsByName(requests[1].metrics)["calls_total"].Sum().DataPoints().At(0).DoubleValue())
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestOTLPExport_queueFull(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	cfg := testOTLPExportConfig()
+	cfg.Protocol = OTLPProtocolHTTP
+	cfg.Endpoint = server.URL + "/v1/metrics"
+	cfg.QueueSize = 1
+
+	exporter, err := newOTLPExporter(&cfg, &mockOverrides{}, "queue-full", slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	defer exporter.Close()
+
+	dropped := testutil.ToFloat64(metricStorageOTLPExportDropped.WithLabelValues("queue-full"))
+
+	// the first export is sent, the second one is queued and the others are dropped
+	for i := range 4 {
+		appendTestMetrics(t, exporter.Appender(context.Background()), int64(i+1)*1000, 1)
+		if i == 0 {
+			require.Eventually(t, func() bool { return len(exporter.queue) == 0 }, 5*time.Second, 10*time.Millisecond)
+		}
+	}
+	assert.Equal(t, dropped+2, testutil.ToFloat64(metricStorageOTLPExportDropped.WithLabelValues("queue-full")))
+}
+
+func TestOTLPExport_noEndpoint(t *testing.T) {
+	cfg := testOTLPExportConfig()
+
+	exporter, err := newOTLPExporter(&cfg, &mockOverrides{}, "test-tenant", slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	defer exporter.Close()
+
+	appendTestMetrics(t, exporter.Appender(context.Background()), 1000, 1)
+	assert.Empty(t, exporter.series)
+}
+
+func testOTLPExportConfig() OTLPExportConfig {
+	return OTLPExportConfig{
+		Protocol:    OTLPProtocolGRPC,
+		Temporality: OTLPTemporalityCumulative,
+		Timeout:     10 * time.Second,
+		QueueSize:   10,
+		Backoff:     backoff.Config{MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, MaxRetries: 3},
+	}
+}
+
+// appendTestMetrics appends a counter, a gauge, a classic and a native histogram like the registry
+// does, and the given extra counters. The values are multiplied by n.
+func appendTestMetrics(t *testing.T, appender storage.Appender, ts int64, n float64, counters ...string) {
+	updateMetadata := func(name string, typ model.MetricType) {
+		_, err := appender.UpdateMetadata(0, labels.FromStrings(labels.MetricName, name), metadata.Metadata{Type: typ})
+		require.NoError(t, err)
+	}
+	appendSample := func(v float64, lbls ...string) {
+		_, err := appender.Append(0, labels.FromStrings(append(lbls, "service", "api")...), ts, v)
+		require.NoError(t, err)
+	}
+
+	updateMetadata("calls_total", model.MetricTypeCounter)
+	appendSample(n, labels.MetricName, "calls_total")
+	for _, name := range counters {
+		updateMetadata(name, model.MetricTypeCounter)
+		appendSample(n, labels.MetricName, name)
+	}
+
+	updateMetadata("size", model.MetricTypeGauge)
+	appendSample(n, labels.MetricName, "size")
+
+	updateMetadata("latency", model.MetricTypeHistogram)
+	appendSample(n, labels.MetricName, "latency_bucket", labels.BucketLabel, "1")
+	appendSample(3*n, labels.MetricName, "latency_bucket", labels.BucketLabel, "2")
+	appendSample(4*n, labels.MetricName, "latency_bucket", labels.BucketLabel, "+Inf")
+	appendSample(5*n, labels.MetricName, "latency_sum")
+	appendSample(4*n, labels.MetricName, "latency_count")
+
+	updateMetadata("native_latency", model.MetricTypeHistogram)
+	_, err := appender.AppendHistogram(0, labels.FromStrings(labels.MetricName, "native_latency", "service", "api"), ts, nil, &histogram.FloatHistogram{
+		Schema:          0,
+		ZeroThreshold:   1e-128,
+		ZeroCount:       n,
+		Count:           4 * n,
+		Sum:             5 * n,
+		PositiveSpans:   []histogram.Span{{Offset: 0, Length: 1}, {Offset: 1, Length: 1}},
+		PositiveBuckets: []float64{n, 2 * n},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, appender.Commit())
+}
+
+func metricsByName(md pmetric.Metrics) map[string]pmetric.Metric {
+	metrics := map[string]pmetric.Metric{}
+	for _, rm := range md.ResourceMetrics().All() {
+		for _, sm := range rm.ScopeMetrics().All() {
+			for _, m := range sm.Metrics().All() {
+				metrics[m.Name()] = m
+			}
+		}
+	}
+	return metrics
+}
+
+type otlpRequest struct {
+	headers grpc_metadata.MD
+	metrics pmetric.Metrics
+}
+
+type mockOTLPReceiver struct {
+	pmetricotlp.UnimplementedGRPCServer
+
+	mtx      sync.Mutex
+	received []otlpRequest
+}
+
+func (m *mockOTLPReceiver) Export(ctx context.Context, req pmetricotlp.ExportRequest) (pmetricotlp.ExportResponse, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	headers, _ := grpc_metadata.FromIncomingContext(ctx)
+	m.received = append(m.received, otlpRequest{headers: headers, metrics: req.Metrics()})
+	return pmetricotlp.NewExportResponse(), nil
+}
+
+func (m *mockOTLPReceiver) requests() []otlpRequest {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.received
+}
+
+// waitForRequests waits until n requests are received, exports are sent in the background.
+func (m *mockOTLPReceiver) waitForRequests(t *testing.T, n int) []otlpRequest {
+	require.Eventually(t, func() bool { return len(m.requests()) >= n }, 5*time.Second, 10*time.Millisecond)
+	requests := m.requests()
+	require.Len(t, requests, n)
+	return requests
+}
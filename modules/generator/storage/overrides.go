@@ -7,6 +7,8 @@ import (
 
 type Overrides interface {
 	MetricsGeneratorRemoteWriteHeaders(userID string) map[string]string
+	MetricsGeneratorOTLPExportEndpoint(userID string) string
+	MetricsGeneratorOTLPExportHeaders(userID string) map[string]string
 	MetricsGeneratorGenerateNativeHistograms(userID string) histograms.HistogramMethod
 }
 
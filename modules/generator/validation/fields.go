@@ -3,6 +3,7 @@ package validation
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/grafana/tempo/modules/distributor/usage"
@@ -11,6 +12,7 @@ import (
 	"github.com/grafana/tempo/pkg/sharedconfig"
 	"github.com/grafana/tempo/pkg/spanfilter"
 	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/util/strutil"
@@ -23,6 +25,9 @@ var SupportedProcessors = []string{
 	processor.SpanMetricsLatencyName,
 	processor.SpanMetricsSizeName,
 	processor.HostInfoName,
+	processor.TraceQLMetricsName,
+	processor.SpanEventsName,
+	processor.CriticalPathName,
 }
 
 var SupportedIntrinsicDimensions = []string{processor.DimService, processor.DimSpanName, processor.DimSpanKind, processor.DimStatusCode, processor.DimStatusMessage}
@@ -39,6 +44,11 @@ var SupportedSpanNameSanitizationModes = []string{
 
 var SupportedSpanNameSanitizationModesSet map[string]struct{}
 
+var SupportedMaxCardinalityPerLabelModes = []string{
+	registry.MaxCardinalityPerLabelModeOverflow,
+	registry.MaxCardinalityPerLabelModeTopK,
+}
+
 var SupportedHistogramModesSet map[string]struct{}
 
 func init() {
@@ -88,6 +98,17 @@ func ValidateSpanNameSanitization(mode string) error {
 	return nil
 }
 
+func ValidateSpanNameSanitizationRules(rules []sharedconfig.SpanNameSanitizationRule) error {
+	return registry.ValidateSpanNameSanitizationRules(rules)
+}
+
+func ValidateMaxCardinalityPerLabelMode(mode string) error {
+	if !slices.Contains(SupportedMaxCardinalityPerLabelModes, mode) {
+		return fmt.Errorf("max_cardinality_per_label_mode \"%s\" is not valid, valid values: %v", mode, SupportedMaxCardinalityPerLabelModes)
+	}
+	return nil
+}
+
 func ValidateHistogramMode(mode string) error {
 	if _, ok := SupportedHistogramModesSet[mode]; !ok {
 		return fmt.Errorf("metrics_generator.generate_native_histograms \"%s\" is not a valid value, valid values: classic, native, both", mode)
@@ -109,6 +130,24 @@ func ValidateHostInfoMetricName(metricName string) error {
 	return nil
 }
 
+func ValidateTraceQLMetrics(metrics []sharedconfig.TraceQLMetric) error {
+	seen := make(map[string]struct{}, len(metrics))
+	for _, m := range metrics {
+		if !model.UTF8Validation.IsValidMetricName(m.Name) {
+			return fmt.Errorf("traceql_metrics metric name \"%s\" is invalid", m.Name)
+		}
+		if _, ok := seen[m.Name]; ok {
+			return fmt.Errorf("traceql_metrics metric name \"%s\" is not unique", m.Name)
+		}
+		seen[m.Name] = struct{}{}
+
+		if _, err := traceql.CompileGeneratorQuery(m.Query); err != nil {
+			return fmt.Errorf("traceql_metrics metric \"%s\" has an invalid query: %w", m.Name, err)
+		}
+	}
+	return nil
+}
+
 func ValidateDimensions(dimensions []string, intrinsicDimensions []string, dimensionMappings []sharedconfig.DimensionMappings, sanitizeFn SanitizeFn) error {
 	seen := make(map[string]string) // sanitized label -> original source
 
@@ -221,3 +260,24 @@ func ValidateFilterPolicies(policies []filterconfig.FilterPolicy) error {
 func ValidateServiceGraphsDimensions(dimensions []string) error {
 	return nil
 }
+
+func ValidateCriticalPathWait(wait time.Duration) error {
+	if wait <= 0 {
+		return fmt.Errorf("metrics_generator.processor.critical_path.wait must be greater than 0")
+	}
+	return nil
+}
+
+func ValidateCriticalPathMaxItems(maxItems int) error {
+	if maxItems <= 0 {
+		return fmt.Errorf("metrics_generator.processor.critical_path.max_items must be greater than 0")
+	}
+	return nil
+}
+
+func ValidateCriticalPathMaxSpansPerTrace(maxSpansPerTrace int) error {
+	if maxSpansPerTrace <= 0 {
+		return fmt.Errorf("metrics_generator.processor.critical_path.max_spans_per_trace must be greater than 0")
+	}
+	return nil
+}
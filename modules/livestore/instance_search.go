@@ -200,6 +200,7 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 	var (
 		resultsMtx = sync.Mutex{}
 		combiner   = traceql.NewMetadataCombiner(maxResults, mostRecent)
+		table      = traceql.NewSearchTableCombiner(nil)
 		metrics    = &tempopb.SearchMetrics{}
 		opts       = common.DefaultSearchOptions()
 	)
@@ -248,6 +249,7 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 			metrics.InspectedTraces += resp.Metrics.InspectedTraces
 			metrics.InspectedBytes += resp.Metrics.InspectedBytes
 		}
+		table.AddRows(resp.Table)
 
 		for _, tr := range resp.Traces {
 			combiner.AddMetadata(tr)
@@ -268,6 +270,7 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 	return &tempopb.SearchResponse{
 		Traces:  combiner.Metadata(),
 		Metrics: metrics,
+		Table:   table.PartialRows(),
 	}, nil
 }
 
@@ -44,6 +44,8 @@ const (
 	ErrorPrefixTraceTooLarge = "TRACE_TOO_LARGE"
 	// ErrorPrefixRateLimited is used to flag batches that have exceeded the spans/second of the tenant
 	ErrorPrefixRateLimited = "RATE_LIMITED"
+	// ErrorPrefixSchemaValidation is used to flag batches with spans rejected by schema validation
+	ErrorPrefixSchemaValidation = "SCHEMA_VALIDATION"
 
 	// metrics
 	MetricMaxLocalTracesPerUser           = "max_local_traces_per_user"
@@ -80,6 +82,145 @@ type IngestionOverrides struct {
 	MaxAttributeBytes int            `yaml:"max_attribute_bytes,omitempty" json:"max_attribute_bytes,omitempty"`
 	ArtificialDelay   *time.Duration `yaml:"artificial_delay,omitempty" json:"artificial_delay,omitempty"`
 	RetryInfoEnabled  bool           `yaml:"retry_info_enabled,omitempty" json:"retry_info_enabled,omitempty"`
+
+	TailSampling        TailSamplingOverrides `yaml:"tail_sampling,omitempty" json:"tail_sampling,omitempty"`
+	AttributeTransforms []AttributeTransform  `yaml:"attribute_transforms,omitempty" json:"attribute_transforms,omitempty"`
+
+	SchemaValidation SchemaValidationOverrides `yaml:"schema_validation,omitempty" json:"schema_validation,omitempty"`
+}
+
+const (
+	// AttributeTransformDelete removes the attribute.
+	AttributeTransformDelete = "delete"
+	// AttributeTransformHash replaces the value of the attribute by its salted SHA-256 hash.
+	AttributeTransformHash = "hash"
+	// AttributeTransformMask replaces the matches of a regular expression in the value of the
+	// attribute.
+	AttributeTransformMask = "mask"
+	// AttributeTransformRename changes the key of the attribute.
+	AttributeTransformRename = "rename"
+	// AttributeTransformInsert adds the attribute if it doesn't exist.
+	AttributeTransformInsert = "insert"
+)
+
+const (
+	AttributeTransformScopeResource = "resource"
+	AttributeTransformScopeSpan     = "span"
+	AttributeTransformScopeEvent    = "event"
+	AttributeTransformScopeLink     = "link"
+)
+
+// AttributeTransform changes the attributes of the received spans before they are written.
+type AttributeTransform struct {
+	Action string `yaml:"action" json:"action"`
+	// Scopes are the attributes the transform applies to. All scopes if empty.
+	Scopes []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+
+	// Key is the key of the attribute. Delete, hash and mask can match keys with KeyPattern instead.
+	Key        string `yaml:"key,omitempty" json:"key,omitempty"`
+	KeyPattern string `yaml:"key_pattern,omitempty" json:"key_pattern,omitempty"`
+
+	// Salt is prepended to the values hashed by hash.
+	Salt config.Secret `yaml:"salt,omitempty" json:"salt,omitempty"`
+	// Pattern is the regular expression replaced by mask. The whole value is replaced if empty.
+	Pattern     string `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	Replacement string `yaml:"replacement,omitempty" json:"replacement,omitempty"`
+	// NewKey is the key set by rename.
+	NewKey string `yaml:"new_key,omitempty" json:"new_key,omitempty"`
+	// Value is the string value added by insert.
+	Value string `yaml:"value,omitempty" json:"value,omitempty"`
+
+	// FilterPolicies select the spans the transform applies to. Resource attributes are transformed
+	// if at least one span of the resource is selected.
+	FilterPolicies []filterconfig.FilterPolicy `yaml:"filter_policies,omitempty" json:"filter_policies,omitempty"`
+}
+
+const (
+	// TailSamplingPolicyStatusCode keeps traces with at least one span with status error.
+	TailSamplingPolicyStatusCode = "status_code"
+	// TailSamplingPolicyLatency keeps traces that last at least the threshold.
+	TailSamplingPolicyLatency = "latency"
+	// TailSamplingPolicyTraceQL keeps traces with at least one span matching the TraceQL query.
+	TailSamplingPolicyTraceQL = "traceql"
+	// TailSamplingPolicyProbabilistic keeps a percentage of the traces.
+	TailSamplingPolicyProbabilistic = "probabilistic"
+	// TailSamplingPolicyRateLimiting keeps up to a number of traces per second for every root service.
+	TailSamplingPolicyRateLimiting = "rate_limiting"
+)
+
+// TailSamplingPolicy decides whether a buffered trace is kept.
+type TailSamplingPolicy struct {
+	Name string `yaml:"name" json:"name"`
+	Type string `yaml:"type" json:"type"`
+
+	// Threshold is the minimum duration of the trace for latency policies.
+	Threshold time.Duration `yaml:"threshold,omitempty" json:"threshold,omitempty"`
+	// Query is the TraceQL spanset filter of traceql policies.
+	Query string `yaml:"query,omitempty" json:"query,omitempty"`
+	// SamplingPercentage is the percentage of traces kept by probabilistic policies.
+	SamplingPercentage float64 `yaml:"sampling_percentage,omitempty" json:"sampling_percentage,omitempty"`
+	// TracesPerSecond is the number of traces per root service kept by rate_limiting policies.
+	TracesPerSecond float64 `yaml:"traces_per_second,omitempty" json:"traces_per_second,omitempty"`
+}
+
+// TailSamplingOverrides configures the tail sampling of the distributor. Tail sampling is enabled if
+// at least one policy is set.
+type TailSamplingOverrides struct {
+	// DecisionWait is the time spans of a trace are buffered before the policies are evaluated.
+	DecisionWait time.Duration `yaml:"decision_wait,omitempty" json:"decision_wait,omitempty"`
+	// MaxTraces is the maximum number of traces of the tenant buffered by each distributor. When
+	// it's reached, the policies are evaluated for new traces right away. Defaults to 10000 if not
+	// set.
+	MaxTraces int `yaml:"max_traces,omitempty" json:"max_traces,omitempty"`
+	// MaxSpansPerTrace is the maximum number of spans buffered for a trace. Traces that exceed it
+	// are kept without evaluating the policies. Defaults to 10000 if not set.
+	MaxSpansPerTrace int                  `yaml:"max_spans_per_trace,omitempty" json:"max_spans_per_trace,omitempty"`
+	Policies         []TailSamplingPolicy `yaml:"policies,omitempty" json:"policies,omitempty"`
+}
+
+const (
+	// SchemaValidationActionCount only records the violations in the metrics and the quality scores.
+	SchemaValidationActionCount = "count"
+	// SchemaValidationActionTag adds the names of the violated rules to the spans.
+	SchemaValidationActionTag = "tag"
+	// SchemaValidationActionReject discards the spans violating the rule.
+	SchemaValidationActionReject = "reject"
+)
+
+const (
+	// SchemaValidationRuleRequiredAttribute is violated by spans without the attribute.
+	SchemaValidationRuleRequiredAttribute = "required_attribute"
+	// SchemaValidationRuleAttributeType is violated by spans with the attribute of another type.
+	SchemaValidationRuleAttributeType = "attribute_type"
+	// SchemaValidationRuleSpanNameCardinality is violated by spans with a new span name once a service
+	// has the max number of span names. The span names are reset every window of the quality score.
+	SchemaValidationRuleSpanNameCardinality = "span_name_cardinality"
+)
+
+// SchemaValidationRule checks that the received spans follow a semantic convention.
+type SchemaValidationRule struct {
+	Name string `yaml:"name" json:"name"`
+	Type string `yaml:"type" json:"type"`
+	// Action overrides the action of the schema validation for this rule.
+	Action string `yaml:"action,omitempty" json:"action,omitempty"`
+
+	// Scope is the scope of the attribute, resource or span. Defaults to resource.
+	Scope string `yaml:"scope,omitempty" json:"scope,omitempty"`
+	// Key is the key of the attribute of required_attribute and attribute_type rules.
+	Key string `yaml:"key,omitempty" json:"key,omitempty"`
+	// ValueType is the type of the attribute of attribute_type rules: string, int, double, bool,
+	// array, kvlist or bytes.
+	ValueType string `yaml:"value_type,omitempty" json:"value_type,omitempty"`
+	// MaxSpanNames is the number of span names per service of span_name_cardinality rules.
+	MaxSpanNames int `yaml:"max_span_names,omitempty" json:"max_span_names,omitempty"`
+}
+
+// SchemaValidationOverrides configures the validation of the received spans in the distributor.
+// Schema validation is enabled if at least one rule is set.
+type SchemaValidationOverrides struct {
+	// Action is the action of the rules without an action. Defaults to count.
+	Action string                 `yaml:"action,omitempty" json:"action,omitempty"`
+	Rules  []SchemaValidationRule `yaml:"rules,omitempty" json:"rules,omitempty"`
 }
 
 type ForwarderOverrides struct {
@@ -110,6 +251,7 @@ type SpanMetricsOverrides struct {
 	EnableInstanceLabel            *bool                            `yaml:"enable_instance_label,omitempty" json:"enable_instance_label,omitempty"`
 	SpanMultiplierKey              string                           `yaml:"span_multiplier_key,omitempty" json:"span_multiplier_key,omitempty"`
 	EnableTraceStateSpanMultiplier *bool                            `yaml:"enable_tracestate_span_multiplier,omitempty" json:"enable_tracestate_span_multiplier,omitempty"`
+	RootDimensions                 []string                         `yaml:"root_dimensions,omitempty" json:"root_dimensions,omitempty"`
 }
 
 type HostInfoOverrides struct {
@@ -117,10 +259,30 @@ type HostInfoOverrides struct {
 	MetricName      string   `yaml:"metric_name,omitempty" json:"metric_name,omitempty"`
 }
 
+type TraceQLMetricsOverrides struct {
+	Metrics          []sharedconfig.TraceQLMetric `yaml:"metrics,omitempty" json:"metrics,omitempty"`
+	HistogramBuckets []float64                    `yaml:"histogram_buckets,omitempty" json:"histogram_buckets,omitempty"`
+}
+
+type SpanEventsOverrides struct {
+	Dimensions        []string                         `yaml:"dimensions,omitempty" json:"dimensions,omitempty"`
+	FilterPolicies    []filterconfig.FilterPolicy      `yaml:"filter_policies,omitempty" json:"filter_policies,omitempty"`
+	DimensionMappings []sharedconfig.DimensionMappings `yaml:"dimension_mappings,omitempty" json:"dimension_mappings,omitempty"`
+}
+
+type CriticalPathOverrides struct {
+	Wait             time.Duration `yaml:"wait,omitempty" json:"wait,omitempty"`
+	MaxItems         int           `yaml:"max_items,omitempty" json:"max_items,omitempty"`
+	MaxSpansPerTrace int           `yaml:"max_spans_per_trace,omitempty" json:"max_spans_per_trace,omitempty"`
+}
+
 type ProcessorOverrides struct {
-	ServiceGraphs ServiceGraphsOverrides `yaml:"service_graphs,omitempty" json:"service_graphs,omitempty"`
-	SpanMetrics   SpanMetricsOverrides   `yaml:"span_metrics,omitempty" json:"span_metrics,omitempty"`
-	HostInfo      HostInfoOverrides      `yaml:"host_info,omitempty" json:"host_info,omitempty"`
+	ServiceGraphs  ServiceGraphsOverrides  `yaml:"service_graphs,omitempty" json:"service_graphs,omitempty"`
+	SpanMetrics    SpanMetricsOverrides    `yaml:"span_metrics,omitempty" json:"span_metrics,omitempty"`
+	HostInfo       HostInfoOverrides       `yaml:"host_info,omitempty" json:"host_info,omitempty"`
+	TraceQLMetrics TraceQLMetricsOverrides `yaml:"traceql_metrics,omitempty" json:"traceql_metrics,omitempty"`
+	SpanEvents     SpanEventsOverrides     `yaml:"span_events,omitempty" json:"span_events,omitempty"`
+	CriticalPath   CriticalPathOverrides   `yaml:"critical_path,omitempty" json:"critical_path,omitempty"`
 }
 
 type RemoteWriteHeaders map[string]config.Secret
@@ -137,6 +299,11 @@ func (h *RemoteWriteHeaders) toStringStringMap() map[string]string {
 	return headers
 }
 
+type OTLPExportOverrides struct {
+	Endpoint string             `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+	Headers  RemoteWriteHeaders `yaml:"headers,omitempty" json:"headers,omitempty"`
+}
+
 type MetricsGeneratorOverrides struct {
 	RingSize                 int                        `yaml:"ring_size,omitempty" json:"ring_size,omitempty"`
 	Processors               listtomap.ListToMap        `yaml:"processors,omitempty" json:"processors,omitempty"`
@@ -147,17 +314,20 @@ type MetricsGeneratorOverrides struct {
 	GenerateNativeHistograms histograms.HistogramMethod `yaml:"generate_native_histograms,omitempty" json:"generate_native_histograms,omitempty"`
 	TraceIDLabelName         string                     `yaml:"trace_id_label_name,omitempty" json:"trace_id_label_name,omitempty"`
 
-	RemoteWriteHeaders RemoteWriteHeaders `yaml:"remote_write_headers,omitempty" json:"remote_write_headers,omitempty"`
+	RemoteWriteHeaders RemoteWriteHeaders  `yaml:"remote_write_headers,omitempty" json:"remote_write_headers,omitempty"`
+	OTLPExport         OTLPExportOverrides `yaml:"otlp_export,omitempty" json:"otlp_export,omitempty"`
 
 	Forwarder      ForwarderOverrides `yaml:"forwarder,omitempty" json:"forwarder,omitempty"`
 	Processor      ProcessorOverrides `yaml:"processor,omitempty" json:"processor,omitempty"`
 	IngestionSlack time.Duration      `yaml:"ingestion_time_range_slack,omitempty" json:"ingestion_time_range_slack,omitempty"`
 
-	NativeHistogramBucketFactor     float64       `yaml:"native_histogram_bucket_factor,omitempty" json:"native_histogram_bucket_factor,omitempty"`
-	NativeHistogramMaxBucketNumber  uint32        `yaml:"native_histogram_max_bucket_number,omitempty" json:"native_histogram_max_bucket_number,omitempty"`
-	NativeHistogramMinResetDuration time.Duration `yaml:"native_histogram_min_reset_duration,omitempty" json:"native_histogram_min_reset_duration,omitempty"`
-	SpanNameSanitization            string        `yaml:"span_name_sanitization,omitempty" json:"span_name_sanitization,omitempty"`
-	MaxCardinalityPerLabel          uint64        `yaml:"max_cardinality_per_label,omitempty" json:"max_cardinality_per_label,omitempty"`
+	NativeHistogramBucketFactor     float64                                 `yaml:"native_histogram_bucket_factor,omitempty" json:"native_histogram_bucket_factor,omitempty"`
+	NativeHistogramMaxBucketNumber  uint32                                  `yaml:"native_histogram_max_bucket_number,omitempty" json:"native_histogram_max_bucket_number,omitempty"`
+	NativeHistogramMinResetDuration time.Duration                           `yaml:"native_histogram_min_reset_duration,omitempty" json:"native_histogram_min_reset_duration,omitempty"`
+	SpanNameSanitization            string                                  `yaml:"span_name_sanitization,omitempty" json:"span_name_sanitization,omitempty"`
+	SpanNameSanitizationRules       []sharedconfig.SpanNameSanitizationRule `yaml:"span_name_sanitization_rules,omitempty" json:"span_name_sanitization_rules,omitempty"`
+	MaxCardinalityPerLabel          uint64                                  `yaml:"max_cardinality_per_label,omitempty" json:"max_cardinality_per_label,omitempty"`
+	MaxCardinalityPerLabelMode      string                                  `yaml:"max_cardinality_per_label_mode,omitempty" json:"max_cardinality_per_label_mode,omitempty"`
 }
 
 type ReadOverrides struct {
@@ -172,6 +342,10 @@ type ReadOverrides struct {
 
 	UnsafeQueryHints bool `yaml:"unsafe_query_hints,omitempty" json:"unsafe_query_hints,omitempty"`
 
+	// MaxLinkFanOut is the maximum number of linked traces looked up by the query frontend to evaluate
+	// the right side of a TraceQL ->link query. 0 disables ->link.
+	MaxLinkFanOut int `yaml:"max_link_fan_out,omitempty" json:"max_link_fan_out,omitempty"`
+
 	// LeftPadTraceIDs left-pads trace IDs in search responses to 32 hex characters with zeros.
 	// This produces W3C/OpenTelemetry compliant trace IDs (32-hex-character lowercase strings).
 	LeftPadTraceIDs bool `yaml:"left_pad_trace_ids,omitempty" json:"left_pad_trace_ids,omitempty"`
@@ -390,6 +564,9 @@ func (c *Config) RegisterFlagsAndApplyDefaults(f *flag.FlagSet) {
 	f.IntVar(&c.Defaults.Read.MaxBlocksPerTagValuesQuery, "querier.max-blocks-per-tag-values-query", 0, "Maximum number of blocks to query for a tag-values query. 0 to disable.")
 	f.IntVar(&c.Defaults.Read.MaxConditionGroupsPerTagQuery, "querier.max-condition-groups-per-tag-query", traceql.DefaultMaxConditionGroupsPerTagQuery, "Maximum number of OR-expanded condition groups allowed in a tag search query. Queries that expand beyond this limit will be rejected.")
 
+	// Frontend limits
+	f.IntVar(&c.Defaults.Read.MaxLinkFanOut, "frontend.max-link-fan-out", 20, "Maximum number of linked traces looked up by the query frontend to evaluate a TraceQL ->link query. 0 to disable ->link.")
+
 	// Generator - NativeHistograms config
 	f.Float64Var(&c.Defaults.MetricsGenerator.NativeHistogramBucketFactor, "metrics-generator.native-histogram-bucket-factor", 1.1, "The growth factor between buckets for native histograms.")
 	_ = (*Uint32Value)(&c.Defaults.MetricsGenerator.NativeHistogramMaxBucketNumber).Set("100")
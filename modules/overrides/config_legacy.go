@@ -19,15 +19,18 @@ import (
 
 func (c *Overrides) toLegacy() LegacyOverrides {
 	return LegacyOverrides{
-		IngestionRateStrategy:      c.Ingestion.RateStrategy,
-		IngestionRateLimitBytes:    c.Ingestion.RateLimitBytes,
-		IngestionBurstSizeBytes:    c.Ingestion.BurstSizeBytes,
-		IngestionTenantShardSize:   c.Ingestion.TenantShardSize,
-		MaxLocalTracesPerUser:      c.Ingestion.MaxLocalTracesPerUser,
-		MaxGlobalTracesPerUser:     c.Ingestion.MaxGlobalTracesPerUser,
-		IngestionMaxAttributeBytes: c.Ingestion.MaxAttributeBytes,
-		IngestionArtificialDelay:   c.Ingestion.ArtificialDelay,
-		IngestionRetryInfoEnabled:  c.Ingestion.RetryInfoEnabled,
+		IngestionRateStrategy:        c.Ingestion.RateStrategy,
+		IngestionRateLimitBytes:      c.Ingestion.RateLimitBytes,
+		IngestionBurstSizeBytes:      c.Ingestion.BurstSizeBytes,
+		IngestionTenantShardSize:     c.Ingestion.TenantShardSize,
+		MaxLocalTracesPerUser:        c.Ingestion.MaxLocalTracesPerUser,
+		MaxGlobalTracesPerUser:       c.Ingestion.MaxGlobalTracesPerUser,
+		IngestionMaxAttributeBytes:   c.Ingestion.MaxAttributeBytes,
+		IngestionArtificialDelay:     c.Ingestion.ArtificialDelay,
+		IngestionRetryInfoEnabled:    c.Ingestion.RetryInfoEnabled,
+		IngestionTailSampling:        c.Ingestion.TailSampling,
+		IngestionAttributeTransforms: c.Ingestion.AttributeTransforms,
+		IngestionSchemaValidation:    c.Ingestion.SchemaValidation,
 
 		Forwarders: c.Forwarders,
 
@@ -40,6 +43,8 @@ func (c *Overrides) toLegacy() LegacyOverrides {
 		MetricsGeneratorGenerateNativeHistograms:                                    c.MetricsGenerator.GenerateNativeHistograms,
 		MetricsGeneratorTraceIDLabelName:                                            c.MetricsGenerator.TraceIDLabelName,
 		MetricsGeneratorRemoteWriteHeaders:                                          c.MetricsGenerator.RemoteWriteHeaders,
+		MetricsGeneratorOTLPExportEndpoint:                                          c.MetricsGenerator.OTLPExport.Endpoint,
+		MetricsGeneratorOTLPExportHeaders:                                           c.MetricsGenerator.OTLPExport.Headers,
 		MetricsGeneratorForwarderQueueSize:                                          c.MetricsGenerator.Forwarder.QueueSize,
 		MetricsGeneratorForwarderWorkers:                                            c.MetricsGenerator.Forwarder.Workers,
 		MetricsGeneratorProcessorServiceGraphsHistogramBuckets:                      c.MetricsGenerator.Processor.ServiceGraphs.HistogramBuckets,
@@ -61,14 +66,25 @@ func (c *Overrides) toLegacy() LegacyOverrides {
 		MetricsGeneratorProcessorSpanMetricsEnableInstanceLabel:                     c.MetricsGenerator.Processor.SpanMetrics.EnableInstanceLabel,
 		MetricsGeneratorProcessorSpanMetricsSpanMultiplierKey:                       c.MetricsGenerator.Processor.SpanMetrics.SpanMultiplierKey,
 		MetricsGeneratorProcessorSpanMetricsEnableTraceStateSpanMultiplier:          c.MetricsGenerator.Processor.SpanMetrics.EnableTraceStateSpanMultiplier,
+		MetricsGeneratorProcessorSpanMetricsRootDimensions:                          c.MetricsGenerator.Processor.SpanMetrics.RootDimensions,
 		MetricsGeneratorProcessorHostInfoHostIdentifiers:                            c.MetricsGenerator.Processor.HostInfo.HostIdentifiers,
 		MetricsGeneratorProcessorHostInfoMetricName:                                 c.MetricsGenerator.Processor.HostInfo.MetricName,
+		MetricsGeneratorProcessorTraceQLMetricsMetrics:                              c.MetricsGenerator.Processor.TraceQLMetrics.Metrics,
+		MetricsGeneratorProcessorTraceQLMetricsHistogramBuckets:                     c.MetricsGenerator.Processor.TraceQLMetrics.HistogramBuckets,
+		MetricsGeneratorProcessorSpanEventsDimensions:                               c.MetricsGenerator.Processor.SpanEvents.Dimensions,
+		MetricsGeneratorProcessorSpanEventsFilterPolicies:                           c.MetricsGenerator.Processor.SpanEvents.FilterPolicies,
+		MetricsGeneratorProcessorSpanEventsDimensionMappings:                        c.MetricsGenerator.Processor.SpanEvents.DimensionMappings,
+		MetricsGeneratorProcessorCriticalPathWait:                                   c.MetricsGenerator.Processor.CriticalPath.Wait,
+		MetricsGeneratorProcessorCriticalPathMaxItems:                               c.MetricsGenerator.Processor.CriticalPath.MaxItems,
+		MetricsGeneratorProcessorCriticalPathMaxSpansPerTrace:                       c.MetricsGenerator.Processor.CriticalPath.MaxSpansPerTrace,
 		MetricsGeneratorIngestionSlack:                                              c.MetricsGenerator.IngestionSlack,
 		MetricsGeneratorNativeHistogramBucketFactor:                                 c.MetricsGenerator.NativeHistogramBucketFactor,
 		MetricsGeneratorNativeHistogramMaxBucketNumber:                              c.MetricsGenerator.NativeHistogramMaxBucketNumber,
 		MetricsGeneratorNativeHistogramMinResetDuration:                             c.MetricsGenerator.NativeHistogramMinResetDuration,
 		MetricsGeneratorSpanNameSanitization:                                        c.MetricsGenerator.SpanNameSanitization,
+		MetricsGeneratorSpanNameSanitizationRules:                                   c.MetricsGenerator.SpanNameSanitizationRules,
 		MetricsGeneratorMaxCardinalityPerLabel:                                      c.MetricsGenerator.MaxCardinalityPerLabel,
+		MetricsGeneratorMaxCardinalityPerLabelMode:                                  c.MetricsGenerator.MaxCardinalityPerLabelMode,
 
 		BlockRetention:     c.Compaction.BlockRetention,
 		CompactionWindow:   c.Compaction.CompactionWindow,
@@ -80,6 +96,7 @@ func (c *Overrides) toLegacy() LegacyOverrides {
 		MaxSearchDuration:             c.Read.MaxSearchDuration,
 		MaxMetricsDuration:            c.Read.MaxMetricsDuration,
 		UnsafeQueryHints:              c.Read.UnsafeQueryHints,
+		MaxLinkFanOut:                 c.Read.MaxLinkFanOut,
 		LeftPadTraceIDs:               c.Read.LeftPadTraceIDs,
 		MetricsSpanOnlyFetch:          c.Read.MetricsSpanOnlyFetch,
 
@@ -98,13 +115,16 @@ func (c *Overrides) toLegacy() LegacyOverrides {
 // limits via flags, or per-user limits via yaml config.
 type LegacyOverrides struct {
 	// Distributor enforced limits.
-	IngestionRateStrategy      string         `yaml:"ingestion_rate_strategy" json:"ingestion_rate_strategy"`
-	IngestionRateLimitBytes    int            `yaml:"ingestion_rate_limit_bytes" json:"ingestion_rate_limit_bytes"`
-	IngestionBurstSizeBytes    int            `yaml:"ingestion_burst_size_bytes" json:"ingestion_burst_size_bytes"`
-	IngestionTenantShardSize   int            `yaml:"ingestion_tenant_shard_size" json:"ingestion_tenant_shard_size"`
-	IngestionMaxAttributeBytes int            `yaml:"ingestion_max_attribute_bytes" json:"ingestion_max_attribute_bytes"`
-	IngestionArtificialDelay   *time.Duration `yaml:"ingestion_artificial_delay" json:"ingestion_artificial_delay"`
-	IngestionRetryInfoEnabled  bool           `yaml:"ingestion_retry_info_enabled" json:"ingestion_retry_info_enabled"`
+	IngestionRateStrategy        string                    `yaml:"ingestion_rate_strategy" json:"ingestion_rate_strategy"`
+	IngestionRateLimitBytes      int                       `yaml:"ingestion_rate_limit_bytes" json:"ingestion_rate_limit_bytes"`
+	IngestionBurstSizeBytes      int                       `yaml:"ingestion_burst_size_bytes" json:"ingestion_burst_size_bytes"`
+	IngestionTenantShardSize     int                       `yaml:"ingestion_tenant_shard_size" json:"ingestion_tenant_shard_size"`
+	IngestionMaxAttributeBytes   int                       `yaml:"ingestion_max_attribute_bytes" json:"ingestion_max_attribute_bytes"`
+	IngestionArtificialDelay     *time.Duration            `yaml:"ingestion_artificial_delay" json:"ingestion_artificial_delay"`
+	IngestionRetryInfoEnabled    bool                      `yaml:"ingestion_retry_info_enabled" json:"ingestion_retry_info_enabled"`
+	IngestionTailSampling        TailSamplingOverrides     `yaml:"ingestion_tail_sampling,omitempty" json:"ingestion_tail_sampling,omitempty"`
+	IngestionAttributeTransforms []AttributeTransform      `yaml:"ingestion_attribute_transforms,omitempty" json:"ingestion_attribute_transforms,omitempty"`
+	IngestionSchemaValidation    SchemaValidationOverrides `yaml:"ingestion_schema_validation,omitempty" json:"ingestion_schema_validation,omitempty"`
 
 	// Ingester enforced limits.
 	MaxLocalTracesPerUser  int `yaml:"max_traces_per_user" json:"max_traces_per_user"`
@@ -114,44 +134,57 @@ type LegacyOverrides struct {
 	Forwarders []string `yaml:"forwarders" json:"forwarders"`
 
 	// Metrics-generator config
-	MetricsGeneratorRingSize                                                    int                              `yaml:"metrics_generator_ring_size" json:"metrics_generator_ring_size"`
-	MetricsGeneratorProcessors                                                  listtomap.ListToMap              `yaml:"metrics_generator_processors" json:"metrics_generator_processors"`
-	MetricsGeneratorMaxActiveSeries                                             uint32                           `yaml:"metrics_generator_max_active_series" json:"metrics_generator_max_active_series"`
-	MetricsGeneratorMaxActiveEntities                                           uint32                           `yaml:"metrics_generator_max_active_entities" json:"metrics_generator_max_active_entities"`
-	MetricsGeneratorCollectionInterval                                          time.Duration                    `yaml:"metrics_generator_collection_interval" json:"metrics_generator_collection_interval"`
-	MetricsGeneratorDisableCollection                                           bool                             `yaml:"metrics_generator_disable_collection" json:"metrics_generator_disable_collection"`
-	MetricsGeneratorGenerateNativeHistograms                                    histograms.HistogramMethod       `yaml:"metrics_generator_generate_native_histograms" json:"metrics_generator_generate_native_histograms"`
-	MetricsGeneratorNativeHistogramBucketFactor                                 float64                          `yaml:"metrics_generator_native_histogram_bucket_factor,omitempty" json:"metrics_generator_native_histogram_bucket_factor,omitempty"`
-	MetricsGeneratorNativeHistogramMaxBucketNumber                              uint32                           `yaml:"metrics_generator_native_histogram_max_bucket_number,omitempty" json:"metrics_generator_native_histogram_max_bucket_number,omitempty"`
-	MetricsGeneratorNativeHistogramMinResetDuration                             time.Duration                    `yaml:"metrics_generator_native_histogram_min_reset_duration,omitempty" json:"native_histogram_min_reset_duration,omitempty"`
-	MetricsGeneratorSpanNameSanitization                                        string                           `yaml:"metrics_generator_span_name_sanitization" json:"metrics_generator_span_name_sanitization"`
-	MetricsGeneratorMaxCardinalityPerLabel                                      uint64                           `yaml:"metrics_generator_max_cardinality_per_label,omitempty" json:"metrics_generator_max_cardinality_per_label,omitempty"`
-	MetricsGeneratorTraceIDLabelName                                            string                           `yaml:"metrics_generator_trace_id_label_name" json:"metrics_generator_trace_id_label_name"`
-	MetricsGeneratorForwarderQueueSize                                          int                              `yaml:"metrics_generator_forwarder_queue_size" json:"metrics_generator_forwarder_queue_size"`
-	MetricsGeneratorForwarderWorkers                                            int                              `yaml:"metrics_generator_forwarder_workers" json:"metrics_generator_forwarder_workers"`
-	MetricsGeneratorRemoteWriteHeaders                                          RemoteWriteHeaders               `yaml:"metrics_generator_remote_write_headers,omitempty" json:"metrics_generator_remote_write_headers,omitempty"`
-	MetricsGeneratorProcessorServiceGraphsHistogramBuckets                      []float64                        `yaml:"metrics_generator_processor_service_graphs_histogram_buckets" json:"metrics_generator_processor_service_graphs_histogram_buckets"`
-	MetricsGeneratorProcessorServiceGraphsDimensions                            []string                         `yaml:"metrics_generator_processor_service_graphs_dimensions" json:"metrics_generator_processor_service_graphs_dimensions"`
-	MetricsGeneratorProcessorServiceGraphsPeerAttributes                        []string                         `yaml:"metrics_generator_processor_service_graphs_peer_attributes" json:"metrics_generator_processor_service_graphs_peer_attributes"`
-	MetricsGeneratorProcessorServiceGraphsFilterPolicies                        []filterconfig.FilterPolicy      `yaml:"metrics_generator_processor_service_graphs_filter_policies" json:"metrics_generator_processor_service_graphs_filter_policies"`
-	MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix              *bool                            `yaml:"metrics_generator_processor_service_graphs_enable_client_server_prefix" json:"metrics_generator_processor_service_graphs_enable_client_server_prefix"`
-	MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram *bool                            `yaml:"metrics_generator_processor_service_graphs_enable_messaging_system_latency_histogram" json:"metrics_generator_processor_service_graphs_enable_messaging_system_latency_histogram"`
-	MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel                *bool                            `yaml:"metrics_generator_processor_service_graphs_enable_virtual_node_label" json:"metrics_generator_processor_service_graphs_enable_virtual_node_label"`
-	MetricsGeneratorProcessorServiceGraphsSpanMultiplierKey                     string                           `yaml:"metrics_generator_processor_service_graphs_span_multiplier_key" json:"metrics_generator_processor_service_graphs_span_multiplier_key"`
-	MetricsGeneratorProcessorServiceGraphsEnableTraceStateSpanMultiplier        *bool                            `yaml:"metrics_generator_processor_service_graphs_enable_tracestate_span_multiplier" json:"metrics_generator_processor_service_graphs_enable_tracestate_span_multiplier"`
-	MetricsGeneratorProcessorSpanMetricsHistogramBuckets                        []float64                        `yaml:"metrics_generator_processor_span_metrics_histogram_buckets" json:"metrics_generator_processor_span_metrics_histogram_buckets"`
-	MetricsGeneratorProcessorSpanMetricsDimensions                              []string                         `yaml:"metrics_generator_processor_span_metrics_dimensions" json:"metrics_generator_processor_span_metrics_dimensions"`
-	MetricsGeneratorProcessorSpanMetricsIntrinsicDimensions                     map[string]bool                  `yaml:"metrics_generator_processor_span_metrics_intrinsic_dimensions" json:"metrics_generator_processor_span_metrics_intrinsic_dimensions"`
-	MetricsGeneratorProcessorSpanMetricsFilterPolicies                          []filterconfig.FilterPolicy      `yaml:"metrics_generator_processor_span_metrics_filter_policies" json:"metrics_generator_processor_span_metrics_filter_policies"`
-	MetricsGeneratorProcessorSpanMetricsDimensionMappings                       []sharedconfig.DimensionMappings `yaml:"metrics_generator_processor_span_metrics_dimension_mappings" json:"metrics_generator_processor_span_metrics_dimension_mapings"`
-	MetricsGeneratorProcessorSpanMetricsEnableTargetInfo                        *bool                            `yaml:"metrics_generator_processor_span_metrics_enable_target_info" json:"metrics_generator_processor_span_metrics_enable_target_info"`
-	MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions            []string                         `yaml:"metrics_generator_processor_span_metrics_target_info_excluded_dimensions" json:"metrics_generator_processor_span_metrics_target_info_excluded_dimensions"`
-	MetricsGeneratorProcessorSpanMetricsEnableInstanceLabel                     *bool                            `yaml:"metrics_generator_processor_span_metrics_enable_instance_label" json:"metrics_generator_processor_span_metrics_enable_instance_label"`
-	MetricsGeneratorProcessorSpanMetricsSpanMultiplierKey                       string                           `yaml:"metrics_generator_processor_span_metrics_span_multiplier_key" json:"metrics_generator_processor_span_metrics_span_multiplier_key"`
-	MetricsGeneratorProcessorSpanMetricsEnableTraceStateSpanMultiplier          *bool                            `yaml:"metrics_generator_processor_span_metrics_enable_tracestate_span_multiplier" json:"metrics_generator_processor_span_metrics_enable_tracestate_span_multiplier"`
-	MetricsGeneratorProcessorHostInfoHostIdentifiers                            []string                         `yaml:"metrics_generator_processor_host_info_host_identifiers" json:"metrics_generator_processor_host_info_host_identifiers"`
-	MetricsGeneratorProcessorHostInfoMetricName                                 string                           `yaml:"metrics_generator_processor_host_info_metric_name" json:"metrics_generator_processor_host_info_metric_name"`
-	MetricsGeneratorIngestionSlack                                              time.Duration                    `yaml:"metrics_generator_ingestion_time_range_slack" json:"metrics_generator_ingestion_time_range_slack,omitempty"`
+	MetricsGeneratorRingSize                                                    int                                     `yaml:"metrics_generator_ring_size" json:"metrics_generator_ring_size"`
+	MetricsGeneratorProcessors                                                  listtomap.ListToMap                     `yaml:"metrics_generator_processors" json:"metrics_generator_processors"`
+	MetricsGeneratorMaxActiveSeries                                             uint32                                  `yaml:"metrics_generator_max_active_series" json:"metrics_generator_max_active_series"`
+	MetricsGeneratorMaxActiveEntities                                           uint32                                  `yaml:"metrics_generator_max_active_entities" json:"metrics_generator_max_active_entities"`
+	MetricsGeneratorCollectionInterval                                          time.Duration                           `yaml:"metrics_generator_collection_interval" json:"metrics_generator_collection_interval"`
+	MetricsGeneratorDisableCollection                                           bool                                    `yaml:"metrics_generator_disable_collection" json:"metrics_generator_disable_collection"`
+	MetricsGeneratorGenerateNativeHistograms                                    histograms.HistogramMethod              `yaml:"metrics_generator_generate_native_histograms" json:"metrics_generator_generate_native_histograms"`
+	MetricsGeneratorNativeHistogramBucketFactor                                 float64                                 `yaml:"metrics_generator_native_histogram_bucket_factor,omitempty" json:"metrics_generator_native_histogram_bucket_factor,omitempty"`
+	MetricsGeneratorNativeHistogramMaxBucketNumber                              uint32                                  `yaml:"metrics_generator_native_histogram_max_bucket_number,omitempty" json:"metrics_generator_native_histogram_max_bucket_number,omitempty"`
+	MetricsGeneratorNativeHistogramMinResetDuration                             time.Duration                           `yaml:"metrics_generator_native_histogram_min_reset_duration,omitempty" json:"native_histogram_min_reset_duration,omitempty"`
+	MetricsGeneratorSpanNameSanitization                                        string                                  `yaml:"metrics_generator_span_name_sanitization" json:"metrics_generator_span_name_sanitization"`
+	MetricsGeneratorSpanNameSanitizationRules                                   []sharedconfig.SpanNameSanitizationRule `yaml:"metrics_generator_span_name_sanitization_rules,omitempty" json:"metrics_generator_span_name_sanitization_rules,omitempty"`
+	MetricsGeneratorMaxCardinalityPerLabel                                      uint64                                  `yaml:"metrics_generator_max_cardinality_per_label,omitempty" json:"metrics_generator_max_cardinality_per_label,omitempty"`
+	MetricsGeneratorMaxCardinalityPerLabelMode                                  string                                  `yaml:"metrics_generator_max_cardinality_per_label_mode,omitempty" json:"metrics_generator_max_cardinality_per_label_mode,omitempty"`
+	MetricsGeneratorTraceIDLabelName                                            string                                  `yaml:"metrics_generator_trace_id_label_name" json:"metrics_generator_trace_id_label_name"`
+	MetricsGeneratorForwarderQueueSize                                          int                                     `yaml:"metrics_generator_forwarder_queue_size" json:"metrics_generator_forwarder_queue_size"`
+	MetricsGeneratorForwarderWorkers                                            int                                     `yaml:"metrics_generator_forwarder_workers" json:"metrics_generator_forwarder_workers"`
+	MetricsGeneratorRemoteWriteHeaders                                          RemoteWriteHeaders                      `yaml:"metrics_generator_remote_write_headers,omitempty" json:"metrics_generator_remote_write_headers,omitempty"`
+	MetricsGeneratorOTLPExportEndpoint                                          string                                  `yaml:"metrics_generator_otlp_export_endpoint,omitempty" json:"metrics_generator_otlp_export_endpoint,omitempty"`
+	MetricsGeneratorOTLPExportHeaders                                           RemoteWriteHeaders                      `yaml:"metrics_generator_otlp_export_headers,omitempty" json:"metrics_generator_otlp_export_headers,omitempty"`
+	MetricsGeneratorProcessorServiceGraphsHistogramBuckets                      []float64                               `yaml:"metrics_generator_processor_service_graphs_histogram_buckets" json:"metrics_generator_processor_service_graphs_histogram_buckets"`
+	MetricsGeneratorProcessorServiceGraphsDimensions                            []string                                `yaml:"metrics_generator_processor_service_graphs_dimensions" json:"metrics_generator_processor_service_graphs_dimensions"`
+	MetricsGeneratorProcessorServiceGraphsPeerAttributes                        []string                                `yaml:"metrics_generator_processor_service_graphs_peer_attributes" json:"metrics_generator_processor_service_graphs_peer_attributes"`
+	MetricsGeneratorProcessorServiceGraphsFilterPolicies                        []filterconfig.FilterPolicy             `yaml:"metrics_generator_processor_service_graphs_filter_policies" json:"metrics_generator_processor_service_graphs_filter_policies"`
+	MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix              *bool                                   `yaml:"metrics_generator_processor_service_graphs_enable_client_server_prefix" json:"metrics_generator_processor_service_graphs_enable_client_server_prefix"`
+	MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram *bool                                   `yaml:"metrics_generator_processor_service_graphs_enable_messaging_system_latency_histogram" json:"metrics_generator_processor_service_graphs_enable_messaging_system_latency_histogram"`
+	MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel                *bool                                   `yaml:"metrics_generator_processor_service_graphs_enable_virtual_node_label" json:"metrics_generator_processor_service_graphs_enable_virtual_node_label"`
+	MetricsGeneratorProcessorServiceGraphsSpanMultiplierKey                     string                                  `yaml:"metrics_generator_processor_service_graphs_span_multiplier_key" json:"metrics_generator_processor_service_graphs_span_multiplier_key"`
+	MetricsGeneratorProcessorServiceGraphsEnableTraceStateSpanMultiplier        *bool                                   `yaml:"metrics_generator_processor_service_graphs_enable_tracestate_span_multiplier" json:"metrics_generator_processor_service_graphs_enable_tracestate_span_multiplier"`
+	MetricsGeneratorProcessorSpanMetricsHistogramBuckets                        []float64                               `yaml:"metrics_generator_processor_span_metrics_histogram_buckets" json:"metrics_generator_processor_span_metrics_histogram_buckets"`
+	MetricsGeneratorProcessorSpanMetricsDimensions                              []string                                `yaml:"metrics_generator_processor_span_metrics_dimensions" json:"metrics_generator_processor_span_metrics_dimensions"`
+	MetricsGeneratorProcessorSpanMetricsIntrinsicDimensions                     map[string]bool                         `yaml:"metrics_generator_processor_span_metrics_intrinsic_dimensions" json:"metrics_generator_processor_span_metrics_intrinsic_dimensions"`
+	MetricsGeneratorProcessorSpanMetricsFilterPolicies                          []filterconfig.FilterPolicy             `yaml:"metrics_generator_processor_span_metrics_filter_policies" json:"metrics_generator_processor_span_metrics_filter_policies"`
+	MetricsGeneratorProcessorSpanMetricsDimensionMappings                       []sharedconfig.DimensionMappings        `yaml:"metrics_generator_processor_span_metrics_dimension_mappings" json:"metrics_generator_processor_span_metrics_dimension_mapings"`
+	MetricsGeneratorProcessorSpanMetricsEnableTargetInfo                        *bool                                   `yaml:"metrics_generator_processor_span_metrics_enable_target_info" json:"metrics_generator_processor_span_metrics_enable_target_info"`
+	MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions            []string                                `yaml:"metrics_generator_processor_span_metrics_target_info_excluded_dimensions" json:"metrics_generator_processor_span_metrics_target_info_excluded_dimensions"`
+	MetricsGeneratorProcessorSpanMetricsEnableInstanceLabel                     *bool                                   `yaml:"metrics_generator_processor_span_metrics_enable_instance_label" json:"metrics_generator_processor_span_metrics_enable_instance_label"`
+	MetricsGeneratorProcessorSpanMetricsSpanMultiplierKey                       string                                  `yaml:"metrics_generator_processor_span_metrics_span_multiplier_key" json:"metrics_generator_processor_span_metrics_span_multiplier_key"`
+	MetricsGeneratorProcessorSpanMetricsEnableTraceStateSpanMultiplier          *bool                                   `yaml:"metrics_generator_processor_span_metrics_enable_tracestate_span_multiplier" json:"metrics_generator_processor_span_metrics_enable_tracestate_span_multiplier"`
+	MetricsGeneratorProcessorSpanMetricsRootDimensions                          []string                                `yaml:"metrics_generator_processor_span_metrics_root_dimensions" json:"metrics_generator_processor_span_metrics_root_dimensions"`
+	MetricsGeneratorProcessorHostInfoHostIdentifiers                            []string                                `yaml:"metrics_generator_processor_host_info_host_identifiers" json:"metrics_generator_processor_host_info_host_identifiers"`
+	MetricsGeneratorProcessorHostInfoMetricName                                 string                                  `yaml:"metrics_generator_processor_host_info_metric_name" json:"metrics_generator_processor_host_info_metric_name"`
+	MetricsGeneratorProcessorTraceQLMetricsMetrics                              []sharedconfig.TraceQLMetric            `yaml:"metrics_generator_processor_traceql_metrics_metrics" json:"metrics_generator_processor_traceql_metrics_metrics"`
+	MetricsGeneratorProcessorTraceQLMetricsHistogramBuckets                     []float64                               `yaml:"metrics_generator_processor_traceql_metrics_histogram_buckets" json:"metrics_generator_processor_traceql_metrics_histogram_buckets"`
+	MetricsGeneratorProcessorSpanEventsDimensions                               []string                                `yaml:"metrics_generator_processor_span_events_dimensions" json:"metrics_generator_processor_span_events_dimensions"`
+	MetricsGeneratorProcessorSpanEventsFilterPolicies                           []filterconfig.FilterPolicy             `yaml:"metrics_generator_processor_span_events_filter_policies" json:"metrics_generator_processor_span_events_filter_policies"`
+	MetricsGeneratorProcessorSpanEventsDimensionMappings                        []sharedconfig.DimensionMappings        `yaml:"metrics_generator_processor_span_events_dimension_mappings" json:"metrics_generator_processor_span_events_dimension_mappings"`
+	MetricsGeneratorProcessorCriticalPathWait                                   time.Duration                           `yaml:"metrics_generator_processor_critical_path_wait" json:"metrics_generator_processor_critical_path_wait"`
+	MetricsGeneratorProcessorCriticalPathMaxItems                               int                                     `yaml:"metrics_generator_processor_critical_path_max_items" json:"metrics_generator_processor_critical_path_max_items"`
+	MetricsGeneratorProcessorCriticalPathMaxSpansPerTrace                       int                                     `yaml:"metrics_generator_processor_critical_path_max_spans_per_trace" json:"metrics_generator_processor_critical_path_max_spans_per_trace"`
+	MetricsGeneratorIngestionSlack                                              time.Duration                           `yaml:"metrics_generator_ingestion_time_range_slack" json:"metrics_generator_ingestion_time_range_slack,omitempty"`
 
 	// Backend-worker/scheduler enforced limits.
 	BlockRetention     model.Duration `yaml:"block_retention" json:"block_retention"`
@@ -167,6 +200,7 @@ type LegacyOverrides struct {
 	MaxSearchDuration    model.Duration `yaml:"max_search_duration" json:"max_search_duration"`
 	MaxMetricsDuration   model.Duration `yaml:"max_metrics_duration" json:"max_metrics_duration"`
 	UnsafeQueryHints     bool           `yaml:"unsafe_query_hints" json:"unsafe_query_hints"`
+	MaxLinkFanOut        int            `yaml:"max_link_fan_out" json:"max_link_fan_out"`
 	LeftPadTraceIDs      bool           `yaml:"left_pad_trace_ids" json:"left_pad_trace_ids"`
 	MetricsSpanOnlyFetch *bool          `yaml:"metrics_spanonly_fetch,omitempty" json:"metrics_spanonly_fetch,omitempty"`
 
@@ -314,6 +348,9 @@ func (l *LegacyOverrides) toNewLimits() *Overrides {
 			MaxAttributeBytes:      l.IngestionMaxAttributeBytes,
 			ArtificialDelay:        l.IngestionArtificialDelay,
 			RetryInfoEnabled:       l.IngestionRetryInfoEnabled,
+			TailSampling:           l.IngestionTailSampling,
+			AttributeTransforms:    l.IngestionAttributeTransforms,
+			SchemaValidation:       l.IngestionSchemaValidation,
 		},
 		Read: ReadOverrides{
 			MaxBytesPerTagValuesQuery:     l.MaxBytesPerTagValuesQuery,
@@ -322,6 +359,7 @@ func (l *LegacyOverrides) toNewLimits() *Overrides {
 			MaxSearchDuration:             l.MaxSearchDuration,
 			MaxMetricsDuration:            l.MaxMetricsDuration,
 			UnsafeQueryHints:              l.UnsafeQueryHints,
+			MaxLinkFanOut:                 l.MaxLinkFanOut,
 			LeftPadTraceIDs:               l.LeftPadTraceIDs,
 			MetricsSpanOnlyFetch:          l.MetricsSpanOnlyFetch,
 		},
@@ -341,6 +379,10 @@ func (l *LegacyOverrides) toNewLimits() *Overrides {
 			IngestionSlack:           l.MetricsGeneratorIngestionSlack,
 			RemoteWriteHeaders:       l.MetricsGeneratorRemoteWriteHeaders,
 			GenerateNativeHistograms: l.MetricsGeneratorGenerateNativeHistograms,
+			OTLPExport: OTLPExportOverrides{
+				Endpoint: l.MetricsGeneratorOTLPExportEndpoint,
+				Headers:  l.MetricsGeneratorOTLPExportHeaders,
+			},
 			Forwarder: ForwarderOverrides{
 				QueueSize: l.MetricsGeneratorForwarderQueueSize,
 				Workers:   l.MetricsGeneratorForwarderWorkers,
@@ -368,17 +410,34 @@ func (l *LegacyOverrides) toNewLimits() *Overrides {
 					EnableInstanceLabel:            l.MetricsGeneratorProcessorSpanMetricsEnableInstanceLabel,
 					SpanMultiplierKey:              l.MetricsGeneratorProcessorSpanMetricsSpanMultiplierKey,
 					EnableTraceStateSpanMultiplier: l.MetricsGeneratorProcessorSpanMetricsEnableTraceStateSpanMultiplier,
+					RootDimensions:                 l.MetricsGeneratorProcessorSpanMetricsRootDimensions,
 				},
 				HostInfo: HostInfoOverrides{
 					HostIdentifiers: l.MetricsGeneratorProcessorHostInfoHostIdentifiers,
 					MetricName:      l.MetricsGeneratorProcessorHostInfoMetricName,
 				},
+				TraceQLMetrics: TraceQLMetricsOverrides{
+					Metrics:          l.MetricsGeneratorProcessorTraceQLMetricsMetrics,
+					HistogramBuckets: l.MetricsGeneratorProcessorTraceQLMetricsHistogramBuckets,
+				},
+				SpanEvents: SpanEventsOverrides{
+					Dimensions:        l.MetricsGeneratorProcessorSpanEventsDimensions,
+					FilterPolicies:    l.MetricsGeneratorProcessorSpanEventsFilterPolicies,
+					DimensionMappings: l.MetricsGeneratorProcessorSpanEventsDimensionMappings,
+				},
+				CriticalPath: CriticalPathOverrides{
+					Wait:             l.MetricsGeneratorProcessorCriticalPathWait,
+					MaxItems:         l.MetricsGeneratorProcessorCriticalPathMaxItems,
+					MaxSpansPerTrace: l.MetricsGeneratorProcessorCriticalPathMaxSpansPerTrace,
+				},
 			},
 			NativeHistogramBucketFactor:     l.MetricsGeneratorNativeHistogramBucketFactor,
 			NativeHistogramMaxBucketNumber:  l.MetricsGeneratorNativeHistogramMaxBucketNumber,
 			NativeHistogramMinResetDuration: l.MetricsGeneratorNativeHistogramMinResetDuration,
 			SpanNameSanitization:            l.MetricsGeneratorSpanNameSanitization,
+			SpanNameSanitizationRules:       l.MetricsGeneratorSpanNameSanitizationRules,
 			MaxCardinalityPerLabel:          l.MetricsGeneratorMaxCardinalityPerLabel,
+			MaxCardinalityPerLabelMode:      l.MetricsGeneratorMaxCardinalityPerLabelMode,
 		},
 		Forwarders: l.Forwarders,
 		Global: GlobalOverrides{
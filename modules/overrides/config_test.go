@@ -388,6 +388,27 @@ func generateTestLegacyOverrides() LegacyOverrides {
 		IngestionMaxAttributeBytes: 1000,
 		IngestionArtificialDelay:   durationPtr(5 * time.Minute),
 		IngestionRetryInfoEnabled:  true,
+		IngestionTailSampling: TailSamplingOverrides{
+			DecisionWait:     10 * time.Second,
+			MaxTraces:        1000,
+			MaxSpansPerTrace: 500,
+			Policies: []TailSamplingPolicy{
+				{Name: "errors", Type: TailSamplingPolicyStatusCode},
+				{Name: "slow", Type: TailSamplingPolicyLatency, Threshold: 5 * time.Second},
+			},
+		},
+		IngestionAttributeTransforms: []AttributeTransform{
+			{Action: AttributeTransformDelete, Key: "password"},
+			{Action: AttributeTransformHash, Scopes: []string{AttributeTransformScopeSpan}, Key: "user.email", Salt: "salt"},
+		},
+		IngestionSchemaValidation: SchemaValidationOverrides{
+			Action: SchemaValidationActionTag,
+			Rules: []SchemaValidationRule{
+				{Name: "service-name", Type: SchemaValidationRuleRequiredAttribute, Action: SchemaValidationActionReject, Key: "service.name"},
+				{Name: "http-status-code", Type: SchemaValidationRuleAttributeType, Scope: AttributeTransformScopeSpan, Key: "http.status_code", ValueType: "int"},
+				{Name: "span-names", Type: SchemaValidationRuleSpanNameCardinality, MaxSpanNames: 100},
+			},
+		},
 
 		MaxLocalTracesPerUser:  1000,
 		MaxGlobalTracesPerUser: 2000,
@@ -399,6 +420,7 @@ func generateTestLegacyOverrides() LegacyOverrides {
 		MetricsGeneratorMaxActiveSeries:                                             1000,
 		MetricsGeneratorMaxActiveEntities:                                           100,
 		MetricsGeneratorMaxCardinalityPerLabel:                                      500,
+		MetricsGeneratorMaxCardinalityPerLabelMode:                                  "top_k",
 		MetricsGeneratorCollectionInterval:                                          10 * time.Second,
 		MetricsGeneratorDisableCollection:                                           false,
 		MetricsGeneratorGenerateNativeHistograms:                                    histograms.HistogramMethodNative,
@@ -406,6 +428,8 @@ func generateTestLegacyOverrides() LegacyOverrides {
 		MetricsGeneratorForwarderQueueSize:                                          100,
 		MetricsGeneratorForwarderWorkers:                                            5,
 		MetricsGeneratorRemoteWriteHeaders:                                          RemoteWriteHeaders{"header-1": "value-1"},
+		MetricsGeneratorOTLPExportEndpoint:                                          "otlp:4317",
+		MetricsGeneratorOTLPExportHeaders:                                           RemoteWriteHeaders{"header-2": "value-2"},
 		MetricsGeneratorProcessorServiceGraphsHistogramBuckets:                      []float64{1.0, 2.0, 5.0},
 		MetricsGeneratorProcessorServiceGraphsDimensions:                            []string{"dimension-1", "dimension-2"},
 		MetricsGeneratorProcessorServiceGraphsPeerAttributes:                        []string{"attribute-1", "attribute-2"},
@@ -446,13 +470,23 @@ func generateTestLegacyOverrides() LegacyOverrides {
 		MetricsGeneratorProcessorSpanMetricsEnableInstanceLabel:            boolPtr(false),
 		MetricsGeneratorProcessorSpanMetricsSpanMultiplierKey:              "custom_key",
 		MetricsGeneratorProcessorSpanMetricsEnableTraceStateSpanMultiplier: boolPtr(true),
+		MetricsGeneratorProcessorSpanMetricsRootDimensions:                 []string{"service", "http.route"},
 		MetricsGeneratorProcessorHostInfoHostIdentifiers:                   []string{"host-id-1", "host-id-2"},
 		MetricsGeneratorProcessorHostInfoMetricName:                        "host_info",
+		MetricsGeneratorProcessorTraceQLMetricsMetrics:                     []sharedconfig.TraceQLMetric{{Name: "db_latency", Query: `{ span.db.system = "postgres" } | histogram_over_time(duration)`}},
+		MetricsGeneratorProcessorTraceQLMetricsHistogramBuckets:            []float64{0.1, 1},
+		MetricsGeneratorProcessorSpanEventsDimensions:                      []string{"exception.escaped"},
+		MetricsGeneratorProcessorSpanEventsFilterPolicies:                  []filterconfig.FilterPolicy{{Include: &filterconfig.PolicyMatch{MatchType: "strict", Attributes: []filterconfig.MatchPolicyAttribute{{Key: "kind", Value: "SPAN_KIND_SERVER"}}}}},
+		MetricsGeneratorProcessorSpanEventsDimensionMappings:               []sharedconfig.DimensionMappings{{Name: "env", SourceLabel: []string{"deployment.environment"}}},
+		MetricsGeneratorProcessorCriticalPathWait:                          30 * time.Second,
+		MetricsGeneratorProcessorCriticalPathMaxItems:                      5000,
+		MetricsGeneratorProcessorCriticalPathMaxSpansPerTrace:              500,
 		MetricsGeneratorIngestionSlack:                                     1 * time.Minute,
 		MetricsGeneratorNativeHistogramBucketFactor:                        1.5,
 		MetricsGeneratorNativeHistogramMaxBucketNumber:                     200,
 		MetricsGeneratorNativeHistogramMinResetDuration:                    10 * time.Minute,
 		MetricsGeneratorSpanNameSanitization:                               "",
+		MetricsGeneratorSpanNameSanitizationRules:                          []sharedconfig.SpanNameSanitizationRule{{Template: "/users/{id}"}},
 
 		BlockRetention:     model.Duration(7 * 24 * time.Hour),
 		CompactionDisabled: true,
@@ -465,6 +499,7 @@ func generateTestLegacyOverrides() LegacyOverrides {
 		MaxSearchDuration:    model.Duration(10 * time.Minute),
 		MaxMetricsDuration:   model.Duration(30 * time.Minute),
 		UnsafeQueryHints:     true,
+		MaxLinkFanOut:        10,
 		MetricsSpanOnlyFetch: boolPtr(true),
 
 		MaxBytesPerTrace: 10 * 1024 * 1024,
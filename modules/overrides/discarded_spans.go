@@ -19,6 +19,8 @@ const (
 	ReasonInvalidTraceID = "invalid_trace_id"
 	// ReasonInvalidSpanID indicates a batch was rejected because it contained an invalid span ID.
 	ReasonInvalidSpanID = "invalid_span_id"
+	// ReasonSchemaValidation indicates that the spans violated a schema validation rule with action reject.
+	ReasonSchemaValidation = "schema_validation"
 	// ReasonUnknown indicates an unknown error when pushing spans.
 	ReasonUnknown = "unknown_error"
 	// ReasonTraceTooLargeToCompact indicates a trace is too large for the backend-worker to combine/compact.
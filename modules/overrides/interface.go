@@ -36,6 +36,9 @@ type Interface interface {
 	MaxBytesPerTrace(userID string) int
 	IngestionArtificialDelay(userID string) (time.Duration, bool)
 	IngestionRetryInfoEnabled(userID string) bool
+	IngestionTailSampling(userID string) TailSamplingOverrides
+	IngestionAttributeTransforms(userID string) []AttributeTransform
+	IngestionSchemaValidation(userID string) SchemaValidationOverrides
 	MaxCompactionRange(userID string) time.Duration
 	Forwarders(userID string) []string
 	MaxBytesPerTagValuesQuery(userID string) int
@@ -55,6 +58,8 @@ type Interface interface {
 	MetricsGeneratorGenerateNativeHistograms(userID string) histograms.HistogramMethod
 	MetricsGeneratorTraceIDLabelName(userID string) string
 	MetricsGeneratorRemoteWriteHeaders(userID string) map[string]string
+	MetricsGeneratorOTLPExportEndpoint(userID string) string
+	MetricsGeneratorOTLPExportHeaders(userID string) map[string]string
 	MetricsGeneratorForwarderQueueSize(userID string) int
 	MetricsGeneratorForwarderWorkers(userID string) int
 	MetricsGeneratorProcessorServiceGraphsHistogramBuckets(userID string) []float64
@@ -74,21 +79,33 @@ type Interface interface {
 	MetricsGeneratorProcessorSpanMetricsEnableInstanceLabel(userID string) (bool, bool)
 	MetricsGeneratorProcessorHostInfoHostIdentifiers(userID string) []string
 	MetricsGeneratorProcessorHostInfoMetricName(userID string) string
+	MetricsGeneratorProcessorTraceQLMetricsMetrics(userID string) []sharedconfig.TraceQLMetric
+	MetricsGeneratorProcessorTraceQLMetricsHistogramBuckets(userID string) []float64
+	MetricsGeneratorProcessorSpanEventsDimensions(userID string) []string
+	MetricsGeneratorProcessorSpanEventsFilterPolicies(userID string) []config.FilterPolicy
+	MetricsGeneratorProcessorSpanEventsDimensionMappings(userID string) []sharedconfig.DimensionMappings
 	MetricsGeneratorProcessorServiceGraphsSpanMultiplierKey(userID string) string
 	MetricsGeneratorProcessorServiceGraphsEnableTraceStateSpanMultiplier(userID string) (bool, bool)
 	MetricsGeneratorProcessorSpanMetricsSpanMultiplierKey(userID string) string
 	MetricsGeneratorProcessorSpanMetricsEnableTraceStateSpanMultiplier(userID string) (bool, bool)
+	MetricsGeneratorProcessorSpanMetricsRootDimensions(userID string) []string
+	MetricsGeneratorProcessorCriticalPathWait(userID string) time.Duration
+	MetricsGeneratorProcessorCriticalPathMaxItems(userID string) int
+	MetricsGeneratorProcessorCriticalPathMaxSpansPerTrace(userID string) int
 	MetricsGeneratorNativeHistogramBucketFactor(userID string) float64
 	MetricsGeneratorNativeHistogramMaxBucketNumber(userID string) uint32
 	MetricsGeneratorNativeHistogramMinResetDuration(userID string) time.Duration
 	MetricsGeneratorSpanNameSanitization(userID string) string
+	MetricsGeneratorSpanNameSanitizationRules(userID string) []sharedconfig.SpanNameSanitizationRule
 	MetricsGeneratorMaxCardinalityPerLabel(userID string) uint64
+	MetricsGeneratorMaxCardinalityPerLabelMode(userID string) string
 	BlockRetention(userID string) time.Duration
 	CompactionDisabled(userID string) bool
 	MaxSearchDuration(userID string) time.Duration
 	MaxMetricsDuration(userID string) time.Duration
 	DedicatedColumns(userID string) backend.DedicatedColumns
 	UnsafeQueryHints(userID string) bool
+	MaxLinkFanOut(userID string) int
 	LeftPadTraceIDs(userID string) bool
 	MetricsSpanOnlyFetch(userID string) *bool
 	CostAttributionMaxCardinality(userID string) uint64
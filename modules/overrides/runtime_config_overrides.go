@@ -384,6 +384,23 @@ func (o *runtimeConfigOverridesManager) IngestionRetryInfoEnabled(userID string)
 	return o.getOverridesForUser(userID).Ingestion.RetryInfoEnabled
 }
 
+// IngestionTailSampling returns the tail sampling config of the distributor for a user.
+func (o *runtimeConfigOverridesManager) IngestionTailSampling(userID string) TailSamplingOverrides {
+	return o.getOverridesForUser(userID).Ingestion.TailSampling
+}
+
+// IngestionAttributeTransforms returns the transforms the distributor applies to the attributes of
+// the received spans of a user.
+func (o *runtimeConfigOverridesManager) IngestionAttributeTransforms(userID string) []AttributeTransform {
+	return o.getOverridesForUser(userID).Ingestion.AttributeTransforms
+}
+
+// IngestionSchemaValidation returns the rules the distributor validates the received spans of a
+// user against.
+func (o *runtimeConfigOverridesManager) IngestionSchemaValidation(userID string) SchemaValidationOverrides {
+	return o.getOverridesForUser(userID).Ingestion.SchemaValidation
+}
+
 // MaxBytesPerTrace returns the maximum size of a single trace in bytes allowed for a user.
 func (o *runtimeConfigOverridesManager) MaxBytesPerTrace(userID string) int {
 	return o.getOverridesForUser(userID).Global.MaxBytesPerTrace
@@ -413,6 +430,11 @@ func (o *runtimeConfigOverridesManager) UnsafeQueryHints(userID string) bool {
 	return o.getOverridesForUser(userID).Read.UnsafeQueryHints
 }
 
+// MaxLinkFanOut returns the maximum number of linked traces looked up for a TraceQL ->link query.
+func (o *runtimeConfigOverridesManager) MaxLinkFanOut(userID string) int {
+	return o.getOverridesForUser(userID).Read.MaxLinkFanOut
+}
+
 func (o *runtimeConfigOverridesManager) LeftPadTraceIDs(userID string) bool {
 	return o.getOverridesForUser(userID).Read.LeftPadTraceIDs
 }
@@ -449,6 +471,16 @@ func (o *runtimeConfigOverridesManager) MetricsGeneratorRemoteWriteHeaders(userI
 	return o.getOverridesForUser(userID).MetricsGenerator.RemoteWriteHeaders.toStringStringMap()
 }
 
+// MetricsGeneratorOTLPExportEndpoint returns the OTLP endpoint metrics are exported to for this tenant.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorOTLPExportEndpoint(userID string) string {
+	return o.getOverridesForUser(userID).MetricsGenerator.OTLPExport.Endpoint
+}
+
+// MetricsGeneratorOTLPExportHeaders returns the custom OTLP export headers for this tenant.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorOTLPExportHeaders(userID string) map[string]string {
+	return o.getOverridesForUser(userID).MetricsGenerator.OTLPExport.Headers.toStringStringMap()
+}
+
 // MetricsGeneratorRingSize is the desired size of the metrics-generator ring for this tenant.
 // Using shuffle sharding, a tenant can use a smaller ring than the entire ring.
 func (o *runtimeConfigOverridesManager) MetricsGeneratorRingSize(userID string) int {
@@ -517,6 +549,12 @@ func (o *runtimeConfigOverridesManager) MetricsGeneratorSpanNameSanitization(use
 	return o.defaultLimits.MetricsGenerator.SpanNameSanitization
 }
 
+// MetricsGeneratorSpanNameSanitizationRules are the rules to rewrite span names, they are evaluated
+// in order before the automatic span name sanitization.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorSpanNameSanitizationRules(userID string) []sharedconfig.SpanNameSanitizationRule {
+	return o.getOverridesForUser(userID).MetricsGenerator.SpanNameSanitizationRules
+}
+
 // MetricsGeneratorMaxCardinalityPerLabel is the maximum number of distinct values any single
 // label can have before values are replaced with __cardinality_overflow__.
 // 0 disables the limit.
@@ -524,6 +562,12 @@ func (o *runtimeConfigOverridesManager) MetricsGeneratorMaxCardinalityPerLabel(u
 	return o.getOverridesForUser(userID).MetricsGenerator.MaxCardinalityPerLabel
 }
 
+// MetricsGeneratorMaxCardinalityPerLabelMode is how values over the max cardinality per label are
+// handled. "top_k" keeps the most active values and replaces the others with __other__.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorMaxCardinalityPerLabelMode(userID string) string {
+	return o.getOverridesForUser(userID).MetricsGenerator.MaxCardinalityPerLabelMode
+}
+
 // MetricsGeneratorTraceIDLabelName is the label name used for the trace ID in metrics.
 // "TraceID" is used if no value is provided.
 func (o *runtimeConfigOverridesManager) MetricsGeneratorTraceIDLabelName(userID string) string {
@@ -647,6 +691,30 @@ func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorHostInfoMetricN
 	return o.getOverridesForUser(userID).MetricsGenerator.Processor.HostInfo.MetricName
 }
 
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorTraceQLMetricsMetrics(userID string) []sharedconfig.TraceQLMetric {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.TraceQLMetrics.Metrics
+}
+
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorTraceQLMetricsHistogramBuckets(userID string) []float64 {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.TraceQLMetrics.HistogramBuckets
+}
+
+// MetricsGeneratorProcessorSpanEventsDimensions controls the dimensions that are added to the
+// span events processor.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanEventsDimensions(userID string) []string {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.SpanEvents.Dimensions
+}
+
+// MetricsGeneratorProcessorSpanEventsFilterPolicies controls the filter policies that are added to the span events processor.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanEventsFilterPolicies(userID string) []filterconfig.FilterPolicy {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.SpanEvents.FilterPolicies
+}
+
+// MetricsGeneratorProcessorSpanEventsDimensionMappings controls custom dimension mapping of the span events processor.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanEventsDimensionMappings(userID string) []sharedconfig.DimensionMappings {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.SpanEvents.DimensionMappings
+}
+
 func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsSpanMultiplierKey(userID string) string {
 	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.SpanMultiplierKey
 }
@@ -671,6 +739,30 @@ func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanMetricsEnab
 	return false, false
 }
 
+// MetricsGeneratorProcessorSpanMetricsRootDimensions controls the dimensions of the root span that
+// are added to the metrics of all spans of a trace.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanMetricsRootDimensions(userID string) []string {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.SpanMetrics.RootDimensions
+}
+
+// MetricsGeneratorProcessorCriticalPathWait controls how long the critical path processor waits
+// for new spans of a trace before it's considered complete.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorCriticalPathWait(userID string) time.Duration {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.CriticalPath.Wait
+}
+
+// MetricsGeneratorProcessorCriticalPathMaxItems controls the number of traces buffered by the
+// critical path processor.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorCriticalPathMaxItems(userID string) int {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.CriticalPath.MaxItems
+}
+
+// MetricsGeneratorProcessorCriticalPathMaxSpansPerTrace controls the number of spans buffered per
+// trace by the critical path processor.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorCriticalPathMaxSpansPerTrace(userID string) int {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.CriticalPath.MaxSpansPerTrace
+}
+
 // BlockRetention is the duration of the block retention for this tenant.
 func (o *runtimeConfigOverridesManager) BlockRetention(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).Compaction.BlockRetention)
@@ -101,7 +101,7 @@ type clientImpl struct {
 var _ Client = (*clientImpl)(nil)
 
 func New(cfg *Config) (Client, error) {
-	rw, err := initBackend(cfg)
+	rw, err := NewVersionedReaderWriter(cfg, OverridesKeyPath)
 	if err != nil {
 		return nil, err
 	}
@@ -112,15 +112,17 @@ func (o *clientImpl) Shutdown() {
 	o.rw.Shutdown()
 }
 
-func initBackend(cfg *Config) (rw backend.VersionedReaderWriter, err error) {
+// NewVersionedReaderWriter creates a client for the configured backend. The key path is the
+// top level directory the caller stores its objects in.
+func NewVersionedReaderWriter(cfg *Config, keyPath string) (rw backend.VersionedReaderWriter, err error) {
 	switch cfg.Backend {
 	case backend.Local:
 		r, w, _, err := local.New(cfg.Local)
 		if err != nil {
 			return nil, err
 		}
-		// Create overrides directory with necessary permissions
-		err = os.MkdirAll(path.Join(cfg.Local.Path, OverridesKeyPath), 0o700)
+		// Create key path directory with necessary permissions
+		err = os.MkdirAll(path.Join(cfg.Local.Path, keyPath), 0o700)
 		if err != nil {
 			return nil, err
 		}
@@ -139,7 +141,8 @@ func initBackend(cfg *Config) (rw backend.VersionedReaderWriter, err error) {
 	}
 	if cfg.Backend == backend.Local || cfg.Backend == backend.S3 || cfg.Backend == backend.Azure {
 		level.Warn(log.Logger).Log(
-			"msg", "versioned backend requests are best-effort for the configured backend, concurrent requests modifying user-configurable overrides might cause data races",
+			"msg", "versioned backend requests are best-effort for the configured backend, concurrent requests modifying the same object might cause data races",
+			"key_path", keyPath,
 			"backend", cfg.Backend,
 		)
 	}
@@ -860,9 +860,11 @@ func (q *Querier) postProcessIngesterSearchResults(req *tempopb.SearchRequest, r
 	}
 
 	traces := map[string]*tempopb.TraceSearchMetadata{}
+	table := traceql.NewSearchTableCombiner(nil)
 
 	for _, result := range results {
 		sr := result.(*tempopb.SearchResponse)
+		table.AddRows(sr.Table)
 
 		for _, t := range sr.Traces {
 			// Just simply take first result for each trace
@@ -879,6 +881,7 @@ func (q *Querier) postProcessIngesterSearchResults(req *tempopb.SearchRequest, r
 	for _, t := range traces {
 		response.Traces = append(response.Traces, t)
 	}
+	response.Table = table.PartialRows()
 
 	// Sort and limit results
 	sort.Slice(response.Traces, func(i, j int) bool {
@@ -66,6 +66,7 @@ const (
 
 	PathTraces              = "/api/traces/{traceID}"
 	PathSearch              = "/api/search"
+	PathSearchExplain       = "/api/search/explain"
 	PathSearchTags          = "/api/search/tags"
 	PathSearchTagValues     = "/api/search/tag/" + MuxVarTagInPath + "/values"
 	PathEcho                = "/api/echo"
@@ -78,6 +79,9 @@ const (
 	// PathOverrides user configurable overrides
 	PathOverrides = "/api/overrides"
 
+	// PathTraceQLMacros per-tenant named TraceQL fragments
+	PathTraceQLMacros = "/api/traceql/macros"
+
 	PathSearchTagValuesV2 = "/api/v2/search/tag/" + MuxVarTagInPath + "/values"
 	PathSearchTagsV2      = "/api/v2/search/tags"
 	PathTracesV2          = "/api/v2/traces/{traceID}"
@@ -12,6 +12,7 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/backoff"
+	dstls "github.com/grafana/dskit/crypto/tls"
 	"github.com/grafana/dskit/flagext"
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
@@ -35,6 +36,16 @@ const (
 	minProducerRecordDataBytesLimit = 1024 * 1024
 )
 
+// Supported SASL mechanisms.
+const (
+	SASLMechanismPlain       = "PLAIN"
+	SASLMechanismScramSHA256 = "SCRAM-SHA-256"
+	SASLMechanismScramSHA512 = "SCRAM-SHA-512"
+	SASLMechanismOAuthBearer = "OAUTHBEARER"
+)
+
+var saslMechanisms = []string{SASLMechanismPlain, SASLMechanismScramSHA256, SASLMechanismScramSHA512, SASLMechanismOAuthBearer}
+
 var (
 	ErrMissingKafkaAddress               = errors.New("the Kafka address has not been configured")
 	ErrMissingKafkaTopic                 = errors.New("the Kafka topic has not been configured")
@@ -42,6 +53,9 @@ var (
 	ErrInvalidMaxConsumerLagAtStartup    = errors.New("the configured max consumer lag at startup must greater or equal than the configured target consumer lag")
 	ErrInvalidProducerMaxRecordSizeBytes = fmt.Errorf("the configured producer max record size bytes must be a value between %d and %d", minProducerRecordDataBytesLimit, maxProducerRecordDataBytesLimit)
 	ErrInconsistentSASLCredentials       = errors.New("the SASL username and password must be both configured to enable SASL authentication")
+	ErrInvalidSASLMechanism              = fmt.Errorf("the configured SASL mechanism is not supported, supported mechanisms: %s", strings.Join(saslMechanisms, ", "))
+	ErrMissingSASLCredentials            = errors.New("the SASL username and password must be configured to use SCRAM authentication")
+	ErrInconsistentSASLOAuthToken        = errors.New("exactly one of the SASL OAuth token and token file must be configured to use OAUTHBEARER authentication")
 )
 
 type Config struct {
@@ -64,8 +78,14 @@ type KafkaConfig struct {
 	DialTimeout  time.Duration `yaml:"dial_timeout"`
 	WriteTimeout time.Duration `yaml:"write_timeout"`
 
-	SASLUsername string         `yaml:"sasl_username"`
-	SASLPassword flagext.Secret `yaml:"sasl_password"`
+	TLSEnabled bool               `yaml:"tls_enabled"`
+	TLS        dstls.ClientConfig `yaml:",inline"`
+
+	SASLMechanism      string         `yaml:"sasl_mechanism"`
+	SASLUsername       string         `yaml:"sasl_username"`
+	SASLPassword       flagext.Secret `yaml:"sasl_password"`
+	SASLOAuthToken     flagext.Secret `yaml:"sasl_oauth_token"`
+	SASLOAuthTokenFile string         `yaml:"sasl_oauth_token_file"`
 
 	ConsumerGroup                     string        `yaml:"consumer_group"`
 	ConsumerGroupOffsetCommitInterval time.Duration `yaml:"consumer_group_offset_commit_interval"`
@@ -101,8 +121,14 @@ func (cfg *KafkaConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet)
 	f.DurationVar(&cfg.DialTimeout, prefix+".dial-timeout", 2*time.Second, "The maximum time allowed to open a connection to a Kafka broker.")
 	f.DurationVar(&cfg.WriteTimeout, prefix+".write-timeout", 10*time.Second, "How long to wait for an incoming write request to be successfully committed to the Kafka backend.")
 
+	f.BoolVar(&cfg.TLSEnabled, prefix+".tls-enabled", false, "Enable connecting to Kafka with TLS.")
+	cfg.TLS.RegisterFlagsWithPrefix(prefix, f)
+
+	f.StringVar(&cfg.SASLMechanism, prefix+".sasl-mechanism", SASLMechanismPlain, "The SASL mechanism used for authentication. Supported mechanisms: "+strings.Join(saslMechanisms, ", ")+".")
 	f.StringVar(&cfg.SASLUsername, prefix+".sasl-username", "", "The SASL username for authentication.")
 	f.Var(&cfg.SASLPassword, prefix+".sasl-password", "The SASL password for authentication.")
+	f.Var(&cfg.SASLOAuthToken, prefix+".sasl-oauth-token", "The OAuth token used for OAUTHBEARER authentication.")
+	f.StringVar(&cfg.SASLOAuthTokenFile, prefix+".sasl-oauth-token-file", "", "Path to a file containing the OAuth token used for OAUTHBEARER authentication. The file is read again for every new connection, so the token can be rotated.")
 
 	f.StringVar(&cfg.ConsumerGroup, prefix+".consumer-group", "", "The consumer group used by the consumer to track the last consumed offset. The consumer group must be different for each ingester. If the configured consumer group contains the '<partition>' placeholder, it is replaced with the actual partition ID owned by the ingester. When empty (recommended), Tempo uses the ingester instance ID to guarantee uniqueness.")
 	f.DurationVar(&cfg.ConsumerGroupOffsetCommitInterval, prefix+".consumer-group-offset-commit-interval", time.Second, "How frequently a consumer should commit the consumed offset to Kafka. The last committed offset is used at startup to continue the consumption from where it was left.")
@@ -141,8 +167,21 @@ func (cfg *KafkaConfig) Validate() error {
 		return ErrInvalidMaxConsumerLagAtStartup
 	}
 
-	if (cfg.SASLUsername == "") != (cfg.SASLPassword.String() == "") {
-		return ErrInconsistentSASLCredentials
+	switch cfg.SASLMechanism {
+	case "", SASLMechanismPlain:
+		if (cfg.SASLUsername == "") != (cfg.SASLPassword.String() == "") {
+			return ErrInconsistentSASLCredentials
+		}
+	case SASLMechanismScramSHA256, SASLMechanismScramSHA512:
+		if cfg.SASLUsername == "" || cfg.SASLPassword.String() == "" {
+			return ErrMissingSASLCredentials
+		}
+	case SASLMechanismOAuthBearer:
+		if (cfg.SASLOAuthToken.String() == "") == (cfg.SASLOAuthTokenFile == "") {
+			return ErrInconsistentSASLOAuthToken
+		}
+	default:
+		return ErrInvalidSASLMechanism
 	}
 
 	return nil
@@ -164,7 +203,13 @@ func (cfg KafkaConfig) SetDefaultNumberOfPartitionsForAutocreatedTopics(logger l
 		return
 	}
 
-	cl, err := kgo.NewClient(commonKafkaClientOptions(cfg, nil, logger)...)
+	opts, err := commonKafkaClientOptions(cfg, nil, logger)
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to create kafka client options", "err", err)
+		return
+	}
+
+	cl, err := kgo.NewClient(opts...)
 	if err != nil {
 		level.Error(logger).Log("msg", "failed to create kafka client", "err", err)
 		return
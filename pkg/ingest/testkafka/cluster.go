@@ -22,9 +22,10 @@ type Cluster struct {
 	controlFuncs     map[kmsg.Key]controlFn
 }
 
-// CreateCluster returns a fake Kafka cluster for unit testing.
-func CreateCluster(t testing.TB, numPartitions int32, topicName string) (*Cluster, string) {
-	fake, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(numPartitions, topicName))
+// CreateCluster returns a fake Kafka cluster for unit testing. The options are applied after the
+// default ones, for example to enable TLS or SASL.
+func CreateCluster(t testing.TB, numPartitions int32, topicName string, opts ...kfake.Opt) (*Cluster, string) {
+	fake, err := kfake.NewCluster(append([]kfake.Opt{kfake.NumBrokers(1), kfake.SeedTopics(numPartitions, topicName)}, opts...)...)
 	require.NoError(t, err)
 	t.Cleanup(fake.Close)
 
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,7 +16,10 @@ import (
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kgo"
 	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/oauth"
 	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
 	"github.com/twmb/franz-go/plugin/kotel"
 	"github.com/twmb/franz-go/plugin/kprom"
 	"go.opentelemetry.io/otel/propagation"
@@ -22,19 +27,24 @@ import (
 	"go.uber.org/atomic"
 )
 
-// NewWriterClient returns the kgo.Client that should be used by the Writer.
+// NewWriterClient returns the kgo.Client that should be used by the Writer. The extra options are
+// applied last and override the defaults.
 //
 // The input prometheus.Registerer must be wrapped with a prefix (the names of metrics
 // registered don't have a prefix).
-func NewWriterClient(kafkaCfg KafkaConfig, maxInflightProduceRequests int, logger log.Logger, reg prometheus.Registerer) (*kgo.Client, error) {
+func NewWriterClient(kafkaCfg KafkaConfig, maxInflightProduceRequests int, logger log.Logger, reg prometheus.Registerer, extraOpts ...kgo.Opt) (*kgo.Client, error) {
 	// Do not export the client ID, because we use it to specify options to the backend.
 	metrics := kprom.NewMetrics(
 		"", // No prefix. We expect the input prometheus.Registered to be wrapped with a prefix.
 		kprom.Registerer(reg),
 		kprom.FetchAndProduceDetail(kprom.Batches, kprom.Records, kprom.CompressedBytes, kprom.UncompressedBytes))
 
-	opts := append(
-		commonKafkaClientOptions(kafkaCfg, metrics, logger),
+	opts, err := commonKafkaClientOptions(kafkaCfg, metrics, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts,
 		kgo.RequiredAcks(kgo.AllISRAcks()),
 		kgo.DefaultProduceTopic(kafkaCfg.Topic),
 
@@ -81,6 +91,7 @@ func NewWriterClient(kafkaCfg KafkaConfig, maxInflightProduceRequests int, logge
 		kgo.MaxBufferedRecords(math.MaxInt), // Use a high value to set it as unlimited, because the client doesn't support "0 as unlimited".
 		kgo.MaxBufferedBytes(0),
 	)
+	opts = append(opts, extraOpts...)
 	if kafkaCfg.AutoCreateTopicEnabled {
 		kafkaCfg.SetDefaultNumberOfPartitionsForAutocreatedTopics(logger)
 	}
@@ -99,7 +110,7 @@ func (o onlySampledTraces) Inject(ctx context.Context, carrier propagation.TextM
 	o.TextMapPropagator.Inject(ctx, carrier)
 }
 
-func commonKafkaClientOptions(cfg KafkaConfig, metrics *kprom.Metrics, logger log.Logger) []kgo.Opt {
+func commonKafkaClientOptions(cfg KafkaConfig, metrics *kprom.Metrics, logger log.Logger) ([]kgo.Opt, error) {
 	opts := []kgo.Opt{
 		kgo.ClientID(cfg.ClientID),
 		kgo.SeedBrokers(cfg.Address),
@@ -147,14 +158,16 @@ func commonKafkaClientOptions(cfg KafkaConfig, metrics *kprom.Metrics, logger lo
 		opts = append(opts, kgo.AllowAutoTopicCreation())
 	}
 
-	// SASL plain auth.
-	if cfg.SASLUsername != "" && cfg.SASLPassword.String() != "" {
-		opts = append(opts, kgo.SASL(plain.Plain(func(_ context.Context) (plain.Auth, error) {
-			return plain.Auth{
-				User: cfg.SASLUsername,
-				Pass: cfg.SASLPassword.String(),
-			}, nil
-		})))
+	if cfg.TLSEnabled {
+		tlsCfg, err := cfg.TLS.GetTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("creating Kafka TLS config: %w", err)
+		}
+		opts = append(opts, kgo.DialTLSConfig(tlsCfg))
+	}
+
+	if mechanism := saslMechanism(cfg); mechanism != nil {
+		opts = append(opts, kgo.SASL(mechanism))
 	}
 
 	tracer := kotel.NewTracer(
@@ -169,7 +182,49 @@ func commonKafkaClientOptions(cfg KafkaConfig, metrics *kprom.Metrics, logger lo
 		opts = append(opts, kgo.WithHooks(metrics))
 	}
 
-	return opts
+	return opts, nil
+}
+
+// saslMechanism returns the SASL mechanism to authenticate with or nil if SASL authentication
+// isn't configured.
+func saslMechanism(cfg KafkaConfig) sasl.Mechanism {
+	switch cfg.SASLMechanism {
+	case SASLMechanismScramSHA256, SASLMechanismScramSHA512:
+		auth := func(_ context.Context) (scram.Auth, error) {
+			return scram.Auth{
+				User: cfg.SASLUsername,
+				Pass: cfg.SASLPassword.String(),
+			}, nil
+		}
+		if cfg.SASLMechanism == SASLMechanismScramSHA256 {
+			return scram.Sha256(auth)
+		}
+		return scram.Sha512(auth)
+
+	case SASLMechanismOAuthBearer:
+		return oauth.Oauth(func(_ context.Context) (oauth.Auth, error) {
+			if cfg.SASLOAuthTokenFile == "" {
+				return oauth.Auth{Token: cfg.SASLOAuthToken.String()}, nil
+			}
+			// the token file is read for every new connection to pick up rotated tokens
+			token, err := os.ReadFile(cfg.SASLOAuthTokenFile)
+			if err != nil {
+				return oauth.Auth{}, fmt.Errorf("reading SASL OAuth token file: %w", err)
+			}
+			return oauth.Auth{Token: strings.TrimSpace(string(token))}, nil
+		})
+
+	default:
+		if cfg.SASLUsername == "" || cfg.SASLPassword.String() == "" {
+			return nil
+		}
+		return plain.Plain(func(_ context.Context) (plain.Auth, error) {
+			return plain.Auth{
+				User: cfg.SASLUsername,
+				Pass: cfg.SASLPassword.String(),
+			}, nil
+		})
+	}
 }
 
 // Producer is a kgo.Client wrapper exposing some higher level features and metrics useful for producers.
@@ -0,0 +1,309 @@
+package ingest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/pkg/ingest/testkafka"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestKafkaClientAuthentication(t *testing.T) {
+	certs := newTestCertificates(t)
+
+	serverTLS := func(clientAuth tls.ClientAuthType) kfake.Opt {
+		return kfake.TLS(&tls.Config{
+			Certificates: []tls.Certificate{certs.server},
+			ClientCAs:    certs.pool,
+			ClientAuth:   clientAuth,
+		})
+	}
+
+	tcs := []struct {
+		name        string
+		clusterOpts []kfake.Opt
+		// setup configures the cluster after it's created, for mechanisms kfake doesn't support
+		setup     func(t *testing.T, cluster *testkafka.Cluster)
+		configure func(cfg *KafkaConfig)
+		expectErr bool
+	}{
+		{
+			name: "no authentication",
+		},
+		{
+			name:        "SASL plain",
+			clusterOpts: []kfake.Opt{kfake.EnableSASL(), kfake.Superuser(SASLMechanismPlain, "user", "pass")},
+			configure: func(cfg *KafkaConfig) {
+				cfg.SASLUsername = "user"
+				require.NoError(t, cfg.SASLPassword.Set("pass"))
+			},
+		},
+		{
+			name:        "SASL SCRAM-SHA-256",
+			clusterOpts: []kfake.Opt{kfake.EnableSASL(), kfake.Superuser(SASLMechanismScramSHA256, "user", "pass")},
+			configure: func(cfg *KafkaConfig) {
+				cfg.SASLMechanism = SASLMechanismScramSHA256
+				cfg.SASLUsername = "user"
+				require.NoError(t, cfg.SASLPassword.Set("pass"))
+			},
+		},
+		{
+			name:        "SASL SCRAM-SHA-512",
+			clusterOpts: []kfake.Opt{kfake.EnableSASL(), kfake.Superuser(SASLMechanismScramSHA512, "user", "pass")},
+			configure: func(cfg *KafkaConfig) {
+				cfg.SASLMechanism = SASLMechanismScramSHA512
+				cfg.SASLUsername = "user"
+				require.NoError(t, cfg.SASLPassword.Set("pass"))
+			},
+		},
+		{
+			name:        "SASL SCRAM-SHA-512 wrong password",
+			clusterOpts: []kfake.Opt{kfake.EnableSASL(), kfake.Superuser(SASLMechanismScramSHA512, "user", "pass")},
+			configure: func(cfg *KafkaConfig) {
+				cfg.SASLMechanism = SASLMechanismScramSHA512
+				cfg.SASLUsername = "user"
+				require.NoError(t, cfg.SASLPassword.Set("wrong"))
+			},
+			expectErr: true,
+		},
+		{
+			name: "SASL OAUTHBEARER",
+			setup: func(t *testing.T, cluster *testkafka.Cluster) {
+				// kfake doesn't support OAUTHBEARER, so the handshake and authentication are handled here
+				cluster.ControlKey(kmsg.SASLHandshake, func(req kmsg.Request) (kmsg.Response, error, bool) {
+					cluster.KeepControl()
+					require.Equal(t, SASLMechanismOAuthBearer, req.(*kmsg.SASLHandshakeRequest).Mechanism)
+					return req.ResponseKind(), nil, true
+				})
+				authenticated := atomic.NewBool(false)
+				cluster.ControlKey(kmsg.SASLAuthenticate, func(req kmsg.Request) (kmsg.Response, error, bool) {
+					cluster.KeepControl()
+					require.Equal(t, "n,,\x01auth=Bearer token\x01\x01", string(req.(*kmsg.SASLAuthenticateRequest).SASLAuthBytes))
+					authenticated.Store(true)
+					return req.ResponseKind(), nil, true
+				})
+				t.Cleanup(func() { require.True(t, authenticated.Load()) })
+			},
+			configure: func(cfg *KafkaConfig) {
+				tokenFile := filepath.Join(t.TempDir(), "token")
+				require.NoError(t, os.WriteFile(tokenFile, []byte("token\n"), 0o600))
+
+				cfg.SASLMechanism = SASLMechanismOAuthBearer
+				cfg.SASLOAuthTokenFile = tokenFile
+			},
+		},
+		{
+			name:        "TLS",
+			clusterOpts: []kfake.Opt{serverTLS(tls.NoClientCert)},
+			configure: func(cfg *KafkaConfig) {
+				cfg.TLSEnabled = true
+				cfg.TLS.CAPath = certs.caPath
+			},
+		},
+		{
+			name:        "TLS unknown CA",
+			clusterOpts: []kfake.Opt{serverTLS(tls.NoClientCert)},
+			configure: func(cfg *KafkaConfig) {
+				cfg.TLSEnabled = true
+			},
+			expectErr: true,
+		},
+		{
+			name:        "TLS insecure skip verify",
+			clusterOpts: []kfake.Opt{serverTLS(tls.NoClientCert)},
+			configure: func(cfg *KafkaConfig) {
+				cfg.TLSEnabled = true
+				cfg.TLS.InsecureSkipVerify = true
+			},
+		},
+		{
+			name:        "mTLS with SASL SCRAM-SHA-256",
+			clusterOpts: []kfake.Opt{serverTLS(tls.RequireAndVerifyClientCert), kfake.EnableSASL(), kfake.Superuser(SASLMechanismScramSHA256, "user", "pass")},
+			configure: func(cfg *KafkaConfig) {
+				cfg.TLSEnabled = true
+				cfg.TLS.CAPath = certs.caPath
+				cfg.TLS.CertPath = certs.clientCertPath
+				cfg.TLS.KeyPath = certs.clientKeyPath
+				cfg.TLS.ServerName = "kafka"
+				cfg.SASLMechanism = SASLMechanismScramSHA256
+				cfg.SASLUsername = "user"
+				require.NoError(t, cfg.SASLPassword.Set("pass"))
+			},
+		},
+		{
+			name:        "mTLS without client certificate",
+			clusterOpts: []kfake.Opt{serverTLS(tls.RequireAndVerifyClientCert)},
+			configure: func(cfg *KafkaConfig) {
+				cfg.TLSEnabled = true
+				cfg.TLS.CAPath = certs.caPath
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster, addr := testkafka.CreateCluster(t, 1, topicName, tc.clusterOpts...)
+			if tc.setup != nil {
+				tc.setup(t, cluster)
+			}
+
+			cfg := createTestKafkaConfig(addr)
+			cfg.AutoCreateTopicEnabled = false
+			if tc.configure != nil {
+				tc.configure(&cfg)
+			}
+			if tc.expectErr {
+				cfg.WriteTimeout = time.Second
+			}
+			require.NoError(t, cfg.Validate())
+
+			writer, err := NewWriterClient(cfg, 1, test.NewTestingLogger(t), prometheus.NewRegistry())
+			require.NoError(t, err)
+			t.Cleanup(writer.Close)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			res := writer.ProduceSync(ctx, &kgo.Record{Partition: 0, Value: []byte("value")})
+			if tc.expectErr {
+				require.Error(t, res.FirstErr())
+				return
+			}
+			require.NoError(t, res.FirstErr())
+
+			reader, err := NewReaderClient(cfg, NewReaderClientMetrics("test", prometheus.NewRegistry()), test.NewTestingLogger(t),
+				kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{topicName: {0: kgo.NewOffset().AtStart()}}))
+			require.NoError(t, err)
+			t.Cleanup(reader.Close)
+
+			fetches := reader.PollFetches(ctx)
+			require.NoError(t, fetches.Err())
+			records := fetches.Records()
+			require.Len(t, records, 1)
+			require.Equal(t, "value", string(records[0].Value))
+		})
+	}
+}
+
+func TestKafkaConfigValidateSASL(t *testing.T) {
+	tcs := []struct {
+		mechanism, username, password, token, tokenFile string
+		expected                                        error
+	}{
+		{mechanism: SASLMechanismPlain},
+		{mechanism: SASLMechanismPlain, username: "user", password: "pass"},
+		{mechanism: SASLMechanismPlain, username: "user", expected: ErrInconsistentSASLCredentials},
+		{mechanism: SASLMechanismScramSHA256, username: "user", password: "pass"},
+		{mechanism: SASLMechanismScramSHA512, expected: ErrMissingSASLCredentials},
+		{mechanism: SASLMechanismOAuthBearer, token: "token"},
+		{mechanism: SASLMechanismOAuthBearer, tokenFile: "/token"},
+		{mechanism: SASLMechanismOAuthBearer, expected: ErrInconsistentSASLOAuthToken},
+		{mechanism: SASLMechanismOAuthBearer, token: "token", tokenFile: "/token", expected: ErrInconsistentSASLOAuthToken},
+		{mechanism: "GSSAPI", expected: ErrInvalidSASLMechanism},
+	}
+
+	for _, tc := range tcs {
+		cfg := createTestKafkaConfig("localhost:9092")
+		cfg.SASLMechanism = tc.mechanism
+		cfg.SASLUsername = tc.username
+		require.NoError(t, cfg.SASLPassword.Set(tc.password))
+		require.NoError(t, cfg.SASLOAuthToken.Set(tc.token))
+		cfg.SASLOAuthTokenFile = tc.tokenFile
+
+		require.Equal(t, tc.expected, cfg.Validate(), strings.Join([]string{tc.mechanism, tc.username, tc.token, tc.tokenFile}, "/"))
+	}
+}
+
+type testCertificates struct {
+	pool   *x509.CertPool
+	server tls.Certificate
+
+	caPath         string
+	clientCertPath string
+	clientKeyPath  string
+}
+
+// newTestCertificates creates a CA with a server certificate for 127.0.0.1 and kafka and a client
+// certificate.
+func newTestCertificates(t *testing.T) testCertificates {
+	dir := t.TempDir()
+
+	newKey := func() *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		return key
+	}
+	writePEM := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+		return path
+	}
+
+	caKey := newKey()
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	newCert := func(serial int64, extKeyUsage x509.ExtKeyUsage) ([]byte, *ecdsa.PrivateKey) {
+		key := newKey()
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "kafka"},
+			DNSNames:     []string{"kafka"},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{extKeyUsage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		return der, key
+	}
+
+	serverDER, serverKey := newCert(2, x509.ExtKeyUsageServerAuth)
+	clientDER, clientKey := newCert(3, x509.ExtKeyUsageClientAuth)
+	clientKeyDER, err := x509.MarshalECPrivateKey(clientKey)
+	require.NoError(t, err)
+
+	certs := testCertificates{
+		pool:           x509.NewCertPool(),
+		server:         tls.Certificate{Certificate: [][]byte{serverDER}, PrivateKey: serverKey},
+		caPath:         writePEM("ca.crt", "CERTIFICATE", caDER),
+		clientCertPath: writePEM("client.crt", "CERTIFICATE", clientDER),
+		clientKeyPath:  writePEM("client.key", "EC PRIVATE KEY", clientKeyDER),
+	}
+	certs.pool.AddCert(ca)
+	return certs
+}
@@ -5,3 +5,21 @@ type DimensionMappings struct {
 	SourceLabel []string `yaml:"source_labels" json:"source_labels"`
 	Join        string   `yaml:"join" json:"join"`
 }
+
+// TraceQLMetric is a metric generated from a TraceQL metrics query.
+type TraceQLMetric struct {
+	Name  string `yaml:"name" json:"name"`
+	Query string `yaml:"query" json:"query"`
+}
+
+// SpanNameSanitizationRule rewrites the span names matching a regular expression or a URL route
+// template. Exactly one of Pattern and Template is set.
+type SpanNameSanitizationRule struct {
+	// Pattern is a regular expression, its matches in the span name are replaced with Replacement.
+	// Replacement can reference capture groups, for example $1.
+	Pattern string `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	// Template is a URL route template like /users/{id}, every {param} matches a path segment.
+	// Matching span names are replaced with the template, or Replacement if set.
+	Template    string `yaml:"template,omitempty" json:"template,omitempty"`
+	Replacement string `yaml:"replacement,omitempty" json:"replacement,omitempty"`
+}
@@ -673,6 +673,8 @@ func (m *DedicatedColumn) GetOptions() DedicatedColumn_Option {
 type SearchResponse struct {
 	Traces  []*TraceSearchMetadata `protobuf:"bytes,1,rep,name=traces,proto3" json:"traces,omitempty"`
 	Metrics *SearchMetrics         `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	// Set for queries that group spans with by() and aggregate the groups.
+	Table []*SearchTableRow `protobuf:"bytes,3,rep,name=table,proto3" json:"table,omitempty"`
 }
 
 func (m *SearchResponse) Reset()         { *m = SearchResponse{} }
@@ -722,6 +724,91 @@ func (m *SearchResponse) GetMetrics() *SearchMetrics {
 	return nil
 }
 
+func (m *SearchResponse) GetTable() []*SearchTableRow {
+	if m != nil {
+		return m.Table
+	}
+	return nil
+}
+
+// SearchTableRow is a by() group combined across all traces evaluated by a search. The rows of a
+// search job are not filtered, the query-frontend filters the combined rows.
+type SearchTableRow struct {
+	Group          []*v1.KeyValue `protobuf:"bytes,1,rep,name=group,proto3" json:"group,omitempty"`
+	Aggregates     []*v1.KeyValue `protobuf:"bytes,2,rep,name=aggregates,proto3" json:"aggregates,omitempty"`
+	TraceCount     uint32         `protobuf:"varint,3,opt,name=traceCount,proto3" json:"traceCount,omitempty"`
+	SpanCount      uint32         `protobuf:"varint,4,opt,name=spanCount,proto3" json:"spanCount,omitempty"`
+	SampleTraceIDs []string       `protobuf:"bytes,5,rep,name=sampleTraceIDs,proto3" json:"sampleTraceIDs,omitempty"`
+}
+
+func (m *SearchTableRow) Reset()         { *m = SearchTableRow{} }
+func (m *SearchTableRow) String() string { return proto.CompactTextString(m) }
+func (*SearchTableRow) ProtoMessage()    {}
+func (*SearchTableRow) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b334b194b16825ec, []int{7}
+}
+func (m *SearchTableRow) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SearchTableRow) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SearchTableRow.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SearchTableRow) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchTableRow.Merge(m, src)
+}
+func (m *SearchTableRow) XXX_Size() int {
+	return m.Size()
+}
+func (m *SearchTableRow) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchTableRow.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchTableRow proto.InternalMessageInfo
+
+func (m *SearchTableRow) GetGroup() []*v1.KeyValue {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
+func (m *SearchTableRow) GetAggregates() []*v1.KeyValue {
+	if m != nil {
+		return m.Aggregates
+	}
+	return nil
+}
+
+func (m *SearchTableRow) GetTraceCount() uint32 {
+	if m != nil {
+		return m.TraceCount
+	}
+	return 0
+}
+
+func (m *SearchTableRow) GetSpanCount() uint32 {
+	if m != nil {
+		return m.SpanCount
+	}
+	return 0
+}
+
+func (m *SearchTableRow) GetSampleTraceIDs() []string {
+	if m != nil {
+		return m.SampleTraceIDs
+	}
+	return nil
+}
+
 type TraceSearchMetadata struct {
 	TraceID           string                   `protobuf:"bytes,1,opt,name=traceID,proto3" json:"traceID,omitempty"`
 	RootServiceName   string                   `protobuf:"bytes,2,opt,name=rootServiceName,proto3" json:"rootServiceName,omitempty"`
@@ -737,7 +824,7 @@ func (m *TraceSearchMetadata) Reset()         { *m = TraceSearchMetadata{} }
 func (m *TraceSearchMetadata) String() string { return proto.CompactTextString(m) }
 func (*TraceSearchMetadata) ProtoMessage()    {}
 func (*TraceSearchMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{7}
+	return fileDescriptor_b334b194b16825ec, []int{8}
 }
 func (m *TraceSearchMetadata) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -831,7 +918,7 @@ func (m *ServiceStats) Reset()         { *m = ServiceStats{} }
 func (m *ServiceStats) String() string { return proto.CompactTextString(m) }
 func (*ServiceStats) ProtoMessage()    {}
 func (*ServiceStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{8}
+	return fileDescriptor_b334b194b16825ec, []int{9}
 }
 func (m *ServiceStats) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -884,7 +971,7 @@ func (m *SpanSet) Reset()         { *m = SpanSet{} }
 func (m *SpanSet) String() string { return proto.CompactTextString(m) }
 func (*SpanSet) ProtoMessage()    {}
 func (*SpanSet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{9}
+	return fileDescriptor_b334b194b16825ec, []int{10}
 }
 func (m *SpanSet) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -946,7 +1033,7 @@ func (m *Span) Reset()         { *m = Span{} }
 func (m *Span) String() string { return proto.CompactTextString(m) }
 func (*Span) ProtoMessage()    {}
 func (*Span) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{10}
+	return fileDescriptor_b334b194b16825ec, []int{11}
 }
 func (m *Span) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1018,13 +1105,15 @@ type SearchMetrics struct {
 	TotalJobs       uint32 `protobuf:"varint,5,opt,name=totalJobs,proto3" json:"totalJobs,omitempty"`
 	TotalBlockBytes uint64 `protobuf:"varint,6,opt,name=totalBlockBytes,proto3" json:"totalBlockBytes,omitempty"`
 	InspectedSpans  uint64 `protobuf:"varint,7,opt,name=inspectedSpans,proto3" json:"inspectedSpans,omitempty"`
+	// Number of linked traces of a ->link query that were not looked up because of max_link_fan_out.
+	SkippedLinkedTraces uint32 `protobuf:"varint,8,opt,name=skippedLinkedTraces,proto3" json:"skippedLinkedTraces,omitempty"`
 }
 
 func (m *SearchMetrics) Reset()         { *m = SearchMetrics{} }
 func (m *SearchMetrics) String() string { return proto.CompactTextString(m) }
 func (*SearchMetrics) ProtoMessage()    {}
 func (*SearchMetrics) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{11}
+	return fileDescriptor_b334b194b16825ec, []int{12}
 }
 func (m *SearchMetrics) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1102,6 +1191,13 @@ func (m *SearchMetrics) GetInspectedSpans() uint64 {
 	return 0
 }
 
+func (m *SearchMetrics) GetSkippedLinkedTraces() uint32 {
+	if m != nil {
+		return m.SkippedLinkedTraces
+	}
+	return 0
+}
+
 type SearchTagsRequest struct {
 	Scope                string    `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`
 	Query                string    `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
@@ -1116,7 +1212,7 @@ func (m *SearchTagsRequest) Reset()         { *m = SearchTagsRequest{} }
 func (m *SearchTagsRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsRequest) ProtoMessage()    {}
 func (*SearchTagsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{12}
+	return fileDescriptor_b334b194b16825ec, []int{13}
 }
 func (m *SearchTagsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1218,7 +1314,7 @@ func (m *SearchTagsBlockRequest) Reset()         { *m = SearchTagsBlockRequest{}
 func (m *SearchTagsBlockRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsBlockRequest) ProtoMessage()    {}
 func (*SearchTagsBlockRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{13}
+	return fileDescriptor_b334b194b16825ec, []int{14}
 }
 func (m *SearchTagsBlockRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1350,7 +1446,7 @@ func (m *SearchTagValuesBlockRequest) Reset()         { *m = SearchTagValuesBloc
 func (m *SearchTagValuesBlockRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchTagValuesBlockRequest) ProtoMessage()    {}
 func (*SearchTagValuesBlockRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{14}
+	return fileDescriptor_b334b194b16825ec, []int{15}
 }
 func (m *SearchTagValuesBlockRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1458,7 +1554,7 @@ func (m *SearchTagsResponse) Reset()         { *m = SearchTagsResponse{} }
 func (m *SearchTagsResponse) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsResponse) ProtoMessage()    {}
 func (*SearchTagsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{15}
+	return fileDescriptor_b334b194b16825ec, []int{16}
 }
 func (m *SearchTagsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1510,7 +1606,7 @@ func (m *SearchTagsV2Response) Reset()         { *m = SearchTagsV2Response{} }
 func (m *SearchTagsV2Response) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsV2Response) ProtoMessage()    {}
 func (*SearchTagsV2Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{16}
+	return fileDescriptor_b334b194b16825ec, []int{17}
 }
 func (m *SearchTagsV2Response) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1562,7 +1658,7 @@ func (m *SearchTagsV2Scope) Reset()         { *m = SearchTagsV2Scope{} }
 func (m *SearchTagsV2Scope) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsV2Scope) ProtoMessage()    {}
 func (*SearchTagsV2Scope) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{17}
+	return fileDescriptor_b334b194b16825ec, []int{18}
 }
 func (m *SearchTagsV2Scope) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1619,7 +1715,7 @@ func (m *SearchTagValuesRequest) Reset()         { *m = SearchTagValuesRequest{}
 func (m *SearchTagValuesRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchTagValuesRequest) ProtoMessage()    {}
 func (*SearchTagValuesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{18}
+	return fileDescriptor_b334b194b16825ec, []int{19}
 }
 func (m *SearchTagValuesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1707,7 +1803,7 @@ func (m *SearchTagValuesResponse) Reset()         { *m = SearchTagValuesResponse
 func (m *SearchTagValuesResponse) String() string { return proto.CompactTextString(m) }
 func (*SearchTagValuesResponse) ProtoMessage()    {}
 func (*SearchTagValuesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{19}
+	return fileDescriptor_b334b194b16825ec, []int{20}
 }
 func (m *SearchTagValuesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1759,7 +1855,7 @@ func (m *TagValue) Reset()         { *m = TagValue{} }
 func (m *TagValue) String() string { return proto.CompactTextString(m) }
 func (*TagValue) ProtoMessage()    {}
 func (*TagValue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{20}
+	return fileDescriptor_b334b194b16825ec, []int{21}
 }
 func (m *TagValue) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1811,7 +1907,7 @@ func (m *SearchTagValuesV2Response) Reset()         { *m = SearchTagValuesV2Resp
 func (m *SearchTagValuesV2Response) String() string { return proto.CompactTextString(m) }
 func (*SearchTagValuesV2Response) ProtoMessage()    {}
 func (*SearchTagValuesV2Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{21}
+	return fileDescriptor_b334b194b16825ec, []int{22}
 }
 func (m *SearchTagValuesV2Response) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1866,7 +1962,7 @@ func (m *MetadataMetrics) Reset()         { *m = MetadataMetrics{} }
 func (m *MetadataMetrics) String() string { return proto.CompactTextString(m) }
 func (*MetadataMetrics) ProtoMessage()    {}
 func (*MetadataMetrics) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{22}
+	return fileDescriptor_b334b194b16825ec, []int{23}
 }
 func (m *MetadataMetrics) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1938,7 +2034,7 @@ func (m *Trace) Reset()         { *m = Trace{} }
 func (m *Trace) String() string { return proto.CompactTextString(m) }
 func (*Trace) ProtoMessage()    {}
 func (*Trace) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{23}
+	return fileDescriptor_b334b194b16825ec, []int{24}
 }
 func (m *Trace) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1983,7 +2079,7 @@ func (m *PushResponse) Reset()         { *m = PushResponse{} }
 func (m *PushResponse) String() string { return proto.CompactTextString(m) }
 func (*PushResponse) ProtoMessage()    {}
 func (*PushResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{24}
+	return fileDescriptor_b334b194b16825ec, []int{25}
 }
 func (m *PushResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2037,7 +2133,7 @@ func (m *PushBytesRequest) Reset()         { *m = PushBytesRequest{} }
 func (m *PushBytesRequest) String() string { return proto.CompactTextString(m) }
 func (*PushBytesRequest) ProtoMessage()    {}
 func (*PushBytesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{25}
+	return fileDescriptor_b334b194b16825ec, []int{26}
 }
 func (m *PushBytesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2092,7 +2188,7 @@ func (m *PushSpansRequest) Reset()         { *m = PushSpansRequest{} }
 func (m *PushSpansRequest) String() string { return proto.CompactTextString(m) }
 func (*PushSpansRequest) ProtoMessage()    {}
 func (*PushSpansRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{26}
+	return fileDescriptor_b334b194b16825ec, []int{27}
 }
 func (m *PushSpansRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2144,7 +2240,7 @@ func (m *TraceBytes) Reset()         { *m = TraceBytes{} }
 func (m *TraceBytes) String() string { return proto.CompactTextString(m) }
 func (*TraceBytes) ProtoMessage()    {}
 func (*TraceBytes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{27}
+	return fileDescriptor_b334b194b16825ec, []int{28}
 }
 func (m *TraceBytes) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2192,7 +2288,7 @@ func (m *LinkSlice) Reset()         { *m = LinkSlice{} }
 func (m *LinkSlice) String() string { return proto.CompactTextString(m) }
 func (*LinkSlice) ProtoMessage()    {}
 func (*LinkSlice) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{28}
+	return fileDescriptor_b334b194b16825ec, []int{29}
 }
 func (m *LinkSlice) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2238,7 +2334,7 @@ func (m *QueryInstantRequest) Reset()         { *m = QueryInstantRequest{} }
 func (m *QueryInstantRequest) String() string { return proto.CompactTextString(m) }
 func (*QueryInstantRequest) ProtoMessage()    {}
 func (*QueryInstantRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{29}
+	return fileDescriptor_b334b194b16825ec, []int{30}
 }
 func (m *QueryInstantRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2299,7 +2395,7 @@ func (m *QueryInstantResponse) Reset()         { *m = QueryInstantResponse{} }
 func (m *QueryInstantResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryInstantResponse) ProtoMessage()    {}
 func (*QueryInstantResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{30}
+	return fileDescriptor_b334b194b16825ec, []int{31}
 }
 func (m *QueryInstantResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2366,7 +2462,7 @@ func (m *InstantSeries) Reset()         { *m = InstantSeries{} }
 func (m *InstantSeries) String() string { return proto.CompactTextString(m) }
 func (*InstantSeries) ProtoMessage()    {}
 func (*InstantSeries) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{31}
+	return fileDescriptor_b334b194b16825ec, []int{32}
 }
 func (m *InstantSeries) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2439,7 +2535,7 @@ func (m *QueryRangeRequest) Reset()         { *m = QueryRangeRequest{} }
 func (m *QueryRangeRequest) String() string { return proto.CompactTextString(m) }
 func (*QueryRangeRequest) ProtoMessage()    {}
 func (*QueryRangeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{32}
+	return fileDescriptor_b334b194b16825ec, []int{33}
 }
 func (m *QueryRangeRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2610,7 +2706,7 @@ func (m *QueryRangeResponse) Reset()         { *m = QueryRangeResponse{} }
 func (m *QueryRangeResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryRangeResponse) ProtoMessage()    {}
 func (*QueryRangeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{33}
+	return fileDescriptor_b334b194b16825ec, []int{34}
 }
 func (m *QueryRangeResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2678,7 +2774,7 @@ func (m *Exemplar) Reset()         { *m = Exemplar{} }
 func (m *Exemplar) String() string { return proto.CompactTextString(m) }
 func (*Exemplar) ProtoMessage()    {}
 func (*Exemplar) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{34}
+	return fileDescriptor_b334b194b16825ec, []int{35}
 }
 func (m *Exemplar) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2738,7 +2834,7 @@ func (m *Sample) Reset()         { *m = Sample{} }
 func (m *Sample) String() string { return proto.CompactTextString(m) }
 func (*Sample) ProtoMessage()    {}
 func (*Sample) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{35}
+	return fileDescriptor_b334b194b16825ec, []int{36}
 }
 func (m *Sample) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2795,7 +2891,7 @@ func (m *TimeSeries) Reset()         { *m = TimeSeries{} }
 func (m *TimeSeries) String() string { return proto.CompactTextString(m) }
 func (*TimeSeries) ProtoMessage()    {}
 func (*TimeSeries) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b334b194b16825ec, []int{36}
+	return fileDescriptor_b334b194b16825ec, []int{37}
 }
 func (m *TimeSeries) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2859,6 +2955,7 @@ func init() {
 	proto.RegisterType((*SearchBlockRequest)(nil), "tempopb.SearchBlockRequest")
 	proto.RegisterType((*DedicatedColumn)(nil), "tempopb.DedicatedColumn")
 	proto.RegisterType((*SearchResponse)(nil), "tempopb.SearchResponse")
+	proto.RegisterType((*SearchTableRow)(nil), "tempopb.SearchTableRow")
 	proto.RegisterType((*TraceSearchMetadata)(nil), "tempopb.TraceSearchMetadata")
 	proto.RegisterMapType((map[string]*ServiceStats)(nil), "tempopb.TraceSearchMetadata.ServiceStatsEntry")
 	proto.RegisterType((*ServiceStats)(nil), "tempopb.ServiceStats")
@@ -2895,168 +2992,173 @@ func init() {
 func init() { proto.RegisterFile("tempo.proto", fileDescriptor_b334b194b16825ec) }
 
 var fileDescriptor_b334b194b16825ec = []byte{
-	// 2561 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0xdd, 0x6f, 0x23, 0x57,
-	0x15, 0xcf, 0xf8, 0xdb, 0xc7, 0x76, 0x3c, 0xb9, 0xc9, 0xa6, 0xae, 0xb3, 0x4d, 0xc2, 0xb0, 0x82,
-	0x68, 0xdb, 0x3a, 0x89, 0xbb, 0x88, 0x76, 0x57, 0x14, 0xd9, 0x1b, 0x77, 0x9b, 0x6e, 0xe2, 0x84,
-	0x6b, 0x6f, 0x28, 0xa8, 0x52, 0x34, 0xb1, 0xef, 0x7a, 0x47, 0xb1, 0x67, 0xdc, 0x99, 0x71, 0x48,
-	0x78, 0xa8, 0x90, 0x10, 0x88, 0x22, 0x1e, 0x2a, 0x9e, 0xe0, 0x2f, 0xe0, 0x5f, 0x40, 0x42, 0xbc,
-	0xc0, 0x4b, 0x11, 0x2f, 0x95, 0x78, 0x41, 0x08, 0x15, 0xb4, 0xfb, 0xc6, 0x5f, 0xc0, 0x23, 0xba,
-	0x5f, 0xf3, 0xe5, 0x71, 0xf6, 0xa3, 0xa9, 0xc4, 0x43, 0x9f, 0x3c, 0xf7, 0xdc, 0xdf, 0x3d, 0x73,
-	0xee, 0x39, 0xe7, 0x9e, 0xf3, 0xbb, 0x63, 0x28, 0xb8, 0x64, 0x34, 0xb6, 0x6a, 0x63, 0xdb, 0x72,
-	0x2d, 0x94, 0x65, 0x83, 0xf1, 0x49, 0x75, 0xb9, 0x67, 0x8d, 0x46, 0x96, 0xb9, 0x79, 0xb6, 0xbd,
-	0xc9, 0x9f, 0x38, 0xa0, 0xfa, 0xfa, 0xc0, 0x70, 0x1f, 0x4d, 0x4e, 0x6a, 0x3d, 0x6b, 0xb4, 0x39,
-	0xb0, 0x06, 0xd6, 0x26, 0x13, 0x9f, 0x4c, 0x1e, 0xb2, 0x11, 0x1b, 0xb0, 0x27, 0x01, 0x5f, 0x72,
-	0x6d, 0xbd, 0x47, 0xa8, 0x16, 0xf6, 0x20, 0xa4, 0x6b, 0x03, 0xcb, 0x1a, 0x0c, 0x89, 0xbf, 0xd6,
-	0x35, 0x46, 0xc4, 0x71, 0xf5, 0xd1, 0x98, 0x03, 0xb4, 0xff, 0x2a, 0xa0, 0x76, 0xe9, 0x82, 0xe6,
-	0xc5, 0xee, 0x0e, 0x26, 0x1f, 0x4e, 0x88, 0xe3, 0xa2, 0x0a, 0x64, 0x99, 0x92, 0xdd, 0x9d, 0x8a,
-	0xb2, 0xae, 0x6c, 0x14, 0xb1, 0x1c, 0xa2, 0x55, 0x80, 0x93, 0xa1, 0xd5, 0x3b, 0xed, 0xb8, 0xba,
-	0xed, 0x56, 0x12, 0xeb, 0xca, 0x46, 0x1e, 0x07, 0x24, 0xa8, 0x0a, 0x39, 0x36, 0x6a, 0x99, 0xfd,
-	0x4a, 0x92, 0xcd, 0x7a, 0x63, 0x74, 0x1d, 0xf2, 0x1f, 0x4e, 0x88, 0x7d, 0xb1, 0x6f, 0xf5, 0x49,
-	0x25, 0xcd, 0x26, 0x7d, 0x01, 0x7a, 0x0d, 0x16, 0xf4, 0xe1, 0xd0, 0xfa, 0xd1, 0xa1, 0x6e, 0xbb,
-	0x86, 0x3e, 0x64, 0x36, 0x55, 0x32, 0xeb, 0xca, 0x46, 0x0e, 0x4f, 0x4f, 0xa0, 0x26, 0xe4, 0xf0,
-	0x3b, 0xdb, 0x8d, 0x87, 0x2e, 0xb1, 0x2b, 0xd9, 0x75, 0x65, 0xa3, 0x50, 0xaf, 0xd6, 0xf8, 0x56,
-	0x6b, 0x72, 0xab, 0xb5, 0xae, 0xdc, 0x6a, 0x13, 0x3e, 0xfd, 0x7c, 0x6d, 0xee, 0x93, 0x7f, 0xad,
-	0x29, 0x15, 0x05, 0x7b, 0xeb, 0xb4, 0xdf, 0x2b, 0xb0, 0x10, 0xd8, 0xba, 0x33, 0xb6, 0x4c, 0x87,
-	0xa0, 0x1b, 0x90, 0x66, 0x9b, 0x65, 0x3b, 0x2f, 0xd4, 0xe7, 0x6b, 0x22, 0x4e, 0x35, 0x06, 0xc5,
-	0x7c, 0x12, 0xbd, 0x01, 0xd9, 0x11, 0x71, 0x6d, 0xa3, 0xe7, 0x30, 0x27, 0x14, 0xea, 0x2f, 0x87,
-	0x71, 0x54, 0xe5, 0x3e, 0x07, 0x60, 0x89, 0x44, 0x35, 0xc8, 0x38, 0xae, 0xee, 0x4e, 0x1c, 0xe6,
-	0x9a, 0xf9, 0xfa, 0xb2, 0xb7, 0x46, 0xec, 0xad, 0xc3, 0x66, 0xb1, 0x40, 0xd1, 0x30, 0x8c, 0x88,
-	0xe3, 0xe8, 0x03, 0x52, 0x49, 0x31, 0x77, 0xc9, 0xa1, 0x76, 0x3b, 0x10, 0x34, 0xf1, 0x1a, 0xf4,
-	0x0d, 0x98, 0x37, 0x4c, 0x67, 0x4c, 0x7a, 0x2e, 0xe9, 0x37, 0x2f, 0x5c, 0xe2, 0xb0, 0x1d, 0xa4,
-	0x70, 0x44, 0xaa, 0xfd, 0x3a, 0x09, 0xa5, 0x0e, 0xd1, 0xed, 0xde, 0x23, 0x19, 0xee, 0xdb, 0x90,
-	0xea, 0xea, 0x03, 0x8a, 0x4f, 0x6e, 0x14, 0xea, 0xeb, 0x9e, 0x55, 0x21, 0x54, 0x8d, 0x42, 0x5a,
-	0xa6, 0x6b, 0x5f, 0x34, 0x53, 0xd4, 0x9d, 0x98, 0xad, 0x41, 0x37, 0xa0, 0xb4, 0x6f, 0x98, 0x3b,
-	0x13, 0x5b, 0x77, 0x0d, 0xcb, 0xdc, 0xe7, 0xee, 0x28, 0xe1, 0xb0, 0x90, 0xa1, 0xf4, 0xf3, 0x00,
-	0x2a, 0x29, 0x50, 0x41, 0x21, 0x5a, 0x82, 0xf4, 0x9e, 0x31, 0x32, 0x5c, 0xb6, 0xdb, 0x12, 0xe6,
-	0x03, 0x2a, 0x75, 0x58, 0xb6, 0xa5, 0xb9, 0x94, 0x0d, 0x90, 0x0a, 0x49, 0x62, 0xf6, 0x59, 0x82,
-	0x94, 0x30, 0x7d, 0xa4, 0xb8, 0xef, 0xd1, 0x6c, 0xaa, 0xe4, 0x98, 0xaf, 0xf8, 0x00, 0x6d, 0x40,
-	0xb9, 0x33, 0xd6, 0x4d, 0xe7, 0x90, 0xd8, 0xf4, 0xb7, 0x43, 0xdc, 0x4a, 0x9e, 0xad, 0x89, 0x8a,
-	0x43, 0x29, 0x05, 0x2f, 0x96, 0x52, 0xd5, 0x6f, 0x43, 0xde, 0x73, 0x13, 0x35, 0xf1, 0x94, 0x5c,
-	0xb0, 0x28, 0xe4, 0x31, 0x7d, 0xa4, 0x26, 0x9e, 0xe9, 0xc3, 0x09, 0x11, 0x07, 0x87, 0x0f, 0x6e,
-	0x27, 0xde, 0x54, 0xb4, 0x9f, 0x27, 0x01, 0x71, 0x77, 0x37, 0xe9, 0x71, 0x91, 0x91, 0xb9, 0x05,
-	0x79, 0x47, 0x06, 0x41, 0x24, 0xe4, 0x72, 0x7c, 0x78, 0xb0, 0x0f, 0xa4, 0x79, 0xc3, 0x0e, 0xdd,
-	0xee, 0x8e, 0x78, 0x91, 0x1c, 0xd2, 0x23, 0xc8, 0xdc, 0x77, 0x48, 0x73, 0x8a, 0xc7, 0xc0, 0x17,
-	0xd0, 0x28, 0x8d, 0xf5, 0x01, 0x71, 0xba, 0x16, 0x57, 0x2d, 0xe2, 0x10, 0x16, 0x52, 0x94, 0x61,
-	0xf6, 0xc9, 0x39, 0x5d, 0xd2, 0x31, 0x7e, 0x4c, 0x44, 0x0c, 0xc2, 0x42, 0xa4, 0x41, 0xd1, 0xb5,
-	0x5c, 0x7d, 0x88, 0x49, 0xcf, 0xb2, 0xfb, 0x0e, 0x3b, 0xa4, 0x25, 0x1c, 0x92, 0x51, 0x3b, 0xcf,
-	0x88, 0xed, 0x18, 0x96, 0xc9, 0x62, 0x92, 0xc7, 0x72, 0x88, 0x10, 0xa4, 0x1c, 0xaa, 0x1a, 0x58,
-	0x06, 0xb3, 0x67, 0x5a, 0x7a, 0x1e, 0x5a, 0x96, 0x4b, 0x6c, 0xf6, 0xd2, 0x02, 0xd3, 0x17, 0x90,
-	0xa0, 0x1d, 0x50, 0xfb, 0xa4, 0x6f, 0xf4, 0x74, 0x97, 0xf4, 0xef, 0x5a, 0xc3, 0xc9, 0xc8, 0x74,
-	0x2a, 0x45, 0x96, 0xd1, 0x15, 0xcf, 0x65, 0x3b, 0x61, 0x00, 0x9e, 0x5a, 0xa1, 0xfd, 0x21, 0x01,
-	0xe5, 0x08, 0x0a, 0xdd, 0x82, 0xb4, 0xd3, 0xb3, 0xc6, 0x44, 0x1c, 0xdb, 0xd5, 0x59, 0xea, 0x6a,
-	0x1d, 0x8a, 0xc2, 0x1c, 0x4c, 0xf7, 0x60, 0xea, 0x23, 0x19, 0x6b, 0xf6, 0x8c, 0xb6, 0x21, 0xe5,
-	0x5e, 0x8c, 0x79, 0x6d, 0x99, 0xaf, 0xbf, 0x32, 0x53, 0x51, 0xf7, 0x62, 0x4c, 0x30, 0x83, 0xa2,
-	0xb7, 0x20, 0x6b, 0x8d, 0xe9, 0x01, 0x71, 0x58, 0x38, 0xe6, 0xeb, 0x6b, 0x33, 0x57, 0x1d, 0x30,
-	0x1c, 0x96, 0x78, 0xed, 0x26, 0xa4, 0x99, 0x45, 0x28, 0x07, 0xa9, 0xce, 0x61, 0xa3, 0xad, 0xce,
-	0xa1, 0x22, 0xe4, 0x70, 0xab, 0x73, 0xf0, 0x00, 0xdf, 0x6d, 0xa9, 0x0a, 0xca, 0x43, 0xba, 0x75,
-	0xd4, 0x6a, 0x77, 0xd5, 0x84, 0xb6, 0x02, 0x29, 0xfa, 0x52, 0x04, 0x90, 0xe9, 0x74, 0xf1, 0x6e,
-	0xfb, 0x9e, 0x3a, 0x87, 0xb2, 0x90, 0xdc, 0x6d, 0x77, 0x55, 0x45, 0xfb, 0x26, 0x64, 0xb8, 0x6e,
-	0xaa, 0xa9, 0x7d, 0xd0, 0x6e, 0xa9, 0x73, 0x74, 0x6d, 0x03, 0xe3, 0xc6, 0x0f, 0x54, 0x85, 0x0a,
-	0x9b, 0x7b, 0x07, 0x4d, 0x35, 0xa1, 0x9d, 0xc3, 0xbc, 0xcc, 0x4a, 0x51, 0x4e, 0x6f, 0x41, 0x86,
-	0x55, 0x4c, 0x59, 0x5d, 0xae, 0x87, 0xeb, 0x24, 0x47, 0xef, 0x13, 0x57, 0xef, 0xeb, 0xae, 0x8e,
-	0x05, 0x16, 0x6d, 0x45, 0xcb, 0x6b, 0x34, 0xeb, 0xa3, 0xb5, 0x55, 0xfb, 0x5b, 0x12, 0x16, 0x63,
-	0x34, 0x46, 0x5b, 0x59, 0xde, 0x6f, 0x65, 0x1b, 0x50, 0xb6, 0x2d, 0xcb, 0xed, 0x10, 0xfb, 0xcc,
-	0xe8, 0x91, 0xb6, 0x1f, 0xaa, 0xa8, 0x98, 0x66, 0x3c, 0x15, 0x31, 0xf5, 0x0c, 0xc7, 0x3b, 0x5b,
-	0x58, 0x48, 0x1b, 0x18, 0x3b, 0x4a, 0xb4, 0x46, 0x3c, 0x30, 0x8d, 0xf3, 0xb6, 0x6e, 0x5a, 0x2c,
-	0x64, 0x29, 0x3c, 0x3d, 0x41, 0xb3, 0xb9, 0xef, 0x97, 0x43, 0x5e, 0xda, 0x02, 0x12, 0x74, 0x13,
-	0xb2, 0x8e, 0xa8, 0x57, 0x19, 0xe6, 0x01, 0xd5, 0xf7, 0x00, 0x97, 0x63, 0x09, 0x40, 0xaf, 0x41,
-	0x4e, 0x3c, 0xd2, 0x73, 0x96, 0x8c, 0x05, 0x7b, 0x08, 0x84, 0xa1, 0xe8, 0xf0, 0xcd, 0xd1, 0x76,
-	0xe3, 0x54, 0x72, 0x6c, 0x45, 0xed, 0xb2, 0xb8, 0xd4, 0x3a, 0x81, 0x05, 0xac, 0xb8, 0xe1, 0x90,
-	0x8e, 0xea, 0x11, 0x2c, 0x4c, 0x41, 0x62, 0xea, 0xdf, 0xab, 0xc1, 0xfa, 0x57, 0xa8, 0x5f, 0x0b,
-	0x04, 0xd5, 0x5f, 0x1c, 0x2c, 0x8b, 0x7b, 0x50, 0x0c, 0x4e, 0xb1, 0xfa, 0x35, 0xd6, 0xcd, 0xbb,
-	0xd6, 0xc4, 0x74, 0x99, 0x62, 0x5a, 0xbf, 0xa4, 0x80, 0xfa, 0x94, 0xd8, 0xb6, 0x65, 0xf3, 0x69,
-	0xde, 0x88, 0x02, 0x12, 0xed, 0x67, 0x0a, 0x64, 0x65, 0xb5, 0xff, 0x3a, 0xa4, 0xe9, 0x42, 0x99,
-	0x96, 0xa5, 0x90, 0xc3, 0x30, 0x9f, 0x63, 0x0d, 0x58, 0x77, 0x7b, 0x8f, 0x48, 0x5f, 0x68, 0x93,
-	0x43, 0x74, 0x07, 0x40, 0x77, 0x5d, 0xdb, 0x38, 0x99, 0xd0, 0x46, 0x9b, 0x64, 0x3a, 0x56, 0x3c,
-	0x1d, 0x82, 0xc7, 0x9d, 0x6d, 0xd7, 0xee, 0x93, 0x8b, 0x23, 0xba, 0x1b, 0x1c, 0x80, 0x6b, 0x7f,
-	0x56, 0x20, 0x45, 0x5f, 0x83, 0x96, 0x21, 0x43, 0x5f, 0xe4, 0xe5, 0xa6, 0x18, 0xc5, 0x96, 0x8e,
-	0xd8, 0xf4, 0x4a, 0xce, 0x4a, 0xaf, 0x1b, 0x50, 0x92, 0xc9, 0x44, 0xc7, 0x8e, 0x48, 0xc4, 0xb0,
-	0x30, 0xb2, 0x8b, 0xf4, 0xf3, 0xed, 0xe2, 0xb7, 0x09, 0xc9, 0x23, 0x24, 0x03, 0xd9, 0x80, 0xb2,
-	0xc7, 0x35, 0xba, 0xf2, 0xd0, 0xb3, 0x5e, 0x1b, 0x11, 0xc7, 0x70, 0x95, 0x44, 0x1c, 0x57, 0x41,
-	0xeb, 0x50, 0x60, 0x1d, 0x83, 0x35, 0x45, 0xc9, 0x1a, 0x82, 0x22, 0xba, 0xd1, 0x9e, 0x35, 0x1a,
-	0x0f, 0x89, 0x4b, 0xfa, 0xef, 0x59, 0x27, 0x8e, 0xec, 0x59, 0x21, 0x21, 0xcd, 0x1b, 0xb6, 0x88,
-	0x21, 0xf8, 0x61, 0xf3, 0x05, 0xd4, 0x6e, 0x5f, 0x25, 0x37, 0x27, 0xc3, 0xcc, 0x89, 0x8a, 0x43,
-	0x76, 0x33, 0xfe, 0xc0, 0xfa, 0x5a, 0xd0, 0x6e, 0x26, 0xd5, 0x3e, 0x4e, 0xd0, 0x03, 0x41, 0x7d,
-	0x43, 0xe9, 0x80, 0xec, 0xe6, 0x4b, 0xb2, 0x8f, 0xf0, 0x68, 0x8b, 0x3e, 0xb1, 0x04, 0x69, 0xc6,
-	0x82, 0x25, 0x29, 0x60, 0x03, 0x9f, 0xf5, 0x24, 0x63, 0x58, 0x4f, 0xca, 0x67, 0x3d, 0x1b, 0x50,
-	0x1e, 0xe9, 0xe7, 0xf4, 0x2d, 0x94, 0xca, 0x30, 0xed, 0x7c, 0x7f, 0x51, 0x31, 0xaa, 0xc3, 0x92,
-	0xe3, 0xea, 0x43, 0xc2, 0x22, 0xe9, 0x74, 0x1f, 0xd9, 0xc4, 0x79, 0x64, 0x0d, 0x25, 0x85, 0x8a,
-	0x9d, 0xbb, 0x12, 0x9a, 0xfd, 0x9f, 0x24, 0x2c, 0xfb, 0xbe, 0x08, 0xd1, 0x9b, 0x37, 0xa7, 0xe9,
-	0x4d, 0x35, 0x52, 0xe8, 0x03, 0xfe, 0xfb, 0x8a, 0xe2, 0x5c, 0x09, 0xc5, 0x89, 0x4b, 0x99, 0x52,
-	0x7c, 0xca, 0x6c, 0xc1, 0xa2, 0x9f, 0x16, 0x7e, 0xc6, 0xcc, 0x33, 0x74, 0xdc, 0x94, 0xf6, 0x9b,
-	0x24, 0xac, 0x78, 0x81, 0xe3, 0xd9, 0x14, 0x8a, 0xf8, 0x77, 0xa6, 0x23, 0xbe, 0x36, 0x1d, 0x71,
-	0xbe, 0xf0, 0xab, 0xb0, 0x5f, 0x29, 0xb3, 0xed, 0xcb, 0x1b, 0x06, 0x3f, 0x52, 0x82, 0x9f, 0x55,
-	0x21, 0xe7, 0xea, 0x03, 0x4a, 0x60, 0x78, 0x2b, 0xcc, 0x63, 0x6f, 0x8c, 0xea, 0x51, 0x16, 0xe6,
-	0xbf, 0x4e, 0x32, 0x83, 0x29, 0x1e, 0xf6, 0x11, 0x2c, 0xf9, 0x6f, 0x39, 0xaa, 0x7b, 0xef, 0xa9,
-	0x43, 0x86, 0x95, 0x3b, 0xd9, 0x70, 0xe3, 0xce, 0xf9, 0x51, 0x9d, 0x13, 0x68, 0x81, 0x7c, 0xa1,
-	0xf7, 0xdf, 0x09, 0x16, 0x5e, 0xa1, 0xd0, 0xeb, 0xa7, 0x4a, 0xa0, 0x9f, 0x22, 0x48, 0xb9, 0xf4,
-	0xd2, 0x9b, 0x60, 0x9b, 0x66, 0xcf, 0xda, 0x2f, 0x13, 0x81, 0x52, 0x15, 0x4a, 0x42, 0xc6, 0x23,
-	0xb9, 0x5f, 0x3c, 0x1e, 0xc9, 0x87, 0x4f, 0xab, 0xdf, 0xa9, 0x98, 0xfa, 0x9d, 0xf6, 0xeb, 0xb7,
-	0x06, 0x45, 0x7e, 0xea, 0xf8, 0xeb, 0x44, 0xca, 0x85, 0x64, 0xb3, 0x8e, 0x61, 0x76, 0xe6, 0x31,
-	0x0c, 0xd5, 0xed, 0xdc, 0x0b, 0xd6, 0xed, 0x53, 0x78, 0x69, 0xca, 0x17, 0x22, 0x98, 0xb4, 0x9d,
-	0x7a, 0x16, 0xf3, 0xac, 0xf1, 0x05, 0x2f, 0x14, 0xb6, 0x5b, 0x90, 0x93, 0xaf, 0x61, 0x91, 0xb9,
-	0xf0, 0xba, 0x24, 0xbf, 0x05, 0xc5, 0xde, 0x9c, 0xb5, 0x9f, 0x28, 0xf0, 0x72, 0xc4, 0xc6, 0x40,
-	0xca, 0x6d, 0x46, 0xad, 0x2c, 0xd4, 0x17, 0x7c, 0x96, 0x2b, 0x66, 0xbe, 0xa8, 0xe1, 0x7f, 0x51,
-	0xa0, 0x1c, 0x99, 0x7c, 0xd6, 0x2f, 0x31, 0x61, 0x56, 0x92, 0x88, 0xb2, 0x92, 0x29, 0x66, 0x93,
-	0x8c, 0x63, 0x36, 0x11, 0x86, 0x94, 0x9a, 0x66, 0x48, 0x31, 0xec, 0x26, 0x1d, 0xcb, 0x6e, 0xb4,
-	0x36, 0xa4, 0xf9, 0xd7, 0xb5, 0x16, 0x94, 0x6c, 0xe2, 0x58, 0x13, 0xbb, 0x47, 0x3a, 0x01, 0x92,
-	0xec, 0x57, 0x6a, 0xfe, 0x89, 0xf1, 0x6c, 0xbb, 0x86, 0x83, 0x30, 0x1c, 0x5e, 0xa5, 0xb5, 0xa1,
-	0x78, 0x38, 0x71, 0xfc, 0xbb, 0xe0, 0xdb, 0x50, 0x62, 0x6c, 0xdc, 0x69, 0x5e, 0x74, 0xc5, 0x27,
-	0xb6, 0xe4, 0xc6, 0x7c, 0xc0, 0xcb, 0x14, 0xdd, 0xa2, 0x08, 0x4c, 0x74, 0xc7, 0x32, 0x71, 0x18,
-	0xae, 0x7d, 0xac, 0x80, 0x4a, 0x21, 0xcc, 0x5a, 0x79, 0x30, 0x5f, 0xf7, 0x2e, 0x98, 0xf4, 0x24,
-	0x17, 0x9b, 0xd7, 0x68, 0x32, 0xff, 0xe3, 0xf3, 0xb5, 0xd2, 0xa1, 0x4d, 0xf4, 0xe1, 0xd0, 0xea,
-	0x71, 0xb4, 0xbc, 0x59, 0xaa, 0x90, 0x34, 0xfa, 0x9c, 0xb1, 0x17, 0x31, 0x7d, 0x44, 0xb7, 0xe0,
-	0x9a, 0x73, 0x6a, 0x8c, 0x45, 0xf0, 0xee, 0x11, 0x93, 0x70, 0x8a, 0xcc, 0xbc, 0x94, 0xc3, 0xf1,
-	0x93, 0xda, 0x4f, 0x85, 0x2d, 0x7c, 0xe3, 0xc2, 0x96, 0xb7, 0x20, 0x7b, 0xc2, 0x2e, 0x08, 0xcf,
-	0xec, 0x31, 0x89, 0x9f, 0x6d, 0x45, 0xe2, 0x32, 0x2b, 0x6e, 0x00, 0x88, 0xef, 0x80, 0x34, 0x9f,
-	0x96, 0x43, 0x77, 0xed, 0xa2, 0xdc, 0xb3, 0xf6, 0x36, 0xe4, 0xf7, 0x0c, 0xf3, 0xb4, 0x33, 0x34,
-	0x7a, 0x04, 0x6d, 0x43, 0x7a, 0x68, 0x98, 0xa7, 0xd2, 0xc2, 0x95, 0x69, 0x0b, 0xa9, 0x65, 0x35,
-	0xba, 0x00, 0x73, 0xa4, 0xd6, 0x81, 0x45, 0xf6, 0x31, 0x6d, 0xd7, 0x74, 0x5c, 0xdd, 0x74, 0x03,
-	0x74, 0x96, 0x17, 0x3e, 0x25, 0xb6, 0xf0, 0x71, 0x46, 0x1f, 0x2e, 0x7c, 0xfc, 0xbe, 0x42, 0x1f,
-	0xb5, 0x3f, 0x29, 0xb0, 0x14, 0xd6, 0x2a, 0xb2, 0xa4, 0x06, 0x19, 0x87, 0xd8, 0x86, 0xe7, 0x43,
-	0xff, 0xea, 0x2f, 0x90, 0x1d, 0x36, 0x8b, 0x05, 0xea, 0xf9, 0xbf, 0x15, 0x5c, 0xe1, 0x77, 0x58,
-	0x07, 0x4a, 0x21, 0xa3, 0xd0, 0x5b, 0x90, 0x19, 0xea, 0x27, 0x64, 0x38, 0xed, 0xde, 0xe9, 0xdb,
-	0x94, 0xf8, 0x8e, 0x2a, 0x16, 0x84, 0x4b, 0x9c, 0x22, 0x4a, 0xdc, 0x7b, 0xa9, 0x5c, 0x52, 0x4d,
-	0xe1, 0xc2, 0xd8, 0xb6, 0x46, 0xc7, 0x1c, 0xa8, 0xfd, 0x33, 0x09, 0x0b, 0xcc, 0x73, 0x58, 0x37,
-	0x07, 0xe4, 0x4a, 0xa2, 0xc1, 0x68, 0x89, 0x4b, 0xc6, 0xe2, 0x9a, 0xc8, 0x9e, 0xc3, 0xdf, 0xeb,
-	0xb3, 0xd1, 0xef, 0xf5, 0x01, 0x2a, 0x96, 0xbb, 0x84, 0x8a, 0xe5, 0x9f, 0x4a, 0xc5, 0x20, 0x8e,
-	0x8a, 0x05, 0x08, 0x54, 0x21, 0x9e, 0x40, 0x95, 0x66, 0x12, 0xa8, 0xf9, 0x67, 0x22, 0x50, 0xe5,
-	0xe7, 0xe6, 0xcd, 0xd7, 0x21, 0x4f, 0xce, 0xc9, 0x68, 0x3c, 0xd4, 0x6d, 0xa7, 0xa2, 0xf2, 0x7d,
-	0x79, 0x02, 0x3a, 0x3b, 0xd2, 0xcf, 0x79, 0x1a, 0x54, 0x16, 0xf8, 0xac, 0x27, 0x40, 0xaf, 0x40,
-	0xd6, 0xe0, 0x89, 0x52, 0x41, 0xf4, 0x40, 0xbf, 0x3b, 0x87, 0xa5, 0xe0, 0x17, 0x8a, 0xd2, 0x04,
-	0xc8, 0x1d, 0x8b, 0xa1, 0xf6, 0x47, 0x05, 0x50, 0x30, 0xbc, 0xe2, 0x58, 0xbc, 0x1a, 0x39, 0x16,
-	0x8b, 0x7e, 0x2b, 0x33, 0x46, 0xe4, 0xff, 0xe8, 0x4c, 0x7c, 0x04, 0xb9, 0x96, 0xf0, 0xca, 0x95,
-	0x1f, 0x07, 0xf4, 0x35, 0x28, 0x7a, 0xff, 0x60, 0x1d, 0x8f, 0xb8, 0xb1, 0x49, 0x5c, 0xf0, 0x64,
-	0xfb, 0x8e, 0xd6, 0x80, 0x4c, 0x47, 0xa7, 0x2d, 0x72, 0x0a, 0x9c, 0x98, 0x02, 0xfb, 0x6f, 0x51,
-	0x02, 0x6f, 0xa1, 0xb5, 0x09, 0x7c, 0xaf, 0x7e, 0x91, 0x5d, 0x6c, 0x42, 0xd6, 0x61, 0xc6, 0xf0,
-	0xf6, 0x54, 0xa8, 0x97, 0xfd, 0x40, 0x30, 0xb9, 0xc0, 0x4b, 0x14, 0xfa, 0x56, 0x30, 0xc9, 0x52,
-	0x11, 0xd2, 0x22, 0xfd, 0x2a, 0x16, 0xf9, 0xc8, 0x98, 0x32, 0x71, 0xf3, 0x03, 0x28, 0x47, 0xfa,
-	0x29, 0x2a, 0x42, 0xae, 0x7d, 0x70, 0xdc, 0xc2, 0xf8, 0x00, 0xab, 0x73, 0x68, 0x11, 0xca, 0xfb,
-	0x8d, 0xf7, 0x8f, 0xf7, 0x76, 0x8f, 0x5a, 0xc7, 0x5d, 0xdc, 0xb8, 0xdb, 0xea, 0xa8, 0x0a, 0x15,
-	0xb2, 0xe7, 0xe3, 0xee, 0xc1, 0xc1, 0xf1, 0x5e, 0x03, 0xdf, 0x6b, 0xa9, 0x09, 0xb4, 0x00, 0xa5,
-	0x07, 0xed, 0xfb, 0xed, 0x83, 0xef, 0xb7, 0xc5, 0xe2, 0xe4, 0xcd, 0x9b, 0x50, 0x0a, 0x25, 0x06,
-	0xd5, 0x7d, 0xf7, 0x60, 0xff, 0x70, 0xaf, 0xd5, 0x6d, 0xa9, 0x73, 0xa8, 0x00, 0xd9, 0xc3, 0x06,
-	0xee, 0xee, 0x36, 0xf6, 0x54, 0xa5, 0xfe, 0x2b, 0x05, 0x32, 0xd4, 0x14, 0x62, 0xa3, 0xef, 0x42,
-	0xde, 0xeb, 0xe0, 0xe8, 0xe5, 0x50, 0xe3, 0x0f, 0x76, 0xf5, 0xea, 0xb5, 0xd0, 0x94, 0x3c, 0x04,
-	0xda, 0x1c, 0x6a, 0x40, 0xc1, 0x03, 0x1f, 0xd5, 0x5f, 0x44, 0x45, 0xfd, 0x77, 0x29, 0xc8, 0xd2,
-	0x03, 0x66, 0x10, 0x1b, 0xbd, 0x0b, 0xa5, 0x77, 0x0c, 0xb3, 0xef, 0xfd, 0x99, 0x86, 0x62, 0xfe,
-	0xc7, 0x93, 0x0a, 0xab, 0x71, 0x53, 0x01, 0xc3, 0x8a, 0xf2, 0xd3, 0x77, 0x8f, 0x98, 0x2e, 0x9a,
-	0xf1, 0x3f, 0x4d, 0xf5, 0xa5, 0x29, 0xb9, 0xa7, 0xa2, 0x05, 0x85, 0xc0, 0x7f, 0x40, 0x68, 0x25,
-	0x82, 0x0c, 0x5e, 0xa4, 0x2f, 0x53, 0x73, 0x0f, 0xc0, 0xbf, 0x02, 0xa1, 0x4b, 0x3e, 0xa8, 0x54,
-	0x57, 0x62, 0xe7, 0x3c, 0x45, 0xf7, 0xe5, 0x96, 0xf8, 0x5d, 0xea, 0x52, 0x55, 0xaf, 0xc4, 0xde,
-	0xe7, 0x02, 0xca, 0x8e, 0xa0, 0x1c, 0xa1, 0xea, 0xe8, 0x69, 0x37, 0xff, 0xea, 0xfa, 0x6c, 0x80,
-	0xa7, 0xf7, 0x87, 0x81, 0x0b, 0x9f, 0xbc, 0x02, 0x3c, 0x5d, 0xb3, 0x36, 0x0b, 0x10, 0xb4, 0xb9,
-	0xfe, 0xd7, 0x14, 0xa8, 0x1d, 0xd7, 0x26, 0xfa, 0xc8, 0x30, 0x07, 0x32, 0x65, 0xee, 0x40, 0x46,
-	0x34, 0xa9, 0xe7, 0x0d, 0xf1, 0x96, 0x82, 0x76, 0xaf, 0x28, 0x36, 0x5b, 0x0a, 0xda, 0xbf, 0xc2,
-	0xe8, 0x6c, 0x29, 0xe8, 0xfd, 0x2f, 0x27, 0x3e, 0x5b, 0x0a, 0xfa, 0xe0, 0xcb, 0x8b, 0xd0, 0x96,
-	0x82, 0x0e, 0x61, 0x41, 0x34, 0x33, 0xbf, 0x69, 0x06, 0x7c, 0x31, 0x45, 0x94, 0x02, 0x8e, 0x9d,
-	0xee, 0xb2, 0x4c, 0xe3, 0x11, 0x2c, 0x06, 0x35, 0x0a, 0x82, 0x87, 0xae, 0x87, 0xd7, 0x85, 0xc9,
-	0x70, 0xc0, 0xc3, 0x71, 0xa4, 0x96, 0xea, 0xad, 0x63, 0xc8, 0xca, 0x1b, 0xe2, 0x3d, 0x80, 0x2b,
-	0xb1, 0xb6, 0x59, 0xf9, 0xf4, 0xf1, 0xaa, 0xf2, 0xd9, 0xe3, 0x55, 0xe5, 0xdf, 0x8f, 0x57, 0x95,
-	0x4f, 0x9e, 0xac, 0xce, 0x7d, 0xf6, 0x64, 0x75, 0xee, 0xef, 0x4f, 0x56, 0xe7, 0x4e, 0x32, 0xec,
-	0xa2, 0xff, 0xc6, 0xff, 0x02, 0x00, 0x00, 0xff, 0xff, 0x9e, 0x03, 0x03, 0xde, 0x75, 0x22, 0x00,
-	0x00,
+	// 2651 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0xcb, 0x6e, 0x23, 0xc7,
+	0xd5, 0x56, 0xf3, 0xce, 0x43, 0x52, 0xa2, 0x4a, 0x1a, 0x99, 0xa6, 0x66, 0x24, 0xfd, 0xfd, 0x0f,
+	0x12, 0x61, 0x6c, 0x53, 0x12, 0x3d, 0x41, 0x7c, 0x41, 0x1c, 0x88, 0x23, 0x7a, 0x2c, 0x5b, 0xa2,
+	0x94, 0x22, 0xad, 0x38, 0x81, 0x01, 0xa1, 0x45, 0xd6, 0x70, 0x1a, 0x22, 0xbb, 0xe9, 0xee, 0xa6,
+	0x2c, 0x65, 0x61, 0x04, 0x08, 0x12, 0xc4, 0x41, 0x16, 0x46, 0x56, 0x79, 0x03, 0xbf, 0x42, 0x80,
+	0x20, 0x9b, 0x64, 0xe3, 0xc0, 0x1b, 0x03, 0xd9, 0x04, 0x41, 0xe0, 0x04, 0x33, 0x40, 0x16, 0x79,
+	0x82, 0x2c, 0x83, 0x53, 0x55, 0x7d, 0x65, 0x53, 0x33, 0x23, 0xcb, 0x40, 0x16, 0x5e, 0xb1, 0xeb,
+	0xd4, 0x57, 0xa7, 0x4e, 0xd5, 0xb9, 0xd4, 0x57, 0x45, 0x28, 0x38, 0x6c, 0x38, 0x32, 0x6b, 0x23,
+	0xcb, 0x74, 0x4c, 0x92, 0xe5, 0x8d, 0xd1, 0x49, 0x75, 0xa9, 0x6b, 0x0e, 0x87, 0xa6, 0xb1, 0x71,
+	0xb6, 0xb5, 0x21, 0xbe, 0x04, 0xa0, 0xfa, 0x52, 0x5f, 0x77, 0x1e, 0x8e, 0x4f, 0x6a, 0x5d, 0x73,
+	0xb8, 0xd1, 0x37, 0xfb, 0xe6, 0x06, 0x17, 0x9f, 0x8c, 0x1f, 0xf0, 0x16, 0x6f, 0xf0, 0x2f, 0x09,
+	0x5f, 0x74, 0x2c, 0xad, 0xcb, 0x50, 0x0b, 0xff, 0x90, 0xd2, 0xd5, 0xbe, 0x69, 0xf6, 0x07, 0xcc,
+	0x1f, 0xeb, 0xe8, 0x43, 0x66, 0x3b, 0xda, 0x70, 0x24, 0x00, 0xea, 0x7f, 0x14, 0x28, 0x77, 0x70,
+	0x40, 0xe3, 0x62, 0x77, 0x87, 0xb2, 0x0f, 0xc6, 0xcc, 0x76, 0x48, 0x05, 0xb2, 0x5c, 0xc9, 0xee,
+	0x4e, 0x45, 0x59, 0x53, 0xd6, 0x8b, 0xd4, 0x6d, 0x92, 0x15, 0x80, 0x93, 0x81, 0xd9, 0x3d, 0x6d,
+	0x3b, 0x9a, 0xe5, 0x54, 0x12, 0x6b, 0xca, 0x7a, 0x9e, 0x06, 0x24, 0xa4, 0x0a, 0x39, 0xde, 0x6a,
+	0x1a, 0xbd, 0x4a, 0x92, 0xf7, 0x7a, 0x6d, 0x72, 0x13, 0xf2, 0x1f, 0x8c, 0x99, 0x75, 0xb1, 0x6f,
+	0xf6, 0x58, 0x25, 0xcd, 0x3b, 0x7d, 0x01, 0x79, 0x11, 0xe6, 0xb5, 0xc1, 0xc0, 0xfc, 0xf0, 0x50,
+	0xb3, 0x1c, 0x5d, 0x1b, 0x70, 0x9b, 0x2a, 0x99, 0x35, 0x65, 0x3d, 0x47, 0x27, 0x3b, 0x48, 0x03,
+	0x72, 0xf4, 0xcd, 0xad, 0xed, 0x07, 0x0e, 0xb3, 0x2a, 0xd9, 0x35, 0x65, 0xbd, 0x50, 0xaf, 0xd6,
+	0xc4, 0x52, 0x6b, 0xee, 0x52, 0x6b, 0x1d, 0x77, 0xa9, 0x0d, 0xf8, 0xec, 0xcb, 0xd5, 0x99, 0x4f,
+	0xfe, 0xb1, 0xaa, 0x54, 0x14, 0xea, 0x8d, 0x53, 0x7f, 0xa7, 0xc0, 0x7c, 0x60, 0xe9, 0xf6, 0xc8,
+	0x34, 0x6c, 0x46, 0x6e, 0x43, 0x9a, 0x2f, 0x96, 0xaf, 0xbc, 0x50, 0x9f, 0xad, 0x49, 0x3f, 0xd5,
+	0x38, 0x94, 0x8a, 0x4e, 0xf2, 0x32, 0x64, 0x87, 0xcc, 0xb1, 0xf4, 0xae, 0xcd, 0x37, 0xa1, 0x50,
+	0x7f, 0x3e, 0x8c, 0x43, 0x95, 0xfb, 0x02, 0x40, 0x5d, 0x24, 0xa9, 0x41, 0xc6, 0x76, 0x34, 0x67,
+	0x6c, 0xf3, 0xad, 0x99, 0xad, 0x2f, 0x79, 0x63, 0xe4, 0xda, 0xda, 0xbc, 0x97, 0x4a, 0x14, 0xba,
+	0x61, 0xc8, 0x6c, 0x5b, 0xeb, 0xb3, 0x4a, 0x8a, 0x6f, 0x97, 0xdb, 0x54, 0x5f, 0x83, 0x72, 0x74,
+	0x1a, 0xf2, 0x2d, 0x98, 0xd5, 0x0d, 0x7b, 0xc4, 0xba, 0x0e, 0xeb, 0x35, 0x2e, 0x1c, 0x66, 0xf3,
+	0x15, 0xa4, 0x68, 0x44, 0xaa, 0xfe, 0x26, 0x09, 0xa5, 0x36, 0xd3, 0xac, 0xee, 0x43, 0xd7, 0xdd,
+	0xaf, 0x41, 0xaa, 0xa3, 0xf5, 0x11, 0x9f, 0x5c, 0x2f, 0xd4, 0xd7, 0x3c, 0xab, 0x42, 0xa8, 0x1a,
+	0x42, 0x9a, 0x86, 0x63, 0x5d, 0x34, 0x52, 0xb8, 0x9d, 0x94, 0x8f, 0x21, 0xb7, 0xa1, 0xb4, 0xaf,
+	0x1b, 0x3b, 0x63, 0x4b, 0x73, 0x74, 0xd3, 0xd8, 0x17, 0xdb, 0x51, 0xa2, 0x61, 0x21, 0x47, 0x69,
+	0xe7, 0x01, 0x54, 0x52, 0xa2, 0x82, 0x42, 0xb2, 0x08, 0xe9, 0x3d, 0x7d, 0xa8, 0x3b, 0x7c, 0xb5,
+	0x25, 0x2a, 0x1a, 0x28, 0xb5, 0x79, 0xb4, 0xa5, 0x85, 0x94, 0x37, 0x48, 0x19, 0x92, 0xcc, 0xe8,
+	0xf1, 0x00, 0x29, 0x51, 0xfc, 0x44, 0xdc, 0x0f, 0x30, 0x9a, 0x2a, 0x39, 0xbe, 0x57, 0xa2, 0x41,
+	0xd6, 0x61, 0xae, 0x3d, 0xd2, 0x0c, 0xfb, 0x90, 0x59, 0xf8, 0xdb, 0x66, 0x4e, 0x25, 0xcf, 0xc7,
+	0x44, 0xc5, 0xa1, 0x90, 0x82, 0xab, 0x85, 0x54, 0xf5, 0xbb, 0x90, 0xf7, 0xb6, 0x09, 0x4d, 0x3c,
+	0x65, 0x17, 0xdc, 0x0b, 0x79, 0x8a, 0x9f, 0x68, 0xe2, 0x99, 0x36, 0x18, 0x33, 0x99, 0x38, 0xa2,
+	0xf1, 0x5a, 0xe2, 0x15, 0x45, 0xfd, 0x45, 0x12, 0x88, 0xd8, 0xee, 0x06, 0xa6, 0x8b, 0xeb, 0x99,
+	0xbb, 0x90, 0xb7, 0x5d, 0x27, 0xc8, 0x80, 0x5c, 0x8a, 0x77, 0x0f, 0xf5, 0x81, 0x18, 0x37, 0x3c,
+	0xe9, 0x76, 0x77, 0xe4, 0x44, 0x6e, 0x13, 0x53, 0x90, 0x6f, 0xdf, 0x21, 0xc6, 0x94, 0xf0, 0x81,
+	0x2f, 0x40, 0x2f, 0x8d, 0xb4, 0x3e, 0xb3, 0x3b, 0xa6, 0x50, 0x2d, 0xfd, 0x10, 0x16, 0x22, 0x4a,
+	0x37, 0x7a, 0xec, 0x1c, 0x87, 0xb4, 0xf5, 0x9f, 0x30, 0xe9, 0x83, 0xb0, 0x90, 0xa8, 0x50, 0x74,
+	0x4c, 0x47, 0x1b, 0x50, 0xd6, 0x35, 0xad, 0x9e, 0xcd, 0x93, 0xb4, 0x44, 0x43, 0x32, 0xb4, 0xf3,
+	0x8c, 0x59, 0xb6, 0x6e, 0x1a, 0xdc, 0x27, 0x79, 0xea, 0x36, 0x09, 0x81, 0x94, 0x8d, 0xaa, 0x81,
+	0x47, 0x30, 0xff, 0xc6, 0xd2, 0xf3, 0xc0, 0x34, 0x1d, 0x66, 0xf1, 0x49, 0x0b, 0x5c, 0x5f, 0x40,
+	0x42, 0x76, 0xa0, 0xdc, 0x63, 0x3d, 0xbd, 0xab, 0x39, 0xac, 0x77, 0xcf, 0x1c, 0x8c, 0x87, 0x86,
+	0x5d, 0x29, 0xf2, 0x88, 0xae, 0x78, 0x5b, 0xb6, 0x13, 0x06, 0xd0, 0x89, 0x11, 0xea, 0xef, 0x13,
+	0x30, 0x17, 0x41, 0x91, 0xbb, 0x90, 0xb6, 0xbb, 0xe6, 0x88, 0xc9, 0xb4, 0x5d, 0x99, 0xa6, 0xae,
+	0xd6, 0x46, 0x14, 0x15, 0x60, 0x5c, 0x83, 0xa1, 0x0d, 0x5d, 0x5f, 0xf3, 0x6f, 0xb2, 0x05, 0x29,
+	0xe7, 0x62, 0x24, 0x6a, 0xcb, 0x6c, 0xfd, 0xd6, 0x54, 0x45, 0x9d, 0x8b, 0x11, 0xa3, 0x1c, 0x4a,
+	0x5e, 0x85, 0xac, 0x39, 0xc2, 0x04, 0xb1, 0xb9, 0x3b, 0x66, 0xeb, 0xab, 0x53, 0x47, 0x1d, 0x70,
+	0x1c, 0x75, 0xf1, 0xea, 0x1d, 0x48, 0x73, 0x8b, 0x48, 0x0e, 0x52, 0xed, 0xc3, 0xed, 0x56, 0x79,
+	0x86, 0x14, 0x21, 0x47, 0x9b, 0xed, 0x83, 0x77, 0xe9, 0xbd, 0x66, 0x59, 0x21, 0x79, 0x48, 0x37,
+	0x8f, 0x9a, 0xad, 0x4e, 0x39, 0xa1, 0x2e, 0x43, 0x0a, 0x27, 0x25, 0x00, 0x99, 0x76, 0x87, 0xee,
+	0xb6, 0xee, 0x97, 0x67, 0x48, 0x16, 0x92, 0xbb, 0xad, 0x4e, 0x59, 0x51, 0xbf, 0x0d, 0x19, 0xa1,
+	0x1b, 0x35, 0xb5, 0x0e, 0x5a, 0xcd, 0xf2, 0x0c, 0x8e, 0xdd, 0xa6, 0x74, 0xfb, 0x47, 0x65, 0x05,
+	0x85, 0x8d, 0xbd, 0x83, 0x46, 0x39, 0xa1, 0x7e, 0xaa, 0xc0, 0xac, 0x1b, 0x96, 0xb2, 0x9e, 0xde,
+	0x85, 0x0c, 0x2f, 0x99, 0x6e, 0x79, 0xb9, 0x19, 0x2e, 0x94, 0x02, 0xbd, 0xcf, 0x1c, 0xad, 0xa7,
+	0x39, 0x1a, 0x95, 0x58, 0xb2, 0x19, 0xad, 0xaf, 0xd1, 0xb0, 0x9f, 0x28, 0xae, 0x2f, 0x41, 0xda,
+	0xd1, 0x4e, 0x06, 0xe8, 0x24, 0x9c, 0xe6, 0xb9, 0x08, 0xbe, 0x83, 0x7d, 0xd4, 0xfc, 0x90, 0x0a,
+	0x94, 0xfa, 0x2f, 0xcf, 0x52, 0xb7, 0x87, 0x6c, 0x41, 0xba, 0x6f, 0x99, 0xe3, 0x91, 0x34, 0x74,
+	0xd9, 0xd3, 0x20, 0x8f, 0xe5, 0xb3, 0xad, 0xda, 0x3b, 0xec, 0xe2, 0x08, 0x73, 0x96, 0x0a, 0x24,
+	0x79, 0x1d, 0x40, 0xeb, 0xf7, 0x2d, 0xd6, 0xd7, 0xb0, 0xde, 0x26, 0x9e, 0x3c, 0x2e, 0x00, 0xc7,
+	0x80, 0xe6, 0xab, 0xbd, 0x67, 0x8e, 0x0d, 0x47, 0x66, 0x63, 0x40, 0xc2, 0x93, 0x75, 0xa4, 0x19,
+	0xa2, 0x3b, 0x25, 0x93, 0xd5, 0x15, 0x60, 0xb9, 0xb7, 0xb5, 0xe1, 0x68, 0xc0, 0x3a, 0xe2, 0x68,
+	0xb6, 0x2b, 0xe9, 0xb5, 0xe4, 0x7a, 0x9e, 0x46, 0xa4, 0xea, 0x5f, 0x92, 0xb0, 0x10, 0xb3, 0xd3,
+	0xd1, 0x33, 0x3e, 0xef, 0x9f, 0xf1, 0xeb, 0x30, 0x67, 0x99, 0xa6, 0xd3, 0x66, 0xd6, 0x99, 0xde,
+	0x65, 0x2d, 0x3f, 0x86, 0xa3, 0x62, 0x2c, 0x05, 0x28, 0xe2, 0xea, 0x39, 0x4e, 0x1c, 0xf9, 0x61,
+	0x21, 0x9e, 0xec, 0xbc, 0xc6, 0x60, 0xf1, 0x7c, 0xd7, 0xd0, 0xcf, 0x5b, 0x9a, 0x61, 0xf2, 0xf5,
+	0xa4, 0xe8, 0x64, 0x07, 0xee, 0x4a, 0xcf, 0x3f, 0x27, 0x44, 0xcd, 0x0f, 0x48, 0xc8, 0x1d, 0xc8,
+	0xda, 0xb2, 0x90, 0x67, 0x78, 0x64, 0x94, 0x7d, 0x4f, 0x0b, 0x39, 0x75, 0x01, 0xe4, 0x45, 0xc8,
+	0xc9, 0x4f, 0x2c, 0x40, 0xc9, 0x58, 0xb0, 0x87, 0x20, 0x14, 0x8a, 0xb6, 0x58, 0x1c, 0x9e, 0xc3,
+	0x76, 0x25, 0xc7, 0x47, 0xd4, 0x2e, 0x8b, 0xd7, 0x5a, 0x3b, 0x30, 0x80, 0x57, 0x7d, 0x1a, 0xd2,
+	0x51, 0x3d, 0x82, 0xf9, 0x09, 0x48, 0xcc, 0xc1, 0xf0, 0x42, 0xf0, 0x60, 0x28, 0xd4, 0x6f, 0xf8,
+	0x56, 0x06, 0x06, 0x07, 0xcf, 0x8b, 0x3d, 0x28, 0x06, 0xbb, 0xc2, 0xb1, 0xa2, 0x44, 0x63, 0x65,
+	0x05, 0x80, 0x59, 0x96, 0x69, 0x89, 0x6e, 0x71, 0x42, 0x07, 0x24, 0xea, 0xcf, 0x15, 0xc8, 0xba,
+	0xc7, 0xe0, 0xff, 0x43, 0x1a, 0x07, 0xba, 0xe9, 0x5a, 0x0a, 0x6d, 0x18, 0x15, 0x7d, 0x9c, 0x99,
+	0x68, 0x4e, 0xf7, 0x21, 0xeb, 0x49, 0x6d, 0x6e, 0x93, 0x67, 0x84, 0xe3, 0x58, 0xfa, 0xc9, 0x18,
+	0x33, 0x22, 0xf9, 0x34, 0x19, 0xe1, 0xc1, 0xd5, 0x3f, 0x29, 0x90, 0xc2, 0x69, 0xc8, 0x12, 0x64,
+	0x70, 0x22, 0x2f, 0x36, 0x65, 0x2b, 0xb6, 0xa6, 0xc6, 0x86, 0x57, 0x72, 0x5a, 0x78, 0xdd, 0x86,
+	0x92, 0x1b, 0x4c, 0xd8, 0xb6, 0x65, 0x20, 0x86, 0x85, 0x91, 0x55, 0xa4, 0x9f, 0x6d, 0x15, 0x9f,
+	0x27, 0xa0, 0x14, 0x2a, 0x52, 0x98, 0x51, 0x1e, 0x09, 0xeb, 0xb8, 0xc5, 0x90, 0x93, 0x90, 0x88,
+	0x38, 0x86, 0xc4, 0x25, 0xe2, 0x48, 0x1c, 0x59, 0x83, 0x02, 0x3f, 0x4a, 0x39, 0x5b, 0x70, 0xe9,
+	0x54, 0x50, 0x84, 0x0b, 0xed, 0x9a, 0x58, 0x09, 0x1c, 0xd6, 0x7b, 0xdb, 0x3c, 0xb1, 0xdd, 0xc3,
+	0x3c, 0x24, 0xc4, 0xb8, 0xe1, 0x83, 0x38, 0x42, 0x24, 0x9b, 0x2f, 0x40, 0xbb, 0x7d, 0x95, 0xc2,
+	0x9c, 0x0c, 0x37, 0x27, 0x2a, 0x0e, 0xd9, 0xcd, 0x89, 0x55, 0x25, 0x1b, 0xb1, 0x9b, 0x4b, 0xc9,
+	0x26, 0x2c, 0xd8, 0xa7, 0xfa, 0x68, 0xc4, 0x7a, 0x7b, 0xba, 0x71, 0xea, 0xed, 0x46, 0x8e, 0xcf,
+	0x1c, 0xd7, 0xa5, 0x7e, 0x9c, 0x80, 0x79, 0xb1, 0x9b, 0xc8, 0xac, 0x5c, 0x62, 0xb4, 0xe8, 0x1e,
+	0xc9, 0x22, 0x3e, 0x44, 0x03, 0xa5, 0xfc, 0x42, 0xe1, 0xf2, 0x2b, 0xde, 0xf0, 0x09, 0x64, 0x32,
+	0x86, 0x40, 0xa6, 0x7c, 0x02, 0xb9, 0x0e, 0x73, 0x43, 0xed, 0x1c, 0x67, 0x41, 0x56, 0xc8, 0xb5,
+	0x8b, 0x1d, 0x89, 0x8a, 0x49, 0x1d, 0x16, 0x6d, 0x47, 0x1b, 0x30, 0xee, 0x7b, 0xbb, 0xf3, 0xd0,
+	0x62, 0xf6, 0x43, 0x73, 0xe0, 0xb2, 0xd1, 0xd8, 0xbe, 0x6b, 0xb9, 0xb1, 0xfc, 0x3b, 0x09, 0x4b,
+	0xfe, 0x5e, 0x84, 0x98, 0xe2, 0x2b, 0x93, 0x4c, 0xb1, 0x3a, 0x71, 0x04, 0xf6, 0xed, 0x6f, 0xd8,
+	0xe2, 0x75, 0xb2, 0xc5, 0xb8, 0x90, 0x29, 0xc5, 0x87, 0x0c, 0x06, 0xbe, 0x17, 0x16, 0x7e, 0xc4,
+	0xcc, 0xca, 0xc0, 0x9f, 0xec, 0x52, 0x7f, 0x9b, 0x84, 0x65, 0xcf, 0x71, 0xbc, 0x2f, 0xec, 0xf1,
+	0xef, 0x4d, 0x7a, 0x7c, 0x75, 0xd2, 0xe3, 0x62, 0xe0, 0x37, 0x6e, 0xbf, 0xd6, 0x4b, 0x42, 0xcf,
+	0xbd, 0xac, 0x89, 0x94, 0x92, 0x4c, 0xb7, 0x0a, 0x39, 0x47, 0xeb, 0x23, 0xe5, 0x11, 0x87, 0x67,
+	0x9e, 0x7a, 0x6d, 0x52, 0x8f, 0xf2, 0x59, 0x7f, 0x3a, 0x97, 0x4b, 0x44, 0x19, 0xad, 0xfa, 0x11,
+	0x2c, 0xfa, 0xb3, 0x1c, 0xd5, 0xbd, 0x79, 0xea, 0x90, 0xe1, 0xe5, 0xce, 0x3d, 0xa2, 0xe3, 0xf2,
+	0xfc, 0xa8, 0x2e, 0xee, 0x22, 0x12, 0x79, 0xa5, 0xf9, 0x5f, 0x87, 0xf9, 0x09, 0x85, 0xde, 0x09,
+	0xac, 0x04, 0x4e, 0x60, 0x02, 0x29, 0x47, 0xeb, 0x0b, 0xfe, 0x9b, 0xa7, 0xfc, 0x5b, 0xfd, 0x55,
+	0x02, 0x96, 0xe2, 0x83, 0x90, 0x33, 0x4f, 0xb1, 0x2f, 0x1e, 0xf3, 0x14, 0xcd, 0x27, 0xd5, 0xef,
+	0x54, 0x4c, 0xfd, 0x4e, 0xfb, 0xf5, 0x5b, 0x85, 0xa2, 0xc8, 0x3a, 0x31, 0x9d, 0x0c, 0xb9, 0x90,
+	0x6c, 0x5a, 0x1a, 0x66, 0xa7, 0xa6, 0x61, 0xa8, 0x6e, 0xe7, 0xae, 0x58, 0xb7, 0x4f, 0xe1, 0xb9,
+	0x89, 0xbd, 0x90, 0xce, 0xc4, 0x03, 0xd8, 0xb3, 0x58, 0x44, 0x8d, 0x2f, 0xb8, 0x92, 0xdb, 0xee,
+	0x42, 0xce, 0x9d, 0x86, 0x90, 0xc0, 0x7d, 0x33, 0x2f, 0x2f, 0x94, 0xb1, 0x8f, 0x10, 0xea, 0x4f,
+	0x15, 0x78, 0x3e, 0x62, 0x63, 0x20, 0xe4, 0x36, 0xa2, 0x56, 0x16, 0xea, 0xf3, 0x3e, 0x2f, 0x96,
+	0x3d, 0x5f, 0xd5, 0xf0, 0x3f, 0x2b, 0x30, 0x17, 0xe9, 0x7c, 0xda, 0x47, 0xad, 0x30, 0x8f, 0x49,
+	0x44, 0x79, 0xcc, 0x04, 0x17, 0x4a, 0xc6, 0x71, 0xa1, 0x08, 0xa7, 0x4a, 0x4d, 0x72, 0xaa, 0x18,
+	0x3e, 0x94, 0x8e, 0xe5, 0x43, 0x6a, 0x0b, 0xd2, 0xe2, 0xa1, 0xb2, 0x09, 0x25, 0x8b, 0xd9, 0xe6,
+	0xd8, 0xea, 0xb2, 0x76, 0x80, 0x56, 0xfb, 0x95, 0x5a, 0xbc, 0xd6, 0x9e, 0x6d, 0xd5, 0x68, 0x10,
+	0x46, 0xc3, 0xa3, 0xd4, 0x16, 0x14, 0x0f, 0xc7, 0xb6, 0x7f, 0xab, 0x7e, 0x03, 0x4a, 0x9c, 0xbf,
+	0xdb, 0x8d, 0x8b, 0x8e, 0x7c, 0xad, 0x4c, 0xae, 0xcf, 0x06, 0x76, 0x19, 0xd1, 0x4d, 0x44, 0x50,
+	0xa6, 0xd9, 0xa6, 0x41, 0xc3, 0x70, 0xf5, 0x63, 0x05, 0xca, 0x08, 0xe1, 0xd6, 0xba, 0x89, 0xf9,
+	0x92, 0x77, 0x55, 0xc7, 0x4c, 0x2e, 0x36, 0x6e, 0x60, 0x30, 0xff, 0xed, 0xcb, 0xd5, 0xd2, 0xa1,
+	0xc5, 0xf0, 0x09, 0xb6, 0x2b, 0xd0, 0x12, 0x84, 0x19, 0xa8, 0xf7, 0x04, 0xc7, 0x2f, 0x52, 0xfc,
+	0x24, 0x77, 0xe1, 0x06, 0x52, 0x38, 0xe9, 0xbc, 0xfb, 0xcc, 0x60, 0x82, 0x54, 0xf3, 0x5d, 0xca,
+	0xd1, 0xf8, 0x4e, 0xf5, 0x67, 0xd2, 0x16, 0xb1, 0x70, 0x69, 0xcb, 0xab, 0x90, 0x3d, 0xe1, 0x57,
+	0x8a, 0xa7, 0xde, 0x31, 0x17, 0x3f, 0xdd, 0x8a, 0xc4, 0x65, 0x56, 0xdc, 0x06, 0x90, 0x4f, 0xaa,
+	0x18, 0x4f, 0x4b, 0xa1, 0x57, 0x8b, 0xa2, 0xbb, 0x66, 0xf5, 0x0d, 0xc8, 0x23, 0x3b, 0x6d, 0x0f,
+	0xf4, 0x2e, 0xbe, 0xe6, 0xa4, 0x07, 0xba, 0x71, 0x6a, 0x4f, 0x3c, 0x18, 0x78, 0x16, 0xa2, 0x65,
+	0x35, 0x1c, 0x40, 0x05, 0x52, 0x6d, 0xc3, 0x02, 0x7f, 0x97, 0xdc, 0x35, 0x6c, 0x47, 0x33, 0x9c,
+	0x00, 0x9d, 0x15, 0x85, 0x4f, 0x89, 0x2d, 0x7c, 0xe2, 0x0e, 0x10, 0x2e, 0x7c, 0xe2, 0x86, 0x83,
+	0x9f, 0xea, 0x1f, 0x15, 0x58, 0x0c, 0x6b, 0x95, 0x51, 0x82, 0x0f, 0xce, 0xcc, 0xd2, 0xbd, 0x3d,
+	0xf4, 0x1f, 0x51, 0x24, 0xb2, 0xcd, 0x7b, 0xa9, 0x44, 0x5d, 0xe1, 0xd5, 0xe5, 0xfa, 0x9e, 0xb4,
+	0x6d, 0x28, 0x85, 0x8c, 0x22, 0xaf, 0x42, 0x66, 0xa0, 0x9d, 0xb0, 0x81, 0xfd, 0x14, 0xef, 0x31,
+	0xf2, 0x49, 0x5a, 0x0e, 0x08, 0x97, 0x38, 0x45, 0x96, 0xb8, 0xb7, 0x53, 0xb9, 0x64, 0x39, 0x45,
+	0x0b, 0x23, 0xcb, 0x1c, 0x1e, 0x0b, 0xa0, 0xfa, 0xf7, 0x24, 0xcc, 0xf3, 0x9d, 0xa3, 0x9a, 0xd1,
+	0x67, 0xd7, 0xe2, 0x0d, 0x4e, 0x4b, 0x1c, 0x36, 0x92, 0x17, 0x4b, 0xfe, 0x1d, 0xfe, 0xeb, 0x23,
+	0x1b, 0xfd, 0xeb, 0x23, 0x40, 0xc5, 0x72, 0x97, 0x50, 0xb1, 0xfc, 0x13, 0xa9, 0x18, 0xc4, 0x51,
+	0xb1, 0x00, 0x81, 0x2a, 0xc4, 0x13, 0xa8, 0xd2, 0x54, 0x02, 0x35, 0xfb, 0x54, 0x04, 0x6a, 0xee,
+	0x99, 0x79, 0xf3, 0x4d, 0xc8, 0xb3, 0x73, 0x36, 0x1c, 0x0d, 0x34, 0xcb, 0xae, 0x94, 0xc5, 0xba,
+	0x3c, 0x01, 0xf6, 0x0e, 0xb5, 0x73, 0x11, 0x06, 0x95, 0x79, 0xd1, 0xeb, 0x09, 0xc8, 0x2d, 0xc8,
+	0xea, 0x22, 0x50, 0x2a, 0x04, 0x13, 0xfa, 0xad, 0x19, 0xea, 0x0a, 0x7e, 0xa9, 0x28, 0x0d, 0x80,
+	0xdc, 0xb1, 0x6c, 0xaa, 0x7f, 0x50, 0x80, 0x04, 0xdd, 0x2b, 0xd3, 0xe2, 0x85, 0x48, 0x5a, 0x2c,
+	0xf8, 0x47, 0x99, 0x3e, 0x64, 0xff, 0x43, 0x39, 0xf1, 0x11, 0xe4, 0x9a, 0x72, 0x57, 0xae, 0x3d,
+	0x1d, 0xc8, 0xff, 0x41, 0xd1, 0xfb, 0x33, 0xf0, 0x78, 0x28, 0x8c, 0x4d, 0xd2, 0x82, 0x27, 0xdb,
+	0xb7, 0xd5, 0x6d, 0xc8, 0xb4, 0xf9, 0x6b, 0xe2, 0x04, 0x38, 0x31, 0x01, 0xf6, 0x67, 0x51, 0x02,
+	0xb3, 0x60, 0x6d, 0x02, 0x7f, 0x57, 0xbf, 0xca, 0x2a, 0x36, 0x20, 0x2b, 0x9e, 0x36, 0xdd, 0x87,
+	0xd6, 0x39, 0xdf, 0x11, 0x5c, 0x2e, 0xf1, 0x2e, 0x8a, 0x7c, 0x27, 0x18, 0x64, 0xa9, 0x08, 0x69,
+	0x71, 0xf7, 0x55, 0x0e, 0xf2, 0x91, 0x31, 0x65, 0xe2, 0xce, 0xfb, 0x30, 0x17, 0x39, 0x4f, 0xf1,
+	0x21, 0xbd, 0x75, 0x70, 0xdc, 0xa4, 0xf4, 0x80, 0x96, 0x67, 0xc8, 0x02, 0xcc, 0xed, 0x6f, 0xbf,
+	0x77, 0xbc, 0xb7, 0x7b, 0xd4, 0x3c, 0xee, 0xd0, 0xed, 0x7b, 0xcd, 0x76, 0x59, 0x41, 0x21, 0xff,
+	0x3e, 0xee, 0x1c, 0x1c, 0x1c, 0xef, 0x6d, 0xd3, 0xfb, 0xcd, 0x72, 0x82, 0xcc, 0x43, 0xe9, 0xdd,
+	0xd6, 0x3b, 0xad, 0x83, 0x1f, 0xb6, 0xe4, 0xe0, 0xe4, 0x9d, 0x3b, 0x50, 0x0a, 0x05, 0x06, 0xea,
+	0xbe, 0x77, 0xb0, 0x7f, 0xb8, 0xd7, 0xec, 0xe0, 0x43, 0x7b, 0x01, 0xb2, 0x87, 0xdb, 0xb4, 0xb3,
+	0xbb, 0xbd, 0x57, 0x56, 0xea, 0xbf, 0x56, 0x20, 0x83, 0xa6, 0x30, 0x8b, 0x7c, 0x1f, 0xf2, 0xde,
+	0x09, 0x4e, 0x9e, 0x0f, 0x1d, 0xfc, 0xc1, 0x53, 0xbd, 0x7a, 0x23, 0xd4, 0xe5, 0x26, 0x81, 0x3a,
+	0x43, 0xb6, 0xa1, 0xe0, 0x81, 0x8f, 0xea, 0x57, 0x51, 0x51, 0xff, 0x34, 0x05, 0x59, 0x4c, 0x30,
+	0x9d, 0x59, 0xe4, 0x2d, 0x28, 0xbd, 0xa9, 0x1b, 0x3d, 0xef, 0x7f, 0x49, 0x12, 0xf3, 0x97, 0xa8,
+	0xab, 0xb0, 0x1a, 0xd7, 0x15, 0x30, 0xac, 0xe8, 0xfe, 0x89, 0xd0, 0x65, 0x86, 0x43, 0xa6, 0xfc,
+	0xe5, 0x55, 0x7d, 0x6e, 0x42, 0xee, 0xa9, 0x68, 0x42, 0x21, 0xf0, 0x77, 0x1a, 0x59, 0x8e, 0x20,
+	0x83, 0x17, 0xe9, 0xcb, 0xd4, 0xdc, 0x07, 0xf0, 0xaf, 0x40, 0xe4, 0x92, 0x07, 0x95, 0xea, 0x72,
+	0x6c, 0x9f, 0xa7, 0xe8, 0x1d, 0x28, 0xfa, 0xf2, 0xa3, 0xfa, 0xa5, 0xaa, 0x6e, 0xc5, 0xde, 0xe7,
+	0x02, 0xca, 0x8e, 0x60, 0x2e, 0x42, 0xd5, 0xc9, 0x93, 0x6e, 0xfe, 0xd5, 0xb5, 0xe9, 0x00, 0x4f,
+	0xef, 0x8f, 0x61, 0x3e, 0xd2, 0x79, 0x54, 0x7f, 0xb2, 0x66, 0x75, 0x1a, 0x20, 0x68, 0x73, 0xfd,
+	0xf3, 0x14, 0x94, 0xdb, 0x8e, 0xc5, 0xb4, 0xa1, 0x6e, 0xf4, 0xdd, 0x90, 0x79, 0x1d, 0x32, 0x62,
+	0xcc, 0x33, 0xbb, 0x78, 0x53, 0x21, 0xbb, 0xd7, 0xe4, 0x9b, 0x4d, 0x85, 0xec, 0x5f, 0xa3, 0x77,
+	0x36, 0x15, 0xf2, 0xde, 0xd7, 0xe3, 0x9f, 0x4d, 0x85, 0xbc, 0xff, 0xf5, 0x79, 0x68, 0x53, 0x21,
+	0x87, 0x30, 0x2f, 0x0f, 0x33, 0xff, 0xd0, 0x0c, 0xec, 0xc5, 0x04, 0x51, 0xaa, 0x2e, 0xc7, 0xf6,
+	0x05, 0x34, 0x1e, 0xc1, 0x42, 0x50, 0xa3, 0x24, 0x78, 0xe4, 0x66, 0x78, 0x5c, 0x98, 0x0c, 0x57,
+	0x6f, 0x4d, 0xe9, 0xf5, 0xf5, 0xd6, 0x29, 0x64, 0xa5, 0x5e, 0x4c, 0xd1, 0x6b, 0xb1, 0xb6, 0x51,
+	0xf9, 0xec, 0xd1, 0x8a, 0xf2, 0xc5, 0xa3, 0x15, 0xe5, 0x9f, 0x8f, 0x56, 0x94, 0x4f, 0x1e, 0xaf,
+	0xcc, 0x7c, 0xf1, 0x78, 0x65, 0xe6, 0xaf, 0x8f, 0x57, 0x66, 0x4e, 0x32, 0xfc, 0xa2, 0xff, 0xf2,
+	0x7f, 0x07, 0x00, 0x7d, 0xf4, 0x01, 0x93, 0xc0, 0x23, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -4417,6 +4519,20 @@ func (m *SearchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Table) > 0 {
+		for iNdEx := len(m.Table) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Table[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if m.Metrics != nil {
 		{
 			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
@@ -4446,6 +4562,76 @@ func (m *SearchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *SearchTableRow) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchTableRow) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchTableRow) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.SampleTraceIDs) > 0 {
+		for iNdEx := len(m.SampleTraceIDs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.SampleTraceIDs[iNdEx])
+			copy(dAtA[i:], m.SampleTraceIDs[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.SampleTraceIDs[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if m.SpanCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SpanCount))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.TraceCount != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.TraceCount))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Aggregates) > 0 {
+		for iNdEx := len(m.Aggregates) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Aggregates[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Group) > 0 {
+		for iNdEx := len(m.Group) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Group[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *TraceSearchMetadata) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -4722,6 +4908,11 @@ func (m *SearchMetrics) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.SkippedLinkedTraces != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.SkippedLinkedTraces))
+		i--
+		dAtA[i] = 0x40
+	}
 	if m.InspectedSpans != 0 {
 		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedSpans))
 		i--
@@ -6341,6 +6532,45 @@ func (m *SearchResponse) Size() (n int) {
 		l = m.Metrics.Size()
 		n += 1 + l + sovTempo(uint64(l))
 	}
+	if len(m.Table) > 0 {
+		for _, e := range m.Table {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SearchTableRow) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Group) > 0 {
+		for _, e := range m.Group {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if len(m.Aggregates) > 0 {
+		for _, e := range m.Aggregates {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if m.TraceCount != 0 {
+		n += 1 + sovTempo(uint64(m.TraceCount))
+	}
+	if m.SpanCount != 0 {
+		n += 1 + sovTempo(uint64(m.SpanCount))
+	}
+	if len(m.SampleTraceIDs) > 0 {
+		for _, s := range m.SampleTraceIDs {
+			l = len(s)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -6489,6 +6719,9 @@ func (m *SearchMetrics) Size() (n int) {
 	if m.InspectedSpans != 0 {
 		n += 1 + sovTempo(uint64(m.InspectedSpans))
 	}
+	if m.SkippedLinkedTraces != 0 {
+		n += 1 + sovTempo(uint64(m.SkippedLinkedTraces))
+	}
 	return n
 }
 
@@ -8484,6 +8717,228 @@ func (m *SearchResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Table", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Table = append(m.Table, &SearchTableRow{})
+			if err := m.Table[len(m.Table)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchTableRow) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchTableRow: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchTableRow: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Group", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Group = append(m.Group, &v1.KeyValue{})
+			if err := m.Group[len(m.Group)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Aggregates", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Aggregates = append(m.Aggregates, &v1.KeyValue{})
+			if err := m.Aggregates[len(m.Aggregates)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceCount", wireType)
+			}
+			m.TraceCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TraceCount |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SpanCount", wireType)
+			}
+			m.SpanCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SpanCount |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SampleTraceIDs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SampleTraceIDs = append(m.SampleTraceIDs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -9461,6 +9916,25 @@ func (m *SearchMetrics) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SkippedLinkedTraces", wireType)
+			}
+			m.SkippedLinkedTraces = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SkippedLinkedTraces |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -35,7 +35,13 @@ type RootExpr struct {
 	MetricsPipeline    firstStageElement
 	MetricsSecondStage secondStageElement
 	Hints              *Hints
-	OptimizationCount  int
+	// Offset shifts the time range of a metrics query into the past, i.e. `{} | rate() offset 7d`.
+	// It is applied by the query frontend, the results are returned at the requested timestamps.
+	Offset time.Duration
+	// Linked is evaluated on the traces that the spans matched by Pipeline link to, i.e.
+	// `{ kind = consumer } ->link { kind = producer }`. The links are followed by ResolveLinks.
+	Linked            *Pipeline
+	OptimizationCount int
 }
 
 func NeedsFullTrace(e ...Element) bool {
@@ -109,14 +115,31 @@ func newRootExprWithMetricsTwoStage(e PipelineElement, m1 firstStageElement, m2
 	}
 }
 
+func newRootExprWithLinkTraversal(lhs, rhs Pipeline) *RootExpr {
+	return &RootExpr{
+		Pipeline: lhs,
+		Linked:   &rhs,
+	}
+}
+
 func (r *RootExpr) withHints(h *Hints) *RootExpr {
 	r.Hints = h
 	return r
 }
 
+func (r *RootExpr) withOffset(d time.Duration) *RootExpr {
+	r.Offset = d
+	return r
+}
+
 // IsNoop detects trivial noop queries like {false} which never return
 // results and can be used to exit early.
 func (r *RootExpr) IsNoop() bool {
+	if _, ok := r.MetricsPipeline.(*MetricsBinaryOperation); ok {
+		// The pipelines are within the operand queries.
+		return false
+	}
+
 	isNoopFilter := func(x any) bool {
 		f, ok := x.(*SpansetFilter)
 		if !ok {
@@ -275,14 +298,32 @@ func newCoalesceOperation() CoalesceOperation {
 func (o CoalesceOperation) extractConditions(*FetchSpansRequest) {
 }
 
+// SelectOperation adds attributes, or the results of functions called on them, to the spans
+// of the search results.
 type SelectOperation struct {
-	attrs []Attribute
+	exprs []FieldExpression
 }
 
-func newSelectOperation(exprs []Attribute) SelectOperation {
+func newSelectOperation(exprs []FieldExpression) SelectOperation {
 	return SelectOperation{
-		attrs: exprs,
+		exprs: exprs,
+	}
+}
+
+// functionCalls returns the function calls selected by the select operations of the pipeline.
+// Their values are computed when the spans are returned, after all attributes have been fetched.
+func (p Pipeline) functionCalls() []*FunctionCall {
+	var calls []*FunctionCall
+	for _, element := range p.Elements {
+		if o, ok := element.(SelectOperation); ok {
+			for _, e := range o.exprs {
+				if f, ok := e.(*FunctionCall); ok {
+					calls = append(calls, f)
+				}
+			}
+		}
 	}
+	return calls
 }
 
 // **********************
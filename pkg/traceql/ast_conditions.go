@@ -1,10 +1,19 @@
 package traceql
 
+import "regexp"
+
 func (r RootExpr) extractConditions(request *FetchSpansRequest) {
 	r.Pipeline.extractConditions(request)
 	if r.MetricsPipeline != nil {
 		r.MetricsPipeline.extractConditions(request)
 	}
+	if r.Linked != nil {
+		// the links of the matching spans are returned to be followed
+		request.SecondPassConditions = append(request.SecondPassConditions,
+			Condition{Attribute: IntrinsicLinkTraceIDAttribute, Op: OpNone},
+			Condition{Attribute: IntrinsicLinkSpanIDAttribute, Op: OpNone},
+		)
+	}
 }
 
 func (f SpansetFilter) extractConditions(request *FetchSpansRequest) {
@@ -35,7 +44,12 @@ func (f SpansetFilter) extractConditions(request *FetchSpansRequest) {
 // extractConditions on Select puts its conditions into the SecondPassConditions
 func (o SelectOperation) extractConditions(request *FetchSpansRequest) {
 	selectR := &FetchSpansRequest{}
-	for _, expr := range o.attrs {
+	for _, expr := range o.exprs {
+		if f, ok := expr.(*FunctionCall); ok {
+			// the values are computed from the args, never filter them
+			f.fetchArguments(selectR)
+			continue
+		}
 		expr.extractConditions(selectR)
 	}
 	// copy any conditions to the normal request's SecondPassConditions
@@ -43,6 +57,18 @@ func (o SelectOperation) extractConditions(request *FetchSpansRequest) {
 }
 
 func (o *BinaryOperation) extractConditions(request *FetchSpansRequest) {
+	// function calls that are compared to something are never pushed down as a filter on their own
+	if o.Op != OpAnd && o.Op != OpOr {
+		if f, ok := o.LHS.(*FunctionCall); ok {
+			f.extractComparisonConditions(o.Op, o.RHS, request)
+			return
+		}
+		if f, ok := o.RHS.(*FunctionCall); ok {
+			f.extractComparisonConditions(o.Op, o.LHS, request)
+			return
+		}
+	}
+
 	// TODO we can further optimise this by attempting to execute every FieldExpression, if they only contain statics it should resolve
 	switch l := o.LHS.(type) {
 	case Attribute:
@@ -128,6 +154,9 @@ func (o UnaryOperation) extractConditions(request *FetchSpansRequest) {
 	// TODO when Op is Not we should just either negate all inner Operands or just fetch the columns with OpNone
 
 	switch expr := o.Expression.(type) {
+	case *FunctionCall:
+		request.AllConditions = false
+		expr.fetchArguments(request)
 	case Attribute:
 		switch o.Op {
 		case OpExists, OpNotExists:
@@ -145,6 +174,91 @@ func (o UnaryOperation) extractConditions(request *FetchSpansRequest) {
 	}
 }
 
+// extractConditions of a function call that is used as a boolean, i.e. { startsWith(span.foo, "bar") },
+// filters its attribute with an equivalent regex. The storage layer doesn't evaluate functions, so
+// the second pass must always evaluate the function call itself.
+func (f *FunctionCall) extractConditions(request *FetchSpansRequest) {
+	request.AllConditions = false
+
+	attr, literal, ok := f.attributeAndString()
+	if !ok {
+		f.fetchArguments(request)
+		return
+	}
+
+	var pattern string
+	switch f.Name {
+	case "startsWith":
+		pattern = "(?s)" + regexp.QuoteMeta(literal) + ".*"
+	case "endsWith":
+		pattern = "(?s).*" + regexp.QuoteMeta(literal)
+	case "contains":
+		pattern = "(?s).*" + regexp.QuoteMeta(literal) + ".*"
+	default:
+		f.fetchArguments(request)
+		return
+	}
+
+	request.appendCondition(Condition{
+		Attribute: attr,
+		Op:        OpRegex,
+		Operands:  []Static{NewStaticString(pattern)},
+	})
+}
+
+// extractComparisonConditions extracts the conditions of the function call compared to other with op.
+// A case-insensitive regex is pushed down for lower(attr) = "literal" and upper(attr) = "LITERAL", it
+// may match more values than the comparison but never less, so the second pass is always required.
+// All other comparisons fetch the args.
+func (f *FunctionCall) extractComparisonConditions(op Operator, other FieldExpression, request *FetchSpansRequest) {
+	request.AllConditions = false
+
+	if s, ok := other.(Static); ok && s.Type == TypeString && op == OpEqual && (f.Name == "lower" || f.Name == "upper") {
+		if attr, ok := f.Args[0].(Attribute); ok {
+			request.appendCondition(Condition{
+				Attribute: attr,
+				Op:        OpRegex,
+				Operands:  []Static{NewStaticString("(?i)" + regexp.QuoteMeta(s.EncodeToString(false)))},
+			})
+			return
+		}
+	}
+
+	f.fetchArguments(request)
+	if o, ok := other.(*FunctionCall); ok {
+		o.fetchArguments(request)
+		return
+	}
+	other.extractConditions(request)
+}
+
+// fetchArguments fetches the attributes referenced by the arguments without filtering them.
+func (f *FunctionCall) fetchArguments(request *FetchSpansRequest) {
+	for _, a := range f.Args {
+		if o, ok := a.(*FunctionCall); ok {
+			o.fetchArguments(request)
+			continue
+		}
+		a.extractConditions(request)
+	}
+}
+
+// attributeAndString returns the args of functions like startsWith(span.foo, "bar").
+func (f *FunctionCall) attributeAndString() (Attribute, string, bool) {
+	if len(f.Args) != 2 {
+		return Attribute{}, "", false
+	}
+	attr, ok := f.Args[0].(Attribute)
+	if !ok {
+		return Attribute{}, "", false
+	}
+	s, ok := f.Args[1].(Static)
+	if !ok || s.Type != TypeString {
+		return Attribute{}, "", false
+	}
+	return attr, s.EncodeToString(false), true
+}
+
 func (s Static) extractConditions(*FetchSpansRequest) {
 }
 
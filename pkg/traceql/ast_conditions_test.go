@@ -311,6 +311,105 @@ func TestSelect_extractConditions(t *testing.T) {
 	}
 }
 
+func TestFunctionCall_extractConditions(t *testing.T) {
+	foo := NewScopedAttribute(AttributeScopeSpan, false, "foo")
+	bar := NewScopedAttribute(AttributeScopeSpan, false, "bar")
+
+	tests := []struct {
+		query                string
+		conditions           []Condition
+		secondPassConditions []Condition
+	}{
+		{
+			query: `{ startsWith(span.foo, "a.b") }`,
+			conditions: []Condition{
+				newCondition(foo, OpRegex, NewStaticString(`(?s)a\.b.*`)),
+			},
+		},
+		{
+			query: `{ endsWith(span.foo, "a") || contains(span.bar, "b") }`,
+			conditions: []Condition{
+				newCondition(foo, OpRegex, NewStaticString(`(?s).*a`)),
+				newCondition(bar, OpRegex, NewStaticString(`(?s).*b.*`)),
+			},
+		},
+		{
+			query: `{ lower(span.foo) = "a+b" && span.bar = "c" }`,
+			conditions: []Condition{
+				newCondition(foo, OpRegex, NewStaticString(`(?i)a\+b`)),
+				newCondition(bar, OpEqual, NewStaticString("c")),
+			},
+		},
+		{
+			query: `{ "A" = upper(span.foo) }`,
+			conditions: []Condition{
+				newCondition(foo, OpRegex, NewStaticString(`(?i)A`)),
+			},
+		},
+		{
+			// only equality can be pushed down
+			query: `{ lower(span.foo) != "a" }`,
+			conditions: []Condition{
+				newCondition(foo, OpNone),
+			},
+		},
+		{
+			query: `{ !startsWith(span.foo, "a") }`,
+			conditions: []Condition{
+				newCondition(foo, OpNone),
+			},
+		},
+		{
+			query: `{ startsWith(span.foo, "a") = false }`,
+			conditions: []Condition{
+				newCondition(foo, OpNone),
+			},
+		},
+		{
+			query: `{ contains(lower(span.foo), span.bar) }`,
+			conditions: []Condition{
+				newCondition(foo, OpNone),
+				newCondition(bar, OpNone),
+			},
+		},
+		{
+			query: `{ len(split(span.foo, "/")) > len(span.bar) }`,
+			conditions: []Condition{
+				newCondition(foo, OpNone),
+				newCondition(bar, OpNone),
+			},
+		},
+		{
+			query: `{ } | by(lower(span.foo)) | select(startsWith(span.bar, "a"))`,
+			conditions: []Condition{
+				newCondition(NewIntrinsic(IntrinsicSpanStartTime), OpNone),
+				newCondition(foo, OpNone),
+			},
+			secondPassConditions: []Condition{
+				newCondition(bar, OpNone),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			expr, err := Parse(tt.query)
+			require.NoError(t, err)
+
+			req := &FetchSpansRequest{
+				Conditions:    []Condition{},
+				AllConditions: true,
+			}
+			expr.Pipeline.extractConditions(req)
+
+			assert.Equal(t, tt.conditions, req.Conditions)
+			assert.Equal(t, tt.secondPassConditions, req.SecondPassConditions)
+			// function calls are never evaluated by the storage layer
+			assert.False(t, req.AllConditions, "FetchSpansRequest.AllConditions")
+		})
+	}
+}
+
 func TestMetricsAggregate_extractConditions(t *testing.T) {
 	tests := map[string]struct {
 		first  []Condition
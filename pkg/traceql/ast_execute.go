@@ -882,6 +882,30 @@ func (o UnaryOperation) execute(span Span) (Static, error) {
 	return NewStaticNil(), fmt.Errorf("UnaryOperation has invalid operator %v", o.Op)
 }
 
+func (f *FunctionCall) execute(span Span) (Static, error) {
+	if f.def == nil {
+		return NewStaticNil(), fmt.Errorf("unknown function: %s", f.Name)
+	}
+
+	for i, a := range f.Args {
+		static, err := a.execute(span)
+		if err != nil {
+			return NewStaticNil(), err
+		}
+
+		// missing attributes or attributes of another type
+		if !f.def.args[i].accepts(static.Type) {
+			if f.def.returnType == TypeBoolean {
+				return StaticFalse, nil
+			}
+			return NewStaticNil(), nil
+		}
+		f.args[i] = static
+	}
+
+	return f.def.execute(f.args[:len(f.Args)]), nil
+}
+
 func (s Static) execute(Span) (Static, error) {
 	return s, nil
 }
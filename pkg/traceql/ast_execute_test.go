@@ -249,6 +249,94 @@ func TestGroup(t *testing.T) {
 	}
 }
 
+func TestFunctionCalls(t *testing.T) {
+	span := &mockSpan{
+		attributes: map[Attribute]Static{
+			NewAttribute("route"):  NewStaticString("/API/Users/{id}"),
+			NewAttribute("path"):   NewStaticString("a/b/c"),
+			NewAttribute("name"):   NewStaticString("résumé"),
+			NewAttribute("ints"):   NewStaticIntArray([]int{1, 2, 3}),
+			NewAttribute("number"): NewStaticInt(404),
+		},
+	}
+
+	tests := []struct {
+		query   string
+		matches bool
+	}{
+		{query: `{ lower(.route) = "/api/users/{id}" }`, matches: true},
+		{query: `{ upper(.route) = "/API/USERS/{ID}" }`, matches: true},
+		{query: `{ lower(.route) = .route }`, matches: false},
+		{query: `{ len(.route) = 15 }`, matches: true},
+		{query: `{ len(.name) = 6 }`, matches: true}, // characters, not bytes
+		{query: `{ len(.ints) = 3 }`, matches: true},
+		{query: `{ len(.route) > len(.path) }`, matches: true},
+		{query: `{ substring(.route, 1, 3) = "API" }`, matches: true},
+		{query: `{ substring(.route, 11) = "{id}" }`, matches: true},
+		{query: `{ substring(.route, 11, 100) = "{id}" }`, matches: true},
+		{query: `{ substring(.route, 100) = "" }`, matches: true},
+		{query: `{ substring(.name, 0, 3) = "rés" }`, matches: true},
+		{query: `{ startsWith(.route, "/API") }`, matches: true},
+		{query: `{ startsWith(.route, "/api") }`, matches: false},
+		{query: `{ startsWith(lower(.route), "/api") }`, matches: true},
+		{query: `{ endsWith(.route, "{id}") }`, matches: true},
+		{query: `{ contains(.route, "Users") }`, matches: true},
+		{query: `{ contains(.route, "users") }`, matches: false},
+		{query: `{ !contains(.route, "users") }`, matches: true},
+		{query: `{ split(.path, "/") = "b" }`, matches: true},
+		{query: `{ split(.path, "/") = "b/c" }`, matches: false},
+		{query: `{ len(split(.path, "/")) = 3 }`, matches: true},
+		// missing attributes and attributes of another type
+		{query: `{ lower(.missing) = "" }`, matches: false},
+		{query: `{ lower(.number) = "404" }`, matches: false},
+		{query: `{ startsWith(.missing, "") }`, matches: false},
+		{query: `{ !startsWith(.number, "4") }`, matches: true},
+		{query: `{ len(.number) > 0 }`, matches: false},
+	}
+
+	for _, tt := range tests {
+		tc := evalTC{
+			query: tt.query,
+			input: []*Spanset{
+				{Spans: []Span{span}},
+			},
+			output: []*Spanset{},
+		}
+		if tt.matches {
+			tc.output = tc.input
+		}
+		testEvaluator(t, tc)
+	}
+}
+
+func TestGroupFunctionCall(t *testing.T) {
+	testEvaluator(t, evalTC{
+		"{ } | by(lower(.foo))",
+		[]*Spanset{
+			{Spans: []Span{
+				&mockSpan{id: []byte{1}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("GET")}},
+				&mockSpan{id: []byte{2}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("get")}},
+				&mockSpan{id: []byte{3}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("Post")}},
+			}},
+		},
+		[]*Spanset{
+			{
+				Spans: []Span{
+					&mockSpan{id: []byte{1}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("GET")}},
+					&mockSpan{id: []byte{2}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("get")}},
+				},
+				Attributes: []*SpansetAttribute{{Name: "by(lower(.foo))", Val: NewStaticString("get")}},
+			},
+			{
+				Spans: []Span{
+					&mockSpan{id: []byte{3}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("Post")}},
+				},
+				Attributes: []*SpansetAttribute{{Name: "by(lower(.foo))", Val: NewStaticString("post")}},
+			},
+		},
+	})
+}
+
 func TestCoalesce(t *testing.T) {
 	testCases := []evalTC{
 		{
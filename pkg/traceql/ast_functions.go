@@ -0,0 +1,173 @@
+package traceql
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+type fieldFunctionArgType int
+
+const (
+	fieldFunctionArgString fieldFunctionArgType = iota
+	fieldFunctionArgInt
+	// fieldFunctionArgStringOrArray accepts a string or any array
+	fieldFunctionArgStringOrArray
+)
+
+func (t fieldFunctionArgType) String() string {
+	switch t {
+	case fieldFunctionArgString:
+		return "string"
+	case fieldFunctionArgInt:
+		return "integer"
+	case fieldFunctionArgStringOrArray:
+		return "string or array"
+	}
+	return "unknown"
+}
+
+func (t fieldFunctionArgType) accepts(s StaticType) bool {
+	switch t {
+	case fieldFunctionArgString:
+		return s == TypeString
+	case fieldFunctionArgInt:
+		return s == TypeInt
+	case fieldFunctionArgStringOrArray:
+		return s == TypeString || s.isArray()
+	}
+	return false
+}
+
+type fieldFunctionDef struct {
+	args []fieldFunctionArgType
+	// optionalArgs is the number of trailing args that can be omitted
+	optionalArgs int
+	returnType   StaticType
+	// execute is called with args that are accepted by their argument type
+	execute func(args []Static) Static
+}
+
+// fieldFunctions are the functions that can be called in field expressions, i.e. in span filters,
+// by() and select(). If an argument doesn't resolve to the expected type, for example because the
+// attribute doesn't exist, functions returning a boolean return false and all others return nil.
+var fieldFunctions = map[string]*fieldFunctionDef{
+	"lower": {
+		args:       []fieldFunctionArgType{fieldFunctionArgString},
+		returnType: TypeString,
+		execute: func(args []Static) Static {
+			return NewStaticString(strings.ToLower(args[0].EncodeToString(false)))
+		},
+	},
+	"upper": {
+		args:       []fieldFunctionArgType{fieldFunctionArgString},
+		returnType: TypeString,
+		execute: func(args []Static) Static {
+			return NewStaticString(strings.ToUpper(args[0].EncodeToString(false)))
+		},
+	},
+	"len": {
+		args:       []fieldFunctionArgType{fieldFunctionArgStringOrArray},
+		returnType: TypeInt,
+		execute: func(args []Static) Static {
+			if args[0].Type == TypeString {
+				return NewStaticInt(utf8.RuneCountInString(args[0].EncodeToString(false)))
+			}
+			n := 0
+			for range args[0].Elements() {
+				n++
+			}
+			return NewStaticInt(n)
+		},
+	},
+	"substring": {
+		args:         []fieldFunctionArgType{fieldFunctionArgString, fieldFunctionArgInt, fieldFunctionArgInt},
+		optionalArgs: 1,
+		returnType:   TypeString,
+		execute: func(args []Static) Static {
+			runes := []rune(args[0].EncodeToString(false))
+			start, _ := args[1].Int()
+			start = min(max(start, 0), len(runes))
+			end := len(runes)
+			if len(args) > 2 {
+				length, _ := args[2].Int()
+				end = min(start+max(length, 0), end)
+			}
+			return NewStaticString(string(runes[start:end]))
+		},
+	},
+	"startsWith": {
+		args:       []fieldFunctionArgType{fieldFunctionArgString, fieldFunctionArgString},
+		returnType: TypeBoolean,
+		execute: func(args []Static) Static {
+			return NewStaticBool(strings.HasPrefix(args[0].EncodeToString(false), args[1].EncodeToString(false)))
+		},
+	},
+	"endsWith": {
+		args:       []fieldFunctionArgType{fieldFunctionArgString, fieldFunctionArgString},
+		returnType: TypeBoolean,
+		execute: func(args []Static) Static {
+			return NewStaticBool(strings.HasSuffix(args[0].EncodeToString(false), args[1].EncodeToString(false)))
+		},
+	},
+	"contains": {
+		args:       []fieldFunctionArgType{fieldFunctionArgString, fieldFunctionArgString},
+		returnType: TypeBoolean,
+		execute: func(args []Static) Static {
+			return NewStaticBool(strings.Contains(args[0].EncodeToString(false), args[1].EncodeToString(false)))
+		},
+	},
+	"split": {
+		args:       []fieldFunctionArgType{fieldFunctionArgString, fieldFunctionArgString},
+		returnType: TypeStringArray,
+		execute: func(args []Static) Static {
+			return NewStaticStringArray(strings.Split(args[0].EncodeToString(false), args[1].EncodeToString(false)))
+		},
+	},
+}
+
+// FunctionCall is a call of one of the fieldFunctions in a field expression.
+// Example: { lower(span.http.route) = "/api/users" }
+type FunctionCall struct {
+	Name string
+	Args []FieldExpression
+
+	def  *fieldFunctionDef
+	args []Static // buffer for the executed args
+}
+
+func newFunctionCall(name string, args []FieldExpression) FieldExpression {
+	f := &FunctionCall{
+		Name: name,
+		Args: args,
+		def:  fieldFunctions[name],
+		args: make([]Static, len(args)),
+	}
+
+	// AST rewrite for simplification
+	if !f.referencesSpan() && f.validate() == nil {
+		if simplified, err := f.execute(nil); err == nil {
+			return simplified
+		}
+	}
+
+	return f
+}
+
+// nolint: revive
+func (FunctionCall) __fieldExpression() {}
+
+func (f *FunctionCall) impliedType() StaticType {
+	if f.def == nil {
+		return TypeNil
+	}
+	return f.def.returnType
+}
+
+func (f *FunctionCall) referencesSpan() bool {
+	for _, a := range f.Args {
+		if a.referencesSpan() {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,8 @@ package traceql
 import (
 	"fmt"
 	"math"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -28,10 +30,13 @@ type getExemplar func(Span) (float64, uint64)
 // for the query time range and step, and different implementations for
 // shardable and unshardable pipelines.
 type MetricsAggregate struct {
-	op         MetricsAggregateOp
-	by         []Attribute
-	attr       Attribute
-	floats     []float64
+	op     MetricsAggregateOp
+	by     []Attribute
+	attr   Attribute
+	floats []float64
+	// buckets are the explicit bucket boundaries of histogram_over_time and quantile_over_time,
+	// log2 buckets are used if they are not set
+	buckets    []float64
 	agg        SpanAggregator
 	seriesAgg  SeriesAggregator
 	exemplarFn getExemplar
@@ -54,15 +59,66 @@ func newMetricsAggregateWithAttr(agg MetricsAggregateOp, attr Attribute, by []At
 	}
 }
 
-func newMetricsAggregateQuantileOverTime(attr Attribute, qs []float64, by []Attribute) *MetricsAggregate {
+func newMetricsAggregateQuantileOverTime(attr Attribute, qs []float64, buckets []float64, by []Attribute) *MetricsAggregate {
 	return &MetricsAggregate{
-		op:     metricsAggregateQuantileOverTime,
-		floats: qs,
-		attr:   attr,
-		by:     by,
+		op:      metricsAggregateQuantileOverTime,
+		floats:  qs,
+		buckets: buckets,
+		attr:    attr,
+		by:      by,
 	}
 }
 
+func newMetricsAggregateHistogramOverTime(attr Attribute, buckets []float64, by []Attribute) *MetricsAggregate {
+	return &MetricsAggregate{
+		op:      metricsAggregateHistogramOverTime,
+		buckets: buckets,
+		attr:    attr,
+		by:      by,
+	}
+}
+
+// maxExplicitBuckets is the maximum number of explicit bucket boundaries of a histogram.
+const maxExplicitBuckets = 1000
+
+// newBucketBoundaries returns the bucket boundaries generated by the given function, like the
+// linear and exponential buckets of the Prometheus client.
+func newBucketBoundaries(fn string, args []float64) ([]float64, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("%s buckets expect 3 arguments, got %d", fn, len(args))
+	}
+
+	start, step, count := args[0], args[1], args[2]
+	if count < 1 || count > maxExplicitBuckets || count != math.Trunc(count) {
+		return nil, fmt.Errorf("%s buckets count must be an integer between 1 and %d: %v", fn, maxExplicitBuckets, count)
+	}
+
+	boundaries := make([]float64, int(count))
+	switch fn {
+	case "linear":
+		if step <= 0 {
+			return nil, fmt.Errorf("linear buckets width must be greater than 0: %v", step)
+		}
+		for i := range boundaries {
+			boundaries[i] = start + step*float64(i)
+		}
+	case "exponential":
+		if start <= 0 {
+			return nil, fmt.Errorf("exponential buckets start must be greater than 0: %v", start)
+		}
+		if step <= 1 {
+			return nil, fmt.Errorf("exponential buckets factor must be greater than 1: %v", step)
+		}
+		for i := range boundaries {
+			boundaries[i] = start * math.Pow(step, float64(i))
+		}
+	default:
+		return nil, fmt.Errorf("unknown buckets function: %s", fn)
+	}
+
+	return boundaries, nil
+}
+
 func (a *MetricsAggregate) extractConditions(request *FetchSpansRequest) {
 	// For metrics aggregators based on a span attribute we have to include it
 	includeAttribute := a.attr != (Attribute{}) && !request.HasAttribute(a.attr)
@@ -130,14 +186,14 @@ func (a *MetricsAggregate) init(q *tempopb.QueryRangeRequest, mode AggregateMode
 
 	case metricsAggregateHistogramOverTime:
 		innerAgg = func() VectorAggregator { return NewCountOverTimeAggregator() }
-		byFunc = bucketizeFnFor(a.attr)
+		byFunc = bucketizeFnFor(a.attr, a.buckets)
 		byFuncLabel = internalLabelBucket
 		a.simpleAggregationOp = sumAggregation
 		a.exemplarFn = exemplarNaN // Histogram final series are counts so exemplars are placeholders
 
 	case metricsAggregateQuantileOverTime:
 		innerAgg = func() VectorAggregator { return NewCountOverTimeAggregator() }
-		byFunc = bucketizeFnFor(a.attr)
+		byFunc = bucketizeFnFor(a.attr, a.buckets)
 		byFuncLabel = internalLabelBucket
 		a.simpleAggregationOp = sumAggregation
 		a.exemplarFn = exemplarFnFor(a.attr)
@@ -167,7 +223,11 @@ func (a *MetricsAggregate) init(q *tempopb.QueryRangeRequest, mode AggregateMode
 	a.agg = NewGroupingAggregator(a.op.String(), innerAggFunc, a.by, byFunc, byFuncLabel)
 }
 
-func bucketizeFnFor(attr Attribute) func(Span) (Static, bool) {
+func bucketizeFnFor(attr Attribute, buckets []float64) func(Span) (Static, bool) {
+	if len(buckets) > 0 {
+		return bucketizeExplicit(attr, buckets)
+	}
+
 	switch attr {
 	case IntrinsicDurationAttribute:
 		// Optimal implementation for duration attribute
@@ -214,6 +274,29 @@ func bucketizeAttribute(a Attribute) func(Span) (Static, bool) {
 	}
 }
 
+// bucketizeExplicit returns the upper boundary of the bucket that the value of the attribute falls in,
+// values above the last boundary fall in the +Inf bucket. Like log2 buckets, durations are bucketed in
+// seconds.
+func bucketizeExplicit(a Attribute, boundaries []float64) func(Span) (Static, bool) {
+	return func(s Span) (Static, bool) {
+		f, t := FloatizeAttribute(s, a)
+
+		switch t {
+		case TypeInt, TypeFloat:
+		case TypeDuration:
+			f /= float64(time.Second)
+		default:
+			return NewStaticNil(), false
+		}
+
+		i := sort.SearchFloat64s(boundaries, f)
+		if i == len(boundaries) {
+			return NewStaticFloat(math.Inf(1)), true
+		}
+		return NewStaticFloat(boundaries[i]), true
+	}
+}
+
 func exemplarFnFor(a Attribute) func(Span) (float64, uint64) {
 	switch a {
 	case IntrinsicDurationAttribute:
@@ -256,7 +339,9 @@ func (a *MetricsAggregate) initSum(q *tempopb.QueryRangeRequest) {
 func (a *MetricsAggregate) initFinal(q *tempopb.QueryRangeRequest) {
 	switch a.op {
 	case metricsAggregateQuantileOverTime:
-		a.seriesAgg = NewHistogramAggregator(q, a.floats, q.Exemplars)
+		h := NewHistogramAggregator(q, a.floats, q.Exemplars)
+		h.boundaries = a.buckets
+		a.seriesAgg = h
 	default:
 		// These are simple additions by series
 		a.seriesAgg = NewSimpleCombiner(q, a.simpleAggregationOp)
@@ -321,6 +406,9 @@ func (a *MetricsAggregate) validate() error {
 			// We reserve a spot for the bucket so quantile has 1 less group by
 			return newUnsupportedError(fmt.Sprintf("metrics group by %v values", len(a.by)))
 		}
+		if err := validateBucketBoundaries(a.buckets); err != nil {
+			return err
+		}
 	case metricsAggregateQuantileOverTime:
 		if len(a.by) >= maxGroupBys {
 			// We reserve a spot for the bucket so quantile has 1 less group by
@@ -331,6 +419,9 @@ func (a *MetricsAggregate) validate() error {
 				return fmt.Errorf("quantile must be between 0 and 1: %v", q)
 			}
 		}
+		if err := validateBucketBoundaries(a.buckets); err != nil {
+			return err
+		}
 	default:
 		return newUnsupportedError(fmt.Sprintf("metrics aggregate operation (%v)", a.op))
 	}
@@ -342,6 +433,18 @@ func (a *MetricsAggregate) validate() error {
 	return nil
 }
 
+func validateBucketBoundaries(boundaries []float64) error {
+	if len(boundaries) > maxExplicitBuckets {
+		return fmt.Errorf("at most %d buckets are allowed: %d", maxExplicitBuckets, len(boundaries))
+	}
+	for i := 1; i < len(boundaries); i++ {
+		if boundaries[i] <= boundaries[i-1] {
+			return fmt.Errorf("bucket boundaries must be in increasing order: %v", boundaries)
+		}
+	}
+	return nil
+}
+
 var _ firstStageElement = (*MetricsAggregate)(nil)
 
 // secondStageElement represents operations that are performed
@@ -515,6 +618,96 @@ func (m *MetricsFilter) compare(v float64) bool {
 
 var _ secondStageElement = (*MetricsFilter)(nil)
 
+// SecondStageFunction is a second stage function applied to the merged results of the
+// first stage, i.e. abs(), clamp_min(0) or label_replace(...). The available functions
+// and their arguments are listed in secondStageFunctions.
+// Example: {} | rate() by (resource.service.name) | sort_desc()
+type SecondStageFunction struct {
+	name string
+	args []Static
+
+	// set by init
+	step   float64 // seconds between data points
+	length int
+	regex  *regexp.Regexp
+}
+
+func newSecondStageFunction(name string, args []Static) *SecondStageFunction {
+	return &SecondStageFunction{name: name, args: args}
+}
+
+func (f *SecondStageFunction) String() string {
+	args := make([]string, 0, len(f.args))
+	for _, a := range f.args {
+		args = append(args, a.String())
+	}
+	return f.name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (f *SecondStageFunction) validate() error {
+	def, ok := secondStageFunctions[f.name]
+	if !ok {
+		return fmt.Errorf("unknown second stage function: %s", f.name)
+	}
+
+	if len(f.args) != len(def.args) {
+		return fmt.Errorf("%s() expects %d arguments, got %d", f.name, len(def.args), len(f.args))
+	}
+
+	for i, a := range f.args {
+		if !def.args[i].accepts(a) {
+			return fmt.Errorf("%s() argument %d must be a %s, got %s", f.name, i+1, def.args[i], a.String())
+		}
+	}
+
+	if def.validate != nil {
+		return def.validate(f)
+	}
+	return nil
+}
+
+func (f *SecondStageFunction) init(req *tempopb.QueryRangeRequest) {
+	f.step = time.Duration(req.Step).Seconds()
+	f.length = NewIntervalMapperFromReq(req).IntervalCount()
+	if f.name == labelReplaceFunction {
+		// already checked by validate
+		f.regex, _ = compileLabelReplaceRegex(f.str(3))
+	}
+}
+
+func (f *SecondStageFunction) process(input SeriesSet) SeriesSet {
+	def, ok := secondStageFunctions[f.name]
+	if !ok {
+		// unknown function, we shouldn't reach here
+		return input
+	}
+	return def.process(f, input)
+}
+
+// sortSeries orders the final series for the sort() and sort_desc() functions.
+// The order can't be represented by the SeriesSet and has to be applied on the response.
+func (f *SecondStageFunction) sortSeries(series []*tempopb.TimeSeries) {
+	def, ok := secondStageFunctions[f.name]
+	if !ok || def.order == 0 {
+		return
+	}
+	sortSeriesBySum(series, def.order < 0)
+}
+
+// float returns the i-th argument as a number. Durations are converted to seconds.
+func (f *SecondStageFunction) float(i int) float64 {
+	if d, ok := f.args[i].Duration(); ok {
+		return d.Seconds()
+	}
+	return f.args[i].Float()
+}
+
+func (f *SecondStageFunction) str(i int) string {
+	return f.args[i].EncodeToString(false)
+}
+
+var _ secondStageElement = (*SecondStageFunction)(nil)
+
 // ChainedSecondStage chains multiple second stage elements together.
 // Elements are processed in order, each receiving the output of the previous.
 // Example: {status=error} | rate() | topk(5) > 10
@@ -559,4 +752,14 @@ func (c ChainedSecondStage) process(input SeriesSet) SeriesSet {
 	return input
 }
 
+// sortSeries applies the order of the last sorting element in the chain, if any.
+func (c ChainedSecondStage) sortSeries(series []*tempopb.TimeSeries) {
+	for i := len(c.elements) - 1; i >= 0; i-- {
+		if f, ok := c.elements[i].(*SecondStageFunction); ok && secondStageFunctions[f.name].order != 0 {
+			f.sortSeries(series)
+			return
+		}
+	}
+}
+
 var _ secondStageElement = ChainedSecondStage{}
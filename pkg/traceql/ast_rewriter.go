@@ -58,11 +58,20 @@ func (f *fieldExpressionRewriter) RewriteRoot(r *RootExpr) *RootExpr {
 
 	pipeline, rwCount := f.rewritePipeline(r.Pipeline)
 
+	var linked *Pipeline
+	if r.Linked != nil {
+		p, linkedCount := f.rewritePipeline(*r.Linked)
+		linked = &p
+		rwCount += linkedCount
+	}
+
 	return &RootExpr{
 		Pipeline:           pipeline,
+		Linked:             linked,
 		MetricsPipeline:    r.MetricsPipeline,
 		MetricsSecondStage: r.MetricsSecondStage,
 		Hints:              r.Hints,
+		Offset:             r.Offset,
 		OptimizationCount:  r.OptimizationCount + rwCount,
 	}
 }
@@ -165,6 +174,14 @@ func (f *fieldExpressionRewriter) rewriteFieldExpression(fe FieldExpression) (Fi
 		exp, n := f.rewriteFieldExpression(e.Expression)
 		rwCount += n
 		fe = newUnaryOperation(e.Op, exp)
+	case *FunctionCall:
+		args := make([]FieldExpression, 0, len(e.Args))
+		for _, a := range e.Args {
+			arg, n := f.rewriteFieldExpression(a)
+			rwCount += n
+			args = append(args, arg)
+		}
+		fe = newFunctionCall(e.Name, args)
 	}
 
 	for _, fn := range f.rewriteFunctions {
@@ -9,14 +9,27 @@ import (
 
 func (r RootExpr) String() string {
 	s := strings.Builder{}
-	s.WriteString(r.Pipeline.String())
-	if r.MetricsPipeline != nil {
-		s.WriteString(" | ")
+	if _, ok := r.MetricsPipeline.(*MetricsBinaryOperation); ok {
+		// Binary operations between metrics queries contain their own pipelines.
 		s.WriteString(r.MetricsPipeline.String())
+	} else {
+		s.WriteString(r.Pipeline.String())
+		if r.Linked != nil {
+			s.WriteString(" ->link ")
+			s.WriteString(r.Linked.String())
+		}
+		if r.MetricsPipeline != nil {
+			s.WriteString(" | ")
+			s.WriteString(r.MetricsPipeline.String())
+		}
 	}
 	if r.MetricsSecondStage != nil {
 		s.WriteString(r.MetricsSecondStage.String())
 	}
+	if r.Offset != 0 {
+		s.WriteString(" offset ")
+		s.WriteString(r.Offset.String())
+	}
 	if r.Hints != nil {
 		s.WriteString(" ")
 		s.WriteString(r.Hints.String())
@@ -41,8 +54,8 @@ func (o CoalesceOperation) String() string {
 }
 
 func (o SelectOperation) String() string {
-	s := make([]string, 0, len(o.attrs))
-	for _, e := range o.attrs {
+	s := make([]string, 0, len(o.exprs))
+	for _, e := range o.exprs {
 		s = append(s, e.String())
 	}
 	return "select(" + strings.Join(s, ", ") + ")"
@@ -80,6 +93,14 @@ func (o UnaryOperation) String() string {
 	return unaryOp(o.Op, o.Expression)
 }
 
+func (f *FunctionCall) String() string {
+	args := make([]string, 0, len(f.Args))
+	for _, a := range f.Args {
+		args = append(args, a.String())
+	}
+	return f.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
 func (s Static) String() string {
 	return s.EncodeToString(true)
 }
@@ -203,6 +224,16 @@ func (a MetricsAggregate) String() string {
 			}
 		}
 	}
+	if len(a.buckets) > 0 {
+		s.WriteString(",buckets=[")
+		for i, f := range a.buckets {
+			s.WriteString(strconv.FormatFloat(f, 'f', -1, 64))
+			if i < len(a.buckets)-1 {
+				s.WriteString(",")
+			}
+		}
+		s.WriteString("]")
+	}
 	s.WriteString(")")
 
 	if len(a.by) > 0 {
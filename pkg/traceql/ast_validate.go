@@ -38,6 +38,18 @@ func (r RootExpr) validate() error {
 		}
 	}
 
+	if r.Linked != nil {
+		err := r.Linked.validate()
+		if err != nil {
+			return err
+		}
+
+		// the spans of linked traces don't know their relations
+		if op, ok := structuralOperator(*r.Linked); ok {
+			return newUnsupportedError(fmt.Sprintf("structural operator (%v) after ->link", op))
+		}
+	}
+
 	// extra validation to disallow compare() with second stage functions
 	// for example: `{} | compare({status=error}) | topk(10)` doesn't make sense
 	if r.MetricsPipeline != nil && r.MetricsSecondStage != nil {
@@ -50,6 +62,32 @@ func (r RootExpr) validate() error {
 	return nil
 }
 
+// structuralOperator returns the first operator that relates spans to each other, i.e. `>>`.
+func structuralOperator(e Element) (Operator, bool) {
+	switch x := e.(type) {
+	case Pipeline:
+		for _, e := range x.Elements {
+			if op, ok := structuralOperator(e); ok {
+				return op, true
+			}
+		}
+	case SpansetOperation:
+		if x.Op != OpSpansetAnd && x.Op != OpSpansetUnion {
+			return x.Op, true
+		}
+		if op, ok := structuralOperator(x.LHS); ok {
+			return op, true
+		}
+		return structuralOperator(x.RHS)
+	case ScalarFilter:
+		if op, ok := structuralOperator(x.LHS); ok {
+			return op, true
+		}
+		return structuralOperator(x.RHS)
+	}
+	return 0, false
+}
+
 func (p Pipeline) validate() error {
 	for _, p := range p.Elements {
 		err := p.validate()
@@ -74,10 +112,13 @@ func (o CoalesceOperation) validate() error {
 }
 
 func (o SelectOperation) validate() error {
-	for _, e := range o.attrs {
+	for _, e := range o.exprs {
 		if err := e.validate(); err != nil {
 			return err
 		}
+		if !e.referencesSpan() {
+			return fmt.Errorf("select field expressions must reference the span: %s", o.String())
+		}
 	}
 
 	return nil
@@ -298,6 +339,33 @@ func (o UnaryOperation) validate() error {
 	return nil
 }
 
+func (f *FunctionCall) validate() error {
+	if f.def == nil {
+		return fmt.Errorf("unknown function: %s", f.Name)
+	}
+
+	if len(f.Args) < len(f.def.args)-f.def.optionalArgs || len(f.Args) > len(f.def.args) {
+		if f.def.optionalArgs > 0 {
+			return fmt.Errorf("%s() expects %d to %d arguments, got %d", f.Name, len(f.def.args)-f.def.optionalArgs, len(f.def.args), len(f.Args))
+		}
+		return fmt.Errorf("%s() expects %d arguments, got %d", f.Name, len(f.def.args), len(f.Args))
+	}
+
+	for i, a := range f.Args {
+		if err := a.validate(); err != nil {
+			return err
+		}
+
+		// the type of attributes is only known when the query is executed
+		t := a.impliedType()
+		if t != TypeAttribute && !f.def.args[i].accepts(t) {
+			return fmt.Errorf("%s() argument %d must be a %s, got %s", f.Name, i+1, f.def.args[i], a.String())
+		}
+	}
+
+	return nil
+}
+
 func (s Static) validate() error {
 	return nil
 }
@@ -378,6 +378,11 @@ func (q *QueryRangeCombiner) Response() *tempopb.QueryRangeResponse {
 	return response
 }
 
+// SortSeries applies the order requested by the query, if any, to the series of a response.
+func (q *QueryRangeCombiner) SortSeries(series []*tempopb.TimeSeries) {
+	q.eval.SortSeries(series)
+}
+
 func (q *QueryRangeCombiner) MaxSeriesReached() bool {
 	return q.maxSeriesReached
 }
@@ -92,6 +92,11 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 	meta := SearchMetaConditionsWithout(fetchSpansRequest.Conditions, fetchSpansRequest.AllConditions)
 	fetchSpansRequest.SecondPassConditions = append(fetchSpansRequest.SecondPassConditions, meta...)
 
+	// the table is built from every group, its aggregate filters are evaluated once the groups are
+	// combined in the query-frontend. The traces are filtered per trace.
+	table := NewSearchTableCombiner(rootExpr)
+	pipeline := searchTablePipeline(rootExpr.Pipeline)
+
 	spansetsEvaluated := 0
 	// set up the expression evaluation as a filter to reduce data pulled
 	fetchSpansRequest.SecondPass = func(inSS *Spanset) ([]*Spanset, error) {
@@ -99,7 +104,7 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 			return nil, nil
 		}
 
-		evalSS, err := rootExpr.Pipeline.evaluate([]*Spanset{inSS})
+		evalSS, err := pipeline.evaluate([]*Spanset{inSS})
 		if err != nil {
 			span.RecordError(err, trace.WithAttributes(attribute.String("msg", "pipeline.evaluate")))
 			return nil, err
@@ -110,6 +115,10 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 			return nil, nil
 		}
 
+		if table.Enabled() {
+			table.markTableOnly(evalSS)
+		}
+
 		// reduce all evalSS to their max length to reduce meta data lookups
 		for i := range evalSS {
 			l := len(evalSS[i].Spans)
@@ -146,6 +155,7 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 		Metrics: &tempopb.SearchMetrics{},
 	}
 	combiner := NewMetadataCombiner(int(searchReq.Limit), mostRecent)
+	selectCalls := rootExpr.Pipeline.functionCalls()
 	for {
 		spanset, err := iterator.Next(ctx)
 		if err != nil && !errors.Is(err, io.EOF) {
@@ -156,12 +166,26 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 			break
 		}
 
+		if table.Enabled() {
+			table.addSpanset(spanset)
+			if isTableOnly(spanset) {
+				continue
+			}
+		}
+
+		selectValues(spanset, selectCalls)
+		if rootExpr.Linked != nil {
+			linkValues(spanset)
+		}
 		combiner.addSpanset(spanset)
 		if combiner.IsCompleteFor(TimestampNever) {
 			break
 		}
 	}
 	res.Traces = combiner.Metadata()
+	if table.Enabled() {
+		res.Table = table.PartialRows()
+	}
 
 	span.SetAttributes(attribute.Int("spansets_evaluated", spansetsEvaluated))
 	span.SetAttributes(attribute.Int("spansets_found", len(res.Traces)))
@@ -283,6 +307,80 @@ func (e *Engine) ExecuteTagNames(
 	return fetcher.Fetch(ctx, autocompleteReq, cb)
 }
 
+// selectValues computes the function calls of select() for the spans of the spanset. This can't
+// be done when the pipeline is evaluated because the selected attributes are fetched afterwards.
+func selectValues(spanset *Spanset, calls []*FunctionCall) {
+	// spansets are pooled, always reset the values of a previous query
+	spanset.selected = nil
+	if len(calls) == 0 {
+		return
+	}
+
+	spanset.selected = make([][]*SpansetAttribute, len(spanset.Spans))
+	for i, span := range spanset.Spans {
+		for _, c := range calls {
+			v, err := c.execute(span)
+			if err != nil || v.Type == TypeNil {
+				continue
+			}
+			spanset.selected[i] = append(spanset.selected[i], &SpansetAttribute{Name: c.String(), Val: v})
+		}
+	}
+}
+
+// linkValues adds the links of the spans of the spanset to their selected values, they are followed
+// by ResolveLinks once the search is complete. A span can have several links so they are returned as
+// arrays: link:traceID and link:spanID hold the trace and span IDs of the links in the same order.
+// A link without a span ID has an empty span ID.
+func linkValues(spanset *Spanset) {
+	if spanset.selected == nil {
+		spanset.selected = make([][]*SpansetAttribute, len(spanset.Spans))
+	}
+
+	for i, span := range spanset.Spans {
+		var traceIDs, spanIDs []string
+		// the attributes of every link are returned together, a link ends when the attribute of
+		// the next one is found
+		hasTraceID, hasSpanID := false, false
+		next := func() {
+			if hasTraceID {
+				if !hasSpanID {
+					spanIDs = append(spanIDs, "")
+				}
+			} else if hasSpanID {
+				// a link without a trace ID can't be followed
+				spanIDs = spanIDs[:len(spanIDs)-1]
+			}
+			hasTraceID, hasSpanID = false, false
+		}
+		span.AllAttributesFunc(func(a Attribute, s Static) {
+			switch a.Intrinsic {
+			case IntrinsicLinkTraceID:
+				if hasTraceID {
+					next()
+				}
+				traceIDs = append(traceIDs, s.EncodeToString(false))
+				hasTraceID = true
+			case IntrinsicLinkSpanID:
+				if hasSpanID {
+					next()
+				}
+				spanIDs = append(spanIDs, s.EncodeToString(false))
+				hasSpanID = true
+			}
+		})
+		next()
+		if len(traceIDs) == 0 {
+			continue
+		}
+
+		spanset.selected[i] = append(spanset.selected[i],
+			&SpansetAttribute{Name: IntrinsicLinkTraceIDAttribute.Name, Val: NewStaticStringArray(traceIDs)},
+			&SpansetAttribute{Name: IntrinsicLinkSpanIDAttribute.Name, Val: NewStaticStringArray(spanIDs)},
+		)
+	}
+}
+
 func asTraceSearchMetadata(spanset *Spanset) *tempopb.TraceSearchMetadata {
 	metadata := &tempopb.TraceSearchMetadata{
 		TraceID:           util.TraceIDToHexString(spanset.TraceID),
@@ -301,7 +399,7 @@ func asTraceSearchMetadata(spanset *Spanset) *tempopb.TraceSearchMetadata {
 		}
 	}
 
-	for _, span := range spanset.Spans {
+	for i, span := range spanset.Spans {
 		tempopbSpan := &tempopb.Span{
 			SpanID:            util.SpanIDToHexString(span.ID()),
 			StartTimeUnixNano: span.StartTimeUnixNanos(),
@@ -315,7 +413,17 @@ func asTraceSearchMetadata(spanset *Spanset) *tempopb.TraceSearchMetadata {
 			tempopbSpan.Name = name.EncodeToString(false)
 		}
 
+		var selected []*SpansetAttribute
+		if i < len(spanset.selected) {
+			selected = spanset.selected[i]
+		}
+
 		for attribute, static := range atts {
+			if isSelected(selected, attribute.Name) {
+				// the selected value replaces the attribute
+				continue
+			}
+
 			if attribute.Intrinsic == IntrinsicName ||
 				attribute.Intrinsic == IntrinsicDuration ||
 				attribute.Intrinsic == IntrinsicTraceDuration ||
@@ -337,6 +445,13 @@ func asTraceSearchMetadata(spanset *Spanset) *tempopb.TraceSearchMetadata {
 			tempopbSpan.Attributes = append(tempopbSpan.Attributes, keyValue)
 		}
 
+		for _, att := range selected {
+			tempopbSpan.Attributes = append(tempopbSpan.Attributes, &common_v1.KeyValue{
+				Key:   att.Name,
+				Value: att.Val.AsAnyValue(),
+			})
+		}
+
 		metadata.SpanSet.Spans = append(metadata.SpanSet.Spans, tempopbSpan)
 	}
 
@@ -367,6 +482,15 @@ func asTraceSearchMetadata(spanset *Spanset) *tempopb.TraceSearchMetadata {
 	return metadata
 }
 
+func isSelected(selected []*SpansetAttribute, name string) bool {
+	for _, att := range selected {
+		if att.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func unixSecToNano(ts uint32) uint64 {
 	return uint64(ts) * uint64(time.Second/time.Nanosecond)
 }
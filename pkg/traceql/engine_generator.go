@@ -0,0 +1,115 @@
+package traceql
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	trace_v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+// GeneratorQueryType is the type of the metric that is generated by a GeneratorQuery.
+type GeneratorQueryType int
+
+const (
+	// GeneratorQueryCounter is incremented by the observed value.
+	GeneratorQueryCounter GeneratorQueryType = iota
+	// GeneratorQueryHistogram observes the value.
+	GeneratorQueryHistogram
+)
+
+// GeneratorQuery is a TraceQL metrics query that is evaluated incrementally on the spans received by
+// the metrics-generator instead of on the spans of a time range. Spans are only evaluated together
+// with the spans of the same trace that are received in the same batch, so structural operators are
+// not supported.
+type GeneratorQuery struct {
+	typ       GeneratorQueryType
+	pipeline  *protoPipeline
+	aggregate *MetricsAggregate
+}
+
+// CompileGeneratorQuery compiles a metrics query that can be evaluated by the metrics-generator. Only
+// rate, count_over_time, sum_over_time, quantile_over_time and histogram_over_time are supported,
+// the other functions can't be computed from counters and histograms.
+func CompileGeneratorQuery(query string) (*GeneratorQuery, error) {
+	expr, pipeline, err := compileProtoPipeline(query, "by the metrics-generator")
+	if err != nil {
+		return nil, err
+	}
+
+	aggregate, ok := expr.MetricsPipeline.(*MetricsAggregate)
+	if !ok {
+		return nil, errors.New("query must be a metrics query with a single metrics function")
+	}
+	if expr.MetricsSecondStage != nil {
+		return nil, errors.New("second stage functions are not supported by the metrics-generator")
+	}
+
+	q := &GeneratorQuery{
+		pipeline:  pipeline,
+		aggregate: aggregate,
+	}
+
+	switch aggregate.op {
+	case metricsAggregateRate, metricsAggregateCountOverTime, metricsAggregateSumOverTime:
+		q.typ = GeneratorQueryCounter
+	case metricsAggregateQuantileOverTime, metricsAggregateHistogramOverTime:
+		q.typ = GeneratorQueryHistogram
+	default:
+		return nil, fmt.Errorf("%v is not supported by the metrics-generator", aggregate.op)
+	}
+
+	return q, nil
+}
+
+// Type returns the type of the metric generated by the query.
+func (q *GeneratorQuery) Type() GeneratorQueryType {
+	return q.typ
+}
+
+// By returns the attributes the metric is grouped by.
+func (q *GeneratorQuery) By() []Attribute {
+	return q.aggregate.by
+}
+
+// Buckets returns the explicit bucket boundaries of the histogram, nil if they are not set.
+func (q *GeneratorQuery) Buckets() []float64 {
+	return q.aggregate.buckets
+}
+
+// Observe evaluates the query on the spans of the batches and calls fn with the value of every
+// matching span. Counters are incremented by 1 for rate and count_over_time. Durations are observed
+// in seconds. Spans without a numeric value are skipped.
+func (q *GeneratorQuery) Observe(batches []*trace_v1.ResourceSpans, fn func(traceID []byte, s Span, value float64)) error {
+	evalSS, err := q.pipeline.evaluate(batches)
+	if err != nil {
+		return err
+	}
+
+	for _, ss := range evalSS {
+		for _, s := range ss.Spans {
+			value, ok := q.value(s)
+			if !ok {
+				continue
+			}
+			fn(ss.TraceID, s, value)
+		}
+	}
+	return nil
+}
+
+func (q *GeneratorQuery) value(s Span) (float64, bool) {
+	switch q.aggregate.op {
+	case metricsAggregateRate, metricsAggregateCountOverTime:
+		return 1, true
+	}
+
+	v, typ := FloatizeAttribute(s, q.aggregate.attr)
+	switch typ {
+	case TypeInt, TypeFloat:
+		return v, true
+	case TypeDuration:
+		return v / float64(time.Second), true
+	}
+	return 0, false
+}
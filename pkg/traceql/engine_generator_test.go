@@ -0,0 +1,107 @@
+package traceql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	resource_v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	trace_v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestCompileGeneratorQuery(t *testing.T) {
+	tcs := []struct {
+		query   string
+		typ     GeneratorQueryType
+		buckets []float64
+		err     string
+	}{
+		{query: `{ } | rate()`, typ: GeneratorQueryCounter},
+		{query: `{ } | count_over_time() by (span.foo)`, typ: GeneratorQueryCounter},
+		{query: `{ } | sum_over_time(span.bytes)`, typ: GeneratorQueryCounter},
+		{query: `{ } | quantile_over_time(duration, .99)`, typ: GeneratorQueryHistogram},
+		{query: `{ } | histogram_over_time(duration, buckets=[0.1, 1])`, typ: GeneratorQueryHistogram, buckets: []float64{0.1, 1}},
+		{query: `{ }`, err: "query must be a metrics query with a single metrics function"},
+		{query: `({ } | rate()) / ({ } | rate())`, err: "query must be a metrics query with a single metrics function"},
+		{query: `{ } | rate() | topk(10)`, err: "second stage functions are not supported by the metrics-generator"},
+		{query: `{ } | min_over_time(duration)`, err: "min_over_time is not supported by the metrics-generator"},
+		{query: `{ } > { } | rate()`, err: "structural operator (>) is not supported by the metrics-generator"},
+		{query: `{ .a = }`, err: "parse error"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := CompileGeneratorQuery(tc.query)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.typ, q.Type())
+			require.Equal(t, tc.buckets, q.Buckets())
+		})
+	}
+}
+
+func TestGeneratorQueryObserve(t *testing.T) {
+	str := func(k, v string) *common_v1.KeyValue {
+		return &common_v1.KeyValue{Key: k, Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: v}}}
+	}
+	span := func(traceID, spanID byte, system, db string, durationMs uint64) *trace_v1.Span {
+		return &trace_v1.Span{
+			TraceId:           []byte{traceID},
+			SpanId:            []byte{spanID},
+			StartTimeUnixNano: 1000,
+			EndTimeUnixNano:   1000 + durationMs*1_000_000,
+			Attributes:        []*common_v1.KeyValue{str("db.system", system), str("db.name", db)},
+		}
+	}
+	batches := []*trace_v1.ResourceSpans{{
+		Resource: &resource_v1.Resource{Attributes: []*common_v1.KeyValue{str("service.name", "api")}},
+		ScopeSpans: []*trace_v1.ScopeSpans{{
+			Spans: []*trace_v1.Span{
+				span(1, 1, "postgres", "users", 100),
+				span(1, 2, "mysql", "users", 200),
+				span(2, 3, "postgres", "orders", 500),
+			},
+		}},
+	}}
+
+	q, err := CompileGeneratorQuery(`{ span.db.system = "postgres" } | quantile_over_time(duration, .99) by (span.db.name, resource.service.name)`)
+	require.NoError(t, err)
+	require.Equal(t, []Attribute{
+		NewScopedAttribute(AttributeScopeSpan, false, "db.name"),
+		NewScopedAttribute(AttributeScopeResource, false, "service.name"),
+	}, q.By())
+
+	type observation struct {
+		traceID []byte
+		labels  []string
+		value   float64
+	}
+	var observations []observation
+	err = q.Observe(batches, func(traceID []byte, s Span, value float64) {
+		var labels []string
+		for _, a := range q.By() {
+			v, _ := s.AttributeFor(a)
+			labels = append(labels, v.EncodeToString(false))
+		}
+		observations = append(observations, observation{traceID: traceID, labels: labels, value: value})
+	})
+	require.NoError(t, err)
+	require.Equal(t, []observation{
+		{traceID: []byte{1}, labels: []string{"users", "api"}, value: 0.1},
+		{traceID: []byte{2}, labels: []string{"orders", "api"}, value: 0.5},
+	}, observations)
+
+	// counters are incremented by 1
+	q, err = CompileGeneratorQuery(`{ resource.service.name = "api" } | rate()`)
+	require.NoError(t, err)
+
+	var total float64
+	require.NoError(t, q.Observe(batches, func(_ []byte, _ Span, value float64) {
+		total += value
+	}))
+	require.Equal(t, 3.0, total)
+}
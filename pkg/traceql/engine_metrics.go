@@ -184,6 +184,24 @@ func AlignRequest(req *tempopb.QueryRangeRequest) {
 	}
 }
 
+// QueryRangeOffset returns the offset in nanoseconds by which the time range of the request
+// is shifted into the past. For range queries it must be a multiple of the step, so that the
+// shifted intervals line up with the intervals of the request.
+func QueryRangeOffset(req *tempopb.QueryRangeRequest, offset time.Duration) (uint64, error) {
+	if offset <= 0 {
+		return 0, nil
+	}
+
+	o := uint64(offset.Nanoseconds())
+	if IsInstant(req) || req.Step == 0 {
+		return o, nil
+	}
+	if o%req.Step != 0 {
+		return 0, fmt.Errorf("offset %s must be a multiple of the step %s", offset, time.Duration(req.Step)) //nolint:gosec
+	}
+	return o, nil
+}
+
 func AlignEndToLeft(req *tempopb.QueryRangeRequest) {
 	if IsInstant(req) {
 		return
@@ -1041,12 +1059,36 @@ func (e *Engine) CompileMetricsQueryRange(req *tempopb.QueryRangeRequest, opts .
 		return nil, fmt.Errorf("compiling query: %w", err)
 	}
 
-	needsFullTrace := expr.NeedsFullTrace()
-
 	if metricsPipeline == nil {
 		return nil, fmt.Errorf("not a metrics query")
 	}
 
+	if b, ok := metricsPipeline.(*MetricsBinaryOperation); ok {
+		// Each query of a binary operation is fetched and evaluated on its own,
+		// and the results are combined in the query-frontend.
+		me := &MetricsEvaluator{
+			storageReq:      storageReq,
+			metricsPipeline: b,
+		}
+		for _, q := range b.queries {
+			qExpr := q.rootExpr(expr.Hints)
+			qReq := &FetchSpansRequest{
+				AllConditions: true,
+			}
+			qExpr.extractConditions(qReq)
+			me.queries = append(me.queries, compileMetricsEvaluator(req, qExpr, qExpr.Pipeline.evaluate, qReq, cfg))
+		}
+		return me, nil
+	}
+
+	return compileMetricsEvaluator(req, expr, eval, storageReq, cfg), nil
+}
+
+// compileMetricsEvaluator sets up the evaluator and storage request for a single metrics query.
+func compileMetricsEvaluator(req *tempopb.QueryRangeRequest, expr *RootExpr, eval SpansetFilterFunc, storageReq *FetchSpansRequest, cfg compileMetricsQueryRangeConfig) *MetricsEvaluator {
+	metricsPipeline := expr.MetricsPipeline
+	needsFullTrace := expr.NeedsFullTrace()
+
 	// Debug sampling hints, remove once we settle on approach.
 	if traceSample, traceSampleOk := expr.Hints.GetFloat(HintTraceSample, cfg.allowUnsafeQueryHints); traceSampleOk {
 		storageReq.TraceSampler = newProbablisticSampler(traceSample)
@@ -1169,7 +1211,7 @@ func (e *Engine) CompileMetricsQueryRange(req *tempopb.QueryRangeRequest, opts .
 
 	optimize(storageReq)
 
-	return me, nil
+	return me
 }
 
 // optimize numerous things within the request that is specific to metrics.
@@ -1279,6 +1321,9 @@ type MetricsEvaluator struct {
 	metricsPipeline                 firstStageElement
 	spansTotal, spansDeduped, bytes uint64
 	mtx                             sync.Mutex
+
+	// queries holds an evaluator for each query of a binary operation.
+	queries []*MetricsEvaluator
 }
 
 func (e *MetricsEvaluator) FetchSpansRequest() FetchSpansRequest {
@@ -1300,6 +1345,15 @@ func timeRangeOverlap(reqStart, reqEnd, dataStart, dataEnd uint64) float64 {
 // uses the known time range of the data for last-minute optimizations. Time range is unix nanos
 
 func (e *MetricsEvaluator) Do(ctx context.Context, f SpansetFetcher, fetcherStart, fetcherEnd uint64, maxSeries int) error {
+	if len(e.queries) > 0 {
+		for _, q := range e.queries {
+			if err := q.Do(ctx, f, fetcherStart, fetcherEnd, maxSeries); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	if !e.needsFullTrace && e.spanOnlyFetch {
 		// The query can operate at a span level so attempt.
 		// This is faster. If not supported then fallback to spanset level.
@@ -1515,10 +1569,29 @@ func (e *MetricsEvaluator) DoSpansOnly(ctx context.Context, f SpansetFetcher, fe
 }
 
 func (e *MetricsEvaluator) Length() int {
+	if len(e.queries) > 0 {
+		l := 0
+		for _, q := range e.queries {
+			l += q.Length()
+		}
+		return l
+	}
+
 	return e.metricsPipeline.length()
 }
 
 func (e *MetricsEvaluator) Metrics() (uint64, uint64, uint64) {
+	if len(e.queries) > 0 {
+		var bytes, spansTotal, spansDeduped uint64
+		for _, q := range e.queries {
+			b, t, d := q.Metrics()
+			bytes += b
+			spansTotal += t
+			spansDeduped += d
+		}
+		return bytes, spansTotal, spansDeduped
+	}
+
 	e.mtx.Lock()
 	defer e.mtx.Unlock()
 
@@ -1526,6 +1599,14 @@ func (e *MetricsEvaluator) Metrics() (uint64, uint64, uint64) {
 }
 
 func (e *MetricsEvaluator) Results() SeriesSet {
+	if len(e.queries) > 0 {
+		results := make([]SeriesSet, len(e.queries))
+		for i, q := range e.queries {
+			results[i] = q.Results()
+		}
+		return withQueryLabels(results)
+	}
+
 	e.mtx.Lock()
 	defer e.mtx.Unlock()
 
@@ -1601,6 +1682,13 @@ func (m *MetricsFrontendEvaluator) Results() SeriesSet {
 	return results
 }
 
+// SortSeries orders the final series if the query ends with a sorting function like sort_desc().
+func (m *MetricsFrontendEvaluator) SortSeries(series []*tempopb.TimeSeries) {
+	if s, ok := m.metricsSecondStage.(interface{ sortSeries([]*tempopb.TimeSeries) }); ok {
+		s.sortSeries(series)
+	}
+}
+
 func (m *MetricsFrontendEvaluator) Length() int {
 	m.mtx.Lock()
 	defer m.mtx.Unlock()
@@ -1875,8 +1963,10 @@ type histSeries struct {
 }
 
 type HistogramAggregator struct {
-	ss             map[string]histSeries
-	qs             []float64
+	ss map[string]histSeries
+	qs []float64
+	// boundaries are the explicit bucket boundaries of the histogram, log2 buckets are used if not set
+	boundaries     []float64
 	intervalMapper IntervalMapper
 	exemplarLimit  uint32
 	// Reusable buffer for all label processing
@@ -1994,7 +2084,7 @@ func (h *HistogramAggregator) Results() SeriesSet {
 
 	quantileValues := make([]float64, len(h.qs))
 	for i, q := range h.qs {
-		quantileValues[i] = Log2Quantile(q, buckets)
+		quantileValues[i] = h.quantile(q, buckets)
 	}
 
 	// Build results using the calculated quantile values
@@ -2026,7 +2116,7 @@ func (h *HistogramAggregator) Results() SeriesSet {
 				}
 
 				// Use sorted buckets for quantile calculation
-				ts.Values[i] = Log2Quantile(q, in.hist[i].Buckets)
+				ts.Values[i] = h.quantile(q, in.hist[i].Buckets)
 			}
 
 			// Select exemplars for this quantile using simplified assignment logic
@@ -2066,6 +2156,13 @@ func (h *HistogramAggregator) assignExemplarToQuantile(exemplarValue float64, qu
 	return bestIdx
 }
 
+func (h *HistogramAggregator) quantile(p float64, buckets []HistogramBucket) float64 {
+	if len(h.boundaries) > 0 {
+		return ExplicitQuantile(p, buckets, h.boundaries)
+	}
+	return Log2Quantile(p, buckets)
+}
+
 func (h *HistogramAggregator) Length() int {
 	return len(h.ss) * len(h.qs)
 }
@@ -2158,6 +2255,58 @@ func Log2QuantileWithBucket(p float64, buckets []HistogramBucket) (float64, int)
 	return math.Pow(2, minV+(maxV-minV)*interp), bucket
 }
 
+// ExplicitQuantile returns the quantile given buckets labeled with the upper boundaries of explicit
+// bucket boundaries and counts. Like histogram_quantile() in Prometheus it interpolates linearly within
+// the bucket, the first bucket starts at 0 and the quantile is the highest boundary if it falls in the
+// +Inf bucket.
+func ExplicitQuantile(p float64, buckets []HistogramBucket, boundaries []float64) float64 {
+	if math.IsNaN(p) ||
+		p < 0 ||
+		p > 1 ||
+		len(buckets) == 0 {
+		return 0
+	}
+
+	totalCount := 0
+	for _, b := range buckets {
+		totalCount += b.Count
+	}
+
+	if totalCount == 0 {
+		return 0
+	}
+
+	rank := p * float64(totalCount)
+
+	var total float64
+	for _, b := range buckets {
+		if b.Count == 0 {
+			continue
+		}
+
+		if total+float64(b.Count) < rank {
+			total += float64(b.Count)
+			continue
+		}
+
+		if math.IsInf(b.Max, 1) {
+			return boundaries[len(boundaries)-1]
+		}
+
+		// The lower boundary is the prior boundary, the buckets of the input can be sparse
+		var minV float64
+		if i := sort.SearchFloat64s(boundaries, b.Max); i > 0 {
+			minV = boundaries[i-1]
+		} else if b.Max <= 0 {
+			return b.Max
+		}
+
+		return minV + (b.Max-minV)*(rank-total)/float64(b.Count)
+	}
+
+	return boundaries[len(boundaries)-1]
+}
+
 var (
 	_ SeriesAggregator = (*SimpleAggregator)(nil)
 	_ SeriesAggregator = (*HistogramAggregator)(nil)
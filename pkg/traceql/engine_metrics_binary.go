@@ -0,0 +1,597 @@
+package traceql
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// internalLabelMetaQuery identifies which operand query of a binary operation
+// a series belongs to while the results are still being combined across shards.
+const internalLabelMetaQuery = "__meta_query"
+
+// metricsExpression is a node in a tree of metrics queries combined with binary operators.
+// Example: ({status=error} | rate()) / ({} | rate())
+type metricsExpression interface {
+	Element
+	// evaluate computes the final series of this node given the final
+	// results of all operand queries, indexed by query.
+	evaluate(results []SeriesSet) SeriesSet
+}
+
+// metricsQuery is a single parenthesized metrics query used as an operand of a
+// binary operation. It is evaluated like any other metrics query, and the final
+// results are combined in the query-frontend.
+type metricsQuery struct {
+	idx         int
+	pipeline    Pipeline
+	firstStage  firstStageElement
+	secondStage secondStageElement
+}
+
+func newMetricsQuery(p Pipeline, first firstStageElement, second ChainedSecondStage) *metricsQuery {
+	q := &metricsQuery{
+		pipeline:   p,
+		firstStage: first,
+	}
+	if len(second.elements) > 0 {
+		q.secondStage = second
+	}
+	return q
+}
+
+func (q *metricsQuery) String() string {
+	s := strings.Builder{}
+	s.WriteString("(")
+	s.WriteString(q.pipeline.String())
+	s.WriteString(" | ")
+	s.WriteString(q.firstStage.String())
+	if q.secondStage != nil {
+		s.WriteString(q.secondStage.String())
+	}
+	s.WriteString(")")
+	return s.String()
+}
+
+func (q *metricsQuery) validate() error {
+	if err := q.pipeline.validate(); err != nil {
+		return err
+	}
+	if err := q.firstStage.validate(); err != nil {
+		return err
+	}
+	if q.secondStage != nil {
+		if err := q.secondStage.validate(); err != nil {
+			return err
+		}
+	}
+
+	// The operand results carry an extra internal label to tell them apart
+	// while combining, on top of the bucket or meta type label that some
+	// aggregates use.
+	var by []Attribute
+	switch x := q.firstStage.(type) {
+	case *MetricsAggregate:
+		by = x.by
+	case *averageOverTimeAggregator:
+		by = x.by
+	case *varianceOverTimeAggregator:
+		by = x.by
+	case *MetricsCompare:
+		return fmt.Errorf("`compare()` cannot be used in binary operations")
+	}
+	if len(by) > maxGroupBys-2 {
+		return newUnsupportedError(fmt.Sprintf("metrics group by %v values in binary operations", len(by)))
+	}
+
+	return nil
+}
+
+// rootExpr returns the standalone version of this query so it can be
+// compiled and evaluated independently of the other operands.
+func (q *metricsQuery) rootExpr(hints *Hints) *RootExpr {
+	return &RootExpr{
+		Pipeline:           q.pipeline,
+		MetricsPipeline:    q.firstStage,
+		MetricsSecondStage: q.secondStage,
+		Hints:              hints,
+	}
+}
+
+func (q *metricsQuery) evaluate(results []SeriesSet) SeriesSet {
+	return results[q.idx]
+}
+
+var _ metricsExpression = (*metricsQuery)(nil)
+
+type vectorMatchCardinality int
+
+const (
+	matchOneToOne vectorMatchCardinality = iota
+	matchManyToOne
+	matchOneToMany
+)
+
+// VectorMatching controls how series on both sides of a binary operation are paired.
+// It mirrors the on()/ignoring() and group_left/group_right modifiers of PromQL.
+type VectorMatching struct {
+	on          bool
+	attrs       []Attribute
+	cardinality vectorMatchCardinality
+}
+
+func newVectorMatching(on bool, attrs []Attribute, cardinality vectorMatchCardinality) *VectorMatching {
+	return &VectorMatching{
+		on:          on,
+		attrs:       attrs,
+		cardinality: cardinality,
+	}
+}
+
+func (v *VectorMatching) String() string {
+	s := strings.Builder{}
+	if v.on {
+		s.WriteString("on(")
+	} else {
+		s.WriteString("ignoring(")
+	}
+	for i, a := range v.attrs {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		s.WriteString(a.String())
+	}
+	s.WriteString(")")
+	switch v.cardinality {
+	case matchManyToOne:
+		s.WriteString(" group_left")
+	case matchOneToMany:
+		s.WriteString(" group_right")
+	}
+	return s.String()
+}
+
+// signature returns the labels used to pair the series with series on the other side.
+// The metric name is never considered.
+func (v *VectorMatching) signature(ls Labels) string {
+	out := make(Labels, 0, len(ls))
+	for _, l := range ls {
+		if l.Name == labels.MetricName {
+			continue
+		}
+		if v != nil && v.on != v.has(l.Name) {
+			continue
+		}
+		out = append(out, l)
+	}
+	return out.String()
+}
+
+func (v *VectorMatching) has(name string) bool {
+	for _, a := range v.attrs {
+		if a.String() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// MetricsBinaryOperation combines the results of two metrics expressions with an
+// arithmetic or comparison operator. The operand queries are evaluated independently
+// through all the layers, and the binary operations are applied in the final layer
+// of the query-frontend once the operand results are complete.
+type MetricsBinaryOperation struct {
+	op       Operator
+	lhs      metricsExpression
+	rhs      metricsExpression
+	scalar   float64
+	isScalar bool
+	matching *VectorMatching
+
+	// The following are only set on the root operation.
+	queries []*metricsQuery
+	mode    AggregateMode
+}
+
+func newMetricsBinaryOperation(op Operator, lhs, rhs metricsExpression, matching *VectorMatching) *MetricsBinaryOperation {
+	return &MetricsBinaryOperation{
+		op:       op,
+		lhs:      lhs,
+		rhs:      rhs,
+		matching: matching,
+	}
+}
+
+func newMetricsScalarOperation(op Operator, lhs metricsExpression, scalar float64) *MetricsBinaryOperation {
+	return &MetricsBinaryOperation{
+		op:       op,
+		lhs:      lhs,
+		scalar:   scalar,
+		isScalar: true,
+	}
+}
+
+// newRootExprWithMetricsExpression builds the root for a tree of metrics queries. A single
+// parenthesized query is unwrapped into a regular metrics query.
+func newRootExprWithMetricsExpression(e metricsExpression) *RootExpr {
+	switch x := e.(type) {
+	case *metricsQuery:
+		return x.rootExpr(nil)
+	case *MetricsBinaryOperation:
+		x.queries = x.collectQueries(nil)
+		for i, q := range x.queries {
+			q.idx = i
+		}
+		return &RootExpr{
+			MetricsPipeline: x,
+		}
+	}
+	return nil
+}
+
+func (b *MetricsBinaryOperation) collectQueries(queries []*metricsQuery) []*metricsQuery {
+	for _, e := range []metricsExpression{b.lhs, b.rhs} {
+		switch x := e.(type) {
+		case *metricsQuery:
+			queries = append(queries, x)
+		case *MetricsBinaryOperation:
+			queries = x.collectQueries(queries)
+		}
+	}
+	return queries
+}
+
+func (b *MetricsBinaryOperation) String() string {
+	s := strings.Builder{}
+	s.WriteString(wrapMetricsExpression(b.lhs))
+	s.WriteString(" ")
+	s.WriteString(b.op.String())
+	s.WriteString(" ")
+	if b.isScalar {
+		s.WriteString(formatMetricsValue(b.scalar))
+		return s.String()
+	}
+	if b.matching != nil {
+		s.WriteString(b.matching.String())
+		s.WriteString(" ")
+	}
+	s.WriteString(wrapMetricsExpression(b.rhs))
+	return s.String()
+}
+
+func wrapMetricsExpression(e metricsExpression) string {
+	if _, ok := e.(*MetricsBinaryOperation); ok {
+		return "(" + e.String() + ")"
+	}
+	return e.String()
+}
+
+func formatMetricsValue(v float64) string {
+	if v == float64(int(v)) && !math.IsInf(v, 0) && !math.IsNaN(v) {
+		return fmt.Sprintf("%d", int(v))
+	}
+	return fmt.Sprintf("%g", v)
+}
+
+func (b *MetricsBinaryOperation) validate() error {
+	switch b.op {
+	case OpAdd, OpSub, OpMult, OpDiv, OpMod, OpPower:
+	case OpEqual, OpNotEqual, OpLess, OpLessEqual, OpGreater, OpGreaterEqual:
+	default:
+		return fmt.Errorf("unsupported metrics binary operation: %s", b.op.String())
+	}
+
+	if err := b.lhs.validate(); err != nil {
+		return err
+	}
+	if !b.isScalar {
+		if err := b.rhs.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *MetricsBinaryOperation) extractConditions(request *FetchSpansRequest) {
+	// Each query is fetched separately with its own conditions, see CompileMetricsQueryRange.
+	// This is the combined set of conditions across all of them, and
+	// the queries match different spans so not all conditions are required.
+	for _, q := range b.queries {
+		q.pipeline.extractConditions(request)
+		q.firstStage.extractConditions(request)
+	}
+	request.AllConditions = false
+}
+
+func (b *MetricsBinaryOperation) init(req *tempopb.QueryRangeRequest, mode AggregateMode) {
+	b.mode = mode
+	for _, q := range b.queries {
+		q.firstStage.init(req, mode)
+		if q.secondStage != nil && mode == AggregateModeFinal {
+			q.secondStage.init(req)
+		}
+	}
+}
+
+// observe is a no-op. Spans are observed by a separate evaluator per query
+// because each query requires its own fetch.
+func (b *MetricsBinaryOperation) observe(Span) {}
+
+func (b *MetricsBinaryOperation) observeExemplar(Span) {}
+
+// observeSeries routes the incoming series to their query using the internal label.
+func (b *MetricsBinaryOperation) observeSeries(in []*tempopb.TimeSeries) {
+	byQuery := make([][]*tempopb.TimeSeries, len(b.queries))
+
+	for _, ts := range in {
+		idx := -1
+		for i, l := range ts.Labels {
+			if l.Key != internalLabelMetaQuery {
+				continue
+			}
+			idx = int(l.Value.GetIntValue())
+
+			cp := *ts
+			cp.Labels = slices.Delete(slices.Clone(ts.Labels), i, i+1)
+			ts = &cp
+			break
+		}
+		if idx < 0 || idx >= len(b.queries) {
+			continue
+		}
+		byQuery[idx] = append(byQuery[idx], ts)
+	}
+
+	for i, ss := range byQuery {
+		if len(ss) > 0 {
+			b.queries[i].firstStage.observeSeries(ss)
+		}
+	}
+}
+
+func (b *MetricsBinaryOperation) result(multiplier float64) SeriesSet {
+	results := make([]SeriesSet, len(b.queries))
+	for i, q := range b.queries {
+		results[i] = q.firstStage.result(multiplier)
+	}
+
+	if b.mode != AggregateModeFinal {
+		// Intermediate results are passed on to the next layer untouched.
+		return withQueryLabels(results)
+	}
+
+	for i, q := range b.queries {
+		if q.secondStage != nil {
+			results[i] = q.secondStage.process(results[i])
+		}
+	}
+	return b.evaluate(results)
+}
+
+func (b *MetricsBinaryOperation) length() int {
+	l := 0
+	for _, q := range b.queries {
+		l += q.firstStage.length()
+	}
+	return l
+}
+
+// withQueryLabels merges the results of each query into a single set, labelling
+// each series with the index of the query it came from.
+func withQueryLabels(results []SeriesSet) SeriesSet {
+	out := SeriesSet{}
+	for i, ss := range results {
+		queryLabel := Label{Name: internalLabelMetaQuery, Value: NewStaticInt(i)}
+		for _, s := range ss {
+			s.Labels = s.Labels.Add(queryLabel)
+			out[s.Labels.MapKey()] = s
+		}
+	}
+	return out
+}
+
+func (b *MetricsBinaryOperation) evaluate(results []SeriesSet) SeriesSet {
+	lhs := b.lhs.evaluate(results)
+	if b.isScalar {
+		return b.evaluateScalar(lhs)
+	}
+	return b.evaluateVector(lhs, b.rhs.evaluate(results))
+}
+
+func (b *MetricsBinaryOperation) isComparison() bool {
+	switch b.op {
+	case OpEqual, OpNotEqual, OpLess, OpLessEqual, OpGreater, OpGreaterEqual:
+		return true
+	}
+	return false
+}
+
+func (b *MetricsBinaryOperation) evaluateScalar(lhs SeriesSet) SeriesSet {
+	out := make(SeriesSet, len(lhs))
+
+	for _, s := range sortedSeries(lhs) {
+		values := make([]float64, len(s.Values))
+		hasValue := false
+		for i, v := range s.Values {
+			values[i] = b.apply(v, b.scalar)
+			if !math.IsNaN(values[i]) {
+				hasValue = true
+			}
+		}
+		if !hasValue {
+			continue
+		}
+		b.add(out, s, values)
+	}
+
+	return out
+}
+
+func (b *MetricsBinaryOperation) evaluateVector(lhs, rhs SeriesSet) SeriesSet {
+	cardinality := matchOneToOne
+	if b.matching != nil {
+		cardinality = b.matching.cardinality
+	}
+
+	// Index the "one" side by signature. Signatures that occur more than
+	// once are ambiguous and dropped, like a failed match.
+	many, one := lhs, rhs
+	if cardinality == matchOneToMany {
+		many, one = rhs, lhs
+	}
+	oneBySig := make(map[string]*TimeSeries, len(one))
+	for _, s := range sortedSeries(one) {
+		sig := b.matching.signature(s.Labels)
+		if _, ok := oneBySig[sig]; ok {
+			oneBySig[sig] = nil
+			continue
+		}
+		oneBySig[sig] = &s
+	}
+
+	// For one-to-one matching each series on the other side must also be unique.
+	seen := make(map[string]struct{}, len(many))
+
+	out := make(SeriesSet, len(many))
+	for _, s := range sortedSeries(many) {
+		sig := b.matching.signature(s.Labels)
+		match := oneBySig[sig]
+		if match == nil {
+			continue
+		}
+		if cardinality == matchOneToOne {
+			if _, ok := seen[sig]; ok {
+				continue
+			}
+			seen[sig] = struct{}{}
+		}
+
+		l, r := s, *match
+		if cardinality == matchOneToMany {
+			l, r = r, l
+		}
+
+		values := make([]float64, min(len(l.Values), len(r.Values)))
+		hasValue := false
+		for i := range values {
+			values[i] = b.apply(l.Values[i], r.Values[i])
+			if !math.IsNaN(values[i]) {
+				hasValue = true
+			}
+		}
+		if !hasValue {
+			continue
+		}
+		b.add(out, s, values)
+	}
+
+	return out
+}
+
+// add inserts the result series. Comparisons filter the series and keep it as is.
+// Arithmetic drops the metric name like Prometheus, and in the case of on() with
+// one-to-one matching only the matching labels are kept.
+func (b *MetricsBinaryOperation) add(out SeriesSet, s TimeSeries, values []float64) {
+	if b.isComparison() {
+		key := s.Labels.MapKey()
+		if _, ok := out[key]; ok {
+			return
+		}
+		out[key] = TimeSeries{
+			Labels:    s.Labels,
+			Values:    values,
+			Exemplars: s.Exemplars,
+		}
+		return
+	}
+
+	onlyMatching := b.matching != nil && b.matching.on && b.matching.cardinality == matchOneToOne
+
+	lbls := make(Labels, 0, len(s.Labels))
+	for _, l := range s.Labels {
+		if l.Name == labels.MetricName {
+			continue
+		}
+		if onlyMatching && !b.matching.has(l.Name) {
+			continue
+		}
+		lbls = append(lbls, l)
+	}
+	if len(lbls) == 0 {
+		// Prometheus-style series always have at least one label.
+		lbls = s.Labels
+	}
+
+	key := lbls.MapKey()
+	if _, ok := out[key]; ok {
+		return
+	}
+	out[key] = TimeSeries{
+		Labels: lbls,
+		Values: values,
+	}
+}
+
+// apply the operator to the pair of values. Comparisons return the left value
+// when true and NaN otherwise, which filters out the data point.
+func (b *MetricsBinaryOperation) apply(l, r float64) float64 {
+	if math.IsNaN(l) || math.IsNaN(r) {
+		return math.NaN()
+	}
+
+	switch b.op {
+	case OpAdd:
+		return l + r
+	case OpSub:
+		return l - r
+	case OpMult:
+		return l * r
+	case OpDiv:
+		return l / r
+	case OpMod:
+		return math.Mod(l, r)
+	case OpPower:
+		return math.Pow(l, r)
+	}
+
+	var keep bool
+	switch b.op {
+	case OpEqual:
+		keep = l == r
+	case OpNotEqual:
+		keep = l != r
+	case OpLess:
+		keep = l < r
+	case OpLessEqual:
+		keep = l <= r
+	case OpGreater:
+		keep = l > r
+	case OpGreaterEqual:
+		keep = l >= r
+	}
+	if keep {
+		return l
+	}
+	return math.NaN()
+}
+
+// sortedSeries returns the series ordered by labels so that the results are deterministic.
+func sortedSeries(ss SeriesSet) []TimeSeries {
+	out := make([]TimeSeries, 0, len(ss))
+	for _, s := range ss {
+		out = append(out, s)
+	}
+	slices.SortFunc(out, func(a, b TimeSeries) int {
+		return strings.Compare(a.Labels.String(), b.Labels.String())
+	})
+	return out
+}
+
+var (
+	_ firstStageElement = (*MetricsBinaryOperation)(nil)
+	_ metricsExpression = (*MetricsBinaryOperation)(nil)
+)
@@ -0,0 +1,330 @@
+package traceql
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsBinaryOperationParse(t *testing.T) {
+	tcs := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query:    `({status=error} | rate()) / ({} | rate())`,
+			expected: `({ status = error } | rate()) / ({ true } | rate())`,
+		},
+		{
+			query:    `({status=error} | rate() by (resource.service.name)) / on(resource.service.name) group_left ({} | rate() by (resource.service.name))`,
+			expected: `({ status = error } | rate()by(resource.service.name)) / on(resource.service.name) group_left ({ true } | rate()by(resource.service.name))`,
+		},
+		{
+			query:    `(({status=error} | count_over_time()) + ({kind=server} | count_over_time())) * 100`,
+			expected: `(({ status = error } | count_over_time()) + ({ kind = server } | count_over_time())) * 100`,
+		},
+		{
+			query:    `({} | rate() | topk(5)) > ignoring(span.foo) ({} | rate() by (span.foo)) with (sample=true)`,
+			expected: `({ true } | rate() | topk(5)) > ignoring(span.foo) ({ true } | rate()by(span.foo)) with(sample=true)`,
+		},
+		{
+			// A single parenthesized metrics query is a regular metrics query
+			query:    `({} | rate())`,
+			expected: `{ true } | rate()`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			expr, err := Parse(tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, expr.String())
+
+			// Round trip
+			expr2, err := Parse(expr.String())
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, expr2.String())
+		})
+	}
+}
+
+func TestMetricsBinaryOperationValidate(t *testing.T) {
+	tcs := []struct {
+		query string
+		err   string
+	}{
+		{
+			query: `({} | compare({status=error})) / ({} | rate())`,
+			err:   "`compare()` cannot be used in binary operations",
+		},
+		{
+			query: `({} | rate() by (.a,.b,.c,.d)) / ({} | rate())`,
+			err:   "metrics group by 4 values in binary operations not yet supported",
+		},
+		{
+			query: `({} | rate() | topk(0)) / ({} | rate())`,
+			err:   errInvalidLimit.Error(),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			_, _, _, _, _, err := Compile(tc.query)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestMetricsBinaryOperationQueryRange(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: 1,
+		End:   uint64(3 * time.Second),
+		Step:  uint64(1 * time.Second),
+	}
+
+	var spans []Span
+	for _, ts := range []int{1, 2, 3} {
+		st := uint64(time.Duration(ts) * time.Second)
+		spans = append(spans,
+			newMockSpan(nil).WithStartTime(st).WithSpanString("foo", "bar").WithSpanString("result", "error"),
+			newMockSpan(nil).WithStartTime(st).WithSpanString("foo", "bar").WithSpanString("result", "ok"),
+			newMockSpan(nil).WithStartTime(st).WithSpanString("foo", "bar").WithSpanString("result", "ok"),
+			newMockSpan(nil).WithStartTime(st).WithSpanString("foo", "bar").WithSpanString("result", "ok"),
+			newMockSpan(nil).WithStartTime(st).WithSpanString("foo", "baz").WithSpanString("result", "error"),
+			newMockSpan(nil).WithStartTime(st).WithSpanString("foo", "baz").WithSpanString("result", "ok"),
+		)
+	}
+
+	tcs := []struct {
+		query    string
+		expected SeriesSet
+	}{
+		{
+			query: `({span.result="error"} | count_over_time() by (span.foo)) / ({} | count_over_time() by (span.foo))`,
+			expected: SeriesSet{
+				LabelsFromArgs("span.foo", "bar").MapKey(): {
+					Labels: LabelsFromArgs("span.foo", "bar"),
+					Values: []float64{0.25, 0.25, 0.25},
+				},
+				LabelsFromArgs("span.foo", "baz").MapKey(): {
+					Labels: LabelsFromArgs("span.foo", "baz"),
+					Values: []float64{0.5, 0.5, 0.5},
+				},
+			},
+		},
+		{
+			query: `({span.result="error"} | count_over_time()) / ({} | count_over_time()) * 100`,
+			expected: SeriesSet{
+				LabelsFromArgs("__name__", "count_over_time").MapKey(): {
+					Labels: LabelsFromArgs("__name__", "count_over_time"),
+					Values: []float64{100.0 / 3, 100.0 / 3, 100.0 / 3},
+				},
+			},
+		},
+		{
+			query: `({span.result="error"} | count_over_time() by (span.foo)) / ({} | count_over_time() by (span.foo)) > 0.3`,
+			expected: SeriesSet{
+				LabelsFromArgs("span.foo", "baz").MapKey(): {
+					Labels: LabelsFromArgs("span.foo", "baz"),
+					Values: []float64{0.5, 0.5, 0.5},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			req := *req
+			req.Query = tc.query
+
+			layer1, err := NewEngine().CompileMetricsQueryRange(&req)
+			require.NoError(t, err)
+			require.NoError(t, layer1.Do(context.Background(), &replayFetcher{spans: spans}, 0, 0, 0))
+
+			layer2, err := NewEngine().CompileMetricsQueryRangeNonRaw(&req, AggregateModeSum)
+			require.NoError(t, err)
+			layer2.ObserveSeries(layer1.Results().ToProto(&req))
+
+			result, _, err := processLayer3(&req, layer2.Results())
+			require.NoError(t, err)
+			require.Len(t, result, len(tc.expected))
+			for k, expected := range tc.expected {
+				actual, ok := result[k]
+				require.True(t, ok, "missing series %v", expected.Labels)
+				require.Equal(t, expected.Labels, actual.Labels)
+				require.InDeltaSlice(t, expected.Values, actual.Values, 0.0001)
+			}
+		})
+	}
+}
+
+// TestMetricsBinaryOperationJobs checks that the intermediate results of the operands are combined
+// across jobs before the operator is applied, and not applied per job.
+func TestMetricsBinaryOperationJobs(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Query: `({span.result="error"} | count_over_time() by (span.foo)) / ({} | count_over_time() by (span.foo))`,
+		Start: 1,
+		End:   uint64(2 * time.Second),
+		Step:  uint64(1 * time.Second),
+	}
+	st := uint64(time.Second)
+
+	// the first job only has the error span, the second job only the ok spans
+	jobs := [][]Span{
+		{
+			newMockSpan(nil).WithStartTime(st).WithSpanString("foo", "bar").WithSpanString("result", "error"),
+		},
+		{
+			newMockSpan(nil).WithStartTime(st).WithSpanString("foo", "bar").WithSpanString("result", "ok"),
+			newMockSpan(nil).WithStartTime(st).WithSpanString("foo", "bar").WithSpanString("result", "ok"),
+			newMockSpan(nil).WithStartTime(st).WithSpanString("foo", "baz").WithSpanString("result", "ok"),
+		},
+	}
+
+	var results []SeriesSet
+	for _, spans := range jobs {
+		layer1, err := NewEngine().CompileMetricsQueryRange(req)
+		require.NoError(t, err)
+		require.NoError(t, layer1.Do(context.Background(), &replayFetcher{spans: spans}, 0, 0, 0))
+
+		layer2, err := NewEngine().CompileMetricsQueryRangeNonRaw(req, AggregateModeSum)
+		require.NoError(t, err)
+		layer2.ObserveSeries(layer1.Results().ToProto(req))
+		results = append(results, layer2.Results())
+	}
+
+	result, _, err := processLayer3(req, results...)
+	require.NoError(t, err)
+
+	// baz has no errors in any job, so there is nothing to divide
+	require.Len(t, result, 1)
+	actual, ok := result[LabelsFromArgs("span.foo", "bar").MapKey()]
+	require.True(t, ok)
+	require.InDelta(t, 1.0/3, actual.Values[0], 0.0001)
+}
+
+func TestMetricsBinaryOperationVectorMatching(t *testing.T) {
+	series := func(values []float64, args ...any) TimeSeries {
+		return TimeSeries{Labels: LabelsFromArgs(args...), Values: values}
+	}
+	set := func(ts ...TimeSeries) SeriesSet {
+		ss := SeriesSet{}
+		for _, s := range ts {
+			ss[s.Labels.MapKey()] = s
+		}
+		return ss
+	}
+
+	byOp := set(
+		series([]float64{4, 8}, ".svc", "a", ".op", "x"),
+		series([]float64{2, math.NaN()}, ".svc", "a", ".op", "y"),
+		series([]float64{1, 1}, ".svc", "b", ".op", "x"),
+	)
+	bySvc := set(
+		series([]float64{8, 8}, ".svc", "a"),
+	)
+
+	tcs := []struct {
+		name     string
+		query    string
+		lhs, rhs SeriesSet
+		expected []TimeSeries
+	}{
+		{
+			name:  "group_left",
+			query: `({} | rate() by (.svc, .op)) / on(.svc) group_left ({} | rate() by (.svc))`,
+			lhs:   byOp,
+			rhs:   bySvc,
+			expected: []TimeSeries{
+				series([]float64{0.5, 1}, ".svc", "a", ".op", "x"),
+				series([]float64{0.25, math.NaN()}, ".svc", "a", ".op", "y"),
+			},
+		},
+		{
+			name:  "group_right",
+			query: `({} | rate() by (.svc)) - ignoring(.op) group_right ({} | rate() by (.svc, .op))`,
+			lhs:   bySvc,
+			rhs:   byOp,
+			expected: []TimeSeries{
+				series([]float64{4, 0}, ".svc", "a", ".op", "x"),
+				series([]float64{6, math.NaN()}, ".svc", "a", ".op", "y"),
+			},
+		},
+		{
+			name:  "one to one on",
+			query: `({} | rate() by (.svc, .op)) + on(.op) ({} | rate() by (.op))`,
+			lhs: set(
+				series([]float64{1, 2}, ".svc", "a", ".op", "x"),
+			),
+			rhs: set(
+				series([]float64{10, 20}, ".op", "x"),
+				series([]float64{10, 20}, ".op", "y"),
+			),
+			expected: []TimeSeries{
+				series([]float64{11, 22}, ".op", "x"),
+			},
+		},
+		{
+			name:     "no match",
+			query:    `({} | rate() by (.svc, .op)) / ({} | rate() by (.svc))`,
+			lhs:      byOp,
+			rhs:      bySvc,
+			expected: []TimeSeries{},
+		},
+		{
+			name:  "comparison keeps labels",
+			query: `({} | rate() by (.svc, .op)) >= on(.svc) group_left ({} | rate() by (.svc)) / 2`,
+			lhs:   byOp,
+			rhs:   bySvc,
+			expected: []TimeSeries{
+				series([]float64{4, 8}, ".svc", "a", ".op", "x"),
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := Parse(tc.query)
+			require.NoError(t, err)
+
+			b, ok := expr.MetricsPipeline.(*MetricsBinaryOperation)
+			require.True(t, ok)
+
+			result := b.evaluate([]SeriesSet{tc.lhs, tc.rhs})
+			require.Len(t, result, len(tc.expected))
+			for _, expected := range tc.expected {
+				actual, ok := result[expected.Labels.MapKey()]
+				require.True(t, ok, "missing series %v", expected.Labels)
+				expectSeriesValues(t, actual.Values, expected.Values)
+			}
+		})
+	}
+}
+
+// replayFetcher returns the same spans on every fetch, so that it can
+// be used by multiple queries.
+type replayFetcher struct {
+	spans []Span
+}
+
+func (r *replayFetcher) Fetch(_ context.Context, request FetchSpansRequest) (FetchSpansResponse, error) {
+	spans := make([]Span, len(r.spans))
+	copy(spans, r.spans)
+
+	return FetchSpansResponse{
+		Results: &MockSpanSetIterator{
+			results: []*Spanset{{Spans: spans}},
+			filter:  request.SecondPass,
+		},
+		Bytes: func() uint64 { return 0 },
+	}, nil
+}
+
+func (r *replayFetcher) FetchSpans(context.Context, FetchSpansRequest) (FetchSpansOnlyResponse, error) {
+	return FetchSpansOnlyResponse{}, util.ErrUnsupported
+}
@@ -0,0 +1,358 @@
+package traceql
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+const (
+	labelReplaceFunction = "label_replace"
+	absentFunction       = "absent"
+)
+
+type secondStageArgType int
+
+const (
+	secondStageArgNumber secondStageArgType = iota
+	secondStageArgDuration
+	secondStageArgString
+)
+
+func (t secondStageArgType) String() string {
+	switch t {
+	case secondStageArgNumber:
+		return "number"
+	case secondStageArgDuration:
+		return "duration"
+	case secondStageArgString:
+		return "string"
+	}
+	return "unknown"
+}
+
+func (t secondStageArgType) accepts(s Static) bool {
+	switch t {
+	case secondStageArgNumber:
+		// durations are accepted as seconds, like in metrics filters
+		return s.Type == TypeInt || s.Type == TypeFloat || s.Type == TypeDuration
+	case secondStageArgDuration:
+		return s.Type == TypeDuration
+	case secondStageArgString:
+		return s.Type == TypeString
+	}
+	return false
+}
+
+type secondStageFunctionDef struct {
+	args     []secondStageArgType
+	validate func(f *SecondStageFunction) error
+	process  func(f *SecondStageFunction, input SeriesSet) SeriesSet
+	// order is set for functions that sort the final series: 1 ascending, -1 descending.
+	order int
+}
+
+// secondStageFunctions are the functions that can be applied to the results of a metrics query,
+// after the results of all jobs have been combined in the query-frontend.
+var secondStageFunctions = map[string]secondStageFunctionDef{
+	"abs": {
+		process: func(_ *SecondStageFunction, input SeriesSet) SeriesSet {
+			return mapSeriesValues(input, true, func(in []float64) []float64 {
+				for i, v := range in {
+					in[i] = math.Abs(v)
+				}
+				return in
+			})
+		},
+	},
+	"clamp_min": {
+		args: []secondStageArgType{secondStageArgNumber},
+		process: func(f *SecondStageFunction, input SeriesSet) SeriesSet {
+			limit := f.float(0)
+			return mapSeriesValues(input, true, func(in []float64) []float64 {
+				for i, v := range in {
+					if v < limit {
+						in[i] = limit
+					}
+				}
+				return in
+			})
+		},
+	},
+	"clamp_max": {
+		args: []secondStageArgType{secondStageArgNumber},
+		process: func(f *SecondStageFunction, input SeriesSet) SeriesSet {
+			limit := f.float(0)
+			return mapSeriesValues(input, true, func(in []float64) []float64 {
+				for i, v := range in {
+					if v > limit {
+						in[i] = limit
+					}
+				}
+				return in
+			})
+		},
+	},
+	"increase": {
+		process: func(f *SecondStageFunction, input SeriesSet) SeriesSet {
+			return mapSeriesValues(input, false, func(in []float64) []float64 {
+				return increase(in, f.step)
+			})
+		},
+	},
+	"delta": {
+		process: func(_ *SecondStageFunction, input SeriesSet) SeriesSet {
+			return mapSeriesValues(input, false, delta)
+		},
+	},
+	"deriv": {
+		process: func(f *SecondStageFunction, input SeriesSet) SeriesSet {
+			return mapSeriesValues(input, false, func(in []float64) []float64 {
+				return deriv(in, f.step)
+			})
+		},
+	},
+	"predict_linear": {
+		args: []secondStageArgType{secondStageArgDuration},
+		process: func(f *SecondStageFunction, input SeriesSet) SeriesSet {
+			return mapSeriesValues(input, false, func(in []float64) []float64 {
+				return predictLinear(in, f.step, f.float(0))
+			})
+		},
+	},
+	"sort": {
+		process: func(_ *SecondStageFunction, input SeriesSet) SeriesSet { return input },
+		order:   1,
+	},
+	"sort_desc": {
+		process: func(_ *SecondStageFunction, input SeriesSet) SeriesSet { return input },
+		order:   -1,
+	},
+	absentFunction: {
+		process: func(f *SecondStageFunction, input SeriesSet) SeriesSet { return absent(input, f.length) },
+	},
+	labelReplaceFunction: {
+		args: []secondStageArgType{secondStageArgString, secondStageArgString, secondStageArgString, secondStageArgString},
+		validate: func(f *SecondStageFunction) error {
+			if f.str(0) == "" {
+				return fmt.Errorf("label_replace() destination label must not be empty")
+			}
+			if _, err := compileLabelReplaceRegex(f.str(3)); err != nil {
+				return fmt.Errorf("label_replace() invalid regex: %w", err)
+			}
+			return nil
+		},
+		process: func(f *SecondStageFunction, input SeriesSet) SeriesSet {
+			return labelReplace(input, f.str(0), f.str(1), f.str(2), f.regex)
+		},
+	},
+}
+
+// mapSeriesValues replaces the values of every series with the output of fn. Exemplars are
+// only kept when the function doesn't change the meaning of the values.
+func mapSeriesValues(input SeriesSet, keepExemplars bool, fn func(in []float64) []float64) SeriesSet {
+	for k, s := range input {
+		s.Values = fn(s.Values)
+		if !keepExemplars {
+			s.Exemplars = nil
+		}
+		input[k] = s
+	}
+	return input
+}
+
+// increase returns the increase since the start of the range of a per-second rate, i.e. the
+// output of rate(). Every value is the running sum of value * step, so the last value is the
+// total over the range.
+func increase(in []float64, step float64) []float64 {
+	out := make([]float64, len(in))
+	total := math.NaN()
+	for i, v := range in {
+		if !math.IsNaN(v) {
+			if math.IsNaN(total) {
+				total = 0
+			}
+			total += v * step
+		}
+		out[i] = total
+	}
+	return out
+}
+
+// delta returns the difference between every value and the first value of the range, like
+// PromQL's delta() with a range that starts at the beginning of the query. Unlike PromQL, the
+// result isn't extrapolated to the edges of the range. Missing values stay NaN.
+func delta(in []float64) []float64 {
+	out := make([]float64, len(in))
+	first := math.NaN()
+	for i, v := range in {
+		if math.IsNaN(first) {
+			first = v
+		}
+		out[i] = v - first
+	}
+	return out
+}
+
+// deriv returns the per-second rate of change between consecutive values, step seconds apart.
+// The first value is NaN, as is any value next to a missing one.
+func deriv(in []float64, step float64) []float64 {
+	out := make([]float64, len(in))
+	for i := range in {
+		if i == 0 || step == 0 {
+			out[i] = math.NaN()
+			continue
+		}
+		out[i] = (in[i] - in[i-1]) / step
+	}
+	return out
+}
+
+// predictLinear predicts the value of the series t seconds after each data point, using a
+// simple linear regression over all the values up to that point.
+func predictLinear(in []float64, step, t float64) []float64 {
+	out := make([]float64, len(in))
+
+	var n, sumX, sumY, sumXX, sumXY float64
+	for i, v := range in {
+		x := float64(i) * step
+		if !math.IsNaN(v) {
+			n++
+			sumX += x
+			sumY += v
+			sumXX += x * x
+			sumXY += x * v
+		}
+
+		d := n*sumXX - sumX*sumX
+		if n < 2 || d == 0 {
+			out[i] = math.NaN()
+			continue
+		}
+
+		slope := (n*sumXY - sumX*sumY) / d
+		intercept := (sumY - slope*sumX) / n
+		out[i] = intercept + slope*(x+t)
+	}
+	return out
+}
+
+// absent returns a single series with the value 1 at every interval where none of the input
+// series have a value, or an empty set if there is data in every interval.
+func absent(input SeriesSet, length int) SeriesSet {
+	values := make([]float64, length)
+	for i := range values {
+		values[i] = 1
+	}
+	for _, s := range input {
+		for i, v := range s.Values {
+			if i < length && !math.IsNaN(v) {
+				values[i] = math.NaN()
+			}
+		}
+	}
+
+	hasValue := false
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			hasValue = true
+			break
+		}
+	}
+	if !hasValue {
+		return SeriesSet{}
+	}
+
+	ls := LabelsFromArgs(labels.MetricName, absentFunction)
+	return SeriesSet{
+		ls.MapKey(): {
+			Labels: ls,
+			Values: values,
+		},
+	}
+}
+
+func compileLabelReplaceRegex(regex string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + regex + ")$")
+}
+
+// labelReplace follows the semantics of the PromQL function. If the regex matches the value of
+// the source label, the destination label is set to the expanded replacement. An empty
+// replacement removes the destination label. Series that collide after the replacement are
+// dropped, keeping the first one in label order.
+func labelReplace(input SeriesSet, dst, replacement, src string, regex *regexp.Regexp) SeriesSet {
+	result := make(SeriesSet, len(input))
+
+	for _, s := range sortedSeries(input) {
+		srcValue := ""
+		for _, l := range s.Labels {
+			if l.Name == src {
+				srcValue = l.Value.EncodeToString(false)
+				break
+			}
+		}
+
+		if matches := regex.FindStringSubmatchIndex(srcValue); matches != nil {
+			value := string(regex.ExpandString(nil, replacement, srcValue, matches))
+			s.Labels = replaceLabel(s.Labels, dst, value)
+		}
+
+		key := s.Labels.MapKey()
+		if _, ok := result[key]; ok {
+			continue
+		}
+		result[key] = s
+	}
+
+	return result
+}
+
+// replaceLabel returns a copy of the labels with the label set to the value. An empty
+// value removes the label. Labels are left unchanged if adding would exceed the max group bys.
+func replaceLabel(ls Labels, name, value string) Labels {
+	out := make(Labels, 0, len(ls)+1)
+	found := false
+	for _, l := range ls {
+		if l.Name != name {
+			out = append(out, l)
+			continue
+		}
+		found = true
+		if value != "" {
+			out = append(out, Label{Name: name, Value: NewStaticString(value)})
+		}
+	}
+
+	if !found && value != "" {
+		if len(out) >= maxGroupBys {
+			return ls
+		}
+		out = append(out, Label{Name: name, Value: NewStaticString(value)})
+	}
+	return out
+}
+
+// sortSeriesBySum orders the series by the sum of their samples. Series with equal sums keep
+// their current order.
+func sortSeriesBySum(series []*tempopb.TimeSeries, desc bool) {
+	sum := func(s *tempopb.TimeSeries) float64 {
+		total := 0.0
+		for _, sample := range s.Samples {
+			if !math.IsNaN(sample.Value) {
+				total += sample.Value
+			}
+		}
+		return total
+	}
+
+	sort.SliceStable(series, func(i, j int) bool {
+		if desc {
+			return sum(series[i]) > sum(series[j])
+		}
+		return sum(series[i]) < sum(series[j])
+	})
+}
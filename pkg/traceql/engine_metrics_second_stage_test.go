@@ -0,0 +1,238 @@
+package traceql
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecondStageFunctionValidate(t *testing.T) {
+	tcs := []struct {
+		query string
+		err   string
+	}{
+		{query: `{} | rate() | abs()`},
+		{query: `{} | rate() | clamp_min(1s)`},
+		{query: `{} | rate() | predict_linear(1h) | sort()`},
+		{query: `{} | rate() | label_replace("a", "$1", "span.foo", "(.*)")`},
+		{
+			query: `{} | rate() | foo()`,
+			err:   "unknown second stage function: foo",
+		},
+		{
+			query: `{} | rate() | abs(1)`,
+			err:   "abs() expects 0 arguments, got 1",
+		},
+		{
+			query: `{} | rate() | clamp_max("a")`,
+			err:   "clamp_max() argument 1 must be a number, got `a`",
+		},
+		{
+			query: `{} | rate() | predict_linear(10)`,
+			err:   "predict_linear() argument 1 must be a duration, got 10",
+		},
+		{
+			query: `{} | rate() | label_replace("", "$1", "span.foo", "(.*)")`,
+			err:   "label_replace() destination label must not be empty",
+		},
+		{
+			query: `{} | rate() | label_replace("a", "$1", "span.foo", "(.*")`,
+			err:   "label_replace() invalid regex: error parsing regexp: missing closing ): `^(?:(.*)$`",
+		},
+		{
+			query: `{} | compare({status=error}) | sort()`,
+			err:   "`compare()` cannot be used with second stage functions",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			_, _, _, _, _, err := Compile(tc.query)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestSecondStageFunctions(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: 1,
+		End:   uint64(4 * time.Second),
+		Step:  uint64(2 * time.Second),
+	}
+	nan := math.NaN()
+
+	series := func(values []float64, args ...any) TimeSeries {
+		return TimeSeries{Labels: LabelsFromArgs(args...), Values: values}
+	}
+
+	tcs := []struct {
+		function string
+		in       []TimeSeries
+		expected []TimeSeries
+	}{
+		{
+			function: `abs()`,
+			in:       []TimeSeries{series([]float64{-1, nan}, "a", "x")},
+			expected: []TimeSeries{series([]float64{1, nan}, "a", "x")},
+		},
+		{
+			function: `clamp_min(2)`,
+			in:       []TimeSeries{series([]float64{1, 3}, "a", "x")},
+			expected: []TimeSeries{series([]float64{2, 3}, "a", "x")},
+		},
+		{
+			function: `clamp_max(2.5)`,
+			in:       []TimeSeries{series([]float64{1, 3}, "a", "x")},
+			expected: []TimeSeries{series([]float64{1, 2.5}, "a", "x")},
+		},
+		{
+			function: `increase()`,
+			in:       []TimeSeries{series([]float64{nan, 1, nan, 2}, "a", "x")},
+			expected: []TimeSeries{series([]float64{nan, 2, 2, 6}, "a", "x")},
+		},
+		{
+			function: `delta()`,
+			in:       []TimeSeries{series([]float64{nan, 1, 4, nan, 2}, "a", "x")},
+			expected: []TimeSeries{series([]float64{nan, 0, 3, nan, 1}, "a", "x")},
+		},
+		{
+			// the same input as deriv() below, with a step of 2s: delta is the change since
+			// the start of the range, deriv the per-second change since the previous value.
+			function: `delta()`,
+			in:       []TimeSeries{series([]float64{1, 4, 10}, "a", "x")},
+			expected: []TimeSeries{series([]float64{0, 3, 9}, "a", "x")},
+		},
+		{
+			function: `deriv()`,
+			in:       []TimeSeries{series([]float64{1, 4, 10}, "a", "x")},
+			expected: []TimeSeries{series([]float64{nan, 1.5, 3}, "a", "x")},
+		},
+		{
+			function: `predict_linear(4s)`,
+			in:       []TimeSeries{series([]float64{1, nan, 3}, "a", "x")},
+			expected: []TimeSeries{series([]float64{nan, nan, 5}, "a", "x")},
+		},
+		{
+			function: `absent()`,
+			in: []TimeSeries{
+				series([]float64{1, nan}, "a", "x"),
+				series([]float64{nan, nan}, "a", "y"),
+			},
+			expected: []TimeSeries{series([]float64{nan, 1}, "__name__", "absent")},
+		},
+		{
+			function: `absent()`,
+			in:       []TimeSeries{series([]float64{1, 1}, "a", "x")},
+			expected: []TimeSeries{},
+		},
+		{
+			function: `absent()`,
+			in:       []TimeSeries{},
+			expected: []TimeSeries{series([]float64{1, 1}, "__name__", "absent")},
+		},
+		{
+			function: `label_replace("svc", "$1", "a", "(.*)-service")`,
+			in: []TimeSeries{
+				series([]float64{1, 1}, "a", "foo-service"),
+				series([]float64{2, 2}, "a", "bar"),
+			},
+			expected: []TimeSeries{
+				series([]float64{1, 1}, "a", "foo-service", "svc", "foo"),
+				series([]float64{2, 2}, "a", "bar"),
+			},
+		},
+		{
+			// an empty replacement removes the label, and the series that collide are dropped
+			function: `label_replace("b", "", "a", "x")`,
+			in: []TimeSeries{
+				series([]float64{1, 1}, "a", "x", "b", "1"),
+				series([]float64{2, 2}, "a", "x", "b", "2"),
+			},
+			expected: []TimeSeries{
+				series([]float64{1, 1}, "a", "x"),
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.function, func(t *testing.T) {
+			expr, err := Parse(`{} | rate() | ` + tc.function)
+			require.NoError(t, err)
+			require.NoError(t, expr.validate())
+
+			stage := expr.MetricsSecondStage
+			stage.init(req)
+
+			in := SeriesSet{}
+			for _, s := range tc.in {
+				in[s.Labels.MapKey()] = s
+			}
+
+			result := stage.process(in)
+			require.Len(t, result, len(tc.expected))
+			for _, expected := range tc.expected {
+				actual, ok := result[expected.Labels.MapKey()]
+				require.True(t, ok, "missing series %v", expected.Labels)
+				require.Equal(t, expected.Labels, actual.Labels)
+				expectSeriesValues(t, actual.Values, expected.Values)
+			}
+		})
+	}
+}
+
+func TestSecondStageSort(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: 1,
+		End:   uint64(3 * time.Second),
+		Step:  uint64(1 * time.Second),
+	}
+
+	in := make([]Span, 0)
+	in = append(in, generateSpans(3, []int{1, 2, 3}, "bar")...)
+	in = append(in, generateSpans(5, []int{1, 2, 3}, "baz")...)
+	in = append(in, generateSpans(1, []int{1, 2, 3}, "quax")...)
+
+	tcs := []struct {
+		query    string
+		expected []string
+	}{
+		{
+			query:    "{ } | count_over_time() by (span.foo) | sort()",
+			expected: []string{"quax", "bar", "baz"},
+		},
+		{
+			query:    "{ } | count_over_time() by (span.foo) | sort_desc() | topk(2)",
+			expected: []string{"baz", "bar"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			req := *req
+			req.Query = tc.query
+
+			layer2, err := processLayer1AndLayer2(&req, in)
+			require.NoError(t, err)
+
+			combiner, err := QueryRangeCombinerFor(&req, AggregateModeFinal, 0)
+			require.NoError(t, err)
+			combiner.Combine(&tempopb.QueryRangeResponse{Series: layer2.ToProto(&req)})
+
+			resp := combiner.Response()
+			combiner.SortSeries(resp.Series)
+
+			actual := make([]string, 0, len(resp.Series))
+			for _, s := range resp.Series {
+				actual = append(actual, s.Labels[0].Value.GetStringValue())
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -2200,6 +2200,129 @@ func TestHistogramOverTime(t *testing.T) {
 	requireEqualSeriesSets(t, out, result)
 }
 
+func TestHistogramOverTimeExplicitBuckets(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: 1,
+		End:   uint64(2 * time.Second),
+		Step:  uint64(1 * time.Second),
+		Query: "{ } | histogram_over_time(span.size, buckets=[100, 1000])",
+	}
+
+	// Spans of two jobs, buckets are merged across them
+	job1 := []Span{
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanInt("size", 50),
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanInt("size", 100),
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanFloat("size", 100.5),
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("size", "big"), // ignored
+	}
+	job2 := []Span{
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanInt("size", 10),
+		newMockSpan(nil).WithStartTime(uint64(2*time.Second)).WithSpanInt("size", 1000),
+		newMockSpan(nil).WithStartTime(uint64(2*time.Second)).WithSpanInt("size", 5000),
+	}
+
+	out := []TimeSeries{
+		{
+			Labels:    []Label{{Name: internalLabelBucket, Value: NewStaticFloat(100)}},
+			Values:    []float64{3, 0},
+			Exemplars: make([]Exemplar, 0),
+		},
+		{
+			Labels:    []Label{{Name: internalLabelBucket, Value: NewStaticFloat(1000)}},
+			Values:    []float64{1, 1},
+			Exemplars: make([]Exemplar, 0),
+		},
+		{
+			Labels:    []Label{{Name: internalLabelBucket, Value: NewStaticFloat(math.Inf(1))}},
+			Values:    []float64{0, 1},
+			Exemplars: make([]Exemplar, 0),
+		},
+	}
+
+	result, seriesCount, err := runTraceQLMetric(req, job1, job2)
+	require.NoError(t, err)
+	require.Equal(t, len(result), seriesCount)
+	requireEqualSeriesSets(t, out, result)
+}
+
+func TestQuantileOverTimeExplicitBuckets(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: 1,
+		End:   uint64(1 * time.Second),
+		Step:  uint64(1 * time.Second),
+		Query: "{ } | quantile_over_time(span.size, 0.25, 0.5, 1, buckets=linear(100, 100, 4))",
+	}
+
+	// 4 spans in the bucket (0, 100] and 4 in (100, 200]
+	var job1, job2 []Span
+	for i := 0; i < 4; i++ {
+		job1 = append(job1, newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanInt("size", 90))
+		job2 = append(job2, newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanInt("size", 150))
+	}
+
+	result, _, err := runTraceQLMetric(req, job1, job2)
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+
+	// Quantiles are interpolated linearly within the buckets
+	require.Equal(t, []float64{50}, result[LabelsFromArgs("p", 0.25).MapKey()].Values)
+	require.Equal(t, []float64{100}, result[LabelsFromArgs("p", 0.5).MapKey()].Values)
+	require.Equal(t, []float64{200}, result[LabelsFromArgs("p", 1.0).MapKey()].Values)
+}
+
+func TestExplicitQuantile(t *testing.T) {
+	boundaries := []float64{1, 2, 4, 8}
+
+	tests := []struct {
+		name     string
+		p        float64
+		buckets  []HistogramBucket
+		expected float64
+	}{
+		{
+			name:     "no buckets",
+			p:        0.5,
+			expected: 0,
+		},
+		{
+			name:     "first bucket starts at 0",
+			p:        0.5,
+			buckets:  []HistogramBucket{{Max: 1, Count: 2}},
+			expected: 0.5,
+		},
+		{
+			name:     "sparse buckets use the prior boundary",
+			p:        0.5,
+			buckets:  []HistogramBucket{{Max: 8, Count: 2}},
+			expected: 6,
+		},
+		{
+			name:     "interpolated across buckets",
+			p:        0.75,
+			buckets:  []HistogramBucket{{Max: 1, Count: 2}, {Max: 4, Count: 2}},
+			expected: 3,
+		},
+		{
+			name:     "+Inf bucket is the highest boundary",
+			p:        0.99,
+			buckets:  []HistogramBucket{{Max: 1, Count: 1}, {Max: math.Inf(1), Count: 9}},
+			expected: 8,
+		},
+		{
+			name:     "invalid quantile",
+			p:        1.5,
+			buckets:  []HistogramBucket{{Max: 1, Count: 1}},
+			expected: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, ExplicitQuantile(tc.p, tc.buckets, boundaries))
+		})
+	}
+}
+
 func TestSecondStageTopK(t *testing.T) {
 	req := &tempopb.QueryRangeRequest{
 		Start: 1,
@@ -2224,6 +2347,24 @@ func TestSecondStageTopK(t *testing.T) {
 	require.Equal(t, []float64{5, 5, 5, 5, 5, 5, 5, 5}, resultBaz.Values)
 }
 
+func TestSecondStageIncrease(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: 1,
+		End:   uint64(8 * time.Second),
+		Step:  uint64(2 * time.Second),
+		Query: "{ } | rate() by (span.foo) | increase()",
+	}
+
+	in := generateSpans(3, []int{1, 2, 3, 4, 5, 6, 7, 8}, "bar")
+
+	result, _, err := runTraceQLMetric(req, in)
+	require.NoError(t, err)
+
+	// rate() is 3 spans per second, increase() is the number of spans since the start
+	resultBar := result[LabelsFromArgs("span.foo", "bar").MapKey()]
+	require.Equal(t, []float64{6, 12, 18, 24}, resultBar.Values)
+}
+
 func TestSecondStageTopKInstant(t *testing.T) {
 	// Instant Queries are just Range Queries with a step that spans the entire range (end - start) + start
 	req := &tempopb.QueryRangeRequest{
@@ -3765,3 +3906,96 @@ func TestLog2QuantileWithBucket(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryRangeOffset(t *testing.T) {
+	tcs := []struct {
+		name     string
+		req      *tempopb.QueryRangeRequest
+		offset   time.Duration
+		expected uint64
+		err      bool
+	}{
+		{
+			name:     "no offset",
+			req:      &tempopb.QueryRangeRequest{Start: 0, End: uint64(time.Hour), Step: uint64(time.Minute)},
+			expected: 0,
+		},
+		{
+			name:     "multiple of step",
+			req:      &tempopb.QueryRangeRequest{Start: 0, End: uint64(time.Hour), Step: uint64(time.Minute)},
+			offset:   7 * 24 * time.Hour,
+			expected: uint64(7 * 24 * time.Hour),
+		},
+		{
+			name:   "not a multiple of step",
+			req:    &tempopb.QueryRangeRequest{Start: 0, End: uint64(time.Hour), Step: uint64(time.Minute)},
+			offset: 90 * time.Second,
+			err:    true,
+		},
+		{
+			name:     "instant",
+			req:      &tempopb.QueryRangeRequest{Start: 0, End: uint64(time.Hour), Step: uint64(time.Hour)},
+			offset:   90 * time.Second,
+			expected: uint64(90 * time.Second),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			offset, err := QueryRangeOffset(tc.req, tc.offset)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, offset)
+		})
+	}
+}
+
+func TestMetricsFunctionCallSecondPass(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: uint64(1 * time.Second),
+		End:   uint64(3 * time.Second),
+		Step:  uint64(1 * time.Second),
+		Query: `{ lower(name) = "checkout" } | rate()`,
+	}
+
+	span := func(id byte, name string) Span {
+		s := newMockSpan([]byte{id}).WithStartTime(uint64(2 * time.Second))
+		s.attributes[NewIntrinsic(IntrinsicName)] = NewStaticString(name)
+		return s
+	}
+
+	eval, err := NewEngine().CompileMetricsQueryRange(req)
+	require.NoError(t, err)
+
+	// the storage layer filters with a case-insensitive regex that matches more values than
+	// lower(), the function call must still be evaluated by the second pass
+	fetcher := &MockSpanSetFetcher{
+		iterator: &MockSpanSetIterator{
+			results: []*Spanset{
+				{
+					TraceID: []byte{1},
+					Spans: []Span{
+						span(1, "Checkout"),
+						span(2, "checkout-v2"),
+						span(3, "CHECKOUT"),
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, eval.Do(t.Context(), fetcher, 0, 0, 0))
+	require.NotNil(t, fetcher.capturedRequest.SecondPass)
+
+	total := 0.0
+	for _, ts := range eval.Results() {
+		for _, v := range ts.Values {
+			if !math.IsNaN(v) {
+				total += v
+			}
+		}
+	}
+	require.Equal(t, 2.0, total)
+}
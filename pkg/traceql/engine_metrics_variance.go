@@ -0,0 +1,512 @@
+package traceql
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+const internalMetaTypeSumSquares = "__sumsq"
+
+// Standard deviation and variance over time aggregator. The intermediate results
+// are the count, sum and sum of squares of the values per interval, which can be
+// added together across jobs. The final value is only computed in the frontend.
+type varianceOverTimeAggregator struct {
+	op   MetricsAggregateOp
+	by   []Attribute
+	attr Attribute
+	// Variance over time span aggregator
+	agg SpanAggregator
+	// Variance over time series aggregator
+	seriesAgg  *varianceOverTimeSeriesAggregator
+	exemplarFn getExemplar
+	mode       AggregateMode
+}
+
+var _ firstStageElement = (*varianceOverTimeAggregator)(nil)
+
+func newVarianceOverTimeMetricsAggregator(op MetricsAggregateOp, attr Attribute, by []Attribute) *varianceOverTimeAggregator {
+	return &varianceOverTimeAggregator{
+		op:   op,
+		attr: attr,
+		by:   by,
+	}
+}
+
+func (a *varianceOverTimeAggregator) init(q *tempopb.QueryRangeRequest, mode AggregateMode) {
+	intervalMapper := NewIntervalMapperFromReq(q)
+
+	a.seriesAgg = &varianceOverTimeSeriesAggregator{
+		op:              a.op,
+		series:          make(map[SeriesMapKey]*varianceSeries),
+		len:             intervalMapper.IntervalCount(),
+		intervalMapper:  intervalMapper,
+		exemplarBuckets: newExemplarBucketSet(q.Exemplars, q.Start, q.End, q.Step, IsInstant(q)),
+		final:           mode == AggregateModeFinal,
+	}
+
+	if mode == AggregateModeRaw {
+		a.agg = newVarianceOverTimeSpanAggregator(a.op, a.attr, a.by, q.Start, q.End, q.Step, IsInstant(q), q.Exemplars)
+	}
+
+	a.mode = mode
+	a.exemplarFn = exemplarFnFor(a.attr)
+}
+
+func (a *varianceOverTimeAggregator) observe(span Span) {
+	a.agg.Observe(span)
+}
+
+func (a *varianceOverTimeAggregator) observeExemplar(span Span) {
+	v, ts := a.exemplarFn(span)
+	a.agg.ObserveExemplar(span, v, ts)
+}
+
+func (a *varianceOverTimeAggregator) observeSeries(ss []*tempopb.TimeSeries) {
+	a.seriesAgg.Combine(ss)
+}
+
+func (a *varianceOverTimeAggregator) result(multiplier float64) SeriesSet {
+	if a.agg != nil {
+		ss := a.agg.Series()
+		if multiplier > 1.0 {
+			// Scaling all of the intermediate values by the same factor doesn't
+			// change the variance, but keeps the weight of this job correct
+			// when combined with others.
+			for _, s := range ss {
+				for i := range s.Values {
+					s.Values[i] *= multiplier
+				}
+			}
+		}
+		return ss
+	}
+
+	// In the frontend-version the results come from
+	// the job-level aggregator
+	return a.seriesAgg.Results()
+}
+
+func (a *varianceOverTimeAggregator) length() int {
+	if a.agg != nil {
+		return a.agg.Length()
+	}
+	return a.seriesAgg.Length()
+}
+
+func (a *varianceOverTimeAggregator) extractConditions(request *FetchSpansRequest) {
+	// For metrics aggregators based on a span attribute we have to include it
+	includeAttribute := a.attr != (Attribute{}) && !request.HasAttribute(a.attr)
+	if includeAttribute {
+		request.SecondPassConditions = append(request.SecondPassConditions, Condition{
+			Attribute: a.attr,
+		})
+	}
+
+	for _, b := range a.by {
+		if !request.HasAttribute(b) {
+			request.SecondPassConditions = append(request.SecondPassConditions, Condition{
+				Attribute: b,
+			})
+		}
+	}
+}
+
+func (a *varianceOverTimeAggregator) validate() error {
+	if len(a.by) >= maxGroupBys {
+		// We reserve a spot for the meta type label
+		return newUnsupportedError(fmt.Sprintf("metrics group by %v values", len(a.by)))
+	}
+	return nil
+}
+
+func (a *varianceOverTimeAggregator) String() string {
+	s := strings.Builder{}
+
+	s.WriteString(a.op.String())
+	s.WriteString("(")
+	if a.attr != (Attribute{}) {
+		s.WriteString(a.attr.String())
+	}
+	s.WriteString(")")
+
+	if len(a.by) > 0 {
+		s.WriteString("by(")
+		for i, b := range a.by {
+			s.WriteString(b.String())
+			if i < len(a.by)-1 {
+				s.WriteString(",")
+			}
+		}
+		s.WriteString(")")
+	}
+	return s.String()
+}
+
+// varianceSeries holds the count, sum and sum of squares of the values per interval.
+type varianceSeries struct {
+	labels    Labels
+	count     []float64
+	sum       []float64
+	sumSq     []float64
+	Exemplars []Exemplar
+}
+
+func newVarianceSeries(l int, lenExemplars uint32, labels Labels) varianceSeries {
+	return varianceSeries{
+		labels:    labels,
+		count:     make([]float64, l),
+		sum:       make([]float64, l),
+		sumSq:     make([]float64, l),
+		Exemplars: make([]Exemplar, 0, lenExemplars),
+	}
+}
+
+func (v *varianceSeries) observe(interval int, val float64) {
+	v.count[interval]++
+	v.sum[interval] += val
+	v.sumSq[interval] += val * val
+}
+
+// intermediate returns the sum series with the exemplars, and the count and sum of squares
+// series identified by the meta type label.
+func (v *varianceSeries) intermediate(ss SeriesSet) {
+	sum := TimeSeries{
+		Labels:    v.labels,
+		Values:    make([]float64, len(v.sum)),
+		Exemplars: v.Exemplars,
+	}
+	count := TimeSeries{
+		Labels: v.labels.Add(Label{internalLabelMetaType, NewStaticString(internalMetaTypeCount)}),
+		Values: make([]float64, len(v.count)),
+	}
+	sumSq := TimeSeries{
+		Labels: v.labels.Add(Label{internalLabelMetaType, NewStaticString(internalMetaTypeSumSquares)}),
+		Values: make([]float64, len(v.sumSq)),
+	}
+
+	// Intervals without values are NaN so that they are dropped from the proto
+	for i := range v.count {
+		if v.count[i] == 0 {
+			sum.Values[i], count.Values[i], sumSq.Values[i] = nan, nan, nan
+			continue
+		}
+		sum.Values[i], count.Values[i], sumSq.Values[i] = v.sum[i], v.count[i], v.sumSq[i]
+	}
+
+	ss[sum.Labels.MapKey()] = sum
+	ss[count.Labels.MapKey()] = count
+	ss[sumSq.Labels.MapKey()] = sumSq
+}
+
+// final returns the population variance or standard deviation of the values.
+func (v *varianceSeries) final(op MetricsAggregateOp) TimeSeries {
+	ts := TimeSeries{
+		Labels:    v.labels,
+		Values:    make([]float64, len(v.count)),
+		Exemplars: v.Exemplars,
+	}
+
+	for i, n := range v.count {
+		if n == 0 {
+			ts.Values[i] = nan
+			continue
+		}
+
+		mean := v.sum[i] / n
+		// Rounding errors can make the variance slightly negative
+		variance := math.Max(v.sumSq[i]/n-mean*mean, 0)
+
+		if op == metricsAggregateStddevOverTime {
+			ts.Values[i] = math.Sqrt(variance)
+		} else {
+			ts.Values[i] = variance
+		}
+	}
+	return ts
+}
+
+// varianceOverTimeSeriesAggregator adds up the intermediate results of the jobs. In the
+// final mode it returns the variance or standard deviation instead of the intermediate series.
+type varianceOverTimeSeriesAggregator struct {
+	op              MetricsAggregateOp
+	series          map[SeriesMapKey]*varianceSeries
+	len             int
+	intervalMapper  IntervalMapper
+	exemplarBuckets bucketSet
+	final           bool
+}
+
+var _ SeriesAggregator = (*varianceOverTimeSeriesAggregator)(nil)
+
+func (b *varianceOverTimeSeriesAggregator) Combine(in []*tempopb.TimeSeries) {
+	for _, ts := range in {
+		metaType := ""
+		for _, l := range ts.Labels {
+			if l.Key == internalLabelMetaType {
+				metaType = l.Value.GetStringValue()
+				break
+			}
+		}
+
+		lbls := getLabels(ts.Labels, internalLabelMetaType)
+		key := lbls.MapKey()
+
+		existing, ok := b.series[key]
+		if !ok {
+			s := newVarianceSeries(b.len, uint32(len(ts.Exemplars)), lbls) //nolint: gosec // G115
+			existing = &s
+			b.series[key] = existing
+		}
+
+		var values []float64
+		switch metaType {
+		case internalMetaTypeCount:
+			values = existing.count
+		case internalMetaTypeSumSquares:
+			values = existing.sumSq
+		default:
+			values = existing.sum
+			b.aggregateExemplars(ts, existing)
+		}
+
+		for _, sample := range ts.Samples {
+			pos := b.intervalMapper.IntervalMs(sample.TimestampMs)
+			if pos < 0 || pos >= len(values) || math.IsNaN(sample.Value) {
+				continue
+			}
+			values[pos] += sample.Value
+		}
+	}
+}
+
+func (b *varianceOverTimeSeriesAggregator) aggregateExemplars(ts *tempopb.TimeSeries, existing *varianceSeries) {
+	for _, exemplar := range ts.Exemplars {
+		if b.exemplarBuckets.testTotal() {
+			break
+		}
+		if b.exemplarBuckets.addAndTest(uint64(exemplar.TimestampMs)) { //nolint: gosec // G115
+			continue // Skip this exemplar and continue, next exemplar might fit in a different bucket
+		}
+		existing.Exemplars = append(existing.Exemplars, Exemplar{
+			Labels:      getLabels(exemplar.Labels, ""),
+			Value:       exemplar.Value,
+			TimestampMs: uint64(exemplar.TimestampMs), //nolint: gosec // G115
+		})
+	}
+}
+
+func (b *varianceOverTimeSeriesAggregator) Results() SeriesSet {
+	ss := SeriesSet{}
+	for k, v := range b.series {
+		if b.final {
+			ss[k] = v.final(b.op)
+			continue
+		}
+		v.intermediate(ss)
+	}
+	return ss
+}
+
+func (b *varianceOverTimeSeriesAggregator) Length() int {
+	return len(b.series)
+}
+
+// Accumulated values of a single series
+type varianceOverTimeSeries[S StaticVals] struct {
+	variance        varianceSeries
+	exemplarBuckets bucketSet
+	vals            S
+	initialized     bool
+}
+
+// In charge of calculating the count, sum and sum of squares for a set of spans
+// First aggregation layer
+type varianceOverTimeSpanAggregator[F FastStatic, S StaticVals] struct {
+	// Config
+	name             string
+	by               []Attribute   // Original attributes: .foo
+	byLookups        [][]Attribute // Lookups: span.foo resource.foo
+	getSpanAttValue  func(s Span) float64
+	intervalMapper   IntervalMapper
+	start, end, step uint64
+	instant          bool
+	exemplars        uint32
+
+	// Data
+	series     map[F]varianceOverTimeSeries[S]
+	lastSeries varianceOverTimeSeries[S]
+	buf        fastStaticWithValues[F, S]
+	lastBuf    fastStaticWithValues[F, S]
+}
+
+var _ SpanAggregator = (*varianceOverTimeSpanAggregator[FastStatic1, StaticVals1])(nil)
+
+func newVarianceOverTimeSpanAggregator(op MetricsAggregateOp, attr Attribute, by []Attribute, start, end, step uint64, instant bool, exemplars uint32) SpanAggregator {
+	lookups := make([][]Attribute, len(by))
+	for i, attr := range by {
+		if attr.Intrinsic == IntrinsicNone && attr.Scope == AttributeScopeNone {
+			// Unscoped attribute. Check span-level, then resource-level.
+			lookups[i] = []Attribute{
+				NewScopedAttribute(AttributeScopeSpan, false, attr.Name),
+				NewScopedAttribute(AttributeScopeResource, false, attr.Name),
+			}
+		} else {
+			lookups[i] = []Attribute{attr}
+		}
+	}
+
+	switch len(lookups) {
+	case 2:
+		return newVarianceAggregator[FastStatic2, StaticVals2](op, attr, by, lookups, start, end, step, instant, exemplars)
+	case 3:
+		return newVarianceAggregator[FastStatic3, StaticVals3](op, attr, by, lookups, start, end, step, instant, exemplars)
+	case 4:
+		return newVarianceAggregator[FastStatic4, StaticVals4](op, attr, by, lookups, start, end, step, instant, exemplars)
+	default:
+		return newVarianceAggregator[FastStatic1, StaticVals1](op, attr, by, lookups, start, end, step, instant, exemplars)
+	}
+}
+
+func newVarianceAggregator[F FastStatic, S StaticVals](op MetricsAggregateOp, attr Attribute, by []Attribute, lookups [][]Attribute, start, end, step uint64, instant bool, exemplars uint32) SpanAggregator {
+	var fn func(s Span) float64
+
+	switch attr {
+	case IntrinsicDurationAttribute:
+		fn = func(s Span) float64 {
+			return float64(s.DurationNanos()) / float64(time.Second)
+		}
+	default:
+		fn = func(s Span) float64 {
+			f, a := FloatizeAttribute(s, attr)
+			if a == TypeNil {
+				return math.Float64frombits(normalNaN)
+			}
+			return f
+		}
+	}
+
+	return &varianceOverTimeSpanAggregator[F, S]{
+		name:            op.String(),
+		series:          map[F]varianceOverTimeSeries[S]{},
+		getSpanAttValue: fn,
+		by:              by,
+		byLookups:       lookups,
+		intervalMapper:  NewIntervalMapper(start, end, step, instant),
+		start:           start,
+		end:             end,
+		step:            step,
+		instant:         instant,
+		exemplars:       exemplars,
+	}
+}
+
+func (g *varianceOverTimeSpanAggregator[F, S]) Observe(span Span) {
+	interval := g.intervalMapper.Interval(span.StartTimeUnixNanos())
+	if interval == -1 {
+		return
+	}
+
+	v := g.getSpanAttValue(span)
+	if math.IsNaN(v) {
+		return
+	}
+
+	s := g.getSeries(span)
+	s.variance.observe(interval, v)
+}
+
+func (g *varianceOverTimeSpanAggregator[F, S]) ObserveExemplar(span Span, value float64, ts uint64) {
+	s := g.getSeries(span)
+	if s.exemplarBuckets.testTotal() {
+		return
+	}
+	if s.exemplarBuckets.addAndTest(ts) {
+		return
+	}
+
+	all := span.AllAttributes()
+	lbls := make(Labels, 0, len(all))
+	for k, v := range all {
+		lbls = append(lbls, Label{k.String(), v})
+	}
+
+	s.variance.Exemplars = append(s.variance.Exemplars, Exemplar{
+		Labels:      lbls,
+		Value:       value,
+		TimestampMs: ts,
+	})
+	g.series[g.buf.fast] = s
+	g.lastSeries = s
+}
+
+func (g *varianceOverTimeSpanAggregator[F, S]) Length() int {
+	return len(g.series)
+}
+
+func (g *varianceOverTimeSpanAggregator[F, S]) labelsFor(vals S) Labels {
+	if g.by == nil {
+		return LabelsFromArgs(labels.MetricName, g.name)
+	}
+	labels := make(Labels, 0, len(g.by)+1)
+	for i := range g.by {
+		if vals[i].Type == TypeNil {
+			continue
+		}
+		labels = append(labels, Label{g.by[i].String(), vals[i]})
+	}
+
+	if len(labels) == 0 {
+		// When all nil then force one
+		labels = append(labels, Label{g.by[0].String(), NewStaticNil()})
+	}
+
+	return labels
+}
+
+func (g *varianceOverTimeSpanAggregator[F, S]) Series() SeriesSet {
+	ss := SeriesSet{}
+
+	for _, s := range g.series {
+		s.variance.labels = g.labelsFor(s.vals)
+		s.variance.intermediate(ss)
+	}
+
+	return ss
+}
+
+// getSeries gets the series for the current span.
+// It will reuse the last series if possible.
+func (g *varianceOverTimeSpanAggregator[F, S]) getSeries(span Span) varianceOverTimeSeries[S] {
+	// Get Grouping values
+	for i, lookups := range g.byLookups {
+		val := lookup(lookups, span)
+		g.buf.vals[i] = val
+		g.buf.fast[i] = val.MapKey()
+	}
+
+	// Fast path
+	if g.lastBuf.fast == g.buf.fast && g.lastSeries.initialized {
+		return g.lastSeries
+	}
+
+	s, ok := g.series[g.buf.fast]
+	if !ok {
+		intervals := g.intervalMapper.IntervalCount()
+		s = varianceOverTimeSeries[S]{
+			vals:            g.buf.vals,
+			variance:        newVarianceSeries(intervals, g.exemplars, nil),
+			exemplarBuckets: newExemplarBucketSet(g.exemplars, g.start, g.end, g.step, g.instant),
+			initialized:     true,
+		}
+		g.series[g.buf.fast] = s
+	}
+
+	g.lastBuf = g.buf
+	g.lastSeries = s
+	return s
+}
@@ -0,0 +1,137 @@
+package traceql
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarianceOverTime(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: 1,
+		End:   uint64(3 * time.Second),
+		Step:  uint64(1 * time.Second),
+	}
+
+	span := func(ts int, svc string, duration time.Duration) Span {
+		return newMockSpan(nil).WithStartTime(uint64(time.Duration(ts)*time.Second)).WithSpanString("service", svc).WithDuration(uint64(duration))
+	}
+
+	// The spans of each interval are split between the two jobs
+	job1 := []Span{
+		span(1, "a", 2*time.Second),
+		span(1, "a", 4*time.Second),
+		span(2, "a", 1*time.Second),
+		span(1, "b", 3*time.Second),
+	}
+	job2 := []Span{
+		span(1, "a", 4*time.Second),
+		span(1, "a", 5*time.Second),
+		span(2, "a", 1*time.Second),
+		span(2, "b", 7*time.Second),
+	}
+
+	// service=a, interval 1: 2,4,4,5 => mean 3.75, variance 1.1875
+	// service=a, interval 2: 1,1 => variance 0
+	// service=b, intervals 1 and 2: single value => variance 0
+	tcs := []struct {
+		query    string
+		expected []TimeSeries
+	}{
+		{
+			query: "{ } | stdvar_over_time(duration) by (span.service)",
+			expected: []TimeSeries{
+				{
+					Labels: LabelsFromArgs("span.service", "a"),
+					Values: []float64{1.1875, 0, math.NaN()},
+				},
+				{
+					Labels: LabelsFromArgs("span.service", "b"),
+					Values: []float64{0, 0, math.NaN()},
+				},
+			},
+		},
+		{
+			query: "{ } | stddev_over_time(duration) by (span.service)",
+			expected: []TimeSeries{
+				{
+					Labels: LabelsFromArgs("span.service", "a"),
+					Values: []float64{math.Sqrt(1.1875), 0, math.NaN()},
+				},
+				{
+					Labels: LabelsFromArgs("span.service", "b"),
+					Values: []float64{0, 0, math.NaN()},
+				},
+			},
+		},
+		{
+			// all services, interval 1: 2,3,4,4,5 => variance 1.04, interval 2: 1,1,7 => variance 8
+			query: "{ } | stddev_over_time(duration)",
+			expected: []TimeSeries{
+				{
+					Labels: LabelsFromArgs("__name__", "stddev_over_time"),
+					Values: []float64{math.Sqrt(1.04), math.Sqrt(8), math.NaN()},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			req := *req
+			req.Query = tc.query
+
+			result, seriesCount, err := runTraceQLMetric(&req, job1, job2)
+			require.NoError(t, err)
+			require.Equal(t, len(tc.expected), seriesCount)
+			require.Len(t, result, len(tc.expected))
+
+			for _, expected := range tc.expected {
+				actual, ok := result[expected.Labels.MapKey()]
+				require.True(t, ok, "missing series %v", expected.Labels)
+				require.InDeltaSlice(t, expected.Values[:2], actual.Values[:2], 1e-9)
+				require.True(t, math.IsNaN(actual.Values[2]))
+			}
+		})
+	}
+}
+
+func TestVarianceOverTimeIntermediateResults(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Start: 1,
+		End:   uint64(2 * time.Second),
+		Step:  uint64(1 * time.Second),
+	}
+
+	a := newVarianceOverTimeMetricsAggregator(metricsAggregateStdvarOverTime, IntrinsicDurationAttribute, []Attribute{NewAttribute("service")})
+	a.init(req, AggregateModeRaw)
+
+	a.observe(newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("service", "a").WithDuration(uint64(2 * time.Second)))
+	a.observe(newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("service", "a").WithDuration(uint64(3 * time.Second)))
+
+	expected := []TimeSeries{
+		{
+			Labels:    LabelsFromArgs(".service", "a"),
+			Values:    []float64{5, math.NaN()},
+			Exemplars: []Exemplar{},
+		},
+		{
+			Labels: LabelsFromArgs(".service", "a", internalLabelMetaType, internalMetaTypeCount),
+			Values: []float64{2, math.NaN()},
+		},
+		{
+			Labels: LabelsFromArgs(".service", "a", internalLabelMetaType, internalMetaTypeSumSquares),
+			Values: []float64{13, math.NaN()},
+		},
+	}
+	requireEqualSeriesSets(t, expected, a.result(1.0))
+
+	// Sampling scales all the intermediate values
+	for i := range expected {
+		expected[i].Values[0] *= 2
+	}
+	requireEqualSeriesSets(t, expected, a.result(2.0))
+}
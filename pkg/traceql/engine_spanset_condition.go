@@ -0,0 +1,43 @@
+package traceql
+
+import (
+	"errors"
+
+	trace_v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+// SpansetCondition is a TraceQL spanset query that is evaluated in memory on the spans of a trace
+// to decide whether the trace matches, for example by the tail sampling of the distributor. Spans
+// don't know their relations, so structural operators are not supported.
+type SpansetCondition struct {
+	pipeline *protoPipeline
+}
+
+// CompileSpansetCondition compiles a spanset query that can be evaluated on spans in memory.
+func CompileSpansetCondition(query string) (*SpansetCondition, error) {
+	expr, pipeline, err := compileProtoPipeline(query, "in conditions")
+	if err != nil {
+		return nil, err
+	}
+
+	if expr.MetricsPipeline != nil || expr.MetricsSecondStage != nil {
+		return nil, errors.New("metrics queries are not supported in conditions")
+	}
+
+	return &SpansetCondition{pipeline: pipeline}, nil
+}
+
+// Matches returns true if at least one span of the batches matches the condition.
+func (c *SpansetCondition) Matches(batches []*trace_v1.ResourceSpans) (bool, error) {
+	evalSS, err := c.pipeline.evaluate(batches)
+	if err != nil {
+		return false, err
+	}
+
+	for _, ss := range evalSS {
+		if len(ss.Spans) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
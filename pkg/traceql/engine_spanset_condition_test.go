@@ -0,0 +1,81 @@
+package traceql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	resource_v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	trace_v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestCompileSpansetCondition(t *testing.T) {
+	tcs := []struct {
+		query string
+		err   string
+	}{
+		{query: `{ }`},
+		{query: `{ span.http.status_code >= 500 }`},
+		{query: `{ status = error } && { resource.service.name = "api" }`},
+		{query: `{ } | count() > 2`},
+		{query: `{ } | rate()`, err: "metrics queries are not supported in conditions"},
+		{query: `{ } > { }`, err: "structural operator (>) is not supported in conditions"},
+		{query: `{ .a = }`, err: "parse error"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			_, err := CompileSpansetCondition(tc.query)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSpansetConditionMatches(t *testing.T) {
+	str := func(k, v string) *common_v1.KeyValue {
+		return &common_v1.KeyValue{Key: k, Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: v}}}
+	}
+	batches := []*trace_v1.ResourceSpans{{
+		Resource: &resource_v1.Resource{Attributes: []*common_v1.KeyValue{str("service.name", "api")}},
+		ScopeSpans: []*trace_v1.ScopeSpans{{
+			Spans: []*trace_v1.Span{
+				{TraceId: []byte{1}, SpanId: []byte{1}, Attributes: []*common_v1.KeyValue{str("db.system", "postgres")}},
+				{TraceId: []byte{1}, SpanId: []byte{2}, Status: &trace_v1.Status{Code: trace_v1.Status_STATUS_CODE_ERROR}},
+			},
+		}},
+	}}
+
+	tcs := []struct {
+		query   string
+		matches bool
+	}{
+		{query: `{ }`, matches: true},
+		{query: `{ span.db.system = "postgres" }`, matches: true},
+		{query: `{ span.db.system = "mysql" }`, matches: false},
+		{query: `{ status = error && resource.service.name = "api" }`, matches: true},
+		{query: `{ status = error } && { span.db.system = "postgres" }`, matches: true},
+		{query: `{ } | count() > 2`, matches: false},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			c, err := CompileSpansetCondition(tc.query)
+			require.NoError(t, err)
+
+			matches, err := c.Matches(batches)
+			require.NoError(t, err)
+			require.Equal(t, tc.matches, matches)
+		})
+	}
+
+	c, err := CompileSpansetCondition(`{ }`)
+	require.NoError(t, err)
+	matches, err := c.Matches(nil)
+	require.NoError(t, err)
+	require.False(t, matches)
+}
@@ -204,6 +204,53 @@ func TestEngine_Execute(t *testing.T) {
 	assert.Equal(t, uint64(100_00), response.Metrics.InspectedBytes)
 }
 
+func TestEngine_ExecuteSelectFunctionCalls(t *testing.T) {
+	e := NewEngine()
+
+	req := &tempopb.SearchRequest{
+		Query: `{ } | select(lower(.foo), len(.foo))`,
+	}
+	spanSetFetcher := MockSpanSetFetcher{
+		iterator: &MockSpanSetIterator{
+			results: []*Spanset{
+				{
+					TraceID: []byte{1},
+					Spans: []Span{
+						&mockSpan{
+							id: []byte{1},
+							attributes: map[Attribute]Static{
+								NewAttribute("foo"): NewStaticString("VALUE"),
+							},
+						},
+						&mockSpan{
+							id:         []byte{2},
+							attributes: map[Attribute]Static{},
+						},
+					},
+				},
+			},
+		},
+	}
+	response, err := e.ExecuteSearch(context.Background(), req, &spanSetFetcher, false)
+	require.NoError(t, err)
+	require.Len(t, response.Traces, 1)
+
+	spans := response.Traces[0].SpanSet.Spans
+	require.Len(t, spans, 2)
+
+	sort.Slice(spans[0].Attributes, func(i, j int) bool {
+		return spans[0].Attributes[i].Key < spans[0].Attributes[j].Key
+	})
+	require.Equal(t, []*v1.KeyValue{
+		{Key: "foo", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "VALUE"}}},
+		{Key: "len(.foo)", Value: &v1.AnyValue{Value: &v1.AnyValue_IntValue{IntValue: 5}}},
+		{Key: "lower(.foo)", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: "value"}}},
+	}, spans[0].Attributes)
+
+	// functions of missing attributes are not returned
+	require.Empty(t, spans[1].Attributes)
+}
+
 func TestEngine_asTraceSearchMetadata(t *testing.T) {
 	now := time.Now()
 
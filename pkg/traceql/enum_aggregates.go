@@ -60,6 +60,8 @@ const (
 	metricsAggregateSumOverTime
 	metricsAggregateQuantileOverTime
 	metricsAggregateHistogramOverTime
+	metricsAggregateStddevOverTime
+	metricsAggregateStdvarOverTime
 )
 
 func (a MetricsAggregateOp) String() string {
@@ -80,6 +82,10 @@ func (a MetricsAggregateOp) String() string {
 		return "quantile_over_time"
 	case metricsAggregateHistogramOverTime:
 		return "histogram_over_time"
+	case metricsAggregateStddevOverTime:
+		return "stddev_over_time"
+	case metricsAggregateStdvarOverTime:
+		return "stdvar_over_time"
 	}
 
 	return fmt.Sprintf("aggregate(%d)", a)
@@ -2,8 +2,9 @@
 package traceql
 
 import (
-  "time"
   "math"
+  "text/scanner"
+  "time"
 )
 %}
 
@@ -15,6 +16,7 @@ import (
     coalesceOperation CoalesceOperation
     selectOperation SelectOperation
     attributeList []Attribute
+    fieldExpressionList []FieldExpression
 
     spansetExpression SpansetExpression
     spansetPipelineExpression SpansetExpression
@@ -33,6 +35,10 @@ import (
     metricsAggregation firstStageElement
     metricsSecondStage secondStageElement
     metricsSecondStagePipeline ChainedSecondStage
+    metricsSecondStageArgs []Static
+    metricsExpression metricsExpression
+    metricsQuery *metricsQuery
+    vectorMatching *VectorMatching
 
     fieldExpression FieldExpression
     static Static
@@ -46,6 +52,8 @@ import (
     staticStr   string
     staticFloat float64
     staticDuration time.Duration
+    // position of an IDENTIFIER, the parser may have read past it when reporting an error about it
+    identifierPos scanner.Position
     numericList []float64
 
     hint *Hint
@@ -58,6 +66,7 @@ import (
 %type <coalesceOperation> coalesceOperation
 %type <selectOperation> selectOperation
 %type <attributeList> attributeList
+%type <fieldExpressionList> selectList
 
 %type <spansetExpression> spansetExpression
 %type <spansetPipelineExpression> spansetPipelineExpression
@@ -69,8 +78,14 @@ import (
 %type <metricsAggregation> metricsAggregation
 %type <metricsSecondStage> metricsSecondStage
 %type <metricsSecondStagePipeline> metricsSecondStagePipeline
+%type <metricsSecondStageArgs> metricsSecondStageArgs
+%type <static> metricsSecondStageArg
 %type <scalarFilterOperation> metricsFilterOperation
 %type <metricsSecondStage> metricsFilter
+%type <metricsExpression> metricsExpression
+%type <metricsQuery> metricsQuery
+%type <vectorMatching> vectorMatching
+%type <staticFloat> metricsScalar
 
 %type <scalarPipelineExpressionFilter> scalarPipelineExpressionFilter
 %type <scalarPipelineExpression> scalarPipelineExpression
@@ -80,13 +95,15 @@ import (
 %type <aggregate> aggregate 
 
 %type <fieldExpression> fieldExpression
+%type <fieldExpression> functionCall
+%type <fieldExpressionList> fieldExpressionList
 %type <static> static
 %type <intrinsicField> intrinsicField
 %type <attributeField> attributeField
 %type <scopedIntrinsicField> scopedIntrinsicField
 %type <attribute> attribute
 
-%type <numericList> numericList
+%type <numericList> numericList bucketBoundaries
 
 %type <hint> hint
 %type <hintList> hintList
@@ -96,7 +113,7 @@ import (
 %token <staticInt>      INTEGER
 %token <staticFloat>    FLOAT
 %token <staticDuration> DURATION
-%token <val>            DOT OPEN_BRACE CLOSE_BRACE OPEN_PARENS CLOSE_PARENS COMMA
+%token <val>            DOT OPEN_BRACE CLOSE_BRACE OPEN_PARENS CLOSE_PARENS OPEN_BRACKET CLOSE_BRACKET COMMA
                         NIL TRUE FALSE STATUS_ERROR STATUS_OK STATUS_UNSET
                         KIND_UNSPECIFIED KIND_INTERNAL KIND_SERVER KIND_CLIENT KIND_PRODUCER KIND_CONSUMER
                         IDURATION CHILDCOUNT NAME STATUS STATUS_MESSAGE PARENT KIND ROOTNAME ROOTSERVICENAME 
@@ -107,9 +124,10 @@ import (
                         COUNT AVG MAX MIN SUM
                         BY COALESCE SELECT
                         END_ATTRIBUTE
-                        RATE COUNT_OVER_TIME MIN_OVER_TIME MAX_OVER_TIME AVG_OVER_TIME SUM_OVER_TIME QUANTILE_OVER_TIME HISTOGRAM_OVER_TIME COMPARE
+                        RATE COUNT_OVER_TIME MIN_OVER_TIME MAX_OVER_TIME AVG_OVER_TIME SUM_OVER_TIME QUANTILE_OVER_TIME HISTOGRAM_OVER_TIME STDDEV_OVER_TIME STDVAR_OVER_TIME COMPARE
                         TOPK BOTTOMK
-                        WITH
+                        ON IGNORING GROUP_LEFT GROUP_RIGHT
+                        WITH OFFSET LINK_TRAVERSAL
 
 // Operators are listed with increasing precedence.
 %left <binOp> PIPE
@@ -129,11 +147,18 @@ root:
     spansetPipeline                                                       { yylex.(*lexer).expr = newRootExpr($1) }
   | spansetPipelineExpression                                             { yylex.(*lexer).expr = newRootExpr($1) }
   | scalarPipelineExpressionFilter                                        { yylex.(*lexer).expr = newRootExpr($1) } 
-  | spansetPipeline PIPE metricsAggregation                               { yylex.(*lexer).expr = newRootExprWithMetrics($1, $3) }
-  | spansetPipeline PIPE metricsAggregation metricsSecondStagePipeline    { yylex.(*lexer).expr = newRootExprWithMetricsTwoStage($1, $3, $4) }
+  | metricsRoot                                                           { }
+  | spansetPipeline LINK_TRAVERSAL spansetPipeline                        { yylex.(*lexer).expr = newRootExprWithLinkTraversal($1, $3) }
   | root hints                                                            { yylex.(*lexer).expr.withHints($2) }
   ;
 
+metricsRoot:
+    spansetPipeline PIPE metricsAggregation                               { yylex.(*lexer).expr = newRootExprWithMetrics($1, $3) }
+  | spansetPipeline PIPE metricsAggregation metricsSecondStagePipeline    { yylex.(*lexer).expr = newRootExprWithMetricsTwoStage($1, $3, $4) }
+  | metricsExpression                                                     { yylex.(*lexer).expr = newRootExprWithMetricsExpression($1) }
+  | metricsRoot OFFSET DURATION                                           { yylex.(*lexer).expr.withOffset($3) }
+  ;
+
 // **********************
 // Spanset Expressions
 // **********************
@@ -183,7 +208,14 @@ coalesceOperation:
   ;
 
 selectOperation:
-    SELECT OPEN_PARENS attributeList CLOSE_PARENS { $$ = newSelectOperation($3) }
+    SELECT OPEN_PARENS selectList CLOSE_PARENS { $$ = newSelectOperation($3) }
+  ;
+
+selectList:
+    attribute                        { $$ = []FieldExpression{$1} }
+  | functionCall                     { $$ = []FieldExpression{$1} }
+  | selectList COMMA attribute       { $$ = append($1, $3) }
+  | selectList COMMA functionCall    { $$ = append($1, $3) }
   ;
 
 attribute:
@@ -205,6 +237,29 @@ numericList:
   | numericList COMMA INTEGER { $$ = append($1, float64($3))}
   ;
 
+// Explicit bucket boundaries of histogram_over_time and quantile_over_time:
+// buckets=[1, 2, 5], buckets=linear(start, width, count) or buckets=exponential(start, factor, count)
+bucketBoundaries:
+    IDENTIFIER EQ OPEN_BRACKET numericList CLOSE_BRACKET
+      {
+          if $1 != "buckets" {
+            yylex.(*lexer).errorAt("unknown argument: " + $1, $<identifierPos>1)
+          }
+          $$ = $4
+      }
+  | IDENTIFIER EQ IDENTIFIER OPEN_PARENS numericList CLOSE_PARENS
+      {
+          if $1 != "buckets" {
+            yylex.(*lexer).errorAt("unknown argument: " + $1, $<identifierPos>1)
+          }
+          var err error
+          $$, err = newBucketBoundaries($3, $5)
+          if err != nil {
+            yylex.(*lexer).errorAt(err.Error(), $<identifierPos>3)
+          }
+      }
+  ;
+
 spansetExpression: // shares the same operators as scalarPipelineExpression. split out for readability
     OPEN_PARENS spansetExpression CLOSE_PARENS   { $$ = $2 }
   | spansetExpression AND   spansetExpression    { $$ = newSpansetOperation(OpSpansetAnd, $1, $3) }
@@ -317,10 +372,18 @@ metricsAggregation:
     | SUM_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                        { $$ = newMetricsAggregateWithAttr(metricsAggregateSumOverTime, $3, $7) }
     | AVG_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS                                                                  { $$ = newAverageOverTimeMetricsAggregator($3, nil) }
     | AVG_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                        { $$ = newAverageOverTimeMetricsAggregator($3, $7) }
-    | QUANTILE_OVER_TIME OPEN_PARENS attribute COMMA numericList CLOSE_PARENS                                           { $$ = newMetricsAggregateQuantileOverTime($3, $5, nil) }
-    | QUANTILE_OVER_TIME OPEN_PARENS attribute COMMA numericList CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS { $$ = newMetricsAggregateQuantileOverTime($3, $5, $9) }
-    | HISTOGRAM_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS                                                            { $$ = newMetricsAggregateWithAttr(metricsAggregateHistogramOverTime, $3, nil) }
-    | HISTOGRAM_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                  { $$ = newMetricsAggregateWithAttr(metricsAggregateHistogramOverTime, $3, $7) }
+    | QUANTILE_OVER_TIME OPEN_PARENS attribute COMMA numericList CLOSE_PARENS                                           { $$ = newMetricsAggregateQuantileOverTime($3, $5, nil, nil) }
+    | QUANTILE_OVER_TIME OPEN_PARENS attribute COMMA numericList CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS { $$ = newMetricsAggregateQuantileOverTime($3, $5, nil, $9) }
+    | QUANTILE_OVER_TIME OPEN_PARENS attribute COMMA numericList COMMA bucketBoundaries CLOSE_PARENS                    { $$ = newMetricsAggregateQuantileOverTime($3, $5, $7, nil) }
+    | QUANTILE_OVER_TIME OPEN_PARENS attribute COMMA numericList COMMA bucketBoundaries CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS { $$ = newMetricsAggregateQuantileOverTime($3, $5, $7, $11) }
+    | HISTOGRAM_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS                                                            { $$ = newMetricsAggregateHistogramOverTime($3, nil, nil) }
+    | HISTOGRAM_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                  { $$ = newMetricsAggregateHistogramOverTime($3, nil, $7) }
+    | HISTOGRAM_OVER_TIME OPEN_PARENS attribute COMMA bucketBoundaries CLOSE_PARENS                                     { $$ = newMetricsAggregateHistogramOverTime($3, $5, nil) }
+    | HISTOGRAM_OVER_TIME OPEN_PARENS attribute COMMA bucketBoundaries CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS { $$ = newMetricsAggregateHistogramOverTime($3, $5, $9) }
+    | STDDEV_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS                                                               { $$ = newVarianceOverTimeMetricsAggregator(metricsAggregateStddevOverTime, $3, nil) }
+    | STDDEV_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                     { $$ = newVarianceOverTimeMetricsAggregator(metricsAggregateStddevOverTime, $3, $7) }
+    | STDVAR_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS                                                               { $$ = newVarianceOverTimeMetricsAggregator(metricsAggregateStdvarOverTime, $3, nil) }
+    | STDVAR_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                     { $$ = newVarianceOverTimeMetricsAggregator(metricsAggregateStdvarOverTime, $3, $7) }
     | COMPARE OPEN_PARENS spansetFilter CLOSE_PARENS                                                                    { $$ = newMetricsCompare($3, 10, 0, 0)}
     | COMPARE OPEN_PARENS spansetFilter COMMA INTEGER CLOSE_PARENS                                                      { $$ = newMetricsCompare($3, $5, 0, 0)}
     | COMPARE OPEN_PARENS spansetFilter COMMA INTEGER COMMA INTEGER COMMA INTEGER CLOSE_PARENS                          { $$ = newMetricsCompare($3, $5, $7, $9)}
@@ -332,6 +395,23 @@ metricsAggregation:
 metricsSecondStage:
     TOPK OPEN_PARENS INTEGER CLOSE_PARENS                        { $$ = newTopKBottomK(OpTopK, $3) }
     | BOTTOMK OPEN_PARENS INTEGER CLOSE_PARENS                   { $$ = newTopKBottomK(OpBottomK, $3) }
+    | IDENTIFIER OPEN_PARENS CLOSE_PARENS                        { $$ = newSecondStageFunction($1, nil) }
+    | IDENTIFIER OPEN_PARENS metricsSecondStageArgs CLOSE_PARENS { $$ = newSecondStageFunction($1, $3) }
+  ;
+
+metricsSecondStageArgs:
+    metricsSecondStageArg                              { $$ = []Static{$1} }
+  | metricsSecondStageArgs COMMA metricsSecondStageArg { $$ = append($1, $3) }
+  ;
+
+metricsSecondStageArg:
+    STRING        { $$ = NewStaticString($1)     }
+  | INTEGER       { $$ = NewStaticInt($1)        }
+  | FLOAT         { $$ = NewStaticFloat($1)      }
+  | DURATION      { $$ = NewStaticDuration($1)   }
+  | SUB INTEGER   { $$ = NewStaticInt(-$2)       }
+  | SUB FLOAT     { $$ = NewStaticFloat(-$2)     }
+  | SUB DURATION  { $$ = NewStaticDuration(-$2)  }
   ;
 
 // **********************
@@ -365,6 +445,60 @@ metricsSecondStagePipeline:
   | metricsSecondStagePipeline metricsFilter             { $$ = $1; $$.Append($2, " ") }
   ;
 
+// **********************
+// Metrics Binary Operations (arithmetic and comparisons between metrics queries)
+// **********************
+metricsQuery:
+    OPEN_PARENS spansetPipeline PIPE metricsAggregation CLOSE_PARENS                            { $$ = newMetricsQuery($2, $4, ChainedSecondStage{}) }
+  | OPEN_PARENS spansetPipeline PIPE metricsAggregation metricsSecondStagePipeline CLOSE_PARENS { $$ = newMetricsQuery($2, $4, $5) }
+  ;
+
+metricsScalar:
+    INTEGER      { $$ = float64($1) }
+  | FLOAT        { $$ = $1 }
+  | SUB INTEGER  { $$ = float64(-$2) }
+  | SUB FLOAT    { $$ = -$2 }
+  ;
+
+vectorMatching:
+    /* empty */                                                                { $$ = nil }
+  | ON OPEN_PARENS attributeList CLOSE_PARENS                                  { $$ = newVectorMatching(true, $3, matchOneToOne) }
+  | ON OPEN_PARENS attributeList CLOSE_PARENS GROUP_LEFT                       { $$ = newVectorMatching(true, $3, matchManyToOne) }
+  | ON OPEN_PARENS attributeList CLOSE_PARENS GROUP_RIGHT                      { $$ = newVectorMatching(true, $3, matchOneToMany) }
+  | IGNORING OPEN_PARENS attributeList CLOSE_PARENS                            { $$ = newVectorMatching(false, $3, matchOneToOne) }
+  | IGNORING OPEN_PARENS attributeList CLOSE_PARENS GROUP_LEFT                 { $$ = newVectorMatching(false, $3, matchManyToOne) }
+  | IGNORING OPEN_PARENS attributeList CLOSE_PARENS GROUP_RIGHT                { $$ = newVectorMatching(false, $3, matchOneToMany) }
+  ;
+
+metricsExpression:
+    metricsQuery                                                      { $$ = $1 }
+  | OPEN_PARENS metricsExpression CLOSE_PARENS                        { $$ = $2 }
+  | metricsExpression ADD vectorMatching metricsExpression             { $$ = newMetricsBinaryOperation(OpAdd, $1, $4, $3) }
+  | metricsExpression SUB vectorMatching metricsExpression             { $$ = newMetricsBinaryOperation(OpSub, $1, $4, $3) }
+  | metricsExpression MUL vectorMatching metricsExpression             { $$ = newMetricsBinaryOperation(OpMult, $1, $4, $3) }
+  | metricsExpression DIV vectorMatching metricsExpression             { $$ = newMetricsBinaryOperation(OpDiv, $1, $4, $3) }
+  | metricsExpression MOD vectorMatching metricsExpression             { $$ = newMetricsBinaryOperation(OpMod, $1, $4, $3) }
+  | metricsExpression POW vectorMatching metricsExpression             { $$ = newMetricsBinaryOperation(OpPower, $1, $4, $3) }
+  | metricsExpression EQ vectorMatching metricsExpression              { $$ = newMetricsBinaryOperation(OpEqual, $1, $4, $3) }
+  | metricsExpression NEQ vectorMatching metricsExpression             { $$ = newMetricsBinaryOperation(OpNotEqual, $1, $4, $3) }
+  | metricsExpression LT vectorMatching metricsExpression              { $$ = newMetricsBinaryOperation(OpLess, $1, $4, $3) }
+  | metricsExpression LTE vectorMatching metricsExpression             { $$ = newMetricsBinaryOperation(OpLessEqual, $1, $4, $3) }
+  | metricsExpression GT vectorMatching metricsExpression              { $$ = newMetricsBinaryOperation(OpGreater, $1, $4, $3) }
+  | metricsExpression GTE vectorMatching metricsExpression             { $$ = newMetricsBinaryOperation(OpGreaterEqual, $1, $4, $3) }
+  | metricsExpression ADD metricsScalar                                { $$ = newMetricsScalarOperation(OpAdd, $1, $3) }
+  | metricsExpression SUB metricsScalar                                { $$ = newMetricsScalarOperation(OpSub, $1, $3) }
+  | metricsExpression MUL metricsScalar                                { $$ = newMetricsScalarOperation(OpMult, $1, $3) }
+  | metricsExpression DIV metricsScalar                                { $$ = newMetricsScalarOperation(OpDiv, $1, $3) }
+  | metricsExpression MOD metricsScalar                                { $$ = newMetricsScalarOperation(OpMod, $1, $3) }
+  | metricsExpression POW metricsScalar                                { $$ = newMetricsScalarOperation(OpPower, $1, $3) }
+  | metricsExpression EQ metricsScalar                                 { $$ = newMetricsScalarOperation(OpEqual, $1, $3) }
+  | metricsExpression NEQ metricsScalar                                { $$ = newMetricsScalarOperation(OpNotEqual, $1, $3) }
+  | metricsExpression LT metricsScalar                                 { $$ = newMetricsScalarOperation(OpLess, $1, $3) }
+  | metricsExpression LTE metricsScalar                                { $$ = newMetricsScalarOperation(OpLessEqual, $1, $3) }
+  | metricsExpression GT metricsScalar                                 { $$ = newMetricsScalarOperation(OpGreater, $1, $3) }
+  | metricsExpression GTE metricsScalar                                { $$ = newMetricsScalarOperation(OpGreaterEqual, $1, $3) }
+  ;
+
 // **********************
 // Hints
 // **********************
@@ -418,6 +552,16 @@ fieldExpression:
   | intrinsicField                           { $$ = $1 }
   | attributeField                           { $$ = $1 }
   | scopedIntrinsicField                     { $$ = $1 }
+  | functionCall                             { $$ = $1 }
+  ;
+
+functionCall:
+    IDENTIFIER OPEN_PARENS fieldExpressionList CLOSE_PARENS { $$ = newFunctionCall($1, $3) }
+  ;
+
+fieldExpressionList:
+    fieldExpression                           { $$ = []FieldExpression{$1} }
+  | fieldExpressionList COMMA fieldExpression { $$ = append($1, $3) }
   ;
 
 // **********************
@@ -446,7 +590,7 @@ static:
           } else if $1 == "maxInt" {
             $$ = NewStaticInt(math.MaxInt)
           } else {
-            yylex.(*lexer).Error("unknown identifier: " + $1)
+            yylex.(*lexer).errorAt("unknown identifier: " + $1, $<identifierPos>1)
             $$ = NewStaticNil()
           }
       }
@@ -5,16 +5,18 @@ import __yyfmt__ "fmt"
 
 import (
 	"math"
+	"text/scanner"
 	"time"
 )
 
 type yySymType struct {
-	yys               int
-	root              RootExpr
-	groupOperation    GroupOperation
-	coalesceOperation CoalesceOperation
-	selectOperation   SelectOperation
-	attributeList     []Attribute
+	yys                 int
+	root                RootExpr
+	groupOperation      GroupOperation
+	coalesceOperation   CoalesceOperation
+	selectOperation     SelectOperation
+	attributeList       []Attribute
+	fieldExpressionList []FieldExpression
 
 	spansetExpression         SpansetExpression
 	spansetPipelineExpression SpansetExpression
@@ -33,6 +35,10 @@ type yySymType struct {
 	metricsAggregation             firstStageElement
 	metricsSecondStage             secondStageElement
 	metricsSecondStagePipeline     ChainedSecondStage
+	metricsSecondStageArgs         []Static
+	metricsExpression              metricsExpression
+	metricsQuery                   *metricsQuery
+	vectorMatching                 *VectorMatching
 
 	fieldExpression      FieldExpression
 	static               Static
@@ -46,7 +52,9 @@ type yySymType struct {
 	staticStr      string
 	staticFloat    float64
 	staticDuration time.Duration
-	numericList    []float64
+	// position of an IDENTIFIER, the parser may have read past it when reporting an error about it
+	identifierPos scanner.Position
+	numericList   []float64
 
 	hint     *Hint
 	hintList []*Hint
@@ -63,101 +71,111 @@ const OPEN_BRACE = 57352
 const CLOSE_BRACE = 57353
 const OPEN_PARENS = 57354
 const CLOSE_PARENS = 57355
-const COMMA = 57356
-const NIL = 57357
-const TRUE = 57358
-const FALSE = 57359
-const STATUS_ERROR = 57360
-const STATUS_OK = 57361
-const STATUS_UNSET = 57362
-const KIND_UNSPECIFIED = 57363
-const KIND_INTERNAL = 57364
-const KIND_SERVER = 57365
-const KIND_CLIENT = 57366
-const KIND_PRODUCER = 57367
-const KIND_CONSUMER = 57368
-const IDURATION = 57369
-const CHILDCOUNT = 57370
-const NAME = 57371
-const STATUS = 57372
-const STATUS_MESSAGE = 57373
-const PARENT = 57374
-const KIND = 57375
-const ROOTNAME = 57376
-const ROOTSERVICENAME = 57377
-const ROOTSERVICE = 57378
-const TRACEDURATION = 57379
-const NESTEDSETLEFT = 57380
-const NESTEDSETRIGHT = 57381
-const NESTEDSETPARENT = 57382
-const ID = 57383
-const TRACE_ID = 57384
-const SPAN_ID = 57385
-const PARENT_ID = 57386
-const TIMESINCESTART = 57387
-const VERSION = 57388
-const PARENT_DOT = 57389
-const RESOURCE_DOT = 57390
-const SPAN_DOT = 57391
-const TRACE_COLON = 57392
-const SPAN_COLON = 57393
-const EVENT_COLON = 57394
-const EVENT_DOT = 57395
-const LINK_COLON = 57396
-const LINK_DOT = 57397
-const INSTRUMENTATION_COLON = 57398
-const INSTRUMENTATION_DOT = 57399
-const COUNT = 57400
-const AVG = 57401
-const MAX = 57402
-const MIN = 57403
-const SUM = 57404
-const BY = 57405
-const COALESCE = 57406
-const SELECT = 57407
-const END_ATTRIBUTE = 57408
-const RATE = 57409
-const COUNT_OVER_TIME = 57410
-const MIN_OVER_TIME = 57411
-const MAX_OVER_TIME = 57412
-const AVG_OVER_TIME = 57413
-const SUM_OVER_TIME = 57414
-const QUANTILE_OVER_TIME = 57415
-const HISTOGRAM_OVER_TIME = 57416
-const COMPARE = 57417
-const TOPK = 57418
-const BOTTOMK = 57419
-const WITH = 57420
-const PIPE = 57421
-const AND = 57422
-const OR = 57423
-const EQ = 57424
-const NEQ = 57425
-const LT = 57426
-const LTE = 57427
-const GT = 57428
-const GTE = 57429
-const NRE = 57430
-const RE = 57431
-const DESC = 57432
-const ANCE = 57433
-const SIBL = 57434
-const NOT_CHILD = 57435
-const NOT_PARENT = 57436
-const NOT_DESC = 57437
-const NOT_ANCE = 57438
-const UNION_CHILD = 57439
-const UNION_PARENT = 57440
-const UNION_DESC = 57441
-const UNION_ANCE = 57442
-const UNION_SIBL = 57443
-const ADD = 57444
-const SUB = 57445
-const NOT = 57446
-const MUL = 57447
-const DIV = 57448
-const MOD = 57449
-const POW = 57450
+const OPEN_BRACKET = 57356
+const CLOSE_BRACKET = 57357
+const COMMA = 57358
+const NIL = 57359
+const TRUE = 57360
+const FALSE = 57361
+const STATUS_ERROR = 57362
+const STATUS_OK = 57363
+const STATUS_UNSET = 57364
+const KIND_UNSPECIFIED = 57365
+const KIND_INTERNAL = 57366
+const KIND_SERVER = 57367
+const KIND_CLIENT = 57368
+const KIND_PRODUCER = 57369
+const KIND_CONSUMER = 57370
+const IDURATION = 57371
+const CHILDCOUNT = 57372
+const NAME = 57373
+const STATUS = 57374
+const STATUS_MESSAGE = 57375
+const PARENT = 57376
+const KIND = 57377
+const ROOTNAME = 57378
+const ROOTSERVICENAME = 57379
+const ROOTSERVICE = 57380
+const TRACEDURATION = 57381
+const NESTEDSETLEFT = 57382
+const NESTEDSETRIGHT = 57383
+const NESTEDSETPARENT = 57384
+const ID = 57385
+const TRACE_ID = 57386
+const SPAN_ID = 57387
+const PARENT_ID = 57388
+const TIMESINCESTART = 57389
+const VERSION = 57390
+const PARENT_DOT = 57391
+const RESOURCE_DOT = 57392
+const SPAN_DOT = 57393
+const TRACE_COLON = 57394
+const SPAN_COLON = 57395
+const EVENT_COLON = 57396
+const EVENT_DOT = 57397
+const LINK_COLON = 57398
+const LINK_DOT = 57399
+const INSTRUMENTATION_COLON = 57400
+const INSTRUMENTATION_DOT = 57401
+const COUNT = 57402
+const AVG = 57403
+const MAX = 57404
+const MIN = 57405
+const SUM = 57406
+const BY = 57407
+const COALESCE = 57408
+const SELECT = 57409
+const END_ATTRIBUTE = 57410
+const RATE = 57411
+const COUNT_OVER_TIME = 57412
+const MIN_OVER_TIME = 57413
+const MAX_OVER_TIME = 57414
+const AVG_OVER_TIME = 57415
+const SUM_OVER_TIME = 57416
+const QUANTILE_OVER_TIME = 57417
+const HISTOGRAM_OVER_TIME = 57418
+const STDDEV_OVER_TIME = 57419
+const STDVAR_OVER_TIME = 57420
+const COMPARE = 57421
+const TOPK = 57422
+const BOTTOMK = 57423
+const ON = 57424
+const IGNORING = 57425
+const GROUP_LEFT = 57426
+const GROUP_RIGHT = 57427
+const WITH = 57428
+const OFFSET = 57429
+const LINK_TRAVERSAL = 57430
+const PIPE = 57431
+const AND = 57432
+const OR = 57433
+const EQ = 57434
+const NEQ = 57435
+const LT = 57436
+const LTE = 57437
+const GT = 57438
+const GTE = 57439
+const NRE = 57440
+const RE = 57441
+const DESC = 57442
+const ANCE = 57443
+const SIBL = 57444
+const NOT_CHILD = 57445
+const NOT_PARENT = 57446
+const NOT_DESC = 57447
+const NOT_ANCE = 57448
+const UNION_CHILD = 57449
+const UNION_PARENT = 57450
+const UNION_DESC = 57451
+const UNION_ANCE = 57452
+const UNION_SIBL = 57453
+const ADD = 57454
+const SUB = 57455
+const NOT = 57456
+const MUL = 57457
+const DIV = 57458
+const MOD = 57459
+const POW = 57460
 
 var yyToknames = [...]string{
 	"$end",
@@ -173,6 +191,8 @@ var yyToknames = [...]string{
 	"CLOSE_BRACE",
 	"OPEN_PARENS",
 	"CLOSE_PARENS",
+	"OPEN_BRACKET",
+	"CLOSE_BRACKET",
 	"COMMA",
 	"NIL",
 	"TRUE",
@@ -234,10 +254,18 @@ var yyToknames = [...]string{
 	"SUM_OVER_TIME",
 	"QUANTILE_OVER_TIME",
 	"HISTOGRAM_OVER_TIME",
+	"STDDEV_OVER_TIME",
+	"STDVAR_OVER_TIME",
 	"COMPARE",
 	"TOPK",
 	"BOTTOMK",
+	"ON",
+	"IGNORING",
+	"GROUP_LEFT",
+	"GROUP_RIGHT",
 	"WITH",
+	"OFFSET",
+	"LINK_TRAVERSAL",
 	"PIPE",
 	"AND",
 	"OR",
@@ -280,231 +308,298 @@ var yyExca = [...]int16{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 316,
-	13, 87,
-	-2, 95,
+	-1, 376,
+	13, 97,
+	-2, 105,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 1250
+const yyLast = 1663
 
 var yyAct = [...]int16{
-	102, 6, 5, 8, 7, 99, 363, 101, 12, 290,
-	294, 18, 67, 238, 239, 240, 249, 249, 231, 230,
-	90, 13, 77, 360, 94, 207, 100, 314, 2, 254,
-	253, 70, 154, 153, 157, 155, 30, 66, 236, 237,
-	29, 238, 239, 240, 249, 87, 88, 89, 90, 206,
-	383, 187, 189, 190, 191, 192, 193, 194, 195, 196,
-	197, 198, 199, 200, 201, 202, 203, 204, 366, 367,
-	368, 78, 79, 80, 81, 82, 83, 213, 241, 242,
-	243, 244, 245, 246, 248, 247, 74, 75, 76, 77,
-	436, 85, 86, 234, 87, 88, 89, 90, 236, 237,
-	233, 238, 239, 240, 249, 206, 221, 223, 224, 225,
-	226, 227, 228, 364, 365, 380, 229, 382, 353, 232,
-	252, 352, 255, 256, 351, 348, 85, 86, 357, 87,
-	88, 89, 90, 118, 103, 104, 105, 108, 131, 347,
-	93, 95, 346, 345, 96, 106, 107, 110, 109, 111,
-	112, 113, 114, 115, 116, 117, 119, 211, 120, 121,
-	122, 124, 123, 125, 126, 369, 127, 128, 129, 130,
-	311, 207, 285, 286, 287, 288, 134, 132, 133, 138,
-	139, 140, 135, 141, 136, 142, 137, 413, 409, 312,
-	408, 311, 278, 407, 406, 250, 251, 241, 242, 243,
-	244, 245, 246, 248, 247, 393, 392, 282, 279, 154,
-	153, 157, 155, 208, 280, 281, 445, 236, 237, 316,
-	238, 239, 240, 249, 283, 398, 78, 79, 80, 81,
-	82, 83, 97, 98, 78, 79, 80, 81, 82, 83,
-	448, 321, 318, 449, 312, 416, 85, 86, 415, 87,
-	88, 89, 90, 399, 72, 73, 397, 74, 75, 76,
-	77, 322, 323, 324, 325, 326, 327, 329, 331, 332,
-	333, 334, 335, 336, 337, 338, 339, 396, 341, 343,
-	48, 53, 356, 395, 50, 394, 49, 379, 57, 371,
-	51, 52, 54, 55, 56, 59, 58, 60, 61, 64,
-	63, 62, 260, 362, 370, 234, 234, 234, 234, 234,
-	234, 443, 233, 233, 233, 233, 233, 233, 67, 378,
-	67, 284, 234, 372, 373, 374, 375, 376, 377, 233,
-	210, 232, 232, 232, 232, 232, 232, 70, 425, 70,
-	381, 444, 321, 442, 321, 318, 261, 262, 232, 250,
-	251, 241, 242, 243, 244, 245, 246, 248, 247, 441,
-	321, 440, 321, 17, 211, 188, 385, 422, 386, 384,
-	421, 236, 237, 420, 238, 239, 240, 249, 439, 321,
-	429, 321, 154, 153, 157, 155, 50, 419, 49, 405,
-	57, 404, 51, 52, 54, 55, 56, 59, 58, 60,
-	61, 64, 63, 62, 388, 234, 234, 428, 321, 426,
-	427, 361, 233, 233, 296, 297, 298, 299, 300, 301,
-	234, 234, 234, 234, 417, 418, 234, 233, 233, 233,
-	233, 232, 232, 233, 424, 423, 400, 401, 387, 430,
-	431, 432, 433, 313, 234, 437, 232, 232, 232, 232,
-	310, 233, 232, 85, 86, 309, 87, 88, 89, 90,
-	358, 359, 308, 446, 118, 103, 104, 105, 108, 131,
-	232, 307, 95, 306, 305, 344, 106, 107, 110, 109,
-	111, 112, 113, 114, 115, 116, 117, 119, 304, 120,
-	121, 122, 124, 123, 125, 126, 303, 127, 128, 129,
-	130, 320, 321, 302, 214, 170, 151, 134, 132, 133,
-	138, 139, 140, 135, 141, 136, 142, 137, 150, 149,
-	148, 147, 118, 103, 104, 105, 108, 131, 146, 92,
-	95, 91, 17, 342, 106, 107, 110, 109, 111, 112,
-	113, 114, 115, 116, 117, 119, 447, 120, 121, 122,
-	124, 123, 125, 126, 438, 127, 128, 129, 130, 389,
-	390, 391, 414, 97, 98, 134, 132, 133, 138, 139,
-	140, 135, 141, 136, 142, 137, 403, 84, 435, 434,
-	118, 103, 104, 105, 108, 131, 412, 411, 95, 71,
-	402, 96, 106, 107, 110, 109, 111, 112, 113, 114,
-	115, 116, 117, 119, 291, 120, 121, 122, 124, 123,
-	125, 126, 350, 127, 128, 129, 130, 143, 144, 145,
-	349, 97, 98, 134, 132, 133, 138, 139, 140, 135,
-	141, 136, 142, 137, 265, 264, 263, 259, 118, 103,
-	104, 105, 108, 131, 258, 257, 95, 28, 289, 330,
-	106, 107, 110, 109, 111, 112, 113, 114, 115, 116,
-	117, 119, 410, 120, 121, 122, 124, 123, 125, 126,
-	69, 127, 128, 129, 130, 16, 4, 295, 292, 97,
-	98, 134, 132, 133, 138, 139, 140, 135, 141, 136,
-	142, 137, 355, 152, 10, 156, 118, 103, 104, 105,
-	108, 131, 1, 0, 95, 0, 0, 328, 106, 107,
-	110, 109, 111, 112, 113, 114, 115, 116, 117, 119,
-	0, 120, 121, 122, 124, 123, 125, 126, 0, 127,
-	128, 129, 130, 0, 0, 0, 0, 97, 98, 134,
-	132, 133, 138, 139, 140, 135, 141, 136, 142, 137,
-	19, 20, 21, 0, 17, 0, 167, 235, 0, 250,
-	251, 241, 242, 243, 244, 245, 246, 248, 247, 354,
-	72, 73, 0, 74, 75, 76, 77, 0, 0, 0,
-	0, 236, 237, 0, 238, 239, 240, 249, 0, 0,
-	0, 0, 0, 0, 0, 97, 98, 0, 340, 0,
-	0, 0, 23, 26, 24, 25, 27, 14, 168, 15,
-	319, 158, 159, 160, 161, 163, 162, 164, 165, 166,
-	0, 0, 0, 0, 0, 0, 250, 251, 241, 242,
-	243, 244, 245, 246, 248, 247, 250, 251, 241, 242,
-	243, 244, 245, 246, 248, 247, 205, 22, 236, 237,
-	0, 238, 239, 240, 249, 0, 0, 0, 236, 237,
-	0, 238, 239, 240, 249, 250, 251, 241, 242, 243,
-	244, 245, 246, 248, 247, 0, 0, 250, 251, 241,
-	242, 243, 244, 245, 246, 248, 247, 236, 237, 0,
-	238, 239, 240, 249, 0, 0, 0, 0, 0, 236,
-	237, 266, 238, 239, 240, 249, 0, 0, 267, 0,
-	268, 0, 0, 31, 36, 269, 0, 33, 0, 32,
-	0, 42, 0, 34, 35, 37, 38, 39, 40, 41,
-	43, 44, 45, 46, 47, 48, 53, 0, 0, 50,
-	0, 49, 0, 57, 0, 51, 52, 54, 55, 56,
-	59, 58, 60, 61, 64, 63, 62, 31, 36, 0,
-	0, 33, 0, 32, 0, 42, 209, 34, 35, 37,
-	38, 39, 40, 41, 43, 44, 45, 46, 47, 19,
-	20, 21, 0, 17, 0, 167, 0, 19, 20, 21,
-	0, 17, 0, 317, 0, 19, 20, 21, 33, 17,
-	32, 315, 42, 0, 34, 35, 37, 38, 39, 40,
-	41, 43, 44, 45, 46, 47, 19, 20, 21, 0,
-	17, 293, 9, 0, 296, 297, 298, 299, 300, 301,
-	0, 23, 26, 24, 25, 27, 14, 168, 15, 23,
-	26, 24, 25, 27, 14, 0, 15, 23, 26, 24,
-	25, 27, 14, 0, 15, 72, 73, 0, 74, 75,
-	76, 77, 65, 3, 0, 0, 0, 0, 23, 26,
-	24, 25, 27, 14, 0, 15, 22, 19, 20, 21,
-	0, 17, 0, 167, 22, 19, 20, 21, 68, 11,
-	0, 222, 22, 0, 169, 171, 172, 173, 174, 175,
-	176, 177, 178, 179, 180, 181, 182, 183, 184, 185,
-	186, 0, 0, 22, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 23,
-	26, 24, 25, 27, 0, 0, 0, 23, 26, 24,
-	25, 27, 270, 277, 271, 273, 274, 0, 272, 0,
-	0, 0, 0, 0, 0, 131, 275, 0, 0, 276,
-	212, 215, 216, 217, 218, 219, 220, 0, 0, 0,
-	0, 0, 0, 119, 22, 120, 121, 122, 124, 123,
-	125, 126, 22, 127, 128, 129, 130, 0, 0, 0,
-	0, 0, 0, 134, 132, 133, 138, 139, 140, 135,
-	141, 136, 142, 137, 118, 103, 104, 105, 108, 0,
-	0, 0, 214, 0, 0, 0, 106, 107, 110, 109,
-	111, 112, 113, 114, 115, 116, 117, 118, 103, 104,
-	105, 108, 0, 0, 0, 0, 0, 0, 0, 106,
-	107, 110, 109, 111, 112, 113, 114, 115, 116, 117,
+	120, 525, 509, 7, 443, 21, 9, 521, 8, 112,
+	354, 347, 305, 121, 374, 2, 117, 352, 76, 13,
+	119, 14, 95, 6, 108, 71, 297, 298, 299, 300,
+	301, 302, 304, 303, 72, 83, 310, 309, 175, 552,
+	118, 178, 234, 176, 440, 229, 292, 293, 171, 294,
+	295, 296, 305, 294, 295, 296, 305, 229, 174, 15,
+	468, 469, 292, 293, 500, 294, 295, 296, 305, 465,
+	75, 92, 93, 94, 95, 33, 34, 210, 212, 213,
+	214, 215, 216, 217, 218, 219, 220, 221, 222, 223,
+	224, 225, 226, 227, 237, 90, 91, 349, 92, 93,
+	94, 95, 103, 104, 52, 105, 106, 107, 108, 78,
+	79, 289, 80, 81, 82, 83, 32, 533, 534, 283,
+	476, 349, 475, 308, 286, 311, 312, 105, 106, 107,
+	108, 288, 433, 230, 22, 23, 24, 432, 20, 431,
+	172, 103, 104, 437, 105, 106, 107, 108, 447, 448,
+	449, 287, 531, 532, 22, 23, 24, 428, 20, 427,
+	172, 426, 275, 277, 278, 279, 280, 281, 282, 425,
+	588, 569, 285, 567, 528, 527, 342, 343, 344, 345,
+	80, 81, 82, 83, 524, 520, 250, 251, 26, 29,
+	27, 28, 30, 16, 190, 17, 350, 179, 180, 181,
+	182, 184, 183, 185, 186, 187, 188, 189, 26, 29,
+	27, 28, 30, 16, 190, 17, 519, 518, 517, 487,
+	306, 307, 297, 298, 299, 300, 301, 302, 304, 303,
+	486, 587, 351, 492, 175, 350, 376, 178, 232, 176,
+	335, 25, 292, 293, 377, 294, 295, 296, 305, 510,
+	511, 512, 513, 379, 174, 450, 336, 507, 446, 599,
+	339, 25, 248, 249, 579, 380, 510, 511, 512, 513,
+	337, 338, 386, 571, 387, 317, 388, 340, 389, 173,
+	390, 551, 391, 570, 392, 536, 393, 535, 394, 496,
+	395, 441, 396, 252, 356, 357, 358, 359, 360, 361,
+	592, 591, 400, 401, 402, 403, 404, 405, 407, 409,
+	410, 411, 412, 413, 414, 415, 416, 417, 495, 419,
+	421, 318, 319, 424, 306, 307, 297, 298, 299, 300,
+	301, 302, 304, 303, 444, 445, 351, 78, 79, 491,
+	80, 81, 82, 83, 490, 601, 292, 293, 501, 294,
+	295, 296, 305, 175, 489, 488, 178, 514, 176, 598,
+	462, 597, 591, 442, 501, 289, 289, 289, 289, 289,
+	289, 289, 289, 174, 514, 596, 586, 585, 501, 501,
+	501, 452, 451, 289, 289, 288, 288, 288, 288, 288,
+	288, 288, 288, 379, 461, 464, 466, 341, 581, 72,
+	289, 501, 72, 288, 288, 287, 287, 287, 287, 287,
+	287, 287, 287, 472, 499, 578, 577, 233, 501, 501,
+	288, 20, 576, 287, 287, 501, 453, 454, 455, 456,
+	457, 458, 459, 460, 323, 75, 575, 595, 75, 501,
+	287, 324, 559, 325, 470, 501, 479, 558, 326, 555,
+	501, 477, 556, 549, 209, 537, 548, 478, 538, 353,
+	584, 471, 356, 357, 358, 359, 360, 361, 582, 175,
+	503, 376, 178, 501, 176, 442, 580, 22, 23, 24,
+	554, 20, 466, 172, 504, 502, 553, 550, 501, 174,
+	441, 72, 547, 356, 357, 358, 359, 360, 361, 20,
+	497, 211, 289, 498, 175, 493, 102, 178, 494, 176,
+	473, 438, 398, 474, 439, 399, 289, 289, 546, 77,
+	545, 544, 288, 516, 174, 515, 482, 75, 481, 480,
+	313, 26, 29, 27, 28, 30, 288, 288, 381, 383,
+	382, 557, 287, 373, 372, 289, 289, 289, 289, 371,
+	566, 289, 370, 369, 289, 289, 287, 287, 368, 367,
+	366, 365, 364, 530, 363, 288, 288, 288, 288, 362,
+	238, 288, 192, 170, 288, 288, 542, 543, 583, 169,
+	168, 289, 167, 289, 25, 287, 287, 287, 287, 166,
+	165, 287, 593, 110, 287, 287, 289, 109, 539, 540,
+	541, 288, 594, 288, 235, 560, 561, 562, 563, 574,
+	526, 568, 565, 564, 572, 573, 288, 529, 55, 506,
+	54, 287, 62, 287, 56, 57, 59, 60, 61, 64,
+	63, 65, 66, 69, 68, 67, 287, 483, 484, 485,
+	505, 589, 526, 590, 137, 122, 123, 124, 127, 150,
+	348, 111, 113, 162, 163, 164, 600, 114, 125, 126,
+	129, 128, 130, 131, 132, 133, 134, 135, 136, 138,
+	430, 139, 140, 141, 143, 142, 144, 145, 429, 146,
+	147, 148, 149, 96, 97, 98, 99, 100, 101, 153,
+	151, 152, 157, 158, 159, 154, 160, 155, 161, 156,
+	565, 564, 322, 90, 91, 321, 92, 93, 94, 95,
+	137, 122, 123, 124, 127, 150, 523, 522, 113, 384,
+	385, 320, 316, 114, 125, 126, 129, 128, 130, 131,
+	132, 133, 134, 135, 136, 138, 315, 139, 140, 141,
+	143, 142, 144, 145, 314, 146, 147, 148, 149, 5,
+	31, 346, 423, 115, 116, 153, 151, 152, 157, 158,
+	159, 154, 160, 155, 161, 156, 74, 18, 327, 334,
+	328, 330, 331, 4, 329, 19, 137, 122, 123, 124,
+	127, 150, 332, 355, 113, 333, 508, 11, 284, 422,
+	125, 126, 129, 128, 130, 131, 132, 133, 134, 135,
+	136, 138, 177, 139, 140, 141, 143, 142, 144, 145,
+	1, 146, 147, 148, 149, 0, 0, 0, 0, 115,
+	116, 153, 151, 152, 157, 158, 159, 154, 160, 155,
+	161, 156, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 137, 122, 123, 124, 127, 150, 0, 0,
+	113, 0, 0, 0, 0, 420, 125, 126, 129, 128,
+	130, 131, 132, 133, 134, 135, 136, 138, 0, 139,
+	140, 141, 143, 142, 144, 145, 0, 146, 147, 148,
+	149, 0, 0, 0, 0, 115, 116, 153, 151, 152,
+	157, 158, 159, 154, 160, 155, 161, 156, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 137, 122,
+	123, 124, 127, 150, 0, 0, 113, 0, 0, 0,
+	0, 408, 125, 126, 129, 128, 130, 131, 132, 133,
+	134, 135, 136, 138, 0, 139, 140, 141, 143, 142,
+	144, 145, 0, 146, 147, 148, 149, 0, 0, 0,
+	0, 115, 116, 153, 151, 152, 157, 158, 159, 154,
+	160, 155, 161, 156, 436, 0, 0, 0, 0, 0,
+	0, 0, 291, 0, 137, 122, 123, 124, 127, 150,
+	0, 0, 113, 0, 0, 0, 0, 406, 125, 126,
+	129, 128, 130, 131, 132, 133, 134, 135, 136, 138,
+	0, 139, 140, 141, 143, 142, 144, 145, 435, 146,
+	147, 148, 149, 0, 0, 0, 0, 115, 116, 153,
+	151, 152, 157, 158, 159, 154, 160, 155, 161, 156,
+	0, 0, 247, 0, 0, 0, 0, 434, 0, 0,
+	0, 306, 307, 297, 298, 299, 300, 301, 302, 304,
+	303, 306, 307, 297, 298, 299, 300, 301, 302, 304,
+	303, 0, 0, 292, 293, 0, 294, 295, 296, 305,
+	418, 0, 0, 292, 293, 0, 294, 295, 296, 305,
+	397, 0, 0, 115, 116, 306, 307, 297, 298, 299,
+	300, 301, 302, 304, 303, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 292, 293, 234,
+	294, 295, 296, 305, 306, 307, 297, 298, 299, 300,
+	301, 302, 304, 303, 254, 256, 258, 260, 262, 264,
+	266, 268, 270, 272, 274, 0, 292, 293, 0, 294,
+	295, 296, 305, 246, 0, 0, 0, 306, 307, 297,
+	298, 299, 300, 301, 302, 304, 303, 306, 307, 297,
+	298, 299, 300, 301, 302, 304, 303, 0, 0, 292,
+	293, 0, 294, 295, 296, 305, 0, 0, 0, 292,
+	293, 231, 294, 295, 296, 305, 0, 0, 84, 85,
+	86, 87, 88, 89, 0, 84, 85, 86, 87, 88,
+	89, 0, 96, 97, 98, 99, 100, 101, 103, 104,
+	228, 105, 106, 107, 108, 103, 104, 0, 105, 106,
+	107, 108, 90, 91, 0, 92, 93, 94, 95, 84,
+	85, 86, 87, 88, 89, 253, 255, 257, 259, 261,
+	263, 265, 267, 269, 271, 273, 0, 0, 0, 78,
+	79, 0, 80, 81, 82, 83, 0, 0, 53, 58,
+	0, 0, 55, 0, 54, 0, 62, 0, 56, 57,
+	59, 60, 61, 64, 63, 65, 66, 69, 68, 67,
+	0, 0, 0, 0, 0, 0, 0, 35, 40, 0,
+	0, 37, 0, 36, 0, 46, 0, 38, 39, 41,
+	42, 43, 44, 45, 47, 48, 49, 50, 51, 53,
+	58, 0, 0, 55, 0, 54, 0, 62, 0, 56,
+	57, 59, 60, 61, 64, 63, 65, 66, 69, 68,
+	67, 35, 40, 0, 0, 37, 0, 36, 0, 46,
+	0, 38, 39, 41, 42, 43, 44, 45, 47, 48,
+	49, 50, 51, 22, 23, 24, 0, 20, 0, 467,
+	0, 22, 23, 24, 0, 20, 0, 378, 0, 22,
+	23, 24, 37, 20, 36, 375, 46, 0, 38, 39,
+	41, 42, 43, 44, 45, 47, 48, 49, 50, 51,
+	22, 23, 24, 0, 20, 0, 172, 0, 22, 23,
+	24, 0, 20, 0, 10, 0, 0, 26, 29, 27,
+	28, 30, 16, 0, 17, 26, 29, 27, 28, 30,
+	16, 0, 17, 26, 29, 27, 28, 30, 16, 0,
+	17, 22, 23, 24, 463, 0, 0, 276, 0, 0,
+	0, 73, 12, 0, 26, 29, 27, 28, 30, 16,
+	0, 17, 26, 29, 27, 28, 30, 16, 0, 17,
+	25, 0, 0, 0, 0, 0, 0, 0, 25, 0,
+	0, 0, 0, 0, 0, 0, 25, 0, 0, 0,
+	0, 0, 0, 0, 0, 26, 29, 27, 28, 30,
+	0, 0, 0, 0, 0, 0, 0, 25, 0, 0,
+	0, 0, 0, 0, 290, 25, 0, 0, 0, 150,
+	353, 0, 0, 356, 357, 358, 359, 360, 361, 236,
+	240, 241, 242, 243, 244, 245, 0, 0, 0, 138,
+	0, 139, 140, 141, 143, 142, 144, 145, 25, 146,
+	147, 148, 149, 150, 0, 0, 0, 0, 0, 153,
+	151, 152, 157, 158, 159, 154, 160, 155, 161, 156,
+	70, 3, 0, 138, 0, 139, 140, 141, 143, 142,
+	144, 145, 0, 146, 147, 148, 149, 0, 0, 0,
+	0, 0, 0, 153, 151, 152, 157, 158, 159, 154,
+	160, 155, 161, 156, 0, 0, 191, 193, 194, 195,
+	196, 197, 198, 199, 200, 201, 202, 203, 204, 205,
+	206, 207, 208, 239, 122, 123, 124, 127, 0, 0,
+	0, 238, 0, 0, 0, 0, 0, 125, 126, 129,
+	128, 130, 131, 132, 133, 134, 135, 136, 239, 122,
+	123, 124, 127, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 125, 126, 129, 128, 130, 131, 132, 133,
+	134, 135, 136,
 }
 
 var yyPact = [...]int16{
-	1010, -38, -43, 877, -1000, 855, -1000, -1000, -1000, 1010,
-	-1000, 152, -1000, -11, 519, 517, -1000, 129, -1000, -1000,
-	-1000, -1000, 611, 516, 509, 508, 507, 506, -1000, 494,
-	744, 493, 493, 493, 493, 493, 493, 493, 493, 493,
-	493, 493, 493, 493, 493, 493, 493, 493, 353, 353,
-	353, 353, 353, 353, 353, 353, 353, 353, 353, 353,
-	353, 353, 353, 353, 353, 833, 92, 200, 953, 317,
-	144, 1200, 492, 492, 492, 492, 492, 492, -1000, -1000,
-	-1000, -1000, -1000, -1000, 1079, 1079, 1079, 1079, 1079, 1079,
-	1079, 576, 1146, -1000, 746, 576, -53, 576, 576, -1000,
+	1392, 30, -13, 1241, -1000, 17, 1219, -1000, -1000, -1000,
+	1392, -1000, 1137, 1110, -1000, 1103, 585, 581, -1000, -1000,
+	640, -1000, -1000, -1000, -1000, 647, 578, 577, 570, 568,
+	567, -1000, 561, 1384, 128, 560, 560, 560, 560, 560,
+	560, 560, 560, 560, 560, 560, 560, 560, 560, 560,
+	560, 560, 446, 489, 489, 489, 489, 489, 489, 489,
+	489, 489, 489, 489, 489, 489, 489, 489, 489, 489,
+	1197, 44, 1168, 225, 404, 1096, 591, 1609, 558, 558,
+	558, 558, 558, 558, -1000, -1000, -1000, -1000, -1000, -1000,
+	180, 180, 180, 180, 180, 180, 180, 180, 180, 180,
+	180, 180, 1425, 1425, 1425, 1425, 1425, 1425, 1425, 706,
+	1500, -1000, 961, 706, -56, 706, 706, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 518, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	740, 732, 718, 271, 717, 701, 698, 405, 739, 209,
+	226, 229, -1000, -1000, -1000, 384, 706, 706, 706, 706,
+	646, 8, 471, 370, 1219, -1000, -1000, -1000, -1000, 557,
+	552, 550, 549, 548, 547, 546, 541, 540, 537, 532,
+	531, 1278, 1363, -1000, -1000, -1000, -1000, 1278, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 641, 640, 633, 298, 632, 631, 630, 874, 1115,
-	163, 172, 178, -1000, -1000, -1000, 308, 576, 576, 576,
-	576, 600, 942, 855, -1000, -1000, -1000, -1000, 491, 484,
-	476, 462, 461, 459, 450, 443, 438, 1071, 431, 914,
-	989, -1000, -1000, -1000, -1000, 914, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 302, 353, -1000,
-	-1000, -1000, -1000, 302, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 973, -1000, -1000,
-	-1000, -1000, 668, -1000, 981, -19, -19, -86, -86, -86,
-	-86, 24, 1079, -60, -60, -88, -88, -88, -88, 797,
-	488, -1000, -1000, -1000, -1000, -1000, 576, 576, 576, 576,
-	576, 692, 634, 576, 576, 576, 576, 576, 576, 576,
-	576, 576, 785, 518, 460, -92, -92, 77, 76, 73,
-	59, 616, 608, 58, 55, 52, -1000, -1000, -1000, -1000,
+	524, 489, -1000, -1000, -1000, -1000, 524, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, 756, 679, 269, 115, 447,
-	-1000, -59, 332, 37, -1000, 62, -1000, -1000, -1000, -1000,
-	-1000, -1000, 291, 276, 1146, 1146, 1146, 1146, 1146, 1146,
-	522, 200, 351, 274, 36, 989, -1000, 981, -54, -1000,
-	-1000, 1146, -92, -92, -91, -91, -91, -64, -1000, -64,
-	-1000, -64, -64, -64, -64, -64, -64, -91, -4, -4,
-	-1000, -64, -1000, -64, -1000, -1000, -1000, -1000, -1000, 51,
-	-16, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 600,
-	1223, 37, -1000, -1000, 426, 392, -1000, -1000, -1000, 553,
-	143, 142, 272, 270, 264, 243, 211, 240, 423, -1000,
-	973, -1000, -1000, -1000, -1000, -1000, -1000, 584, 570, -1000,
-	-1000, -1000, 379, 377, 131, 130, 127, 125, 580, 124,
-	-1000, 556, 235, 232, 1146, 1146, 375, 361, 358, 355,
-	421, -1000, -1000, 326, 396, -1000, -1000, 394, 367, 1146,
-	1146, 1146, 1146, 572, 27, 1146, -1000, 548, -1000, -1000,
-	365, 348, 346, 330, -1000, -1000, 299, 328, 202, -1000,
-	-1000, -1000, -1000, 1146, -1000, 540, 227, 230, -1000, -1000,
+	128, -1000, -1000, -1000, -1000, -1000, -3, -1000, 1355, -1000,
+	65, 65, -83, -83, -83, -83, 526, -1000, 528, 527,
+	-1000, -1000, 713, 526, -1000, 526, -1000, 526, -1000, 526,
+	-1000, 526, -1000, 526, -1000, 526, -1000, 526, -1000, 526,
+	-1000, 526, -1000, 526, -1000, -10, 1425, 12, 12, -94,
+	-94, -94, -94, 1067, 499, -1000, -1000, -1000, -1000, -1000,
+	518, -1000, 706, 706, 706, 706, 706, 970, 904, 706,
+	706, 706, 706, 706, 706, 706, 706, 706, 1057, 838,
+	772, -62, -62, 706, 101, 93, 91, 89, 674, 666,
+	71, 69, 64, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, 1024, 995, 951, 130, 498, -1000, -48, 148,
+	1168, 29, 202, 254, -1000, 142, -1000, -1000, -1000, -1000,
+	-1000, -1000, 369, 368, 1534, 1534, 1534, 1534, 1534, 1534,
+	1534, 1534, 411, 347, 32, 1363, -1000, 1421, 1355, -20,
+	-44, 1347, 1534, 1534, -1000, -1000, -44, -96, -96, -96,
+	-96, -17, -17, -17, -17, -17, -17, -1000, -1000, 1500,
+	-62, -62, -106, -106, -106, -50, -1000, -50, -1000, -50,
+	-50, -50, -50, -50, -50, -106, -66, -66, -1000, -50,
+	-1000, -50, -1000, 497, 234, -1000, -1000, -1000, -1000, 54,
+	52, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 646,
+	1634, 254, -1000, -1000, 517, 516, 514, -1000, -1000, -1000,
+	631, 165, 154, 342, 341, 331, 326, 217, 492, 305,
+	276, 487, -1000, -1000, 401, 148, -25, 1347, 472, -1000,
+	457, -1000, -1000, -1000, 706, -1000, -1000, -1000, -1000, -1000,
+	634, 613, 244, -1000, -1000, -1000, 513, 511, 153, 152,
+	151, 120, 710, 119, 638, 110, 109, -1000, 611, -1000,
+	128, 1534, 68, 33, 234, 274, 272, -1000, 442, -1000,
+	-1000, -1000, -1000, -1000, 592, 1534, 1534, 509, 508, 506,
+	480, 440, -1000, -1000, 475, 268, -53, 474, 468, 436,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 261, -1000,
+	-1000, -1000, 434, 429, 1534, 1534, 1534, 1534, 606, 108,
+	1534, 106, 269, 1534, 1534, -1000, 603, -1000, -1000, -1000,
+	423, 409, 403, 402, -1000, -1000, 251, 464, 385, 456,
+	710, 448, 364, 363, 215, -1000, -1000, -1000, -1000, 105,
+	1534, -1000, 1534, 285, 710, -1000, -1000, 596, 425, 362,
+	348, 694, -1000, 346, 246, 1534, -1000, -1000, -1000, -1000,
+	332, -1000,
 }
 
 var yyPgo = [...]int16{
-	0, 702, 4, 695, 3, 19, 2, 1062, 694, 27,
-	8, 1, 577, 693, 6, 678, 677, 10, 676, 1088,
-	21, 675, 670, 11, 24, 5, 26, 7, 0, 18,
-	662, 9, 648, 647,
+	0, 810, 8, 802, 6, 60, 788, 23, 1560, 787,
+	14, 21, 3, 506, 244, 4, 17, 786, 2, 783,
+	10, 18, 775, 1143, 1032, 773, 1441, 59, 767, 766,
+	5, 9, 13, 752, 16, 40, 20, 0, 61, 7,
+	1, 11, 751, 750, 749,
 }
 
 var yyR1 = [...]int8{
-	0, 1, 1, 1, 1, 1, 1, 7, 7, 7,
+	0, 1, 1, 1, 1, 1, 1, 44, 44, 44,
+	44, 8, 8, 8, 8, 8, 8, 8, 8, 8,
+	8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
+	9, 10, 10, 10, 10, 10, 10, 10, 10, 10,
+	2, 3, 4, 6, 6, 6, 6, 38, 38, 38,
+	5, 5, 39, 39, 39, 39, 40, 40, 7, 7,
 	7, 7, 7, 7, 7, 7, 7, 7, 7, 7,
-	7, 7, 7, 7, 7, 7, 8, 9, 9, 9,
-	9, 9, 9, 9, 9, 9, 2, 3, 4, 29,
-	29, 29, 5, 5, 30, 30, 30, 30, 6, 6,
-	6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
-	6, 6, 6, 6, 6, 6, 6, 10, 10, 11,
-	12, 12, 12, 12, 12, 12, 18, 18, 19, 19,
-	19, 19, 19, 19, 19, 19, 21, 22, 20, 20,
-	20, 20, 20, 20, 20, 20, 20, 20, 20, 20,
-	20, 20, 23, 23, 23, 23, 23, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 14, 14, 16, 16,
-	16, 16, 16, 16, 17, 17, 17, 17, 17, 17,
-	15, 15, 15, 15, 31, 33, 32, 32, 24, 24,
-	24, 24, 24, 24, 24, 24, 24, 24, 24, 24,
-	24, 24, 24, 24, 24, 24, 24, 24, 24, 24,
-	24, 24, 24, 24, 24, 24, 24, 25, 25, 25,
-	25, 25, 25, 25, 25, 25, 25, 25, 25, 25,
-	25, 25, 25, 26, 26, 26, 26, 26, 26, 26,
-	26, 26, 26, 26, 26, 28, 28, 28, 28, 28,
-	28, 28, 28, 28, 28, 28, 28, 28, 28, 28,
-	28, 28, 28, 27, 27, 27, 27, 27, 27, 27,
-	27, 27,
+	7, 7, 7, 7, 7, 7, 7, 11, 11, 12,
+	13, 13, 13, 13, 13, 13, 25, 25, 26, 26,
+	26, 26, 26, 26, 26, 26, 28, 29, 27, 27,
+	27, 27, 27, 27, 27, 27, 27, 27, 27, 27,
+	27, 27, 30, 30, 30, 30, 30, 14, 14, 14,
+	14, 14, 14, 14, 14, 14, 14, 14, 14, 14,
+	14, 14, 14, 14, 14, 14, 14, 14, 14, 14,
+	14, 14, 14, 14, 15, 15, 15, 15, 17, 17,
+	18, 18, 18, 18, 18, 18, 18, 19, 19, 19,
+	19, 19, 19, 20, 20, 20, 20, 20, 20, 16,
+	16, 16, 16, 22, 22, 24, 24, 24, 24, 23,
+	23, 23, 23, 23, 23, 23, 21, 21, 21, 21,
+	21, 21, 21, 21, 21, 21, 21, 21, 21, 21,
+	21, 21, 21, 21, 21, 21, 21, 21, 21, 21,
+	21, 21, 41, 43, 42, 42, 31, 31, 31, 31,
+	31, 31, 31, 31, 31, 31, 31, 31, 31, 31,
+	31, 31, 31, 31, 31, 31, 31, 31, 31, 31,
+	31, 31, 31, 31, 31, 31, 32, 33, 33, 34,
+	34, 34, 34, 34, 34, 34, 34, 34, 34, 34,
+	34, 34, 34, 34, 34, 35, 35, 35, 35, 35,
+	35, 35, 35, 35, 35, 35, 35, 37, 37, 37,
+	37, 37, 37, 37, 37, 37, 37, 37, 37, 37,
+	37, 37, 37, 37, 37, 36, 36, 36, 36, 36,
+	36, 36, 36, 36,
 }
 
 var yyR2 = [...]int8{
-	0, 1, 1, 1, 3, 4, 2, 3, 3, 3,
+	0, 1, 1, 1, 1, 3, 2, 3, 4, 1,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 1, 3, 1, 1, 1,
-	1, 3, 3, 3, 3, 3, 4, 3, 4, 1,
-	1, 1, 1, 3, 1, 1, 3, 3, 3, 3,
+	3, 3, 3, 3, 3, 3, 3, 3, 3, 1,
+	3, 1, 1, 1, 1, 3, 3, 3, 3, 3,
+	4, 3, 4, 1, 1, 3, 3, 1, 1, 1,
+	1, 3, 1, 1, 3, 3, 5, 6, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 1, 2, 3, 3,
 	1, 1, 1, 1, 1, 1, 3, 3, 3, 3,
@@ -512,114 +607,152 @@ var yyR2 = [...]int8{
 	3, 3, 3, 3, 3, 1, 1, 1, 1, 2,
 	2, 2, 3, 4, 4, 4, 4, 3, 7, 3,
 	7, 4, 8, 4, 8, 4, 8, 4, 8, 6,
-	10, 4, 8, 4, 6, 10, 4, 4, 1, 1,
-	1, 1, 1, 1, 2, 2, 2, 3, 3, 3,
-	2, 1, 3, 2, 3, 4, 1, 3, 3, 3,
+	10, 8, 12, 4, 8, 6, 10, 4, 8, 4,
+	8, 4, 6, 10, 4, 4, 3, 4, 1, 3,
+	1, 1, 1, 1, 2, 2, 2, 1, 1, 1,
+	1, 1, 1, 2, 2, 2, 3, 3, 3, 2,
+	1, 3, 2, 5, 6, 1, 1, 2, 2, 0,
+	4, 5, 5, 4, 5, 5, 1, 3, 4, 4,
+	4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
+	3, 3, 3, 4, 1, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+	3, 3, 3, 3, 3, 3, 3, 3, 3, 2,
+	2, 1, 1, 1, 1, 1, 4, 1, 3, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 1, 1, 2, 2, 2, 2, 2,
+	1, 1, 1, 1, 1, 1, 1, 2, 2, 2,
 	2, 2, 2, 2, 2, 2, 2, 2, 2, 2,
-	2, 2, 2, 3, 3, 3, 3, 4, 4, 3,
-	3, 3,
+	2, 2, 2, 2, 2, 3, 3, 3, 3, 4,
+	4, 3, 3, 3,
 }
 
 var yyChk = [...]int16{
-	-1000, -1, -9, -7, -18, -6, -11, -2, -4, 12,
-	-8, -19, -10, -20, 63, 65, -21, 10, -23, 6,
-	7, 8, 103, 58, 60, 61, 59, 62, -33, 78,
-	79, 80, 86, 84, 90, 91, 81, 92, 93, 94,
-	95, 96, 88, 97, 98, 99, 100, 101, 80, 86,
-	84, 90, 91, 81, 92, 93, 94, 88, 96, 95,
-	97, 98, 101, 100, 99, -7, -9, -6, -19, -22,
-	-20, -12, 102, 103, 105, 106, 107, 108, 82, 83,
-	84, 85, 86, 87, -12, 102, 103, 105, 106, 107,
-	108, 12, 12, 11, -24, 12, 15, 103, 104, -25,
-	-26, -27, -28, 5, 6, 7, 16, 17, 8, 19,
-	18, 20, 21, 22, 23, 24, 25, 26, 4, 27,
-	29, 30, 31, 33, 32, 34, 35, 37, 38, 39,
-	40, 9, 48, 49, 47, 53, 55, 57, 50, 51,
-	52, 54, 56, 6, 7, 8, 12, 12, 12, 12,
-	12, 12, -13, -6, -11, -2, -3, -4, 67, 68,
-	69, 70, 72, 71, 73, 74, 75, 12, 64, -7,
-	12, -7, -7, -7, -7, -7, -7, -7, -7, -7,
-	-7, -7, -7, -7, -7, -7, -7, -6, 12, -6,
-	-6, -6, -6, -6, -6, -6, -6, -6, -6, -6,
-	-6, -6, -6, -6, -6, 13, 13, 79, 13, 13,
-	13, 13, -19, -25, 12, -19, -19, -19, -19, -19,
-	-19, -20, 12, -20, -20, -20, -20, -20, -20, -24,
-	-5, -29, -26, -27, -28, 11, 102, 103, 105, 106,
-	107, 82, 83, 84, 85, 86, 87, 89, 88, 108,
-	80, 81, -24, 83, 82, -24, -24, 4, 4, 4,
-	4, 48, 49, 4, 4, 4, 27, 34, 36, 41,
-	27, 29, 33, 30, 31, 41, 44, 28, 29, 45,
-	42, 43, 29, 46, 13, -24, -24, -24, -24, -32,
-	-31, 4, -15, 79, -17, -16, 82, 83, 84, 85,
-	86, 87, 12, 12, 12, 12, 12, 12, 12, 12,
-	12, -6, -20, 12, -9, 12, -23, 12, -9, 13,
-	13, 14, -24, -24, -24, -24, -24, -24, 15, -24,
-	15, -24, -24, -24, -24, -24, -24, -24, -24, -24,
-	13, -24, 15, -24, 15, 66, 66, 66, 66, 4,
-	4, 66, 66, 66, 13, 13, 13, 13, 13, 14,
-	82, 79, -17, -14, 76, 77, 6, 7, 8, 103,
-	13, 13, -29, -29, -29, -29, -29, -29, -10, 13,
-	79, -29, 66, 66, -31, -25, -14, 12, 12, 6,
-	7, 8, 63, 63, 13, 13, 13, 13, 14, 13,
-	13, 14, 6, 6, 12, 12, 63, 63, 63, 63,
-	-30, 7, 6, 63, 6, 13, 13, -5, -5, 12,
-	12, 12, 12, 14, 13, 12, 13, 14, 13, 13,
-	-5, -5, -5, -5, 7, 6, 63, -5, 6, 13,
-	13, 13, 13, 12, 13, 14, -5, 6, 13, 13,
+	-1000, -1, -10, -8, -25, -44, -7, -12, -2, -4,
+	12, -9, -26, -21, -11, -27, 65, 67, -28, -22,
+	10, -30, 6, 7, 8, 113, 60, 62, 63, 61,
+	64, -43, 86, 88, 89, 90, 96, 94, 100, 101,
+	91, 102, 103, 104, 105, 106, 98, 107, 108, 109,
+	110, 111, 87, 90, 96, 94, 100, 101, 91, 102,
+	103, 104, 98, 106, 105, 107, 108, 111, 110, 109,
+	-8, -10, -7, -26, -29, -27, -21, -13, 112, 113,
+	115, 116, 117, 118, 92, 93, 94, 95, 96, 97,
+	112, 113, 115, 116, 117, 118, 92, 93, 94, 95,
+	96, 97, -13, 112, 113, 115, 116, 117, 118, 12,
+	12, 11, -31, 12, 17, 113, 114, -34, -35, -36,
+	-37, -32, 5, 6, 7, 18, 19, 8, 21, 20,
+	22, 23, 24, 25, 26, 27, 28, 4, 29, 31,
+	32, 33, 35, 34, 36, 37, 39, 40, 41, 42,
+	9, 50, 51, 49, 55, 57, 59, 52, 53, 54,
+	56, 58, 6, 7, 8, 12, 12, 12, 12, 12,
+	12, -10, 12, -14, -7, -12, -2, -3, -4, 69,
+	70, 71, 72, 74, 73, 75, 76, 77, 78, 79,
+	66, -8, 12, -8, -8, -8, -8, -8, -8, -8,
+	-8, -8, -8, -8, -8, -8, -8, -8, -8, 8,
+	-7, 12, -7, -7, -7, -7, -7, -7, -7, -7,
+	-7, -7, -7, -7, -7, -7, -7, -7, 13, 13,
+	89, 13, 13, 13, 13, 13, -26, -34, 12, 4,
+	-26, -26, -26, -26, -26, -26, -23, -24, 82, 83,
+	6, 7, 113, -23, -24, -23, -24, -23, -24, -23,
+	-24, -23, -24, -23, -24, -23, -24, -23, -24, -23,
+	-24, -23, -24, -23, -24, -27, 12, -27, -27, -27,
+	-27, -27, -27, -31, -6, -38, -32, -35, -36, -37,
+	4, 11, 112, 113, 115, 116, 117, 92, 93, 94,
+	95, 96, 97, 99, 98, 118, 90, 91, -31, 93,
+	92, -31, -31, 12, 4, 4, 4, 4, 50, 51,
+	4, 4, 4, 29, 36, 38, 43, 29, 31, 35,
+	32, 33, 43, 46, 30, 31, 47, 44, 45, 31,
+	48, 13, -31, -31, -31, -31, -42, -41, 4, 89,
+	-7, -27, -16, 89, -20, -19, 92, 93, 94, 95,
+	96, 97, 12, 12, 12, 12, 12, 12, 12, 12,
+	12, 12, 12, 12, -10, 12, -30, -14, 12, -10,
+	-21, 12, 12, 12, 6, 7, -21, -21, -21, -21,
+	-21, -21, -21, -21, -21, -21, -21, 13, 13, 16,
+	-31, -31, -31, -31, -31, -31, 17, -31, 17, -31,
+	-31, -31, -31, -31, -31, -31, -31, -31, 13, -31,
+	17, -31, 17, -33, -31, 68, 68, 68, 68, 4,
+	4, 68, 68, 68, 13, 13, 13, 13, 13, 16,
+	92, 89, -20, -15, 80, 81, 4, 6, 7, 8,
+	113, 13, 13, -38, -38, -38, -38, -38, -38, -38,
+	-38, -11, 13, 13, -16, 89, -10, 12, -5, -38,
+	-5, -38, -32, 13, 16, 68, 68, -41, -34, -15,
+	12, 12, 12, 6, 7, 8, 65, 65, 13, 13,
+	13, 13, 16, 13, 16, 13, 13, 13, 16, 13,
+	89, 16, 13, 13, -31, 6, 6, 13, -17, -18,
+	5, 6, 7, 8, 113, 12, 12, 65, 65, 65,
+	65, -39, 7, 6, 65, -40, 4, 65, 65, 6,
+	-38, 84, 85, 84, 85, 13, 13, 13, 16, 6,
+	7, 8, -5, -5, 12, 12, 12, 12, 16, 13,
+	12, 13, 92, 12, 12, 13, 16, -18, 13, 13,
+	-5, -5, -5, -5, 7, 6, -40, 65, -5, 65,
+	14, 4, -5, -5, 6, 13, 13, 13, 13, 13,
+	12, 13, 12, -39, 12, 13, 13, 16, 65, -5,
+	-5, 16, 15, -39, 6, 12, 13, 13, 13, 13,
+	-5, 13,
 }
 
 var yyDef = [...]int16{
-	0, -2, 1, 2, 3, 27, 28, 29, 30, 0,
-	25, 0, 66, 0, 0, 0, 85, 0, 95, 96,
-	97, 98, 0, 0, 0, 0, 0, 0, 6, 0,
+	0, -2, 1, 2, 3, 4, 31, 32, 33, 34,
+	0, 29, 0, 9, 76, 0, 0, 0, 95, 186,
+	0, 105, 106, 107, 108, 0, 0, 0, 0, 0,
+	0, 6, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 31, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 80, 81, 82, 83, 84, 85,
+	179, 179, 179, 179, 179, 179, 179, 179, 179, 179,
+	179, 179, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 77, 0, 0, 0, 0, 0, 241, 242, 243,
+	244, 245, 249, 250, 251, 252, 253, 254, 255, 256,
+	257, 258, 259, 260, 261, 262, 263, 264, 265, 266,
+	267, 268, 269, 270, 271, 272, 273, 274, 275, 276,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 27, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 70, 71,
-	72, 73, 74, 75, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 67, 0, 0, 0, 0, 0, 173,
-	174, 175, 176, 177, 178, 179, 180, 181, 182, 183,
-	184, 185, 186, 187, 188, 189, 190, 191, 192, 193,
-	194, 195, 196, 197, 198, 199, 200, 201, 202, 203,
-	204, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 99, 100, 101, 0, 0, 0, 0,
-	0, 0, 4, 31, 32, 33, 34, 35, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 8,
-	0, 9, 10, 11, 12, 13, 14, 15, 16, 17,
-	18, 19, 20, 21, 22, 23, 24, 49, 0, 50,
-	51, 52, 53, 54, 55, 56, 57, 58, 59, 60,
-	61, 62, 63, 64, 65, 7, 26, 0, 48, 78,
-	86, 88, 76, 77, 0, 79, 80, 81, 82, 83,
-	84, 69, 0, 89, 90, 91, 92, 93, 94, 0,
-	0, 42, 39, 40, 41, 68, 0, 0, 0, 0,
+	0, 0, 109, 110, 111, 0, 0, 0, 0, 0,
+	0, 5, 0, 7, 35, 36, 37, 38, 39, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 171, 172, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 205, 206, 207, 208,
-	209, 210, 211, 212, 213, 214, 215, 216, 217, 218,
-	219, 220, 221, 222, 102, 0, 0, 0, 0, 0,
-	146, 0, 5, 0, 141, 0, 128, 129, 130, 131,
-	132, 133, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, -2, 0, 0, 36,
-	38, 0, 149, 150, 151, 152, 153, 154, 167, 155,
-	165, 156, 157, 158, 159, 160, 161, 162, 163, 164,
-	148, 166, 169, 168, 170, 223, 224, 225, 226, 0,
-	0, 229, 230, 231, 103, 104, 105, 106, 145, 0,
-	0, 0, 143, 140, 0, 0, 134, 135, 136, 0,
-	107, 109, 0, 0, 0, 0, 0, 0, 0, 37,
-	0, 43, 227, 228, 147, 144, 142, 0, 0, 137,
-	138, 139, 0, 0, 111, 113, 115, 117, 0, 121,
-	123, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 44, 45, 0, 0, 126, 127, 0, 0, 0,
-	0, 0, 0, 0, 119, 0, 124, 0, 108, 110,
-	0, 0, 0, 0, 46, 47, 0, 0, 0, 112,
-	114, 116, 118, 0, 122, 0, 0, 0, 120, 125,
+	0, 12, 0, 13, 14, 15, 16, 17, 18, 19,
+	20, 21, 22, 23, 24, 25, 26, 27, 28, 10,
+	59, 0, 60, 61, 62, 63, 64, 65, 66, 67,
+	68, 69, 70, 71, 72, 73, 74, 75, 11, 30,
+	0, 58, 88, 96, 98, 187, 86, 87, 0, 264,
+	89, 90, 91, 92, 93, 94, 0, 200, 0, 0,
+	175, 176, 0, 0, 201, 0, 202, 0, 203, 0,
+	204, 0, 205, 0, 206, 0, 207, 0, 208, 0,
+	209, 0, 210, 0, 211, 79, 0, 99, 100, 101,
+	102, 103, 104, 0, 0, 43, 44, 47, 48, 49,
+	0, 78, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 239, 240, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 277, 278, 279, 280, 281, 282, 283,
+	284, 285, 286, 287, 288, 289, 290, 291, 292, 293,
+	294, 112, 0, 0, 0, 0, 0, 214, 0, 0,
+	0, 0, 8, 0, 170, 0, 157, 158, 159, 160,
+	161, 162, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, -2, 0, 0, 0,
+	188, 0, 0, 0, 177, 178, 189, 190, 191, 192,
+	193, 194, 195, 196, 197, 198, 199, 40, 42, 0,
+	217, 218, 219, 220, 221, 222, 235, 223, 233, 224,
+	225, 226, 227, 228, 229, 230, 231, 232, 216, 234,
+	237, 236, 238, 0, 247, 295, 296, 297, 298, 0,
+	0, 301, 302, 303, 113, 114, 115, 116, 213, 0,
+	0, 0, 172, 169, 0, 0, 0, 163, 164, 165,
+	0, 117, 119, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 41, 173, 0, 0, 0, 0, 0, 50,
+	0, 45, 46, 246, 0, 299, 300, 215, 212, 171,
+	0, 0, 0, 166, 167, 168, 0, 0, 121, 123,
+	125, 127, 0, 133, 0, 137, 139, 141, 0, 174,
+	0, 0, 180, 183, 248, 0, 0, 146, 0, 148,
+	150, 151, 152, 153, 0, 0, 0, 0, 0, 0,
+	0, 0, 52, 53, 0, 0, 0, 0, 0, 0,
+	51, 181, 182, 184, 185, 144, 145, 147, 0, 154,
+	155, 156, 0, 0, 0, 0, 0, 0, 0, 129,
+	0, 135, 0, 0, 0, 142, 0, 149, 118, 120,
+	0, 0, 0, 0, 54, 55, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 122, 124, 126, 128, 131,
+	0, 134, 0, 0, 0, 138, 140, 0, 0, 0,
+	0, 0, 56, 0, 0, 0, 130, 136, 57, 143,
+	0, 132,
 }
 
 var yyTok1 = [...]int8{
@@ -637,7 +770,8 @@ var yyTok2 = [...]int8{
 	72, 73, 74, 75, 76, 77, 78, 79, 80, 81,
 	82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
 	92, 93, 94, 95, 96, 97, 98, 99, 100, 101,
-	102, 103, 104, 105, 106, 107, 108,
+	102, 103, 104, 105, 106, 107, 108, 109, 110, 111,
+	112, 113, 114, 115, 116, 117, 118,
 }
 
 var yyTok3 = [...]int8{
@@ -995,14 +1129,13 @@ yydefault:
 			yylex.(*lexer).expr = newRootExpr(yyDollar[1].scalarPipelineExpressionFilter)
 		}
 	case 4:
-		yyDollar = yyS[yypt-3 : yypt+1]
+		yyDollar = yyS[yypt-1 : yypt+1]
 		{
-			yylex.(*lexer).expr = newRootExprWithMetrics(yyDollar[1].spansetPipeline, yyDollar[3].metricsAggregation)
 		}
 	case 5:
-		yyDollar = yyS[yypt-4 : yypt+1]
+		yyDollar = yyS[yypt-3 : yypt+1]
 		{
-			yylex.(*lexer).expr = newRootExprWithMetricsTwoStage(yyDollar[1].spansetPipeline, yyDollar[3].metricsAggregation, yyDollar[4].metricsSecondStagePipeline)
+			yylex.(*lexer).expr = newRootExprWithLinkTraversal(yyDollar[1].spansetPipeline, yyDollar[3].spansetPipeline)
 		}
 	case 6:
 		yyDollar = yyS[yypt-2 : yypt+1]
@@ -1012,931 +1145,1301 @@ yydefault:
 	case 7:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
-			yyVAL.spansetPipelineExpression = yyDollar[2].spansetPipelineExpression
+			yylex.(*lexer).expr = newRootExprWithMetrics(yyDollar[1].spansetPipeline, yyDollar[3].metricsAggregation)
 		}
 	case 8:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yylex.(*lexer).expr = newRootExprWithMetricsTwoStage(yyDollar[1].spansetPipeline, yyDollar[3].metricsAggregation, yyDollar[4].metricsSecondStagePipeline)
+		}
+	case 9:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yylex.(*lexer).expr = newRootExprWithMetricsExpression(yyDollar[1].metricsExpression)
+		}
+	case 10:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yylex.(*lexer).expr.withOffset(yyDollar[3].staticDuration)
+		}
+	case 11:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.spansetPipelineExpression = yyDollar[2].spansetPipelineExpression
+		}
+	case 12:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetAnd, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 9:
+	case 13:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 10:
+	case 14:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetParent, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 11:
+	case 15:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 12:
+	case 16:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetAncestor, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 13:
+	case 17:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnion, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 14:
+	case 18:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 15:
+	case 19:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 16:
+	case 20:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotParent, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 17:
+	case 21:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 18:
+	case 22:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotAncestor, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 19:
+	case 23:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 20:
+	case 24:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 21:
+	case 25:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionParent, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 22:
+	case 26:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 23:
+	case 27:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionAncestor, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 24:
+	case 28:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
-	case 25:
+	case 29:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.spansetPipelineExpression = yyDollar[1].wrappedSpansetPipeline
 		}
-	case 26:
+	case 30:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.wrappedSpansetPipeline = yyDollar[2].spansetPipeline
 		}
-	case 27:
+	case 31:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].spansetExpression)
 		}
-	case 28:
+	case 32:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].scalarFilter)
 		}
-	case 29:
+	case 33:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].groupOperation)
 		}
-	case 30:
+	case 34:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].selectOperation)
 		}
-	case 31:
+	case 35:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].spansetExpression)
 		}
-	case 32:
+	case 36:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].scalarFilter)
 		}
-	case 33:
+	case 37:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].groupOperation)
 		}
-	case 34:
+	case 38:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].coalesceOperation)
 		}
-	case 35:
+	case 39:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].selectOperation)
 		}
-	case 36:
+	case 40:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.groupOperation = newGroupOperation(yyDollar[3].fieldExpression)
 		}
-	case 37:
+	case 41:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.coalesceOperation = newCoalesceOperation()
 		}
-	case 38:
+	case 42:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
-			yyVAL.selectOperation = newSelectOperation(yyDollar[3].attributeList)
+			yyVAL.selectOperation = newSelectOperation(yyDollar[3].fieldExpressionList)
 		}
-	case 39:
+	case 43:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.fieldExpressionList = []FieldExpression{yyDollar[1].attribute}
+		}
+	case 44:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.fieldExpressionList = []FieldExpression{yyDollar[1].fieldExpression}
+		}
+	case 45:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.fieldExpressionList = append(yyDollar[1].fieldExpressionList, yyDollar[3].attribute)
+		}
+	case 46:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.fieldExpressionList = append(yyDollar[1].fieldExpressionList, yyDollar[3].fieldExpression)
+		}
+	case 47:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.attribute = yyDollar[1].intrinsicField
 		}
-	case 40:
+	case 48:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.attribute = yyDollar[1].attributeField
 		}
-	case 41:
+	case 49:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.attribute = yyDollar[1].scopedIntrinsicField
 		}
-	case 42:
+	case 50:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.attributeList = []Attribute{yyDollar[1].attribute}
 		}
-	case 43:
+	case 51:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.attributeList = append(yyDollar[1].attributeList, yyDollar[3].attribute)
 		}
-	case 44:
+	case 52:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.numericList = []float64{yyDollar[1].staticFloat}
 		}
-	case 45:
+	case 53:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.numericList = []float64{float64(yyDollar[1].staticInt)}
 		}
-	case 46:
+	case 54:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.numericList = append(yyDollar[1].numericList, yyDollar[3].staticFloat)
 		}
-	case 47:
+	case 55:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.numericList = append(yyDollar[1].numericList, float64(yyDollar[3].staticInt))
 		}
-	case 48:
+	case 56:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			if yyDollar[1].staticStr != "buckets" {
+				yylex.(*lexer).errorAt("unknown argument: "+yyDollar[1].staticStr, yyDollar[1].identifierPos)
+			}
+			yyVAL.numericList = yyDollar[4].numericList
+		}
+	case 57:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			if yyDollar[1].staticStr != "buckets" {
+				yylex.(*lexer).errorAt("unknown argument: "+yyDollar[1].staticStr, yyDollar[1].identifierPos)
+			}
+			var err error
+			yyVAL.numericList, err = newBucketBoundaries(yyDollar[3].staticStr, yyDollar[5].numericList)
+			if err != nil {
+				yylex.(*lexer).errorAt(err.Error(), yyDollar[3].identifierPos)
+			}
+		}
+	case 58:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = yyDollar[2].spansetExpression
 		}
-	case 49:
+	case 59:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetAnd, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 50:
+	case 60:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetChild, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 51:
+	case 61:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetParent, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 52:
+	case 62:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetDescendant, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 53:
+	case 63:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetAncestor, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 54:
+	case 64:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnion, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 55:
+	case 65:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetSibling, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 56:
+	case 66:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotChild, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 57:
+	case 67:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotParent, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 58:
+	case 68:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotSibling, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 59:
+	case 69:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotAncestor, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 60:
+	case 70:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotDescendant, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 61:
+	case 71:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnionChild, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 62:
+	case 72:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnionParent, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 63:
+	case 73:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnionSibling, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 64:
+	case 74:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnionAncestor, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 65:
+	case 75:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnionDescendant, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 66:
+	case 76:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.spansetExpression = yyDollar[1].spansetFilter
 		}
-	case 67:
+	case 77:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.spansetFilter = newSpansetFilter(NewStaticBool(true))
 		}
-	case 68:
+	case 78:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.spansetFilter = newSpansetFilter(yyDollar[2].fieldExpression)
 		}
-	case 69:
+	case 79:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarFilter = newScalarFilter(yyDollar[2].scalarFilterOperation, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 70:
+	case 80:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarFilterOperation = OpEqual
 		}
-	case 71:
+	case 81:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarFilterOperation = OpNotEqual
 		}
-	case 72:
+	case 82:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarFilterOperation = OpLess
 		}
-	case 73:
+	case 83:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarFilterOperation = OpLessEqual
 		}
-	case 74:
+	case 84:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarFilterOperation = OpGreater
 		}
-	case 75:
+	case 85:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarFilterOperation = OpGreaterEqual
 		}
-	case 76:
+	case 86:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarPipelineExpressionFilter = newScalarFilter(yyDollar[2].scalarFilterOperation, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 77:
+	case 87:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarPipelineExpressionFilter = newScalarFilter(yyDollar[2].scalarFilterOperation, yyDollar[1].scalarPipelineExpression, yyDollar[3].static)
 		}
-	case 78:
+	case 88:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarPipelineExpression = yyDollar[2].scalarPipelineExpression
 		}
-	case 79:
+	case 89:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpAdd, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 80:
+	case 90:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpSub, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 81:
+	case 91:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpMult, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 82:
+	case 92:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpDiv, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 83:
+	case 93:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpMod, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 84:
+	case 94:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpPower, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 85:
+	case 95:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarPipelineExpression = yyDollar[1].wrappedScalarPipeline
 		}
-	case 86:
+	case 96:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.wrappedScalarPipeline = yyDollar[2].scalarPipeline
 		}
-	case 87:
+	case 97:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].aggregate)
 		}
-	case 88:
+	case 98:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarExpression = yyDollar[2].scalarExpression
 		}
-	case 89:
+	case 99:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpAdd, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 90:
+	case 100:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpSub, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 91:
+	case 101:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpMult, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 92:
+	case 102:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpDiv, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 93:
+	case 103:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpMod, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 94:
+	case 104:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpPower, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 95:
+	case 105:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarExpression = yyDollar[1].aggregate
 		}
-	case 96:
+	case 106:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarExpression = NewStaticInt(yyDollar[1].staticInt)
 		}
-	case 97:
+	case 107:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarExpression = NewStaticFloat(yyDollar[1].staticFloat)
 		}
-	case 98:
+	case 108:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarExpression = NewStaticDuration(yyDollar[1].staticDuration)
 		}
-	case 99:
+	case 109:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scalarExpression = NewStaticInt(-yyDollar[2].staticInt)
 		}
-	case 100:
+	case 110:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scalarExpression = NewStaticFloat(-yyDollar[2].staticFloat)
 		}
-	case 101:
+	case 111:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scalarExpression = NewStaticDuration(-yyDollar[2].staticDuration)
 		}
-	case 102:
+	case 112:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.aggregate = newAggregate(aggregateCount, nil)
 		}
-	case 103:
+	case 113:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.aggregate = newAggregate(aggregateMax, yyDollar[3].fieldExpression)
 		}
-	case 104:
+	case 114:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.aggregate = newAggregate(aggregateMin, yyDollar[3].fieldExpression)
 		}
-	case 105:
+	case 115:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.aggregate = newAggregate(aggregateAvg, yyDollar[3].fieldExpression)
 		}
-	case 106:
+	case 116:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.aggregate = newAggregate(aggregateSum, yyDollar[3].fieldExpression)
 		}
-	case 107:
+	case 117:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateRate, nil)
 		}
-	case 108:
+	case 118:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateRate, yyDollar[6].attributeList)
 		}
-	case 109:
+	case 119:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateCountOverTime, nil)
 		}
-	case 110:
+	case 120:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateCountOverTime, yyDollar[6].attributeList)
 		}
-	case 111:
+	case 121:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateMinOverTime, yyDollar[3].attribute, nil)
 		}
-	case 112:
+	case 122:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateMinOverTime, yyDollar[3].attribute, yyDollar[7].attributeList)
 		}
-	case 113:
+	case 123:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateMaxOverTime, yyDollar[3].attribute, nil)
 		}
-	case 114:
+	case 124:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateMaxOverTime, yyDollar[3].attribute, yyDollar[7].attributeList)
 		}
-	case 115:
+	case 125:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateSumOverTime, yyDollar[3].attribute, nil)
 		}
-	case 116:
+	case 126:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateSumOverTime, yyDollar[3].attribute, yyDollar[7].attributeList)
 		}
-	case 117:
+	case 127:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.metricsAggregation = newAverageOverTimeMetricsAggregator(yyDollar[3].attribute, nil)
 		}
-	case 118:
+	case 128:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.metricsAggregation = newAverageOverTimeMetricsAggregator(yyDollar[3].attribute, yyDollar[7].attributeList)
 		}
-	case 119:
+	case 129:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
-			yyVAL.metricsAggregation = newMetricsAggregateQuantileOverTime(yyDollar[3].attribute, yyDollar[5].numericList, nil)
+			yyVAL.metricsAggregation = newMetricsAggregateQuantileOverTime(yyDollar[3].attribute, yyDollar[5].numericList, nil, nil)
 		}
-	case 120:
+	case 130:
 		yyDollar = yyS[yypt-10 : yypt+1]
 		{
-			yyVAL.metricsAggregation = newMetricsAggregateQuantileOverTime(yyDollar[3].attribute, yyDollar[5].numericList, yyDollar[9].attributeList)
+			yyVAL.metricsAggregation = newMetricsAggregateQuantileOverTime(yyDollar[3].attribute, yyDollar[5].numericList, nil, yyDollar[9].attributeList)
 		}
-	case 121:
+	case 131:
+		yyDollar = yyS[yypt-8 : yypt+1]
+		{
+			yyVAL.metricsAggregation = newMetricsAggregateQuantileOverTime(yyDollar[3].attribute, yyDollar[5].numericList, yyDollar[7].numericList, nil)
+		}
+	case 132:
+		yyDollar = yyS[yypt-12 : yypt+1]
+		{
+			yyVAL.metricsAggregation = newMetricsAggregateQuantileOverTime(yyDollar[3].attribute, yyDollar[5].numericList, yyDollar[7].numericList, yyDollar[11].attributeList)
+		}
+	case 133:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
-			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateHistogramOverTime, yyDollar[3].attribute, nil)
+			yyVAL.metricsAggregation = newMetricsAggregateHistogramOverTime(yyDollar[3].attribute, nil, nil)
 		}
-	case 122:
+	case 134:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
-			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateHistogramOverTime, yyDollar[3].attribute, yyDollar[7].attributeList)
+			yyVAL.metricsAggregation = newMetricsAggregateHistogramOverTime(yyDollar[3].attribute, nil, yyDollar[7].attributeList)
 		}
-	case 123:
+	case 135:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.metricsAggregation = newMetricsAggregateHistogramOverTime(yyDollar[3].attribute, yyDollar[5].numericList, nil)
+		}
+	case 136:
+		yyDollar = yyS[yypt-10 : yypt+1]
+		{
+			yyVAL.metricsAggregation = newMetricsAggregateHistogramOverTime(yyDollar[3].attribute, yyDollar[5].numericList, yyDollar[9].attributeList)
+		}
+	case 137:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.metricsAggregation = newVarianceOverTimeMetricsAggregator(metricsAggregateStddevOverTime, yyDollar[3].attribute, nil)
+		}
+	case 138:
+		yyDollar = yyS[yypt-8 : yypt+1]
+		{
+			yyVAL.metricsAggregation = newVarianceOverTimeMetricsAggregator(metricsAggregateStddevOverTime, yyDollar[3].attribute, yyDollar[7].attributeList)
+		}
+	case 139:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.metricsAggregation = newVarianceOverTimeMetricsAggregator(metricsAggregateStdvarOverTime, yyDollar[3].attribute, nil)
+		}
+	case 140:
+		yyDollar = yyS[yypt-8 : yypt+1]
+		{
+			yyVAL.metricsAggregation = newVarianceOverTimeMetricsAggregator(metricsAggregateStdvarOverTime, yyDollar[3].attribute, yyDollar[7].attributeList)
+		}
+	case 141:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.metricsAggregation = newMetricsCompare(yyDollar[3].spansetFilter, 10, 0, 0)
 		}
-	case 124:
+	case 142:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.metricsAggregation = newMetricsCompare(yyDollar[3].spansetFilter, yyDollar[5].staticInt, 0, 0)
 		}
-	case 125:
+	case 143:
 		yyDollar = yyS[yypt-10 : yypt+1]
 		{
 			yyVAL.metricsAggregation = newMetricsCompare(yyDollar[3].spansetFilter, yyDollar[5].staticInt, yyDollar[7].staticInt, yyDollar[9].staticInt)
 		}
-	case 126:
+	case 144:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.metricsSecondStage = newTopKBottomK(OpTopK, yyDollar[3].staticInt)
 		}
-	case 127:
+	case 145:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.metricsSecondStage = newTopKBottomK(OpBottomK, yyDollar[3].staticInt)
 		}
-	case 128:
+	case 146:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.metricsSecondStage = newSecondStageFunction(yyDollar[1].staticStr, nil)
+		}
+	case 147:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.metricsSecondStage = newSecondStageFunction(yyDollar[1].staticStr, yyDollar[3].metricsSecondStageArgs)
+		}
+	case 148:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.metricsSecondStageArgs = []Static{yyDollar[1].static}
+		}
+	case 149:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.metricsSecondStageArgs = append(yyDollar[1].metricsSecondStageArgs, yyDollar[3].static)
+		}
+	case 150:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.static = NewStaticString(yyDollar[1].staticStr)
+		}
+	case 151:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.static = NewStaticInt(yyDollar[1].staticInt)
+		}
+	case 152:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.static = NewStaticFloat(yyDollar[1].staticFloat)
+		}
+	case 153:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.static = NewStaticDuration(yyDollar[1].staticDuration)
+		}
+	case 154:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.static = NewStaticInt(-yyDollar[2].staticInt)
+		}
+	case 155:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.static = NewStaticFloat(-yyDollar[2].staticFloat)
+		}
+	case 156:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.static = NewStaticDuration(-yyDollar[2].staticDuration)
+		}
+	case 157:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarFilterOperation = OpEqual
 		}
-	case 129:
+	case 158:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarFilterOperation = OpNotEqual
 		}
-	case 130:
+	case 159:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarFilterOperation = OpLess
 		}
-	case 131:
+	case 160:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarFilterOperation = OpLessEqual
 		}
-	case 132:
+	case 161:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarFilterOperation = OpGreater
 		}
-	case 133:
+	case 162:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.scalarFilterOperation = OpGreaterEqual
 		}
-	case 134:
+	case 163:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.metricsSecondStage = newMetricsFilter(yyDollar[1].scalarFilterOperation, float64(yyDollar[2].staticInt))
 		}
-	case 135:
+	case 164:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.metricsSecondStage = newMetricsFilter(yyDollar[1].scalarFilterOperation, yyDollar[2].staticFloat)
 		}
-	case 136:
+	case 165:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.metricsSecondStage = newMetricsFilter(yyDollar[1].scalarFilterOperation, float64(yyDollar[2].staticDuration)/float64(time.Second))
 		}
-	case 137:
+	case 166:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.metricsSecondStage = newMetricsFilter(yyDollar[1].scalarFilterOperation, float64(-yyDollar[3].staticInt))
 		}
-	case 138:
+	case 167:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.metricsSecondStage = newMetricsFilter(yyDollar[1].scalarFilterOperation, -yyDollar[3].staticFloat)
 		}
-	case 139:
+	case 168:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.metricsSecondStage = newMetricsFilter(yyDollar[1].scalarFilterOperation, float64(-yyDollar[3].staticDuration)/float64(time.Second))
 		}
-	case 140:
+	case 169:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.metricsSecondStagePipeline.Append(yyDollar[2].metricsSecondStage, " | ")
 		}
-	case 141:
+	case 170:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.metricsSecondStagePipeline.Append(yyDollar[1].metricsSecondStage, " ")
 		}
-	case 142:
+	case 171:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.metricsSecondStagePipeline = yyDollar[1].metricsSecondStagePipeline
 			yyVAL.metricsSecondStagePipeline.Append(yyDollar[3].metricsSecondStage, " | ")
 		}
-	case 143:
+	case 172:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.metricsSecondStagePipeline = yyDollar[1].metricsSecondStagePipeline
 			yyVAL.metricsSecondStagePipeline.Append(yyDollar[2].metricsSecondStage, " ")
 		}
-	case 144:
+	case 173:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.metricsQuery = newMetricsQuery(yyDollar[2].spansetPipeline, yyDollar[4].metricsAggregation, ChainedSecondStage{})
+		}
+	case 174:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.metricsQuery = newMetricsQuery(yyDollar[2].spansetPipeline, yyDollar[4].metricsAggregation, yyDollar[5].metricsSecondStagePipeline)
+		}
+	case 175:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.staticFloat = float64(yyDollar[1].staticInt)
+		}
+	case 176:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.staticFloat = yyDollar[1].staticFloat
+		}
+	case 177:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.staticFloat = float64(-yyDollar[2].staticInt)
+		}
+	case 178:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.staticFloat = -yyDollar[2].staticFloat
+		}
+	case 179:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.vectorMatching = nil
+		}
+	case 180:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.vectorMatching = newVectorMatching(true, yyDollar[3].attributeList, matchOneToOne)
+		}
+	case 181:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.vectorMatching = newVectorMatching(true, yyDollar[3].attributeList, matchManyToOne)
+		}
+	case 182:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.vectorMatching = newVectorMatching(true, yyDollar[3].attributeList, matchOneToMany)
+		}
+	case 183:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.vectorMatching = newVectorMatching(false, yyDollar[3].attributeList, matchOneToOne)
+		}
+	case 184:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.vectorMatching = newVectorMatching(false, yyDollar[3].attributeList, matchManyToOne)
+		}
+	case 185:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.vectorMatching = newVectorMatching(false, yyDollar[3].attributeList, matchOneToMany)
+		}
+	case 186:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.metricsExpression = yyDollar[1].metricsQuery
+		}
+	case 187:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.metricsExpression = yyDollar[2].metricsExpression
+		}
+	case 188:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsBinaryOperation(OpAdd, yyDollar[1].metricsExpression, yyDollar[4].metricsExpression, yyDollar[3].vectorMatching)
+		}
+	case 189:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsBinaryOperation(OpSub, yyDollar[1].metricsExpression, yyDollar[4].metricsExpression, yyDollar[3].vectorMatching)
+		}
+	case 190:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsBinaryOperation(OpMult, yyDollar[1].metricsExpression, yyDollar[4].metricsExpression, yyDollar[3].vectorMatching)
+		}
+	case 191:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsBinaryOperation(OpDiv, yyDollar[1].metricsExpression, yyDollar[4].metricsExpression, yyDollar[3].vectorMatching)
+		}
+	case 192:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsBinaryOperation(OpMod, yyDollar[1].metricsExpression, yyDollar[4].metricsExpression, yyDollar[3].vectorMatching)
+		}
+	case 193:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsBinaryOperation(OpPower, yyDollar[1].metricsExpression, yyDollar[4].metricsExpression, yyDollar[3].vectorMatching)
+		}
+	case 194:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsBinaryOperation(OpEqual, yyDollar[1].metricsExpression, yyDollar[4].metricsExpression, yyDollar[3].vectorMatching)
+		}
+	case 195:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsBinaryOperation(OpNotEqual, yyDollar[1].metricsExpression, yyDollar[4].metricsExpression, yyDollar[3].vectorMatching)
+		}
+	case 196:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsBinaryOperation(OpLess, yyDollar[1].metricsExpression, yyDollar[4].metricsExpression, yyDollar[3].vectorMatching)
+		}
+	case 197:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsBinaryOperation(OpLessEqual, yyDollar[1].metricsExpression, yyDollar[4].metricsExpression, yyDollar[3].vectorMatching)
+		}
+	case 198:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsBinaryOperation(OpGreater, yyDollar[1].metricsExpression, yyDollar[4].metricsExpression, yyDollar[3].vectorMatching)
+		}
+	case 199:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsBinaryOperation(OpGreaterEqual, yyDollar[1].metricsExpression, yyDollar[4].metricsExpression, yyDollar[3].vectorMatching)
+		}
+	case 200:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsScalarOperation(OpAdd, yyDollar[1].metricsExpression, yyDollar[3].staticFloat)
+		}
+	case 201:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsScalarOperation(OpSub, yyDollar[1].metricsExpression, yyDollar[3].staticFloat)
+		}
+	case 202:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsScalarOperation(OpMult, yyDollar[1].metricsExpression, yyDollar[3].staticFloat)
+		}
+	case 203:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsScalarOperation(OpDiv, yyDollar[1].metricsExpression, yyDollar[3].staticFloat)
+		}
+	case 204:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsScalarOperation(OpMod, yyDollar[1].metricsExpression, yyDollar[3].staticFloat)
+		}
+	case 205:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsScalarOperation(OpPower, yyDollar[1].metricsExpression, yyDollar[3].staticFloat)
+		}
+	case 206:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsScalarOperation(OpEqual, yyDollar[1].metricsExpression, yyDollar[3].staticFloat)
+		}
+	case 207:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsScalarOperation(OpNotEqual, yyDollar[1].metricsExpression, yyDollar[3].staticFloat)
+		}
+	case 208:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsScalarOperation(OpLess, yyDollar[1].metricsExpression, yyDollar[3].staticFloat)
+		}
+	case 209:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsScalarOperation(OpLessEqual, yyDollar[1].metricsExpression, yyDollar[3].staticFloat)
+		}
+	case 210:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsScalarOperation(OpGreater, yyDollar[1].metricsExpression, yyDollar[3].staticFloat)
+		}
+	case 211:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.metricsExpression = newMetricsScalarOperation(OpGreaterEqual, yyDollar[1].metricsExpression, yyDollar[3].staticFloat)
+		}
+	case 212:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.hint = newHint(yyDollar[1].staticStr, yyDollar[3].static)
 		}
-	case 145:
+	case 213:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.hints = newHints(yyDollar[3].hintList)
 		}
-	case 146:
+	case 214:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.hintList = []*Hint{yyDollar[1].hint}
 		}
-	case 147:
+	case 215:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.hintList = append(yyDollar[1].hintList, yyDollar[3].hint)
 		}
-	case 148:
+	case 216:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = yyDollar[2].fieldExpression
 		}
-	case 149:
+	case 217:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpAdd, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 150:
+	case 218:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpSub, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 151:
+	case 219:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpMult, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 152:
+	case 220:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpDiv, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 153:
+	case 221:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpMod, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 154:
+	case 222:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 155:
+	case 223:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpNotEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 156:
+	case 224:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpLess, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 157:
+	case 225:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpLessEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 158:
+	case 226:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpGreater, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 159:
+	case 227:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpGreaterEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 160:
+	case 228:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpRegex, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 161:
+	case 229:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpNotRegex, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 162:
+	case 230:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpPower, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 163:
+	case 231:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpAnd, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 164:
+	case 232:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpOr, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 165:
+	case 233:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newUnaryOperation(OpExists, yyDollar[1].fieldExpression)
 		}
-	case 166:
+	case 234:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newUnaryOperation(OpExists, yyDollar[3].fieldExpression)
 		}
-	case 167:
+	case 235:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newUnaryOperation(OpNotExists, yyDollar[1].fieldExpression)
 		}
-	case 168:
+	case 236:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = newUnaryOperation(OpNotExists, yyDollar[3].fieldExpression)
 		}
-	case 169:
+	case 237:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = NewStaticBool(false)
 		}
-	case 170:
+	case 238:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.fieldExpression = NewStaticBool(false)
 		}
-	case 171:
+	case 239:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.fieldExpression = newUnaryOperation(OpSub, yyDollar[2].fieldExpression)
 		}
-	case 172:
+	case 240:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.fieldExpression = newUnaryOperation(OpNot, yyDollar[2].fieldExpression)
 		}
-	case 173:
+	case 241:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.fieldExpression = yyDollar[1].static
 		}
-	case 174:
+	case 242:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.fieldExpression = yyDollar[1].intrinsicField
 		}
-	case 175:
+	case 243:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.fieldExpression = yyDollar[1].attributeField
 		}
-	case 176:
+	case 244:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.fieldExpression = yyDollar[1].scopedIntrinsicField
 		}
-	case 177:
+	case 245:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.fieldExpression = yyDollar[1].fieldExpression
+		}
+	case 246:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.fieldExpression = newFunctionCall(yyDollar[1].staticStr, yyDollar[3].fieldExpressionList)
+		}
+	case 247:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.fieldExpressionList = []FieldExpression{yyDollar[1].fieldExpression}
+		}
+	case 248:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.fieldExpressionList = append(yyDollar[1].fieldExpressionList, yyDollar[3].fieldExpression)
+		}
+	case 249:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.static = NewStaticString(yyDollar[1].staticStr)
 		}
-	case 178:
+	case 250:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.static = NewStaticInt(yyDollar[1].staticInt)
 		}
-	case 179:
+	case 251:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.static = NewStaticFloat(yyDollar[1].staticFloat)
 		}
-	case 180:
+	case 252:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.static = NewStaticBool(true)
 		}
-	case 181:
+	case 253:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.static = NewStaticBool(false)
 		}
-	case 182:
+	case 254:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.static = NewStaticDuration(yyDollar[1].staticDuration)
 		}
-	case 183:
+	case 255:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.static = NewStaticStatus(StatusOk)
 		}
-	case 184:
+	case 256:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.static = NewStaticStatus(StatusError)
 		}
-	case 185:
+	case 257:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.static = NewStaticStatus(StatusUnset)
 		}
-	case 186:
+	case 258:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.static = NewStaticKind(KindUnspecified)
 		}
-	case 187:
+	case 259:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.static = NewStaticKind(KindInternal)
 		}
-	case 188:
+	case 260:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.static = NewStaticKind(KindServer)
 		}
-	case 189:
+	case 261:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.static = NewStaticKind(KindClient)
 		}
-	case 190:
+	case 262:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.static = NewStaticKind(KindProducer)
 		}
-	case 191:
+	case 263:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.static = NewStaticKind(KindConsumer)
 		}
-	case 192:
+	case 264:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			if yyDollar[1].staticStr == "minInt" {
@@ -1944,201 +2447,201 @@ yydefault:
 			} else if yyDollar[1].staticStr == "maxInt" {
 				yyVAL.static = NewStaticInt(math.MaxInt)
 			} else {
-				yylex.(*lexer).Error("unknown identifier: " + yyDollar[1].staticStr)
+				yylex.(*lexer).errorAt("unknown identifier: "+yyDollar[1].staticStr, yyDollar[1].identifierPos)
 				yyVAL.static = NewStaticNil()
 			}
 		}
-	case 193:
+	case 265:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicDuration)
 		}
-	case 194:
+	case 266:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicName)
 		}
-	case 195:
+	case 267:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicStatus)
 		}
-	case 196:
+	case 268:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicStatusMessage)
 		}
-	case 197:
+	case 269:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicKind)
 		}
-	case 198:
+	case 270:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicParent)
 		}
-	case 199:
+	case 271:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicTraceRootSpan)
 		}
-	case 200:
+	case 272:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicTraceRootService)
 		}
-	case 201:
+	case 273:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicTraceDuration)
 		}
-	case 202:
+	case 274:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicNestedSetLeft)
 		}
-	case 203:
+	case 275:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicNestedSetRight)
 		}
-	case 204:
+	case 276:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicNestedSetParent)
 		}
-	case 205:
+	case 277:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicTraceDuration)
 		}
-	case 206:
+	case 278:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicTraceRootSpan)
 		}
-	case 207:
+	case 279:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicTraceRootService)
 		}
-	case 208:
+	case 280:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicTraceID)
 		}
-	case 209:
+	case 281:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicDuration)
 		}
-	case 210:
+	case 282:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicName)
 		}
-	case 211:
+	case 283:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicKind)
 		}
-	case 212:
+	case 284:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicStatus)
 		}
-	case 213:
+	case 285:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicStatusMessage)
 		}
-	case 214:
+	case 286:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicSpanID)
 		}
-	case 215:
+	case 287:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicParentID)
 		}
-	case 216:
+	case 288:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicChildCount)
 		}
-	case 217:
+	case 289:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicEventName)
 		}
-	case 218:
+	case 290:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicEventTimeSinceStart)
 		}
-	case 219:
+	case 291:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicLinkTraceID)
 		}
-	case 220:
+	case 292:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicLinkSpanID)
 		}
-	case 221:
+	case 293:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicInstrumentationName)
 		}
-	case 222:
+	case 294:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicInstrumentationVersion)
 		}
-	case 223:
+	case 295:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.attributeField = NewAttribute(yyDollar[2].staticStr)
 		}
-	case 224:
+	case 296:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeResource, false, yyDollar[2].staticStr)
 		}
-	case 225:
+	case 297:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeSpan, false, yyDollar[2].staticStr)
 		}
-	case 226:
+	case 298:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeNone, true, yyDollar[2].staticStr)
 		}
-	case 227:
+	case 299:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeResource, true, yyDollar[3].staticStr)
 		}
-	case 228:
+	case 300:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeSpan, true, yyDollar[3].staticStr)
 		}
-	case 229:
+	case 301:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeEvent, false, yyDollar[2].staticStr)
 		}
-	case 230:
+	case 302:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeLink, false, yyDollar[2].staticStr)
 		}
-	case 231:
+	case 303:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeInstrumentation, false, yyDollar[2].staticStr)
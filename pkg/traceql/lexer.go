@@ -20,6 +20,8 @@ var tokenMap = map[string]int{
 	"}":                   CLOSE_BRACE,
 	"(":                   OPEN_PARENS,
 	")":                   CLOSE_PARENS,
+	"[":                   OPEN_BRACKET,
+	"]":                   CLOSE_BRACKET,
 	"=":                   EQ,
 	"!=":                  NEQ,
 	"=~":                  RE,
@@ -62,6 +64,7 @@ var tokenMap = map[string]int{
 	"&<":                  UNION_PARENT,
 	"&>>":                 UNION_DESC,
 	"&<<":                 UNION_ANCE,
+	"->link":              LINK_TRAVERSAL,
 	"duration":            IDURATION,
 	"childCount":          CHILDCOUNT,
 	"name":                NAME,
@@ -109,10 +112,17 @@ var tokenMap = map[string]int{
 	"sum_over_time":       SUM_OVER_TIME,
 	"quantile_over_time":  QUANTILE_OVER_TIME,
 	"histogram_over_time": HISTOGRAM_OVER_TIME,
+	"stddev_over_time":    STDDEV_OVER_TIME,
+	"stdvar_over_time":    STDVAR_OVER_TIME,
 	"compare":             COMPARE,
 	"topk":                TOPK,
 	"bottomk":             BOTTOMK,
+	"on":                  ON,
+	"ignoring":            IGNORING,
+	"group_left":          GROUP_LEFT,
+	"group_right":         GROUP_RIGHT,
 	"with":                WITH,
+	"offset":              OFFSET,
 }
 
 type lexer struct {
@@ -204,6 +214,16 @@ func (l *lexer) Lex(lval *yySymType) int {
 		return FLOAT
 	}
 
+	// ->link is the only token that continues with letters, it isn't found by the combination tokens below
+	if l.TokenText() == "-" && l.Peek() == '>' {
+		l.Next()
+		if l.Scan() != scanner.Ident || l.TokenText() != "link" {
+			l.Error("unexpected ->, expecting ->link")
+			return 0
+		}
+		return LINK_TRAVERSAL
+	}
+
 	// look for combination tokenMap starting with 2 and working up til there is no match
 	// this is only to disambiguate tokenMap with common prefixes. it will not find 3+ token combinations
 	// with no valid prefixes
@@ -249,11 +269,16 @@ func (l *lexer) Lex(lval *yySymType) int {
 
 	// default to an identifier
 	lval.staticStr = l.TokenText()
+	lval.identifierPos = l.Position
 	return IDENTIFIER
 }
 
 func (l *lexer) Error(msg string) {
-	l.errs = append(l.errs, newParseError(msg, l.Line, l.Column))
+	l.errorAt(msg, l.Position)
+}
+
+func (l *lexer) errorAt(msg string, pos scanner.Position) {
+	l.errs = append(l.errs, newParseError(msg, pos.Line, pos.Column))
 }
 
 func parseAttribute(s *scanner.Scanner) (string, error) {
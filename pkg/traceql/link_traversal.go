@@ -0,0 +1,281 @@
+package traceql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// linkLookupConcurrency is the number of linked traces looked up at the same time by ResolveLinks.
+const linkLookupConcurrency = 5
+
+// ErrLinkTraversalDisabled is returned by ResolveLinks if no linked traces can be looked up.
+var ErrLinkTraversalDisabled = errors.New("link traversal is disabled")
+
+// TraceLookup returns the trace with the given ID. A nil trace is returned if it's not found.
+type TraceLookup func(ctx context.Context, traceID []byte) (*tempopb.Trace, error)
+
+// ResolveLinks follows the links of the spans that matched the left side of a ->link query. The
+// linked traces are looked up and the right side is evaluated on them. Linked traces with matching
+// spans are added to the response, spans on the left side are only kept if one of their links points
+// to a matching span, and at most limit traces of the left side are kept once they are filtered. At
+// most maxTraces linked traces are looked up, links to other traces are not followed and are counted
+// in the SkippedLinkedTraces metric of the response. The response is not changed for queries without
+// ->link.
+func ResolveLinks(ctx context.Context, req *tempopb.SearchRequest, resp *tempopb.SearchResponse, lookup TraceLookup, limit, maxTraces int) error {
+	rootExpr, err := Parse(req.Query)
+	if err != nil {
+		return err
+	}
+	if rootExpr.Linked == nil {
+		return nil
+	}
+	if maxTraces <= 0 {
+		return ErrLinkTraversalDisabled
+	}
+
+	spansPerSpanSet := int(req.SpansPerSpanSet)
+	if spansPerSpanSet == 0 {
+		spansPerSpanSet = DefaultSpansPerSpanSet
+	}
+
+	// the attributes of the right side are returned with the matching spans
+	fetchReq := &FetchSpansRequest{}
+	rootExpr.Linked.extractConditions(fetchReq)
+	returned := []Attribute{NewIntrinsic(IntrinsicName)}
+	for _, c := range append(fetchReq.Conditions, fetchReq.SecondPassConditions...) {
+		returned = append(returned, c.Attribute)
+	}
+	selectCalls := rootExpr.Linked.functionCalls()
+
+	// matched holds the trace IDs and trace+span IDs of the matching spans of the linked traces
+	matched := map[string]struct{}{}
+	var linkedTraces []*tempopb.TraceSearchMetadata
+
+	traceIDs, skipped := linkedTraceIDs(resp.Traces, maxTraces)
+	if skipped > 0 {
+		if resp.Metrics == nil {
+			resp.Metrics = &tempopb.SearchMetrics{}
+		}
+		resp.Metrics.SkippedLinkedTraces += uint32(skipped) //nolint:gosec
+	}
+
+	linked, err := lookupLinkedTraces(ctx, traceIDs, lookup)
+	if err != nil {
+		return err
+	}
+
+	for i, traceID := range traceIDs {
+		tr := linked[i]
+		if tr == nil {
+			continue
+		}
+
+		id, err := util.HexStringToTraceID(traceID)
+		if err != nil {
+			continue
+		}
+
+		spanset := linkedSpanset(id, tr, returned)
+		if len(spanset.Spans) == 0 {
+			continue
+		}
+
+		evalSS, err := rootExpr.Linked.evaluate([]*Spanset{spanset})
+		if err != nil {
+			return err
+		}
+
+		var metadata *tempopb.TraceSearchMetadata
+		for _, ss := range evalSS {
+			if len(ss.Spans) == 0 {
+				continue
+			}
+
+			for _, s := range ss.Spans {
+				matched[traceID] = struct{}{}
+				matched[traceID+util.SpanIDToHexString(s.ID())] = struct{}{}
+			}
+
+			ss.AddAttribute(attributeMatched, NewStaticInt(len(ss.Spans)))
+			if len(ss.Spans) > spansPerSpanSet {
+				ss.Spans = ss.Spans[:spansPerSpanSet]
+			}
+			selectValues(ss, selectCalls)
+
+			m := asTraceSearchMetadata(ss)
+			if metadata == nil {
+				metadata = m
+				continue
+			}
+			metadata.SpanSets = append(metadata.SpanSets, m.SpanSet)
+		}
+
+		if metadata != nil {
+			linkedTraces = append(linkedTraces, metadata)
+		}
+	}
+
+	// only keep the spans of the left side that are linked to a matching span
+	traces := resp.Traces[:0]
+	for _, tr := range resp.Traces {
+		spansets := tr.SpanSets[:0]
+		for _, ss := range tr.SpanSets {
+			spans := ss.Spans[:0]
+			for _, s := range ss.Spans {
+				if linksToMatch(s, matched) {
+					spans = append(spans, s)
+				}
+			}
+			if len(spans) == 0 {
+				continue
+			}
+			ss.Spans = spans
+			ss.Matched = uint32(len(spans))
+			spansets = append(spansets, ss)
+		}
+		if len(spansets) == 0 {
+			continue
+		}
+		tr.SpanSets = spansets
+		tr.SpanSet = spansets[0]
+		traces = append(traces, tr)
+	}
+	if limit > 0 && len(traces) > limit {
+		traces = traces[:limit]
+	}
+
+	resp.Traces = append(traces, linkedTraces...)
+	return nil
+}
+
+// lookupLinkedTraces looks up the linked traces concurrently. The traces are returned in the order of
+// the trace IDs, a trace is nil if it's not found.
+func lookupLinkedTraces(ctx context.Context, traceIDs []string, lookup TraceLookup) ([]*tempopb.Trace, error) {
+	traces := make([]*tempopb.Trace, len(traceIDs))
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(linkLookupConcurrency)
+	for i, traceID := range traceIDs {
+		id, err := util.HexStringToTraceID(traceID)
+		if err != nil {
+			continue
+		}
+
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			tr, err := lookup(ctx, id)
+			if err != nil {
+				return fmt.Errorf("error looking up linked trace %s: %w", traceID, err)
+			}
+			traces[i] = tr
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return traces, nil
+}
+
+// linkedTraceIDs returns up to limit unique trace IDs that the spans of the traces link to, and the
+// number of other trace IDs that were skipped.
+func linkedTraceIDs(traces []*tempopb.TraceSearchMetadata, limit int) ([]string, int) {
+	seen := map[string]struct{}{}
+	var ids []string
+	skipped := 0
+
+	for _, tr := range traces {
+		for _, id := range LinkedTraceIDs(tr) {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			if len(ids) == limit {
+				skipped++
+				continue
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, skipped
+}
+
+// LinkedTraceIDs returns the trace IDs that the spans of a trace returned by a ->link query link to.
+// IDs can be returned more than once.
+func LinkedTraceIDs(tr *tempopb.TraceSearchMetadata) []string {
+	var ids []string
+	for _, ss := range tr.SpanSets {
+		for _, s := range ss.Spans {
+			traceIDs, _ := spanLinks(s)
+			ids = append(ids, traceIDs...)
+		}
+	}
+	return ids
+}
+
+// linksToMatch returns true if one of the links of the span points to a matching span. Links
+// without a span ID match any span of the linked trace.
+func linksToMatch(s *tempopb.Span, matched map[string]struct{}) bool {
+	traceIDs, spanIDs := spanLinks(s)
+	for i, traceID := range traceIDs {
+		key := traceID
+		if i < len(spanIDs) && spanIDs[i] != "" {
+			key += spanIDs[i]
+		}
+		if _, ok := matched[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// spanLinks returns the links added to the span by linkValues.
+func spanLinks(s *tempopb.Span) (traceIDs, spanIDs []string) {
+	for _, kv := range s.Attributes {
+		switch kv.Key {
+		case IntrinsicLinkTraceIDAttribute.Name:
+			traceIDs = stringValues(kv.Value)
+		case IntrinsicLinkSpanIDAttribute.Name:
+			spanIDs = stringValues(kv.Value)
+		}
+	}
+	return traceIDs, spanIDs
+}
+
+func stringValues(v *common_v1.AnyValue) []string {
+	if s, ok := v.GetValue().(*common_v1.AnyValue_StringValue); ok {
+		return []string{s.StringValue}
+	}
+
+	var values []string
+	for _, e := range v.GetArrayValue().GetValues() {
+		values = append(values, e.GetStringValue())
+	}
+	return values
+}
+
+// linkedSpanset returns a spanset with all spans of the trace.
+func linkedSpanset(traceID []byte, tr *tempopb.Trace, returned []Attribute) *Spanset {
+	ss := newProtoSpanset(traceID)
+	for _, rs := range tr.ResourceSpans {
+		service := protoServiceName(rs.Resource)
+		for _, scope := range rs.ScopeSpans {
+			for _, s := range scope.Spans {
+				addProtoSpan(ss, s, rs.Resource, scope.Scope, service, returned)
+			}
+		}
+	}
+	return ss
+}
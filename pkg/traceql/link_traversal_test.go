@@ -0,0 +1,184 @@
+package traceql
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	resource_v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	trace_v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+func TestResolveLinks(t *testing.T) {
+	linkedTraceID := func(n byte) []byte {
+		id := make([]byte, 16)
+		id[15] = n
+		return id
+	}
+	linkedSpanID := func(n byte) []byte {
+		id := make([]byte, 8)
+		id[7] = n
+		return id
+	}
+
+	// producer spans with a link to a consumer trace, 0 for no link
+	producer := func(id, link byte) Span {
+		s := newMockSpan(linkedSpanID(id))
+		s.attributes[NewIntrinsic(IntrinsicKind)] = NewStaticKind(KindProducer)
+		if link != 0 {
+			s.attributes[IntrinsicLinkTraceIDAttribute] = NewStaticString(util.TraceIDToHexString(linkedTraceID(link)))
+			s.attributes[IntrinsicLinkSpanIDAttribute] = NewStaticString(util.SpanIDToHexString(linkedSpanID(link)))
+		}
+		return s
+	}
+
+	consumer := func(id byte, kind trace_v1.Span_SpanKind) *tempopb.Trace {
+		return &tempopb.Trace{
+			ResourceSpans: []*trace_v1.ResourceSpans{{
+				Resource: &resource_v1.Resource{
+					Attributes: []*common_v1.KeyValue{{Key: "service.name", Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: "consumer"}}}},
+				},
+				ScopeSpans: []*trace_v1.ScopeSpans{{
+					Spans: []*trace_v1.Span{{
+						TraceId:           linkedTraceID(id),
+						SpanId:            linkedSpanID(id),
+						Name:              "consume",
+						Kind:              kind,
+						StartTimeUnixNano: 10,
+						EndTimeUnixNano:   20,
+						Attributes:        []*common_v1.KeyValue{{Key: "topic", Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: "orders"}}}},
+					}},
+				}},
+			}},
+		}
+	}
+
+	traces := map[string]*tempopb.Trace{
+		util.TraceIDToHexString(linkedTraceID(2)): consumer(2, trace_v1.Span_SPAN_KIND_CONSUMER),
+		util.TraceIDToHexString(linkedTraceID(3)): consumer(3, trace_v1.Span_SPAN_KIND_INTERNAL),
+	}
+
+	search := func(query string) (*tempopb.SearchRequest, *tempopb.SearchResponse) {
+		rootExpr, err := Parse(query)
+		require.NoError(t, err)
+
+		ss := &Spanset{
+			TraceID: linkedTraceID(1),
+			Spans:   []Span{producer(1, 2), producer(2, 3), producer(3, 0)},
+		}
+		ss.AddAttribute(attributeMatched, NewStaticInt(3))
+		if rootExpr.Linked != nil {
+			linkValues(ss)
+		}
+
+		return &tempopb.SearchRequest{Query: query}, &tempopb.SearchResponse{
+			Traces: []*tempopb.TraceSearchMetadata{asTraceSearchMetadata(ss)},
+		}
+	}
+
+	var lookups atomic.Int32
+	lookup := func(_ context.Context, traceID []byte) (*tempopb.Trace, error) {
+		lookups.Add(1)
+		return traces[util.TraceIDToHexString(traceID)], nil
+	}
+
+	// only the producer span that links to a consumer span is kept
+	req, resp := search(`{ kind = producer } ->link { kind = consumer && span.topic = "orders" }`)
+	require.NoError(t, ResolveLinks(context.Background(), req, resp, lookup, 0, 10))
+	require.Equal(t, int32(2), lookups.Load())
+	require.Len(t, resp.Traces, 2)
+	require.Nil(t, resp.Metrics)
+
+	lhs := resp.Traces[0]
+	require.Equal(t, util.TraceIDToHexString(linkedTraceID(1)), lhs.TraceID)
+	require.Len(t, lhs.SpanSets, 1)
+	require.Equal(t, lhs.SpanSets[0], lhs.SpanSet)
+	require.Equal(t, uint32(1), lhs.SpanSet.Matched)
+	require.Len(t, lhs.SpanSet.Spans, 1)
+	require.Equal(t, util.SpanIDToHexString(linkedSpanID(1)), lhs.SpanSet.Spans[0].SpanID)
+
+	rhs := resp.Traces[1]
+	require.Equal(t, util.TraceIDToHexString(linkedTraceID(2)), rhs.TraceID)
+	require.Equal(t, "consume", rhs.RootTraceName)
+	require.Equal(t, "consumer", rhs.RootServiceName)
+	require.Equal(t, uint32(1), rhs.SpanSet.Matched)
+	require.Len(t, rhs.SpanSet.Spans, 1)
+	require.Equal(t, "consume", rhs.SpanSet.Spans[0].Name)
+	require.Contains(t, rhs.SpanSet.Spans[0].Attributes, &common_v1.KeyValue{Key: "topic", Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: "orders"}}})
+
+	// the fan-out limits the linked traces that are looked up
+	lookups.Store(0)
+	req, resp = search(`{ kind = producer } ->link { }`)
+	require.NoError(t, ResolveLinks(context.Background(), req, resp, lookup, 0, 1))
+	require.Equal(t, int32(1), lookups.Load())
+	require.Len(t, resp.Traces, 2)
+	require.Len(t, resp.Traces[0].SpanSet.Spans, 1)
+	require.Equal(t, uint32(1), resp.Metrics.SkippedLinkedTraces)
+
+	// the limit applies to the traces of the left side once they are filtered
+	unlinked := &Spanset{TraceID: linkedTraceID(4), Spans: []Span{producer(4, 0)}}
+	linkValues(unlinked)
+	req, resp = search(`{ kind = producer } ->link { kind = consumer }`)
+	resp.Traces = append([]*tempopb.TraceSearchMetadata{asTraceSearchMetadata(unlinked)}, resp.Traces...)
+	require.NoError(t, ResolveLinks(context.Background(), req, resp, lookup, 1, 10))
+	require.Len(t, resp.Traces, 2)
+	require.Equal(t, util.TraceIDToHexString(linkedTraceID(1)), resp.Traces[0].TraceID)
+	require.Equal(t, util.TraceIDToHexString(linkedTraceID(2)), resp.Traces[1].TraceID)
+
+	// nothing matches on the linked traces
+	req, resp = search(`{ kind = producer } ->link { kind = server }`)
+	require.NoError(t, ResolveLinks(context.Background(), req, resp, lookup, 0, 10))
+	require.Empty(t, resp.Traces)
+
+	// link traversal is disabled
+	req, resp = search(`{ kind = producer } ->link { kind = consumer }`)
+	require.ErrorIs(t, ResolveLinks(context.Background(), req, resp, lookup, 0, 0), ErrLinkTraversalDisabled)
+
+	// queries without ->link are not changed
+	lookups.Store(0)
+	req, resp = search(`{ kind = producer }`)
+	require.NoError(t, ResolveLinks(context.Background(), req, resp, lookup, 0, 10))
+	require.Equal(t, int32(0), lookups.Load())
+	require.Len(t, resp.Traces, 1)
+	require.Len(t, resp.Traces[0].SpanSet.Spans, 3)
+}
+
+func TestLinkValues(t *testing.T) {
+	// the second link has no span ID
+	s := &orderedAttributesSpan{mockSpan: newMockSpan([]byte{1}), attributes: []attrVal{
+		{IntrinsicLinkTraceIDAttribute, NewStaticString("a")},
+		{IntrinsicLinkSpanIDAttribute, NewStaticString("1")},
+		{IntrinsicLinkTraceIDAttribute, NewStaticString("b")},
+		{IntrinsicLinkTraceIDAttribute, NewStaticString("c")},
+		{IntrinsicLinkSpanIDAttribute, NewStaticString("3")},
+	}}
+	ss := &Spanset{Spans: []Span{s}}
+	linkValues(ss)
+
+	tr := asTraceSearchMetadata(ss)
+	traceIDs, spanIDs := spanLinks(tr.SpanSet.Spans[0])
+	require.Equal(t, []string{"a", "b", "c"}, traceIDs)
+	require.Equal(t, []string{"1", "", "3"}, spanIDs)
+}
+
+type attrVal struct {
+	a Attribute
+	s Static
+}
+
+// orderedAttributesSpan returns its attributes in order, like the spans of the storage layer.
+type orderedAttributesSpan struct {
+	*mockSpan
+	attributes []attrVal
+}
+
+func (s *orderedAttributesSpan) AllAttributesFunc(cb func(Attribute, Static)) {
+	for _, a := range s.attributes {
+		cb(a.a, a.s)
+	}
+}
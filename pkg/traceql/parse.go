@@ -3,6 +3,7 @@ package traceql
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"text/scanner"
 
@@ -127,3 +128,97 @@ func newParseError(msg string, line, col int) *ParseError {
 		col:  col,
 	}
 }
+
+// MacroPrefix marks a reference to a named query macro, e.g. $errors.
+const MacroPrefix = '$'
+
+// ExpandMacros replaces every $name reference outside of string literals with the body of the
+// macro with that name in parentheses, so the operators of the body keep their precedence, i.e.
+// $m && c with the body a || b is (a || b) && c. Macro bodies are TraceQL expressions and can
+// reference other macros. Unknown macros and cycles are errors. Expansion is done on the query
+// text, so it has to happen before the query is parsed and validated.
+func ExpandMacros(query string, macros map[string]string) (string, error) {
+	if !strings.ContainsRune(query, MacroPrefix) {
+		return query, nil
+	}
+	return expandMacros(query, macros, nil)
+}
+
+// IsValidMacroName returns true if the name can be referenced from a query.
+func IsValidMacroName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isMacroNameChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func expandMacros(query string, macros map[string]string, stack []string) (string, error) {
+	sb := strings.Builder{}
+	sb.Grow(len(query))
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch c {
+		case '"', '`':
+			end := stringLiteralEnd(query, i)
+			sb.WriteString(query[i:end])
+			i = end
+		case MacroPrefix:
+			end := i + 1
+			for end < len(query) && isMacroNameChar(query[end]) {
+				end++
+			}
+			name := query[i+1 : end]
+			if !IsValidMacroName(name) {
+				return "", fmt.Errorf("invalid macro reference at position %d", i)
+			}
+			if slices.Contains(stack, name) {
+				return "", fmt.Errorf("macro cycle detected: $%s -> $%s", strings.Join(stack, " -> $"), name)
+			}
+			body, ok := macros[name]
+			if !ok {
+				return "", fmt.Errorf("unknown macro: $%s", name)
+			}
+			expanded, err := expandMacros(body, macros, append(stack[:len(stack):len(stack)], name))
+			if err != nil {
+				return "", err
+			}
+			sb.WriteByte('(')
+			sb.WriteString(expanded)
+			sb.WriteByte(')')
+			i = end
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// stringLiteralEnd returns the position after the string literal starting at start. Double
+// quoted strings can contain escaped quotes, backtick quoted strings can't. Unterminated
+// strings run to the end of the query and are left for the parser to reject.
+func stringLiteralEnd(query string, start int) int {
+	quote := query[start]
+	for i := start + 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+func isMacroNameChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
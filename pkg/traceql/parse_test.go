@@ -551,8 +551,8 @@ func TestSelectOperation(t *testing.T) {
 		expected    Pipeline
 		expectedStr string
 	}{
-		{in: "select(.a)", expected: newPipeline(newSelectOperation([]Attribute{NewAttribute("a")})), expectedStr: "select(.a)"},
-		{in: "select(.a,.b)", expected: newPipeline(newSelectOperation([]Attribute{NewAttribute("a"), NewAttribute("b")})), expectedStr: "select(.a, .b)"},
+		{in: "select(.a)", expected: newPipeline(newSelectOperation([]FieldExpression{NewAttribute("a")})), expectedStr: "select(.a)"},
+		{in: "select(.a,.b)", expected: newPipeline(newSelectOperation([]FieldExpression{NewAttribute("a"), NewAttribute("b")})), expectedStr: "select(.a, .b)"},
 	}
 
 	for _, tc := range tests {
@@ -566,6 +566,87 @@ func TestSelectOperation(t *testing.T) {
 	}
 }
 
+func TestFunctionCall(t *testing.T) {
+	tests := []struct {
+		in          string
+		expected    Pipeline
+		expectedStr string
+	}{
+		{
+			in: `{ lower(span.foo) = "bar" }`,
+			expected: newPipeline(newSpansetFilter(newBinaryOperation(OpEqual,
+				newFunctionCall("lower", []FieldExpression{NewScopedAttribute(AttributeScopeSpan, false, "foo")}),
+				NewStaticString("bar"),
+			))),
+			expectedStr: "{ (lower(span.foo)) = `bar` }",
+		},
+		{
+			in: `{ startsWith(.foo, "a") && len(split(name, "/")) > 2 }`,
+			expected: newPipeline(newSpansetFilter(newBinaryOperation(OpAnd,
+				newFunctionCall("startsWith", []FieldExpression{NewAttribute("foo"), NewStaticString("a")}),
+				newBinaryOperation(OpGreater,
+					newFunctionCall("len", []FieldExpression{
+						newFunctionCall("split", []FieldExpression{NewIntrinsic(IntrinsicName), NewStaticString("/")}),
+					}),
+					NewStaticInt(2),
+				),
+			))),
+			expectedStr: "{ (startsWith(.foo, `a`)) && ((len(split(name, `/`))) > 2) }",
+		},
+		{
+			in: `{ } | by(substring(.foo, 0, 3)) | select(.bar, upper(.bar))`,
+			expected: newPipeline(
+				newSpansetFilter(NewStaticBool(true)),
+				newGroupOperation(newFunctionCall("substring", []FieldExpression{NewAttribute("foo"), NewStaticInt(0), NewStaticInt(3)})),
+				newSelectOperation([]FieldExpression{
+					NewAttribute("bar"),
+					newFunctionCall("upper", []FieldExpression{NewAttribute("bar")}),
+				}),
+			),
+			expectedStr: "{ true }|by(substring(.foo, 0, 3))|select(.bar, upper(.bar))",
+		},
+		{
+			// function calls without attributes are evaluated when parsing
+			in:          `{ .foo = lower("BAR") }`,
+			expected:    newPipeline(newSpansetFilter(newBinaryOperation(OpEqual, NewAttribute("foo"), NewStaticString("bar")))),
+			expectedStr: "{ .foo = `bar` }",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			actual, err := Parse(tc.in)
+
+			require.NoError(t, err)
+			require.Equal(t, newRootExpr(tc.expected), actual)
+			require.Equal(t, tc.expectedStr, actual.String())
+		})
+	}
+}
+
+func TestFunctionCallErrors(t *testing.T) {
+	tests := []struct {
+		in  string
+		err string
+	}{
+		{in: `{ foo(.a) }`, err: "unknown function: foo"},
+		{in: `{ lower(.a, .b) = "a" }`, err: "lower() expects 1 arguments, got 2"},
+		{in: `{ substring(.a) = "a" }`, err: "substring() expects 2 to 3 arguments, got 1"},
+		{in: `{ startsWith(.a, 1) }`, err: "startsWith() argument 2 must be a string, got 1"},
+		{in: `{ len(duration) > 1 }`, err: "len() argument 1 must be a string or array, got duration"},
+		{in: `{ lower(.a) }`, err: "span filter field expressions must resolve to a boolean: { lower(.a) }"},
+		{in: `{ } | select(lower("a"))`, err: "select field expressions must reference the span: select(`a`)"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			expr, err := Parse(tc.in)
+			require.NoError(t, err)
+			require.EqualError(t, expr.validate(), tc.err)
+		})
+	}
+}
+
 func TestSpansetExpressionErrors(t *testing.T) {
 	tests := []struct {
 		in  string
@@ -1577,6 +1658,81 @@ func TestHints(t *testing.T) {
 	}
 }
 
+func TestOffset(t *testing.T) {
+	tests := []struct {
+		in          string
+		expected    *RootExpr
+		expectedStr string
+	}{
+		{
+			in: `{ } | rate() offset 7d`,
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregate(metricsAggregateRate, nil),
+			).withOffset(7 * 24 * time.Hour),
+			expectedStr: "{ true } | rate() offset 168h0m0s",
+		},
+		{
+			in: `{ } | rate() offset 1h with(foo="bar")`,
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregate(metricsAggregateRate, nil),
+			).withOffset(time.Hour).withHints(newHints([]*Hint{
+				newHint("foo", NewStaticString("bar")),
+			})),
+			expectedStr: "{ true } | rate() offset 1h0m0s with(foo=`bar`)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			actual, err := Parse(tc.in)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+			require.Equal(t, tc.expectedStr, actual.String())
+		})
+	}
+}
+
+func TestLinkTraversal(t *testing.T) {
+	tests := []struct {
+		in          string
+		expected    *RootExpr
+		expectedStr string
+	}{
+		{
+			in: `{ kind = producer } ->link { kind = consumer }`,
+			expected: newRootExprWithLinkTraversal(
+				newPipeline(newSpansetFilter(newBinaryOperation(OpEqual, NewIntrinsic(IntrinsicKind), NewStaticKind(KindProducer)))),
+				newPipeline(newSpansetFilter(newBinaryOperation(OpEqual, NewIntrinsic(IntrinsicKind), NewStaticKind(KindConsumer)))),
+			),
+			expectedStr: "{ kind = producer } ->link { kind = consumer }",
+		},
+		{
+			in: `{ .a }->link{ .b } | count() > 1`,
+			expected: newRootExprWithLinkTraversal(
+				newPipeline(newSpansetFilter(NewAttribute("a"))),
+				newPipeline(
+					newSpansetFilter(NewAttribute("b")),
+					newScalarFilter(OpGreater, newAggregate(aggregateCount, nil), NewStaticInt(1)),
+				),
+			),
+			expectedStr: "{ .a } ->link { .b }|(count()) > 1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			actual, err := Parse(tc.in)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+			require.Equal(t, tc.expectedStr, actual.String())
+		})
+	}
+}
+
 func TestReallyLongQuery(t *testing.T) {
 	for i := 1000; i < 1050; i++ {
 		longVal := strings.Repeat("a", i)
@@ -1665,6 +1821,28 @@ func TestMetrics(t *testing.T) {
 			),
 			expectedStr: `{ true } | avg_over_time(duration)by(name,span.http.status_code)`,
 		},
+		{
+			in: `{ } | stddev_over_time(duration) by(name)`,
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newVarianceOverTimeMetricsAggregator(metricsAggregateStddevOverTime,
+					NewIntrinsic(IntrinsicDuration),
+					[]Attribute{
+						NewIntrinsic(IntrinsicName),
+					}),
+			),
+			expectedStr: `{ true } | stddev_over_time(duration)by(name)`,
+		},
+		{
+			in: `{ } | stdvar_over_time(span.http.response.size)`,
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newVarianceOverTimeMetricsAggregator(metricsAggregateStdvarOverTime,
+					NewScopedAttribute(AttributeScopeSpan, false, "http.response.size"),
+					nil),
+			),
+			expectedStr: `{ true } | stdvar_over_time(span.http.response.size)`,
+		},
 		{
 			in: `{ } | sum_over_time(duration) by(name, span.http.status_code)`,
 			expected: newRootExprWithMetrics(
@@ -1685,6 +1863,7 @@ func TestMetrics(t *testing.T) {
 				newMetricsAggregateQuantileOverTime(
 					NewIntrinsic(IntrinsicDuration),
 					[]float64{0, 0.9, 0.95, 1.0},
+					nil,
 					[]Attribute{
 						NewIntrinsic(IntrinsicName),
 						NewScopedAttribute(AttributeScopeSpan, false, "http.status_code"),
@@ -1692,6 +1871,45 @@ func TestMetrics(t *testing.T) {
 			),
 			expectedStr: `{ true } | quantile_over_time(duration,0.00000,0.90000,0.95000,1.00000)by(name,span.http.status_code)`,
 		},
+		{
+			in: `{ } | quantile_over_time(span.payload_size, 0.5, 0.99, buckets=[100, 1000, 1e4]) by(name)`,
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregateQuantileOverTime(
+					NewScopedAttribute(AttributeScopeSpan, false, "payload_size"),
+					[]float64{0.5, 0.99},
+					[]float64{100, 1000, 10000},
+					[]Attribute{NewIntrinsic(IntrinsicName)}),
+			),
+			expectedStr: `{ true } | quantile_over_time(span.payload_size,0.50000,0.99000,buckets=[100,1000,10000])by(name)`,
+		},
+		{
+			in: `{ } | histogram_over_time(span.payload_size, buckets=[0.5, 1, 2.5])`,
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregateHistogramOverTime(NewScopedAttribute(AttributeScopeSpan, false, "payload_size"), []float64{0.5, 1, 2.5}, nil),
+			),
+			expectedStr: `{ true } | histogram_over_time(span.payload_size,buckets=[0.5,1,2.5])`,
+		},
+		{
+			in: `{ } | histogram_over_time(span.payload_size, buckets=linear(0, 250, 4)) by(name)`,
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregateHistogramOverTime(
+					NewScopedAttribute(AttributeScopeSpan, false, "payload_size"),
+					[]float64{0, 250, 500, 750},
+					[]Attribute{NewIntrinsic(IntrinsicName)}),
+			),
+			expectedStr: `{ true } | histogram_over_time(span.payload_size,buckets=[0,250,500,750])by(name)`,
+		},
+		{
+			in: `{ } | histogram_over_time(duration, buckets=exponential(0.001, 10, 4))`,
+			expected: newRootExprWithMetrics(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregateHistogramOverTime(NewIntrinsic(IntrinsicDuration), []float64{0.001, 0.01, 0.1, 1}, nil),
+			),
+			expectedStr: `{ true } | histogram_over_time(duration,buckets=[0.001,0.01,0.1,1])`,
+		},
 	}
 
 	for _, tc := range tests {
@@ -1897,6 +2115,61 @@ func TestMetricsSecondStage(t *testing.T) {
 			),
 			expectedStr: `{ true } | count_over_time() | topk(1) != 0 | topk(10)`,
 		},
+		{
+			in: `{ } | rate() | abs() | clamp_min(-1.5) | clamp_max(10)`,
+			expected: newRootExprWithMetricsTwoStage(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregate(metricsAggregateRate, nil),
+				ChainedSecondStage{
+					elements: []secondStageElement{
+						newSecondStageFunction("abs", nil),
+						newSecondStageFunction("clamp_min", []Static{NewStaticFloat(-1.5)}),
+						newSecondStageFunction("clamp_max", []Static{NewStaticInt(10)}),
+					},
+					separators: []string{" | ", " | ", " | "},
+				},
+			),
+			expectedStr: `{ true } | rate() | abs() | clamp_min(-1.5) | clamp_max(10)`,
+		},
+		{
+			in: `{ } | rate() by(span.foo) | predict_linear(1h) > 10 | sort_desc()`,
+			expected: newRootExprWithMetricsTwoStage(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregate(metricsAggregateRate, []Attribute{
+					NewScopedAttribute(AttributeScopeSpan, false, "foo"),
+				}),
+				ChainedSecondStage{
+					elements: []secondStageElement{
+						newSecondStageFunction("predict_linear", []Static{NewStaticDuration(time.Hour)}),
+						newMetricsFilter(OpGreater, 10),
+						newSecondStageFunction("sort_desc", nil),
+					},
+					separators: []string{" | ", " ", " | "},
+				},
+			),
+			expectedStr: `{ true } | rate()by(span.foo) | predict_linear(1h0m0s) > 10 | sort_desc()`,
+		},
+		{
+			in: `{ } | rate() by(resource.service.name) | label_replace("svc", "$1", "resource.service.name", "(.*)-service")`,
+			expected: newRootExprWithMetricsTwoStage(
+				newPipeline(newSpansetFilter(NewStaticBool(true))),
+				newMetricsAggregate(metricsAggregateRate, []Attribute{
+					NewScopedAttribute(AttributeScopeResource, false, "service.name"),
+				}),
+				ChainedSecondStage{
+					elements: []secondStageElement{
+						newSecondStageFunction("label_replace", []Static{
+							NewStaticString("svc"),
+							NewStaticString("$1"),
+							NewStaticString("resource.service.name"),
+							NewStaticString("(.*)-service"),
+						}),
+					},
+					separators: []string{" | "},
+				},
+			),
+			expectedStr: "{ true } | rate()by(resource.service.name) | label_replace(`svc`, `$1`, `resource.service.name`, `(.*)-service`)",
+		},
 	}
 
 	for _, tc := range tests {
@@ -2148,3 +2421,81 @@ func TestMetricsFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestExpandMacros(t *testing.T) {
+	macros := map[string]string{
+		"errors":     "status = error",
+		"checkout":   "resource.service.name = `checkout`",
+		"slow":       "$checkout && duration > 1s",
+		"any_error":  "status = error || span.http.status_code >= 500",
+		"quoted":     "span.foo = \"$errors\"",
+		"cycle_a":    "$cycle_b",
+		"cycle_b":    "$cycle_a",
+		"error_rate": "{ $errors } | rate() by (span.foo)",
+	}
+
+	tests := []struct {
+		query string
+		want  string
+		err   string
+	}{
+		{
+			query: "{ status = error }",
+			want:  "{ status = error }",
+		},
+		{
+			query: "{ $errors && $checkout }",
+			want:  "{ (status = error) && (resource.service.name = `checkout`) }",
+		},
+		{
+			query: "{ $slow } | rate()",
+			want:  "{ ((resource.service.name = `checkout`) && duration > 1s) } | rate()",
+		},
+		{
+			// the body keeps its precedence
+			query: "{ $any_error && duration > 1s }",
+			want:  "{ (status = error || span.http.status_code >= 500) && duration > 1s }",
+		},
+		{
+			query: "$error_rate",
+			want:  "({ (status = error) } | rate() by (span.foo))",
+		},
+		{
+			// references in strings are left alone
+			query: "{ $errors && span.foo = \"$bar \\\" $baz\" && span.bar = `$foo` }",
+			want:  "{ (status = error) && span.foo = \"$bar \\\" $baz\" && span.bar = `$foo` }",
+		},
+		{
+			// also in the bodies of macros
+			query: "{ $quoted }",
+			want:  "{ (span.foo = \"$errors\") }",
+		},
+		{
+			query: "{ $missing }",
+			err:   "unknown macro: $missing",
+		},
+		{
+			query: "{ $cycle_a }",
+			err:   "macro cycle detected: $cycle_a -> $cycle_b -> $cycle_a",
+		},
+		{
+			query: "{ $ }",
+			err:   "invalid macro reference at position 2",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			actual, err := ExpandMacros(tc.query, macros)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, actual)
+
+			_, err = Parse(actual)
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package traceql
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	resource_v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	trace_v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// protoPipeline is the spanset pipeline of a query that is evaluated in memory on the spans of
+// proto batches instead of on the spans fetched from the backend.
+type protoPipeline struct {
+	pipeline Pipeline
+
+	// pipeline elements reuse buffers between evaluations
+	mtx sync.Mutex
+}
+
+// compileProtoPipeline parses and validates a query that is evaluated on proto batches. The spans
+// don't know their relations, so links and structural operators are rejected. where names the
+// caller in the errors, i.e. "in conditions".
+func compileProtoPipeline(query, where string) (*RootExpr, *protoPipeline, error) {
+	expr, err := Parse(query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := expr.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	if expr.Linked != nil {
+		return nil, nil, fmt.Errorf("->link is not supported %s", where)
+	}
+	if op, ok := structuralOperator(expr.Pipeline); ok {
+		return nil, nil, fmt.Errorf("structural operator (%v) is not supported %s", op, where)
+	}
+
+	return expr, &protoPipeline{pipeline: expr.Pipeline}, nil
+}
+
+// evaluate returns the spansets of the batches that match the pipeline.
+func (p *protoPipeline) evaluate(batches []*trace_v1.ResourceSpans) ([]*Spanset, error) {
+	spansets := protoSpansets(batches, nil)
+	if len(spansets) == 0 {
+		return nil, nil
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	return p.pipeline.evaluate(spansets)
+}
+
+// protoSpansets returns a spanset per trace with the spans of the batches. The spansets only hold
+// the spans of the batches, so trace level intrinsics can be based on a part of the trace.
+func protoSpansets(batches []*trace_v1.ResourceSpans, returned []Attribute) []*Spanset {
+	var (
+		spansets []*Spanset
+		byTrace  = map[string]*Spanset{}
+	)
+
+	for _, rs := range batches {
+		service := protoServiceName(rs.Resource)
+		for _, scope := range rs.ScopeSpans {
+			for _, s := range scope.Spans {
+				ss, ok := byTrace[string(s.TraceId)]
+				if !ok {
+					ss = newProtoSpanset(s.TraceId)
+					byTrace[string(s.TraceId)] = ss
+					spansets = append(spansets, ss)
+				}
+				addProtoSpan(ss, s, rs.Resource, scope.Scope, service, returned)
+			}
+		}
+	}
+
+	return spansets
+}
+
+func newProtoSpanset(traceID []byte) *Spanset {
+	return &Spanset{
+		TraceID:      traceID,
+		ServiceStats: map[string]ServiceStats{},
+	}
+}
+
+// addProtoSpan adds the span to the spanset and updates the trace level fields of the spanset.
+func addProtoSpan(ss *Spanset, s *trace_v1.Span, resource *resource_v1.Resource, scope *common_v1.InstrumentationScope, service string, returned []Attribute) {
+	ss.Spans = append(ss.Spans, &protoSpan{
+		span:     s,
+		resource: resource,
+		scope:    scope,
+		spanset:  ss,
+		returned: returned,
+	})
+
+	stats := ss.ServiceStats[service]
+	stats.SpanCount++
+	if s.GetStatus().GetCode() == trace_v1.Status_STATUS_CODE_ERROR {
+		stats.ErrorCount++
+	}
+	ss.ServiceStats[service] = stats
+
+	start, end := ss.StartTimeUnixNanos, ss.StartTimeUnixNanos+ss.DurationNanos
+	if len(ss.Spans) == 1 || s.StartTimeUnixNano < start {
+		start = s.StartTimeUnixNano
+	}
+	end = max(end, s.EndTimeUnixNano)
+	ss.StartTimeUnixNanos = start
+	ss.DurationNanos = 0
+	if end > start {
+		ss.DurationNanos = end - start
+	}
+
+	if len(s.ParentSpanId) == 0 {
+		ss.RootSpanName = s.Name
+		ss.RootServiceName = service
+	}
+}
+
+func protoServiceName(resource *resource_v1.Resource) string {
+	for _, kv := range resource.GetAttributes() {
+		if kv.Key == "service.name" {
+			return kv.Value.GetStringValue()
+		}
+	}
+	return ""
+}
+
+// protoSpan is a span that is evaluated in memory from its proto representation, e.g. the span of
+// a linked trace. Spans don't know their relations, so structural queries are not supported.
+type protoSpan struct {
+	span     *trace_v1.Span
+	resource *resource_v1.Resource
+	scope    *common_v1.InstrumentationScope
+	spanset  *Spanset
+
+	// returned are the attributes returned by AllAttributes
+	returned []Attribute
+}
+
+var _ Span = (*protoSpan)(nil)
+
+func (s *protoSpan) AttributeFor(a Attribute) (Static, bool) {
+	if a.Intrinsic != IntrinsicNone {
+		return s.intrinsic(a.Intrinsic)
+	}
+
+	switch a.Scope {
+	case AttributeScopeSpan:
+		return findAttribute(s.span.Attributes, a.Name)
+	case AttributeScopeResource:
+		return findAttribute(s.resource.GetAttributes(), a.Name)
+	case AttributeScopeInstrumentation:
+		return findAttribute(s.scope.GetAttributes(), a.Name)
+	case AttributeScopeNone:
+		if v, ok := findAttribute(s.span.Attributes, a.Name); ok {
+			return v, true
+		}
+		return findAttribute(s.resource.GetAttributes(), a.Name)
+	}
+
+	return NewStaticNil(), false
+}
+
+func (s *protoSpan) intrinsic(i Intrinsic) (Static, bool) {
+	switch i {
+	case IntrinsicName:
+		return NewStaticString(s.span.Name), true
+	case IntrinsicStatus:
+		return NewStaticStatus(statusFromProto(s.span.GetStatus().GetCode())), true
+	case IntrinsicStatusMessage:
+		return NewStaticString(s.span.GetStatus().GetMessage()), true
+	case IntrinsicKind:
+		return NewStaticKind(kindFromProto(s.span.Kind)), true
+	case IntrinsicDuration:
+		return NewStaticDuration(time.Duration(s.DurationNanos())), true
+	case IntrinsicSpanID:
+		return NewStaticString(util.SpanIDToHexString(s.span.SpanId)), true
+	case IntrinsicParentID:
+		if len(s.span.ParentSpanId) == 0 {
+			return NewStaticNil(), false
+		}
+		return NewStaticString(util.SpanIDToHexString(s.span.ParentSpanId)), true
+	case IntrinsicTraceID:
+		return NewStaticString(util.TraceIDToHexString(s.spanset.TraceID)), true
+	case IntrinsicTraceRootSpan:
+		return NewStaticString(s.spanset.RootSpanName), true
+	case IntrinsicTraceRootService:
+		return NewStaticString(s.spanset.RootServiceName), true
+	case IntrinsicTraceDuration:
+		return NewStaticDuration(time.Duration(s.spanset.DurationNanos)), true
+	case IntrinsicInstrumentationName:
+		return NewStaticString(s.scope.GetName()), true
+	case IntrinsicInstrumentationVersion:
+		return NewStaticString(s.scope.GetVersion()), true
+	}
+
+	return NewStaticNil(), false
+}
+
+func (s *protoSpan) AllAttributes() map[Attribute]Static {
+	atts := make(map[Attribute]Static, len(s.returned))
+	s.AllAttributesFunc(func(a Attribute, v Static) {
+		atts[a] = v
+	})
+	return atts
+}
+
+func (s *protoSpan) AllAttributesFunc(cb func(Attribute, Static)) {
+	for _, a := range s.returned {
+		if v, ok := s.AttributeFor(a); ok {
+			cb(a, v)
+		}
+	}
+}
+
+func (s *protoSpan) ID() []byte {
+	return s.span.SpanId
+}
+
+func (s *protoSpan) StartTimeUnixNanos() uint64 {
+	return s.span.StartTimeUnixNano
+}
+
+func (s *protoSpan) DurationNanos() uint64 {
+	if s.span.EndTimeUnixNano < s.span.StartTimeUnixNano {
+		return 0
+	}
+	return s.span.EndTimeUnixNano - s.span.StartTimeUnixNano
+}
+
+func (s *protoSpan) SiblingOf([]Span, []Span, bool, bool, []Span) []Span {
+	return nil
+}
+
+func (s *protoSpan) DescendantOf([]Span, []Span, bool, bool, bool, []Span) []Span {
+	return nil
+}
+
+func (s *protoSpan) ChildOf([]Span, []Span, bool, bool, bool, []Span) []Span {
+	return nil
+}
+
+func findAttribute(attrs []*common_v1.KeyValue, name string) (Static, bool) {
+	for _, kv := range attrs {
+		if kv.Key == name {
+			return StaticFromAnyValue(kv.Value), true
+		}
+	}
+	return NewStaticNil(), false
+}
+
+func statusFromProto(code trace_v1.Status_StatusCode) Status {
+	switch code {
+	case trace_v1.Status_STATUS_CODE_UNSET:
+		return StatusUnset
+	case trace_v1.Status_STATUS_CODE_OK:
+		return StatusOk
+	case trace_v1.Status_STATUS_CODE_ERROR:
+		return StatusError
+	}
+	return Status(code)
+}
+
+func kindFromProto(kind trace_v1.Span_SpanKind) Kind {
+	switch kind {
+	case trace_v1.Span_SPAN_KIND_UNSPECIFIED:
+		return KindUnspecified
+	case trace_v1.Span_SPAN_KIND_INTERNAL:
+		return KindInternal
+	case trace_v1.Span_SPAN_KIND_SERVER:
+		return KindServer
+	case trace_v1.Span_SPAN_KIND_CLIENT:
+		return KindClient
+	case trace_v1.Span_SPAN_KIND_PRODUCER:
+		return KindProducer
+	case trace_v1.Span_SPAN_KIND_CONSUMER:
+		return KindConsumer
+	}
+	return Kind(kind)
+}
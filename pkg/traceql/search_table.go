@@ -0,0 +1,429 @@
+package traceql
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// maxSampleTraceIDs is the number of trace IDs kept as examples for every table row.
+const maxSampleTraceIDs = 5
+
+var tableAggregateOps = map[string]AggregateOp{
+	aggregateCount.String(): aggregateCount,
+	aggregateMax.String():   aggregateMax,
+	aggregateMin.String():   aggregateMin,
+	aggregateSum.String():   aggregateSum,
+	aggregateAvg.String():   aggregateAvg,
+}
+
+type tableValueKind int
+
+const (
+	tableValueInt tableValueKind = iota
+	tableValueFloat
+	tableValueDuration
+)
+
+type tableAggregate struct {
+	name  string
+	op    AggregateOp
+	kind  tableValueKind
+	value float64
+	// weight is the number of spans behind an avg, so that averages of different traces can be combined
+	weight float64
+}
+
+type tableRow struct {
+	row        *tempopb.SearchTableRow
+	aggregates []*tableAggregate
+	traces     map[string]struct{}
+}
+
+// tableFilter is an aggregate filter of the query, i.e. `count() > 2`, applied to the combined
+// aggregate of a group.
+type tableFilter struct {
+	name string
+	op   Operator
+	rhs  Static
+}
+
+// SearchTableCombiner builds the search table from every group of every trace evaluated by the
+// search jobs, not only the traces that match the query or are kept within its limit. Aggregates
+// are combined across traces: counts and sums are added up, min and max are kept and averages are
+// weighted by the number of matched spans. The aggregate filters of the query are only evaluated
+// on the combined aggregates of every group. Each job returns the unfiltered rows of the traces it
+// evaluated, see PartialRows, and the query-frontend combines them with AddRows. A trace found by
+// several jobs is counted once per job.
+type SearchTableCombiner struct {
+	rows    map[string]*tableRow
+	filters []tableFilter
+	grouped bool
+}
+
+// NewSearchTableCombiner returns a combiner for the search table of the given query. The query can be
+// nil, in which case the groups are not filtered.
+func NewSearchTableCombiner(query *RootExpr) *SearchTableCombiner {
+	c := &SearchTableCombiner{
+		rows: map[string]*tableRow{},
+	}
+	if query == nil {
+		return c
+	}
+
+	for _, e := range query.Pipeline.Elements {
+		if _, ok := e.(GroupOperation); ok {
+			c.grouped = true
+			continue
+		}
+		if lhs, op, rhs, ok := tableFilterOperands(e); ok {
+			c.filters = append(c.filters, tableFilter{name: lhs.String(), op: op, rhs: rhs})
+		}
+	}
+	return c
+}
+
+// searchTablePipeline returns the pipeline evaluated per trace for queries with a search table. Their
+// aggregate filters are replaced by the aggregates, so that every group of every trace is added to
+// the table. The groups that don't match the filters are marked with markTableOnly and are not
+// returned as search results. Other pipelines are returned as is.
+func searchTablePipeline(p Pipeline) Pipeline {
+	if !NewSearchTableCombiner(&RootExpr{Pipeline: p}).Enabled() {
+		return p
+	}
+
+	elements := make([]PipelineElement, 0, len(p.Elements))
+	for _, e := range p.Elements {
+		if lhs, _, _, ok := tableFilterOperands(e); ok {
+			e = lhs
+		}
+		elements = append(elements, e)
+	}
+	return Pipeline{Elements: elements}
+}
+
+// tableFilterOperands returns the operands of an aggregate filter, i.e. `count() > 2`. Like
+// ScalarFilter.evaluate, only aggregate binop static is supported.
+func tableFilterOperands(e PipelineElement) (Aggregate, Operator, Static, bool) {
+	f, ok := e.(ScalarFilter)
+	if !ok {
+		return Aggregate{}, 0, Static{}, false
+	}
+	lhs, ok := f.LHS.(Aggregate)
+	if !ok {
+		return Aggregate{}, 0, Static{}, false
+	}
+	rhs, ok := f.RHS.(Static)
+	if !ok {
+		return Aggregate{}, 0, Static{}, false
+	}
+	return lhs, f.Op, rhs, true
+}
+
+// markTableOnly marks the spansets that don't match the aggregate filters of the query, they are only
+// added to the table.
+func (c *SearchTableCombiner) markTableOnly(spansets []*Spanset) {
+	for _, ss := range spansets {
+		if !c.matchesSpanset(ss) {
+			ss.AddAttribute(attributeTableOnly, NewStaticBool(true))
+		}
+	}
+}
+
+func (c *SearchTableCombiner) matchesSpanset(ss *Spanset) bool {
+	for _, f := range c.filters {
+		for _, a := range ss.Attributes {
+			if a.Name != f.name {
+				continue
+			}
+			if ok, err := binOp(f.op, a.Val, f.rhs); err != nil || !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isTableOnly returns true if the spanset was marked by markTableOnly.
+func isTableOnly(ss *Spanset) bool {
+	for _, a := range ss.Attributes {
+		if a.Name == attributeTableOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled returns true if the query groups spans with by() and filters the groups with an aggregate,
+// i.e. if its search response has a table.
+func (c *SearchTableCombiner) Enabled() bool {
+	return c.grouped && len(c.filters) > 0
+}
+
+// addSpanset adds a spanset evaluated by the engine to the table.
+func (c *SearchTableCombiner) addSpanset(ss *Spanset) {
+	var (
+		matched uint32
+		attrs   = make([]*common_v1.KeyValue, 0, len(ss.Attributes))
+	)
+	for _, a := range ss.Attributes {
+		if a.Name == attributeMatched {
+			if n, ok := a.Val.Int(); ok {
+				matched = uint32(n)
+			}
+			continue
+		}
+		attrs = append(attrs, &common_v1.KeyValue{Key: a.Name, Value: a.Val.AsAnyValue()})
+	}
+	c.addGroup(util.TraceIDToHexString(ss.TraceID), matched, attrs)
+}
+
+func (c *SearchTableCombiner) addGroup(traceID string, matched uint32, attrs []*common_v1.KeyValue) {
+	group, aggregates := splitTableAttributes(attrs)
+	if len(group) == 0 || len(aggregates) == 0 {
+		return
+	}
+
+	r := c.row(group)
+	r.row.SpanCount += matched
+	// a trace can have several spansets in the same group, it's only counted once
+	if _, ok := r.traces[traceID]; !ok {
+		r.traces[traceID] = struct{}{}
+		r.row.TraceCount++
+		r.addSampleTraceID(traceID)
+	}
+
+	for _, kv := range aggregates {
+		r.observe(kv, float64(max(matched, 1)))
+	}
+}
+
+// AddRows adds the rows returned by a search job, see PartialRows. It returns true if the table changed.
+func (c *SearchTableCombiner) AddRows(rows []*tempopb.SearchTableRow) bool {
+	for _, row := range rows {
+		r := c.row(row.Group)
+		r.row.SpanCount += row.SpanCount
+		r.row.TraceCount += row.TraceCount
+		for _, id := range row.SampleTraceIDs {
+			r.addSampleTraceID(id)
+		}
+
+		for _, kv := range row.Aggregates {
+			r.observe(kv, float64(max(row.SpanCount, 1)))
+		}
+	}
+	return len(rows) > 0
+}
+
+func (c *SearchTableCombiner) row(group []*common_v1.KeyValue) *tableRow {
+	key := tableRowKey(group)
+	r, ok := c.rows[key]
+	if !ok {
+		r = &tableRow{
+			row:    &tempopb.SearchTableRow{Group: group},
+			traces: map[string]struct{}{},
+		}
+		c.rows[key] = r
+	}
+	return r
+}
+
+// Rows returns the rows of the groups that match the aggregate filters of the query, sorted by group.
+// New rows are returned on every call so they can be modified by the caller.
+func (c *SearchTableCombiner) Rows() []*tempopb.SearchTableRow {
+	return c.collectRows(true)
+}
+
+// PartialRows returns the rows of every group, they are combined with the rows of the other jobs
+// before the aggregate filters are evaluated.
+func (c *SearchTableCombiner) PartialRows() []*tempopb.SearchTableRow {
+	return c.collectRows(false)
+}
+
+func (c *SearchTableCombiner) collectRows(filtered bool) []*tempopb.SearchTableRow {
+	keys := make([]string, 0, len(c.rows))
+	for k := range c.rows {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var result []*tempopb.SearchTableRow
+	for _, k := range keys {
+		r := c.rows[k]
+		if filtered && !c.matches(r) {
+			continue
+		}
+
+		row := &tempopb.SearchTableRow{
+			Group:          r.row.Group,
+			TraceCount:     r.row.TraceCount,
+			SpanCount:      r.row.SpanCount,
+			SampleTraceIDs: append([]string(nil), r.row.SampleTraceIDs...),
+		}
+		// traces are combined in no particular order
+		sort.Strings(row.SampleTraceIDs)
+		for _, a := range r.aggregates {
+			row.Aggregates = append(row.Aggregates, &common_v1.KeyValue{Key: a.name, Value: a.result()})
+		}
+		result = append(result, row)
+	}
+	return result
+}
+
+func (c *SearchTableCombiner) matches(r *tableRow) bool {
+	for _, f := range c.filters {
+		for _, a := range r.aggregates {
+			if a.name != f.name {
+				continue
+			}
+			if ok, err := binOp(f.op, a.static(), f.rhs); err != nil || !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// splitTableAttributes separates the by() attributes of a spanset from its aggregates.
+func splitTableAttributes(attrs []*common_v1.KeyValue) (group, aggregates []*common_v1.KeyValue) {
+	for _, kv := range attrs {
+		if strings.HasPrefix(kv.Key, "by(") {
+			group = append(group, kv)
+			continue
+		}
+		if _, ok := tableAggregateOp(kv.Key); ok {
+			aggregates = append(aggregates, kv)
+		}
+	}
+	return group, aggregates
+}
+
+func tableAggregateOp(name string) (AggregateOp, bool) {
+	i := strings.IndexByte(name, '(')
+	if i < 0 || !strings.HasSuffix(name, ")") {
+		return 0, false
+	}
+	op, ok := tableAggregateOps[name[:i]]
+	return op, ok
+}
+
+func tableRowKey(group []*common_v1.KeyValue) string {
+	sb := strings.Builder{}
+	for _, kv := range group {
+		sb.WriteString(kv.Key)
+		sb.WriteByte(0)
+		sb.WriteString(kv.Value.String())
+		sb.WriteByte(0)
+	}
+	return sb.String()
+}
+
+func (r *tableRow) addSampleTraceID(traceID string) {
+	if len(r.row.SampleTraceIDs) >= maxSampleTraceIDs {
+		return
+	}
+	for _, id := range r.row.SampleTraceIDs {
+		if id == traceID {
+			return
+		}
+	}
+	r.row.SampleTraceIDs = append(r.row.SampleTraceIDs, traceID)
+}
+
+func (r *tableRow) observe(kv *common_v1.KeyValue, weight float64) {
+	v, kind, ok := tableValue(kv.Value)
+	if !ok {
+		return
+	}
+
+	var a *tableAggregate
+	for _, existing := range r.aggregates {
+		if existing.name == kv.Key {
+			a = existing
+			break
+		}
+	}
+	if a == nil {
+		op, _ := tableAggregateOp(kv.Key)
+		a = &tableAggregate{name: kv.Key, op: op, kind: kind}
+		switch op {
+		case aggregateMin:
+			a.value = math.Inf(1)
+		case aggregateMax:
+			a.value = math.Inf(-1)
+		}
+		r.aggregates = append(r.aggregates, a)
+	}
+	if kind == tableValueFloat && a.kind == tableValueInt {
+		a.kind = tableValueFloat
+	}
+
+	switch a.op {
+	case aggregateCount, aggregateSum:
+		a.value += v
+	case aggregateMin:
+		a.value = math.Min(a.value, v)
+	case aggregateMax:
+		a.value = math.Max(a.value, v)
+	case aggregateAvg:
+		a.value += v * weight
+		a.weight += weight
+	}
+}
+
+func (a *tableAggregate) result() *common_v1.AnyValue {
+	v := a.value
+	if a.op == aggregateAvg {
+		if a.weight == 0 {
+			v = math.NaN()
+		} else {
+			v /= a.weight
+		}
+	}
+
+	switch {
+	case a.kind == tableValueDuration:
+		return &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: time.Duration(v).String()}}
+	case a.kind == tableValueInt && a.op != aggregateAvg:
+		return &common_v1.AnyValue{Value: &common_v1.AnyValue_IntValue{IntValue: int64(v)}}
+	}
+	return &common_v1.AnyValue{Value: &common_v1.AnyValue_DoubleValue{DoubleValue: v}}
+}
+
+func (a *tableAggregate) static() Static {
+	v := a.value
+	if a.op == aggregateAvg && a.weight != 0 {
+		v /= a.weight
+	}
+
+	switch {
+	case a.kind == tableValueDuration:
+		return NewStaticDuration(time.Duration(v))
+	case a.kind == tableValueInt && a.op != aggregateAvg:
+		return NewStaticInt(int(v))
+	}
+	return NewStaticFloat(v)
+}
+
+// tableValue returns the numeric value of an aggregate. Durations are encoded as strings by the
+// search results and are returned in nanoseconds.
+func tableValue(v *common_v1.AnyValue) (float64, tableValueKind, bool) {
+	switch val := v.GetValue().(type) {
+	case *common_v1.AnyValue_IntValue:
+		return float64(val.IntValue), tableValueInt, true
+	case *common_v1.AnyValue_DoubleValue:
+		return val.DoubleValue, tableValueFloat, true
+	case *common_v1.AnyValue_StringValue:
+		d, err := time.ParseDuration(val.StringValue)
+		if err != nil {
+			return 0, 0, false
+		}
+		return float64(d), tableValueDuration, true
+	}
+	return 0, 0, false
+}
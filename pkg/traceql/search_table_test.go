@@ -0,0 +1,201 @@
+package traceql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTable(t *testing.T) {
+	span := func(svc string, duration time.Duration) Span {
+		s := newMockSpan(nil).WithSpanString("svc", svc)
+		s.attributes[NewIntrinsic(IntrinsicDuration)] = NewStaticDuration(duration)
+		return s
+	}
+
+	spansets := []*Spanset{
+		{
+			TraceID: []byte{1},
+			Spans: []Span{
+				span("a", 1*time.Second),
+				span("a", 2*time.Second),
+				span("a", 3*time.Second),
+				span("b", 1*time.Second),
+			},
+		},
+		{
+			TraceID: []byte{2},
+			Spans: []Span{
+				span("a", 6*time.Second),
+				span("b", 2*time.Second),
+				span("b", 4*time.Second),
+			},
+		},
+	}
+
+	tcs := []struct {
+		query    string
+		expected []*tempopb.SearchTableRow
+		// traces is the number of traces matching the query, the table is built from every trace
+		traces int
+	}{
+		{
+			query:  "{ } | by(span.svc) | count() > 0",
+			traces: 2,
+			expected: []*tempopb.SearchTableRow{
+				{
+					Group:          []*common_v1.KeyValue{stringKV("by(span.svc)", "a")},
+					Aggregates:     []*common_v1.KeyValue{intKV("count()", 4)},
+					TraceCount:     2,
+					SpanCount:      4,
+					SampleTraceIDs: []string{"1", "2"},
+				},
+				{
+					Group:          []*common_v1.KeyValue{stringKV("by(span.svc)", "b")},
+					Aggregates:     []*common_v1.KeyValue{intKV("count()", 3)},
+					TraceCount:     2,
+					SpanCount:      3,
+					SampleTraceIDs: []string{"1", "2"},
+				},
+			},
+		},
+		{
+			// the filters apply to the combined groups, no trace has more than 3 spans in a group
+			query: "{ } | by(span.svc) | count() > 3",
+			expected: []*tempopb.SearchTableRow{
+				{
+					Group:          []*common_v1.KeyValue{stringKV("by(span.svc)", "a")},
+					Aggregates:     []*common_v1.KeyValue{intKV("count()", 4)},
+					TraceCount:     2,
+					SpanCount:      4,
+					SampleTraceIDs: []string{"1", "2"},
+				},
+			},
+		},
+		{
+			query:  "{ } | by(span.svc) | count() > 1 | avg(duration) > 1s | max(duration) > 0",
+			traces: 2,
+			expected: []*tempopb.SearchTableRow{
+				{
+					// the avg is weighted by the number of spans in each trace
+					Group:          []*common_v1.KeyValue{stringKV("by(span.svc)", "a")},
+					Aggregates:     []*common_v1.KeyValue{intKV("count()", 4), stringKV("avg(duration)", "3s"), stringKV("max(duration)", "6s")},
+					TraceCount:     2,
+					SpanCount:      4,
+					SampleTraceIDs: []string{"1", "2"},
+				},
+				{
+					Group:          []*common_v1.KeyValue{stringKV("by(span.svc)", "b")},
+					Aggregates:     []*common_v1.KeyValue{intKV("count()", 3), stringKV("avg(duration)", "2.333333333s"), stringKV("max(duration)", "4s")},
+					TraceCount:     2,
+					SpanCount:      3,
+					SampleTraceIDs: []string{"1", "2"},
+				},
+			},
+		},
+		{
+			query: "{ } | by(span.svc) | count() > 10",
+		},
+		{
+			// no aggregates
+			query:  "{ } | by(span.svc)",
+			traces: 2,
+		},
+		{
+			// no groups
+			query:  "{ } | count() > 1",
+			traces: 2,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			resp, err := NewEngine().ExecuteSearch(context.Background(), &tempopb.SearchRequest{Query: tc.query, SpansPerSpanSet: 10}, &secondPassFetcher{spansets: spansets}, false)
+			require.NoError(t, err)
+			require.Len(t, resp.Traces, tc.traces)
+
+			expr, err := Parse(tc.query)
+			require.NoError(t, err)
+			c := NewSearchTableCombiner(expr)
+			c.AddRows(resp.Table)
+			require.Equal(t, tc.expected, c.Rows())
+		})
+	}
+}
+
+func TestSearchTableCombinesRows(t *testing.T) {
+	group := []*common_v1.KeyValue{stringKV("by(span.svc)", "a")}
+	rows := []*tempopb.SearchTableRow{
+		{
+			Group:          group,
+			Aggregates:     []*common_v1.KeyValue{intKV("avg(span.foo)", 4), intKV("min(span.foo)", 4)},
+			TraceCount:     1,
+			SpanCount:      1,
+			SampleTraceIDs: []string{"1"},
+		},
+		{
+			Group:          group,
+			Aggregates:     []*common_v1.KeyValue{{Key: "avg(span.foo)", Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_DoubleValue{DoubleValue: 2.5}}}, intKV("min(span.foo)", 1)},
+			TraceCount:     2,
+			SpanCount:      3,
+			SampleTraceIDs: []string{"1", "2"},
+		},
+	}
+
+	c := NewSearchTableCombiner(nil)
+	require.True(t, c.AddRows(rows))
+	require.False(t, c.AddRows(nil))
+
+	combined := c.Rows()
+	require.Len(t, combined, 1)
+	// the avg is weighted by the number of spans of each row
+	require.Equal(t, []*common_v1.KeyValue{
+		{Key: "avg(span.foo)", Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_DoubleValue{DoubleValue: 2.875}}},
+		intKV("min(span.foo)", 1),
+	}, combined[0].Aggregates)
+	require.Equal(t, uint32(3), combined[0].TraceCount)
+	require.Equal(t, uint32(4), combined[0].SpanCount)
+	require.Equal(t, []string{"1", "2"}, combined[0].SampleTraceIDs)
+}
+
+// secondPassFetcher returns every spanset produced by the second pass, unlike the
+// MockSpanSetFetcher that only keeps the spans of the first one.
+type secondPassFetcher struct {
+	spansets []*Spanset
+}
+
+func (f *secondPassFetcher) Fetch(_ context.Context, request FetchSpansRequest) (FetchSpansResponse, error) {
+	var results []*Spanset
+	for _, ss := range f.spansets {
+		evaluated, err := request.SecondPass(ss.clone())
+		if err != nil {
+			return FetchSpansResponse{}, err
+		}
+		for _, e := range evaluated {
+			e.TraceID = ss.TraceID
+			results = append(results, e)
+		}
+	}
+
+	return FetchSpansResponse{
+		Results: &MockSpanSetIterator{results: results},
+		Bytes:   func() uint64 { return 0 },
+	}, nil
+}
+
+func (f *secondPassFetcher) FetchSpans(context.Context, FetchSpansRequest) (FetchSpansOnlyResponse, error) {
+	return FetchSpansOnlyResponse{}, util.ErrUnsupported
+}
+
+func stringKV(k, v string) *common_v1.KeyValue {
+	return &common_v1.KeyValue{Key: k, Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: v}}}
+}
+
+func intKV(k string, v int64) *common_v1.KeyValue {
+	return &common_v1.KeyValue{Key: k, Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_IntValue{IntValue: v}}}
+}
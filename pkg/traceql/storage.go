@@ -193,6 +193,9 @@ type Span interface {
 // should we just make matched a field on the spanset instead of a special attribute?
 const attributeMatched = "__matched"
 
+// attributeTableOnly marks the spansets that are only added to the search table, see markTableOnly.
+const attributeTableOnly = "__table_only"
+
 type SpansetAttribute struct {
 	Name string
 	Val  Static
@@ -216,6 +219,10 @@ type Spanset struct {
 	ServiceStats       map[string]ServiceStats
 	Attributes         []*SpansetAttribute
 
+	// selected holds the values of the function calls in select() for every span, in the
+	// order of Spans. It is set by the engine once all attributes have been fetched.
+	selected [][]*SpansetAttribute
+
 	// Set this function to provide upstream callers with a method to
 	// release this spanset and all its spans when finished. This method will be
 	// called with the spanset itself as the argument. This is done for a worthwhile
@@ -734,6 +734,11 @@ func searchesThatMatch(t *testing.T, traceIDText string) []struct {
 		{"Regex Match None", traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelName + ` !~ "xyz" && ` + LabelName + ` !~ "bar"}`)},
 		{"Exists", traceql.MustExtractFetchSpansRequestWithMetadata(`{span.foo != nil}`)},
 		{"Not Exists", traceql.MustExtractFetchSpansRequestWithMetadata(`{span.xyz = nil}`)},
+		// String functions
+		{"startsWith", traceql.MustExtractFetchSpansRequestWithMetadata(`{startsWith(` + LabelName + `, "hel")}`)},
+		{"endsWith", traceql.MustExtractFetchSpansRequestWithMetadata(`{endsWith(` + LabelName + `, "llo")}`)},
+		{"contains", traceql.MustExtractFetchSpansRequestWithMetadata(`{contains(span.foo, "e")}`)},
+		{"upper", traceql.MustExtractFetchSpansRequestWithMetadata(`{upper(` + LabelName + `) = "HELLO"}`)},
 		// Event
 		{"Event IN", traceql.MustExtractFetchSpansRequestWithMetadata(`{event.message = "exception" || event.message = "test"}`)},
 		{"Event Not In", traceql.MustExtractFetchSpansRequestWithMetadata(`{event.message != "foo" && event.message != "bar"}`)},
@@ -797,6 +802,9 @@ func searchesThatDontMatch(t *testing.T) []struct {
 		{"Intrinsic: status", traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelStatus + ` = unset}`)},
 		{"Intrinsic: statusMessage", traceql.MustExtractFetchSpansRequestWithMetadata(`{` + "statusMessage" + ` = "abc"}`)},
 		{"Intrinsic: name", traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelName + ` = "nothello"}`)},
+		{"startsWith", traceql.MustExtractFetchSpansRequestWithMetadata(`{startsWith(` + LabelName + `, "llo")}`)},
+		{"contains", traceql.MustExtractFetchSpansRequestWithMetadata(`{contains(` + LabelName + `, "xyz")}`)},
+		{"lower", traceql.MustExtractFetchSpansRequestWithMetadata(`{lower(` + LabelName + `) = "nothello"}`)},
 		{"Intrinsic: kind", traceql.MustExtractFetchSpansRequestWithMetadata(`{` + LabelKind + ` = producer }`)},
 		{"Intrinsic: event:name", traceql.MustExtractFetchSpansRequestWithMetadata(`{event:name = "x2"}`)},
 		{"Intrinsic: link:spanID", traceql.MustExtractFetchSpansRequestWithMetadata(`{link:spanID = "ffffffffffffffff"}`)},